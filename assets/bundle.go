@@ -0,0 +1,14 @@
+// Package assets bundles the asset files into the build for the browser,
+// which has no file system to load them from; internal/storage reads them
+// from here there. Desktop builds load assets from disk and don't import
+// this package.
+package assets
+
+import "embed"
+
+// FS holds the asset files by their path in this directory, e.g.
+// "levels/level_01.json". New top-level files and directories must be
+// added to the list.
+//
+//go:embed campaign.json worldmap.json levels sprites themes tiles
+var FS embed.FS
@@ -0,0 +1,359 @@
+// Command assetpipe checks the game's content and prepares it for shipping.
+//
+// Usage:
+//
+//	go run ./cmd/assetpipe [-assets assets] [-out dist/assets] [-strict] [-check]
+//
+// Every level in <assets>/levels (Tiled JSON or TMX) is validated with the
+// level checks, its tileset images (or external tilesets) must exist and
+// match the sizes the level declares, and its rules file, if any, must
+// parse. Any problem fails the run before anything is written.
+//
+// Otherwise each level is written to -out as compact JSON without
+// editor-only data (the budget properties), with a gzip-compressed copy
+// next to it. The tileset and rules files the levels use are copied along,
+// keeping their paths relative to the assets directory, and manifest.json
+// lists every file written with its size and SHA-256.
+//
+// -check only runs the checks. Like leveltool validate, the level checks
+// link ebiten through the world package, so run under xvfb-run on a
+// headless CI machine.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/torsten/GoP/internal/levelcheck"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/tiled"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// manifestVersion is bumped when the manifest format changes.
+const manifestVersion = 1
+
+// Manifest describes the shipped content.
+type Manifest struct {
+	Version int             `json:"version"`
+	Levels  []ManifestLevel `json:"levels"`
+	Files   []ManifestFile  `json:"files"` // Tilesets and rules files
+}
+
+// ManifestLevel is a shipped level and the files it uses. Paths are
+// relative to the output directory.
+type ManifestLevel struct {
+	ManifestFile
+	Compressed ManifestFile `json:"compressed"`
+	Tilesets   []string     `json:"tilesets"`
+	Rules      string       `json:"rules,omitempty"`
+}
+
+// ManifestFile is a file written by the pipeline.
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// level is a checked level ready to be written.
+type level struct {
+	path  string   // Source file
+	rel   string   // Output path, relative to the output directory
+	data  []byte   // Stripped, compact JSON
+	deps  []string // Tileset files, relative to the assets directory
+	rules string   // Rules file relative to the assets directory, or ""
+}
+
+func main() {
+	assetsDir := flag.String("assets", "assets", "assets directory")
+	outDir := flag.String("out", "dist/assets", "output directory")
+	strict := flag.Bool("strict", false, "treat level warnings as failures")
+	checkOnly := flag.Bool("check", false, "only run the checks, write nothing")
+	flag.Parse()
+
+	paths, err := levelFiles(filepath.Join(*assetsDir, "levels"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "assetpipe: %v\n", err)
+		os.Exit(1)
+	}
+	if len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "assetpipe: no levels in %s\n", filepath.Join(*assetsDir, "levels"))
+		os.Exit(1)
+	}
+
+	var levels []level
+	failed := false
+	for _, path := range paths {
+		l, problems := check(*assetsDir, path, *strict)
+		for _, p := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, p)
+		}
+		if l == nil {
+			failed = true
+			continue
+		}
+		levels = append(levels, *l)
+	}
+	if failed {
+		fmt.Fprintf(os.Stderr, "assetpipe: content has errors, nothing written\n")
+		os.Exit(1)
+	}
+	fmt.Printf("%d levels checked\n", len(levels))
+	if *checkOnly {
+		return
+	}
+
+	manifest, err := write(*assetsDir, *outDir, levels)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "assetpipe: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d levels and %d files to %s\n", len(manifest.Levels), len(manifest.Files), *outDir)
+}
+
+// levelFiles returns the level files in dir, sorted.
+func levelFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".json" && ext != ".tmx") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// check runs every check on the level at path. It returns the level ready
+// to write, or nil if it failed, and the problems found; with warnings
+// only, the level is returned along with them unless strict is set.
+func check(assetsDir, path string, strict bool) (*level, []string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	m, err := tiled.Parse(path, data)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if tiled.IsTMX(path) {
+		// The game reads JSON only
+		if data, err = m.EncodeJSON(); err != nil {
+			return nil, []string{err.Error()}
+		}
+	}
+
+	var problems []string
+	failed := false
+	fail := func(format string, args ...any) {
+		problems = append(problems, "ERROR: "+fmt.Sprintf(format, args...))
+		failed = true
+	}
+
+	lc, err := levelcheck.ParseLevel(data, path)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	result := levelcheck.Validate(lc)
+	for _, issue := range result.AllIssues() {
+		where := ""
+		if issue.ObjectIndex >= 0 && issue.ObjectIndex < len(lc.Objects) {
+			obj := lc.Objects[issue.ObjectIndex]
+			where = fmt.Sprintf(" [object %d %s %q]", obj.ID, obj.Type, obj.Name)
+		}
+		problems = append(problems, fmt.Sprintf("%s%s: %s", strings.ToUpper(string(issue.Type)), where, levelcheck.Format(issue)))
+	}
+	failed = result.HasErrors() || (strict && result.HasWarnings())
+
+	l := &level{path: path}
+	levelDir := filepath.Dir(path)
+	for _, ts := range m.Tilesets {
+		file := ts.Image
+		if ts.Source != "" {
+			file = ts.Source
+		}
+		if file == "" {
+			fail("tileset %q has no image", ts.Name)
+			continue
+		}
+		full := filepath.Join(levelDir, file)
+		rel, err := relativeTo(assetsDir, full)
+		if err != nil {
+			fail("tileset %q: %v", ts.Name, err)
+			continue
+		}
+		if ts.Source == "" {
+			if err := checkImage(full, ts.ImageWidth, ts.ImageHeight); err != nil {
+				fail("tileset %q: %v", ts.Name, err)
+				continue
+			}
+		} else if _, err := os.Stat(full); err != nil {
+			fail("tileset %q: %v", ts.Name, err)
+			continue
+		}
+		l.deps = append(l.deps, rel)
+	}
+
+	rulesPath := rules.PathForLevel(path)
+	if ruleData, err := rules.ReadLevelFile(path); err != nil {
+		fail("rules: %v", err)
+	} else if ruleData != nil {
+		if _, err := rules.ParseYAML(ruleData); err != nil {
+			fail("%s: %v", rulesPath, err)
+		} else if l.rules, err = relativeTo(assetsDir, rulesPath); err != nil {
+			fail("rules: %v", err)
+		}
+	}
+
+	if failed {
+		return nil, problems
+	}
+
+	m.StripProperties(world.BudgetProperties...)
+	if l.data, err = json.Marshal(m); err != nil {
+		return nil, append(problems, "ERROR: "+err.Error())
+	}
+	if l.rel, err = relativeTo(assetsDir, path); err != nil {
+		return nil, append(problems, "ERROR: "+err.Error())
+	}
+	l.rel = strings.TrimSuffix(l.rel, filepath.Ext(l.rel)) + ".json"
+	return l, problems
+}
+
+// checkImage checks that the PNG at path exists and is w×h pixels. Zero
+// sizes aren't checked.
+func checkImage(path string, w, h int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if (w != 0 && cfg.Width != w) || (h != 0 && cfg.Height != h) {
+		return fmt.Errorf("%s is %dx%d, the level expects %dx%d", path, cfg.Width, cfg.Height, w, h)
+	}
+	return nil
+}
+
+// relativeTo returns path relative to dir, failing for paths outside it.
+func relativeTo(dir, path string) (string, error) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside %s", path, dir)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// write writes the levels, their compressed copies, the files they use and
+// the manifest to outDir.
+func write(assetsDir, outDir string, levels []level) (*Manifest, error) {
+	manifest := &Manifest{Version: manifestVersion, Levels: []ManifestLevel{}, Files: []ManifestFile{}}
+	copied := make(map[string]bool)
+
+	copyFile := func(rel string) error {
+		if copied[rel] {
+			return nil
+		}
+		copied[rel] = true
+		data, err := os.ReadFile(filepath.Join(assetsDir, rel))
+		if err != nil {
+			return err
+		}
+		f, err := writeFile(outDir, rel, data)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, f)
+		return nil
+	}
+
+	for _, l := range levels {
+		f, err := writeFile(outDir, l.rel, l.data)
+		if err != nil {
+			return nil, err
+		}
+		compressed, err := compress(l.data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", l.path, err)
+		}
+		gz, err := writeFile(outDir, l.rel+".gz", compressed)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := ManifestLevel{ManifestFile: f, Compressed: gz, Tilesets: []string{}, Rules: l.rules}
+		for _, dep := range l.deps {
+			if err := copyFile(dep); err != nil {
+				return nil, err
+			}
+			entry.Tilesets = append(entry.Tilesets, dep)
+		}
+		if l.rules != "" {
+			if err := copyFile(l.rules); err != nil {
+				return nil, err
+			}
+		}
+		manifest.Levels = append(manifest.Levels, entry)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writeFile(outDir, "manifest.json", append(data, '\n')); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// compress gzips data at the best compression level.
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFile writes data to rel under outDir and returns its manifest entry.
+func writeFile(outDir, rel string, data []byte) (ManifestFile, error) {
+	path := filepath.Join(outDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ManifestFile{}, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return ManifestFile{}, err
+	}
+	sum := sha256.Sum256(data)
+	return ManifestFile{Path: rel, Size: len(data), SHA256: hex.EncodeToString(sum[:])}, nil
+}
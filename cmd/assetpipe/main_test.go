@@ -0,0 +1,72 @@
+//go:build display
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+func TestCheckCompressedLevel(t *testing.T) {
+	data, err := os.ReadFile("../../assets/levels/level_01.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tileset, err := os.ReadFile("../../assets/tiles/tiles.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, compression := range []string{"", tiled.CompressionZlib, tiled.CompressionGzip} {
+		m, err := tiled.ParseJSON(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range m.Layers {
+			if m.Layers[i].Type == "tilelayer" {
+				m.Layers[i].Encoding = tiled.EncodingBase64
+				m.Layers[i].Compression = compression
+			}
+		}
+		level, err := m.EncodeJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assets := t.TempDir()
+		path := filepath.Join(assets, "levels", "level.json")
+		for file, data := range map[string][]byte{path: level, filepath.Join(assets, "tiles", "tiles.png"): tileset} {
+			if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		l, problems := check(assets, path, false)
+		if l == nil {
+			t.Fatalf("%q: check failed: %v", compression, problems)
+		}
+		got, err := tiled.ParseJSON(l.data)
+		if err != nil {
+			t.Fatalf("%q: shipped level doesn't parse: %v", compression, err)
+		}
+		for i, gl := range got.Layers {
+			want := m.Layers[i]
+			if gl.Type != "tilelayer" {
+				continue
+			}
+			if gl.Encoding != want.Encoding || gl.Compression != want.Compression {
+				t.Errorf("%q: layer %q shipped as %q/%q, want %q/%q", compression, gl.Name, gl.Encoding, gl.Compression, want.Encoding, want.Compression)
+			}
+			if !slices.Equal(gl.Data, want.Data) {
+				t.Errorf("%q: layer %q tiles changed", compression, gl.Name)
+			}
+		}
+	}
+}
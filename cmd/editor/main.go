@@ -2,19 +2,75 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"io/fs"
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/config"
 	"github.com/torsten/GoP/internal/editor"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/input"
 )
 
 func main() {
+	tuningPath := flag.String("tuning", "", "JSON tuning file applied on top of the config file's tuning; the playtest tuning overlay saves to it (default: "+editor.DefaultTuningPath+")")
+	prefabPath := flag.String("prefabs", editor.DefaultPrefabPath, "JSON prefab library listed in the object palette's Prefabs tab")
+	typesPath := flag.String("types", "", "YAML or JSON file of custom object types added to the palette")
+	flag.Parse()
+
+	// Load the optional config file
+	file, err := config.Load(config.Path())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := input.SetBindings(file.Keybinds); err != nil {
+		log.Fatal(err)
+	}
+	if file.Language != "" {
+		if err := i18n.SetLanguage(file.Language); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := file.Log.Apply(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Custom object types have to be known before the palette is created
+	if *typesPath != "" {
+		if err := editor.LoadCustomSchemas(*typesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Create the editor application
 	app := editor.NewApp()
+	tuning := file.GameTuning()
+	if *tuningPath != "" {
+		// A missing file is fine: it's created when the overlay saves
+		if tuning, err = config.LoadTuning(*tuningPath, tuning); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Fatal(err)
+		}
+	}
+	app.SetTuning(tuning)
+	app.SetTuningPath(*tuningPath)
+	if err := app.SetPrefabPath(*prefabPath); err != nil {
+		log.Fatal(err)
+	}
+	cam := file.EditorCamera
+	app.SetZoomLimits(cam.MinZoom, cam.MaxZoom, cam.ZoomSteps)
 
 	// Configure the window
-	ebiten.SetWindowSize(1280, 720)
-	ebiten.SetWindowTitle("GoP Level Editor")
+	width, height, title := 1280, 720, "GoP Level Editor"
+	if w := file.EditorWindow; w.Width > 0 && w.Height > 0 {
+		width, height = w.Width, w.Height
+	}
+	if file.EditorWindow.Title != "" {
+		title = file.EditorWindow.Title
+	}
+	ebiten.SetWindowSize(width, height)
+	ebiten.SetWindowTitle(title)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetFPSMode(ebiten.FPSModeVsyncOn)
 
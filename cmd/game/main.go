@@ -2,30 +2,324 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"path/filepath"
+	"strings"
 
+	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/torsten/GoP/internal/app"
+	"github.com/torsten/GoP/internal/campaign"
+	"github.com/torsten/GoP/internal/config"
+	"github.com/torsten/GoP/internal/gameplay"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/levelcheck"
+	"github.com/torsten/GoP/internal/logging"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/save"
+	"github.com/torsten/GoP/internal/scenes/menu"
+	"github.com/torsten/GoP/internal/scenes/overworld"
+	"github.com/torsten/GoP/internal/scenes/results"
 	"github.com/torsten/GoP/internal/scenes/sandbox"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/worldmap"
 )
 
+// builtinLevelPath is the source file of the built-in level, relative to the
+// repository root, where runtime edits of it are saved.
+const builtinLevelPath = "assets/levels/level_01.json"
+
+// defaultWorldmapPath is the overworld shown by the overworld scene.
+const defaultWorldmapPath = "assets/worldmap.json"
+
+// defaultCampaignPath is the campaign manifest with the order levels are
+// played in.
+const defaultCampaignPath = "assets/campaign.json"
+
+var logger = logging.New("game")
+
 func main() {
+	levelPath := flag.String("level", "", "Tiled JSON level to play (default: built-in level)")
+	debug := flag.Bool("debug", false, "Start with the debug overlay enabled and allow runtime level editing (F12)")
+	sceneName := flag.String("scene", "sandbox", "Initial scene: sandbox, menu or overworld")
+	worldmapPath := flag.String("worldmap", defaultWorldmapPath, "Worldmap JSON for the overworld scene")
+	campaignPath := flag.String("campaign", defaultCampaignPath, "Campaign JSON with the order levels are played in")
+	tuningPath := flag.String("tuning", "", "JSON tuning file applied on top of the config file's tuning")
+	flag.Parse()
+
+	// Load the optional config file
+	file, err := config.Load(config.Path())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := input.SetBindings(file.Keybinds); err != nil {
+		log.Fatal(err)
+	}
+	touch := input.TouchOptions{Mode: input.TouchMode(file.Touch.Mode), Size: file.Touch.Size, Opacity: file.Touch.Opacity}
+	if err := input.SetTouchOptions(touch); err != nil {
+		log.Fatal(err)
+	}
+	if file.Language != "" {
+		if err := i18n.SetLanguage(file.Language); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := file.Log.Apply(); err != nil {
+		log.Fatal(err)
+	}
+
+	tuning := file.GameTuning()
+	if *tuningPath != "" {
+		if tuning, err = config.LoadTuning(*tuningPath, tuning); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var levelData []byte
+	if *levelPath != "" {
+		if levelData, err = readLevel(*levelPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Finishing a campaign level leads on to the next one
+	levels, err := campaign.Load(*campaignPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Create configuration
 	cfg := &app.Config{
-		WindowWidth:  640,
-		WindowHeight: 360,
-		WindowTitle:  "GoP Game",
-		DebugMode:    false,
+		WindowWidth:   1280,
+		WindowHeight:  720,
+		WindowTitle:   "GoP Game",
+		DebugMode:     false,
+		LogicalWidth:  640,
+		LogicalHeight: 360,
+		ScaleMode:     app.ScaleInteger,
+	}
+	cfg.ApplyFile(file)
+	if *debug {
+		cfg.DebugMode = true
+	}
+	if path, err := app.DefaultSettingsPath(); err == nil {
+		cfg.SettingsPath = path
+	}
+
+	// Level completion for the overworld
+	progress := &save.Data{}
+	savePath, err := save.DefaultPath()
+	if err == nil {
+		if progress, err = save.Load(savePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Runtime edits are saved to the level being played
+	editPath := *levelPath
+	if editPath == "" {
+		editPath = builtinLevelPath
 	}
 
 	// Create app
 	game := app.New(cfg)
 
+	newSandbox := func() *sandbox.Scene {
+		var scene *sandbox.Scene
+		if levelData != nil {
+			scene = sandbox.NewWithLevel(levelData)
+		} else {
+			scene = sandbox.New()
+		}
+		scene.SetTuning(tuning)
+		scene.SetLevelPath(editPath)
+		if data, err := rules.ReadLevelFile(editPath); err != nil {
+			logger.Warnf("Failed to read rules: %v", err)
+		} else {
+			scene.SetRules(data)
+		}
+		scene.SetSavedVars(progress.SavedVars())
+		if cfg.DebugMode {
+			scene.EnableEditMode(editPath)
+		}
+		return scene
+	}
+
+	// playLevel plays the level at path with data (nil for the built-in
+	// level) and records its completion. The results screen then offers a
+	// retry or next, labelled nextLabel.
+	var playLevel func(path string, data []byte, title, nextLabel string, next func() error)
+	playLevel = func(path string, data []byte, title, nextLabel string, next func() error) {
+		levelData, editPath = data, path
+		play := newSandbox()
+		var stats results.Stats
+		play.SetOnComplete(func(p *gameplay.LevelProgress) {
+			stats = results.Stats{
+				Level:        title,
+				Time:         p.Elapsed,
+				BestTime:     progress.Record(path).BestTime,
+				NewBest:      progress.Complete(path, p.Elapsed),
+				Deaths:       p.Deaths,
+				Collected:    p.Collected,
+				Collectibles: p.Collectibles,
+			}
+			if savePath == "" {
+				return
+			}
+			if err := progress.Save(savePath); err != nil {
+				logger.Errorf("Failed to save progress: %v", err)
+			}
+		}, func() error {
+			screen := results.New(stats, nextLabel)
+			screen.OnContinue = next
+			screen.OnRetry = func() error {
+				playLevel(path, data, title, nextLabel, next)
+				return nil
+			}
+			game.SetScene(screen)
+			return nil
+		})
+		game.SetScene(play)
+	}
+
+	// The overworld launches levels and is shown again once they're done,
+	// with the token at the level's node
+	var showOverworld func(at string) error
+	showOverworld = func(at string) error {
+		m, err := worldmap.Load(*worldmapPath)
+		if err != nil {
+			return err
+		}
+		scene := overworld.New(m, progress, at)
+		scene.OnSelect = func(node worldmap.Node, level string) error {
+			data, err := readLevel(level)
+			if err != nil {
+				logger.Errorf("%v", err)
+				return nil
+			}
+			playLevel(level, data, node.Name, i18n.T("results.worldMap"), func() error {
+				return showOverworld(node.ID)
+			})
+			return nil
+		}
+		game.SetScene(scene)
+		return nil
+	}
+
+	// playFrom plays the level at path with data. Campaign levels lead on to
+	// the next one, the last one and levels outside the campaign back to the
+	// title menu.
+	var showMenu func() error
+	var playFrom func(path string, data []byte)
+	playFrom = func(path string, data []byte) {
+		title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		nextLabel, next := i18n.T("results.titleMenu"), showMenu
+		if i := levels.Index(path); i >= 0 {
+			title = levels.Title(i)
+			nextLabel = i18n.T("results.campaignComplete", levels.Name)
+		}
+		if i, ok := levels.Next(path); ok {
+			nextLabel = i18n.T("results.next", levels.Title(i))
+			next = func() error {
+				nextPath := levels.LevelPath(i)
+				data, err := readLevel(nextPath)
+				if err != nil {
+					return err
+				}
+				playFrom(nextPath, data)
+				return nil
+			}
+		}
+		playLevel(path, data, title, nextLabel, next)
+	}
+
+	// The title menu plays the level the game was started with
+	startPath, startData := editPath, levelData
+	var newMenu func() *menu.Scene
+	newMenu = func() *menu.Scene {
+		return menu.New("GoP",
+			menu.Item{Label: i18n.T("menu.play"), OnSelect: func() error {
+				playFrom(startPath, startData)
+				return nil
+			}},
+			menu.Item{Label: i18n.T("menu.worldMap"), OnSelect: func() error {
+				return showOverworld("")
+			}},
+			menu.Item{Label: i18n.T("menu.language", i18n.Name(i18n.Language())), OnSelect: func() error {
+				if err := game.SetLanguage(i18n.NextLanguage()); err != nil {
+					return err
+				}
+				// Rebuild the menu in the new language, on the same item
+				scene := newMenu()
+				scene.Select(2) // This item
+				game.SetScene(scene)
+				return nil
+			}},
+			menu.Item{Label: i18n.T("menu.quit"), OnSelect: func() error {
+				return ebiten.Termination
+			}},
+		)
+	}
+	showMenu = func() error {
+		game.SetScene(newMenu())
+		return nil
+	}
+
+	// Console command to switch levels without restarting
+	game.Console().Register(app.Command{
+		Name:  "load",
+		Usage: "load level <path>",
+		Help:  "Play a Tiled JSON level",
+		Run: func(args []string) (string, error) {
+			if len(args) != 2 || args[0] != "level" {
+				return "", fmt.Errorf("load takes level and a path")
+			}
+			data, err := readLevel(args[1])
+			if err != nil {
+				return "", err
+			}
+			playFrom(args[1], data)
+			return "loaded " + args[1], nil
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 1 {
+				return nil
+			}
+			return []string{"level"}
+		},
+	})
+
 	// Create and set initial scene
-	scene := sandbox.New()
-	game.SetScene(scene)
+	switch *sceneName {
+	case "sandbox":
+		playFrom(startPath, startData)
+	case "menu":
+		showMenu()
+	case "overworld":
+		if err := showOverworld(""); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown scene %q (want sandbox, menu or overworld)", *sceneName)
+	}
 
 	// Run the game
 	if err := game.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// readLevel reads the level file at path, refusing levels the editor would
+// reject.
+func readLevel(path string) ([]byte, error) {
+	data, err := storage.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read level: %w", err)
+	}
+	if err := levelcheck.Check(data, path); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
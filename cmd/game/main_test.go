@@ -0,0 +1,30 @@
+//go:build display
+
+package main
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/levelcheck"
+	"github.com/torsten/GoP/internal/rules"
+)
+
+func TestBuiltinLevelRules(t *testing.T) {
+	// Asset paths are relative to the repository root
+	t.Chdir("../..")
+
+	data, err := rules.ReadLevelFile(builtinLevelPath)
+	if err != nil {
+		t.Fatalf("ReadLevelFile(%s) failed: %v", builtinLevelPath, err)
+	}
+	ruleSet, err := rules.ParseYAML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ruleSet.Rules) == 0 {
+		t.Errorf("%s has no rules (looked for %s)", builtinLevelPath, rules.PathForLevel(builtinLevelPath))
+	}
+	if n := levelcheck.RuleCount(builtinLevelPath); n != len(ruleSet.Rules) {
+		t.Errorf("RuleCount = %d, want %d", n, len(ruleSet.Rules))
+	}
+}
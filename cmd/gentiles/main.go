@@ -1,208 +1,463 @@
-// gentiles generates a sample tileset PNG for the tilemap system.
-// The tileset is 128x128 pixels with 8x8 tiles of 16x16 pixels each.
+// Command gentiles generates a tileset PNG for the tilemap system from a
+// tile definition file, plus a matching Tiled tileset descriptor (.tsj).
+//
+// Usage:
+//
+//	go run ./cmd/gentiles
+//	go run ./cmd/gentiles -defs my_tiles.json -tile 32 -cols 4 -rows 4 -o out/tiles.png
+//
+// The definition file lists the tiles in ID order; a tile with an "id"
+// skips ahead to that ID, leaving the cells before it to placeholders.
+// Each tile has a base
+// color, optionally with noise, and overlays painting a color over the
+// pixels of a pattern:
+//
+//	{
+//	  "tiles": [
+//	    {"name": "grass", "color": "#8b5a2b", "overlays": [
+//	      {"pattern": "all", "color": "#228b22", "bottom": 4}
+//	    ]},
+//	    {"name": "stone", "color": "#808080", "noise": {"x": 3, "y": 7, "mod": 40, "offset": -20}}
+//	  ]
+//	}
+//
+// Patterns are "all", "modulo" ((x*px + y*py + offset) % mod < below), "bricks"
+// (the mortar of a brick wall), "lines" (whole columns and rows), "points"
+// and "border" (the tile's edge). Overlays apply in order, and top/bottom
+// limit them to a band of rows. Cells without a definition get a numbered
+// placeholder color with a white border. Pattern coordinates are pixels, so
+// definitions written for one tile size keep their detail size at others.
+//
+// A tile can be animated by naming the tiles it cycles through, usually
+// starting with itself, and how long each shows in milliseconds:
+//
+//	{"name": "water", "color": "#4169e1", "animation": {"frames": ["water", "water_2"], "duration": 300}}
+//
+// The animation is written to the .tsj, which the game and editor read.
 package main
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
 	"image"
 	"image/color"
 	"image/png"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-const (
-	tileSize  = 16
-	tilesX    = 8
-	tilesY    = 8
-	imageSize = tileSize * tilesX // 128x128
-)
+// tileDefs is the contents of a tile definition file.
+type tileDefs struct {
+	Tiles []tileDef `json:"tiles"`
 
-func main() {
-	img := image.NewRGBA(image.Rect(0, 0, imageSize, imageSize))
+	byID map[int]tileDef // Set by loadDefs
+	last int             // Highest tile ID
+}
 
-	// Generate each tile
-	for ty := 0; ty < tilesY; ty++ {
-		for tx := 0; tx < tilesX; tx++ {
-			tileID := ty*tilesX + tx
-			drawTile(img, tx, ty, tileID)
-		}
+// tileDef describes how one tile is drawn.
+type tileDef struct {
+	ID       *int       `json:"id,omitempty"` // Defaults to the next ID
+	Name     string     `json:"name"`
+	Color    hexColor   `json:"color"`
+	Noise    *noise     `json:"noise,omitempty"`
+	Overlays []overlay  `json:"overlays,omitempty"`
+	Props    properties `json:"properties,omitempty"` // Copied into the .tsj
+
+	Animation *animation `json:"animation,omitempty"`
+}
+
+// animation cycles a tile through the named tiles.
+type animation struct {
+	Frames   []string `json:"frames"`
+	Duration int      `json:"duration"` // Milliseconds per frame
+}
+
+// noise varies the base color per pixel by (x*px + y*py) % mod + offset,
+// added to each channel.
+type noise struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Mod    int `json:"mod"`
+	Offset int `json:"offset"`
+}
+
+// overlay paints Color over the pixels of a pattern.
+type overlay struct {
+	Pattern string   `json:"pattern"`
+	Color   hexColor `json:"color"`
+
+	// Top and Bottom limit the overlay to rows Top to Bottom (exclusive);
+	// Bottom 0 is the tile's height
+	Top    int `json:"top,omitempty"`
+	Bottom int `json:"bottom,omitempty"`
+
+	// modulo
+	X     int `json:"x,omitempty"`
+	Y     int `json:"y,omitempty"`
+	Mod   int `json:"mod,omitempty"`
+	Below int `json:"below,omitempty"`
+
+	// bricks: mortar every BrickHeight rows and BrickWidth columns, every
+	// other row shifted by Offset. modulo adds Offset, e.g. to shift the
+	// pattern between animation frames
+	BrickWidth  int `json:"brickWidth,omitempty"`
+	BrickHeight int `json:"brickHeight,omitempty"`
+	Offset      int `json:"offset,omitempty"`
+
+	// lines
+	Columns []int `json:"columns,omitempty"`
+	Rows    []int `json:"rows,omitempty"`
+
+	// points, as [x, y]
+	Points [][2]int `json:"points,omitempty"`
+}
+
+// properties are custom tile properties for Tiled.
+type properties map[string]any
+
+// hexColor is a color written as "#rrggbb" or "#rrggbbaa".
+type hexColor color.RGBA
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *hexColor) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 6 {
+		hex += "ff"
 	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return fmt.Errorf("invalid color %q, want #rrggbb or #rrggbbaa", s)
+	}
+	*c = hexColor{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}
+	return nil
+}
 
-	// Create output directory
-	outDir := "assets/tiles"
-	if err := os.MkdirAll(outDir, 0755); err != nil {
-		panic(err)
+// validate checks the overlay's pattern and its parameters.
+func (o overlay) validate() error {
+	switch o.Pattern {
+	case "all", "lines", "points", "border":
+	case "modulo":
+		if o.Mod <= 0 {
+			return fmt.Errorf("modulo pattern needs a positive mod")
+		}
+	case "bricks":
+		if o.BrickWidth <= 0 || o.BrickHeight <= 0 {
+			return fmt.Errorf("bricks pattern needs a positive brickWidth and brickHeight")
+		}
+	default:
+		return fmt.Errorf("unknown pattern %q", o.Pattern)
 	}
+	return nil
+}
 
-	// Write PNG file
-	outPath := filepath.Join(outDir, "tiles.png")
-	f, err := os.Create(outPath)
-	if err != nil {
-		panic(err)
+// covers reports whether the overlay paints pixel px, py of a size x size
+// tile.
+func (o overlay) covers(px, py, size int) bool {
+	bottom := o.Bottom
+	if bottom == 0 {
+		bottom = size
+	}
+	if py < o.Top || py >= bottom {
+		return false
 	}
-	defer f.Close()
 
-	if err := png.Encode(f, img); err != nil {
-		panic(err)
+	switch o.Pattern {
+	case "all":
+		return true
+	case "modulo":
+		return (o.X*px+o.Y*py+o.Offset)%o.Mod < o.Below
+	case "bricks":
+		offset := 0
+		if (py/o.BrickHeight)%2 == 1 {
+			offset = o.Offset
+		}
+		return py%o.BrickHeight == 0 || (px+offset)%o.BrickWidth == 0
+	case "lines":
+		return contains(o.Columns, px) || contains(o.Rows, py)
+	case "points":
+		for _, p := range o.Points {
+			if p[0] == px && p[1] == py {
+				return true
+			}
+		}
+		return false
+	case "border":
+		return px == 0 || py == 0 || px == size-1 || py == size-1
 	}
+	return false
+}
 
-	println("Generated tileset:", outPath)
+func contains(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
 }
 
-func drawTile(img *image.RGBA, tx, ty, tileID int) {
-	// Calculate pixel offset for this tile
-	offsetX := tx * tileSize
-	offsetY := ty * tileSize
+// pixel returns the color of pixel px, py of the tile.
+func (t tileDef) pixel(px, py, size int) color.RGBA {
+	c := color.RGBA(t.Color)
+	if n := t.Noise; n != nil && n.Mod > 0 {
+		v := (n.X*px+n.Y*py)%n.Mod + n.Offset
+		c = color.RGBA{clamp(int(c.R) + v), clamp(int(c.G) + v), clamp(int(c.B) + v), c.A}
+	}
+	for _, o := range t.Overlays {
+		if o.covers(px, py, size) {
+			c = color.RGBA(o.Color)
+		}
+	}
+	return c
+}
 
-	for py := 0; py < tileSize; py++ {
-		for px := 0; px < tileSize; px++ {
-			var c color.RGBA
+func clamp(v int) uint8 {
+	return uint8(max(0, min(255, v)))
+}
 
-			switch tileID {
-			case 0:
-				// Grass top - green on top, brown on bottom
-				if py < 4 {
-					// Green grass
-					c = color.RGBA{34, 139, 34, 255} // Forest green
-				} else {
-					// Brown dirt
-					c = color.RGBA{139, 90, 43, 255} // Saddle brown
-				}
+// placeholder returns the tile drawn for IDs without a definition: a color
+// derived from the ID with a white border.
+func placeholder(id int) tileDef {
+	return tileDef{
+		Color: hexColor{uint8((id * 37) % 256), uint8((id * 73) % 256), uint8((id * 113) % 256), 255},
+		Overlays: []overlay{
+			{Pattern: "border", Color: hexColor{255, 255, 255, 255}},
+		},
+	}
+}
 
-			case 1:
-				// Dirt - solid brown
-				c = color.RGBA{139, 90, 43, 255}
-				// Add some variation
-				if (px+py)%3 == 0 {
-					c = color.RGBA{120, 80, 35, 255}
-				}
+func main() {
+	defsPath := flag.String("defs", "assets/tiles/tiledefs.json", "tile definition file")
+	tileSize := flag.Int("tile", 16, "tile width and height in pixels")
+	cols := flag.Int("cols", 8, "tiles per row of the sheet")
+	rows := flag.Int("rows", 8, "rows of tiles in the sheet")
+	outPath := flag.String("o", "assets/tiles/tiles.png", "output PNG; the .tsj is written next to it")
+	flag.Parse()
 
-			case 2:
-				// Stone - gray with texture
-				base := uint8(128)
-				variation := int8(((px*3 + py*7) % 40) - 20)
-				c = color.RGBA{
-					R: uint8(int(base) + int(variation)),
-					G: uint8(int(base) + int(variation)),
-					B: uint8(int(base) + int(variation)),
-					A: 255,
-				}
-				// Add cracks
-				if px == 7 || py == 8 {
-					c = color.RGBA{100, 100, 100, 255}
-				}
+	if *tileSize <= 0 || *cols <= 0 || *rows <= 0 {
+		fmt.Fprintln(os.Stderr, "usage: gentiles [-defs tiledefs.json] [-tile 16] [-cols 8] [-rows 8] [-o tiles.png]")
+		os.Exit(2)
+	}
 
-			case 3:
-				// Brick - red-brown brick pattern
-				// Determine brick row
-				brickRow := py / 4
-				brickOffset := 0
-				if brickRow%2 == 1 {
-					brickOffset = 8
-				}
-				brickX := (px + brickOffset) % 16
-
-				// Mortar lines
-				if py%4 == 0 || brickX == 0 {
-					c = color.RGBA{180, 160, 140, 255} // Mortar
-				} else {
-					c = color.RGBA{178, 102, 68, 255} // Brick
-					// Add variation
-					if (px+py)%5 == 0 {
-						c = color.RGBA{160, 90, 55, 255}
-					}
-				}
+	defs, err := loadDefs(*defsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if defs.last >= *cols**rows {
+		fmt.Fprintf(os.Stderr, "Error: %d tiles don't fit a %dx%d sheet\n", defs.last+1, *cols, *rows)
+		os.Exit(1)
+	}
 
-			case 4:
-				// Grass variant with flowers
-				if py < 4 {
-					c = color.RGBA{34, 139, 34, 255}
-					// Add small flowers
-					if (px == 4 || px == 11) && py == 2 {
-						c = color.RGBA{255, 255, 0, 255} // Yellow flower
-					}
-				} else {
-					c = color.RGBA{139, 90, 43, 255}
-				}
+	img := render(defs, *tileSize, *cols, *rows)
+	if err := writePNG(*outPath, img); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing tileset: %v\n", err)
+		os.Exit(1)
+	}
+	tsjPath := strings.TrimSuffix(*outPath, filepath.Ext(*outPath)) + ".tsj"
+	if err := writeTSJ(tsjPath, filepath.Base(*outPath), defs, *tileSize, *cols, *rows); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing tileset descriptor: %v\n", err)
+		os.Exit(1)
+	}
 
-			case 5:
-				// Stone variant with moss
-				base := uint8(128)
-				variation := int8(((px*3 + py*7) % 40) - 20)
-				c = color.RGBA{
-					R: uint8(int(base) + int(variation) - 20),
-					G: uint8(int(base) + int(variation) + 10),
-					B: uint8(int(base) + int(variation) - 10),
-					A: 255,
-				}
-				// Moss patches
-				if (px+py)%7 < 3 && py > 8 {
-					c = color.RGBA{60, 120, 60, 255}
-				}
+	fmt.Printf("Generated tileset: %s (%dx%d tiles of %dpx) and %s\n", *outPath, *cols, *rows, *tileSize, tsjPath)
+}
 
-			case 6:
-				// Dark brick
-				brickRow := py / 4
-				brickOffset := 0
-				if brickRow%2 == 1 {
-					brickOffset = 8
+// loadDefs reads and checks a tile definition file.
+func loadDefs(path string) (*tileDefs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tile definitions: %w", err)
+	}
+	var defs tileDefs
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	defs.byID = make(map[int]tileDef, len(defs.Tiles))
+	ids := make(map[string]int, len(defs.Tiles))
+	next := 0
+	for i, t := range defs.Tiles {
+		if t.ID != nil {
+			if *t.ID < next {
+				return nil, fmt.Errorf("%s: tile %d (%s): id %d is not after the tiles before it", path, i, t.Name, *t.ID)
+			}
+			next = *t.ID
+		}
+		defs.byID[next] = t
+		defs.last = next
+		if t.Name != "" {
+			ids[t.Name] = next
+		}
+		next++
+	}
+	for i, t := range defs.Tiles {
+		for _, o := range t.Overlays {
+			if err := o.validate(); err != nil {
+				return nil, fmt.Errorf("%s: tile %d (%s): %w", path, i, t.Name, err)
+			}
+		}
+		if a := t.Animation; a != nil {
+			if len(a.Frames) == 0 || a.Duration <= 0 {
+				return nil, fmt.Errorf("%s: tile %d (%s): animation needs frames and a positive duration", path, i, t.Name)
+			}
+			for _, name := range a.Frames {
+				if _, ok := ids[name]; !ok {
+					return nil, fmt.Errorf("%s: tile %d (%s): animation frame %q is not a tile", path, i, t.Name, name)
 				}
-				brickX := (px + brickOffset) % 16
+			}
+		}
+	}
+	return &defs, nil
+}
 
-				if py%4 == 0 || brickX == 0 {
-					c = color.RGBA{100, 90, 80, 255}
-				} else {
-					c = color.RGBA{120, 60, 40, 255}
-				}
+// render draws the sheet: the defined tiles at their IDs, placeholders in
+// the other cells.
+func render(defs *tileDefs, size, cols, rows int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size*cols, size*rows))
+	for id := 0; id < cols*rows; id++ {
+		t, ok := defs.byID[id]
+		if !ok {
+			t = placeholder(id)
+		}
+		offsetX, offsetY := (id%cols)*size, (id/cols)*size
+		for py := 0; py < size; py++ {
+			for px := 0; px < size; px++ {
+				img.SetRGBA(offsetX+px, offsetY+py, t.pixel(px, py, size))
+			}
+		}
+	}
+	return img
+}
 
-			case 7:
-				// Wood planks
-				if px%4 == 0 {
-					c = color.RGBA{100, 70, 40, 255} // Gap
-				} else {
-					c = color.RGBA{160, 120, 80, 255} // Wood
-					// Wood grain
-					if py%3 == 0 {
-						c = color.RGBA{140, 100, 60, 255}
-					}
-				}
+// writePNG writes img to path, creating the directory if needed.
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-			case 8:
-				// Sand
-				c = color.RGBA{238, 214, 175, 255}
-				if (px*2+py*3)%7 == 0 {
-					c = color.RGBA{220, 200, 160, 255}
-				}
+// tsjTileset is a Tiled JSON tileset.
+type tsjTileset struct {
+	Type        string    `json:"type"`
+	Version     string    `json:"version"`
+	Name        string    `json:"name"`
+	Image       string    `json:"image"`
+	ImageWidth  int       `json:"imagewidth"`
+	ImageHeight int       `json:"imageheight"`
+	TileWidth   int       `json:"tilewidth"`
+	TileHeight  int       `json:"tileheight"`
+	TileCount   int       `json:"tilecount"`
+	Columns     int       `json:"columns"`
+	Margin      int       `json:"margin"`
+	Spacing     int       `json:"spacing"`
+	Tiles       []tsjTile `json:"tiles,omitempty"`
+}
 
-			case 9:
-				// Water (solid blue for now)
-				c = color.RGBA{65, 105, 225, 255}
-				// Wave pattern
-				if (px+py)%5 < 2 {
-					c = color.RGBA{100, 149, 237, 255}
-				}
+// tsjTile holds a tile's custom properties and animation.
+type tsjTile struct {
+	ID         int           `json:"id"`
+	Properties []tsjProperty `json:"properties"`
+	Animation  []tsjFrame    `json:"animation,omitempty"`
+}
 
-			case 10:
-				// Lava
-				c = color.RGBA{255, 80, 0, 255}
-				if (px*2+py)%4 < 2 {
-					c = color.RGBA{255, 160, 0, 255}
-				}
+// tsjFrame is a frame of a tile animation.
+type tsjFrame struct {
+	TileID   int `json:"tileid"`
+	Duration int `json:"duration"`
+}
 
-			default:
-				// Placeholder tiles - colored squares with number pattern
-				baseR := uint8((tileID * 37) % 256)
-				baseG := uint8((tileID * 73) % 256)
-				baseB := uint8((tileID * 113) % 256)
-				c = color.RGBA{baseR, baseG, baseB, 255}
+type tsjProperty struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
 
-				// Add border
-				if px == 0 || py == 0 || px == 15 || py == 15 {
-					c = color.RGBA{255, 255, 255, 255}
-				}
+// writeTSJ writes the Tiled tileset for the sheet image. Each defined tile
+// gets its name and properties as custom properties, and its animation.
+func writeTSJ(path, image string, defs *tileDefs, size, cols, rows int) error {
+	ts := tsjTileset{
+		Type:        "tileset",
+		Version:     "1.10",
+		Name:        strings.TrimSuffix(image, filepath.Ext(image)),
+		Image:       image,
+		ImageWidth:  size * cols,
+		ImageHeight: size * rows,
+		TileWidth:   size,
+		TileHeight:  size,
+		TileCount:   cols * rows,
+		Columns:     cols,
+	}
+	ids := make(map[string]int, len(defs.byID))
+	for id, t := range defs.byID {
+		ids[t.Name] = id
+	}
+	for id := 0; id <= defs.last; id++ {
+		t, ok := defs.byID[id]
+		if !ok {
+			continue
+		}
+		props := []tsjProperty{}
+		if t.Name != "" {
+			props = append(props, tsjProperty{Name: "name", Type: "string", Value: t.Name})
+		}
+		for _, name := range sortedKeys(t.Props) {
+			props = append(props, tsjProperty{Name: name, Type: tiledType(t.Props[name]), Value: t.Props[name]})
+		}
+		tile := tsjTile{ID: id, Properties: props}
+		if a := t.Animation; a != nil {
+			for _, name := range a.Frames {
+				tile.Animation = append(tile.Animation, tsjFrame{TileID: ids[name], Duration: a.Duration})
 			}
+		}
+		if len(props) > 0 || len(tile.Animation) > 0 {
+			ts.Tiles = append(ts.Tiles, tile)
+		}
+	}
 
-			img.SetRGBA(offsetX+px, offsetY+py, c)
+	data, err := json.MarshalIndent(ts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// tiledType returns the Tiled property type for a JSON value.
+func tiledType(v any) string {
+	switch v := v.(type) {
+	case bool:
+		return "bool"
+	case float64:
+		if v == float64(int(v)) {
+			return "int"
 		}
+		return "float"
+	default:
+		return "string"
+	}
+}
+
+func sortedKeys(p properties) []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
+	return keys
 }
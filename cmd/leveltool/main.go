@@ -0,0 +1,481 @@
+// Command leveltool validates, inspects, converts, and reshapes level files
+// for CI and content pipelines.
+//
+// Usage:
+//
+//	go run ./cmd/leveltool validate [-strict] [-json] level.json...
+//	go run ./cmd/leveltool stats level.json...
+//	go run ./cmd/leveltool convert -o level.tmx level.json
+//	go run ./cmd/leveltool resize -w 100 -h 30 [-outdir dir] level.json...
+//	go run ./cmd/leveltool crop -x 10 -y 0 -w 40 -h 25 [-outdir dir] level.json...
+//	go run ./cmd/leveltool diff [-json] old.json new.json
+//	go run ./cmd/leveltool stress -w 1000 -h 1000 -objects 10000 [-seed 1] -o stress.json
+//
+// Levels may be Tiled JSON or TMX; the format follows the file extension.
+// resize and crop overwrite their inputs unless -outdir is given.
+// validate -json prints a report in the format described by cmd/schema.
+// diff reports changed tiles per layer and region, added, removed and
+// modified objects, and property changes; like diff(1) it exits 1 when the
+// levels differ. stress generates a huge level with many objects for
+// trying the game and editor on (see tiled.StressMap); write it next to
+// the other levels, in assets/levels/, for its tileset to be found.
+//
+// validate and stats load levels through the game's world package, which
+// links ebiten, so on a headless CI machine run them under xvfb-run.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/torsten/GoP/internal/levelcheck"
+	"github.com/torsten/GoP/internal/schema"
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+// errFailed reports that a command ran but found problems.
+var errFailed = errors.New("failed")
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]func(args []string) error{
+	"validate": runValidate,
+	"stats":    runStats,
+	"convert":  runConvert,
+	"resize":   runResize,
+	"crop":     runCrop,
+	"diff":     runDiff,
+	"stress":   runStress,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := commands[os.Args[1]](os.Args[2:]); err != nil {
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintf(os.Stderr, "leveltool: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// usage prints the list of subcommands.
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: leveltool <command> [flags] <level>...
+
+commands:
+  validate  check levels for errors (exit 1 on errors, or warnings with -strict)
+  stats     print tile usage, object counts, and budget usage
+  convert   convert between Tiled JSON and TMX (-o output)
+  resize    change the level size in tiles (-w, -h)
+  crop      cut the level to a tile rectangle (-x, -y, -w, -h)
+  diff      compare two versions of a level (exit 1 if they differ, -json)
+  stress    generate a huge level with many objects (-w, -h, -objects, -o)`)
+}
+
+// readMap reads a level file in either format.
+func readMap(path string) (*tiled.Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := tiled.Parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// writeMap writes a level file in the format given by its extension.
+func writeMap(path string, m *tiled.Map) error {
+	data, err := m.Encode(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// loadLevel reads a level for the shared level checks.
+func loadLevel(path string) (levelcheck.Level, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return levelcheck.Level{}, err
+	}
+	if tiled.IsTMX(path) {
+		m, err := tiled.ParseTMX(data)
+		if err != nil {
+			return levelcheck.Level{}, fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = m.EncodeJSON(); err != nil {
+			return levelcheck.Level{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	level, err := levelcheck.ParseLevel(data, path)
+	if err != nil {
+		return levelcheck.Level{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return level, nil
+}
+
+// runValidate checks each level and prints its issues.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	strict := fs.Bool("strict", false, "treat warnings as failures")
+	jsonOut := fs.Bool("json", false, "print a JSON validation report")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("validate: no levels given")
+	}
+
+	report := schema.NewReport()
+	failed := false
+	for _, path := range fs.Args() {
+		level, err := loadLevel(path)
+		if err != nil {
+			failed = true
+			if *jsonOut {
+				report.Levels = append(report.Levels, schema.LevelReport{
+					Path:     path,
+					Errors:   []schema.Issue{{Message: err.Error(), Object: -1}},
+					Warnings: []schema.Issue{},
+				})
+			} else {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			continue
+		}
+
+		result := levelcheck.Validate(level)
+		if result.HasErrors() || (*strict && result.HasWarnings()) {
+			failed = true
+		}
+		if *jsonOut {
+			report.Levels = append(report.Levels, result.Report(path))
+			continue
+		}
+
+		for _, issue := range result.AllIssues() {
+			where := ""
+			if issue.ObjectIndex >= 0 && issue.ObjectIndex < len(level.Objects) {
+				obj := level.Objects[issue.ObjectIndex]
+				where = fmt.Sprintf(" [object %d %s %q]", obj.ID, obj.Type, obj.Name)
+			}
+			fmt.Printf("%s: %s%s: %s\n", path, strings.ToUpper(string(issue.Type)), where, levelcheck.Format(issue))
+		}
+		fmt.Printf("%s: %d errors, %d warnings\n", path, result.ErrorCount(), result.WarningCount())
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	}
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// runStats prints statistics for each level.
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	top := fs.Int("top", 10, "number of most-used tiles to list per layer")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("stats: no levels given")
+	}
+
+	for i, path := range fs.Args() {
+		m, err := readMap(path)
+		if err != nil {
+			return err
+		}
+		level, err := loadLevel(path)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%s\n", path)
+		fmt.Printf("  size: %dx%d tiles (%dx%d px)\n", m.Width, m.Height, m.Width*m.TileWidth, m.Height*m.TileHeight)
+
+		for _, l := range m.Layers {
+			if l.Type != tiled.LayerTiles {
+				continue
+			}
+			usage := l.TileUsage()
+			filled := 0
+			for _, n := range usage {
+				filled += n
+			}
+			tiles := len(l.Data)
+			for _, c := range l.Chunks {
+				tiles += len(c.Data)
+			}
+			fmt.Printf("  layer %q: %d/%d tiles filled, %d distinct\n", l.Name, filled, tiles, len(usage))
+			for _, gid := range mostUsed(usage, *top) {
+				fmt.Printf("    gid %4d: %d\n", gid, usage[gid])
+			}
+		}
+
+		counts := make(map[string]int)
+		for _, obj := range level.Objects {
+			counts[string(obj.Type)]++
+		}
+		fmt.Printf("  objects: %d\n", len(level.Objects))
+		for _, typ := range sortedKeys(counts) {
+			fmt.Printf("    %-12s %d\n", typ, counts[typ])
+		}
+
+		budget, usage := levelcheck.Budget(level)
+		fmt.Printf("  budget: %s\n", budget.Summary(usage))
+	}
+	return nil
+}
+
+// runConvert converts a level between JSON and TMX.
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	out := fs.String("o", "", "output file (.json or .tmx)")
+	fs.Parse(args)
+	if fs.NArg() != 1 || *out == "" {
+		return fmt.Errorf("usage: leveltool convert -o <output> <input>")
+	}
+
+	m, err := readMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := writeMap(*out, m); err != nil {
+		return err
+	}
+	fmt.Printf("%s -> %s\n", fs.Arg(0), *out)
+	return nil
+}
+
+// runStress writes a generated stress level.
+func runStress(args []string) error {
+	fs := flag.NewFlagSet("stress", flag.ExitOnError)
+	w := fs.Int("w", 1000, "width in tiles")
+	h := fs.Int("h", 1000, "height in tiles")
+	objects := fs.Int("objects", 10000, "number of objects besides the spawn and goal")
+	seed := fs.Int64("seed", 1, "random seed; the same seed gives the same level")
+	out := fs.String("o", "", "output file (.json or .tmx)")
+	fs.Parse(args)
+	if fs.NArg() != 0 || *out == "" {
+		return fmt.Errorf("usage: leveltool stress [-w n] [-h n] [-objects n] [-seed n] -o <output>")
+	}
+
+	m, err := tiled.StressMap(*w, *h, *objects, *seed)
+	if err != nil {
+		return err
+	}
+	if err := writeMap(*out, m); err != nil {
+		return err
+	}
+	fmt.Printf("%s: %dx%d tiles, %d objects\n", *out, *w, *h, *objects+2)
+	return nil
+}
+
+// runResize changes the size of each level.
+func runResize(args []string) error {
+	fs := flag.NewFlagSet("resize", flag.ExitOnError)
+	w := fs.Int("w", 0, "new width in tiles")
+	h := fs.Int("h", 0, "new height in tiles")
+	outDir := fs.String("outdir", "", "write results here instead of overwriting inputs")
+	fs.Parse(args)
+
+	return reshape(fs.Args(), *outDir, func(m *tiled.Map) (int, error) {
+		return m.Resize(*w, *h)
+	})
+}
+
+// runCrop cuts each level to a tile rectangle.
+func runCrop(args []string) error {
+	fs := flag.NewFlagSet("crop", flag.ExitOnError)
+	x := fs.Int("x", 0, "left tile column")
+	y := fs.Int("y", 0, "top tile row")
+	w := fs.Int("w", 0, "width in tiles")
+	h := fs.Int("h", 0, "height in tiles")
+	outDir := fs.String("outdir", "", "write results here instead of overwriting inputs")
+	fs.Parse(args)
+
+	return reshape(fs.Args(), *outDir, func(m *tiled.Map) (int, error) {
+		return m.Crop(*x, *y, *w, *h)
+	})
+}
+
+// runDiff compares two level files and prints what changed.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "print the differences as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: leveltool diff [-json] <old> <new>")
+	}
+
+	before, err := readMap(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := readMap(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	d := tiled.Compare(before, after)
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printDiff(fs.Arg(0), fs.Arg(1), d)
+	}
+	if !d.Empty() {
+		return errFailed
+	}
+	return nil
+}
+
+// printDiff prints the differences between two levels, one change a line.
+func printDiff(oldPath, newPath string, d *tiled.Diff) {
+	fmt.Printf("%s -> %s\n", oldPath, newPath)
+	if d.Empty() {
+		fmt.Println("  no differences")
+		return
+	}
+
+	if s := d.Size; s != nil {
+		fmt.Printf("  size: %dx%d -> %dx%d tiles\n", s.OldWidth, s.OldHeight, s.NewWidth, s.NewHeight)
+	}
+	printProperties("  property", d.Properties)
+	for _, l := range d.Layers {
+		switch l.Change {
+		case tiled.Modified:
+			fmt.Printf("  layer %q: %s changed in %s\n", l.Name, plural(l.Tiles, "tile"), plural(len(l.Regions), "region"))
+		default:
+			fmt.Printf("  layer %q: %s, %s\n", l.Name, l.Change, plural(l.Tiles, "tile"))
+		}
+		for _, r := range l.Regions {
+			fmt.Printf("    %s: %s\n", r, plural(r.Changed, "tile"))
+		}
+		printProperties(fmt.Sprintf("    layer %q property", l.Name), l.Properties)
+	}
+	for _, o := range d.Objects {
+		name := ""
+		if o.Name != "" {
+			name = fmt.Sprintf(" %q", o.Name)
+		}
+		prefix := fmt.Sprintf("  object %d %s%s", o.ID, o.Type, name)
+		if o.Change != tiled.Modified {
+			fmt.Printf("%s: %s at %g,%g\n", prefix, o.Change, o.X, o.Y)
+			continue
+		}
+		for _, f := range o.Fields {
+			fmt.Printf("%s: %s %v -> %v\n", prefix, f.Field, f.Old, f.New)
+		}
+		printProperties(prefix+": property", o.Properties)
+	}
+}
+
+// printProperties prints property changes, each line starting with prefix.
+func printProperties(prefix string, changes []tiled.PropertyChange) {
+	for _, p := range changes {
+		switch {
+		case p.Old == nil:
+			fmt.Printf("%s %s: added %#v\n", prefix, p.Name, p.New)
+		case p.New == nil:
+			fmt.Printf("%s %s: removed (was %#v)\n", prefix, p.Name, p.Old)
+		default:
+			fmt.Printf("%s %s: %#v -> %#v\n", prefix, p.Name, p.Old, p.New)
+		}
+	}
+}
+
+// plural formats a count with a noun, adding "s" unless it's one.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// reshape applies fn to each level and writes the result.
+func reshape(paths []string, outDir string, fn func(*tiled.Map) (int, error)) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no levels given")
+	}
+	if outDir != "" {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range paths {
+		m, err := readMap(path)
+		if err != nil {
+			return err
+		}
+		removed, err := fn(m)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		out := path
+		if outDir != "" {
+			out = filepath.Join(outDir, filepath.Base(path))
+		}
+		if err := writeMap(out, m); err != nil {
+			return err
+		}
+
+		fmt.Printf("%s: %dx%d tiles", out, m.Width, m.Height)
+		if removed > 0 {
+			fmt.Printf(", removed %d objects outside the level", removed)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// mostUsed returns up to n GIDs ordered by usage, most used first.
+func mostUsed(usage map[int]int, n int) []int {
+	gids := make([]int, 0, len(usage))
+	for gid := range usage {
+		gids = append(gids, gid)
+	}
+	sort.Slice(gids, func(i, j int) bool {
+		if usage[gids[i]] != usage[gids[j]] {
+			return usage[gids[i]] > usage[gids[j]]
+		}
+		return gids[i] < gids[j]
+	})
+	if len(gids) > n {
+		gids = gids[:n]
+	}
+	return gids
+}
+
+// sortedKeys returns the map's keys in order.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
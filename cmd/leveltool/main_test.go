@@ -0,0 +1,78 @@
+//go:build display
+
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+var compressions = []string{"", tiled.CompressionZlib, tiled.CompressionGzip}
+
+// compressedLevel writes level_01 with base64 tile data, compressed with
+// compression, and returns its path and map.
+func compressedLevel(t *testing.T, compression string) (string, *tiled.Map) {
+	t.Helper()
+	m, err := readMap("../../assets/levels/level_01.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range m.Layers {
+		if m.Layers[i].Type == "tilelayer" {
+			m.Layers[i].Encoding = tiled.EncodingBase64
+			m.Layers[i].Compression = compression
+		}
+	}
+	path := filepath.Join(t.TempDir(), "level.json")
+	if err := writeMap(path, m); err != nil {
+		t.Fatal(err)
+	}
+	return path, m
+}
+
+func TestConvertCompressedLevel(t *testing.T) {
+	for _, compression := range compressions {
+		in, want := compressedLevel(t, compression)
+
+		// JSON -> TMX -> JSON keeps the tiles and how they're stored
+		tmx := filepath.Join(filepath.Dir(in), "level.tmx")
+		out := filepath.Join(filepath.Dir(in), "out.json")
+		if err := runConvert([]string{"-o", tmx, in}); err != nil {
+			t.Fatalf("%q: convert to TMX failed: %v", compression, err)
+		}
+		if err := runConvert([]string{"-o", out, tmx}); err != nil {
+			t.Fatalf("%q: convert to JSON failed: %v", compression, err)
+		}
+		got, err := readMap(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, l := range got.Layers {
+			w := want.Layers[i]
+			if l.Type != "tilelayer" {
+				continue
+			}
+			if l.Encoding != w.Encoding || l.Compression != w.Compression {
+				t.Errorf("%q: layer %q stored as %q/%q, want %q/%q", compression, l.Name, l.Encoding, l.Compression, w.Encoding, w.Compression)
+			}
+			if !slices.Equal(l.Data, w.Data) {
+				t.Errorf("%q: layer %q tiles changed", compression, l.Name)
+			}
+		}
+	}
+}
+
+func TestCheckCompressedLevel(t *testing.T) {
+	for _, compression := range compressions {
+		path, _ := compressedLevel(t, compression)
+		if err := runValidate([]string{path}); err != nil {
+			t.Errorf("%q: validate failed: %v", compression, err)
+		}
+		if err := runStats([]string{path}); err != nil {
+			t.Errorf("%q: stats failed: %v", compression, err)
+		}
+	}
+}
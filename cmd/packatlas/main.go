@@ -0,0 +1,252 @@
+// Command packatlas packs a directory of individual PNG frames into a single
+// power-of-two atlas image with a JSON manifest mapping frame names to rects.
+//
+// Usage:
+//
+//	go run ./cmd/packatlas -in frames/player -out assets/sprites/player.png
+//
+// The manifest is written next to the atlas with a .json extension. Frame
+// names are the PNG file names without extension; frames numbered like
+// run_1 ... run_10 are packed in numeric order.
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxAtlasSize is the largest atlas dimension the packer will produce.
+const maxAtlasSize = 4096
+
+// atlasRect and atlasManifest mirror assets.AtlasRect and assets.AtlasManifest.
+// They are duplicated here so the tool doesn't depend on ebiten and runs headless.
+type atlasRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type atlasManifest struct {
+	Image  string               `json:"image"`
+	Width  int                  `json:"width"`
+	Height int                  `json:"height"`
+	Frames map[string]atlasRect `json:"frames"`
+}
+
+// compareFrameNames mirrors assets.CompareFrameNames: names differing only
+// in a trailing number are ordered by its value, so "run_2" comes before
+// "run_10".
+func compareFrameNames(a, b string) int {
+	aBase, aNum := splitFrameNumber(a)
+	bBase, bNum := splitFrameNumber(b)
+	return cmp.Or(strings.Compare(aBase, bBase), cmp.Compare(aNum, bNum), strings.Compare(a, b))
+}
+
+// splitFrameNumber mirrors the assets function of the same name.
+func splitFrameNumber(name string) (string, int) {
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(name[i:])
+	if err != nil {
+		return name, -1
+	}
+	return name[:i], n
+}
+
+// frame is a single input image to be packed.
+type frame struct {
+	name string
+	img  image.Image
+	x, y int
+}
+
+func main() {
+	inDir := flag.String("in", "", "directory containing PNG frames")
+	outPath := flag.String("out", "atlas.png", "output atlas PNG path")
+	padding := flag.Int("padding", 1, "padding in pixels between frames")
+	flag.Parse()
+
+	if *inDir == "" {
+		fmt.Fprintln(os.Stderr, "usage: packatlas -in <dir> [-out atlas.png] [-padding 1]")
+		os.Exit(2)
+	}
+
+	frames, err := loadFrames(*inDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading frames: %v\n", err)
+		os.Exit(1)
+	}
+	if len(frames) == 0 {
+		fmt.Fprintf(os.Stderr, "No PNG files found in %s\n", *inDir)
+		os.Exit(1)
+	}
+
+	width, height, err := pack(frames, *padding)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error packing frames: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeAtlas(*outPath, frames, width, height); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing atlas: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Packed %d frames into %s (%dx%d)\n", len(frames), *outPath, width, height)
+}
+
+// loadFrames decodes all PNG files in a directory, in compareFrameNames
+// order.
+func loadFrames(dir string) ([]*frame, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var frames []*frame
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".png") {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", entry.Name(), err)
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		frames = append(frames, &frame{name: name, img: img})
+	}
+
+	slices.SortFunc(frames, func(a, b *frame) int {
+		return compareFrameNames(a.name, b.name)
+	})
+	return frames, nil
+}
+
+// pack assigns positions to all frames using shelf packing and returns the
+// power-of-two atlas size. The width is doubled until everything fits in a
+// roughly square atlas.
+func pack(frames []*frame, padding int) (int, int, error) {
+	// Pack tallest frames first so shelves waste less space
+	order := make([]*frame, len(frames))
+	copy(order, frames)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].img.Bounds().Dy() > order[j].img.Bounds().Dy()
+	})
+
+	// Start with the smallest power of two that fits the widest frame
+	maxW := 0
+	for _, f := range frames {
+		if w := f.img.Bounds().Dx() + padding; w > maxW {
+			maxW = w
+		}
+	}
+	width := nextPowerOfTwo(maxW)
+
+	for width <= maxAtlasSize {
+		usedHeight := shelfPack(order, width, padding)
+		height := nextPowerOfTwo(usedHeight)
+		if height <= width || width == maxAtlasSize {
+			if height > maxAtlasSize {
+				break
+			}
+			return width, height, nil
+		}
+		width *= 2
+	}
+
+	return 0, 0, fmt.Errorf("frames don't fit in a %dx%d atlas", maxAtlasSize, maxAtlasSize)
+}
+
+// shelfPack places frames left-to-right in rows of the given width.
+// Returns the total height used.
+func shelfPack(frames []*frame, width, padding int) int {
+	x, y, shelfHeight := 0, 0, 0
+	for _, f := range frames {
+		w := f.img.Bounds().Dx()
+		h := f.img.Bounds().Dy()
+
+		// Start a new shelf if this frame doesn't fit on the current one
+		if x > 0 && x+w > width {
+			x = 0
+			y += shelfHeight + padding
+			shelfHeight = 0
+		}
+
+		f.x = x
+		f.y = y
+		x += w + padding
+		if h > shelfHeight {
+			shelfHeight = h
+		}
+	}
+	return y + shelfHeight
+}
+
+// nextPowerOfTwo returns the smallest power of two >= n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+// writeAtlas writes the atlas PNG and its JSON manifest.
+func writeAtlas(outPath string, frames []*frame, width, height int) error {
+	atlas := image.NewRGBA(image.Rect(0, 0, width, height))
+	manifest := atlasManifest{
+		Image:  filepath.Base(outPath),
+		Width:  width,
+		Height: height,
+		Frames: make(map[string]atlasRect, len(frames)),
+	}
+
+	for _, f := range frames {
+		b := f.img.Bounds()
+		dst := image.Rect(f.x, f.y, f.x+b.Dx(), f.y+b.Dy())
+		draw.Draw(atlas, dst, f.img, b.Min, draw.Src)
+		manifest.Frames[f.name] = atlasRect{X: f.x, Y: f.y, W: b.Dx(), H: b.Dy()}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, atlas); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	manifestPath := strings.TrimSuffix(outPath, filepath.Ext(outPath)) + ".json"
+	return os.WriteFile(manifestPath, append(data, '\n'), 0644)
+}
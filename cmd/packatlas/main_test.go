@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// solidFrame returns a frame of the given size filled with one color.
+func solidFrame(name string, w, h int, c color.RGBA) *frame {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return &frame{name: name, img: img}
+}
+
+func TestCompareFrameNames(t *testing.T) {
+	names := []string{"run_10", "idle", "run_2", "run_1a", "run", "run_02", "jump_3", "run_1", "jump_12"}
+	slices.SortFunc(names, compareFrameNames)
+	want := []string{"idle", "jump_3", "jump_12", "run", "run_1", "run_02", "run_2", "run_10", "run_1a"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("sorted names = %v, want %v", names, want)
+	}
+}
+
+func TestLoadFramesNumericOrder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"run_10.png", "run_2.png", "run_1.png", "notes.txt"} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	frames, err := loadFrames(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range frames {
+		names = append(names, f.name)
+	}
+	if want := []string{"run_1", "run_2", "run_10"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("frames = %v, want %v", names, want)
+	}
+}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name          string
+		sizes         [][2]int
+		padding       int
+		width, height int
+	}{
+		{"one frame", [][2]int{{16, 16}}, 0, 16, 16},
+		{"padding grows the atlas", [][2]int{{16, 16}}, 1, 32, 16},
+		{"shelves", [][2]int{{16, 16}, {16, 16}, {16, 16}, {16, 8}}, 0, 32, 32},
+		{"tall frame first", [][2]int{{8, 8}, {8, 32}, {8, 8}}, 2, 32, 32},
+		{"wide frame", [][2]int{{100, 10}, {10, 10}}, 1, 128, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var frames []*frame
+			for i, s := range tt.sizes {
+				frames = append(frames, solidFrame(string(rune('a'+i)), s[0], s[1], color.RGBA{A: 255}))
+			}
+			width, height, err := pack(frames, tt.padding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if width != tt.width || height != tt.height {
+				t.Errorf("atlas is %dx%d, want %dx%d", width, height, tt.width, tt.height)
+			}
+
+			// Frames fit in the atlas and are padding pixels apart
+			for i, f := range frames {
+				r := image.Rect(f.x, f.y, f.x+f.img.Bounds().Dx(), f.y+f.img.Bounds().Dy())
+				if !r.In(image.Rect(0, 0, width, height)) {
+					t.Errorf("frame %s at %v is outside the atlas", f.name, r)
+				}
+				for _, g := range frames[i+1:] {
+					o := image.Rect(g.x, g.y, g.x+g.img.Bounds().Dx(), g.y+g.img.Bounds().Dy())
+					if r.Inset(-tt.padding).Overlaps(o) {
+						t.Errorf("frames %s at %v and %s at %v are closer than %d pixels", f.name, r, g.name, o, tt.padding)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPackTooLarge(t *testing.T) {
+	frames := []*frame{solidFrame("huge", maxAtlasSize+1, 1, color.RGBA{})}
+	if _, _, err := pack(frames, 0); err == nil {
+		t.Error("packing a frame wider than the largest atlas succeeded")
+	}
+}
+
+func TestWriteAtlas(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	frames := []*frame{solidFrame("run_1", 8, 8, red), solidFrame("run_2", 4, 8, blue)}
+	width, height, err := pack(frames, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "sprites", "player.png")
+	if err := writeAtlas(out, frames, width, height); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(out), "player.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest atlasManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	want := atlasManifest{Image: "player.png", Width: 16, Height: 8, Frames: map[string]atlasRect{
+		"run_1": {X: 0, Y: 0, W: 8, H: 8},
+		"run_2": {X: 9, Y: 0, W: 4, H: 8},
+	}}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Errorf("manifest = %+v, want %+v", manifest, want)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, c := range map[string]color.RGBA{"run_1": red, "run_2": blue} {
+		r := manifest.Frames[name]
+		if got := color.RGBAModel.Convert(img.At(r.X+r.W-1, r.Y+r.H-1)); got != c {
+			t.Errorf("frame %s corner = %v, want %v", name, got, c)
+		}
+	}
+	if got := color.RGBAModel.Convert(img.At(8, 0)); got != (color.RGBA{}) {
+		t.Errorf("padding pixel = %v, want transparent", got)
+	}
+}
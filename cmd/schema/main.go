@@ -0,0 +1,79 @@
+// Command schema emits the versioned JSON Schema documents for GoP's level,
+// rules, and validation report formats, for external tools and CI.
+//
+// Usage:
+//
+//	go run ./cmd/schema level            # print one document
+//	go run ./cmd/schema -o schema/       # write all documents, e.g. schema/level.v1.json
+//	go run ./cmd/schema -o schema/ rules # write the named documents
+//
+// The level document is built from the editor's object schemas, which link
+// ebiten, so on a headless CI machine run it under xvfb-run.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/torsten/GoP/internal/editor"
+	"github.com/torsten/GoP/internal/schema"
+)
+
+func main() {
+	outDir := flag.String("o", "", "directory to write documents to (default: print to stdout)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: schema [-o dir] [%s]...\n", strings.Join(schema.Names(), "|"))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*outDir, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "schema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run prints or writes the named documents.
+func run(outDir string, names []string) error {
+	docs := schema.All(editor.SchemaObjectTypes())
+
+	if len(names) == 0 {
+		if outDir == "" {
+			flag.Usage()
+			os.Exit(2)
+		}
+		names = schema.Names()
+	}
+	if outDir == "" && len(names) > 1 {
+		return fmt.Errorf("printing needs exactly one document; use -o to write several")
+	}
+
+	if outDir != "" {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return err
+		}
+	}
+	for _, name := range names {
+		doc, ok := docs[name]
+		if !ok {
+			return fmt.Errorf("unknown document %q (want %s)", name, strings.Join(schema.Names(), ", "))
+		}
+		data, err := doc.JSON()
+		if err != nil {
+			return err
+		}
+		if outDir == "" {
+			os.Stdout.Write(data)
+			continue
+		}
+		path := filepath.Join(outDir, schema.FileName(name))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+// Command shootlevels renders screenshots of every level for documentation and review.
+//
+// Usage:
+//
+//	go run ./cmd/shootlevels -levels assets/levels -out docs/screenshots -zoom 1,2
+//
+// For each level it writes <level>_full_<zoom>x.png with the whole map and
+// <level>_spawn_<zoom>x.png with the area around the player spawn. Tile layers
+// are composited with object markers on top. Levels are loaded through
+// internal/tiled and rendered on the CPU, so the tool doesn't depend on
+// ebiten and runs headless (e.g. in CI).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+// backgroundColor matches the game's sandbox scene background.
+var backgroundColor = color.RGBA{0x10, 0x10, 0x20, 0xff}
+
+// objectColors mirror the editor's object schema colors.
+var objectColors = map[string]color.RGBA{
+	"spawn":       {0x00, 0xff, 0x00, 0xff},
+	"platform":    {0x80, 0x40, 0xc0, 0xff},
+	"switch":      {0xff, 0xc8, 0x00, 0xff},
+	"door":        {0x00, 0x80, 0xff, 0xff},
+	"hazard":      {0xff, 0x00, 0x00, 0xff},
+	"checkpoint":  {0x00, 0xff, 0xff, 0xff},
+	"goal":        {0xff, 0xd7, 0x00, 0xff},
+	"killplane":   {0xff, 0x40, 0x40, 0xff},
+	"light":       {0xff, 0xf0, 0xa0, 0xff},
+	"collectible": {0xff, 0xdc, 0x40, 0xff},
+}
+
+// unknownObjectColor is used for object types missing from objectColors.
+var unknownObjectColor = color.RGBA{0xff, 0x00, 0xff, 0xff}
+
+// spawnMarkerSize is the size of the spawn marker, matching the player size.
+const spawnMarkerSize = 12
+
+func main() {
+	levelsDir := flag.String("levels", "assets/levels", "directory containing level JSON files")
+	tilesetPath := flag.String("tileset", "assets/tiles/tiles.png", "tileset PNG")
+	outDir := flag.String("out", "docs/screenshots", "output directory")
+	zoomList := flag.String("zoom", "1,2", "comma-separated zoom levels")
+	spawnW := flag.Int("spawn-w", 640, "width of the spawn area in pixels (0 disables)")
+	spawnH := flag.Int("spawn-h", 360, "height of the spawn area in pixels")
+	objects := flag.Bool("objects", true, "draw object markers")
+	flag.Parse()
+
+	zooms, err := parseZooms(*zoomList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	tileset, err := loadPNG(*tilesetPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	levels, err := filepath.Glob(filepath.Join(*levelsDir, "*.json"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(levels) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no levels found in %s\n", *levelsDir)
+		os.Exit(1)
+	}
+	sort.Strings(levels)
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, path := range levels {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if err := shootLevel(path, name, tileset, *outDir, zooms, *spawnW, *spawnH, *objects); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// shootLevel renders a level and writes its full-map and spawn-area screenshots.
+func shootLevel(path, name string, tileset image.Image, outDir string, zooms []float64, spawnW, spawnH int, objects bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	m, err := tiled.ParseJSON(data)
+	if err != nil {
+		return err
+	}
+
+	full := renderMap(m, tileset, objects)
+	if full.Bounds().Empty() {
+		return fmt.Errorf("level has no tiles to render")
+	}
+
+	// Spawn area, centered on the spawn point and clamped to the map
+	var spawnArea image.Rectangle
+	if spawnW > 0 && spawnH > 0 {
+		if sx, sy, ok := findSpawn(m); ok {
+			spawnArea = centeredRect(sx+spawnMarkerSize/2, sy+spawnMarkerSize/2, spawnW, spawnH, full.Bounds())
+		}
+	}
+
+	for _, z := range zooms {
+		suffix := formatZoom(z)
+
+		fullPath := filepath.Join(outDir, fmt.Sprintf("%s_full_%s.png", name, suffix))
+		if err := savePNG(fullPath, scaleNearest(full, z)); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", fullPath)
+
+		if spawnArea.Empty() {
+			continue
+		}
+		spawnPath := filepath.Join(outDir, fmt.Sprintf("%s_spawn_%s.png", name, suffix))
+		if err := savePNG(spawnPath, scaleNearest(full.SubImage(spawnArea), z)); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", spawnPath)
+	}
+	return nil
+}
+
+// renderMap composites all visible tile layers except Collision, like the
+// game's map renderer, and optionally draws object markers on top. The
+// image's bounds are the map's in pixels (see mapBounds).
+func renderMap(m *tiled.Map, tileset image.Image, objects bool) *image.RGBA {
+	img := image.NewRGBA(mapBounds(m))
+	draw.Draw(img, img.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	cols := tileset.Bounds().Dx() / m.TileWidth
+	rows := tileset.Bounds().Dy() / m.TileHeight
+
+	// drawTiles draws a row-major block of GIDs whose top left is tile x, y
+	drawTiles := func(data []int, width, x, y int) {
+		for i, gid := range data {
+			// Tiled uses 1-based IDs, convert to 0-based
+			id := gid - 1
+			if gid == 0 || id >= cols*rows {
+				continue
+			}
+			tx, ty := x+i%width, y+i/width
+			dst := image.Rect(tx*m.TileWidth, ty*m.TileHeight, (tx+1)*m.TileWidth, (ty+1)*m.TileHeight)
+			src := image.Pt(tileset.Bounds().Min.X+(id%cols)*m.TileWidth, tileset.Bounds().Min.Y+(id/cols)*m.TileHeight)
+			draw.Draw(img, dst, tileset, src, draw.Over)
+		}
+	}
+	for _, layer := range m.Layers {
+		if layer.Type != tiled.LayerTiles || layer.Name == "Collision" || !layer.Visible {
+			continue
+		}
+		drawTiles(layer.Data, layer.Width, 0, 0)
+		for _, c := range layer.Chunks {
+			drawTiles(c.Data, c.Width, c.X, c.Y)
+		}
+	}
+
+	if objects {
+		for _, layer := range m.Layers {
+			if layer.Type != tiled.LayerObjects || !layer.Visible {
+				continue
+			}
+			for _, obj := range layer.Objects {
+				drawObject(img, obj)
+			}
+		}
+	}
+
+	return img
+}
+
+// mapBounds returns the map's area in pixels. That of an infinite map is
+// its chunks', which may start left of or above the origin.
+func mapBounds(m *tiled.Map) image.Rectangle {
+	if !m.Infinite {
+		return image.Rect(0, 0, m.Width*m.TileWidth, m.Height*m.TileHeight)
+	}
+	var r image.Rectangle
+	for _, layer := range m.Layers {
+		for _, c := range layer.Chunks {
+			r = r.Union(image.Rect(c.X*m.TileWidth, c.Y*m.TileHeight, (c.X+c.Width)*m.TileWidth, (c.Y+c.Height)*m.TileHeight))
+		}
+	}
+	return r
+}
+
+// drawObject draws an object as a translucent fill with a solid outline.
+func drawObject(img *image.RGBA, obj tiled.Object) {
+	c, ok := objectColors[obj.Type]
+	if !ok {
+		c = unknownObjectColor
+	}
+
+	w, h := obj.Width, obj.Height
+	if obj.Type == "spawn" || w == 0 || h == 0 {
+		w, h = spawnMarkerSize, spawnMarkerSize
+	}
+	r := image.Rect(int(obj.X), int(obj.Y), int(math.Ceil(obj.X+w)), int(math.Ceil(obj.Y+h))).Intersect(img.Bounds())
+	if r.Empty() {
+		return
+	}
+
+	fill := color.RGBA{c.R / 3, c.G / 3, c.B / 3, 0x55}
+	draw.Draw(img, r, image.NewUniform(fill), image.Point{}, draw.Over)
+
+	outline := image.NewUniform(c)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1), outline, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y), outline, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y), outline, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y), outline, image.Point{}, draw.Src)
+}
+
+// findSpawn returns the position of the first spawn object.
+func findSpawn(m *tiled.Map) (x, y int, found bool) {
+	for _, layer := range m.Layers {
+		for _, obj := range layer.Objects {
+			if obj.Type == "spawn" {
+				return int(obj.X), int(obj.Y), true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// centeredRect returns a w x h rectangle centered on (cx, cy), shifted to stay
+// inside bounds and shrunk if bounds is smaller.
+func centeredRect(cx, cy, w, h int, bounds image.Rectangle) image.Rectangle {
+	w = min(w, bounds.Dx())
+	h = min(h, bounds.Dy())
+	x := max(bounds.Min.X, min(cx-w/2, bounds.Max.X-w))
+	y := max(bounds.Min.Y, min(cy-h/2, bounds.Max.Y-h))
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// scaleNearest scales an image by zoom with nearest-neighbor sampling,
+// keeping pixel art crisp.
+func scaleNearest(src image.Image, zoom float64) *image.RGBA {
+	b := src.Bounds()
+	w := max(1, int(math.Round(float64(b.Dx())*zoom)))
+	h := max(1, int(math.Round(float64(b.Dy())*zoom)))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		sy := b.Min.Y + min(int(float64(y)/zoom), b.Dy()-1)
+		for x := 0; x < w; x++ {
+			sx := b.Min.X + min(int(float64(x)/zoom), b.Dx()-1)
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// parseZooms parses a comma-separated list of positive zoom factors.
+func parseZooms(s string) ([]float64, error) {
+	var zooms []float64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		z, err := strconv.ParseFloat(part, 64)
+		if err != nil || z <= 0 {
+			return nil, fmt.Errorf("invalid zoom %q", part)
+		}
+		zooms = append(zooms, z)
+	}
+	if len(zooms) == 0 {
+		return nil, fmt.Errorf("no zoom levels given")
+	}
+	return zooms, nil
+}
+
+// formatZoom formats a zoom factor for file names, e.g. "2x" or "0.5x".
+func formatZoom(z float64) string {
+	return strconv.FormatFloat(z, 'f', -1, 64) + "x"
+}
+
+// loadPNG reads and decodes a PNG file.
+func loadPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// savePNG encodes img and writes it to path.
+func savePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
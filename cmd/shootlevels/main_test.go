@@ -0,0 +1,74 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+// tilesetColor is the color of every tile of testTileset.
+var tilesetColor = color.RGBA{0xff, 0x00, 0x00, 0xff}
+
+// testTileset returns a 2x2 tileset of 16px tiles.
+func testTileset() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(img, img.Bounds(), image.NewUniform(tilesetColor), image.Point{}, draw.Src)
+	return img
+}
+
+// parseLevel parses a level for the tests.
+func parseLevel(t *testing.T, data string) *tiled.Map {
+	t.Helper()
+	m, err := tiled.ParseJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	return m
+}
+
+func TestRenderInfiniteMap(t *testing.T) {
+	// One tile left of and above the origin, one right of it
+	m := parseLevel(t, `{"infinite": true, "width": 2, "height": 1, "tilewidth": 16, "tileheight": 16, "layers": [
+	  {"name": "Tiles", "type": "tilelayer", "chunks": [
+	    {"x": -1, "y": -1, "width": 1, "height": 1, "data": [1]},
+	    {"x": 3, "y": 0, "width": 1, "height": 1, "data": [2]}
+	  ]}
+	]}`)
+	img := renderMap(m, testTileset(), false)
+	if got, want := img.Bounds(), image.Rect(-16, -16, 64, 16); got != want {
+		t.Fatalf("image bounds = %v, want %v", got, want)
+	}
+	for _, p := range []image.Point{{-8, -8}, {56, 8}} {
+		if got := img.RGBAAt(p.X, p.Y); got != tilesetColor {
+			t.Errorf("pixel %v = %v, want a tile", p, got)
+		}
+	}
+	if got := img.RGBAAt(8, 8); got != backgroundColor {
+		t.Errorf("pixel 8, 8 = %v, want background", got)
+	}
+}
+
+func TestRenderCompressedMap(t *testing.T) {
+	for _, compression := range []string{"", tiled.CompressionZlib, tiled.CompressionGzip} {
+		data, err := tiled.EncodeTileData([]int{1, 0, 0, 2}, compression)
+		if err != nil {
+			t.Fatalf("EncodeTileData(%q) failed: %v", compression, err)
+		}
+		m := parseLevel(t, `{"width": 2, "height": 2, "tilewidth": 16, "tileheight": 16, "layers": [
+		  {"name": "Tiles", "type": "tilelayer", "width": 2, "height": 2, "encoding": "base64",
+		   "compression": "`+compression+`", "data": "`+data+`"}
+		]}`)
+		img := renderMap(m, testTileset(), false)
+		for _, p := range []image.Point{{8, 8}, {24, 24}} {
+			if got := img.RGBAAt(p.X, p.Y); got != tilesetColor {
+				t.Errorf("%q: pixel %v = %v, want a tile", compression, p, got)
+			}
+		}
+		if got := img.RGBAAt(24, 8); got != backgroundColor {
+			t.Errorf("%q: pixel 24, 8 = %v, want background", compression, got)
+		}
+	}
+}
@@ -6,10 +6,18 @@ import (
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/debugui"
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/i18n"
 	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/logging"
 	timestep "github.com/torsten/GoP/internal/time"
 )
 
+// logger logs app and scene lifecycle events.
+var logger = logging.New("app")
+
 // Scene represents a game scene that can be active in the app.
 type Scene interface {
 	// Update updates the scene's non-physics logic.
@@ -31,12 +39,52 @@ type SceneDebugger interface {
 	DrawDebug(screen *ebiten.Image)
 }
 
+// PostFXUser is an optional interface for scenes that trigger post-processing
+// effects themselves, e.g. a screen flash when the player takes damage.
+type PostFXUser interface {
+	// SetPostProcessor is called with the app's post-processor when the scene is set.
+	SetPostProcessor(p *gfx.PostProcessor)
+}
+
+// Interpolator is an optional interface for scenes that draw entities between
+// their last two fixed updates, e.g. animated doors.
+type Interpolator interface {
+	// SetInterpolation is called before each Draw with how far the frame is
+	// between the last FixedUpdate and the next, from 0 to 1.
+	SetInterpolation(alpha float64)
+}
+
+// TimestepUser is an optional interface for scenes that need the app's fixed
+// timestep, e.g. for the length of a FixedUpdate when the tick rate is
+// configurable.
+type TimestepUser interface {
+	// SetTimestep is called with the app's timestep when the scene is set.
+	SetTimestep(ts *timestep.Timestep)
+}
+
 // App is the main application struct that implements ebiten.Game.
 type App struct {
 	scene       Scene
 	input       *input.Input
 	config      *Config
 	debugActive bool
+
+	// Post-processing applied to the scene (F10 toggles it)
+	postfx   *gfx.PostProcessor
+	postfxOn bool
+
+	// Offscreen image at the fixed logical resolution, scaled to the window
+	canvas   *ebiten.Image
+	viewport Viewport
+
+	// User settings persisted to config.SettingsPath
+	settings *Settings
+
+	// Drop-down debug console (backquote), pauses the scene while open
+	console *Console
+
+	// Performance overlay (F7)
+	profiler *debugui.Profiler
 	
 	// Fixed timestep for physics
 	timestep   *timestep.Timestep
@@ -49,26 +97,144 @@ func New(cfg *Config) *App {
 		cfg = DefaultConfig()
 	}
 
-	return &App{
-		input:      input.NewInput(),
-		config:     cfg,
-		timestep:   timestep.NewTimestep(),
-		lastUpdate: time.Now(),
+	a := &App{
+		input:       input.NewInput(),
+		config:      cfg,
+		debugActive: cfg.DebugMode,
+		timestep:    timestep.NewTimestepWithConfig(cfg.Timestep),
+		lastUpdate:  time.Now(),
+		postfx:      gfx.NewPostProcessor(),
+		postfxOn:    true,
+		viewport:    Viewport{ScaleX: 1, ScaleY: 1},
+		settings:    &Settings{},
+		console:     NewConsole(),
+		profiler:    debugui.NewProfiler(),
+	}
+	a.console.AddToggle(Toggle{Name: "debug", Value: &a.debugActive})
+	a.console.AddToggle(Toggle{Name: "postfx", Value: &a.postfxOn})
+	a.SetPostFX(cfg.PostFX)
+	a.input.SetCursorTransform(func(x, y float64) (float64, float64) {
+		return a.viewport.WindowToLogical(x, y)
+	})
+
+	if cfg.SettingsPath != "" {
+		settings, err := LoadSettings(cfg.SettingsPath)
+		if err != nil {
+			logger.Infof("Using default settings: %v", err)
+		} else {
+			a.settings = settings
+		}
+	}
+	if a.settings.Language != "" {
+		if err := i18n.SetLanguage(a.settings.Language); err != nil {
+			logger.Warnf("Ignoring saved language: %v", err)
+		}
 	}
+	a.console.Register(Command{
+		Name:  "lang",
+		Usage: "lang [code]",
+		Help:  "Show or switch the language",
+		Run: func(args []string) (string, error) {
+			if len(args) > 0 {
+				if err := a.SetLanguage(args[0]); err != nil {
+					return "", err
+				}
+			}
+			return "language " + i18n.Language(), nil
+		},
+		Complete: func(args []string) []string {
+			if len(args) > 1 {
+				return nil
+			}
+			return i18n.Languages()
+		},
+	})
+
+	return a
+}
+
+// SetLanguage switches the language strings are shown in and remembers it
+// in the settings file.
+func (a *App) SetLanguage(code string) error {
+	if err := i18n.SetLanguage(code); err != nil {
+		return err
+	}
+	a.settings.Language = code
+	return nil
+}
+
+// SetPostFX changes the enabled post-processing effects at runtime.
+func (a *App) SetPostFX(fx PostFXConfig) {
+	a.config.PostFX = fx
+	a.postfx.SetEnabled(gfx.EffectScanlines, fx.Scanlines)
+	a.postfx.SetEnabled(gfx.EffectVignette, fx.Vignette)
+	a.postfx.SetEnabled(gfx.EffectPaletteSwap, fx.PaletteSwap)
+}
+
+// PostProcessor returns the app's post-processor.
+func (a *App) PostProcessor() *gfx.PostProcessor {
+	return a.postfx
+}
+
+// Console returns the debug console, for registering commands.
+func (a *App) Console() *Console {
+	return a.console
+}
+
+// Profiler returns the performance overlay.
+func (a *App) Profiler() *debugui.Profiler {
+	return a.profiler
 }
 
 // SetScene switches the current scene.
 func (a *App) SetScene(scene Scene) {
 	a.scene = scene
+	if user, ok := scene.(PostFXUser); ok {
+		user.SetPostProcessor(a.postfx)
+	}
+	if user, ok := scene.(TimestepUser); ok {
+		user.SetTimestep(a.timestep)
+	}
+	a.console.setScene(scene)
 }
 
 // Update implements ebiten.Game.Update.
 func (a *App) Update() error {
+	a.profiler.BeginUpdate()
+	startTicks := a.timestep.TotalTicks()
+	defer func() {
+		a.profiler.EndUpdate(a.timestep.TotalTicks() - startTicks)
+	}()
+
+	// The console takes all input and pauses the scene while open
+	if a.console.Update() {
+		a.lastUpdate = time.Now()
+		a.input.Update()
+		return nil
+	}
+
 	// Handle debug toggle
 	if a.input.JustPressed(input.ActionDebugToggle) {
 		a.debugActive = !a.debugActive
 	}
 
+	// Handle post-processing toggle
+	if a.input.JustPressed(input.ActionPostFXToggle) {
+		a.postfxOn = !a.postfxOn
+	}
+
+	// Handle profiler toggle
+	if a.input.JustPressed(input.ActionProfilerToggle) {
+		a.profiler.Toggle()
+	}
+
+	// Handle fullscreen toggle (F11 or Alt+Enter)
+	if a.input.JustPressed(input.ActionFullscreenToggle) ||
+		(ebiten.IsKeyPressed(ebiten.KeyAlt) && inpututil.IsKeyJustPressed(ebiten.KeyEnter)) {
+		a.SetFullscreen(!ebiten.IsFullscreen())
+	}
+	a.trackWindow()
+
 	// Handle quit action
 	if a.input.Pressed(input.ActionQuit) {
 		return fmt.Errorf("quit requested")
@@ -87,6 +253,9 @@ func (a *App) Update() error {
 		}
 	}
 
+	// Advance timed post-processing effects
+	a.postfx.Update(time.Second / 60)
+
 	// Non-physics updates
 	if a.scene != nil {
 		if err := a.scene.Update(a.input); err != nil {
@@ -102,30 +271,157 @@ func (a *App) Update() error {
 
 // Draw implements ebiten.Game.Draw.
 func (a *App) Draw(screen *ebiten.Image) {
-	// Delegate to current scene
+	a.profiler.BeginDraw()
+	defer a.profiler.EndDraw()
+
+	if !a.fixedResolution() {
+		a.drawFrame(screen)
+		return
+	}
+
+	// Render at the logical resolution, then scale into the window
+	if a.canvas == nil {
+		a.canvas = ebiten.NewImage(a.config.LogicalWidth, a.config.LogicalHeight)
+	}
+	a.canvas.Clear()
+	a.drawFrame(a.canvas)
+	a.viewport = drawScaled(screen, a.canvas, a.config.ScaleMode)
+}
+
+// drawFrame draws the scene and debug overlay onto target.
+func (a *App) drawFrame(target *ebiten.Image) {
+	// Delegate to current scene, through the post-processor if enabled
+	if in, ok := a.scene.(Interpolator); ok {
+		in.SetInterpolation(a.timestep.AlphaAfter(time.Since(a.lastUpdate)))
+	}
 	if a.scene != nil {
-		a.scene.Draw(screen)
+		if a.postfxOn {
+			a.scene.Draw(a.postfx.Begin(target))
+			if err := a.postfx.End(target); err != nil {
+				// Fall back to drawing without effects
+				logger.Warnf("Post-processing disabled: %v", err)
+				a.postfxOn = false
+				a.scene.Draw(target)
+			}
+		} else {
+			a.scene.Draw(target)
+		}
 	}
 
+	// Draw the on-screen gamepad over the scene
+	a.input.DrawTouch(target)
+
 	// Draw debug overlay on top
 	if a.debugActive {
-		a.drawDebugOverlay(screen)
+		a.drawDebugOverlay(target)
 	}
+
+	// Draw the profiler with the counts from the frame just drawn
+	if a.profiler.Visible() {
+		if provider, ok := a.scene.(debugui.StatsProvider); ok {
+			a.profiler.SetStats(provider.ProfilerStats())
+		} else {
+			a.profiler.SetStats(nil)
+		}
+		a.profiler.Draw(target)
+	}
+
+	// Draw the console over everything
+	a.console.Draw(target)
 }
 
 // Layout implements ebiten.Game.Layout.
+// With a fixed logical resolution the screen matches the window and the scene
+// is laid out at the logical size; otherwise the scene decides.
 func (a *App) Layout(outsideW, outsideH int) (int, int) {
+	if a.fixedResolution() {
+		if a.scene != nil {
+			a.scene.Layout(a.config.LogicalWidth, a.config.LogicalHeight)
+		}
+		return outsideW, outsideH
+	}
 	if a.scene != nil {
 		return a.scene.Layout(outsideW, outsideH)
 	}
 	return a.config.WindowWidth, a.config.WindowHeight
 }
 
+// SetScaleMode changes how the logical resolution is scaled to the window.
+func (a *App) SetScaleMode(mode ScaleMode) {
+	a.config.ScaleMode = mode
+}
+
+// Viewport returns where the logical image was last drawn in the window,
+// for converting window positions (e.g. the mouse) to logical coordinates.
+func (a *App) Viewport() Viewport {
+	return a.viewport
+}
+
+// fixedResolution returns whether the game renders at a fixed logical resolution.
+func (a *App) fixedResolution() bool {
+	return a.config.LogicalWidth > 0 && a.config.LogicalHeight > 0
+}
+
 // Run starts the game loop.
+// Saved settings override the configured window size and are written
+// back when the game loop ends.
 func (a *App) Run() error {
-	ebiten.SetWindowSize(a.config.WindowWidth, a.config.WindowHeight)
+	w, h := a.config.WindowWidth, a.config.WindowHeight
+	if a.settings.WindowWidth > 0 && a.settings.WindowHeight > 0 {
+		w, h = a.settings.WindowWidth, a.settings.WindowHeight
+	}
+	ebiten.SetWindowSize(w, h)
+	if a.settings.HasPosition {
+		ebiten.SetWindowPosition(a.settings.WindowX, a.settings.WindowY)
+	}
 	ebiten.SetWindowTitle(a.config.WindowTitle)
-	return ebiten.RunGame(a)
+	if a.fixedResolution() {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	}
+	ebiten.SetFullscreen(a.settings.Fullscreen)
+	if a.settings.FPSMode != "" {
+		a.SetFPSMode(a.settings.FPSMode)
+	}
+
+	err := ebiten.RunGame(a)
+
+	if a.config.SettingsPath != "" {
+		if saveErr := a.settings.Save(a.config.SettingsPath); saveErr != nil {
+			logger.Errorf("Failed to save settings: %v", saveErr)
+		}
+	}
+	return err
+}
+
+// SetFullscreen switches between fullscreen and windowed mode.
+func (a *App) SetFullscreen(fullscreen bool) {
+	ebiten.SetFullscreen(fullscreen)
+	a.settings.Fullscreen = fullscreen
+}
+
+// SetFPSMode changes how frames are paced at runtime.
+func (a *App) SetFPSMode(mode FPSMode) {
+	ebiten.SetVsyncEnabled(mode != FPSModeUncapped)
+	a.settings.FPSMode = mode
+}
+
+// FPSMode returns the current frame pacing mode.
+func (a *App) FPSMode() FPSMode {
+	if ebiten.IsVsyncEnabled() {
+		return FPSModeVsync
+	}
+	return FPSModeUncapped
+}
+
+// trackWindow records the windowed size and position for the settings file.
+// Nothing is recorded while fullscreen so the window comes back where it was.
+func (a *App) trackWindow() {
+	if ebiten.IsFullscreen() {
+		return
+	}
+	a.settings.WindowWidth, a.settings.WindowHeight = ebiten.WindowSize()
+	a.settings.WindowX, a.settings.WindowY = ebiten.WindowPosition()
+	a.settings.HasPosition = true
 }
 
 // drawDebugOverlay renders the debug information overlay.
@@ -141,7 +437,8 @@ func (a *App) drawDebugOverlay(screen *ebiten.Image) {
 	tps := ebiten.CurrentTPS()
 	w, h := screen.Size()
 
-	debugText := fmt.Sprintf("FPS: %.1f\nTPS: %.1f\nWindow: %dx%d", fps, tps, w, h)
+	debugText := fmt.Sprintf("FPS: %.1f\nTPS: %.1f\nWindow: %dx%d\nPhysics: %dHz (%d panic resets)",
+		fps, tps, w, h, a.timestep.TickRate(), a.timestep.Panics())
 
 	// Add scene debug info if available
 	if a.scene != nil {
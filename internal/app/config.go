@@ -1,20 +1,81 @@
 // Package app provides the main application structure and scene management.
 package app
 
+import (
+	"time"
+
+	"github.com/torsten/GoP/internal/config"
+	timestep "github.com/torsten/GoP/internal/time"
+)
+
 // Config holds application configuration settings.
 type Config struct {
 	WindowWidth  int
 	WindowHeight int
 	WindowTitle  string
 	DebugMode    bool
+
+	// LogicalWidth and LogicalHeight fix the internal resolution the game
+	// renders at. The image is scaled to the window using ScaleMode.
+	// Zero means the scene's Layout decides the resolution.
+	LogicalWidth  int
+	LogicalHeight int
+	ScaleMode     ScaleMode
+
+	// PostFX selects the full-screen post-processing effects
+	PostFX PostFXConfig
+
+	// Timestep sets the physics tick rate and catch-up limits. Zero fields
+	// use the defaults (60Hz, 5 steps per frame).
+	Timestep timestep.Config
+
+	// SettingsPath is the user settings file (window size/position,
+	// fullscreen, FPS mode). Empty disables loading and saving settings.
+	SettingsPath string
+}
+
+// PostFXConfig selects which post-processing effects are applied.
+// The damage flash is always available and needs no setting.
+type PostFXConfig struct {
+	Scanlines   bool
+	Vignette    bool
+	PaletteSwap bool
 }
 
 // DefaultConfig returns a Config with sensible default values.
 func DefaultConfig() *Config {
 	return &Config{
-		WindowWidth:  640,
-		WindowHeight: 360,
-		WindowTitle:  "Game",
-		DebugMode:    false,
+		WindowWidth:   640,
+		WindowHeight:  360,
+		WindowTitle:   "Game",
+		DebugMode:     false,
+		LogicalWidth:  640,
+		LogicalHeight: 360,
+		ScaleMode:     ScaleInteger,
+	}
+}
+
+// ApplyFile overrides the configuration with the values set in a config file.
+func (c *Config) ApplyFile(f *config.File) {
+	if f.Window.Width > 0 {
+		c.WindowWidth = f.Window.Width
+	}
+	if f.Window.Height > 0 {
+		c.WindowHeight = f.Window.Height
+	}
+	if f.Window.Title != "" {
+		c.WindowTitle = f.Window.Title
+	}
+	if f.Debug != nil {
+		c.DebugMode = *f.Debug
+	}
+	if f.Physics.TickRate > 0 {
+		c.Timestep.TickRate = f.Physics.TickRate
+	}
+	if f.Physics.MaxStepsPerFrame > 0 {
+		c.Timestep.MaxStepsPerFrame = f.Physics.MaxStepsPerFrame
+	}
+	if f.Physics.PanicThresholdMs > 0 {
+		c.Timestep.PanicThreshold = time.Duration(f.Physics.PanicThresholdMs) * time.Millisecond
 	}
 }
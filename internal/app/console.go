@@ -0,0 +1,450 @@
+package app
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/logging"
+	"github.com/torsten/GoP/internal/tween"
+)
+
+// Console layout and limits
+const (
+	consoleScrollback  = 200 // Output lines kept
+	consoleHistorySize = 50  // Commands kept for Up/Down
+	consoleLineHeight  = 14
+	consolePadding     = 6
+	consoleSlideTime   = 0.15 // Seconds to drop down or roll up fully
+)
+
+// Console colors
+var (
+	consoleBackground = color.RGBA{0, 0, 0, 210}
+	consoleInputColor = color.RGBA{40, 40, 60, 230}
+)
+
+// Command is a debug console command.
+type Command struct {
+	// Name is the first word of the command line.
+	Name string
+	// Usage shows the arguments, e.g. "teleport <x> <y>".
+	Usage string
+	// Help is a one-line description for the help command.
+	Help string
+	// Run executes the command with the words after the name and returns
+	// text to print, if any.
+	Run func(args []string) (string, error)
+	// Complete returns candidates for the last argument given the ones
+	// before it. Nil means no argument completion.
+	Complete func(args []string) []string
+}
+
+// Toggle is a named on/off setting flipped with the console's toggle command.
+type Toggle struct {
+	Name  string
+	Value *bool
+}
+
+// ConsoleUser is an optional interface for scenes that add console commands
+// and toggles. They're registered when the scene is set and removed when
+// another scene replaces it.
+type ConsoleUser interface {
+	ConsoleCommands() []Command
+	ConsoleToggles() []Toggle
+}
+
+// Console is the drop-down debug console, opened with the backquote key.
+// Commands come from the app, the current scene and the game's main.
+type Console struct {
+	open  bool
+	slide *tween.Tween[float64] // Drop-down position, 0 hidden to 1 shown
+
+	commands      map[string]Command
+	toggles       map[string]*bool
+	sceneCommands []string // Names registered by the current scene
+	sceneToggles  []string
+
+	line    string   // Text being typed
+	output  []string // Scrollback
+	history []string
+	histPos int // Index into history while browsing with Up/Down
+}
+
+// NewConsole creates a closed console with the help, clear, toggle, log and
+// loglevel commands.
+func NewConsole() *Console {
+	c := &Console{
+		slide:    tween.New(0.0, 0.0, 0, tween.OutQuad),
+		commands: make(map[string]Command),
+		toggles:  make(map[string]*bool),
+	}
+	c.Register(Command{
+		Name:  "help",
+		Usage: "help [command]",
+		Help:  "List commands, or show one command's usage",
+		Run:   c.help,
+		Complete: func(args []string) []string {
+			if len(args) > 1 {
+				return nil
+			}
+			return c.commandNames()
+		},
+	})
+	c.Register(Command{
+		Name:  "clear",
+		Usage: "clear",
+		Help:  "Clear the console output",
+		Run: func([]string) (string, error) {
+			c.output = nil
+			return "", nil
+		},
+	})
+	c.Register(Command{
+		Name:  "toggle",
+		Usage: "toggle <name>",
+		Help:  "Flip a debug setting",
+		Run:   c.toggle,
+		Complete: func(args []string) []string {
+			if len(args) > 1 {
+				return nil
+			}
+			return c.toggleNames()
+		},
+	})
+	c.Register(Command{
+		Name:     "log",
+		Usage:    "log [n] [level]",
+		Help:     "Show the last n log entries (default 20) at level or above",
+		Run:      showLog,
+		Complete: completeLevel,
+	})
+	c.Register(Command{
+		Name:  "loglevel",
+		Usage: "loglevel [level]",
+		Help:  "Show or set the level written to stderr and the log file",
+		Run: func(args []string) (string, error) {
+			if len(args) > 0 {
+				level, err := logging.ParseLevel(args[0])
+				if err != nil {
+					return "", err
+				}
+				logging.SetLevel(level)
+			}
+			return "log level " + logging.CurrentLevel().String(), nil
+		},
+		Complete: completeLevel,
+	})
+	return c
+}
+
+// Register adds a command, replacing one with the same name.
+func (c *Console) Register(cmd Command) {
+	c.commands[cmd.Name] = cmd
+}
+
+// AddToggle adds a setting for the toggle command.
+func (c *Console) AddToggle(t Toggle) {
+	c.toggles[t.Name] = t.Value
+}
+
+// setScene replaces the scene's commands and toggles with those of scene.
+func (c *Console) setScene(scene Scene) {
+	for _, name := range c.sceneCommands {
+		delete(c.commands, name)
+	}
+	for _, name := range c.sceneToggles {
+		delete(c.toggles, name)
+	}
+	c.sceneCommands, c.sceneToggles = nil, nil
+
+	user, ok := scene.(ConsoleUser)
+	if !ok {
+		return
+	}
+	for _, cmd := range user.ConsoleCommands() {
+		c.Register(cmd)
+		c.sceneCommands = append(c.sceneCommands, cmd.Name)
+	}
+	for _, t := range user.ConsoleToggles() {
+		c.AddToggle(t)
+		c.sceneToggles = append(c.sceneToggles, t.Name)
+	}
+}
+
+// IsOpen returns whether the console is shown.
+func (c *Console) IsOpen() bool {
+	return c.open
+}
+
+// Print adds a line to the console output.
+func (c *Console) Print(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		c.output = append(c.output, line)
+	}
+	if len(c.output) > consoleScrollback {
+		c.output = c.output[len(c.output)-consoleScrollback:]
+	}
+}
+
+// Execute runs a command line, printing it and its result.
+func (c *Console) Execute(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	c.Print("> " + line)
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > consoleHistorySize {
+			c.history = c.history[1:]
+		}
+	}
+	c.histPos = len(c.history)
+
+	words := strings.Fields(line)
+	cmd, ok := c.commands[words[0]]
+	if !ok {
+		c.Print(fmt.Sprintf("unknown command %q, try help", words[0]))
+		return
+	}
+	out, err := cmd.Run(words[1:])
+	if err != nil {
+		c.Print("error: " + err.Error())
+		if cmd.Usage != "" {
+			c.Print("usage: " + cmd.Usage)
+		}
+		return
+	}
+	if out != "" {
+		c.Print(out)
+	}
+}
+
+// Complete fills in the current word of line: the command name or, past the
+// first word, the command's argument. With several candidates it completes
+// their common prefix and prints them.
+func (c *Console) Complete(line string) string {
+	words := strings.Fields(line)
+	if len(words) == 0 || strings.HasSuffix(line, " ") {
+		words = append(words, "")
+	}
+	last := words[len(words)-1]
+
+	var candidates []string
+	if len(words) == 1 {
+		candidates = c.commandNames()
+	} else if cmd, ok := c.commands[words[0]]; ok && cmd.Complete != nil {
+		candidates = cmd.Complete(words[1:])
+	}
+
+	var matches []string
+	for _, cand := range candidates {
+		if strings.HasPrefix(cand, last) {
+			matches = append(matches, cand)
+		}
+	}
+	if len(matches) == 0 {
+		return line
+	}
+
+	prefix := words[:len(words)-1]
+	if len(matches) == 1 {
+		return strings.Join(append(prefix, matches[0]), " ") + " "
+	}
+	c.Print(strings.Join(matches, "  "))
+	return strings.Join(append(prefix, commonPrefix(matches)), " ")
+}
+
+// commonPrefix returns the longest prefix shared by all of words.
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// commandNames returns the registered command names, sorted.
+func (c *Console) commandNames() []string {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toggleNames returns the registered toggle names, sorted.
+func (c *Console) toggleNames() []string {
+	names := make([]string, 0, len(c.toggles))
+	for name := range c.toggles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// help runs the help command.
+func (c *Console) help(args []string) (string, error) {
+	if len(args) > 0 {
+		cmd, ok := c.commands[args[0]]
+		if !ok {
+			return "", fmt.Errorf("unknown command %q", args[0])
+		}
+		return cmd.Usage + "  " + cmd.Help, nil
+	}
+	lines := make([]string, 0, len(c.commands))
+	for _, name := range c.commandNames() {
+		lines = append(lines, fmt.Sprintf("%-28s %s", c.commands[name].Usage, c.commands[name].Help))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// showLog runs the log command.
+func showLog(args []string) (string, error) {
+	n, min := 20, logging.LevelDebug
+	for _, arg := range args {
+		if v, err := strconv.Atoi(arg); err == nil {
+			n = v
+			continue
+		}
+		level, err := logging.ParseLevel(arg)
+		if err != nil {
+			return "", err
+		}
+		min = level
+	}
+	entries := logging.Recent(n, min)
+	if len(entries) == 0 {
+		return "no log entries", nil
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// completeLevel completes a log level name.
+func completeLevel([]string) []string {
+	return []string{"debug", "error", "info", "warn"}
+}
+
+// toggle runs the toggle command.
+func (c *Console) toggle(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("toggle takes one name: %s", strings.Join(c.toggleNames(), ", "))
+	}
+	value, ok := c.toggles[args[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown toggle %q: %s", args[0], strings.Join(c.toggleNames(), ", "))
+	}
+	*value = !*value
+	state := "off"
+	if *value {
+		state = "on"
+	}
+	return fmt.Sprintf("%s %s", args[0], state), nil
+}
+
+// Update opens and closes the console with backquote and, while open,
+// handles typing, Enter, Tab completion and Up/Down history. Returns whether
+// the console is open, in which case the game shouldn't see the input.
+func (c *Console) Update() bool {
+	c.slide.Update(1.0 / 60.0)
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackquote) {
+		c.open = !c.open
+		target := 0.0
+		if c.open {
+			target = 1
+		}
+		c.slide.Retarget(target, consoleSlideTime*math.Abs(target-c.slide.Value()))
+		return true
+	}
+	if !c.open {
+		return false
+	}
+
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if r != '`' && r >= ' ' {
+			c.line += string(r)
+		}
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		c.Execute(c.line)
+		c.line = ""
+	case repeatingKeyPressed(ebiten.KeyBackspace):
+		if len(c.line) > 0 {
+			c.line = c.line[:len(c.line)-1]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		c.line = c.Complete(c.line)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		if c.histPos > 0 {
+			c.histPos--
+			c.line = c.history[c.histPos]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		if c.histPos < len(c.history) {
+			c.histPos++
+		}
+		c.line = ""
+		if c.histPos < len(c.history) {
+			c.line = c.history[c.histPos]
+		}
+	}
+	return true
+}
+
+// repeatingKeyPressed reports a key press, repeating while it's held.
+func repeatingKeyPressed(key ebiten.Key) bool {
+	const (
+		delay    = 30
+		interval = 3
+	)
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d >= delay && (d-delay)%interval == 0)
+}
+
+// Draw renders the console over the top half of the screen, sliding down
+// as it opens and back up as it closes.
+func (c *Console) Draw(screen *ebiten.Image) {
+	shown := c.slide.Value()
+	if shown <= 0 {
+		return
+	}
+
+	w, h := screen.Size()
+	height := h / 2
+	bottom := int(float64(height) * shown) // Slides down from above the screen
+	draw.FillRect(screen, 0, 0, float64(w), float64(bottom), consoleBackground)
+
+	// Input line at the bottom of the console
+	inputY := bottom - consoleLineHeight - consolePadding
+	draw.FillRect(screen, 0, float64(inputY-2), float64(w), consoleLineHeight+4, consoleInputColor)
+	ebitenutil.DebugPrintAt(screen, "] "+c.line+"_", consolePadding, inputY)
+
+	// Newest output lines above it
+	rows := (height - consoleLineHeight - 2*consolePadding) / consoleLineHeight
+	start := len(c.output) - rows
+	if start < 0 {
+		start = 0
+	}
+	y := inputY - (len(c.output)-start)*consoleLineHeight - 2
+	for _, line := range c.output[start:] {
+		ebitenutil.DebugPrintAt(screen, line, consolePadding, y)
+		y += consoleLineHeight
+	}
+}
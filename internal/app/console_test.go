@@ -0,0 +1,141 @@
+//go:build display
+
+package app
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/torsten/GoP/internal/logging"
+)
+
+func TestConsoleExecute(t *testing.T) {
+	c := NewConsole()
+	var got []string
+	c.Register(Command{
+		Name:  "echo",
+		Usage: "echo <words>",
+		Run: func(args []string) (string, error) {
+			got = args
+			return strings.Join(args, " "), nil
+		},
+	})
+	c.Register(Command{
+		Name:  "fail",
+		Usage: "fail",
+		Run:   func([]string) (string, error) { return "", errors.New("boom") },
+	})
+
+	c.Execute("  echo hello   world ")
+	if !reflect.DeepEqual(got, []string{"hello", "world"}) {
+		t.Errorf("echo got args %q, want [hello world]", got)
+	}
+	c.Execute("fail")
+	c.Execute("nope")
+
+	want := []string{"> echo hello   world", "hello world", "> fail", "error: boom", "usage: fail", "> nope", `unknown command "nope", try help`}
+	if !reflect.DeepEqual(c.output, want) {
+		t.Errorf("output = %q, want %q", c.output, want)
+	}
+	if !reflect.DeepEqual(c.history, []string{"echo hello   world", "fail", "nope"}) {
+		t.Errorf("history = %q", c.history)
+	}
+}
+
+func TestConsoleToggle(t *testing.T) {
+	c := NewConsole()
+	on := false
+	c.AddToggle(Toggle{Name: "grid", Value: &on})
+
+	c.Execute("toggle grid")
+	if !on {
+		t.Error("toggle grid didn't turn it on")
+	}
+	c.Execute("toggle grid")
+	if on {
+		t.Error("second toggle grid didn't turn it off")
+	}
+	c.Execute("toggle nothing")
+	if last := c.output[len(c.output)-2]; !strings.HasPrefix(last, "error: unknown toggle") {
+		t.Errorf("unknown toggle printed %q", last)
+	}
+}
+
+func TestConsoleComplete(t *testing.T) {
+	c := NewConsole()
+	on := false
+	c.AddToggle(Toggle{Name: "collision", Value: &on})
+	c.AddToggle(Toggle{Name: "camera", Value: &on})
+	c.Register(Command{Name: "teleport", Run: func([]string) (string, error) { return "", nil }})
+
+	tests := []struct {
+		line, want string
+	}{
+		{"tel", "teleport "},
+		{"t", "t"}, // teleport and toggle
+		{"tog", "toggle "},
+		{"toggle c", "toggle c"}, // camera and collision
+		{"toggle co", "toggle collision "},
+		{"toggle ", "toggle c"},
+		{"zzz", "zzz"},
+		{"teleport 1", "teleport 1"}, // no argument completion
+	}
+	for _, tt := range tests {
+		if got := c.Complete(tt.line); got != tt.want {
+			t.Errorf("Complete(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestConsoleLog(t *testing.T) {
+	c := NewConsole()
+	logging.New("test").Warnf("hello %d", 1)
+
+	c.Execute("log 1 warn")
+	if last := c.output[len(c.output)-1]; !strings.HasSuffix(last, "WARN  [test] hello 1") {
+		t.Errorf("log printed %q, want the warning", last)
+	}
+
+	c.Execute("log loud")
+	if last := c.output[len(c.output)-1]; !strings.HasPrefix(last, "usage:") {
+		t.Errorf("log with a bad level printed %q, want usage", last)
+	}
+}
+
+type consoleScene struct {
+	Scene
+	value bool
+}
+
+func (s *consoleScene) ConsoleCommands() []Command {
+	return []Command{{Name: "scenecmd", Run: func([]string) (string, error) { return "", nil }}}
+}
+
+func (s *consoleScene) ConsoleToggles() []Toggle {
+	return []Toggle{{Name: "scenetoggle", Value: &s.value}}
+}
+
+func TestConsoleSceneCommands(t *testing.T) {
+	c := NewConsole()
+	c.setScene(&consoleScene{})
+	if _, ok := c.commands["scenecmd"]; !ok {
+		t.Fatal("scene command not registered")
+	}
+	if _, ok := c.toggles["scenetoggle"]; !ok {
+		t.Fatal("scene toggle not registered")
+	}
+
+	// A scene without commands removes the old scene's
+	c.setScene(nil)
+	if _, ok := c.commands["scenecmd"]; ok {
+		t.Error("scene command still registered after the scene changed")
+	}
+	if _, ok := c.toggles["scenetoggle"]; ok {
+		t.Error("scene toggle still registered after the scene changed")
+	}
+	if _, ok := c.commands["help"]; !ok {
+		t.Error("built-in help removed with the scene's commands")
+	}
+}
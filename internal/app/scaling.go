@@ -0,0 +1,92 @@
+package app
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ScaleMode selects how the fixed logical resolution is scaled to the window.
+type ScaleMode int
+
+const (
+	// ScaleInteger scales by the largest whole factor that fits, keeping every
+	// pixel the same size. Falls back to ScaleFit if the window is smaller
+	// than the logical resolution.
+	ScaleInteger ScaleMode = iota
+	// ScaleFit scales as large as fits while keeping the aspect ratio.
+	ScaleFit
+	// ScaleStretch fills the whole window, ignoring the aspect ratio.
+	ScaleStretch
+)
+
+// String returns the scale mode name for debugging.
+func (m ScaleMode) String() string {
+	switch m {
+	case ScaleInteger:
+		return "Integer"
+	case ScaleFit:
+		return "Fit"
+	case ScaleStretch:
+		return "Stretch"
+	default:
+		return "Unknown"
+	}
+}
+
+// letterboxColor fills the bars around the scaled game image.
+var letterboxColor = color.Black
+
+// Viewport is where the logical image is drawn in the window.
+type Viewport struct {
+	X, Y           float64 // Top-left corner in window pixels
+	ScaleX, ScaleY float64
+}
+
+// ComputeViewport returns the placement of a srcW x srcH image in a dstW x dstH
+// window for the given mode. The image is centered, leaving letterbox bars.
+func ComputeViewport(mode ScaleMode, srcW, srcH, dstW, dstH int) Viewport {
+	if srcW <= 0 || srcH <= 0 {
+		return Viewport{ScaleX: 1, ScaleY: 1}
+	}
+
+	sx := float64(dstW) / float64(srcW)
+	sy := float64(dstH) / float64(srcH)
+
+	if mode == ScaleStretch {
+		return Viewport{ScaleX: sx, ScaleY: sy}
+	}
+
+	scale := math.Min(sx, sy)
+	if mode == ScaleInteger && scale >= 1 {
+		scale = math.Floor(scale)
+	}
+
+	return Viewport{
+		X:      math.Floor((float64(dstW) - float64(srcW)*scale) / 2),
+		Y:      math.Floor((float64(dstH) - float64(srcH)*scale) / 2),
+		ScaleX: scale,
+		ScaleY: scale,
+	}
+}
+
+// WindowToLogical converts a window position to logical coordinates.
+func (v Viewport) WindowToLogical(x, y float64) (float64, float64) {
+	return (x - v.X) / v.ScaleX, (y - v.Y) / v.ScaleY
+}
+
+// drawScaled draws the logical canvas onto screen with letterbox bars.
+func drawScaled(screen, canvas *ebiten.Image, mode ScaleMode) Viewport {
+	cw, ch := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	v := ComputeViewport(mode, cw, ch, sw, sh)
+
+	screen.Fill(letterboxColor)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(v.ScaleX, v.ScaleY)
+	op.GeoM.Translate(v.X, v.Y)
+	op.Filter = ebiten.FilterNearest
+	screen.DrawImage(canvas, op)
+	return v
+}
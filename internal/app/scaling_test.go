@@ -0,0 +1,36 @@
+//go:build display
+
+package app
+
+import "testing"
+
+func TestComputeViewport(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       ScaleMode
+		dstW, dstH int
+		want       Viewport
+	}{
+		{"integer exact", ScaleInteger, 1280, 720, Viewport{X: 0, Y: 0, ScaleX: 2, ScaleY: 2}},
+		{"integer letterbox", ScaleInteger, 1600, 900, Viewport{X: 160, Y: 90, ScaleX: 2, ScaleY: 2}},
+		{"integer too small falls back to fit", ScaleInteger, 320, 180, Viewport{X: 0, Y: 0, ScaleX: 0.5, ScaleY: 0.5}},
+		{"fit pillarbox", ScaleFit, 1000, 360, Viewport{X: 180, Y: 0, ScaleX: 1, ScaleY: 1}},
+		{"fit fractional", ScaleFit, 960, 540, Viewport{X: 0, Y: 0, ScaleX: 1.5, ScaleY: 1.5}},
+		{"stretch", ScaleStretch, 1280, 360, Viewport{X: 0, Y: 0, ScaleX: 2, ScaleY: 1}},
+	}
+
+	for _, tt := range tests {
+		got := ComputeViewport(tt.mode, 640, 360, tt.dstW, tt.dstH)
+		if got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestViewportWindowToLogical(t *testing.T) {
+	v := Viewport{X: 160, Y: 90, ScaleX: 2, ScaleY: 2}
+	x, y := v.WindowToLogical(170, 100)
+	if x != 5 || y != 5 {
+		t.Errorf("Expected (5, 5), got (%v, %v)", x, y)
+	}
+}
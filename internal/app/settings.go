@@ -0,0 +1,75 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// FPSMode selects how frames are paced.
+type FPSMode string
+
+const (
+	// FPSModeVsync syncs frames to the display refresh rate.
+	FPSModeVsync FPSMode = "vsync"
+	// FPSModeUncapped renders as fast as possible with vsync off.
+	FPSModeUncapped FPSMode = "uncapped"
+)
+
+// Settings are user preferences persisted between runs.
+type Settings struct {
+	WindowWidth  int     `json:"windowWidth,omitempty"`
+	WindowHeight int     `json:"windowHeight,omitempty"`
+	WindowX      int     `json:"windowX"`
+	WindowY      int     `json:"windowY"`
+	HasPosition  bool    `json:"hasPosition"` // WindowX/Y were saved from a real window
+	Fullscreen   bool    `json:"fullscreen"`
+	FPSMode      FPSMode `json:"fpsMode,omitempty"`
+	Language     string  `json:"language,omitempty"` // Language picked in the game, over the config file's
+}
+
+// DefaultSettingsPath returns the settings file location in the user's config directory.
+func DefaultSettingsPath() (string, error) {
+	dir, err := storage.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// LoadSettings reads settings from path.
+// A missing file is not an error and returns empty settings.
+func LoadSettings(path string) (*Settings, error) {
+	data, err := storage.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse settings: %w", err)
+	}
+	return &s, nil
+}
+
+// Save writes the settings to path, creating the directory if needed.
+func (s *Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := storage.MkdirAll(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create settings directory: %w", err)
+	}
+	if err := storage.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build display
+
+package app
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "settings.json")
+
+	want := &Settings{
+		WindowWidth:  1280,
+		WindowHeight: 720,
+		WindowX:      40,
+		WindowY:      60,
+		HasPosition:  true,
+		Fullscreen:   true,
+		FPSMode:      FPSModeUncapped,
+	}
+	if err := want.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLoadSettingsMissingFile(t *testing.T) {
+	s, err := LoadSettings(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+	if *s != (Settings{}) {
+		t.Errorf("Expected empty settings, got %+v", s)
+	}
+}
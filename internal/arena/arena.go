@@ -0,0 +1,43 @@
+// Package arena provides a frame arena: scratch slices handed out during a
+// fixed update and all reclaimed at once at the start of the next, so the
+// update doesn't allocate once the arena has grown to fit it.
+package arena
+
+// Arena hands out slices of T carved from one buffer. The zero value is
+// ready to use. Slices stay valid until the next Reset; it's not safe for
+// concurrent use.
+type Arena[T any] struct {
+	buf  []T
+	used int // Elements of buf handed out since the last Reset
+	want int // Elements asked for since the last Reset
+}
+
+// Alloc returns a zeroed slice of n elements, capped at n so appending to it
+// reallocates instead of running into the next slice. When the buffer is
+// full it allocates the slice instead, and Reset grows the buffer to fit.
+func (a *Arena[T]) Alloc(n int) []T {
+	a.want += n
+	if a.used+n > len(a.buf) {
+		return make([]T, n)
+	}
+	s := a.buf[a.used : a.used+n : a.used+n]
+	a.used += n
+	clear(s)
+	return s
+}
+
+// Reset reclaims every slice handed out since the last Reset; don't use
+// them afterwards. Call it once per fixed update, before any Alloc.
+func (a *Arena[T]) Reset() {
+	if a.want > len(a.buf) {
+		// Room for a bigger update than this one, so the buffer doesn't
+		// grow a little every time
+		a.buf = make([]T, max(a.want, 2*len(a.buf)))
+	}
+	a.used, a.want = 0, 0
+}
+
+// Cap returns how many elements an update can use without allocating.
+func (a *Arena[T]) Cap() int {
+	return len(a.buf)
+}
@@ -0,0 +1,60 @@
+package arena
+
+import "testing"
+
+func TestArenaAlloc(t *testing.T) {
+	var a Arena[int]
+	first := a.Alloc(3)
+	if len(first) != 3 || cap(first) != 3 {
+		t.Fatalf("Alloc(3) has len %d and cap %d, want 3 and 3", len(first), cap(first))
+	}
+	a.Reset()
+	if a.Cap() < 3 {
+		t.Fatalf("after an update using 3 elements Cap() = %d", a.Cap())
+	}
+
+	first = a.Alloc(2)
+	first[0], first[1] = 1, 2
+	second := a.Alloc(1)
+	second[0] = 3
+	_ = append(first, 4)
+	if second[0] != 3 {
+		t.Error("appending to a slice overwrote the next one")
+	}
+
+	// Reclaimed memory comes back zeroed
+	a.Reset()
+	for i, v := range a.Alloc(3) {
+		if v != 0 {
+			t.Errorf("element %d after Reset = %d, want 0", i, v)
+		}
+	}
+}
+
+func TestArenaGrowsToFitUpdate(t *testing.T) {
+	var a Arena[int]
+	for _, n := range []int{4, 4, 40} {
+		a.Alloc(n)
+	}
+	a.Reset()
+	if a.Cap() < 48 {
+		t.Errorf("after an update using 48 elements Cap() = %d", a.Cap())
+	}
+}
+
+func TestArenaSteadyStateDoesNotAllocate(t *testing.T) {
+	var a Arena[[4]float64]
+	update := func() {
+		a.Reset()
+		for n := 1; n <= 8; n++ {
+			s := a.Alloc(n)[:0]
+			for i := 0; i < n; i++ {
+				s = append(s, [4]float64{float64(i)})
+			}
+		}
+	}
+	update() // Grows the arena
+	if allocs := testing.AllocsPerRun(100, update); allocs != 0 {
+		t.Errorf("an update allocated %v times, want 0", allocs)
+	}
+}
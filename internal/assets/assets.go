@@ -1,19 +1,21 @@
 // Package assets provides asset loading utilities for the game.
-// Assets are loaded from disk at runtime.
+// Assets are loaded from disk at runtime, or from the bundle built into the
+// browser build (see internal/storage).
 package assets
 
 import (
 	"io/fs"
-	"os"
+
+	"github.com/torsten/GoP/internal/storage"
 )
 
 // AssetsDir is the default directory for game assets.
 const AssetsDir = "assets"
 
 // FS returns the filesystem for the assets directory.
-// This uses the OS filesystem for runtime loading.
+// This uses the OS filesystem on desktop and the asset bundle in the browser.
 func FS() fs.FS {
-	return os.DirFS(AssetsDir)
+	return storage.DirFS(AssetsDir)
 }
 
 // SubFS returns a sub-filesystem rooted at the given path.
@@ -0,0 +1,168 @@
+package assets
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AtlasRect is the location of a single frame inside an atlas image.
+type AtlasRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// AtlasManifest describes the frames packed into an atlas image.
+// It is written as JSON next to the atlas PNG by cmd/packatlas.
+type AtlasManifest struct {
+	Image  string               `json:"image"`  // Atlas image path, relative to the manifest
+	Width  int                  `json:"width"`  // Atlas width in pixels
+	Height int                  `json:"height"` // Atlas height in pixels
+	Frames map[string]AtlasRect `json:"frames"` // Frame name to rectangle
+}
+
+// ParseAtlasManifest parses an atlas manifest from JSON data.
+func ParseAtlasManifest(data []byte) (*AtlasManifest, error) {
+	var m AtlasManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse atlas manifest: %w", err)
+	}
+	if m.Image == "" {
+		return nil, fmt.Errorf("atlas manifest has no image")
+	}
+	return &m, nil
+}
+
+// Atlas is a packed sprite atlas with frames addressable by name.
+// Frames are extracted using SubImage, sharing memory with the atlas image.
+type Atlas struct {
+	image  *ebiten.Image
+	frames map[string]*ebiten.Image
+}
+
+// NewAtlas creates an atlas from an image and its manifest.
+// Frames that fall outside the image bounds are reported as an error.
+func NewAtlas(img *ebiten.Image, manifest *AtlasManifest) (*Atlas, error) {
+	bounds := img.Bounds()
+	frames := make(map[string]*ebiten.Image, len(manifest.Frames))
+
+	for name, r := range manifest.Frames {
+		rect := image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+		if r.W <= 0 || r.H <= 0 || !rect.In(bounds) {
+			return nil, fmt.Errorf("atlas frame %q is out of bounds", name)
+		}
+		frames[name] = img.SubImage(rect).(*ebiten.Image)
+	}
+
+	return &Atlas{
+		image:  img,
+		frames: frames,
+	}, nil
+}
+
+// LoadAtlas loads an atlas manifest and its image from a filesystem.
+// The image path in the manifest is resolved relative to the manifest.
+func LoadAtlas(fsys fs.FS, manifestPath string) (*Atlas, error) {
+	data, err := fs.ReadFile(fsys, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read atlas manifest %s: %w", manifestPath, err)
+	}
+
+	manifest, err := ParseAtlasManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := LoadImage(fsys, path.Join(path.Dir(manifestPath), manifest.Image))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAtlas(img, manifest)
+}
+
+// Frame returns the frame with the given name.
+// Returns nil if no such frame exists.
+func (a *Atlas) Frame(name string) *ebiten.Image {
+	return a.frames[name]
+}
+
+// Frames returns the frames with the given names, in order.
+// Returns an error naming the first frame that doesn't exist.
+func (a *Atlas) Frames(names ...string) ([]*ebiten.Image, error) {
+	frames := make([]*ebiten.Image, 0, len(names))
+	for _, name := range names {
+		frame, ok := a.frames[name]
+		if !ok {
+			return nil, fmt.Errorf("atlas has no frame %q", name)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// FrameNames returns all frame names in CompareFrameNames order.
+func (a *Atlas) FrameNames() []string {
+	names := make([]string, 0, len(a.frames))
+	for name := range a.frames {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, CompareFrameNames)
+	return names
+}
+
+// FrameNamesWithPrefix returns the names of all frames starting with prefix,
+// in CompareFrameNames order. This is useful for frame sequences like
+// "run_0", "run_1", ..., "run_10".
+func (a *Atlas) FrameNamesWithPrefix(prefix string) []string {
+	var names []string
+	for _, name := range a.FrameNames() {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CompareFrameNames orders frame names alphabetically, except that names
+// differing only in a trailing number are ordered by its value, so "run_2"
+// comes before "run_10". cmd/packatlas packs frames in the same order.
+func CompareFrameNames(a, b string) int {
+	aBase, aNum := splitFrameNumber(a)
+	bBase, bNum := splitFrameNumber(b)
+	return cmp.Or(strings.Compare(aBase, bBase), cmp.Compare(aNum, bNum), strings.Compare(a, b))
+}
+
+// splitFrameNumber splits the trailing number off a frame name: "run_10" is
+// "run_" and 10. Names without one return -1.
+func splitFrameNumber(name string) (string, int) {
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(name[i:])
+	if err != nil {
+		return name, -1
+	}
+	return name[:i], n
+}
+
+// FrameCount returns the total number of frames.
+func (a *Atlas) FrameCount() int {
+	return len(a.frames)
+}
+
+// Image returns the underlying atlas image.
+func (a *Atlas) Image() *ebiten.Image {
+	return a.image
+}
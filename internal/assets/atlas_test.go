@@ -0,0 +1,77 @@
+//go:build display
+
+package assets
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestCompareFrameNames(t *testing.T) {
+	names := []string{"run_10", "idle", "run_2", "run_1a", "run", "run_02", "jump_3", "run_1", "jump_12"}
+	slices.SortFunc(names, CompareFrameNames)
+	want := []string{"idle", "jump_3", "jump_12", "run", "run_1", "run_02", "run_2", "run_10", "run_1a"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("sorted names = %v, want %v", names, want)
+	}
+}
+
+func TestAtlasFrames(t *testing.T) {
+	manifest, err := ParseAtlasManifest([]byte(`{"image": "player.png", "width": 64, "height": 16, "frames": {
+	  "idle":   {"x": 0, "y": 0, "w": 16, "h": 16},
+	  "run_1":  {"x": 16, "y": 0, "w": 8, "h": 16},
+	  "run_2":  {"x": 24, "y": 0, "w": 8, "h": 16},
+	  "run_10": {"x": 32, "y": 0, "w": 8, "h": 16}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	atlas, err := NewAtlas(ebiten.NewImage(64, 16), manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := atlas.FrameCount(); got != 4 {
+		t.Errorf("FrameCount = %d, want 4", got)
+	}
+	if got, want := atlas.FrameNamesWithPrefix("run_"), []string{"run_1", "run_2", "run_10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FrameNamesWithPrefix = %v, want %v", got, want)
+	}
+	if got, want := atlas.FrameNames(), []string{"idle", "run_1", "run_2", "run_10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FrameNames = %v, want %v", got, want)
+	}
+
+	frame := atlas.Frame("run_2")
+	if frame == nil {
+		t.Fatal("Frame(run_2) = nil")
+	}
+	if got, want := frame.Bounds().Min.X, 24; got != want || frame.Bounds().Dx() != 8 || frame.Bounds().Dy() != 16 {
+		t.Errorf("Frame(run_2) bounds = %v, want 8x16 at x %d", frame.Bounds(), want)
+	}
+	if atlas.Frame("walk_1") != nil {
+		t.Error("Frame of a missing name is not nil")
+	}
+
+	frames, err := atlas.Frames("run_10", "idle")
+	if err != nil || len(frames) != 2 || frames[0] != atlas.Frame("run_10") || frames[1] != atlas.Frame("idle") {
+		t.Errorf("Frames(run_10, idle) = %v, %v; want those frames in order", frames, err)
+	}
+	if _, err := atlas.Frames("idle", "walk_1"); err == nil {
+		t.Error("Frames with a missing name succeeded")
+	}
+}
+
+func TestNewAtlasRejectsBadFrames(t *testing.T) {
+	for _, r := range []AtlasRect{{X: 60, Y: 0, W: 8, H: 8}, {X: 0, Y: 0, W: 0, H: 8}, {X: -1, Y: 0, W: 4, H: 4}} {
+		manifest := &AtlasManifest{Image: "a.png", Frames: map[string]AtlasRect{"bad": r}}
+		if _, err := NewAtlas(ebiten.NewImage(64, 16), manifest); err == nil {
+			t.Errorf("NewAtlas with frame %+v succeeded", r)
+		}
+	}
+	if _, err := ParseAtlasManifest([]byte(`{"frames": {}}`)); err == nil {
+		t.Error("ParseAtlasManifest without an image succeeded")
+	}
+}
@@ -6,9 +6,9 @@ import (
 	"image"
 	_ "image/png" // PNG decoder
 	"io/fs"
-	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/storage"
 )
 
 // LoadImage loads an image from a filesystem and converts it to *ebiten.Image.
@@ -41,7 +41,7 @@ func LoadImageFromBytes(data []byte) (*ebiten.Image, error) {
 
 // LoadFile loads a file's contents as bytes from the assets directory.
 func LoadFile(path string) ([]byte, error) {
-	return os.ReadFile(AssetsDir + "/" + path)
+	return storage.ReadFile(AssetsDir + "/" + path)
 }
 
 // LoadSpriteSheet loads the sprite sheet from assets/sprites/test_sheet.png.
@@ -82,6 +82,16 @@ func LoadTilesetRaw() (image.Image, error) {
 	return img, nil
 }
 
+// LoadTilesetDescriptor loads the Tiled tileset file of the tileset,
+// assets/tiles/tiles.tsj, which holds its tile animations.
+func LoadTilesetDescriptor() ([]byte, error) {
+	fsys, err := SubFS("tiles")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tiles directory: %w", err)
+	}
+	return fs.ReadFile(fsys, "tiles.tsj")
+}
+
 // LoadLevelJSON loads the level JSON from assets/levels/level_01.json.
 func LoadLevelJSON() ([]byte, error) {
 	return LoadFile("levels/level_01.json")
@@ -3,6 +3,13 @@
 // and pixel-perfect snapping for clean pixel art rendering.
 package camera
 
+import (
+	"math"
+
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/tween"
+)
+
 // Camera provides smooth following with deadzone and bounds constraints.
 // The camera position (X, Y) represents the top-left corner of the viewport
 // in world coordinates.
@@ -28,10 +35,18 @@ type Camera struct {
 	// Target position for smoothing (world coordinates)
 	targetX, targetY float64
 
+	// Position before the last Update, for Interpolated
+	prevX, prevY float64
+	hasPrev      bool
+
 	// Pixel-perfect snapping
 	// When true, rounds final position to integers to prevent sub-pixel
 	// rendering issues for pixel art.
 	PixelPerfect bool
+
+	// Glide from a fixed position to the followed one (see GlideTo)
+	glideFrom mathx.Vec2
+	glide     *tween.Tween[float64]
 }
 
 // NewCamera creates a new camera with the given viewport dimensions.
@@ -95,6 +110,9 @@ func (c *Camera) Follow(targetX, targetY, targetW, targetH float64) {
 // dt is the delta time in seconds (used for smoothing).
 // Call this once per frame after setting the target with Follow.
 func (c *Camera) Update(dt float64) {
+	c.prevX, c.prevY = c.X, c.Y
+	c.hasPrev = true
+
 	// Calculate target position relative to current camera (screen coordinates)
 	relX := c.targetX - c.X
 	relY := c.targetY - c.Y
@@ -140,12 +158,9 @@ func (c *Camera) Update(dt float64) {
 		// Apply frame-rate independent smoothing
 		// Using exponential decay: pos += (target - pos) * (1 - e^(-rate * dt))
 		// Simplified: pos += (target - pos) * factor * dt * 60 (normalized to 60fps)
-		smoothFactor := factor * dt * 60
-		if smoothFactor > 1 {
-			smoothFactor = 1
-		}
-		c.X += (desiredX - c.X) * smoothFactor
-		c.Y += (desiredY - c.Y) * smoothFactor
+		smoothFactor := mathx.Clamp01(factor * dt * 60)
+		c.X = mathx.Lerp(c.X, desiredX, smoothFactor)
+		c.Y = mathx.Lerp(c.Y, desiredY, smoothFactor)
 	} else {
 		// Instant follow
 		c.X = desiredX
@@ -161,22 +176,24 @@ func (c *Camera) Update(dt float64) {
 			// Level narrower than viewport: center horizontally
 			c.X = maxX / 2
 		} else {
-			if c.X < 0 {
-				c.X = 0
-			} else if c.X > maxX {
-				c.X = maxX
-			}
+			c.X = mathx.Clamp(c.X, 0, maxX)
 		}
 
 		if maxY < 0 {
 			// Level shorter than viewport: center vertically
 			c.Y = maxY / 2
 		} else {
-			if c.Y < 0 {
-				c.Y = 0
-			} else if c.Y > maxY {
-				c.Y = maxY
-			}
+			c.Y = mathx.Clamp(c.Y, 0, maxY)
+		}
+	}
+
+	// Blend in from where a glide started
+	if c.glide != nil {
+		c.glide.Update(dt)
+		pos := tween.Lerp(c.glideFrom, mathx.V(c.X, c.Y), c.glide.Value())
+		c.X, c.Y = pos.X, pos.Y
+		if c.glide.Done() {
+			c.glide = nil
 		}
 	}
 
@@ -187,6 +204,49 @@ func (c *Camera) Update(dt float64) {
 	}
 }
 
+// Glide eases the camera from where it is now to wherever following the
+// target puts it over the next duration seconds, instead of jumping there,
+// e.g. back to a respawn point. The target may keep moving meanwhile.
+func (c *Camera) Glide(duration float64, ease tween.Ease) {
+	c.glideFrom = mathx.V(c.X, c.Y)
+	c.glide = tween.New(0.0, 1.0, duration, ease)
+}
+
+// Gliding returns whether a Glide is in progress.
+func (c *Camera) Gliding() bool {
+	return c.glide != nil
+}
+
+// Snap moves the camera straight to the target set with Follow, without
+// smoothing, and forgets where it was so drawing doesn't interpolate from
+// there. Use it after teleporting the target, e.g. into another room.
+func (c *Camera) Snap() {
+	smoothing := c.Smoothing
+	c.Smoothing = 0
+	c.glide = nil
+	c.Update(0)
+	c.Smoothing = smoothing
+	c.prevX, c.prevY = c.X, c.Y
+}
+
+// Interpolated returns a copy of the camera positioned alpha (0 to 1) of the
+// way from where it was before the last Update to where it is now, for
+// drawing between fixed updates. Call Update at the physics rate for this to
+// line up with interpolated bodies.
+func (c *Camera) Interpolated(alpha float64) *Camera {
+	view := *c
+	if !c.hasPrev {
+		return &view
+	}
+	view.X = mathx.Lerp(c.prevX, c.X, alpha)
+	view.Y = mathx.Lerp(c.prevY, c.Y, alpha)
+	if c.PixelPerfect {
+		view.X = math.Floor(view.X)
+		view.Y = math.Floor(view.Y)
+	}
+	return &view
+}
+
 // WorldToScreen converts world coordinates to screen coordinates.
 // Returns the screen position where a world point should be rendered.
 func (c *Camera) WorldToScreen(x, y float64) (screenX, screenY int) {
@@ -212,10 +272,11 @@ func (c *Camera) Bounds() (x, y, w, h float64) {
 //   - (tx1, ty1) is the first visible tile (inclusive)
 //   - (tx2, ty2) is the last visible tile + 1 (exclusive)
 func (c *Camera) VisibleTiles(tileSize int) (tx1, ty1, tx2, ty2 int) {
-	tx1 = int(c.X) / tileSize
-	ty1 = int(c.Y) / tileSize
-	tx2 = (int(c.X) + c.ViewportW + tileSize - 1) / tileSize
-	ty2 = (int(c.Y) + c.ViewportH + tileSize - 1) / tileSize
+	size := float64(tileSize)
+	tx1 = int(math.Floor(c.X / size))
+	ty1 = int(math.Floor(c.Y / size))
+	tx2 = int(math.Ceil((c.X + float64(c.ViewportW)) / size))
+	ty2 = int(math.Ceil((c.Y + float64(c.ViewportH)) / size))
 	return
 }
 
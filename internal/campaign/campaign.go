@@ -0,0 +1,110 @@
+// Package campaign describes the order levels are played in: finishing one
+// level of a campaign leads on to the next.
+package campaign
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// Campaign is a level order loaded from a campaign manifest, e.g.:
+//
+//	{
+//	  "name": "GoP",
+//	  "levels": [
+//	    {"name": "First Steps", "level": "levels/level_01.json"},
+//	    {"name": "The Climb", "level": "levels/level_02.json"}
+//	  ]
+//	}
+type Campaign struct {
+	// Name is shown when the campaign is finished
+	Name   string  `json:"name"`
+	Levels []Entry `json:"levels"`
+
+	dir string // Directory entry levels are relative to
+}
+
+// Entry is one level of a campaign.
+type Entry struct {
+	Name  string `json:"name"`
+	Level string `json:"level"` // Level file relative to the manifest
+}
+
+// Parse parses a campaign manifest from JSON. Entry levels are relative to
+// dir. Entries are checked so mistakes are reported at load time.
+func Parse(data []byte, dir string) (*Campaign, error) {
+	var c Campaign
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse campaign: %w", err)
+	}
+	c.dir = dir
+
+	if len(c.Levels) == 0 {
+		return nil, fmt.Errorf("campaign %q has no levels", c.Name)
+	}
+	seen := make(map[string]bool, len(c.Levels))
+	for i, e := range c.Levels {
+		if e.Level == "" {
+			return nil, fmt.Errorf("campaign %q: entry %d has no level", c.Name, i+1)
+		}
+		path := c.LevelPath(i)
+		if seen[path] {
+			return nil, fmt.Errorf("campaign %q: level %s is listed twice", c.Name, e.Level)
+		}
+		seen[path] = true
+	}
+	return &c, nil
+}
+
+// Load loads a campaign manifest. Entry levels are relative to the file.
+func Load(path string) (*Campaign, error) {
+	data, err := storage.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaign %s: %w", path, err)
+	}
+	return Parse(data, filepath.Dir(path))
+}
+
+// LevelPath returns the file path of the i-th level.
+func (c *Campaign) LevelPath(i int) string {
+	level := c.Levels[i].Level
+	if filepath.IsAbs(level) {
+		return filepath.Clean(level)
+	}
+	return filepath.Join(c.dir, level)
+}
+
+// Index returns the position of the level at path in the campaign, or -1 if
+// it isn't part of it.
+func (c *Campaign) Index(path string) int {
+	path = filepath.Clean(path)
+	for i := range c.Levels {
+		if c.LevelPath(i) == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// Next returns the index of the level after the one at path. It returns
+// false for the last level and for levels outside the campaign.
+func (c *Campaign) Next(path string) (int, bool) {
+	i := c.Index(path)
+	if i < 0 || i+1 >= len(c.Levels) {
+		return 0, false
+	}
+	return i + 1, true
+}
+
+// Title returns the name to show for the i-th level: its name, or its file
+// name without the extension.
+func (c *Campaign) Title(i int) string {
+	if name := c.Levels[i].Name; name != "" {
+		return name
+	}
+	base := filepath.Base(c.Levels[i].Level)
+	return base[:len(base)-len(filepath.Ext(base))]
+}
@@ -0,0 +1,73 @@
+package campaign
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCampaign = `{
+	"name": "Test",
+	"levels": [
+		{"name": "First", "level": "levels/l1.json"},
+		{"level": "levels/l2.json"},
+		{"name": "Last", "level": "levels/l3.json"}
+	]
+}`
+
+func parseTestCampaign(t *testing.T) *Campaign {
+	t.Helper()
+	c, err := Parse([]byte(testCampaign), "assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNext(t *testing.T) {
+	c := parseTestCampaign(t)
+
+	tests := []struct {
+		path   string
+		want   int
+		wantOK bool
+	}{
+		{filepath.Join("assets", "levels", "l1.json"), 1, true},
+		{"assets/levels/../levels/l2.json", 2, true},
+		{filepath.Join("assets", "levels", "l3.json"), 0, false},
+		{filepath.Join("assets", "levels", "other.json"), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := c.Next(tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Next(%s) = %d, %v, want %d, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	c := parseTestCampaign(t)
+	for i, want := range []string{"First", "l2", "Last"} {
+		if got := c.Title(i); got != want {
+			t.Errorf("Title(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"no levels", `{"name": "X", "levels": []}`, "no levels"},
+		{"missing level", `{"name": "X", "levels": [{"name": "A"}]}`, "entry 1 has no level"},
+		{"duplicate", `{"name": "X", "levels": [{"level": "a.json"}, {"level": "./a.json"}]}`, "listed twice"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.json), ".")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Package config loads user configuration for the game and editor.
+//
+// A config file is JSON with optional sections for the window, debug mode,
+// keybinds, tuning overrides, the editor camera, logging, and the physics
+// timestep. Anything left out keeps the built-in default, and a missing file
+// is the same as an empty one. Environment
+// variables override the file so testers can tweak settings per run.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/torsten/GoP/internal/logging"
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// DefaultPath is the config file read from the working directory when
+// EnvPath isn't set.
+const DefaultPath = "gop.json"
+
+// Environment variables that override the config file.
+const (
+	EnvPath         = "GOP_CONFIG"        // Config file path
+	EnvWindowWidth  = "GOP_WINDOW_WIDTH"  // Window width in pixels
+	EnvWindowHeight = "GOP_WINDOW_HEIGHT" // Window height in pixels
+	EnvDebug        = "GOP_DEBUG"         // Debug mode ("1", "true", ...)
+)
+
+// File is the contents of a config file.
+type File struct {
+	Window       WindowConfig `json:"window"`
+	EditorWindow WindowConfig `json:"editorWindow"`
+
+	// Debug enables debug mode; nil keeps the default
+	Debug *bool `json:"debug,omitempty"`
+
+	// Keybinds maps action names (e.g. "jump") to key names (e.g. "Space", "Z").
+	// A listed action replaces all of its default keys.
+	Keybinds map[string][]string `json:"keybinds,omitempty"`
+
+	// Tuning overrides individual movement tuning values
+	Tuning TuningOverrides `json:"tuning"`
+
+	// EditorCamera sets the level editor's zoom range and steps
+	EditorCamera EditorCameraConfig `json:"editorCamera"`
+
+	// Log sets the log level and an optional log file
+	Log LogConfig `json:"log"`
+
+	// Physics sets the fixed timestep rate and catch-up limits
+	Physics PhysicsConfig `json:"physics"`
+
+	// Language is the code of the language to show, e.g. "de" (see
+	// internal/i18n); empty keeps English
+	Language string `json:"language,omitempty"`
+
+	// Touch sets up the game's on-screen gamepad for touch screens
+	Touch TouchConfig `json:"touch"`
+}
+
+// TouchConfig holds the on-screen gamepad settings. Zero values keep the
+// default: shown after the first touch, 48px buttons at 40% opacity.
+type TouchConfig struct {
+	// Mode is "auto" (show after the first touch), "on" or "off"
+	Mode    string  `json:"mode,omitempty"`
+	Size    float64 `json:"size,omitempty"`    // Button diameter in pixels
+	Opacity float64 `json:"opacity,omitempty"` // 0-1
+}
+
+// validate checks the gamepad settings for nonsense values.
+func (c TouchConfig) validate() error {
+	switch c.Mode {
+	case "", "auto", "on", "off":
+	default:
+		return fmt.Errorf("unknown touch mode %q", c.Mode)
+	}
+	if c.Size < 0 || c.Opacity < 0 || c.Opacity > 1 {
+		return fmt.Errorf("invalid touch buttons: size %g, opacity %g", c.Size, c.Opacity)
+	}
+	return nil
+}
+
+// PhysicsConfig holds the fixed timestep settings. Zero values keep the
+// default: 60Hz, 5 steps per frame, a one second panic threshold.
+type PhysicsConfig struct {
+	// TickRate is the number of fixed updates per second, e.g. 60 or 120
+	TickRate int `json:"tickRate,omitempty"`
+	// MaxStepsPerFrame limits the fixed updates run to catch up in one frame
+	MaxStepsPerFrame int `json:"maxStepsPerFrame,omitempty"`
+	// PanicThresholdMs is how far behind, in milliseconds, the simulation
+	// may fall before the backlog is dropped
+	PanicThresholdMs int `json:"panicThresholdMs,omitempty"`
+}
+
+// validate checks the timestep settings for nonsense values.
+func (c PhysicsConfig) validate() error {
+	if c.TickRate < 0 || c.TickRate > 1000 {
+		return fmt.Errorf("invalid physics tick rate %d", c.TickRate)
+	}
+	if c.MaxStepsPerFrame < 0 || c.PanicThresholdMs < 0 {
+		return fmt.Errorf("invalid physics catch-up limits: %d steps per frame, %dms panic threshold",
+			c.MaxStepsPerFrame, c.PanicThresholdMs)
+	}
+	return nil
+}
+
+// LogConfig holds logging settings. Empty values keep the default: info
+// level, no log file.
+type LogConfig struct {
+	Level string `json:"level,omitempty"`
+	File  string `json:"file,omitempty"`
+}
+
+// validate checks the log level name.
+func (c LogConfig) validate() error {
+	if c.Level == "" {
+		return nil
+	}
+	_, err := logging.ParseLevel(c.Level)
+	return err
+}
+
+// Apply sets the log level and opens the log file.
+func (c LogConfig) Apply() error {
+	if c.Level != "" {
+		level, err := logging.ParseLevel(c.Level)
+		if err != nil {
+			return err
+		}
+		logging.SetLevel(level)
+	}
+	if c.File != "" {
+		return logging.SetFile(c.File)
+	}
+	return nil
+}
+
+// EditorCameraConfig holds the editor camera's zoom settings. Zero values
+// keep the default.
+type EditorCameraConfig struct {
+	MinZoom float64 `json:"minZoom,omitempty"`
+	MaxZoom float64 `json:"maxZoom,omitempty"`
+	// ZoomSteps are the zoom factors the mouse wheel snaps to, ascending
+	ZoomSteps []float64 `json:"zoomSteps,omitempty"`
+}
+
+// validate checks the zoom settings for nonsense values.
+func (c EditorCameraConfig) validate() error {
+	if c.MinZoom < 0 || c.MaxZoom < 0 || (c.MaxZoom > 0 && c.MaxZoom < c.MinZoom) {
+		return fmt.Errorf("invalid editor zoom range %g-%g", c.MinZoom, c.MaxZoom)
+	}
+	for i, z := range c.ZoomSteps {
+		if z <= 0 || (i > 0 && z <= c.ZoomSteps[i-1]) {
+			return fmt.Errorf("editor zoom steps must be positive and ascending, got %v", c.ZoomSteps)
+		}
+	}
+	return nil
+}
+
+// WindowConfig holds window settings. Zero values keep the default.
+type WindowConfig struct {
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Title  string `json:"title,omitempty"`
+}
+
+// Path returns the config file path from EnvPath, or DefaultPath if unset.
+func Path() string {
+	if path := os.Getenv(EnvPath); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Parse parses config file data.
+func Parse(data []byte) (*File, error) {
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	for _, w := range []WindowConfig{f.Window, f.EditorWindow} {
+		if w.Width < 0 || w.Height < 0 {
+			return nil, fmt.Errorf("invalid window size %dx%d", w.Width, w.Height)
+		}
+	}
+	if err := f.EditorCamera.validate(); err != nil {
+		return nil, err
+	}
+	if err := f.Log.validate(); err != nil {
+		return nil, err
+	}
+	if err := f.Physics.validate(); err != nil {
+		return nil, err
+	}
+	if err := f.Touch.validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Load reads the config file at path and applies environment overrides.
+// A missing file is not an error; the result then only has the overrides.
+func Load(path string) (*File, error) {
+	f := &File{}
+
+	data, err := storage.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// No config file, use defaults
+	case err != nil:
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	default:
+		if f, err = Parse(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := f.ApplyEnv(os.Getenv); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ApplyEnv overrides settings from environment variables read with getenv.
+// The window variables apply to the game window only.
+func (f *File) ApplyEnv(getenv func(string) string) error {
+	if v := getenv(EnvWindowWidth); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q", EnvWindowWidth, v)
+		}
+		f.Window.Width = n
+	}
+	if v := getenv(EnvWindowHeight); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s %q", EnvWindowHeight, v)
+		}
+		f.Window.Height = n
+	}
+	if v := getenv(EnvDebug); v != "" {
+		debug, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", EnvDebug, v)
+		}
+		f.Debug = &debug
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/torsten/GoP/internal/game"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`{
+		"window": {"width": 800, "height": 600, "title": "Test"},
+		"debug": true,
+		"keybinds": {"jump": ["Z", "Space"]},
+		"editorCamera": {"maxZoom": 8, "zoomSteps": [0.5, 1, 2, 4, 8]},
+		"log": {"level": "debug", "file": "gop.log"},
+		"physics": {"tickRate": 120, "maxStepsPerFrame": 8},
+		"touch": {"mode": "on", "size": 64}
+	}`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if f.Window.Width != 800 || f.Window.Height != 600 || f.Window.Title != "Test" {
+		t.Errorf("Window = %+v, want 800x600 \"Test\"", f.Window)
+	}
+	if f.Debug == nil || !*f.Debug {
+		t.Errorf("Debug = %v, want true", f.Debug)
+	}
+	if got := f.Keybinds["jump"]; len(got) != 2 || got[0] != "Z" {
+		t.Errorf("Keybinds[jump] = %v, want [Z Space]", got)
+	}
+	if c := f.EditorCamera; c.MinZoom != 0 || c.MaxZoom != 8 || len(c.ZoomSteps) != 5 {
+		t.Errorf("EditorCamera = %+v, want max 8 with 5 steps", c)
+	}
+	if f.Log.Level != "debug" || f.Log.File != "gop.log" {
+		t.Errorf("Log = %+v, want debug to gop.log", f.Log)
+	}
+	if p := f.Physics; p.TickRate != 120 || p.MaxStepsPerFrame != 8 || p.PanicThresholdMs != 0 {
+		t.Errorf("Physics = %+v, want 120Hz with 8 steps per frame", p)
+	}
+	if c := f.Touch; c.Mode != "on" || c.Size != 64 || c.Opacity != 0 {
+		t.Errorf("Touch = %+v, want on with 64px buttons", c)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		`{"window": {"width": -1}}`,
+		`{"tuning": {"jump": {"coyoteTime": 100}}}`,
+		`{"tuning": {"jump": {"coyoteTime": "soon"}}}`,
+		`{"editorCamera": {"minZoom": 2, "maxZoom": 1}}`,
+		`{"editorCamera": {"zoomSteps": [1, 0.5]}}`,
+		`{"log": {"level": "loud"}}`,
+		`{"physics": {"tickRate": -60}}`,
+		`{"physics": {"maxStepsPerFrame": -1}}`,
+		`{"touch": {"mode": "sometimes"}}`,
+		`{"touch": {"opacity": 1.5}}`,
+		`not json`,
+	}
+	for _, data := range tests {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%s) succeeded, want error", data)
+		}
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if f.Debug != nil || f.Window.Width != 0 {
+		t.Errorf("missing file should give an empty config, got %+v", f)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gop.json")
+	if err := os.WriteFile(path, []byte(`{"window": {"width": 1024}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvWindowHeight, "768")
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if f.Window.Width != 1024 || f.Window.Height != 768 {
+		t.Errorf("Window = %dx%d, want 1024x768", f.Window.Width, f.Window.Height)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		EnvWindowWidth: "1920",
+		EnvDebug:       "false",
+	}
+	debug := true
+	f := &File{Window: WindowConfig{Width: 800, Height: 600}, Debug: &debug}
+
+	if err := f.ApplyEnv(func(k string) string { return env[k] }); err != nil {
+		t.Fatalf("ApplyEnv failed: %v", err)
+	}
+	if f.Window.Width != 1920 {
+		t.Errorf("Width = %d, want 1920 from env", f.Window.Width)
+	}
+	if f.Window.Height != 600 {
+		t.Errorf("Height = %d, want 600 from file", f.Window.Height)
+	}
+	if f.Debug == nil || *f.Debug {
+		t.Errorf("Debug = %v, want false from env", f.Debug)
+	}
+}
+
+func TestApplyEnvInvalid(t *testing.T) {
+	for _, env := range []map[string]string{
+		{EnvWindowWidth: "wide"},
+		{EnvWindowHeight: "0"},
+		{EnvDebug: "maybe"},
+	} {
+		f := &File{}
+		if err := f.ApplyEnv(func(k string) string { return env[k] }); err == nil {
+			t.Errorf("ApplyEnv(%v) succeeded, want error", env)
+		}
+	}
+}
+
+func TestGameTuning(t *testing.T) {
+	data := []byte(`{
+		"tuning": {
+			"horizontal": {"maxSpeed": 300},
+			"jump": {"coyoteTime": "150ms", "variableHeight": false},
+			"gravity": {"maxFall": 900},
+			"health": {"maxHP": 3, "invulnerability": "500ms"}
+		}
+	}`)
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	got := f.GameTuning()
+	want := game.DefaultTuning()
+	want.Horizontal.MaxSpeed = 300
+	want.Jump.CoyoteTime = 150 * time.Millisecond
+	want.Jump.VariableHeight = false
+	want.Gravity.MaxFall = 900
+	want.Health.MaxHP = 3
+	want.Health.Invulnerability = 500 * time.Millisecond
+
+	if got != want {
+		t.Errorf("GameTuning() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGameTuningDefaults(t *testing.T) {
+	f := &File{}
+	if got := f.GameTuning(); got != game.DefaultTuning() {
+		t.Errorf("empty config should keep default tuning, got %+v", got)
+	}
+}
+
+func TestLoadTuning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuning.json")
+	data := []byte(`{"jump": {"velocity": 500, "bufferTime": "80ms"}}`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	base := game.DefaultTuning()
+	base.Horizontal.MaxSpeed = 123
+	got, err := LoadTuning(path, base)
+	if err != nil {
+		t.Fatalf("LoadTuning failed: %v", err)
+	}
+
+	want := base
+	want.Jump.Velocity = 500
+	want.Jump.BufferTime = 80 * time.Millisecond
+	if got != want {
+		t.Errorf("LoadTuning() = %+v, want %+v", got, want)
+	}
+
+	if _, err := LoadTuning(filepath.Join(t.TempDir(), "missing.json"), base); err == nil {
+		t.Error("LoadTuning of a missing file succeeded, want error")
+	}
+}
+
+func TestSaveTuning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuning.json")
+	want := game.DefaultTuning()
+	want.Horizontal.Friction = 0.3
+	want.Jump.Velocity = -320
+	want.Jump.CoyoteTime = 120 * time.Millisecond
+	want.Jump.VariableHeight = false
+	want.Health.MaxHP = 2
+
+	if err := SaveTuning(path, want); err != nil {
+		t.Fatalf("SaveTuning failed: %v", err)
+	}
+
+	// Every field is written, so the base doesn't show through
+	base := game.Tuning{}
+	got, err := LoadTuning(path, base)
+	if err != nil {
+		t.Fatalf("LoadTuning failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("LoadTuning(SaveTuning(t)) = %+v, want %+v", got, want)
+	}
+
+	if err := SaveTuning(filepath.Join(t.TempDir(), "missing", "tuning.json"), want); err == nil {
+		t.Error("SaveTuning into a missing directory succeeded, want error")
+	}
+}
+
+func TestTuningPaths(t *testing.T) {
+	paths := TuningPaths()
+	for _, want := range []string{"horizontal.maxSpeed", "jump.velocity", "jump.coyoteTime", "gravity.base", "health.maxHP"} {
+		found := false
+		for _, p := range paths {
+			found = found || p == want
+		}
+		if !found {
+			t.Errorf("TuningPaths() is missing %q: %v", want, paths)
+		}
+	}
+	if len(paths) != 28 {
+		t.Errorf("TuningPaths() has %d paths, want 28", len(paths))
+	}
+}
+
+func TestSetTuningValue(t *testing.T) {
+	got := game.DefaultTuning()
+	for _, set := range [][2]string{
+		{"jump.velocity", "-320"},
+		{"jump.coyoteTime", "120ms"},
+		{"jump.variableHeight", "false"},
+		{"health.maxHP", "3"},
+	} {
+		if err := SetTuningValue(&got, set[0], set[1]); err != nil {
+			t.Fatalf("SetTuningValue(%s, %s) failed: %v", set[0], set[1], err)
+		}
+	}
+
+	want := game.DefaultTuning()
+	want.Jump.Velocity = -320
+	want.Jump.CoyoteTime = 120 * time.Millisecond
+	want.Jump.VariableHeight = false
+	want.Health.MaxHP = 3
+	if got != want {
+		t.Errorf("SetTuningValue() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range [][2]string{
+		{"jump", "1"},
+		{"jump.height", "1"},
+		{"flight.velocity", "1"},
+		{"jump.velocity", "fast"},
+		{"jump.coyoteTime", "soon"},
+	} {
+		before := got
+		if err := SetTuningValue(&got, bad[0], bad[1]); err == nil {
+			t.Errorf("SetTuningValue(%s, %s) succeeded, want error", bad[0], bad[1])
+		}
+		if got != before {
+			t.Errorf("failed SetTuningValue(%s, %s) changed the tuning", bad[0], bad[1])
+		}
+	}
+}
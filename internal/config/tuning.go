@@ -0,0 +1,263 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// Duration is a time.Duration written as a string in config files, e.g. "100ms".
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"100ms\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// TuningOverrides mirrors game.Tuning with optional fields.
+// Only the fields set in the config file replace the defaults.
+type TuningOverrides struct {
+	Horizontal struct {
+		Acceleration  *float64 `json:"acceleration,omitempty"`
+		Deceleration  *float64 `json:"deceleration,omitempty"`
+		MaxSpeed      *float64 `json:"maxSpeed,omitempty"`
+		Friction      *float64 `json:"friction,omitempty"`
+		AirControl    *float64 `json:"airControl,omitempty"`
+		MaxStepHeight *float64 `json:"maxStepHeight,omitempty"`
+	} `json:"horizontal"`
+
+	Jump struct {
+		Velocity         *float64  `json:"velocity,omitempty"`
+		CoyoteTime       *Duration `json:"coyoteTime,omitempty"`
+		BufferTime       *Duration `json:"bufferTime,omitempty"`
+		VariableHeight   *bool     `json:"variableHeight,omitempty"`
+		EarlyReleaseMult *float64  `json:"earlyReleaseMult,omitempty"`
+		ApexThreshold    *float64  `json:"apexThreshold,omitempty"`
+		ApexGravityMult  *float64  `json:"apexGravityMult,omitempty"`
+		ApexSpeedBonus   *float64  `json:"apexSpeedBonus,omitempty"`
+		CornerCorrection *float64  `json:"cornerCorrection,omitempty"`
+		LedgeForgiveness *float64  `json:"ledgeForgiveness,omitempty"`
+	} `json:"jump"`
+
+	Gravity struct {
+		Base     *float64 `json:"base,omitempty"`
+		FallMult *float64 `json:"fallMult,omitempty"`
+		MaxFall  *float64 `json:"maxFall,omitempty"`
+	} `json:"gravity"`
+
+	Health struct {
+		MaxHP           *int      `json:"maxHP,omitempty"`
+		Invulnerability *Duration `json:"invulnerability,omitempty"`
+		Knockback       *float64  `json:"knockback,omitempty"`
+		KnockbackLift   *float64  `json:"knockbackLift,omitempty"`
+	} `json:"health"`
+
+	Crouch struct {
+		HeightMult *float64 `json:"heightMult,omitempty"`
+		SpeedMult  *float64 `json:"speedMult,omitempty"`
+	} `json:"crouch"`
+
+	Death struct {
+		HoldTime    *Duration `json:"holdTime,omitempty"`
+		FadeOutTime *Duration `json:"fadeOutTime,omitempty"`
+		FadeInTime  *Duration `json:"fadeInTime,omitempty"`
+	} `json:"death"`
+}
+
+// Apply writes the set overrides into t.
+func (o *TuningOverrides) Apply(t *game.Tuning) {
+	h, j, g, hp, cr, d := &o.Horizontal, &o.Jump, &o.Gravity, &o.Health, &o.Crouch, &o.Death
+
+	setFloat(&t.Horizontal.Acceleration, h.Acceleration)
+	setFloat(&t.Horizontal.Deceleration, h.Deceleration)
+	setFloat(&t.Horizontal.MaxSpeed, h.MaxSpeed)
+	setFloat(&t.Horizontal.Friction, h.Friction)
+	setFloat(&t.Horizontal.AirControl, h.AirControl)
+	setFloat(&t.Horizontal.MaxStepHeight, h.MaxStepHeight)
+
+	setFloat(&t.Jump.Velocity, j.Velocity)
+	setDuration(&t.Jump.CoyoteTime, j.CoyoteTime)
+	setDuration(&t.Jump.BufferTime, j.BufferTime)
+	if j.VariableHeight != nil {
+		t.Jump.VariableHeight = *j.VariableHeight
+	}
+	setFloat(&t.Jump.EarlyReleaseMult, j.EarlyReleaseMult)
+	setFloat(&t.Jump.ApexThreshold, j.ApexThreshold)
+	setFloat(&t.Jump.ApexGravityMult, j.ApexGravityMult)
+	setFloat(&t.Jump.ApexSpeedBonus, j.ApexSpeedBonus)
+	setFloat(&t.Jump.CornerCorrection, j.CornerCorrection)
+	setFloat(&t.Jump.LedgeForgiveness, j.LedgeForgiveness)
+
+	setFloat(&t.Gravity.Base, g.Base)
+	setFloat(&t.Gravity.FallMult, g.FallMult)
+	setFloat(&t.Gravity.MaxFall, g.MaxFall)
+
+	if hp.MaxHP != nil {
+		t.Health.MaxHP = *hp.MaxHP
+	}
+	setDuration(&t.Health.Invulnerability, hp.Invulnerability)
+	setFloat(&t.Health.Knockback, hp.Knockback)
+	setFloat(&t.Health.KnockbackLift, hp.KnockbackLift)
+
+	setFloat(&t.Crouch.HeightMult, cr.HeightMult)
+	setFloat(&t.Crouch.SpeedMult, cr.SpeedMult)
+
+	setDuration(&t.Death.HoldTime, d.HoldTime)
+	setDuration(&t.Death.FadeOutTime, d.FadeOutTime)
+	setDuration(&t.Death.FadeInTime, d.FadeInTime)
+}
+
+// GameTuning returns the default tuning with the file's overrides applied.
+func (f *File) GameTuning() game.Tuning {
+	t := game.DefaultTuning()
+	f.Tuning.Apply(&t)
+	return t
+}
+
+// LoadTuning reads a tuning file in the format of the config file's "tuning"
+// section and applies it on top of base.
+func LoadTuning(path string, base game.Tuning) (game.Tuning, error) {
+	data, err := storage.ReadFile(path)
+	if err != nil {
+		return base, fmt.Errorf("failed to read tuning: %w", err)
+	}
+	var o TuningOverrides
+	if err := json.Unmarshal(data, &o); err != nil {
+		return base, fmt.Errorf("%s: failed to parse tuning: %w", path, err)
+	}
+	o.Apply(&base)
+	return base, nil
+}
+
+// setFloat assigns *v to dst if v is set.
+func setFloat(dst *float64, v *float64) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// setDuration assigns *v to dst if v is set.
+func setDuration(dst *time.Duration, v *Duration) {
+	if v != nil {
+		*dst = time.Duration(*v)
+	}
+}
+
+// TuningOverridesFor returns overrides that set every field to t's value.
+func TuningOverridesFor(t game.Tuning) TuningOverrides {
+	var o TuningOverrides
+	h, j, g, hp, cr, d := &o.Horizontal, &o.Jump, &o.Gravity, &o.Health, &o.Crouch, &o.Death
+
+	h.Acceleration = &t.Horizontal.Acceleration
+	h.Deceleration = &t.Horizontal.Deceleration
+	h.MaxSpeed = &t.Horizontal.MaxSpeed
+	h.Friction = &t.Horizontal.Friction
+	h.AirControl = &t.Horizontal.AirControl
+	h.MaxStepHeight = &t.Horizontal.MaxStepHeight
+
+	j.Velocity = &t.Jump.Velocity
+	j.CoyoteTime = (*Duration)(&t.Jump.CoyoteTime)
+	j.BufferTime = (*Duration)(&t.Jump.BufferTime)
+	j.VariableHeight = &t.Jump.VariableHeight
+	j.EarlyReleaseMult = &t.Jump.EarlyReleaseMult
+	j.ApexThreshold = &t.Jump.ApexThreshold
+	j.ApexGravityMult = &t.Jump.ApexGravityMult
+	j.ApexSpeedBonus = &t.Jump.ApexSpeedBonus
+	j.CornerCorrection = &t.Jump.CornerCorrection
+	j.LedgeForgiveness = &t.Jump.LedgeForgiveness
+
+	g.Base = &t.Gravity.Base
+	g.FallMult = &t.Gravity.FallMult
+	g.MaxFall = &t.Gravity.MaxFall
+
+	hp.MaxHP = &t.Health.MaxHP
+	hp.Invulnerability = (*Duration)(&t.Health.Invulnerability)
+	hp.Knockback = &t.Health.Knockback
+	hp.KnockbackLift = &t.Health.KnockbackLift
+
+	cr.HeightMult = &t.Crouch.HeightMult
+	cr.SpeedMult = &t.Crouch.SpeedMult
+
+	d.HoldTime = (*Duration)(&t.Death.HoldTime)
+	d.FadeOutTime = (*Duration)(&t.Death.FadeOutTime)
+	d.FadeInTime = (*Duration)(&t.Death.FadeInTime)
+	return o
+}
+
+// SaveTuning writes t to path as a complete tuning file in the format
+// LoadTuning reads.
+func SaveTuning(path string, t game.Tuning) error {
+	data, err := json.MarshalIndent(TuningOverridesFor(t), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tuning: %w", err)
+	}
+	if err := storage.WriteFile(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write tuning: %w", err)
+	}
+	return nil
+}
+
+// TuningPaths returns the dotted names of all tuning values in the tuning
+// file format, e.g. "jump.velocity", sorted.
+func TuningPaths() []string {
+	data, err := json.Marshal(TuningOverridesFor(game.DefaultTuning()))
+	if err != nil {
+		return nil
+	}
+	var groups map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil
+	}
+
+	var paths []string
+	for group, fields := range groups {
+		for field := range fields {
+			paths = append(paths, group+"."+field)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// SetTuningValue sets one tuning value by its dotted name in the tuning file
+// format, e.g. "jump.velocity" to "-320" or "jump.coyoteTime" to "120ms".
+func SetTuningValue(t *game.Tuning, path, value string) error {
+	group, field, ok := strings.Cut(path, ".")
+	if !ok || group == "" || field == "" {
+		return fmt.Errorf("tuning value %q must be group.field, e.g. jump.velocity", path)
+	}
+	if !json.Valid([]byte(value)) {
+		value = strconv.Quote(value) // Durations are strings
+	}
+	data := fmt.Sprintf(`{%q: {%q: %s}}`, group, field, value)
+
+	var o TuningOverrides
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&o); err != nil {
+		return fmt.Errorf("tuning value %s: %w", path, err)
+	}
+	o.Apply(t)
+	return nil
+}
@@ -0,0 +1,278 @@
+// Package debugui provides debug overlays shared by the game and the editor.
+package debugui
+
+import (
+	"fmt"
+	"image/color"
+	"runtime"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// Profiler layout and sampling
+const (
+	profilerHistory     = 120 // Frames shown in the graph
+	ProfilerWidth       = 240 // Width of the overlay in pixels
+	profilerGraphHeight = 60
+	profilerLineHeight  = 14
+	profilerPadding     = 6
+	memStatsInterval    = 30 // Frames between runtime.ReadMemStats calls
+
+	// graphBudget is the frame time at the top of the graph: two 60 FPS frames
+	graphBudget = 2 * time.Second / 60
+)
+
+// Profiler colors
+var (
+	profilerBackground  = color.RGBA{0, 0, 0, 190}
+	profilerUpdateColor = color.RGBA{90, 170, 255, 255}
+	profilerDrawColor   = color.RGBA{255, 170, 60, 255}
+	profilerBudgetColor = color.RGBA{255, 255, 255, 80}
+)
+
+// Sample is the timing of one frame.
+type Sample struct {
+	Frame  time.Duration // Time since the previous frame started
+	Update time.Duration // Time spent in Update calls
+	Draw   time.Duration // Time spent in Draw
+	Steps  int           // Physics steps run
+}
+
+// Stat is a named count shown by the profiler, e.g. the number of entities.
+type Stat struct {
+	Name  string
+	Value int
+}
+
+// StatsProvider is implemented by scenes (and the editor) that report counts
+// to the profiler, e.g. entities and estimated draw calls.
+type StatsProvider interface {
+	ProfilerStats() []Stat
+}
+
+// Profiler records frame timings and draws them as an overlay with a frame
+// time graph, the update/draw split, physics steps, caller-provided stats and
+// GC statistics.
+//
+// Call BeginUpdate/EndUpdate around each Update and BeginDraw/EndDraw around
+// each Draw; EndDraw completes the frame's sample.
+type Profiler struct {
+	visible bool
+
+	samples []Sample // Ring buffer of completed frames
+	next    int
+	full    bool
+
+	current     Sample
+	inFrame     bool // Whether current has been started
+	frameStart  time.Time
+	updateStart time.Time
+	drawStart   time.Time
+
+	stats []Stat
+
+	mem      runtime.MemStats
+	memFrame int // Frames until the memory stats are read again
+	now      func() time.Time
+}
+
+// NewProfiler creates a hidden profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		samples: make([]Sample, profilerHistory),
+		now:     time.Now,
+	}
+}
+
+// Visible returns whether the overlay is shown.
+func (p *Profiler) Visible() bool {
+	return p.visible
+}
+
+// SetVisible shows or hides the overlay.
+func (p *Profiler) SetVisible(visible bool) {
+	p.visible = visible
+}
+
+// Toggle shows or hides the overlay.
+func (p *Profiler) Toggle() {
+	p.visible = !p.visible
+}
+
+// startFrame starts a new sample at now unless one is in progress.
+func (p *Profiler) startFrame(now time.Time) {
+	if p.inFrame {
+		return
+	}
+	if !p.frameStart.IsZero() {
+		p.current.Frame = now.Sub(p.frameStart)
+	}
+	p.frameStart = now
+	p.inFrame = true
+}
+
+// BeginUpdate marks the start of an Update call. The first one after a Draw
+// starts a new frame.
+func (p *Profiler) BeginUpdate() {
+	p.updateStart = p.now()
+	p.startFrame(p.updateStart)
+}
+
+// EndUpdate marks the end of an Update call that ran steps physics steps.
+func (p *Profiler) EndUpdate(steps int) {
+	p.current.Update += p.now().Sub(p.updateStart)
+	p.current.Steps += steps
+}
+
+// BeginDraw marks the start of a Draw call. Ebiten can draw without
+// updating, in which case it starts the frame.
+func (p *Profiler) BeginDraw() {
+	p.drawStart = p.now()
+	p.startFrame(p.drawStart)
+}
+
+// EndDraw marks the end of a Draw call and records the frame's sample.
+func (p *Profiler) EndDraw() {
+	p.current.Draw += p.now().Sub(p.drawStart)
+	p.samples[p.next] = p.current
+	p.next = (p.next + 1) % len(p.samples)
+	if p.next == 0 {
+		p.full = true
+	}
+	p.current = Sample{}
+	p.inFrame = false
+}
+
+// SetStats replaces the counts shown below the timings.
+func (p *Profiler) SetStats(stats []Stat) {
+	p.stats = stats
+}
+
+// Samples returns the recorded frames, oldest first.
+func (p *Profiler) Samples() []Sample {
+	if !p.full {
+		return append([]Sample(nil), p.samples[:p.next]...)
+	}
+	return append(append([]Sample(nil), p.samples[p.next:]...), p.samples[:p.next]...)
+}
+
+// Summary is the average of the recorded frames.
+type Summary struct {
+	Frame, Update, Draw time.Duration
+	Worst               time.Duration // Slowest frame time
+	Steps               float64       // Physics steps per frame
+}
+
+// Summarize averages the recorded frames.
+func (p *Profiler) Summarize() Summary {
+	var sum Summary
+	samples := p.Samples()
+	if len(samples) == 0 {
+		return sum
+	}
+	steps := 0
+	for _, s := range samples {
+		sum.Frame += s.Frame
+		sum.Update += s.Update
+		sum.Draw += s.Draw
+		sum.Worst = max(sum.Worst, s.Frame)
+		steps += s.Steps
+	}
+	n := len(samples)
+	sum.Frame /= time.Duration(n)
+	sum.Update /= time.Duration(n)
+	sum.Draw /= time.Duration(n)
+	sum.Steps = float64(steps) / float64(n)
+	return sum
+}
+
+// Draw renders the overlay in the top-right corner if it's visible.
+func (p *Profiler) Draw(screen *ebiten.Image) {
+	p.DrawAt(screen, screen.Bounds().Dx()-ProfilerWidth-profilerPadding, profilerPadding)
+}
+
+// DrawAt renders the overlay with its top-left corner at x, y if it's visible.
+func (p *Profiler) DrawAt(screen *ebiten.Image, x, y int) {
+	if !p.visible {
+		return
+	}
+
+	// Reading memory stats stops the world briefly, so don't do it every frame
+	if p.memFrame <= 0 {
+		runtime.ReadMemStats(&p.mem)
+		p.memFrame = memStatsInterval
+	}
+	p.memFrame--
+
+	lines := p.lines()
+	height := profilerGraphHeight + 3*profilerPadding + len(lines)*profilerLineHeight
+	draw.FillRect(screen, float64(x), float64(y), ProfilerWidth, float64(height), profilerBackground)
+
+	p.drawGraph(screen, float64(x+profilerPadding), float64(y+profilerPadding), ProfilerWidth-2*profilerPadding)
+
+	textY := y + profilerGraphHeight + 2*profilerPadding
+	for _, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, x+profilerPadding, textY)
+		textY += profilerLineHeight
+	}
+}
+
+// drawGraph draws one bar per frame, update time stacked under draw time,
+// scaled so graphBudget fills the graph. The line marks one 60 FPS frame.
+func (p *Profiler) drawGraph(screen *ebiten.Image, x, y, width float64) {
+	barWidth := width / profilerHistory
+	toHeight := func(d time.Duration) float64 {
+		return min(float64(d)/float64(graphBudget), 1) * profilerGraphHeight
+	}
+
+	bottom := y + profilerGraphHeight
+	samples := p.Samples()
+	offset := profilerHistory - len(samples)
+	for i, s := range samples {
+		bx := x + float64(offset+i)*barWidth
+		uh := toHeight(s.Update)
+		dh := min(toHeight(s.Draw), profilerGraphHeight-uh)
+		draw.FillRect(screen, bx, bottom-uh, barWidth, uh, profilerUpdateColor)
+		draw.FillRect(screen, bx, bottom-uh-dh, barWidth, dh, profilerDrawColor)
+	}
+
+	budgetY := bottom - toHeight(time.Second/60)
+	draw.Line(screen, x, budgetY, x+width, budgetY, 1, profilerBudgetColor)
+}
+
+// lines returns the overlay's text.
+func (p *Profiler) lines() []string {
+	avg := p.Summarize()
+	fps := 0.0
+	if avg.Frame > 0 {
+		fps = float64(time.Second) / float64(avg.Frame)
+	}
+
+	lines := []string{
+		fmt.Sprintf("frame  %5.2fms (%3.0f FPS)", ms(avg.Frame), fps),
+		fmt.Sprintf("worst  %5.2fms", ms(avg.Worst)),
+		fmt.Sprintf("update %5.2fms  draw %5.2fms", ms(avg.Update), ms(avg.Draw)),
+		fmt.Sprintf("physics steps/frame %.2f", avg.Steps),
+	}
+	for _, s := range p.stats {
+		lines = append(lines, fmt.Sprintf("%s %d", s.Name, s.Value))
+	}
+
+	var lastPause time.Duration
+	if p.mem.NumGC > 0 {
+		lastPause = time.Duration(p.mem.PauseNs[(p.mem.NumGC+255)%256])
+	}
+	lines = append(lines,
+		fmt.Sprintf("heap %.1fMB  objects %d", float64(p.mem.HeapAlloc)/(1<<20), p.mem.HeapObjects),
+		fmt.Sprintf("GC %d  last pause %.2fms", p.mem.NumGC, ms(lastPause)),
+	)
+	return lines
+}
+
+// ms converts a duration to fractional milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
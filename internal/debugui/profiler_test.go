@@ -0,0 +1,78 @@
+//go:build display
+
+package debugui
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock advances by the given steps each time it's read.
+type fakeClock struct {
+	t     time.Time
+	steps []time.Duration
+}
+
+func (c *fakeClock) now() time.Time {
+	if len(c.steps) > 0 {
+		c.t = c.t.Add(c.steps[0])
+		c.steps = c.steps[1:]
+	}
+	return c.t
+}
+
+func TestProfilerSamples(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	p := NewProfiler()
+	p.now = clock.now
+
+	// Frame 1: two updates (2ms, 3ms) then a 4ms draw
+	clock.steps = []time.Duration{0, 2 * time.Millisecond, 0, 3 * time.Millisecond, 0, 4 * time.Millisecond}
+	p.BeginUpdate()
+	p.EndUpdate(1)
+	p.BeginUpdate()
+	p.EndUpdate(1)
+	p.BeginDraw()
+	p.EndDraw()
+
+	// Frame 2 starts 16ms after frame 1 and only draws
+	clock.steps = []time.Duration{7 * time.Millisecond, 6 * time.Millisecond}
+	p.BeginDraw()
+	p.EndDraw()
+
+	samples := p.Samples()
+	if len(samples) != 2 {
+		t.Fatalf("recorded %d samples, want 2", len(samples))
+	}
+	want := Sample{Update: 5 * time.Millisecond, Draw: 4 * time.Millisecond, Steps: 2}
+	if samples[0] != want {
+		t.Errorf("frame 1 = %+v, want %+v", samples[0], want)
+	}
+	want = Sample{Frame: 16 * time.Millisecond, Draw: 6 * time.Millisecond}
+	if samples[1] != want {
+		t.Errorf("frame 2 = %+v, want %+v", samples[1], want)
+	}
+
+	sum := p.Summarize()
+	if sum.Frame != 8*time.Millisecond || sum.Worst != 16*time.Millisecond || sum.Steps != 1 {
+		t.Errorf("Summarize() = %+v, want frame 8ms, worst 16ms, 1 step", sum)
+	}
+}
+
+func TestProfilerHistory(t *testing.T) {
+	p := NewProfiler()
+	for i := 0; i < profilerHistory+5; i++ {
+		p.BeginUpdate()
+		p.EndUpdate(i)
+		p.BeginDraw()
+		p.EndDraw()
+	}
+
+	samples := p.Samples()
+	if len(samples) != profilerHistory {
+		t.Fatalf("kept %d samples, want %d", len(samples), profilerHistory)
+	}
+	if first, last := samples[0].Steps, samples[len(samples)-1].Steps; first != 5 || last != profilerHistory+4 {
+		t.Errorf("samples run from step %d to %d, want 5 to %d", first, last, profilerHistory+4)
+	}
+}
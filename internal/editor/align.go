@@ -0,0 +1,349 @@
+package editor
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// AlignMode is a way of lining up selected objects.
+type AlignMode int
+
+const (
+	// AlignLeft lines up the left edges with the leftmost object.
+	AlignLeft AlignMode = iota
+	// AlignRight lines up the right edges with the rightmost object.
+	AlignRight
+	// AlignTop lines up the top edges with the topmost object.
+	AlignTop
+	// AlignBottom lines up the bottom edges with the bottommost object.
+	AlignBottom
+	// AlignCenterX lines up the horizontal centers with the selection's center.
+	AlignCenterX
+	// AlignCenterY lines up the vertical centers with the selection's center.
+	AlignCenterY
+)
+
+// selectionBounds returns the box around the objects at indices.
+func selectionBounds(objects []world.ObjectData, indices []int) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, idx := range indices {
+		obj := objects[idx]
+		minX = math.Min(minX, obj.X)
+		minY = math.Min(minY, obj.Y)
+		maxX = math.Max(maxX, obj.X+obj.W)
+		maxY = math.Max(maxY, obj.Y+obj.H)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// validIndices drops indices outside objects.
+func validIndices(objects []world.ObjectData, indices []int) []int {
+	valid := make([]int, 0, len(indices))
+	for _, idx := range indices {
+		if idx >= 0 && idx < len(objects) {
+			valid = append(valid, idx)
+		}
+	}
+	return valid
+}
+
+// newMoveObjectsAction creates a composite action moving each object to
+// positions[i]. Objects already in place are left out. Returns nil if nothing
+// moves.
+func newMoveObjectsAction(desc string, objects []world.ObjectData, indices []int, positions [][2]float64) *CompositeAction {
+	actions := make([]Action, 0, len(indices))
+	for i, idx := range indices {
+		obj := objects[idx]
+		x, y := positions[i][0], positions[i][1]
+		if x != obj.X || y != obj.Y {
+			actions = append(actions, NewMoveObjectAction(idx, obj.X, obj.Y, x, y))
+		}
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	return NewCompositeAction(desc, actions...)
+}
+
+// NewAlignAction creates an action lining up the objects at indices. Needs at
+// least two objects; returns nil if nothing would move.
+func NewAlignAction(objects []world.ObjectData, indices []int, mode AlignMode) *CompositeAction {
+	indices = validIndices(objects, indices)
+	if len(indices) < 2 {
+		return nil
+	}
+
+	minX, minY, maxX, maxY := selectionBounds(objects, indices)
+	positions := make([][2]float64, len(indices))
+	for i, idx := range indices {
+		obj := objects[idx]
+		x, y := obj.X, obj.Y
+		switch mode {
+		case AlignLeft:
+			x = minX
+		case AlignRight:
+			x = maxX - obj.W
+		case AlignTop:
+			y = minY
+		case AlignBottom:
+			y = maxY - obj.H
+		case AlignCenterX:
+			x = (minX+maxX)/2 - obj.W/2
+		case AlignCenterY:
+			y = (minY+maxY)/2 - obj.H/2
+		}
+		positions[i] = [2]float64{x, y}
+	}
+
+	return newMoveObjectsAction(alignDescriptions[mode], objects, indices, positions)
+}
+
+// alignDescriptions are the undo descriptions of the align modes.
+var alignDescriptions = map[AlignMode]string{
+	AlignLeft:    "Align left",
+	AlignRight:   "Align right",
+	AlignTop:     "Align top",
+	AlignBottom:  "Align bottom",
+	AlignCenterX: "Align horizontal centers",
+	AlignCenterY: "Align vertical centers",
+}
+
+// NewDistributeAction creates an action spacing the objects at indices evenly
+// between the outermost two, horizontally or vertically, with equal gaps
+// between them. Needs at least three objects; returns nil if nothing would
+// move.
+func NewDistributeAction(objects []world.ObjectData, indices []int, horizontal bool) *CompositeAction {
+	indices = validIndices(objects, indices)
+	if len(indices) < 3 {
+		return nil
+	}
+
+	// Work along one axis: position and size
+	pos := func(o world.ObjectData) float64 { return o.Y }
+	size := func(o world.ObjectData) float64 { return o.H }
+	desc := "Distribute vertically"
+	if horizontal {
+		pos = func(o world.ObjectData) float64 { return o.X }
+		size = func(o world.ObjectData) float64 { return o.W }
+		desc = "Distribute horizontally"
+	}
+
+	sorted := append([]int(nil), indices...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := objects[sorted[i]], objects[sorted[j]]
+		return pos(a)+size(a)/2 < pos(b)+size(b)/2
+	})
+
+	first, last := objects[sorted[0]], objects[sorted[len(sorted)-1]]
+	total := 0.0
+	for _, idx := range sorted {
+		total += size(objects[idx])
+	}
+	gap := (pos(last) + size(last) - pos(first) - total) / float64(len(sorted)-1)
+
+	positions := make([][2]float64, len(sorted))
+	next := pos(first)
+	for i, idx := range sorted {
+		obj := objects[idx]
+		if horizontal {
+			positions[i] = [2]float64{next, obj.Y}
+		} else {
+			positions[i] = [2]float64{obj.X, next}
+		}
+		next += size(obj) + gap
+	}
+
+	return newMoveObjectsAction(desc, objects, sorted, positions)
+}
+
+// NewSnapToGridAction creates an action moving each object at indices to the
+// nearest grid position. Returns nil if all are already on the grid.
+func NewSnapToGridAction(objects []world.ObjectData, indices []int, gridW, gridH int) *CompositeAction {
+	indices = validIndices(objects, indices)
+	if len(indices) == 0 || gridW <= 0 || gridH <= 0 {
+		return nil
+	}
+
+	positions := make([][2]float64, len(indices))
+	for i, idx := range indices {
+		obj := objects[idx]
+		positions[i] = [2]float64{
+			math.Round(obj.X/float64(gridW)) * float64(gridW),
+			math.Round(obj.Y/float64(gridH)) * float64(gridH),
+		}
+	}
+
+	return newMoveObjectsAction("Snap to grid", objects, indices, positions)
+}
+
+// Align toolbar layout
+const (
+	alignToolbarX      = 8
+	alignToolbarY      = 8
+	alignButtonWidth   = 26
+	alignButtonHeight  = 18
+	alignButtonSpacing = 2
+)
+
+// alignButton is one button of the align toolbar.
+type alignButton struct {
+	label   string
+	tooltip string
+	action  func(state *EditorState, indices []int) *CompositeAction
+}
+
+// alignButtons are the toolbar buttons, left to right.
+var alignButtons = []alignButton{
+	{"L", "Align left (Alt+Left)", alignWith(AlignLeft)},
+	{"CX", "Align horizontal centers (Alt+M)", alignWith(AlignCenterX)},
+	{"R", "Align right (Alt+Right)", alignWith(AlignRight)},
+	{"T", "Align top (Alt+Up)", alignWith(AlignTop)},
+	{"CY", "Align vertical centers (Alt+Shift+M)", alignWith(AlignCenterY)},
+	{"B", "Align bottom (Alt+Down)", alignWith(AlignBottom)},
+	{"DH", "Distribute horizontally (Alt+D)", distributeWith(true)},
+	{"DV", "Distribute vertically (Alt+Shift+D)", distributeWith(false)},
+	{"#", "Snap to grid (Alt+R)", snapSelection},
+}
+
+func alignWith(mode AlignMode) func(*EditorState, []int) *CompositeAction {
+	return func(state *EditorState, indices []int) *CompositeAction {
+		return NewAlignAction(state.Objects, indices, mode)
+	}
+}
+
+func distributeWith(horizontal bool) func(*EditorState, []int) *CompositeAction {
+	return func(state *EditorState, indices []int) *CompositeAction {
+		return NewDistributeAction(state.Objects, indices, horizontal)
+	}
+}
+
+func snapSelection(state *EditorState, indices []int) *CompositeAction {
+	if state.MapData == nil {
+		return nil
+	}
+	return NewSnapToGridAction(state.Objects, indices, state.MapData.TileWidth(), state.MapData.TileHeight())
+}
+
+// AlignToolbar is the row of align and distribute buttons shown over the
+// canvas while several objects are selected. The same commands are on
+// Alt shortcuts.
+type AlignToolbar struct {
+	hovered int // Index of the button under the cursor, -1 if none
+}
+
+// NewAlignToolbar creates a new align toolbar.
+func NewAlignToolbar() *AlignToolbar {
+	return &AlignToolbar{hovered: -1}
+}
+
+// visible returns whether the toolbar is shown.
+func (t *AlignToolbar) visible(state *EditorState) bool {
+	selection := state.GetSelectionManager()
+	return state.CurrentTool == ToolSelect && selection != nil && selection.SelectionCount() > 1
+}
+
+// buttonAt returns the button index at a screen position, or -1.
+func (t *AlignToolbar) buttonAt(mx, my int) int {
+	if my < alignToolbarY || my >= alignToolbarY+alignButtonHeight || mx < alignToolbarX {
+		return -1
+	}
+	i := (mx - alignToolbarX) / (alignButtonWidth + alignButtonSpacing)
+	if i >= len(alignButtons) || (mx-alignToolbarX)%(alignButtonWidth+alignButtonSpacing) >= alignButtonWidth {
+		return -1
+	}
+	return i
+}
+
+// Update handles toolbar clicks and the Alt shortcuts. Returns true if a
+// click landed on the toolbar, so the canvas shouldn't handle it.
+func (t *AlignToolbar) Update(state *EditorState) bool {
+	selection := state.GetSelectionManager()
+	if selection == nil || !selection.HasSelection() {
+		t.hovered = -1
+		return false
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyAlt) && !state.IsEditingProperty {
+		shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+		button := -1
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+			button = 0
+		case inpututil.IsKeyJustPressed(ebiten.KeyM) && !shift:
+			button = 1
+		case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+			button = 2
+		case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+			button = 3
+		case inpututil.IsKeyJustPressed(ebiten.KeyM) && shift:
+			button = 4
+		case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+			button = 5
+		case inpututil.IsKeyJustPressed(ebiten.KeyD) && !shift:
+			button = 6
+		case inpututil.IsKeyJustPressed(ebiten.KeyD) && shift:
+			button = 7
+		case inpututil.IsKeyJustPressed(ebiten.KeyR):
+			button = 8
+		}
+		if button >= 0 {
+			t.apply(state, button)
+		}
+	}
+
+	if !t.visible(state) {
+		t.hovered = -1
+		return false
+	}
+	mx, my := ebiten.CursorPosition()
+	t.hovered = t.buttonAt(mx, my)
+	if t.hovered < 0 {
+		return false
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		t.apply(state, t.hovered)
+	}
+	return true
+}
+
+// apply runs a toolbar command on the selection.
+func (t *AlignToolbar) apply(state *EditorState, button int) {
+	b := alignButtons[button]
+	action := b.action(state, state.GetSelectionManager().SelectedIndices())
+	if action == nil {
+		state.ShowStatusMessage(i18n.T("editor.nothingToDo", b.tooltip), false)
+		return
+	}
+	state.History.Do(action, state)
+	state.ShowStatusMessage(action.Description(), false)
+}
+
+// Draw renders the toolbar and the tooltip of the hovered button.
+func (t *AlignToolbar) Draw(screen *ebiten.Image, state *EditorState) {
+	if !t.visible(state) {
+		return
+	}
+
+	for i, b := range alignButtons {
+		x := float64(alignToolbarX + i*(alignButtonWidth+alignButtonSpacing))
+		bg := color.RGBA{40, 40, 50, 230}
+		if i == t.hovered {
+			bg = propertyHoverColor
+		}
+		draw.FillRect(screen, x, alignToolbarY, alignButtonWidth, alignButtonHeight, bg)
+		ebitenutil.DebugPrintAt(screen, b.label, int(x)+(alignButtonWidth-len(b.label)*6)/2, alignToolbarY+1)
+	}
+
+	if t.hovered >= 0 {
+		ebitenutil.DebugPrintAt(screen, alignButtons[t.hovered].tooltip, alignToolbarX, alignToolbarY+alignButtonHeight+4)
+	}
+}
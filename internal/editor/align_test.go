@@ -0,0 +1,125 @@
+//go:build display
+
+package editor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// alignObjects returns three objects of different sizes, spanning 10..82
+// horizontally and 0..64 vertically.
+func alignObjects() []world.ObjectData {
+	return []world.ObjectData{
+		{ID: 1, X: 10, Y: 20, W: 16, H: 16},
+		{ID: 2, X: 50, Y: 0, W: 32, H: 8},
+		{ID: 3, X: 30, Y: 40, W: 8, H: 24},
+	}
+}
+
+// positions returns the objects' top-left corners.
+func positions(objects []world.ObjectData) [][2]float64 {
+	p := make([][2]float64, len(objects))
+	for i, obj := range objects {
+		p[i] = [2]float64{obj.X, obj.Y}
+	}
+	return p
+}
+
+// checkDoUndo applies action to objects, compares the positions with want
+// and checks undoing puts every object back.
+func checkDoUndo(t *testing.T, objects []world.ObjectData, action *CompositeAction, want [][2]float64) {
+	t.Helper()
+	if action == nil {
+		t.Fatal("got no action")
+	}
+	before := positions(objects)
+	state := &EditorState{Objects: objects}
+	action.Do(state)
+	if got := positions(state.Objects); !reflect.DeepEqual(got, want) {
+		t.Errorf("after Do positions %v, want %v", got, want)
+	}
+	action.Undo(state)
+	if got := positions(state.Objects); !reflect.DeepEqual(got, before) {
+		t.Errorf("after Undo positions %v, want %v", got, before)
+	}
+}
+
+func TestNewAlignAction(t *testing.T) {
+	tests := []struct {
+		mode AlignMode
+		want [][2]float64
+	}{
+		{AlignLeft, [][2]float64{{10, 20}, {10, 0}, {10, 40}}},
+		{AlignRight, [][2]float64{{66, 20}, {50, 0}, {74, 40}}},
+		{AlignTop, [][2]float64{{10, 0}, {50, 0}, {30, 0}}},
+		{AlignBottom, [][2]float64{{10, 48}, {50, 56}, {30, 40}}},
+		{AlignCenterX, [][2]float64{{38, 20}, {30, 0}, {42, 40}}},
+		{AlignCenterY, [][2]float64{{10, 24}, {50, 28}, {30, 20}}},
+	}
+	for _, tt := range tests {
+		t.Run(alignDescriptions[tt.mode], func(t *testing.T) {
+			objects := alignObjects()
+			action := NewAlignAction(objects, []int{0, 1, 2}, tt.mode)
+			checkDoUndo(t, objects, action, tt.want)
+			if action != nil && action.Description() != alignDescriptions[tt.mode] {
+				t.Errorf("description %q, want %q", action.Description(), alignDescriptions[tt.mode])
+			}
+		})
+	}
+}
+
+func TestNewAlignActionNothingToDo(t *testing.T) {
+	objects := alignObjects()
+	if NewAlignAction(objects, []int{0, 7}, AlignLeft) != nil {
+		t.Error("aligning one valid object gave an action")
+	}
+	objects[1].X = 10
+	objects[2].X = 10
+	if NewAlignAction(objects, []int{0, 1, 2}, AlignLeft) != nil {
+		t.Error("aligning objects already in line gave an action")
+	}
+}
+
+func TestNewDistributeAction(t *testing.T) {
+	// 70 wide in 120: the outer two stay and the gaps are 25 each, whatever
+	// order the selection is in
+	objects := []world.ObjectData{
+		{ID: 1, X: 0, Y: 0, W: 10, H: 10},
+		{ID: 2, X: 30, Y: 30, W: 40, H: 40},
+		{ID: 3, X: 100, Y: 100, W: 20, H: 20},
+	}
+	checkDoUndo(t, objects, NewDistributeAction(objects, []int{2, 0, 1}, true),
+		[][2]float64{{0, 0}, {35, 30}, {100, 100}})
+	checkDoUndo(t, objects, NewDistributeAction(objects, []int{1, 2, 0}, false),
+		[][2]float64{{0, 0}, {30, 35}, {100, 100}})
+
+	if NewDistributeAction(objects, []int{0, 2}, true) != nil {
+		t.Error("distributing two objects gave an action")
+	}
+	objects[1].X = 35
+	if NewDistributeAction(objects, []int{0, 1, 2}, true) != nil {
+		t.Error("distributing evenly spaced objects gave an action")
+	}
+}
+
+func TestNewSnapToGridAction(t *testing.T) {
+	// Halves round away from zero, on both sides of the origin
+	objects := []world.ObjectData{
+		{ID: 1, X: -9, Y: -7},
+		{ID: 2, X: -8, Y: -24},
+		{ID: 3, X: 8, Y: 23},
+		{ID: 4, X: 32, Y: -40},
+	}
+	checkDoUndo(t, objects, NewSnapToGridAction(objects, []int{0, 1, 2, 3}, 16, 16),
+		[][2]float64{{-16, 0}, {-16, -32}, {16, 16}, {32, -48}})
+
+	if NewSnapToGridAction(objects, []int{0}, 0, 16) != nil {
+		t.Error("snapping to a grid 0 wide gave an action")
+	}
+	if NewSnapToGridAction([]world.ObjectData{{X: -16, Y: 32}}, []int{0}, 16, 16) != nil {
+		t.Error("snapping an object on the grid gave an action")
+	}
+}
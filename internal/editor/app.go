@@ -3,13 +3,21 @@ package editor
 import (
 	"fmt"
 	"image/color"
-	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/debugui"
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/levelcheck"
+	"github.com/torsten/GoP/internal/logging"
 )
 
+// logger is shared by the editor package.
+var logger = logging.New("editor")
+
 // ConfirmDialog represents a modal confirmation dialog.
 type ConfirmDialog struct {
 	Message   string
@@ -27,11 +35,22 @@ type App struct {
 	screenWidth     int
 	screenHeight    int
 	validation      *ValidationResult   // Last validation result
+	ruleCount       int                 // Rules in the level's rules file, for the budget counter
 	playtest        *PlaytestController // Playtest mode controller
 	clipboard       *Clipboard          // Clipboard for copy/paste
+	prefabs         *PrefabLibrary      // Prefabs listed in the object palette
 	showHelp        bool                // Show keyboard shortcuts overlay
 	minimap         *Minimap            // Minimap component
 	confirmDialog   *ConfirmDialog      // Active confirmation dialog (nil when none)
+	findReplace     *FindReplaceDialog  // Find/replace dialog for object properties
+	outliner        *OutlinerPanel      // Object outliner and search
+	overviewDialog  *OverviewDialog     // Scale picker for the overview image export
+	problems        *ProblemsPanel      // List of validation problems
+	layersPanel     *LayersPanel        // Tile layers with their opacity and tint
+	liveValidation  *LiveValidator      // Revalidates shortly after each change
+	alignToolbar    *AlignToolbar       // Align/distribute buttons for multi-selections
+	statusBar       *StatusBar          // Status strip along the bottom of the canvas
+	profiler        *debugui.Profiler   // Performance overlay (F7)
 }
 
 // NewApp creates a new editor application.
@@ -70,8 +89,8 @@ func NewApp() *App {
 	// Set up the link mode callback from properties panel
 	propertiesPanel.OnStartLinkMode = func(switchIndex int) {
 		state.StartLinkMode(switchIndex)
-		state.ShowStatusMessage("Click on a door to link, or press Escape to cancel", false)
-		log.Printf("Started link mode for switch at index %d", switchIndex)
+		state.ShowStatusMessage(i18n.T("editor.linkHint"), false)
+		logger.Debugf("Started link mode for switch at index %d", switchIndex)
 	}
 
 	// Create playtest controller with reference to app
@@ -80,20 +99,91 @@ func NewApp() *App {
 	// Create clipboard
 	app.clipboard = NewClipboard()
 
+	// Create an empty prefab library until SetPrefabPath loads one
+	app.prefabs = NewPrefabLibrary("")
+	objectPalette.SetPrefabs(app.prefabs)
+
 	// Create minimap
 	app.minimap = NewMinimap()
 
+	// Create find/replace dialog
+	app.findReplace = NewFindReplaceDialog()
+
+	// Create outliner
+	app.outliner = NewOutlinerPanel()
+
+	// Create overview export dialog
+	app.overviewDialog = NewOverviewDialog()
+
+	// Create problems panel and live validation
+	app.problems = NewProblemsPanel()
+	app.liveValidation = NewLiveValidator()
+
+	// Create layers panel
+	app.layersPanel = NewLayersPanel()
+
+	// Create align toolbar
+	app.alignToolbar = NewAlignToolbar()
+
+	// Create status bar
+	app.statusBar = NewStatusBar()
+
+	// Create profiler
+	app.profiler = debugui.NewProfiler()
+
 	return app
 }
 
+// SetZoomLimits sets the canvas zoom range and the steps the mouse wheel
+// snaps to. Zero limits and nil steps keep the defaults.
+func (a *App) SetZoomLimits(minZoom, maxZoom float64, steps []float64) {
+	a.camera.SetZoomLimits(minZoom, maxZoom, steps)
+}
+
+// SetTuningPath sets the tuning file the playtest tuning overlay saves to.
+func (a *App) SetTuningPath(path string) {
+	a.playtest.SetTuningPath(path)
+}
+
+// SetPrefabPath loads the prefab library at path (DefaultPrefabPath if
+// empty); saving a prefab writes to it. A missing file is an empty library.
+func (a *App) SetPrefabPath(path string) error {
+	lib, err := LoadPrefabLibrary(path)
+	if err != nil {
+		return err
+	}
+	a.prefabs = lib
+	a.objectPalette.SetPrefabs(lib)
+	return nil
+}
+
+// SetTuning sets the player's movement tuning used in playtests.
+func (a *App) SetTuning(t game.Tuning) {
+	a.playtest.SetTuning(t)
+}
+
 // Update updates the editor state.
 // This is called every tick (typically 60 times per second).
 func (a *App) Update() error {
+	a.profiler.BeginUpdate()
+	defer func() {
+		steps := 0
+		if a.playtest != nil && a.playtest.IsActive() {
+			steps = a.playtest.timestep.StepsThisFrame()
+		}
+		a.profiler.EndUpdate(steps)
+	}()
+
 	// If playtest mode is active, delegate to playtest controller
 	if a.playtest != nil && a.playtest.IsActive() {
 		return a.playtest.Update()
 	}
 
+	// F7 toggles the profiler (in playtests it toggles the rule trace)
+	if inpututil.IsKeyJustPressed(ebiten.KeyF7) {
+		a.profiler.Toggle()
+	}
+
 	// Handle confirmation dialog input (blocks all other input)
 	if a.confirmDialog != nil {
 		if inpututil.IsKeyJustPressed(ebiten.KeyY) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
@@ -107,11 +197,37 @@ func (a *App) Update() error {
 		return nil
 	}
 
-	// Handle playtest mode toggle (P key)
+	// Handle find/replace dialog input (blocks all other input)
+	if a.findReplace.IsOpen() {
+		a.findReplace.Update(a.state)
+		return nil
+	}
+
+	// Handle overview export dialog input (blocks all other input)
+	if a.overviewDialog.IsOpen() {
+		if scale, ok := a.overviewDialog.Update(); ok {
+			a.exportOverview(a.overviewDialog.Path(), scale)
+		}
+		a.state.UpdateStatusMessage()
+		return nil
+	}
+
+	// Handle outliner input (blocks all other input)
+	if a.outliner.IsOpen() {
+		a.outliner.Update(a.state, a.camera, a.canvasWidth(), a.canvasHeight())
+		a.state.UpdateStatusMessage()
+		return nil
+	}
+
+	// Handle playtest mode toggle (P key, Shift+P plays from the cursor)
 	if inpututil.IsKeyJustPressed(ebiten.KeyP) && !a.propertiesPanel.IsEditing() {
 		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
+			if ebiten.IsKeyPressed(ebiten.KeyShift) {
+				a.playFromCursor()
+				return nil
+			}
 			if err := a.playtest.StartPlaytest(); err != nil {
-				log.Printf("Failed to start playtest: %v", err)
+				logger.Errorf("Failed to start playtest: %v", err)
 			}
 			return nil
 		}
@@ -129,8 +245,14 @@ func (a *App) Update() error {
 	// Handle keyboard shortcuts for tools and layers
 	a.handleToolShortcuts()
 
-	// Handle validation shortcuts
+	// Handle validation shortcuts, and validate again after changes
 	a.handleValidationShortcuts()
+	if a.liveValidation.Update(a.state, 1.0/60.0) {
+		a.validation = ValidateLevel(a.state)
+		a.ruleCount = levelcheck.RuleCount(a.state.FilePath)
+	}
+	onProblems := a.problems.Update(a.state, a.camera, a.validation, a.canvasWidth(), a.canvasHeight())
+	onLayers := a.layersPanel.Update(a.state, a.canvasWidth())
 
 	// Update camera controls
 	a.camera.Update()
@@ -143,9 +265,21 @@ func (a *App) Update() error {
 	// Sync property editing state so canvas can guard its shortcuts
 	a.state.IsEditingProperty = a.propertiesPanel.IsEditing()
 
+	// Handle align toolbar and shortcuts (clicks on the toolbar don't reach the canvas)
+	onToolbar := a.alignToolbar.Update(a.state)
+
+	// Handle minimap clicks and viewport dragging (they don't reach the canvas either)
+	onMinimap := a.handleMinimapInput()
+
+	// Handle status bar clicks (grid/collision toggles)
+	a.statusBar.SetSegments(a.statusSegments())
+	onStatusBar := a.statusBar.Update(a.canvasWidth(), a.canvasHeight()+StatusBarHeight)
+
 	// Update canvas with current screen size (handles grid/collision toggle, tool input, etc.)
 	a.canvas.SetScreenSize(a.screenWidth, a.screenHeight)
-	a.canvas.Update()
+	if !onToolbar && !onMinimap && !onStatusBar && !onProblems && !onLayers {
+		a.canvas.Update()
+	}
 
 	// Update cursor shape based on hover state and tool
 	a.updateCursorShape()
@@ -159,9 +293,6 @@ func (a *App) Update() error {
 	// Handle properties panel input
 	a.handlePropertiesInput()
 
-	// Handle minimap click for navigation
-	a.handleMinimapInput()
-
 	// Update status message timer
 	a.state.UpdateStatusMessage()
 
@@ -226,9 +357,9 @@ func (a *App) handlePaletteInput() {
 					a.state.SelectCollision(solid)
 					a.state.SetTool(ToolPaint)
 					if solid {
-						log.Println("Selected collision: Solid")
+						logger.Debugf("Selected collision: Solid")
 					} else {
-						log.Println("Selected collision: Empty")
+						logger.Debugf("Selected collision: Empty")
 					}
 				}
 				return
@@ -244,7 +375,7 @@ func (a *App) handlePaletteInput() {
 				if tileID >= 0 {
 					a.state.SelectTile(tileID)
 					a.state.SetTool(ToolPaint)
-					log.Printf("Selected tile ID: %d", tileID)
+					logger.Debugf("Selected tile ID: %d", tileID)
 				}
 			}
 		}
@@ -267,8 +398,22 @@ func (a *App) handleObjectPaletteInput() {
 		if a.objectPalette.IsInPalette(mx, my, screenWidth, 0) {
 			if a.objectPalette.HandleClick(mx, my, screenWidth, 0) {
 				a.state.SetTool(ToolPlaceObject)
-				log.Printf("Selected object type: %s", a.objectPalette.SelectedType())
+				logger.Debugf("Selected object type: %s", a.objectPalette.SelectedType())
+			}
+		}
+	}
+
+	// Right-click a prefab to delete it from the library
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		if i := a.objectPalette.PrefabAt(mx, my, screenWidth, 0); i >= 0 {
+			name := a.prefabs.Prefabs[i].Name
+			a.prefabs.Remove(i)
+			a.objectPalette.ClearSelection()
+			if err := a.prefabs.Save(); err != nil {
+				a.state.ShowStatusMessage(err.Error(), true)
+				return
 			}
+			a.state.ShowStatusMessage(i18n.T("editor.prefabDeleted", name), false)
 		}
 	}
 }
@@ -303,27 +448,31 @@ func (a *App) handlePropertiesInput() {
 }
 
 // handleMinimapInput handles mouse input for the minimap.
-func (a *App) handleMinimapInput() {
+// Returns true if the minimap has the mouse.
+func (a *App) handleMinimapInput() bool {
 	if a.minimap == nil {
-		return
+		return false
 	}
 
 	mx, my := ebiten.CursorPosition()
+	return a.minimap.HandleInput(mx, my, a.state, a.camera, a.canvasWidth(), a.canvasHeight())
+}
+
+// canvasWidth returns the width of the canvas, left of the palettes.
+func (a *App) canvasWidth() int {
 	screenWidth := a.screenWidth
-	screenHeight := a.screenHeight
 	if screenWidth == 0 {
 		screenWidth = 1280
 	}
-	if screenHeight == 0 {
-		screenHeight = 720
-	}
-
-	canvasWidth := screenWidth - PaletteWidth - ObjectPaletteWidth
+	return screenWidth - PaletteWidth - ObjectPaletteWidth
+}
 
-	// Check for click on minimap
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		a.minimap.HandleClick(mx, my, a.state, a.camera, canvasWidth, screenHeight)
+// canvasHeight returns the height of the canvas above the status bar.
+func (a *App) canvasHeight() int {
+	if a.screenHeight == 0 {
+		return 720 - StatusBarHeight
 	}
+	return a.screenHeight - StatusBarHeight
 }
 
 // getPropertiesPanelStartY calculates the Y position where the properties panel should start.
@@ -366,7 +515,7 @@ func (a *App) handleToolShortcuts() {
 	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyZ) {
 		if !ebiten.IsKeyPressed(ebiten.KeyShift) {
 			if a.state.History.Undo(a.state) {
-				log.Printf("Undo: %s", a.state.History.UndoDescription())
+				logger.Infof("Undo: %s", a.state.History.UndoDescription())
 			}
 			return
 		}
@@ -375,13 +524,13 @@ func (a *App) handleToolShortcuts() {
 	// Redo: Ctrl+Y or Ctrl+Shift+Z
 	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyY) {
 		if a.state.History.Redo(a.state) {
-			log.Printf("Redo: %s", a.state.History.RedoDescription())
+			logger.Infof("Redo: %s", a.state.History.RedoDescription())
 		}
 		return
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyControl) && ebiten.IsKeyPressed(ebiten.KeyShift) && inpututil.IsKeyJustPressed(ebiten.KeyZ) {
 		if a.state.History.Redo(a.state) {
-			log.Printf("Redo: %s", a.state.History.RedoDescription())
+			logger.Infof("Redo: %s", a.state.History.RedoDescription())
 		}
 		return
 	}
@@ -397,7 +546,7 @@ func (a *App) handleToolShortcuts() {
 		// Only if not pressing Ctrl (to avoid conflict with Ctrl+S)
 		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
 			a.state.SetTool(ToolSelect)
-			log.Println("Selected tool: Select")
+			logger.Debugf("Selected tool: Select")
 		}
 	}
 
@@ -405,7 +554,7 @@ func (a *App) handleToolShortcuts() {
 	if inpututil.IsKeyJustPressed(ebiten.Key2) {
 		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
 			a.state.SetTool(ToolPaint)
-			log.Println("Selected tool: Paint")
+			logger.Debugf("Selected tool: Paint")
 		}
 	}
 
@@ -413,38 +562,82 @@ func (a *App) handleToolShortcuts() {
 	if inpututil.IsKeyJustPressed(ebiten.Key3) || inpututil.IsKeyJustPressed(ebiten.KeyE) {
 		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
 			a.state.SetTool(ToolErase)
-			log.Println("Selected tool: Erase")
+			logger.Debugf("Selected tool: Erase")
 		}
 	}
 
-	// 4 or F - Fill tool
+	// 4 or F - Fill tool (Shift+F fits the level in view)
 	if inpututil.IsKeyJustPressed(ebiten.Key4) || inpututil.IsKeyJustPressed(ebiten.KeyF) {
-		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
+		if !ebiten.IsKeyPressed(ebiten.KeyControl) && !ebiten.IsKeyPressed(ebiten.KeyShift) {
 			a.state.SetTool(ToolFill)
-			log.Println("Selected tool: Fill")
+			logger.Debugf("Selected tool: Fill")
 		}
 	}
 
-	// 5 or O - Place Object tool
+	// 5 or O - Place Object tool (Shift+O places a linked switch and door)
 	if inpututil.IsKeyJustPressed(ebiten.Key5) || inpututil.IsKeyJustPressed(ebiten.KeyO) {
-		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) && inpututil.IsKeyJustPressed(ebiten.KeyO) && !ebiten.IsKeyPressed(ebiten.KeyControl) {
+			a.canvas.tools.StartLinkedPair(a.state)
+			logger.Debugf("Selected tool: Place Linked Switch+Door")
+		} else if !ebiten.IsKeyPressed(ebiten.KeyControl) {
 			a.state.SetTool(ToolPlaceObject)
-			log.Println("Selected tool: Place Object")
+			logger.Debugf("Selected tool: Place Object")
 		}
 	}
 
+	// Home or Shift+F - Fit the whole level in view
+	if inpututil.IsKeyJustPressed(ebiten.KeyHome) ||
+		(ebiten.IsKeyPressed(ebiten.KeyShift) && inpututil.IsKeyJustPressed(ebiten.KeyF) && !ebiten.IsKeyPressed(ebiten.KeyControl)) {
+		a.fitLevelInView()
+	}
+
 	// Layer selection shortcuts
 	// Tab - Cycle between layers
 	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
 		a.state.CycleLayer()
-		log.Printf("Current layer: %s", a.state.CurrentLayer)
+		logger.Debugf("Current layer: %s", a.state.CurrentLayer)
 	}
 
-	// H - Toggle current layer visibility
-	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
+	// H - Toggle current layer visibility, Shift+H - Show the layers panel
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) && ebiten.IsKeyPressed(ebiten.KeyShift) {
+		a.layersPanel.Toggle()
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyH) {
 		a.state.ToggleLayerVisibility()
 		visible := a.state.IsLayerVisible(a.state.CurrentLayer)
-		log.Printf("Layer %s visibility: %v", a.state.CurrentLayer, visible)
+		logger.Debugf("Layer %s visibility: %v", a.state.CurrentLayer, visible)
+	}
+
+	// Object layer shortcuts
+	if inpututil.IsKeyJustPressed(ebiten.KeyL) {
+		switch {
+		case ebiten.IsKeyPressed(ebiten.KeyControl):
+			// Ctrl+L - Add an object layer
+			name := a.state.AddObjectLayer()
+			a.state.ShowStatusMessage(i18n.T("editor.layerAdded", name), false)
+		case ebiten.IsKeyPressed(ebiten.KeyShift):
+			// Shift+L - Toggle active object layer visibility
+			a.state.ToggleObjectLayerVisibility()
+			layer := a.state.CurrentObjectLayer()
+			if !a.state.IsObjectLayerVisible(layer) {
+				a.clearHiddenSelection()
+			}
+			logger.Debugf("Object layer %s visibility: %v", layer, a.state.IsObjectLayerVisible(layer))
+		default:
+			// L - Cycle the active object layer
+			a.state.CycleObjectLayer()
+			a.state.ShowStatusMessage(i18n.T("editor.layer", a.state.CurrentObjectLayer()), false)
+		}
+	}
+
+	// Move selection to the active object layer: Ctrl+M
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		selection := a.state.GetSelectionManager()
+		if selection != nil && selection.HasSelection() {
+			layer := a.state.CurrentObjectLayer()
+			action := NewMoveToLayerAction(a.state, selection.SelectedIndices(), layer)
+			a.state.History.Do(action, a.state)
+			a.state.ShowStatusMessage(i18n.T("editor.movedToLayer", selection.SelectionCount(), layer), false)
+		}
 	}
 
 	// Delete/Backspace - Delete selected object(s) or hovered tile in erase mode
@@ -459,7 +652,7 @@ func (a *App) handleToolShortcuts() {
 				a.state.History.Do(action, a.state)
 				selection.ClearSelection()
 				a.state.ClearSelection()
-				log.Printf("Deleted %d objects", count)
+				logger.Infof("Deleted %d objects", count)
 			} else {
 				// Single object delete
 				obj := a.state.GetSelectedObject()
@@ -471,7 +664,7 @@ func (a *App) handleToolShortcuts() {
 				a.state.History.Do(action, a.state)
 				a.state.ClearSelection()
 				selection.ClearSelection()
-				log.Printf("Deleted selected object: %s", objType)
+				logger.Infof("Deleted selected object: %s", objType)
 			}
 		} else if a.state.CurrentTool == ToolErase {
 			// In erase mode, delete the hovered tile
@@ -480,7 +673,7 @@ func (a *App) handleToolShortcuts() {
 				// Create an erase action for the hovered tile
 				action := NewEraseTileAction(a.state, a.state.CurrentLayer, tileX, tileY)
 				a.state.History.Do(action, a.state)
-				log.Printf("Erased tile at (%d, %d)", tileX, tileY)
+				logger.Debugf("Erased tile at (%d, %d)", tileX, tileY)
 			}
 		}
 	}
@@ -492,22 +685,26 @@ func (a *App) handleToolShortcuts() {
 		} else if a.state.IsInLinkMode() {
 			// Cancel link mode
 			a.state.EndLinkMode()
-			a.state.ShowStatusMessage("Link cancelled", false)
-			log.Println("Cancelled link mode")
+			a.state.ShowStatusMessage(i18n.T("editor.linkCancelled"), false)
+			logger.Debugf("Cancelled link mode")
+		} else if a.problems.IsOpen() {
+			a.problems.Close()
+		} else if a.canvas.tools.CancelLinkedPair(a.state) {
+			a.state.ShowStatusMessage(i18n.T("editor.linkedPlacementCancelled"), false)
 		} else if a.state.HasSelection() {
 			a.state.ClearSelection()
 			selection := a.state.GetSelectionManager()
 			if selection != nil {
 				selection.ClearSelection()
 			}
-			log.Println("Cleared selection")
+			logger.Debugf("Cleared selection")
 		}
 	}
 
 	// Copy: Ctrl+C
 	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyC) {
 		if a.clipboard.Copy(a.state) {
-			log.Println("Copied selection to clipboard")
+			logger.Infof("Copied selection to clipboard")
 		}
 	}
 
@@ -526,17 +723,57 @@ func (a *App) handleToolShortcuts() {
 					a.state.SelectObject(indices[0])
 				}
 			}
-			log.Printf("Pasted %d objects from clipboard", len(indices))
+			logger.Infof("Pasted %d objects from clipboard", len(indices))
 		}
 	}
 
+	// Duplicate: Ctrl+D, offset by one grid cell
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyD) {
+		a.duplicateSelection()
+	}
+
 	// Cut: Ctrl+X
 	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyX) {
 		if a.clipboard.Cut(a.state) {
-			log.Println("Cut selection to clipboard")
+			logger.Infof("Cut selection to clipboard")
+		}
+	}
+
+	// Save selection as prefab: Ctrl+P
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		a.saveSelectionAsPrefab()
+	}
+
+	// Find/replace properties: Ctrl+F
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		a.findReplace.Open(a.state)
+	}
+
+	// Object outliner: Ctrl+E; export overview image: Ctrl+Shift+E
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			if a.state.HasLevel() {
+				a.overviewDialog.Open(a.state)
+			}
+		} else {
+			a.outliner.Open(a.state)
 		}
 	}
 
+	// Cycle level bounds policy: Ctrl+B
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyB) && a.state.MapData != nil {
+		action, next := newCycleBoundsPolicyAction(a.state)
+		a.state.History.Do(action, a.state)
+		a.state.ShowStatusMessage(i18n.T("editor.levelBounds", next), false)
+	}
+
+	// Cycle how saving stores tile data: Ctrl+U
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyU) && a.state.MapData != nil {
+		a.state.TileFormat = a.state.TileFormat.Next()
+		a.state.SetModified(true)
+		a.state.ShowStatusMessage(i18n.T("editor.tileFormat", a.state.TileFormat), false)
+	}
+
 	// Help: ? or F1
 	if inpututil.IsKeyJustPressed(ebiten.KeyF1) ||
 		(ebiten.IsKeyPressed(ebiten.KeyShift) && inpututil.IsKeyJustPressed(ebiten.KeySlash)) {
@@ -544,6 +781,90 @@ func (a *App) handleToolShortcuts() {
 	}
 }
 
+// fitLevelInView zooms and centers the canvas on the whole level.
+func (a *App) fitLevelInView() {
+	if a.state.MapData == nil {
+		return
+	}
+	levelW := float64(a.state.MapData.Width() * a.state.MapData.TileWidth())
+	levelH := float64(a.state.MapData.Height() * a.state.MapData.TileHeight())
+	a.camera.FitToLevel(levelW, levelH, a.canvasWidth(), a.canvasHeight())
+	a.state.ShowStatusMessage(i18n.T("editor.zoom", a.camera.Zoom*100), false)
+}
+
+// playFromCursor starts a playtest with the player at the mouse position.
+func (a *App) playFromCursor() {
+	mx, my := ebiten.CursorPosition()
+	if mx < 0 || mx >= a.canvasWidth() || my < 0 || my >= a.canvasHeight() {
+		a.state.ShowStatusMessage(i18n.T("editor.playFromHereHint"), true)
+		return
+	}
+	worldX, worldY := a.camera.ScreenToWorld(mx, my)
+	if err := a.playtest.StartPlaytestAt(worldX, worldY); err != nil {
+		logger.Errorf("Failed to start playtest: %v", err)
+	}
+}
+
+// duplicateSelection copies the selected objects one grid cell down and to the
+// right, and selects the copies.
+func (a *App) duplicateSelection() {
+	selection := a.state.GetSelectionManager()
+	if selection == nil || !selection.HasSelection() || a.state.MapData == nil {
+		return
+	}
+
+	dx := float64(a.state.MapData.TileWidth())
+	dy := float64(a.state.MapData.TileHeight())
+	action, indices := NewDuplicateObjectsAction(a.state, selection.SelectedIndices(), dx, dy)
+	if action == nil {
+		return
+	}
+	a.state.History.Do(action, a.state)
+
+	selection.ClearSelection()
+	for _, idx := range indices {
+		selection.AddToSelection(idx)
+	}
+	a.state.SelectObject(indices[0])
+	logger.Infof("Duplicated %d objects", len(indices))
+}
+
+// saveSelectionAsPrefab adds the selected objects to the prefab library and
+// writes it.
+func (a *App) saveSelectionAsPrefab() {
+	selection := a.state.GetSelectionManager()
+	if selection == nil || !selection.HasSelection() {
+		a.state.ShowStatusMessage(i18n.T("editor.prefabSelectFirst"), true)
+		return
+	}
+	indices := selection.SelectedIndices()
+	prefab, ok := NewPrefab("", a.state.Objects, indices)
+	if !ok {
+		return
+	}
+	prefab.Name = PrefabName(prefab.Objects)
+	name := a.prefabs.Add(prefab)
+	if err := a.prefabs.Save(); err != nil {
+		a.state.ShowStatusMessage(err.Error(), true)
+		return
+	}
+	a.state.ShowStatusMessage(i18n.T("editor.prefabSaved", name, a.prefabs.Path()), false)
+	logger.Infof("Saved %d objects as prefab %s", len(prefab.Objects), name)
+}
+
+// clearHiddenSelection deselects everything if the primary selection is on a
+// hidden object layer.
+func (a *App) clearHiddenSelection() {
+	obj := a.state.GetSelectedObject()
+	if obj == nil || a.state.IsObjectVisible(obj) {
+		return
+	}
+	a.state.ClearSelection()
+	if selection := a.state.GetSelectionManager(); selection != nil {
+		selection.ClearSelection()
+	}
+}
+
 // handleValidationShortcuts processes keyboard shortcuts for validation.
 func (a *App) handleValidationShortcuts() {
 	// Don't process single-key shortcuts while editing properties
@@ -551,9 +872,11 @@ func (a *App) handleValidationShortcuts() {
 		return
 	}
 
-	// V - Run validation
+	// V - Run validation, Shift+V - Show problems
 	if inpututil.IsKeyJustPressed(ebiten.KeyV) {
-		if !ebiten.IsKeyPressed(ebiten.KeyControl) {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) && !ebiten.IsKeyPressed(ebiten.KeyControl) {
+			a.problems.Toggle()
+		} else if !ebiten.IsKeyPressed(ebiten.KeyControl) {
 			a.runValidation()
 		}
 	}
@@ -586,23 +909,24 @@ func (a *App) updateCursorShape() {
 // runValidation validates the current level and logs the results.
 func (a *App) runValidation() {
 	a.validation = ValidateLevel(a.state)
+	a.ruleCount = levelcheck.RuleCount(a.state.FilePath)
 
 	if !a.validation.HasIssues() {
-		log.Println("Validation passed: No issues found")
+		logger.Infof("Validation passed: No issues found")
 		return
 	}
 
 	// Log all errors
 	for _, err := range a.validation.Errors {
-		log.Printf("ERROR: %s", FormatValidationError(err))
+		logger.Errorf("Validation error: %s", FormatValidationError(err))
 	}
 
 	// Log all warnings
 	for _, warn := range a.validation.Warnings {
-		log.Printf("WARNING: %s", FormatValidationError(warn))
+		logger.Warnf("Validation warning: %s", FormatValidationError(warn))
 	}
 
-	log.Printf("Validation complete: %d errors, %d warnings", a.validation.ErrorCount(), a.validation.WarningCount())
+	logger.Infof("Validation complete: %d errors, %d warnings", a.validation.ErrorCount(), a.validation.WarningCount())
 }
 
 // showConfirmDialog displays a confirmation dialog that blocks all other input.
@@ -633,10 +957,10 @@ func (a *App) doNewLevel() {
 	a.propertiesPanel.SetState(a.state)
 	a.propertiesPanel.OnStartLinkMode = func(switchIndex int) {
 		a.state.StartLinkMode(switchIndex)
-		a.state.ShowStatusMessage("Click on a door to link, or press Escape to cancel", false)
-		log.Printf("Started link mode for switch at index %d", switchIndex)
+		a.state.ShowStatusMessage(i18n.T("editor.linkHint"), false)
+		logger.Debugf("Started link mode for switch at index %d", switchIndex)
 	}
-	log.Println("Created new level")
+	logger.Infof("Created new level")
 }
 
 // openLevel opens an existing level file, prompting if there are unsaved changes.
@@ -659,48 +983,50 @@ func (a *App) doOpenLevel() {
 
 	state, err := OpenLevel(path)
 	if err != nil {
-		log.Printf("Failed to open level: %v", err)
-		a.state.ShowStatusMessage(fmt.Sprintf("Failed to open: %v", err), true)
+		logger.Errorf("Failed to open level: %v", err)
+		a.state.ShowStatusMessage(i18n.T("editor.openFailed", err), true)
 		return
 	}
 
 	a.state = state
+	a.ruleCount = levelcheck.RuleCount(a.state.FilePath)
 	a.camera.Reset()
 	a.canvas = NewCanvas(a.state, a.camera, a.tileset)
 	a.canvas.tools.SetObjectPalette(a.objectPalette)
 	a.propertiesPanel.SetState(a.state)
 	a.propertiesPanel.OnStartLinkMode = func(switchIndex int) {
 		a.state.StartLinkMode(switchIndex)
-		a.state.ShowStatusMessage("Click on a door to link, or press Escape to cancel", false)
-		log.Printf("Started link mode for switch at index %d", switchIndex)
+		a.state.ShowStatusMessage(i18n.T("editor.linkHint"), false)
+		logger.Debugf("Started link mode for switch at index %d", switchIndex)
 	}
-	log.Printf("Opened level: %s", a.state.FilePath)
-	a.state.ShowStatusMessage(fmt.Sprintf("Opened: %s", a.state.FilePath), false)
+	logger.Infof("Opened level: %s", a.state.FilePath)
+	a.state.ShowStatusMessage(i18n.T("editor.opened", a.state.FilePath), false)
 }
 
 // saveLevel saves the current level.
 func (a *App) saveLevel() {
 	if !a.state.HasLevel() {
-		log.Println("No level to save")
-		a.state.ShowStatusMessage("No level to save", true)
+		logger.Warnf("No level to save")
+		a.state.ShowStatusMessage(i18n.T("editor.nothingToSave"), true)
 		return
 	}
 
 	// Run validation before save
 	a.validation = ValidateLevel(a.state)
+	a.ruleCount = levelcheck.RuleCount(a.state.FilePath)
 
 	// Log validation issues
 	if a.validation.HasIssues() {
 		for _, err := range a.validation.Errors {
-			log.Printf("ERROR: %s", FormatValidationError(err))
+			logger.Errorf("Validation error: %s", FormatValidationError(err))
 		}
 		for _, warn := range a.validation.Warnings {
-			log.Printf("WARNING: %s", FormatValidationError(warn))
+			logger.Warnf("Validation warning: %s", FormatValidationError(warn))
 		}
 
 		// Still allow saving with warnings, but log the issues
 		if a.validation.HasErrors() {
-			log.Printf("Level has %d critical errors - consider fixing before playing", a.validation.ErrorCount())
+			logger.Warnf("Level has %d critical errors - consider fixing before playing", a.validation.ErrorCount())
 		}
 	}
 
@@ -711,20 +1037,20 @@ func (a *App) saveLevel() {
 	}
 
 	if err := SaveLevel(a.state); err != nil {
-		log.Printf("Failed to save level: %v", err)
-		a.state.ShowStatusMessage(fmt.Sprintf("Failed to save: %v", err), true)
+		logger.Errorf("Failed to save level: %v", err)
+		a.state.ShowStatusMessage(i18n.T("editor.saveFailed", err), true)
 		return
 	}
 
-	log.Printf("Saved level: %s", a.state.FilePath)
-	a.state.ShowStatusMessage(fmt.Sprintf("Saved: %s", a.state.FilePath), false)
+	logger.Infof("Saved level: %s", a.state.FilePath)
+	a.state.ShowStatusMessage(savedMessage(a.state.FilePath), false)
 }
 
 // saveLevelAs saves the current level to a new file.
 func (a *App) saveLevelAs() {
 	if !a.state.HasLevel() {
-		log.Println("No level to save")
-		a.state.ShowStatusMessage("No level to save", true)
+		logger.Warnf("No level to save")
+		a.state.ShowStatusMessage(i18n.T("editor.nothingToSave"), true)
 		return
 	}
 
@@ -736,17 +1062,33 @@ func (a *App) saveLevelAs() {
 	}
 
 	if err := SaveLevelAs(a.state, path); err != nil {
-		log.Printf("Failed to save level: %v", err)
-		a.state.ShowStatusMessage(fmt.Sprintf("Failed to save: %v", err), true)
+		logger.Errorf("Failed to save level: %v", err)
+		a.state.ShowStatusMessage(i18n.T("editor.saveFailed", err), true)
 		return
 	}
 
-	log.Printf("Saved level as: %s", a.state.FilePath)
-	a.state.ShowStatusMessage(fmt.Sprintf("Saved: %s", a.state.FilePath), false)
+	logger.Infof("Saved level as: %s", a.state.FilePath)
+	a.state.ShowStatusMessage(savedMessage(a.state.FilePath), false)
+}
+
+// exportOverview writes the level overview image at scale to path.
+func (a *App) exportOverview(path string, scale float64) {
+	w, h, err := a.canvas.ExportOverview(path, scale)
+	if err != nil {
+		logger.Errorf("Failed to export overview: %v", err)
+		a.state.ShowStatusMessage(i18n.T("editor.overviewFailed", err), true)
+		return
+	}
+	logger.Infof("Exported overview: %s (%dx%d)", path, w, h)
+	a.state.ShowStatusMessage(i18n.T("editor.overviewExported", path, w, h), false)
 }
 
 // Draw renders the editor to the screen.
 func (a *App) Draw(screen *ebiten.Image) {
+	a.profiler.BeginDraw()
+	defer a.profiler.EndDraw()
+	defer a.drawProfiler(screen)
+
 	// If playtest mode is active, delegate to playtest controller
 	if a.playtest != nil && a.playtest.IsActive() {
 		a.playtest.Draw(screen)
@@ -761,6 +1103,7 @@ func (a *App) Draw(screen *ebiten.Image) {
 
 	// Draw tilemap canvas (left portion of screen)
 	a.canvas.Draw(screen)
+	a.alignToolbar.Draw(screen, a.state)
 
 	// Draw tile palette or collision palette (right sidebar, upper portion)
 	screenWidth, screenHeight := screen.Size()
@@ -781,9 +1124,9 @@ func (a *App) Draw(screen *ebiten.Image) {
 
 	// Draw separator lines between canvas and palettes
 	separatorColor := color.RGBA{70, 70, 90, 255}
-	ebitenutil.DrawRect(screen, float64(tilePaletteX), 0, 2, float64(screenHeight), separatorColor)
+	draw.FillRect(screen, float64(tilePaletteX), 0, 2, float64(screenHeight), separatorColor)
 	objPaletteX := screenWidth - ObjectPaletteWidth
-	ebitenutil.DrawRect(screen, float64(objPaletteX), 0, 2, float64(screenHeight), separatorColor)
+	draw.FillRect(screen, float64(objPaletteX), 0, 2, float64(screenHeight), separatorColor)
 
 	// Draw minimap
 	a.drawMinimap(screen)
@@ -799,21 +1142,24 @@ func (a *App) Draw(screen *ebiten.Image) {
 		a.drawHelpOverlay(screen)
 	}
 
+	// Draw problems list and outliner if open
+	a.problems.Draw(screen, a.state, a.validation, a.canvasHeight())
+	a.layersPanel.Draw(screen, a.state, a.canvasWidth())
+	a.outliner.Draw(screen, a.state)
+	a.overviewDialog.Draw(screen)
+
+	// Draw find/replace dialog if open
+	a.findReplace.Draw(screen, a.state)
+
 	// Draw confirmation dialog if active (last thing drawn, on top of everything)
 	if a.confirmDialog != nil {
 		a.drawConfirmDialog(screen)
 	}
 }
 
-// drawStatusBar draws a simple status bar at the bottom of the screen.
+// drawStatusBar draws the status bar at the bottom of the canvas and puts
+// the file name in the window title.
 func (a *App) drawStatusBar(screen *ebiten.Image) {
-	// For now, just display basic info using the window title
-	// A proper text rendering system will be added later
-	width, height := screen.Size()
-	_ = width
-	_ = height
-
-	// Update window title with status
 	title := "GoP Level Editor"
 	if a.state.HasLevel() {
 		if a.state.FilePath != "" {
@@ -824,44 +1170,109 @@ func (a *App) drawStatusBar(screen *ebiten.Image) {
 		if a.state.IsModified() {
 			title += " *"
 		}
+	}
+	ebiten.SetWindowTitle(title)
 
-		// Add current tool
-		toolName := a.getToolName(a.state.CurrentTool)
-		title += fmt.Sprintf(" | Tool: %s", toolName)
+	a.statusBar.Draw(screen)
+}
 
-		// Add current layer with visibility indicator
-		layerVisible := a.state.IsLayerVisible(a.state.CurrentLayer)
-		visibility := "visible"
-		if !layerVisible {
-			visibility = "hidden"
-		}
-		title += fmt.Sprintf(" | Layer: %s (%s)", a.state.CurrentLayer, visibility)
+// statusSegments returns the fields of the status bar.
+func (a *App) statusSegments() []StatusSegment {
+	if !a.state.HasLevel() {
+		return nil
+	}
 
-		// Add grid and collision overlay status
-		title += fmt.Sprintf(" | Grid: %v, Collision: %v", a.canvas.ShowGrid(), a.canvas.ShowCollision())
+	// Cursor position, when over the canvas
+	cursor := "Tile -, -"
+	mx, my := ebiten.CursorPosition()
+	if mx >= 0 && mx < a.canvasWidth() && my >= 0 && my < a.canvasHeight() {
+		wx, wy := a.camera.ScreenToWorld(mx, my)
+		tx, ty := a.canvas.ScreenToTile(mx, my)
+		cursor = fmt.Sprintf("Tile %d, %d  World %.0f, %.0f", tx, ty, wx, wy)
+	}
 
-		// Add selected tile info
-		if a.state.SelectedTile >= 0 {
-			title += fmt.Sprintf(" | Tile: %d", a.state.SelectedTile)
+	layer := a.state.CurrentLayer
+	if a.state.MapData != nil {
+		if tileLayer := a.state.MapData.Layer(layer); tileLayer != nil {
+			if style := layerStyleSummary(tileLayer); style != "" {
+				layer += " " + style
+			}
 		}
+	}
+	if !a.state.IsLayerVisible(a.state.CurrentLayer) {
+		layer += " (hidden)"
+	}
+	objectLayer := a.state.CurrentObjectLayer()
+	if !a.state.IsObjectLayerVisible(objectLayer) {
+		objectLayer += " (hidden)"
+	}
+
+	selected := 0
+	if selection := a.state.GetSelectionManager(); selection != nil {
+		selected = selection.SelectionCount()
+	}
+
+	segments := []StatusSegment{
+		{Text: cursor},
+		{Text: "Tool: " + a.getToolName(a.state.CurrentTool)},
+		{Text: "Layer: " + layer, OnClick: a.layersPanel.Toggle},
+		{Text: "Objects: " + objectLayer},
+		{Text: i18n.T("editor.zoom", a.camera.Zoom*100)},
+		{Text: fmt.Sprintf("Selected: %d", selected)},
+		{Text: "Grid: " + onOff(a.canvas.ShowGrid()), OnClick: func() {
+			a.canvas.SetShowGrid(!a.canvas.ShowGrid())
+		}},
+		{Text: "Collision: " + onOff(a.canvas.ShowCollision()), OnClick: func() {
+			a.canvas.SetShowCollision(!a.canvas.ShowCollision())
+		}},
+	}
+	if onion := a.canvas.OnionSkin(); onion != "" {
+		segments = append(segments, StatusSegment{Text: "Onion: " + onion, OnClick: a.canvas.SetOnionSkinOff})
+	}
+	if ts := a.tileset.Tileset(); ts != nil && ts.HasAnimations() {
+		segments = append(segments, StatusSegment{Text: "Tile Anim: " + onOff(a.canvas.AnimateTiles()), OnClick: func() {
+			a.canvas.SetAnimateTiles(!a.canvas.AnimateTiles())
+		}})
+	}
+
+	// Validation summary (click to show the problems)
+	showProblems := func() { a.problems.Toggle() }
+	switch {
+	case a.validation == nil:
+	case a.validation.ErrorCount() > 0:
+		segments = append(segments, StatusSegment{
+			Text:    fmt.Sprintf("%d errors, %d warnings", a.validation.ErrorCount(), a.validation.WarningCount()),
+			Color:   statusErrorColor,
+			OnClick: showProblems,
+		})
+	case a.validation.WarningCount() > 0:
+		segments = append(segments, StatusSegment{
+			Text:    fmt.Sprintf("%d warnings", a.validation.WarningCount()),
+			Color:   statusWarningColor,
+			OnClick: showProblems,
+		})
+	default:
+		segments = append(segments, StatusSegment{Text: "Valid", OnClick: showProblems})
+	}
 
-		// Add validation status
-		if a.validation != nil && a.validation.HasIssues() {
-			title += fmt.Sprintf(" | Issues: %d errors, %d warnings", a.validation.ErrorCount(), a.validation.WarningCount())
-		}
+	// Live budget counter
+	budget, usage := levelcheck.Budget(levelForState(a.state, a.ruleCount))
+	segments = append(segments, StatusSegment{Text: budget.Summary(usage)})
 
-		// Add undo/redo status
-		if a.state.History.CanUndo() {
-			title += fmt.Sprintf(" | Undo: %s", a.state.History.UndoDescription())
-		}
-		if a.state.History.CanRedo() {
-			title += fmt.Sprintf(" | Redo: %s", a.state.History.RedoDescription())
-		}
+	// Last undoable action
+	if a.state.History.CanUndo() {
+		segments = append(segments, StatusSegment{Text: "Undo: " + a.state.History.UndoDescription()})
+	}
+
+	return segments
+}
 
-		// Add playtest hint
-		title += " | P: Playtest"
+// onOff formats a toggle state for the status bar.
+func onOff(on bool) string {
+	if on {
+		return "on"
 	}
-	ebiten.SetWindowTitle(title)
+	return "off"
 }
 
 // getToolName returns a human-readable name for a tool.
@@ -942,30 +1353,6 @@ func (a *App) ScreenSize() (width, height int) {
 func (a *App) drawHelpOverlay(screen *ebiten.Image) {
 	screenWidth, screenHeight := screen.Size()
 
-	// Semi-transparent background
-	overlayWidth := 400
-	overlayHeight := 450
-	overlayX := (screenWidth - overlayWidth) / 2
-	overlayY := (screenHeight - overlayHeight) / 2
-
-	// Draw background
-	overlayImg := ebiten.NewImage(overlayWidth, overlayHeight)
-	overlayImg.Fill(color.RGBA{40, 40, 50, 240})
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(overlayX), float64(overlayY))
-	screen.DrawImage(overlayImg, op)
-
-	// Draw border
-	borderColor := color.RGBA{100, 100, 120, 255}
-	ebitenutil.DrawRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), 2, borderColor)
-	ebitenutil.DrawRect(screen, float64(overlayX), float64(overlayY+overlayHeight-2), float64(overlayWidth), 2, borderColor)
-	ebitenutil.DrawRect(screen, float64(overlayX), float64(overlayY), 2, float64(overlayHeight), borderColor)
-	ebitenutil.DrawRect(screen, float64(overlayX+overlayWidth-2), float64(overlayY), 2, float64(overlayHeight), borderColor)
-
-	// Title
-	titleY := overlayY + 15
-	ebitenutil.DebugPrintAt(screen, "KEYBOARD SHORTCUTS", overlayX+120, titleY)
-
 	// Shortcuts list
 	shortcuts := []struct {
 		key    string
@@ -976,47 +1363,110 @@ func (a *App) drawHelpOverlay(screen *ebiten.Image) {
 		{"Ctrl+O", "Open Level"},
 		{"Ctrl+S", "Save Level"},
 		{"Ctrl+Shift+S", "Save As"},
+		{"Ctrl+U", "Cycle Tile Data Format"},
 		{"--- Tools ---", ""},
 		{"1 / S", "Select Tool"},
 		{"2", "Paint Tool"},
 		{"3 / E", "Erase Tool"},
 		{"4 / F", "Fill Tool"},
 		{"5 / O", "Place Object Tool"},
+		{"Shift+O", "Place Linked Switch+Door"},
 		{"--- Selection ---", ""},
 		{"Shift+Click", "Add to Selection"},
+		{"Drag Empty Space", "Box Select (+Shift: Add)"},
 		{"Ctrl+C", "Copy"},
 		{"Ctrl+V", "Paste"},
 		{"Ctrl+X", "Cut"},
+		{"Ctrl+D", "Duplicate"},
+		{"Ctrl+P", "Save Selection as Prefab"},
+		{"Right-click Prefab", "Delete Prefab"},
+		{"Alt+Drag", "Drag Out a Copy"},
+		{"Alt+Arrows", "Align Edges"},
+		{"Alt+M / +Shift", "Align Centers H / V"},
+		{"Alt+D / +Shift", "Distribute H / V"},
+		{"Alt+R", "Snap Selection to Grid"},
 		{"Del/Backspace", "Delete Selected"},
 		{"Escape", "Clear Selection"},
 		{"--- View ---", ""},
 		{"G", "Toggle Grid"},
 		{"C", "Toggle Collision"},
+		{"K", "Show All Switch Links"},
+		{"A", "Play Tile Animations"},
+		{"J", "Onion Skin: Other Layer / Saved"},
+		{"Home / Shift+F", "Fit Level in View"},
+		{"Wheel", "Zoom at Cursor"},
+		{"Middle/Space+Drag", "Pan"},
 		{"H", "Toggle Layer Visibility"},
+		{"Shift+H", "Layers: Opacity / Tint"},
 		{"Tab", "Cycle Layers"},
+		{"L", "Cycle Object Layers"},
+		{"Shift+L", "Toggle Object Layer Visibility"},
+		{"Ctrl+L", "Add Object Layer"},
+		{"Ctrl+M", "Move Selection to Object Layer"},
 		{"--- Other ---", ""},
 		{"P", "Playtest Mode"},
+		{"Shift+P", "Playtest From Cursor"},
+		{"T", "Toggle Last Playtest Path"},
 		{"V", "Validate Level"},
+		{"Shift+V", "Problems List"},
+		{"Ctrl+F", "Find/Replace Properties"},
+		{"Ctrl+E", "Object Outliner / Search"},
+		{"Ctrl+Shift+E", "Export Overview Image"},
+		{"Ctrl+B", "Cycle Level Bounds Policy"},
 		{"Ctrl+Z", "Undo"},
 		{"Ctrl+Y", "Redo"},
+		{"F7", "Toggle Profiler"},
 		{"F1 / ?", "Toggle This Help"},
 	}
 
-	y := titleY + 25
-	for _, s := range shortcuts {
+	// Lay the list out in two columns, breaking at the first section
+	// header past the middle
+	split := len(shortcuts)
+	for i := len(shortcuts) / 2; i < len(shortcuts); i++ {
+		if shortcuts[i].action == "" {
+			split = i
+			break
+		}
+	}
+	rows := max(split, len(shortcuts)-split)
+
+	// Semi-transparent background
+	const columnWidth = 380
+	overlayWidth := 2*columnWidth + 20
+	overlayHeight := 80 + rows*14
+	overlayX := (screenWidth - overlayWidth) / 2
+	overlayY := (screenHeight - overlayHeight) / 2
+
+	// Draw background
+	draw.FillRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), float64(overlayHeight), color.RGBA{40, 40, 50, 240})
+
+	// Draw border
+	borderColor := color.RGBA{100, 100, 120, 255}
+	draw.StrokeRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), float64(overlayHeight), 2, borderColor)
+
+	// Title
+	titleY := overlayY + 15
+	ebitenutil.DebugPrintAt(screen, "KEYBOARD SHORTCUTS", overlayX+overlayWidth/2-54, titleY)
+
+	for i, s := range shortcuts {
+		x := overlayX + 20
+		y := titleY + 25 + i*14
+		if i >= split {
+			x += columnWidth
+			y = titleY + 25 + (i-split)*14
+		}
 		if s.action == "" {
 			// Section header
-			ebitenutil.DebugPrintAt(screen, s.key, overlayX+20, y)
+			ebitenutil.DebugPrintAt(screen, s.key, x, y)
 		} else {
 			// Shortcut entry
-			ebitenutil.DebugPrintAt(screen, s.key, overlayX+20, y)
-			ebitenutil.DebugPrintAt(screen, s.action, overlayX+140, y)
+			ebitenutil.DebugPrintAt(screen, s.key, x, y)
+			ebitenutil.DebugPrintAt(screen, s.action, x+120, y)
 		}
-		y += 14
 	}
 
 	// Close hint
-	ebitenutil.DebugPrintAt(screen, "Press F1 or ? to close", overlayX+120, overlayY+overlayHeight-25)
+	ebitenutil.DebugPrintAt(screen, "Press F1 or ? to close", overlayX+overlayWidth/2-66, overlayY+overlayHeight-25)
 }
 
 // drawConfirmDialog draws a centered confirmation dialog overlay.
@@ -1029,18 +1479,11 @@ func (a *App) drawConfirmDialog(screen *ebiten.Image) {
 	overlayY := (screenHeight - overlayHeight) / 2
 
 	// Draw background
-	overlayImg := ebiten.NewImage(overlayWidth, overlayHeight)
-	overlayImg.Fill(color.RGBA{40, 40, 50, 240})
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(overlayX), float64(overlayY))
-	screen.DrawImage(overlayImg, op)
+	draw.FillRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), float64(overlayHeight), color.RGBA{40, 40, 50, 240})
 
 	// Draw border
 	borderColor := color.RGBA{200, 160, 60, 255}
-	ebitenutil.DrawRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), 2, borderColor)
-	ebitenutil.DrawRect(screen, float64(overlayX), float64(overlayY+overlayHeight-2), float64(overlayWidth), 2, borderColor)
-	ebitenutil.DrawRect(screen, float64(overlayX), float64(overlayY), 2, float64(overlayHeight), borderColor)
-	ebitenutil.DrawRect(screen, float64(overlayX+overlayWidth-2), float64(overlayY), 2, float64(overlayHeight), borderColor)
+	draw.StrokeRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), float64(overlayHeight), 2, borderColor)
 
 	// Draw message
 	ebitenutil.DebugPrintAt(screen, a.confirmDialog.Message, overlayX+20, overlayY+20)
@@ -1049,10 +1492,32 @@ func (a *App) drawConfirmDialog(screen *ebiten.Image) {
 	ebitenutil.DebugPrintAt(screen, "Y / Enter: Yes    N / Escape: No", overlayX+40, overlayY+50)
 }
 
+// drawProfiler draws the performance overlay with the frame's counts: in
+// the top-right corner of the canvas, or of the screen during playtests.
+func (a *App) drawProfiler(screen *ebiten.Image) {
+	if !a.profiler.Visible() {
+		return
+	}
+	if a.playtest != nil && a.playtest.IsActive() {
+		a.profiler.SetStats(a.playtest.ProfilerStats())
+		a.profiler.Draw(screen)
+		return
+	}
+
+	objects := len(a.state.Objects)
+	chunks := a.canvas.MapDrawCalls()
+	a.profiler.SetStats([]debugui.Stat{
+		{Name: "objects", Value: objects},
+		{Name: "map chunks", Value: chunks},
+		{Name: "draw calls ~", Value: chunks + objects},
+	})
+	a.profiler.DrawAt(screen, a.canvasWidth()-debugui.ProfilerWidth-6, 6)
+}
+
 // drawMinimap draws a small overview of the level in the corner.
 func (a *App) drawMinimap(screen *ebiten.Image) {
 	if a.minimap != nil {
-		a.minimap.Draw(screen, a.state, a.camera)
+		a.minimap.Draw(screen, a.state, a.camera, a.tileset)
 	}
 }
 
@@ -1078,18 +1543,11 @@ func (a *App) drawStatusMessage(screen *ebiten.Image) {
 	}
 
 	// Draw background
-	msgImg := ebiten.NewImage(msgWidth, msgHeight)
-	msgImg.Fill(bgColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(msgX), float64(msgY))
-	screen.DrawImage(msgImg, op)
+	draw.FillRect(screen, float64(msgX), float64(msgY), float64(msgWidth), float64(msgHeight), bgColor)
 
 	// Draw border
 	borderColor := color.RGBA{255, 255, 255, 200}
-	ebitenutil.DrawRect(screen, float64(msgX), float64(msgY), float64(msgWidth), 2, borderColor)
-	ebitenutil.DrawRect(screen, float64(msgX), float64(msgY+msgHeight-2), float64(msgWidth), 2, borderColor)
-	ebitenutil.DrawRect(screen, float64(msgX), float64(msgY), 2, float64(msgHeight), borderColor)
-	ebitenutil.DrawRect(screen, float64(msgX+msgWidth-2), float64(msgY), 2, float64(msgHeight), borderColor)
+	draw.StrokeRect(screen, float64(msgX), float64(msgY), float64(msgWidth), float64(msgHeight), 2, borderColor)
 
 	// Draw text
 	textX := msgX + 10
@@ -0,0 +1,81 @@
+//go:build display
+
+package editor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+// stressState returns the editor state of a generated width x height level
+// with objects objects, and its JSON.
+func stressState(b *testing.B, width, height, objects int) (*EditorState, []byte) {
+	b.Helper()
+	m, err := tiled.StressMap(width, height, objects, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := m.EncodeJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+	state, err := ParseLevel(data, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return state, data
+}
+
+func BenchmarkParseLevel1000x1000(b *testing.B) {
+	_, data := stressState(b, 1000, 1000, 0)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseLevel(data, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSaveLevel1000x1000(b *testing.B) {
+	state, _ := stressState(b, 1000, 1000, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// SaveLevelAs without writing the file
+		tiledJSON, err := editorStateToTiledJSON(state)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := json.MarshalIndent(tiledJSON, "", "  "); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCanvasVisibleTiles(b *testing.B) {
+	state, _ := stressState(b, 1000, 1000, 0)
+	camera := NewCamera()
+	canvas := NewCanvas(state, camera, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		camera.X, camera.Y = float64(i*7%16000), float64(i*13%16000)
+		camera.Zoom = 0.25 + float64(i%8)*0.25
+		canvas.visibleTiles(1280, 720)
+	}
+}
+
+func BenchmarkCanvasDrawObjects10000(b *testing.B) {
+	state, _ := stressState(b, 400, 100, 10000)
+	camera := NewCamera()
+	canvas := NewCanvas(state, camera, nil)
+	screen := ebiten.NewImage(1280, 720)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Scrolling over the level: most objects are culled
+		camera.X = float64(i * 16 % (400*16 - 1280))
+		canvas.drawObjects(screen, 1280)
+	}
+}
@@ -1,7 +1,10 @@
 package editor
 
 import (
+	"math"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/world"
 )
 
 const (
@@ -13,8 +16,13 @@ const (
 	MinZoom = 0.25
 	// MaxZoom is the maximum zoom level.
 	MaxZoom = 4.0
+	// fitMargin is the share of the view a fitted level fills.
+	fitMargin = 0.95
 )
 
+// DefaultZoomSteps are the zoom factors the mouse wheel snaps to.
+var DefaultZoomSteps = []float64{0.25, 1.0 / 3, 0.5, 2.0 / 3, 1, 1.5, 2, 3, 4}
+
 // Camera handles the viewport transformation for the editor canvas.
 type Camera struct {
 	X    float64 // Camera position in world coordinates
@@ -34,6 +42,11 @@ type Camera struct {
 	DragStartY    float64
 	DragStartCamX float64
 	DragStartCamY float64
+
+	// Zoom range and the steps the mouse wheel snaps to (none for smooth zoom)
+	minZoom, maxZoom float64
+	zoomSteps        []float64
+	wheel            float64 // Wheel movement not yet turned into a zoom step
 }
 
 // NewCamera creates a new camera with default values.
@@ -43,25 +56,45 @@ func NewCamera() *Camera {
 		Y:         0,
 		Zoom:      1.0,
 		isPanning: false,
+		minZoom:   MinZoom,
+		maxZoom:   MaxZoom,
+		zoomSteps: DefaultZoomSteps,
 	}
 }
 
-// Update processes input for camera control.
-// Pan with middle mouse button or arrow keys.
-// Zoom with mouse wheel.
-func (c *Camera) Update() {
-	// Handle keyboard panning
-	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
-		c.X -= CameraPanSpeed / c.Zoom
+// SetZoomLimits sets the zoom range and the wheel zoom steps. Zero limits and
+// nil steps keep the current ones.
+func (c *Camera) SetZoomLimits(minZoom, maxZoom float64, steps []float64) {
+	if minZoom > 0 {
+		c.minZoom = minZoom
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
-		c.X += CameraPanSpeed / c.Zoom
+	if maxZoom > 0 {
+		c.maxZoom = maxZoom
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
-		c.Y -= CameraPanSpeed / c.Zoom
+	if steps != nil {
+		c.zoomSteps = steps
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
-		c.Y += CameraPanSpeed / c.Zoom
+	c.SetZoom(c.Zoom)
+}
+
+// Update processes input for camera control.
+// Pan with middle mouse button or arrow keys, zoom with the mouse wheel.
+// Zoom with mouse wheel.
+func (c *Camera) Update() {
+	// Handle keyboard panning (Alt+arrows align objects instead)
+	if !ebiten.IsKeyPressed(ebiten.KeyAlt) {
+		if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+			c.X -= CameraPanSpeed / c.Zoom
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+			c.X += CameraPanSpeed / c.Zoom
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+			c.Y -= CameraPanSpeed / c.Zoom
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+			c.Y += CameraPanSpeed / c.Zoom
+		}
 	}
 
 	// Handle middle mouse button panning
@@ -83,27 +116,73 @@ func (c *Camera) Update() {
 		c.isPanning = false
 	}
 
-	// Handle zoom with mouse wheel
+	// Handle zoom with mouse wheel, keeping the point under the cursor in place
 	_, wheelY := ebiten.Wheel()
 	if wheelY != 0 {
-		// Get mouse position before zoom for zoom-at-cursor
 		mx, my := ebiten.CursorPosition()
-		worldX, worldY := c.ScreenToWorld(mx, my)
+		if len(c.zoomSteps) == 0 {
+			c.ZoomAt(c.Zoom+wheelY*ZoomSpeed, mx, my)
+		} else {
+			// Step once per wheel notch, so touchpads don't skip steps
+			c.wheel += wheelY
+			if math.Abs(c.wheel) >= 1 {
+				c.ZoomAt(c.nextZoomStep(c.wheel > 0), mx, my)
+				c.wheel = 0
+			}
+		}
+	}
+}
 
-		// Apply zoom
-		c.Zoom += wheelY * ZoomSpeed
-		if c.Zoom < MinZoom {
-			c.Zoom = MinZoom
+// ZoomAt changes the zoom level, keeping the world point under the screen
+// position in place.
+func (c *Camera) ZoomAt(zoom float64, screenX, screenY int) {
+	worldX, worldY := c.ScreenToWorld(screenX, screenY)
+	c.SetZoom(zoom)
+	newWorldX, newWorldY := c.ScreenToWorld(screenX, screenY)
+	c.X += worldX - newWorldX
+	c.Y += worldY - newWorldY
+}
+
+// nextZoomStep returns the next zoom step in or out from the current zoom.
+// Past the last step it returns the zoom limit.
+func (c *Camera) nextZoomStep(in bool) float64 {
+	const eps = 1e-6
+	if in {
+		for _, z := range c.zoomSteps {
+			if z > c.Zoom+eps {
+				return z
+			}
 		}
-		if c.Zoom > MaxZoom {
-			c.Zoom = MaxZoom
+		return c.maxZoom
+	}
+	for i := len(c.zoomSteps) - 1; i >= 0; i-- {
+		if z := c.zoomSteps[i]; z < c.Zoom-eps {
+			return z
 		}
+	}
+	return c.minZoom
+}
 
-		// Adjust camera to zoom at cursor position
-		newWorldX, newWorldY := c.ScreenToWorld(mx, my)
-		c.X += worldX - newWorldX
-		c.Y += worldY - newWorldY
+// FitToLevel zooms and centers the camera so a level of the given world size
+// fits in a view of the given screen size. The zoom snaps down to a zoom step
+// so tiles stay crisp.
+func (c *Camera) FitToLevel(levelW, levelH float64, viewW, viewH int) {
+	if levelW <= 0 || levelH <= 0 || viewW <= 0 || viewH <= 0 {
+		return
 	}
+
+	fit := math.Min(float64(viewW)/levelW, float64(viewH)/levelH) * fitMargin
+	zoom := fit
+	for i := len(c.zoomSteps) - 1; i >= 0; i-- {
+		if c.zoomSteps[i] <= fit {
+			zoom = c.zoomSteps[i]
+			break
+		}
+	}
+	c.SetZoom(zoom)
+
+	c.X = levelW/2 - float64(viewW)/2/c.Zoom
+	c.Y = levelH/2 - float64(viewH)/2/c.Zoom
 }
 
 // ScreenToWorld converts screen coordinates to world coordinates.
@@ -123,8 +202,8 @@ func (c *Camera) WorldToScreen(worldX, worldY float64) (screenX, screenY int) {
 // ScreenToWorldTile converts screen coordinates to tile coordinates.
 func (c *Camera) ScreenToWorldTile(screenX, screenY, tileWidth, tileHeight int) (tileX, tileY int) {
 	worldX, worldY := c.ScreenToWorld(screenX, screenY)
-	tileX = int(worldX) / tileWidth
-	tileY = int(worldY) / tileHeight
+	tileX = world.TileIndex(worldX, tileWidth)
+	tileY = world.TileIndex(worldY, tileHeight)
 	return
 }
 
@@ -134,13 +213,13 @@ func (c *Camera) SetPosition(x, y float64) {
 	c.Y = y
 }
 
-// SetZoom sets the camera zoom level.
+// SetZoom sets the camera zoom level, clamped to the zoom limits.
 func (c *Camera) SetZoom(zoom float64) {
-	if zoom < MinZoom {
-		zoom = MinZoom
+	if zoom < c.minZoom {
+		zoom = c.minZoom
 	}
-	if zoom > MaxZoom {
-		zoom = MaxZoom
+	if zoom > c.maxZoom {
+		zoom = c.maxZoom
 	}
 	c.Zoom = zoom
 }
@@ -4,10 +4,15 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/sound"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -20,16 +25,21 @@ type Canvas struct {
 	showGrid      bool
 	showCollision bool
 	mousePressed  bool
-	hoverHandle   HandlePosition    // Current handle being hovered
-	validation    *ValidationResult // Current validation result
-	hoveredTileX  int               // Currently hovered tile X coordinate
-	hoveredTileY  int               // Currently hovered tile Y coordinate
-	screenWidth   int               // Current screen width (set from App.Layout)
-	screenHeight  int               // Current screen height (set from App.Layout)
-	cachedPixel       *ebiten.Image // 1x1 pixel for grid lines
-	cachedOverlayTile *ebiten.Image // tileW x tileH for collision overlay
-	cachedOverlayW    int           // cached overlay width for invalidation
-	cachedOverlayH    int           // cached overlay height for invalidation
+	hoverHandle   HandlePosition     // Current handle being hovered
+	validation    *ValidationResult  // Current validation result
+	hoveredTileX  int                // Currently hovered tile X coordinate
+	hoveredTileY  int                // Currently hovered tile Y coordinate
+	screenWidth   int                // Current screen width (set from App.Layout)
+	screenHeight  int                // Current screen height (set from App.Layout)
+	recording     *PlaytestRecording // Path recorded in the last playtest
+	showRecording bool               // Draw the recorded path (T)
+	showAllLinks  bool               // Draw every switch link, not just the selected ones (K)
+	animateTiles  bool               // Play tile animations; off shows their first frame (A)
+	onion         onionSkin          // Translucent second layer under the active one (J)
+	chunks        *world.ChunkCache  // Cached tile layer chunks
+	chunksFor     *world.MapData     // Map the chunk cache was built for
+	mapDrawCalls  int                // Tile layer draw calls last frame, for the profiler
+	overview      bool               // Rendering an overview export: no selection shown
 }
 
 // NewCanvas creates a new canvas for rendering the tilemap.
@@ -45,7 +55,6 @@ func NewCanvas(state *EditorState, camera *Camera, tileset *Tileset) *Canvas {
 		validation:    nil,
 		hoveredTileX:  -1,
 		hoveredTileY:  -1,
-		cachedPixel:   ebiten.NewImage(1, 1),
 	}
 	// Set the state reference for tools that need it
 	c.tools.SetState(state)
@@ -81,14 +90,25 @@ func (c *Canvas) Draw(screen *ebiten.Image) {
 		c.drawCollisionOverlay(screen, canvasWidth)
 	}
 
+	// Draw level bounds and kill line
+	c.drawLevelBounds(screen, canvasWidth)
+
 	// Draw objects
 	c.drawObjects(screen, canvasWidth)
 
+	// Draw the last playtest's path, jumps and deaths
+	if c.showRecording && c.recording != nil {
+		c.recording.Draw(screen, c.camera)
+	}
+
 	// Draw grid overlay
 	if c.showGrid {
 		c.drawGrid(screen, canvasWidth, screenHeight)
 	}
 
+	// Draw box selection band
+	c.drawBoxSelect(screen)
+
 	// Draw tool preview
 	c.drawToolPreview(screen, canvasWidth)
 
@@ -101,42 +121,28 @@ func (c *Canvas) Draw(screen *ebiten.Image) {
 	}
 }
 
-// drawTileLayers renders all visible tile layers.
+// drawTileLayers renders all visible tile layers through the chunk cache,
+// which is rebuilt when another level is loaded.
 func (c *Canvas) drawTileLayers(screen *ebiten.Image, canvasWidth int) {
-	if c.state.MapData == nil || c.tileset == nil || !c.tileset.IsLoaded() {
+	c.mapDrawCalls = 0
+	md := c.state.MapData
+	if md == nil || c.tileset == nil || !c.tileset.IsLoaded() {
 		return
 	}
 
-	tileW := c.state.MapData.TileWidth()
-	tileH := c.state.MapData.TileHeight()
-
-	// Calculate visible tile range based on camera
-	camX := c.camera.X
-	camY := c.camera.Y
-	zoom := c.camera.Zoom
-
-	// Calculate visible tiles
-	tx1 := int(camX) / tileW
-	ty1 := int(camY) / tileH
-	tx2 := (int(camX) + int(float64(canvasWidth)/zoom) + tileW) / tileW
-	ty2 := (int(camY) + int(float64(screen.Bounds().Dy())/zoom) + tileH) / tileH
-
-	// Clamp to map bounds
-	if tx1 < 0 {
-		tx1 = 0
-	}
-	if ty1 < 0 {
-		ty1 = 0
-	}
-	if tx2 > c.state.MapData.Width() {
-		tx2 = c.state.MapData.Width()
-	}
-	if ty2 > c.state.MapData.Height() {
-		ty2 = c.state.MapData.Height()
+	if c.chunks == nil || c.chunksFor != md {
+		c.chunks = world.NewChunkCache(c.tileset.Tileset(), md.TileWidth(), md.TileHeight())
+		c.chunksFor = md
 	}
+	c.chunks.BeginFrame()
 
-	// Draw each layer
-	for _, layer := range c.state.MapData.Layers() {
+	// Draw each layer, with the onion skin right under the active one
+	onionDrawn := false
+	for _, layer := range md.Layers() {
+		if layer.Name() == c.state.CurrentLayer {
+			c.drawOnionSkin(screen, canvasWidth)
+			onionDrawn = true
+		}
 		// Skip collision layer for normal rendering
 		if layer.Name() == "Collision" {
 			continue
@@ -145,39 +151,18 @@ func (c *Canvas) drawTileLayers(screen *ebiten.Image, canvasWidth int) {
 		if !c.state.IsLayerVisible(layer.Name()) {
 			continue
 		}
-		c.drawLayer(screen, layer, tx1, ty1, tx2, ty2, tileW, tileH, camX, camY, zoom)
+		c.chunks.DrawLayer(screen, layer, c.camera.X, c.camera.Y, c.camera.Zoom, canvasWidth, screen.Bounds().Dy())
 	}
+	if !onionDrawn {
+		c.drawOnionSkin(screen, canvasWidth)
+	}
+	c.mapDrawCalls = c.chunks.DrawCalls()
 }
 
-// drawLayer renders a single tile layer.
-func (c *Canvas) drawLayer(screen *ebiten.Image, layer *world.TileLayer, tx1, ty1, tx2, ty2, tileW, tileH int, camX, camY, zoom float64) {
-	for ty := ty1; ty < ty2; ty++ {
-		for tx := tx1; tx < tx2; tx++ {
-			tileID := layer.TileAt(tx, ty)
-			if tileID == 0 {
-				continue // Empty tile
-			}
-
-			// Tiled uses 1-based IDs, convert to 0-based
-			tile := c.tileset.Tile(tileID - 1)
-			if tile == nil {
-				continue
-			}
-
-			// Calculate screen position with camera transform
-			worldX := float64(tx * tileW)
-			worldY := float64(ty * tileH)
-			screenX := (worldX - camX) * zoom
-			screenY := (worldY - camY) * zoom
-
-			// Draw tile
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Scale(zoom, zoom)
-			op.GeoM.Translate(screenX, screenY)
-			op.Filter = ebiten.FilterNearest
-			screen.DrawImage(tile, op)
-		}
-	}
+// MapDrawCalls returns the number of DrawImage calls the tile layers took in
+// the last frame, one per visible non-empty chunk.
+func (c *Canvas) MapDrawCalls() int {
+	return c.mapDrawCalls
 }
 
 // drawCollisionOverlay renders the collision layer as a semi-transparent overlay.
@@ -202,34 +187,7 @@ func (c *Canvas) drawCollisionOverlay(screen *ebiten.Image, canvasWidth int) {
 	camY := c.camera.Y
 	zoom := c.camera.Zoom
 
-	// Calculate visible tiles
-	tx1 := int(camX) / tileW
-	ty1 := int(camY) / tileH
-	tx2 := (int(camX) + int(float64(canvasWidth)/zoom) + tileW) / tileW
-	ty2 := (int(camY) + int(float64(screen.Bounds().Dy())/zoom) + tileH) / tileH
-
-	// Clamp to map bounds
-	if tx1 < 0 {
-		tx1 = 0
-	}
-	if ty1 < 0 {
-		ty1 = 0
-	}
-	if tx2 > c.state.MapData.Width() {
-		tx2 = c.state.MapData.Width()
-	}
-	if ty2 > c.state.MapData.Height() {
-		ty2 = c.state.MapData.Height()
-	}
-
-	// Use cached collision overlay tile (recreate if tile size changed)
-	if c.cachedOverlayTile == nil || c.cachedOverlayW != tileW || c.cachedOverlayH != tileH {
-		c.cachedOverlayTile = ebiten.NewImage(tileW, tileH)
-		c.cachedOverlayW = tileW
-		c.cachedOverlayH = tileH
-	}
-	c.cachedOverlayTile.Fill(collisionOverlayColor)
-
+	tx1, ty1, tx2, ty2 := c.visibleTiles(canvasWidth, screen.Bounds().Dy())
 	for ty := ty1; ty < ty2; ty++ {
 		for tx := tx1; tx < tx2; tx++ {
 			tileID := collisionLayer.TileAt(tx, ty)
@@ -244,14 +202,25 @@ func (c *Canvas) drawCollisionOverlay(screen *ebiten.Image, canvasWidth int) {
 			screenY := (worldY - camY) * zoom
 
 			// Draw collision overlay
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Scale(zoom, zoom)
-			op.GeoM.Translate(screenX, screenY)
-			screen.DrawImage(c.cachedOverlayTile, op)
+			draw.FillRect(screen, screenX, screenY, float64(tileW)*zoom, float64(tileH)*zoom, collisionOverlayColor)
 		}
 	}
 }
 
+// visibleTiles returns the range of map tiles tx1 to tx2 and ty1 to ty2,
+// exclusive, that a canvasWidth x screenHeight view of the camera shows.
+func (c *Canvas) visibleTiles(canvasWidth, screenHeight int) (tx1, ty1, tx2, ty2 int) {
+	tileW := c.state.MapData.TileWidth()
+	tileH := c.state.MapData.TileHeight()
+	camX, camY, zoom := c.camera.X, c.camera.Y, c.camera.Zoom
+
+	tx1 = max(world.TileIndex(camX, tileW), 0)
+	ty1 = max(world.TileIndex(camY, tileH), 0)
+	tx2 = min(world.TileIndex(camX+float64(canvasWidth)/zoom, tileW)+1, c.state.MapData.Width())
+	ty2 = min(world.TileIndex(camY+float64(screenHeight)/zoom, tileH)+1, c.state.MapData.Height())
+	return tx1, ty1, tx2, ty2
+}
+
 // drawObjects renders all objects as colored rectangles.
 func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 	if c.state == nil {
@@ -264,6 +233,9 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 
 	// Get selection manager for multi-select
 	selection := c.state.GetSelectionManager()
+	if c.overview {
+		selection = nil
+	}
 
 	// First pass: draw switch/door links
 	c.drawSwitchDoorLinks(screen, canvasWidth, camX, camY, zoom)
@@ -271,8 +243,16 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 	// Second pass: draw platform paths
 	c.drawPlatformPaths(screen, canvasWidth, camX, camY, zoom)
 
+	// Light radii
+	c.drawLightRadii(screen, camX, camY, zoom)
+
 	// Third pass: draw objects
 	for i, obj := range c.state.Objects {
+		// Skip objects on hidden layers
+		if !c.state.IsObjectVisible(&obj) {
+			continue
+		}
+
 		// Convert world coordinates to screen coordinates
 		screenX := (obj.X - camX) * zoom
 		screenY := (obj.Y - camY) * zoom
@@ -292,19 +272,29 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 			objColor = parseColor(schema.Color)
 		}
 
-		// Draw object rectangle
-		objImg := ebiten.NewImage(int(w), int(h))
-		objImg.Fill(objColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(screenX, screenY)
-		screen.DrawImage(objImg, op)
+		// Draw object rectangle; force zones are see-through, with arrows
+		// showing their force
+		switch {
+		case obj.Point:
+			c.drawPointObject(screen, screenX, screenY, objColor)
+		case obj.IsShape():
+			c.drawObjectShape(screen, obj, objColor, camX, camY, zoom)
+		case obj.Ellipse:
+			draw.FillEllipse(screen, screenX, screenY, w, h, draw.Fade(objColor, 0.6))
+			draw.StrokeEllipse(screen, screenX, screenY, w, h, 2, darkerColor(objColor, 0.6))
+		default:
+			fill := color.Color(objColor)
+			if obj.Type == world.ObjectTypeForceZone {
+				fill = draw.Fade(objColor, 0.3)
+			}
+			draw.FillRect(screen, screenX, screenY, w, h, fill)
+			if obj.Type == world.ObjectTypeForceZone {
+				c.drawForceArrows(screen, obj, screenX, screenY, w, h)
+			}
 
-		// Draw border
-		borderColor := darkerColor(objColor, 0.6)
-		ebitenutil.DrawRect(screen, screenX, screenY, w, 2, borderColor)
-		ebitenutil.DrawRect(screen, screenX, screenY+h-2, w, 2, borderColor)
-		ebitenutil.DrawRect(screen, screenX, screenY, 2, h, borderColor)
-		ebitenutil.DrawRect(screen, screenX+w-2, screenY, 2, h, borderColor)
+			// Draw border
+			draw.StrokeRect(screen, screenX, screenY, w, h, 2, darkerColor(objColor, 0.6))
+		}
 
 		// Check if this object is selected (single or multi)
 		isSelected := selection != nil && selection.IsSelected(i)
@@ -316,18 +306,25 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 			if selection.SelectionCount() > 1 {
 				selectionColor = color.RGBA{0, 255, 255, 200} // Cyan for multi-select
 			}
-			ebitenutil.DrawRect(screen, screenX-2, screenY-2, w+4, 2, selectionColor)
-			ebitenutil.DrawRect(screen, screenX-2, screenY+h, w+4, 2, selectionColor)
-			ebitenutil.DrawRect(screen, screenX-2, screenY-2, 2, h+4, selectionColor)
-			ebitenutil.DrawRect(screen, screenX+w, screenY-2, 2, h+4, selectionColor)
+			if obj.Point {
+				draw.StrokeRect(screen, screenX-pointMarkerSize-2, screenY-pointMarkerSize-2, 2*pointMarkerSize+4, 2*pointMarkerSize+4, 2, selectionColor)
+			} else {
+				draw.StrokeRect(screen, screenX-2, screenY-2, w+4, h+4, 2, selectionColor)
+			}
 
 			// Draw resize handles only for primary selection
 			if selection.SelectedIndex() == i {
-				c.drawSelectionHandles(screen, screenX, screenY, w, h, zoom)
+				if !obj.IsShape() {
+					c.drawSelectionHandles(screen, screenX, screenY, w, h, zoom)
+				}
 				// Draw endpoint handle for platforms
 				if obj.Type == world.ObjectTypePlatform {
 					c.drawEndpointHandle(screen, obj, camX, camY, zoom, i == c.state.DraggingObjectIdx && c.state.IsDraggingEndpoint)
 				}
+				// Draw the hearing range for sound emitters
+				if obj.Type == world.ObjectTypeSound {
+					c.drawSoundRadius(screen, obj, camX, camY, zoom)
+				}
 			}
 		}
 
@@ -346,10 +343,7 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 
 				// Draw error/warning border around the object
 				borderWidth := 3.0
-				ebitenutil.DrawRect(screen, screenX-borderWidth, screenY-borderWidth, w+2*borderWidth, borderWidth, indicatorColor)
-				ebitenutil.DrawRect(screen, screenX-borderWidth, screenY+h, w+2*borderWidth, borderWidth, indicatorColor)
-				ebitenutil.DrawRect(screen, screenX-borderWidth, screenY-borderWidth, borderWidth, h+2*borderWidth, indicatorColor)
-				ebitenutil.DrawRect(screen, screenX+w, screenY-borderWidth, borderWidth, h+2*borderWidth, indicatorColor)
+				draw.StrokeRect(screen, screenX-borderWidth, screenY-borderWidth, w+2*borderWidth, h+2*borderWidth, borderWidth, indicatorColor)
 
 				// Draw error count badge
 				badgeX := screenX + w - 16
@@ -360,11 +354,7 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 				if badgeY < 0 {
 					badgeY = screenY
 				}
-				badgeImg := ebiten.NewImage(20, 20)
-				badgeImg.Fill(indicatorColor)
-				op := &ebiten.DrawImageOptions{}
-				op.GeoM.Translate(badgeX, badgeY)
-				screen.DrawImage(badgeImg, op)
+				draw.FillRect(screen, badgeX, badgeY, 20, 20, indicatorColor)
 
 				// Draw issue count on badge
 				countText := fmt.Sprintf("%d", len(issues))
@@ -381,9 +371,49 @@ func (c *Canvas) drawObjects(screen *ebiten.Image, canvasWidth int) {
 			labelY := int(screenY) + 4
 			ebitenutil.DebugPrintAt(screen, schema.Name, labelX, labelY)
 		}
+
+		// Show goal completion requirements below the goal
+		if obj.Type == world.ObjectTypeGoal && zoom >= 0.5 {
+			if req := world.NewGoalRequirements(obj); !req.IsZero() {
+				ebitenutil.DebugPrintAt(screen, req.String(), int(screenX), int(screenY+h)+2)
+			}
+		}
+	}
+}
+
+// drawObjectShape draws a polygon object filled see-through with its
+// outline, or a polyline object as a line with its points marked.
+func (c *Canvas) drawObjectShape(screen *ebiten.Image, obj world.ObjectData, objColor color.RGBA, camX, camY, zoom float64) {
+	points := obj.Points()
+	for i, p := range points {
+		points[i] = mathx.V((p.X-camX)*zoom, (p.Y-camY)*zoom)
+	}
+	closed := len(obj.Polygon) > 0
+	if closed {
+		draw.FillPolygon(screen, points, draw.Fade(objColor, 0.4))
+	}
+	draw.StrokePolyline(screen, points, closed, 2, darkerColor(objColor, 0.6))
+	if !closed {
+		for _, p := range points {
+			draw.FillCircle(screen, p.X, p.Y, 3, objColor)
+		}
 	}
 }
 
+// pointMarkerSize is the length, in screen pixels, of each arm of a point
+// object's crosshair.
+const pointMarkerSize = 8.0
+
+// drawPointObject draws a point object as a crosshair around its position.
+func (c *Canvas) drawPointObject(screen *ebiten.Image, screenX, screenY float64, objColor color.RGBA) {
+	outline := darkerColor(objColor, 0.6)
+	draw.Line(screen, screenX-pointMarkerSize, screenY, screenX+pointMarkerSize, screenY, 3, outline)
+	draw.Line(screen, screenX, screenY-pointMarkerSize, screenX, screenY+pointMarkerSize, 3, outline)
+	draw.Line(screen, screenX-pointMarkerSize, screenY, screenX+pointMarkerSize, screenY, 1, objColor)
+	draw.Line(screen, screenX, screenY-pointMarkerSize, screenX, screenY+pointMarkerSize, 1, objColor)
+	draw.StrokeCircle(screen, screenX, screenY, pointMarkerSize/2, 1, objColor)
+}
+
 // drawEntityIcon draws a type indicator icon on each entity.
 func (c *Canvas) drawEntityIcon(screen *ebiten.Image, obj world.ObjectData, screenX, screenY, w, h, zoom float64) {
 	// Get the first letter of the type
@@ -403,6 +433,20 @@ func (c *Canvas) drawEntityIcon(screen *ebiten.Image, obj world.ObjectData, scre
 		letter = "C"
 	case world.ObjectTypeGoal:
 		letter = "G"
+	case world.ObjectTypeKillPlane:
+		letter = "K"
+	case world.ObjectTypeLight:
+		letter = "L"
+	case world.ObjectTypeCollectible:
+		letter = "O"
+	case world.ObjectTypeKey:
+		letter = "Y"
+	case world.ObjectTypeExit:
+		letter = "E"
+	case world.ObjectTypeSound:
+		letter = "A"
+	case world.ObjectTypeForceZone:
+		letter = "F"
 	default:
 		return
 	}
@@ -416,10 +460,62 @@ func (c *Canvas) drawEntityIcon(screen *ebiten.Image, obj world.ObjectData, scre
 	}
 }
 
-// drawPlatformPaths draws movement paths for platforms.
+// drawLevelBounds draws the level edges for clamp/wrap policies and the kill line.
+func (c *Canvas) drawLevelBounds(screen *ebiten.Image, canvasWidth int) {
+	mapData := c.state.MapData
+	levelW := float64(mapData.Width() * mapData.TileWidth())
+	levelH := float64(mapData.Height() * mapData.TileHeight())
+	bounds := world.NewLevelBounds(mapData.Properties(), c.state.Objects, levelW, levelH)
+
+	camX := c.camera.X
+	camY := c.camera.Y
+	zoom := c.camera.Zoom
+
+	left := -camX * zoom
+	top := -camY * zoom
+	right := (levelW - camX) * zoom
+	bottom := (levelH - camY) * zoom
+
+	switch bounds.Policy {
+	case world.BoundsClamp:
+		draw.StrokeRect(screen, left, top, right-left, bottom-top, 2, clampBoundsColor)
+	case world.BoundsWrap:
+		c.drawDashedLine(screen, left, top, right, top, wrapBoundsColor)
+		c.drawDashedLine(screen, left, bottom, right, bottom, wrapBoundsColor)
+		c.drawDashedLine(screen, left, top, left, bottom, wrapBoundsColor)
+		c.drawDashedLine(screen, right, top, right, bottom, wrapBoundsColor)
+	}
+
+	if !bounds.Kill {
+		return
+	}
+
+	killY := (bounds.KillY - camY) * zoom
+	if killY < 0 || killY >= float64(screen.Bounds().Dy()) {
+		return
+	}
+
+	// Extend the line a little past the level so it's visible at the edges
+	x1 := math.Max(left-32, 0)
+	x2 := math.Min(right+32, float64(canvasWidth))
+	c.drawDashedLine(screen, x1, killY, x2, killY, killLineColor)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("KILL y=%.0f", bounds.KillY), int(x1)+4, int(killY)-16)
+}
+
+// drawPlatformPaths draws movement paths for platforms. Platforms following
+// a path object get a line from their center to where they join it.
 func (c *Canvas) drawPlatformPaths(screen *ebiten.Image, canvasWidth int, camX, camY, zoom float64) {
 	for _, obj := range c.state.Objects {
-		if obj.Type != world.ObjectTypePlatform {
+		if obj.Type != world.ObjectTypePlatform || !c.state.IsObjectVisible(&obj) {
+			continue
+		}
+
+		if id := obj.GetPropString(world.PropPlatformPath, ""); id != "" {
+			if path := world.FindPath(c.state.Objects, id); path != nil && path.IsShape() {
+				start := path.Points()[0]
+				c.drawDashedLine(screen, (obj.X+obj.W/2-camX)*zoom, (obj.Y+obj.H/2-camY)*zoom,
+					(start.X-camX)*zoom, (start.Y-camY)*zoom, platformPathColor)
+			}
 			continue
 		}
 
@@ -446,35 +542,84 @@ func (c *Canvas) drawPlatformPaths(screen *ebiten.Image, canvasWidth int, camX,
 		markerX := endScreenX
 		markerY := endScreenY
 
-		// Draw marker rectangle
-		markerImg := ebiten.NewImage(int(markerSize), int(markerSize))
-		markerImg.Fill(platformPathColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(markerX-markerSize/2, markerY-markerSize/2)
-		screen.DrawImage(markerImg, op)
+		// Draw marker rectangle with a border
+		draw.FillRect(screen, markerX-markerSize/2, markerY-markerSize/2, markerSize, markerSize, platformPathColor)
+		draw.StrokeRect(screen, markerX-markerSize/2, markerY-markerSize/2, markerSize, markerSize, 1, color.RGBA{255, 255, 255, 200})
+	}
+}
+
+// drawLightRadii draws the reach of each light as a circle outline.
+func (c *Canvas) drawLightRadii(screen *ebiten.Image, camX, camY, zoom float64) {
+	for _, obj := range c.state.Objects {
+		if obj.Type != world.ObjectTypeLight || !c.state.IsObjectVisible(&obj) {
+			continue
+		}
+
+		radius := obj.GetPropFloat("radius", 64) * zoom
+		centerX := (obj.X + obj.W/2 - camX) * zoom
+		centerY := (obj.Y + obj.H/2 - camY) * zoom
 
-		// Draw marker border
-		ebitenutil.DrawRect(screen, markerX-markerSize/2, markerY-markerSize/2, markerSize, 1, color.RGBA{255, 255, 255, 200})
-		ebitenutil.DrawRect(screen, markerX-markerSize/2, markerY+markerSize/2-1, markerSize, 1, color.RGBA{255, 255, 255, 200})
-		ebitenutil.DrawRect(screen, markerX-markerSize/2, markerY-markerSize/2, 1, markerSize, color.RGBA{255, 255, 255, 200})
-		ebitenutil.DrawRect(screen, markerX+markerSize/2-1, markerY-markerSize/2, 1, markerSize, color.RGBA{255, 255, 255, 200})
+		draw.StrokeCircle(screen, centerX, centerY, radius, 1, lightRadiusColor)
+	}
+}
+
+// drawSoundRadius draws the range a positional sound emitter is heard in,
+// with an inner ring where it is still at half volume. Ambient emitters
+// are heard everywhere and get no ring.
+func (c *Canvas) drawSoundRadius(screen *ebiten.Image, obj world.ObjectData, camX, camY, zoom float64) {
+	falloff := sound.ParseFalloff(obj.GetPropString(world.PropSoundFalloff, ""))
+	if falloff == sound.FalloffNone {
+		return
+	}
+	radius := obj.GetPropFloat(world.PropSoundRadius, world.DefaultSoundRadius) * zoom
+	centerX := (obj.X + obj.W/2 - camX) * zoom
+	centerY := (obj.Y + obj.H/2 - camY) * zoom
+	draw.StrokeCircle(screen, centerX, centerY, radius, 1, soundRadiusColor)
+
+	half := 0.5 // Linear: half volume halfway out
+	if falloff == sound.FalloffQuadratic {
+		half = 1 - math.Sqrt(0.5)
+	}
+	draw.StrokeCircle(screen, centerX, centerY, radius*half, 1, draw.Fade(soundRadiusColor, 0.5))
+}
+
+// drawForceArrows fills a force zone's screen rectangle with a grid of
+// arrows pointing along the net force inside it: gravity (scaled) plus wind.
+// Longer arrows are stronger forces, relative to normal gravity.
+func (c *Canvas) drawForceArrows(screen *ebiten.Image, obj world.ObjectData, x, y, w, h float64) {
+	const spacing = 32.0 // Screen pixels between arrows
+	const normal = 900.0 // Force drawn at full arrow length (default gravity)
+
+	fx := obj.GetPropFloat(world.PropForceWindX, 0)
+	fy := normal*obj.GetPropFloat(world.PropForceGravityScale, world.DefaultForceGravityScale) + obj.GetPropFloat(world.PropForceWindY, 0)
+	strength := math.Hypot(fx, fy)
+	if strength < 1 {
+		// Weightless: nothing to point at
+		return
+	}
+	length := math.Min(strength/normal, 1.5) * spacing * 0.6
+	dx, dy := fx/strength*length/2, fy/strength*length/2
+
+	for cy := y + spacing/2; cy < y+h; cy += spacing {
+		for cx := x + spacing/2; cx < x+w; cx += spacing {
+			draw.SmoothLine(screen, cx-dx, cy-dy, cx+dx, cy+dy, 1.5, forceArrowColor)
+			c.drawLinkArrowhead(screen, cx-dx, cy-dy, cx+dx, cy+dy, 0, 0, forceArrowColor)
+		}
 	}
 }
 
 // drawDashedLine draws a dashed line between two points.
 func (c *Canvas) drawDashedLine(screen *ebiten.Image, x1, y1, x2, y2 float64, col color.Color) {
 	// Calculate line length and direction
-	dx := x2 - x1
-	dy := y2 - y1
-	length := math.Sqrt(dx*dx + dy*dy)
+	length := mathx.Distance(x1, y1, x2, y2)
 
 	if length == 0 {
 		return
 	}
 
 	// Normalize direction
-	nx := dx / length
-	ny := dy / length
+	nx := (x2 - x1) / length
+	ny := (y2 - y1) / length
 
 	// Draw dashes
 	dashLength := 8.0
@@ -495,7 +640,7 @@ func (c *Canvas) drawDashedLine(screen *ebiten.Image, x1, y1, x2, y2 float64, co
 		endY := y1 + ny*endPos
 
 		// Draw the dash segment
-		ebitenutil.DrawLine(screen, startX, startY, endX, endY, col)
+		draw.SmoothLine(screen, startX, startY, endX, endY, 1, col)
 
 		// Move to next dash
 		pos += dashLength + gapLength
@@ -515,58 +660,109 @@ func (c *Canvas) drawSwitchDoorLinks(screen *ebiten.Image, canvasWidth int, camX
 		}
 	}
 
-	// Draw links from switches to doors
+	// Draw links from switches to their doors and platforms
 	for switchIdx, obj := range c.state.Objects {
-		if obj.Type != world.ObjectTypeSwitch {
+		if obj.Type != world.ObjectTypeSwitch || !c.state.IsObjectVisible(&obj) {
 			continue
 		}
 
-		targetID := obj.GetPropString("door_id", "")
-		if targetID == "" {
-			continue
+		for _, targetID := range world.SwitchTargets(obj) {
+			c.drawSwitchLink(screen, switchIdx, targetID, camX, camY, zoom)
 		}
+	}
+}
 
-		// Find the target door
-		var doorObj *world.ObjectData
-		var doorIdx int
-		for i, d := range c.state.Objects {
-			if d.Type == world.ObjectTypeDoor {
-				id := d.GetPropString("id", "")
-				if id == targetID {
-					doorObj = &c.state.Objects[i]
-					doorIdx = i
-					break
-				}
+// drawSwitchLink draws the line from a switch to one of its targets.
+func (c *Canvas) drawSwitchLink(screen *ebiten.Image, switchIdx int, targetID string, camX, camY, zoom float64) {
+	obj := c.state.Objects[switchIdx]
+
+	// Find the target door or platform
+	var doorObj *world.ObjectData
+	var doorIdx int
+	for i, d := range c.state.Objects {
+		if isSwitchTarget(d) {
+			id := d.GetPropString("id", "")
+			if id == targetID {
+				doorObj = &c.state.Objects[i]
+				doorIdx = i
+				break
 			}
 		}
+	}
 
-		if doorObj == nil {
-			continue
-		}
+	if doorObj == nil {
+		return
+	}
 
-		// Check if switch or door is selected
-		selection := c.state.GetSelectionManager()
-		isSelected := (selection != nil && (selection.IsSelected(switchIdx) || selection.IsSelected(doorIdx)))
+	// Check if switch or door is selected
+	selection := c.state.GetSelectionManager()
+	if c.overview {
+		selection = nil
+	}
+	isSelected := (selection != nil && (selection.IsSelected(switchIdx) || selection.IsSelected(doorIdx)))
 
-		// Only draw if selected or if showing all links
-		if !isSelected {
-			continue
+	// Only draw if selected or if showing all links
+	if !isSelected && !c.showAllLinks {
+		return
+	}
+
+	// Calculate screen positions
+	switchCenterX := (obj.X + obj.W/2 - camX) * zoom
+	switchCenterY := (obj.Y + obj.H/2 - camY) * zoom
+	doorCenterX := (doorObj.X + doorObj.W/2 - camX) * zoom
+	doorCenterY := (doorObj.Y + doorObj.H/2 - camY) * zoom
+
+	// Generate a color based on the door ID
+	linkColor := generateLinkColor(targetID)
+
+	// Draw the connection line
+	draw.SmoothLine(screen, switchCenterX, switchCenterY, doorCenterX, doorCenterY, 1.5, linkColor)
+
+	// When auditing all links, show which way each goes and what it targets
+	if c.showAllLinks {
+		c.drawLinkArrowhead(screen, switchCenterX, switchCenterY, doorCenterX, doorCenterY, doorObj.W*zoom, doorObj.H*zoom, linkColor)
+		if zoom >= 0.5 {
+			midX := (switchCenterX + doorCenterX) / 2
+			midY := (switchCenterY + doorCenterY) / 2
+			ebitenutil.DebugPrintAt(screen, targetID, int(midX)+4, int(midY)-14)
 		}
+	}
+}
 
-		// Calculate screen positions
-		switchCenterX := (obj.X + obj.W/2 - camX) * zoom
-		switchCenterY := (obj.Y + obj.H/2 - camY) * zoom
-		doorCenterX := (doorObj.X + doorObj.W/2 - camX) * zoom
-		doorCenterY := (doorObj.Y + doorObj.H/2 - camY) * zoom
+// drawLinkArrowhead draws an arrowhead where the line from x1, y1 to the
+// center x2, y2 of a w x h target (in screen pixels) meets its edge.
+func (c *Canvas) drawLinkArrowhead(screen *ebiten.Image, x1, y1, x2, y2, w, h float64, clr color.Color) {
+	dx, dy := x2-x1, y2-y1
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	dx, dy = dx/length, dy/length
 
-		// Generate a color based on the door ID
-		linkColor := generateLinkColor(targetID)
+	// Back off from the center to the target's edge
+	edge := length
+	if dx != 0 {
+		edge = math.Min(edge, w/2/math.Abs(dx))
+	}
+	if dy != 0 {
+		edge = math.Min(edge, h/2/math.Abs(dy))
+	}
+	tipX, tipY := x2-dx*edge, y2-dy*edge
 
-		// Draw the connection line
-		ebitenutil.DrawLine(screen, switchCenterX, switchCenterY, doorCenterX, doorCenterY, linkColor)
+	const size = 8.0
+	for _, angle := range []float64{math.Pi / 6, -math.Pi / 6} {
+		sin, cos := math.Sincos(angle)
+		bx := -(dx*cos - dy*sin) * size
+		by := -(dx*sin + dy*cos) * size
+		draw.SmoothLine(screen, tipX, tipY, tipX+bx, tipY+by, 1.5, clr)
 	}
 }
 
+// isSwitchTarget returns whether a switch can be linked to the object.
+func isSwitchTarget(obj world.ObjectData) bool {
+	return obj.Type == world.ObjectTypeDoor || obj.Type == world.ObjectTypePlatform
+}
+
 // hashString generates a simple hash from a string.
 func hashString(s string) uint32 {
 	var h uint32
@@ -620,18 +816,9 @@ func (c *Canvas) drawSelectionHandles(screen *ebiten.Image, screenX, screenY, w,
 	}
 
 	for _, handle := range handles {
-		// Draw handle background
-		handleImg := ebiten.NewImage(int(handleSize), int(handleSize))
-		handleImg.Fill(handleColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(handle.x, handle.y)
-		screen.DrawImage(handleImg, op)
-
-		// Draw handle border
-		ebitenutil.DrawRect(screen, handle.x, handle.y, handleSize, 1, handleBorder)
-		ebitenutil.DrawRect(screen, handle.x, handle.y+handleSize-1, handleSize, 1, handleBorder)
-		ebitenutil.DrawRect(screen, handle.x, handle.y, 1, handleSize, handleBorder)
-		ebitenutil.DrawRect(screen, handle.x+handleSize-1, handle.y, 1, handleSize, handleBorder)
+		// Draw handle background and border
+		draw.FillRect(screen, handle.x, handle.y, handleSize, handleSize, handleColor)
+		draw.StrokeRect(screen, handle.x, handle.y, handleSize, handleSize, 1, handleBorder)
 	}
 }
 
@@ -658,18 +845,9 @@ func (c *Canvas) drawEndpointHandle(screen *ebiten.Image, obj world.ObjectData,
 	}
 	handleBorder := color.RGBA{0, 0, 0, 255}
 
-	// Draw handle background
-	handleImg := ebiten.NewImage(int(handleSize), int(handleSize))
-	handleImg.Fill(handleColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(endScreenX-hs, endScreenY-hs)
-	screen.DrawImage(handleImg, op)
-
-	// Draw handle border
-	ebitenutil.DrawRect(screen, endScreenX-hs, endScreenY-hs, handleSize, 1, handleBorder)
-	ebitenutil.DrawRect(screen, endScreenX-hs, endScreenY+hs-1, handleSize, 1, handleBorder)
-	ebitenutil.DrawRect(screen, endScreenX-hs, endScreenY-hs, 1, handleSize, handleBorder)
-	ebitenutil.DrawRect(screen, endScreenX+hs-1, endScreenY-hs, 1, handleSize, handleBorder)
+	// Draw handle background and border
+	draw.FillRect(screen, endScreenX-hs, endScreenY-hs, handleSize, handleSize, handleColor)
+	draw.StrokeRect(screen, endScreenX-hs, endScreenY-hs, handleSize, handleSize, 1, handleBorder)
 
 	// Draw coordinates label when dragging
 	if isDragging {
@@ -694,28 +872,8 @@ func (c *Canvas) drawGrid(screen *ebiten.Image, canvasWidth, screenHeight int) {
 	mapWidth := c.state.MapData.Width()
 	mapHeight := c.state.MapData.Height()
 
-	// Calculate visible tile range
-	startTileX := int(camX) / tileW
-	startTileY := int(camY) / tileH
-	endTileX := startTileX + int(float64(canvasWidth)/float64(tileW)/zoom) + 2
-	endTileY := startTileY + int(float64(screenHeight)/float64(tileH)/zoom) + 2
-
-	// Clamp to map bounds
-	if startTileX < 0 {
-		startTileX = 0
-	}
-	if startTileY < 0 {
-		startTileY = 0
-	}
-	if endTileX > mapWidth {
-		endTileX = mapWidth
-	}
-	if endTileY > mapHeight {
-		endTileY = mapHeight
-	}
-
-	// Use cached 1x1 pixel for grid lines
-	c.cachedPixel.Fill(gridColor)
+	// Lines run along the edges of the visible tiles, the far ones included
+	startTileX, startTileY, endTileX, endTileY := c.visibleTiles(canvasWidth, screenHeight)
 
 	// Calculate the screen position of the map boundaries
 	mapRightWorld := float64(mapWidth * tileW)
@@ -753,10 +911,7 @@ func (c *Canvas) drawGrid(screen *ebiten.Image, canvasWidth, screenHeight int) {
 
 		lineHeight := lineBottom - lineTop
 		if lineHeight > 0 {
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Scale(1, lineHeight)
-			op.GeoM.Translate(screenX, lineTop)
-			screen.DrawImage(c.cachedPixel, op)
+			draw.FillRect(screen, screenX, lineTop, 1, lineHeight, gridColor)
 		}
 	}
 
@@ -788,14 +943,32 @@ func (c *Canvas) drawGrid(screen *ebiten.Image, canvasWidth, screenHeight int) {
 
 		lineWidth := lineRight - lineLeft
 		if lineWidth > 0 {
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Scale(lineWidth, 1)
-			op.GeoM.Translate(lineLeft, screenY)
-			screen.DrawImage(c.cachedPixel, op)
+			draw.FillRect(screen, lineLeft, screenY, lineWidth, 1, gridColor)
 		}
 	}
 }
 
+// drawBoxSelect renders the select tool's rubber band while dragging.
+func (c *Canvas) drawBoxSelect(screen *ebiten.Image) {
+	selectTool := c.tools.SelectTool()
+	if selectTool == nil || !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	x, y, w, h, ok := selectTool.BoxSelectRect()
+	if !ok {
+		return
+	}
+
+	zoom := c.camera.Zoom
+	sx := (x - c.camera.X) * zoom
+	sy := (y - c.camera.Y) * zoom
+	w *= zoom
+	h *= zoom
+
+	draw.FillRect(screen, sx, sy, w, h, boxSelectFillColor)
+	draw.StrokeRect(screen, sx, sy, w, h, 1, boxSelectBorderColor)
+}
+
 // drawToolPreview renders a preview of the selected tile under the cursor.
 func (c *Canvas) drawToolPreview(screen *ebiten.Image, canvasWidth int) {
 	// Only show preview for paint tool
@@ -826,11 +999,12 @@ func (c *Canvas) drawToolPreview(screen *ebiten.Image, canvasWidth int) {
 	// Convert to tile coordinates
 	tileW := c.state.MapData.TileWidth()
 	tileH := c.state.MapData.TileHeight()
-	tileX := int(worldX) / tileW
-	tileY := int(worldY) / tileH
+	tileX := world.TileIndex(worldX, tileW)
+	tileY := world.TileIndex(worldY, tileH)
 
 	// Check if within map bounds
-	if tileX < 0 || tileX >= c.state.MapData.Width() || tileY < 0 || tileY >= c.state.MapData.Height() {
+	layer := c.state.MapData.Layer(c.state.CurrentLayer)
+	if layer == nil || !canPaint(layer, tileX, tileY) {
 		return
 	}
 
@@ -847,13 +1021,7 @@ func (c *Canvas) drawToolPreview(screen *ebiten.Image, canvasWidth int) {
 		if !c.state.SelectedCollision {
 			previewColor = collisionEmptyColor
 		}
-		previewTile := ebiten.NewImage(tileW, tileH)
-		previewTile.Fill(previewColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Scale(c.camera.Zoom, c.camera.Zoom)
-		op.GeoM.Translate(screenX, screenY)
-		op.ColorM.Scale(1, 1, 1, 0.5) // Semi-transparent
-		screen.DrawImage(previewTile, op)
+		draw.FillRect(screen, screenX, screenY, float64(tileW)*c.camera.Zoom, float64(tileH)*c.camera.Zoom, draw.Fade(previewColor, 0.5)) // Semi-transparent
 	} else {
 		// Draw tile preview
 		tile := c.tileset.Tile(c.state.SelectedTile)
@@ -876,7 +1044,9 @@ func (c *Canvas) drawObjectPlacementPreview(screen *ebiten.Image, canvasWidth in
 	}
 
 	objType := c.tools.GetPlaceObjectType()
-	if objType == "" {
+	prefab, isPrefab := c.tools.GetPlacePrefab()
+	isPair := c.tools.IsPlacingLinkedPair()
+	if objType == "" && !isPrefab && !isPair {
 		return
 	}
 
@@ -897,40 +1067,63 @@ func (c *Canvas) drawObjectPlacementPreview(screen *ebiten.Image, canvasWidth in
 	worldX = float64(int(worldX/float64(gridSize))) * float64(gridSize)
 	worldY = float64(int(worldY/float64(gridSize))) * float64(gridSize)
 
+	if isPair {
+		// The switch follows the cursor until placed, then the door does
+		objType = world.ObjectTypeSwitch
+		if sx, sy, ok := c.tools.LinkedPairSwitch(); ok {
+			if schema := GetSchema(world.ObjectTypeSwitch); schema != nil {
+				c.drawPlacementGhost(screen, sx, sy, schema.DefaultW, schema.DefaultH, parseColor(schema.Color), schema.Name)
+				// Line from the switch to the cursor, as in link mode
+				fromX := (sx + schema.DefaultW/2 - c.camera.X) * c.camera.Zoom
+				fromY := (sy + schema.DefaultH/2 - c.camera.Y) * c.camera.Zoom
+				draw.SmoothLine(screen, fromX, fromY, float64(mx), float64(my), 1.5, color.RGBA{255, 200, 0, 200})
+			}
+			objType = world.ObjectTypeDoor
+		}
+	}
+
+	if isPrefab {
+		for _, obj := range prefab.Objects {
+			if schema := GetSchema(obj.Type); schema != nil {
+				c.drawPlacementGhost(screen, worldX+obj.X, worldY+obj.Y, obj.W, obj.H, parseColor(schema.Color), "")
+			}
+		}
+		if c.camera.Zoom >= 0.5 {
+			screenX := (worldX - c.camera.X) * c.camera.Zoom
+			screenY := (worldY - c.camera.Y) * c.camera.Zoom
+			ebitenutil.DebugPrintAt(screen, prefab.Name, int(screenX), int(screenY)-16)
+		}
+		return
+	}
+
 	// Get schema for size and color
 	schema := GetSchema(objType)
 	if schema == nil {
 		return
 	}
 
-	w := schema.DefaultW
-	h := schema.DefaultH
-	objColor := parseColor(schema.Color)
+	c.drawPlacementGhost(screen, worldX, worldY, schema.DefaultW, schema.DefaultH, parseColor(schema.Color), schema.Name)
+}
 
+// drawPlacementGhost draws the semi-transparent outline of an object about to
+// be placed at world position x, y, with label inside it.
+func (c *Canvas) drawPlacementGhost(screen *ebiten.Image, x, y, w, h float64, objColor color.RGBA, label string) {
 	// Convert to screen coordinates
-	screenX := (worldX - c.camera.X) * c.camera.Zoom
-	screenY := (worldY - c.camera.Y) * c.camera.Zoom
+	screenX := (x - c.camera.X) * c.camera.Zoom
+	screenY := (y - c.camera.Y) * c.camera.Zoom
 	sw := w * c.camera.Zoom
 	sh := h * c.camera.Zoom
 
 	// Draw semi-transparent fill
 	ghostColor := color.RGBA{objColor.R, objColor.G, objColor.B, 100}
-	ghostImg := ebiten.NewImage(int(sw), int(sh))
-	ghostImg.Fill(ghostColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(screenX, screenY)
-	screen.DrawImage(ghostImg, op)
+	draw.FillRect(screen, screenX, screenY, sw, sh, ghostColor)
 
 	// Draw border
-	borderColor := color.RGBA{objColor.R, objColor.G, objColor.B, 180}
-	ebitenutil.DrawRect(screen, screenX, screenY, sw, 2, borderColor)
-	ebitenutil.DrawRect(screen, screenX, screenY+sh-2, sw, 2, borderColor)
-	ebitenutil.DrawRect(screen, screenX, screenY, 2, sh, borderColor)
-	ebitenutil.DrawRect(screen, screenX+sw-2, screenY, 2, sh, borderColor)
+	draw.StrokeRect(screen, screenX, screenY, sw, sh, 2, color.RGBA{objColor.R, objColor.G, objColor.B, 180})
 
 	// Draw type label
-	if c.camera.Zoom >= 0.5 {
-		ebitenutil.DebugPrintAt(screen, schema.Name, int(screenX)+4, int(screenY)+4)
+	if label != "" && c.camera.Zoom >= 0.5 {
+		ebitenutil.DebugPrintAt(screen, label, int(screenX)+4, int(screenY)+4)
 	}
 }
 
@@ -957,13 +1150,10 @@ func (c *Canvas) drawLinkModeFeedback(screen *ebiten.Image, canvasWidth int) {
 	switchCenterX := (switchObj.X + switchObj.W/2 - camX) * zoom
 	switchCenterY := (switchObj.Y + switchObj.H/2 - camY) * zoom
 
-	// Draw line from switch to cursor
-	linkLineColor := color.RGBA{255, 200, 0, 200} // Yellow/orange color
-	ebitenutil.DrawLine(screen, switchCenterX, switchCenterY, float64(mx), float64(my), linkLineColor)
-
-	// Highlight all doors
-	for _, obj := range c.state.Objects {
-		if obj.Type != world.ObjectTypeDoor {
+	// Highlight all doors and platforms, the hovered one brighter
+	hovered := c.linkTargetAt(c.camera.ScreenToWorld(mx, my))
+	for i, obj := range c.state.Objects {
+		if !isSwitchTarget(obj) || !c.state.IsObjectVisible(&obj) {
 			continue
 		}
 
@@ -973,14 +1163,46 @@ func (c *Canvas) drawLinkModeFeedback(screen *ebiten.Image, canvasWidth int) {
 		w := obj.W * zoom
 		h := obj.H * zoom
 
-		// Draw highlight border around door
+		// Draw highlight border around target
 		highlightColor := color.RGBA{0, 255, 100, 200} // Green highlight
+		if i == hovered {
+			highlightColor = color.RGBA{200, 255, 220, 255}
+		}
 		borderWidth := 3.0
-		ebitenutil.DrawRect(screen, screenX-borderWidth, screenY-borderWidth, w+2*borderWidth, borderWidth, highlightColor)
-		ebitenutil.DrawRect(screen, screenX-borderWidth, screenY+h, w+2*borderWidth, borderWidth, highlightColor)
-		ebitenutil.DrawRect(screen, screenX-borderWidth, screenY-borderWidth, borderWidth, h+2*borderWidth, highlightColor)
-		ebitenutil.DrawRect(screen, screenX+w, screenY-borderWidth, borderWidth, h+2*borderWidth, highlightColor)
+		draw.StrokeRect(screen, screenX-borderWidth, screenY-borderWidth, w+2*borderWidth, h+2*borderWidth, borderWidth, highlightColor)
+	}
+
+	// Over a target, preview the link as it will be drawn; otherwise draw a
+	// line from the switch to the cursor
+	if hovered >= 0 {
+		target := c.state.Objects[hovered]
+		id := target.GetPropString("id", "")
+		targetX := (target.X + target.W/2 - camX) * zoom
+		targetY := (target.Y + target.H/2 - camY) * zoom
+		draw.SmoothLine(screen, switchCenterX, switchCenterY, targetX, targetY, 2, generateLinkColor(id))
+		label := id
+		if label == "" {
+			label = "no ID"
+		}
+		ebitenutil.DebugPrintAt(screen, label, mx+12, my+8)
+		return
 	}
+	linkLineColor := color.RGBA{255, 200, 0, 200} // Yellow/orange color
+	draw.SmoothLine(screen, switchCenterX, switchCenterY, float64(mx), float64(my), 1.5, linkLineColor)
+}
+
+// linkTargetAt returns the index of the visible door or platform at world
+// position x, y, or -1.
+func (c *Canvas) linkTargetAt(x, y float64) int {
+	for i, obj := range c.state.Objects {
+		if !isSwitchTarget(obj) || !c.state.IsObjectVisible(&obj) {
+			continue
+		}
+		if x >= obj.X && x < obj.X+obj.W && y >= obj.Y && y < obj.Y+obj.H {
+			return i
+		}
+	}
+	return -1
 }
 
 // Update handles input for the canvas.
@@ -996,6 +1218,29 @@ func (c *Canvas) Update() error {
 		if inpututil.IsKeyJustPressed(ebiten.KeyC) {
 			c.showCollision = !c.showCollision
 		}
+
+		// Toggle showing all switch links with K key
+		if inpututil.IsKeyJustPressed(ebiten.KeyK) {
+			c.showAllLinks = !c.showAllLinks
+		}
+
+		// Toggle the playtest path overlay with T key
+		if inpututil.IsKeyJustPressed(ebiten.KeyT) && c.recording != nil {
+			c.showRecording = !c.showRecording
+		}
+
+		// Toggle tile animations with A key
+		if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+			c.SetAnimateTiles(!c.animateTiles)
+		}
+
+		// Cycle the onion skin with J key
+		if inpututil.IsKeyJustPressed(ebiten.KeyJ) {
+			c.CycleOnionSkin()
+		}
+	}
+	if c.animateTiles && c.chunks != nil {
+		c.chunks.SetTime(c.chunks.Time() + time.Second/60)
 	}
 
 	// Handle tool input
@@ -1039,13 +1284,14 @@ func (c *Canvas) handleToolInput() {
 	// Convert to tile coordinates
 	tileW := c.state.MapData.TileWidth()
 	tileH := c.state.MapData.TileHeight()
-	tileX := int(worldX) / tileW
-	tileY := int(worldY) / tileH
+	tileX := world.TileIndex(worldX, tileW)
+	tileY := world.TileIndex(worldY, tileH)
 
 	// Update hovered tile position (clamped to map bounds)
 	mapWidth := c.state.MapData.Width()
 	mapHeight := c.state.MapData.Height()
-	if tileX >= 0 && tileX < mapWidth && tileY >= 0 && tileY < mapHeight {
+	if tileX >= 0 && tileX < mapWidth && tileY >= 0 && tileY < mapHeight ||
+		c.state.MapData.Infinite() && tileX >= 0 && tileY >= 0 {
 		c.hoveredTileX = tileX
 		c.hoveredTileY = tileY
 	} else {
@@ -1243,28 +1489,20 @@ func (c *Canvas) endEndpointDrag() {
 }
 
 // handleLinkModeInput handles input when in link mode.
+// Clicking a door or platform sets the switch's primary target; Shift+click
+// adds it to the switch's targets list instead.
 func (c *Canvas) handleLinkModeInput(worldX, worldY float64) {
-	// Check for mouse click on a door
+	// Check for mouse click on a door or platform
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		// Find the door object under the cursor
-		for i, obj := range c.state.Objects {
-			if obj.Type != world.ObjectTypeDoor {
-				continue
-			}
-
-			// Check if click is within this door's bounds
-			if worldX >= obj.X && worldX < obj.X+obj.W &&
-				worldY >= obj.Y && worldY < obj.Y+obj.H {
-				// Found a door - link it to the switch
-				c.linkSwitchToDoor(i)
-				return
-			}
+		// Clicking a door links it to the switch; clicks elsewhere are ignored
+		if i := c.linkTargetAt(worldX, worldY); i >= 0 {
+			c.linkSwitchToDoor(i)
 		}
-		// Click didn't hit a door - could show feedback or just ignore
 	}
 }
 
-// linkSwitchToDoor creates a link between the selected switch and the clicked door.
+// linkSwitchToDoor creates a link between the selected switch and the clicked
+// door or platform.
 func (c *Canvas) linkSwitchToDoor(doorIndex int) {
 	switchIndex := c.state.GetLinkSource()
 	if switchIndex < 0 || switchIndex >= len(c.state.Objects) {
@@ -1286,17 +1524,35 @@ func (c *Canvas) linkSwitchToDoor(doorIndex int) {
 		return
 	}
 
-	// Verify the target is a door
-	if doorObj.Type != world.ObjectTypeDoor {
+	// Verify the target is a door or platform
+	if !isSwitchTarget(doorObj) {
 		c.state.EndLinkMode()
 		return
 	}
+	kind, label := "door", "Door"
+	if doorObj.Type == world.ObjectTypePlatform {
+		kind, label = "platform", "Platform"
+	}
 
-	// Get the door's ID
+	// Get the target's ID
 	doorID := doorObj.GetPropString("id", "")
 	if doorID == "" {
-		// Door has no ID - show error message
-		c.state.ShowStatusMessage("Door has no ID - set an ID first", true)
+		// Target has no ID - show error message
+		c.state.ShowStatusMessage(i18n.T("editor.linkNoID", label), true)
+		c.state.EndLinkMode()
+		return
+	}
+
+	// Shift+click adds to the targets list
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		oldTargets := switchObj.GetPropString(world.PropSwitchTargets, "")
+		var oldValue any
+		if _, ok := switchObj.Props[world.PropSwitchTargets]; ok {
+			oldValue = oldTargets
+		}
+		action := NewSetPropertyAction(switchIndex, world.PropSwitchTargets, oldValue, world.AddTarget(oldTargets, doorID))
+		c.state.History.Do(action, c.state)
+		c.state.ShowStatusMessage(i18n.T("editor.linkAddedTarget", kind, doorID), false)
 		c.state.EndLinkMode()
 		return
 	}
@@ -1309,7 +1565,7 @@ func (c *Canvas) linkSwitchToDoor(doorIndex int) {
 	c.state.History.Do(action, c.state)
 
 	// Show success message
-	c.state.ShowStatusMessage(fmt.Sprintf("Linked switch to door '%s'", doorID), false)
+	c.state.ShowStatusMessage(i18n.T("editor.linked", kind, doorID), false)
 
 	// Exit link mode
 	c.state.EndLinkMode()
@@ -1325,7 +1581,7 @@ func (c *Canvas) ScreenToTile(screenX, screenY int) (int, int) {
 	tileW := c.state.MapData.TileWidth()
 	tileH := c.state.MapData.TileHeight()
 
-	return int(worldX) / tileW, int(worldY) / tileH
+	return world.TileIndex(worldX, tileW), world.TileIndex(worldY, tileH)
 }
 
 // IsInCanvas returns true if the screen coordinates are within the canvas area.
@@ -1343,6 +1599,31 @@ func (c *Canvas) SetShowGrid(show bool) {
 	c.showGrid = show
 }
 
+// ShowAllLinks returns whether every switch link is drawn.
+func (c *Canvas) ShowAllLinks() bool {
+	return c.showAllLinks
+}
+
+// SetShowAllLinks sets whether every switch link is drawn, rather than only
+// those of selected objects.
+func (c *Canvas) SetShowAllLinks(show bool) {
+	c.showAllLinks = show
+}
+
+// AnimateTiles returns whether tile animations play.
+func (c *Canvas) AnimateTiles() bool {
+	return c.animateTiles
+}
+
+// SetAnimateTiles sets whether tile animations play. Stopping them shows
+// every animated tile's first frame again.
+func (c *Canvas) SetAnimateTiles(animate bool) {
+	c.animateTiles = animate
+	if !animate && c.chunks != nil {
+		c.chunks.SetTime(0)
+	}
+}
+
 // ShowCollision returns whether the collision overlay is visible.
 func (c *Canvas) ShowCollision() bool {
 	return c.showCollision
@@ -1353,6 +1634,16 @@ func (c *Canvas) SetShowCollision(show bool) {
 	c.showCollision = show
 }
 
+// SetPlaytestRecording sets the path from the last playtest and shows it.
+// Empty recordings are dropped.
+func (c *Canvas) SetPlaytestRecording(r *PlaytestRecording) {
+	if r != nil && r.IsEmpty() {
+		r = nil
+	}
+	c.recording = r
+	c.showRecording = r != nil
+}
+
 // HoverHandle returns the current handle being hovered.
 func (c *Canvas) HoverHandle() HandlePosition {
 	return c.hoverHandle
@@ -1384,6 +1675,14 @@ var (
 	platformPathColor       = color.RGBA{128, 64, 192, 200} // Purple for platform paths
 	endpointHandleColor     = color.RGBA{255, 255, 0, 255}  // Yellow for endpoint handles
 	endpointHandleDragColor = color.RGBA{0, 255, 255, 255}  // Cyan when dragging
+	killLineColor           = color.RGBA{255, 64, 64, 220}  // Red for the kill line
+	clampBoundsColor        = color.RGBA{255, 165, 0, 200}  // Orange for clamped edges
+	wrapBoundsColor         = color.RGBA{0, 200, 255, 200}  // Cyan for wrapping edges
+	lightRadiusColor        = color.RGBA{255, 240, 96, 160} // Pale yellow for light radii
+	soundRadiusColor        = color.RGBA{64, 200, 200, 200} // Teal for sound emitter ranges
+	forceArrowColor         = color.RGBA{96, 170, 255, 220} // Pale blue for force zone arrows
+	boxSelectFillColor      = color.RGBA{0, 160, 255, 40}   // Translucent blue box selection
+	boxSelectBorderColor    = color.RGBA{0, 200, 255, 220}  // Cyan box selection outline
 )
 
 // darkerColor returns a darker version of the given color.
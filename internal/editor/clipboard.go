@@ -3,7 +3,6 @@ package editor
 
 import (
 	"encoding/json"
-	"log"
 
 	"github.com/torsten/GoP/internal/world"
 )
@@ -45,13 +44,13 @@ func (c *Clipboard) Copy(state *EditorState) bool {
 	// Serialize the objects
 	data, err := json.Marshal(objects)
 	if err != nil {
-		log.Printf("Failed to copy objects: %v", err)
+		logger.Errorf("Failed to copy objects: %v", err)
 		return false
 	}
 
 	c.data = data
 	c.count = len(objects)
-	log.Printf("Copied %d objects to clipboard", c.count)
+	logger.Infof("Copied %d objects to clipboard", c.count)
 	return true
 }
 
@@ -65,7 +64,7 @@ func (c *Clipboard) Paste(state *EditorState) []int {
 	// Deserialize the objects
 	var objects []world.ObjectData
 	if err := json.Unmarshal(c.data, &objects); err != nil {
-		log.Printf("Failed to paste objects: %v", err)
+		logger.Errorf("Failed to paste objects: %v", err)
 		return nil
 	}
 
@@ -104,7 +103,7 @@ func (c *Clipboard) Paste(state *EditorState) []int {
 	if len(actions) > 0 {
 		action := NewCompositeAction("Paste objects", actions...)
 		state.History.Do(action, state)
-		log.Printf("Pasted %d objects from clipboard", len(objects))
+		logger.Infof("Pasted %d objects from clipboard", len(objects))
 	}
 
 	return newIndices
@@ -131,7 +130,7 @@ func (c *Clipboard) Cut(state *EditorState) bool {
 	selection.ClearSelection()
 	state.ClearSelection()
 
-	log.Printf("Cut %d objects", len(indices))
+	logger.Infof("Cut %d objects", len(indices))
 	return true
 }
 
@@ -0,0 +1,233 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/torsten/GoP/internal/gameplay"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/world"
+	"gopkg.in/yaml.v3"
+)
+
+// customTypes lists the types added with RegisterSchema, in the order they
+// were added, so the palette shows them after the built-in ones.
+var customTypes []world.ObjectType
+
+// customTypeFile is the format of a custom object type file, e.g.:
+//
+//	types:
+//	  - type: lava
+//	    name: Lava Pool
+//	    color: "#FF6000"
+//	    width: 64
+//	    height: 16
+//	    spawnAs: hazard
+//	    properties:
+//	      - {name: damage, type: float, default: 2, min: 0, max: 99}
+type customTypeFile struct {
+	Types []customType `json:"types" yaml:"types"`
+}
+
+// customType is one object type of a custom object type file.
+type customType struct {
+	Type       string           `json:"type" yaml:"type"`
+	Name       string           `json:"name" yaml:"name"`
+	Color      string           `json:"color" yaml:"color"`
+	Width      float64          `json:"width" yaml:"width"`
+	Height     float64          `json:"height" yaml:"height"`
+	SpawnAs    string           `json:"spawnAs" yaml:"spawnAs"`
+	Properties []customProperty `json:"properties" yaml:"properties"`
+}
+
+// customProperty is one property of a custom object type.
+type customProperty struct {
+	Name     string   `json:"name" yaml:"name"`
+	Type     string   `json:"type" yaml:"type"`
+	Required bool     `json:"required" yaml:"required"`
+	Default  any      `json:"default" yaml:"default"`
+	Min      float64  `json:"min" yaml:"min"`
+	Max      float64  `json:"max" yaml:"max"`
+	Options  []string `json:"options" yaml:"options"`
+}
+
+// LoadCustomSchemas reads the object types in the file at path, YAML or JSON
+// (by its .json extension), and registers them with RegisterSchema. Call it
+// before creating the App, whose palette lists the types known then.
+func LoadCustomSchemas(path string) error {
+	data, err := storage.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("object types %s: %w", path, err)
+	}
+	var file customTypeFile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return fmt.Errorf("object types %s: %w", path, err)
+	}
+
+	for _, t := range file.Types {
+		schema, err := t.schema()
+		if err == nil {
+			err = RegisterSchema(schema)
+		}
+		if err != nil {
+			return fmt.Errorf("object types %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// schema converts the file entry to an object schema.
+func (t customType) schema() (ObjectSchema, error) {
+	s := ObjectSchema{
+		Type:     t.Type,
+		Name:     t.Name,
+		DefaultW: t.Width,
+		DefaultH: t.Height,
+		Color:    t.Color,
+		SpawnAs:  world.ObjectType(t.SpawnAs),
+	}
+	for _, p := range t.Properties {
+		def, err := customDefault(p)
+		if err != nil {
+			return ObjectSchema{}, fmt.Errorf("type %s: property %s: %w", t.Type, p.Name, err)
+		}
+		s.Properties = append(s.Properties, PropertySchema{
+			Name:     p.Name,
+			Type:     p.Type,
+			Required: p.Required,
+			Default:  def,
+			Min:      p.Min,
+			Max:      p.Max,
+			Options:  p.Options,
+		})
+	}
+	return s, nil
+}
+
+// customDefault converts a property's default value from the file to the
+// Go type the editor stores for its property type. A missing default is the
+// type's zero value, or an enum's first option.
+func customDefault(p customProperty) (any, error) {
+	switch p.Type {
+	case "float", "int":
+		if p.Default == nil {
+			return 0.0, nil
+		}
+		f, ok := toFloat(p.Default)
+		if !ok {
+			return nil, fmt.Errorf("default %v is not a number", p.Default)
+		}
+		return f, nil
+	case "bool":
+		if p.Default == nil {
+			return false, nil
+		}
+		b, ok := p.Default.(bool)
+		if !ok {
+			return nil, fmt.Errorf("default %v is not a bool", p.Default)
+		}
+		return b, nil
+	case "vec2":
+		var vec [2]float64
+		if p.Default == nil {
+			return vec, nil
+		}
+		list, ok := p.Default.([]any)
+		if !ok || len(list) != 2 {
+			return nil, fmt.Errorf("default %v is not an [x, y] pair", p.Default)
+		}
+		for i, v := range list {
+			if vec[i], ok = toFloat(v); !ok {
+				return nil, fmt.Errorf("default %v is not an [x, y] pair", p.Default)
+			}
+		}
+		return vec, nil
+	case "string", "color", "enum":
+		s := ""
+		if p.Default != nil {
+			var ok bool
+			if s, ok = p.Default.(string); !ok {
+				return nil, fmt.Errorf("default %v is not a string", p.Default)
+			}
+		} else if p.Type == "enum" && len(p.Options) > 0 {
+			s = p.Options[0]
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown type %q (want string, float, int, bool, enum, color, or vec2)", p.Type)
+}
+
+// toFloat returns a JSON or YAML number as a float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// RegisterSchema adds a custom object type, so the palette lists it and the
+// properties panel and validation use its properties. Built-in types can't be
+// replaced. If the schema has a SpawnAs type the game spawns its objects as
+// that type, through gameplay.RegisterObjectAlias.
+func RegisterSchema(s ObjectSchema) error {
+	typ := world.ObjectType(s.Type)
+	if s.Type == "" {
+		return fmt.Errorf("object type without a type name")
+	}
+	if _, ok := SchemaRegistry[typ]; ok {
+		return fmt.Errorf("object type %s is already defined", s.Type)
+	}
+	if s.SpawnAs != "" && !isBuiltInType(s.SpawnAs) {
+		return fmt.Errorf("object type %s: spawnAs %s is not a built-in type", s.Type, s.SpawnAs)
+	}
+	for _, p := range s.Properties {
+		if p.Name == "" {
+			return fmt.Errorf("object type %s: property without a name", s.Type)
+		}
+		if p.Type == "enum" && len(p.Options) == 0 {
+			return fmt.Errorf("object type %s: enum property %s has no options", s.Type, p.Name)
+		}
+	}
+
+	if s.Name == "" {
+		s.Name = s.Type
+	}
+	if s.DefaultW <= 0 {
+		s.DefaultW = 32
+	}
+	if s.DefaultH <= 0 {
+		s.DefaultH = 32
+	}
+	if s.Color == "" {
+		s.Color = "#808080"
+	}
+	SchemaRegistry[typ] = &s
+	customTypes = append(customTypes, typ)
+	if s.SpawnAs != "" {
+		gameplay.RegisterObjectAlias(typ, s.SpawnAs)
+	}
+	return nil
+}
+
+// isBuiltInType returns whether typ is one of the editor's own object types.
+func isBuiltInType(typ world.ObjectType) bool {
+	if _, ok := SchemaRegistry[typ]; !ok {
+		return false
+	}
+	for _, custom := range customTypes {
+		if custom == typ {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// DuplicateObjects returns copies of the objects at indices, offset by dx, dy,
+// in index order. Each copy gets a new Tiled object ID, and copies of objects
+// with an id property get a new unique one, so links to the originals stay
+// on the originals.
+func DuplicateObjects(objects []world.ObjectData, indices []int, dx, dy float64) []world.ObjectData {
+	sorted := append([]int(nil), indices...)
+	sort.Ints(sorted)
+
+	maxID := 0
+	for _, obj := range objects {
+		if obj.ID > maxID {
+			maxID = obj.ID
+		}
+	}
+
+	// Copies count as existing objects for the IDs of later copies
+	existing := append([]world.ObjectData(nil), objects...)
+	copies := make([]world.ObjectData, 0, len(sorted))
+	for _, idx := range sorted {
+		if idx < 0 || idx >= len(objects) {
+			continue
+		}
+		src := objects[idx]
+
+		obj := CreateObjectWithAutoID(src.Type, src.X+dx, src.Y+dy, existing)
+		obj.Name = src.Name
+		obj.W, obj.H = src.W, src.H
+		obj.Layer = src.Layer
+
+		generated, hasGenerated := obj.Props["id"]
+		obj.Props = make(map[string]any, len(src.Props))
+		for k, v := range src.Props {
+			obj.Props[k] = v
+		}
+		if hasGenerated {
+			obj.Props["id"] = generated
+		} else if id, ok := src.Props["id"].(string); ok && id != "" {
+			obj.Props["id"] = GenerateUniqueIDWithCustomPrefix(GetIDPrefixForType(src.Type), existing)
+		}
+
+		maxID++
+		obj.ID = maxID
+
+		existing = append(existing, obj)
+		copies = append(copies, obj)
+	}
+	return copies
+}
+
+// NewDuplicateObjectsAction creates an action that adds copies of the objects
+// at indices, offset by dx, dy, to the end of the level. Returns the action
+// and the indices the copies get, or nil if there is nothing to copy.
+func NewDuplicateObjectsAction(state *EditorState, indices []int, dx, dy float64) (*CompositeAction, []int) {
+	copies := DuplicateObjects(state.Objects, indices, dx, dy)
+	if len(copies) == 0 {
+		return nil, nil
+	}
+	return newAddObjectsAction(fmt.Sprintf("Duplicate %d objects", len(copies)), len(state.Objects), copies)
+}
+
+// newAddObjectsAction creates a composite action adding objects from index
+// start on, and returns it with the indices they get.
+func newAddObjectsAction(desc string, start int, objects []world.ObjectData) (*CompositeAction, []int) {
+	actions := make([]Action, len(objects))
+	newIndices := make([]int, len(objects))
+	for i, obj := range objects {
+		newIndices[i] = start + i
+		actions[i] = NewAddObjectAction(obj, start+i)
+	}
+	return NewCompositeAction(desc, actions...), newIndices
+}
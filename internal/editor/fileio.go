@@ -4,58 +4,95 @@ package editor
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"sort"
 
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/storage"
 	"github.com/torsten/GoP/internal/world"
 )
 
 // TiledJSON represents the full Tiled JSON format for serialization.
 // This ensures 100% read/write compatibility with the existing level format.
 type TiledJSON struct {
-	CompressionLevel int            `json:"compressionlevel"`
-	Height           int            `json:"height"`
-	Infinite         bool           `json:"infinite"`
-	Layers           []TiledLayer   `json:"layers"`
-	NextLayerID      int            `json:"nextlayerid"`
-	NextObjectID     int            `json:"nextobjectid"`
-	Orientation      string         `json:"orientation"`
-	RenderOrder      string         `json:"renderorder"`
-	TiledVersion     string         `json:"tiledversion"`
-	TileHeight       int            `json:"tileheight"`
-	Tilesets         []TiledTileset `json:"tilesets"`
-	TileWidth        int            `json:"tilewidth"`
-	Type             string         `json:"type"`
-	Version          string         `json:"version"`
-	Width            int            `json:"width"`
+	CompressionLevel int             `json:"compressionlevel"`
+	Height           int             `json:"height"`
+	Infinite         bool            `json:"infinite"`
+	Layers           []TiledLayer    `json:"layers"`
+	NextLayerID      int             `json:"nextlayerid"`
+	NextObjectID     int             `json:"nextobjectid"`
+	Orientation      string          `json:"orientation"`
+	Properties       []TiledProperty `json:"properties,omitempty"`
+	RenderOrder      string          `json:"renderorder"`
+	TiledVersion     string          `json:"tiledversion"`
+	TileHeight       int             `json:"tileheight"`
+	Tilesets         []TiledTileset  `json:"tilesets"`
+	TileWidth        int             `json:"tilewidth"`
+	Type             string          `json:"type"`
+	Version          string          `json:"version"`
+	Width            int             `json:"width"`
+
+	extra extraFields // Fields the editor doesn't model
 }
 
 // TiledLayer represents a layer in the Tiled JSON format.
 type TiledLayer struct {
-	Data       []int           `json:"data,omitempty"` // For tile layers
-	Height     int             `json:"height"`
-	ID         int             `json:"id"`
-	Name       string          `json:"name"`
-	Opacity    float64         `json:"opacity"`
-	Type       string          `json:"type"` // "tilelayer" or "objectgroup"
-	Visible    bool            `json:"visible"`
-	Width      int             `json:"width"`
-	X          int             `json:"x"`
-	Y          int             `json:"y"`
-	Objects    []TiledObject   `json:"objects,omitempty"`    // For object layers
-	Properties []TiledProperty `json:"properties,omitempty"` // For object layers
+	Chunks      []TiledChunk    `json:"chunks,omitempty"`      // For tile layers of infinite maps
+	Compression string          `json:"compression,omitempty"` // Of base64 data, see world.DecodeTileData
+	Data        []int           `json:"data,omitempty"`        // For tile layers
+	Encoding    string          `json:"encoding,omitempty"`    // "base64", or a JSON array if empty
+	Height      int             `json:"height"`
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Opacity     float64         `json:"opacity"`
+	StartX      int             `json:"startx,omitempty"` // Top left of the chunks
+	StartY      int             `json:"starty,omitempty"`
+	TintColor   string          `json:"tintcolor,omitempty"` // #RRGGBB or #AARRGGBB
+	Type        string          `json:"type"`                // "tilelayer" or "objectgroup"
+	Visible     bool            `json:"visible"`
+	Width       int             `json:"width"`
+	X           int             `json:"x"`
+	Y           int             `json:"y"`
+	Objects     []TiledObject   `json:"objects,omitempty"`    // For object layers
+	Properties  []TiledProperty `json:"properties,omitempty"` // For object layers
+
+	extra extraFields
+	raw   json.RawMessage // Layers the editor doesn't show, as loaded
 }
 
 // TiledObject represents an object in the Tiled JSON format.
 type TiledObject struct {
+	Ellipse    bool            `json:"ellipse,omitempty"`
 	Height     float64         `json:"height"`
 	ID         int             `json:"id"`
 	Name       string          `json:"name"`
+	Point      bool            `json:"point,omitempty"`
+	Polygon    []TiledPoint    `json:"polygon,omitempty"`
+	Polyline   []TiledPoint    `json:"polyline,omitempty"`
 	Properties []TiledProperty `json:"properties,omitempty"`
 	Type       string          `json:"type"`
-	Visible    bool            `json:"visible,omitempty"`
+	Visible    bool            `json:"visible"`
 	Width      float64         `json:"width"`
 	X          float64         `json:"x"`
 	Y          float64         `json:"y"`
+
+	extra extraFields // Such as "rotation" or "gid"
+}
+
+// TiledPoint is a point of a polygon or polyline object, relative to the
+// object's position.
+type TiledPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// TiledChunk is a block of a tile layer of an infinite map.
+type TiledChunk struct {
+	Data   []int `json:"data"`
+	Height int   `json:"height"`
+	Width  int   `json:"width"`
+	X      int   `json:"x"`
+	Y      int   `json:"y"`
 }
 
 // TiledProperty represents a custom property in the Tiled JSON format.
@@ -63,6 +100,8 @@ type TiledProperty struct {
 	Name  string `json:"name"`
 	Type  string `json:"type"`
 	Value any    `json:"value"`
+
+	extra extraFields // Such as "propertytype"
 }
 
 // TiledTileset represents a tileset reference in the Tiled JSON format.
@@ -78,6 +117,8 @@ type TiledTileset struct {
 	TileCount   int    `json:"tilecount"`
 	TileHeight  int    `json:"tileheight"`
 	TileWidth   int    `json:"tilewidth"`
+
+	raw json.RawMessage // The tileset as loaded
 }
 
 // DefaultLevelPath is the hardcoded path for OpenLevel (will be replaced with dialogs later).
@@ -181,7 +222,7 @@ func NewLevel(width, height int) *EditorState {
 
 // OpenLevel loads an existing Tiled JSON file and returns the editor state.
 func OpenLevel(path string) (*EditorState, error) {
-	data, err := os.ReadFile(path)
+	data, err := storage.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read level file: %w", err)
 	}
@@ -230,10 +271,78 @@ func tiledJSONToEditorState(tiledJSON *TiledJSON, path string) (*EditorState, er
 	state.FilePath = path
 	state.MapData = mapData
 	state.Objects = objects
+	state.ObjectLayers = objectLayersFromTiled(tiledJSON)
+	state.ActiveObjectLayer = state.CurrentObjectLayer()
+	state.TileFormat = tileFormatOf(tiledJSON)
+	state.saved = tiledJSON
 
 	return state, nil
 }
 
+// TileFormat is how saving stores the tiles of tile layers: as JSON arrays
+// of tile IDs, or base64 encoded and, for large levels at a fraction of
+// the size, compressed.
+type TileFormat string
+
+// Tile data formats.
+const (
+	TileFormatArray  TileFormat = ""
+	TileFormatBase64 TileFormat = "base64"
+	TileFormatZlib   TileFormat = "zlib"
+	TileFormatGzip   TileFormat = "gzip"
+)
+
+// tileFormatOf returns the format the level's first tile layer is stored
+// in, so saving keeps it.
+func tileFormatOf(t *TiledJSON) TileFormat {
+	for _, l := range t.Layers {
+		if l.Type != "tilelayer" {
+			continue
+		}
+		if l.Encoding == world.EncodingBase64 && l.Compression != "" {
+			return TileFormat(l.Compression)
+		}
+		return TileFormat(l.Encoding)
+	}
+	return TileFormatArray
+}
+
+// layerEncoding returns the Tiled layer encoding and compression of the
+// format.
+func (f TileFormat) layerEncoding() (encoding, compression string) {
+	switch f {
+	case TileFormatArray:
+		return "", ""
+	case TileFormatBase64:
+		return world.EncodingBase64, ""
+	}
+	return world.EncodingBase64, string(f)
+}
+
+// Next returns the format after f in the order the editor cycles them:
+// arrays, zlib, then gzip. Uncompressed base64 only comes from files.
+func (f TileFormat) Next() TileFormat {
+	switch f {
+	case TileFormatArray:
+		return TileFormatZlib
+	case TileFormatZlib:
+		return TileFormatGzip
+	default:
+		return TileFormatArray
+	}
+}
+
+// String returns the format's name as shown in the editor.
+func (f TileFormat) String() string {
+	if f == TileFormatArray {
+		return "JSON array"
+	}
+	if f == TileFormatBase64 {
+		return "base64"
+	}
+	return "base64+" + string(f)
+}
+
 // SaveLevel saves the editor state to the current file path.
 // Returns an error if no file path is set.
 func SaveLevel(state *EditorState) error {
@@ -243,7 +352,10 @@ func SaveLevel(state *EditorState) error {
 	return SaveLevelAs(state, state.FilePath)
 }
 
-// SaveLevelAs saves the editor state to a new file path.
+// SaveLevelAs saves the editor state to a new file path. The output is
+// canonical so version-control diffs stay small: objects are ordered by ID
+// and properties by name, and what the editor doesn't edit, including
+// fields it doesn't know, is written back as it was loaded.
 func SaveLevelAs(state *EditorState, path string) error {
 	if state.MapData == nil {
 		return fmt.Errorf("no level data to save")
@@ -262,17 +374,46 @@ func SaveLevelAs(state *EditorState, path string) error {
 	}
 
 	// Write to file
-	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+	if err := storage.WriteFile(path, jsonData); err != nil {
 		return fmt.Errorf("failed to write level file: %w", err)
 	}
 
 	// Update state with new path and clear modified flag
 	state.FilePath = path
+	state.saved = tiledJSON
 	state.SetModified(false)
 
 	return nil
 }
 
+// savedMessage returns the status message for a level saved to path. The
+// browser build keeps it in localStorage, not in a file, so it says so.
+func savedMessage(path string) string {
+	if storage.Browser {
+		return i18n.T("editor.savedBrowser", path)
+	}
+	return i18n.T("editor.saved", path)
+}
+
+// setTiledChunks stores an infinite layer's tiles as chunks, with the
+// layer's start and size spanning them as Tiled writes them.
+func setTiledChunks(l *TiledLayer, chunks []world.TileChunk) {
+	l.Data, l.Width, l.Height = nil, 0, 0
+	l.Chunks = make([]TiledChunk, 0, len(chunks))
+	if len(chunks) == 0 {
+		return
+	}
+	x1, y1 := chunks[0].X, chunks[0].Y
+	x2, y2 := x1, y1
+	for _, c := range chunks {
+		l.Chunks = append(l.Chunks, TiledChunk{Data: c.Data, Height: world.ChunkSize, Width: world.ChunkSize, X: c.X, Y: c.Y})
+		x1, y1 = min(x1, c.X), min(y1, c.Y)
+		x2, y2 = max(x2, c.X+world.ChunkSize), max(y2, c.Y+world.ChunkSize)
+	}
+	l.StartX, l.StartY = x1, y1
+	l.Width, l.Height = x2-x1, y2-y1
+}
+
 // editorStateToTiledJSON converts EditorState to TiledJSON for serialization.
 func editorStateToTiledJSON(state *EditorState) (*TiledJSON, error) {
 	if state.MapData == nil {
@@ -289,91 +430,94 @@ func editorStateToTiledJSON(state *EditorState) (*TiledJSON, error) {
 	// Add tile layers
 	for _, layer := range state.MapData.Layers() {
 		tiledLayer := TiledLayer{
-			Data:    layer.Data(),
-			Height:  layer.Height(),
-			ID:      layerID,
-			Name:    layer.Name(),
-			Opacity: 1.0,
-			Type:    "tilelayer",
-			Visible: true,
-			Width:   layer.Width(),
-			X:       0,
-			Y:       0,
+			Data:      layer.Data(),
+			Height:    layer.Height(),
+			ID:        layerID,
+			Name:      layer.Name(),
+			Opacity:   layer.Opacity(),
+			TintColor: world.FormatTintColor(layer.Tint()),
+			Type:      "tilelayer",
+			Visible:   true,
+			Width:     layer.Width(),
+			X:         0,
+			Y:         0,
+		}
+		tiledLayer.Encoding, tiledLayer.Compression = state.TileFormat.layerEncoding()
+		if layer.Infinite() {
+			setTiledChunks(&tiledLayer, layer.Chunks())
 		}
 		layers = append(layers, tiledLayer)
 		layerID++
 	}
 
-	// Build objects from state
-	objects := make([]TiledObject, 0)
+	// Build one object layer per editor layer, keeping their order. Objects
+	// on a layer that doesn't exist go to the first one.
+	layerNames := state.ObjectLayerNames()
+	layerObjects := make(map[string][]TiledObject, len(layerNames))
 	for _, obj := range state.Objects {
 		// Track the highest object ID
 		if obj.ID >= nextObjectID {
 			nextObjectID = obj.ID + 1
 		}
 
-		// Convert properties
-		props := make([]TiledProperty, 0)
-		for key, value := range obj.Props {
-			prop := TiledProperty{
-				Name:  key,
-				Value: value,
-			}
-			// Determine type based on value
-			switch v := value.(type) {
-			case string:
-				prop.Type = "string"
-			case float64:
-				prop.Type = "float"
-			case int:
-				prop.Type = "int"
-				prop.Value = float64(v)
-			case bool:
-				prop.Type = "bool"
-			default:
-				prop.Type = "string"
-			}
-			props = append(props, prop)
-		}
-
 		tiledObj := TiledObject{
+			Ellipse:    obj.Ellipse,
 			Height:     obj.H,
 			ID:         obj.ID,
 			Name:       obj.Name,
-			Properties: props,
+			Point:      obj.Point,
+			Polygon:    toTiledPoints(obj.Polygon),
+			Polyline:   toTiledPoints(obj.Polyline),
+			Properties: toTiledProperties(obj.Props),
 			Type:       string(obj.Type),
 			Visible:    true,
 			Width:      obj.W,
 			X:          obj.X,
 			Y:          obj.Y,
 		}
-		objects = append(objects, tiledObj)
+		// Tiled gives shapes no size of their own
+		if obj.IsShape() {
+			tiledObj.Width, tiledObj.Height = 0, 0
+		}
+		layer := state.ObjectLayerOf(&obj)
+		layerObjects[layer] = append(layerObjects[layer], tiledObj)
 	}
 
-	// Add object layer
-	objectLayer := TiledLayer{
-		Height:  0,
-		ID:      layerID,
-		Name:    "Objects",
-		Opacity: 1.0,
-		Type:    "objectgroup",
-		Visible: true,
-		Width:   0,
-		X:       0,
-		Y:       0,
-		Objects: objects,
+	// Add object layers
+	for _, name := range layerNames {
+		objects := layerObjects[name]
+		if objects == nil {
+			objects = make([]TiledObject, 0)
+		}
+		sort.SliceStable(objects, func(i, j int) bool {
+			return objects[i].ID < objects[j].ID
+		})
+		objectLayer := TiledLayer{
+			Height:  0,
+			ID:      layerID,
+			Name:    name,
+			Opacity: 1.0,
+			Type:    "objectgroup",
+			Visible: true,
+			Width:   0,
+			X:       0,
+			Y:       0,
+			Objects: objects,
+		}
+		layers = append(layers, objectLayer)
+		layerID++
 	}
-	layers = append(layers, objectLayer)
 
 	// Build the full Tiled JSON
 	tiledJSON := &TiledJSON{
 		CompressionLevel: -1,
 		Height:           state.MapData.Height(),
-		Infinite:         false,
+		Infinite:         state.MapData.Infinite(),
 		Layers:           layers,
-		NextLayerID:      layerID + 1,
+		NextLayerID:      layerID,
 		NextObjectID:     nextObjectID,
 		Orientation:      "orthogonal",
+		Properties:       toTiledProperties(state.MapData.Properties()),
 		RenderOrder:      "right-down",
 		TiledVersion:     "1.10.2",
 		TileHeight:       state.MapData.TileHeight(),
@@ -397,6 +541,50 @@ func editorStateToTiledJSON(state *EditorState) (*TiledJSON, error) {
 		Version:   "1.10",
 		Width:     state.MapData.Width(),
 	}
+	keepSaved(tiledJSON, state.saved)
 
 	return tiledJSON, nil
 }
+
+// toTiledProperties converts a property map to Tiled properties, sorted by name.
+func toTiledProperties(values map[string]any) []TiledProperty {
+	props := make([]TiledProperty, 0, len(values))
+	for key, value := range values {
+		prop := TiledProperty{
+			Name:  key,
+			Value: value,
+		}
+		// Determine type based on value
+		switch v := value.(type) {
+		case string:
+			prop.Type = "string"
+		case float64:
+			prop.Type = "float"
+		case int:
+			prop.Type = "int"
+			prop.Value = float64(v)
+		case bool:
+			prop.Type = "bool"
+		default:
+			prop.Type = "string"
+		}
+		props = append(props, prop)
+	}
+
+	sort.Slice(props, func(i, j int) bool {
+		return props[i].Name < props[j].Name
+	})
+	return props
+}
+
+// toTiledPoints converts shape points to Tiled points.
+func toTiledPoints(points []mathx.Vec2) []TiledPoint {
+	if len(points) == 0 {
+		return nil
+	}
+	out := make([]TiledPoint, len(points))
+	for i, p := range points {
+		out[i] = TiledPoint{X: p.X, Y: p.Y}
+	}
+	return out
+}
@@ -0,0 +1,367 @@
+package editor
+
+import (
+	"fmt"
+	"image/color"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Find/replace dialog dimensions
+const (
+	findReplaceWidth       = 460
+	findReplaceHeight      = 360
+	findReplaceFieldHeight = 18
+	findReplaceMaxPreview  = 12
+)
+
+// FindReplaceQuery describes which property values to find and what to replace them with.
+type FindReplaceQuery struct {
+	ObjectType string // Object type filter (empty matches all types)
+	Property   string // Property name (empty or "*" matches all properties)
+	Pattern    string // Value pattern, supports '*' and '?' wildcards
+	Replace    string // Replacement value, converted to the property's type
+}
+
+// PropertyMatch is a single property value matched by a find query.
+type PropertyMatch struct {
+	ObjectIndex int    // Index of the object in the level
+	Property    string // Name of the matched property
+	OldValue    any    // Current value of the property
+	NewValue    any    // Value after replacement (nil if Err is set)
+	Err         error  // Set if the replacement can't be converted to the property's type
+}
+
+// FindPropertyMatches returns all object properties matching the query.
+// Matches are ordered by object index and then by property name.
+func FindPropertyMatches(objects []world.ObjectData, query FindReplaceQuery) []PropertyMatch {
+	var matches []PropertyMatch
+
+	for i, obj := range objects {
+		if query.ObjectType != "" && string(obj.Type) != query.ObjectType {
+			continue
+		}
+
+		// Sort property names for a stable preview order
+		names := make([]string, 0, len(obj.Props))
+		for name := range obj.Props {
+			if query.Property == "" || query.Property == "*" || name == query.Property {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := obj.Props[name]
+			if !matchPropertyValue(query.Pattern, value) {
+				continue
+			}
+
+			newValue, err := convertReplacement(obj.Type, name, value, query.Replace)
+			if err == nil && formatPropertyValue(newValue) == formatPropertyValue(value) {
+				// Replacement wouldn't change anything
+				continue
+			}
+
+			matches = append(matches, PropertyMatch{
+				ObjectIndex: i,
+				Property:    name,
+				OldValue:    value,
+				NewValue:    newValue,
+				Err:         err,
+			})
+		}
+	}
+
+	return matches
+}
+
+// NewReplacePropertiesAction creates a single composite action that applies all valid matches.
+// Returns nil if there is nothing to replace.
+func NewReplacePropertiesAction(matches []PropertyMatch) *CompositeAction {
+	actions := make([]Action, 0, len(matches))
+	for _, m := range matches {
+		if m.Err != nil {
+			continue
+		}
+		actions = append(actions, NewSetPropertyAction(m.ObjectIndex, m.Property, m.OldValue, m.NewValue))
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	return NewCompositeAction(fmt.Sprintf("Replace %d property values", len(actions)), actions...)
+}
+
+// matchPropertyValue reports whether a property value matches the pattern.
+func matchPropertyValue(pattern string, value any) bool {
+	str := formatPropertyValue(value)
+	if !strings.ContainsAny(pattern, "*?") {
+		return str == pattern
+	}
+	matched, err := path.Match(pattern, str)
+	return err == nil && matched
+}
+
+// formatPropertyValue converts a property value to the string used for matching and display.
+func formatPropertyValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// convertReplacement converts the replacement text to the type of the property.
+// The schema type is used if known, otherwise the type of the current value.
+func convertReplacement(typ world.ObjectType, name string, oldValue any, text string) (any, error) {
+	propType := ""
+	if schema := GetSchema(typ); schema != nil {
+		for _, field := range schema.Properties {
+			for _, ps := range field.stored() {
+				if ps.Name == name {
+					propType = ps.Type
+				}
+			}
+		}
+	}
+	if propType == "" {
+		switch oldValue.(type) {
+		case float64:
+			propType = "float"
+		case bool:
+			propType = "bool"
+		default:
+			propType = "string"
+		}
+	}
+
+	switch propType {
+	case "float", "int":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", text)
+		}
+		return f, nil
+	case "bool":
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a bool", text)
+		}
+		return b, nil
+	default:
+		return text, nil
+	}
+}
+
+// FindReplaceDialog is a modal dialog for finding and replacing property values across the level.
+// It only searches the open level: the editor has one level open at a time, so changing
+// several levels means opening each of them in turn.
+type FindReplaceDialog struct {
+	open        bool
+	fields      [4]string // Type, Property, Find, Replace
+	activeField int       // Index of the field receiving text input
+	matches     []PropertyMatch
+}
+
+// findReplaceLabels are the labels for the dialog input fields.
+var findReplaceLabels = [4]string{"Type:", "Property:", "Find:", "Replace:"}
+
+// NewFindReplaceDialog creates a new, closed find/replace dialog.
+func NewFindReplaceDialog() *FindReplaceDialog {
+	return &FindReplaceDialog{
+		activeField: 1,
+	}
+}
+
+// Open shows the dialog and refreshes the preview for the given state.
+// If an object is selected, the type filter defaults to its type.
+func (d *FindReplaceDialog) Open(state *EditorState) {
+	d.open = true
+	if obj := state.GetSelectedObject(); obj != nil && d.fields[0] == "" {
+		d.fields[0] = string(obj.Type)
+	}
+	d.refresh(state)
+}
+
+// Close hides the dialog.
+func (d *FindReplaceDialog) Close() {
+	d.open = false
+}
+
+// IsOpen returns true if the dialog is currently shown.
+func (d *FindReplaceDialog) IsOpen() bool {
+	return d.open
+}
+
+// Query returns the query described by the dialog fields.
+func (d *FindReplaceDialog) Query() FindReplaceQuery {
+	return FindReplaceQuery{
+		ObjectType: strings.TrimSpace(d.fields[0]),
+		Property:   strings.TrimSpace(d.fields[1]),
+		Pattern:    d.fields[2],
+		Replace:    d.fields[3],
+	}
+}
+
+// Matches returns the current preview list.
+func (d *FindReplaceDialog) Matches() []PropertyMatch {
+	return d.matches
+}
+
+// refresh recomputes the preview list from the current fields.
+func (d *FindReplaceDialog) refresh(state *EditorState) {
+	d.matches = FindPropertyMatches(state.Objects, d.Query())
+}
+
+// Update handles text input for the dialog.
+// Enter applies the replacement as a single undoable action, Escape closes the dialog.
+func (d *FindReplaceDialog) Update(state *EditorState) {
+	if !d.open {
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		d.Close()
+		return
+	}
+
+	// Tab / Shift+Tab cycles between fields
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			d.activeField = (d.activeField + len(d.fields) - 1) % len(d.fields)
+		} else {
+			d.activeField = (d.activeField + 1) % len(d.fields)
+		}
+	}
+
+	changed := false
+
+	// Handle text input
+	for _, c := range ebiten.AppendInputChars(nil) {
+		d.fields[d.activeField] += string(c)
+		changed = true
+	}
+
+	// Handle backspace
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		field := d.fields[d.activeField]
+		if len(field) > 0 {
+			d.fields[d.activeField] = field[:len(field)-1]
+			changed = true
+		}
+	}
+
+	if changed {
+		d.refresh(state)
+	}
+
+	// Enter applies all valid matches
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		action := NewReplacePropertiesAction(d.matches)
+		if action == nil {
+			state.ShowStatusMessage(i18n.T("editor.nothingToReplace"), true)
+			return
+		}
+		state.History.Do(action, state)
+		logger.Infof("Find/replace: %s", action.Description())
+		state.ShowStatusMessage(action.Description(), false)
+		d.Close()
+	}
+}
+
+// Draw renders the dialog with its input fields and the match preview.
+func (d *FindReplaceDialog) Draw(screen *ebiten.Image, state *EditorState) {
+	if !d.open {
+		return
+	}
+
+	screenWidth, screenHeight := screen.Size()
+	x := (screenWidth - findReplaceWidth) / 2
+	y := (screenHeight - findReplaceHeight) / 2
+
+	// Draw background
+	draw.FillRect(screen, float64(x), float64(y), float64(findReplaceWidth), float64(findReplaceHeight), color.RGBA{40, 40, 50, 240})
+
+	// Draw border
+	borderColor := color.RGBA{100, 100, 120, 255}
+	draw.StrokeRect(screen, float64(x), float64(y), findReplaceWidth, findReplaceHeight, 2, borderColor)
+
+	// Title
+	ebitenutil.DebugPrintAt(screen, "FIND / REPLACE PROPERTIES IN THIS LEVEL", x+116, y+10)
+
+	// Input fields
+	fieldY := y + 35
+	fieldX := x + 90
+	fieldW := float64(findReplaceWidth - 110)
+	for i, label := range findReplaceLabels {
+		ebitenutil.DebugPrintAt(screen, label, x+15, fieldY)
+
+		bg := propertyInputBgColor
+		if i == d.activeField {
+			bg = propertyHoverColor
+		}
+		draw.FillRect(screen, float64(fieldX), float64(fieldY), fieldW, findReplaceFieldHeight, bg)
+
+		text := d.fields[i]
+		if i == d.activeField {
+			text += "|"
+		} else if text == "" && (i == 0 || i == 1) {
+			text = "(any)"
+		}
+		ebitenutil.DebugPrintAt(screen, text, fieldX+4, fieldY+1)
+
+		fieldY += findReplaceFieldHeight + 6
+	}
+
+	// Preview list
+	previewY := fieldY + 6
+	valid := 0
+	for _, m := range d.matches {
+		if m.Err == nil {
+			valid++
+		}
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Matches: %d (%d replaceable)", len(d.matches), valid), x+15, previewY)
+	previewY += 18
+
+	for i, m := range d.matches {
+		if i >= findReplaceMaxPreview {
+			ebitenutil.DebugPrintAt(screen, fmt.Sprintf("... and %d more", len(d.matches)-i), x+15, previewY)
+			break
+		}
+
+		objType := "?"
+		if m.ObjectIndex >= 0 && m.ObjectIndex < len(state.Objects) {
+			objType = string(state.Objects[m.ObjectIndex].Type)
+		}
+
+		line := fmt.Sprintf("#%d %s.%s: %s -> %s", m.ObjectIndex, objType, m.Property,
+			formatPropertyValue(m.OldValue), formatPropertyValue(m.NewValue))
+		if m.Err != nil {
+			line = fmt.Sprintf("! #%d %s.%s: %v", m.ObjectIndex, objType, m.Property, m.Err)
+		}
+		ebitenutil.DebugPrintAt(screen, line, x+15, previewY)
+		previewY += 14
+	}
+
+	// Hint
+	ebitenutil.DebugPrintAt(screen, "Tab: Next Field  Enter: Replace All  Esc: Close", x+60, y+findReplaceHeight-22)
+}
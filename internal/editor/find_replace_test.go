@@ -0,0 +1,151 @@
+//go:build display
+
+package editor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// findReplaceObjects returns the objects the find/replace tests search.
+func findReplaceObjects() []world.ObjectData {
+	return []world.ObjectData{
+		{ID: 1, Type: world.ObjectTypeSwitch, Props: map[string]any{"door_id": "d1", "once": false}},
+		{ID: 2, Type: world.ObjectTypeDoor, Props: map[string]any{"id": "d1", "openTime": 0.25, "locked": false}},
+		{ID: 3, Type: world.ObjectTypeDoor, Props: map[string]any{"id": "d2", "openTime": 0.5}},
+		{ID: 4, Type: "sign", Props: map[string]any{"text": "d1", "size": 2.0}},
+	}
+}
+
+func TestFindPropertyMatches(t *testing.T) {
+	type match struct {
+		object   int
+		property string
+		newValue any
+		err      bool
+	}
+	tests := []struct {
+		name  string
+		query FindReplaceQuery
+		want  []match
+	}{
+		{
+			name:  "any type and property",
+			query: FindReplaceQuery{Pattern: "d1", Replace: "d3"},
+			want:  []match{{0, "door_id", "d3", false}, {1, "id", "d3", false}, {3, "text", "d3", false}},
+		},
+		{
+			name:  "type filter",
+			query: FindReplaceQuery{ObjectType: "door", Property: "*", Pattern: "d?", Replace: "gate"},
+			want:  []match{{1, "id", "gate", false}, {2, "id", "gate", false}},
+		},
+		{
+			name:  "property filter",
+			query: FindReplaceQuery{Property: "door_id", Pattern: "d*", Replace: "d2"},
+			want:  []match{{0, "door_id", "d2", false}},
+		},
+		{
+			name:  "numbers match as written",
+			query: FindReplaceQuery{Property: "openTime", Pattern: "0.*", Replace: "1"},
+			want:  []match{{1, "openTime", 1.0, false}, {2, "openTime", 1.0, false}},
+		},
+		{
+			name:  "unchanged values are skipped",
+			query: FindReplaceQuery{Property: "openTime", Pattern: "*", Replace: "0.50"},
+			want:  []match{{1, "openTime", 0.5, false}},
+		},
+		{
+			name:  "replacement of the wrong type",
+			query: FindReplaceQuery{Property: "locked", Pattern: "false", Replace: "maybe"},
+			want:  []match{{1, "locked", nil, true}},
+		},
+		{
+			name:  "no match",
+			query: FindReplaceQuery{Pattern: "d9", Replace: "d1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []match
+			for _, m := range FindPropertyMatches(findReplaceObjects(), tt.query) {
+				got = append(got, match{m.ObjectIndex, m.Property, m.NewValue, m.Err != nil})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertReplacement(t *testing.T) {
+	tests := []struct {
+		typ      world.ObjectType
+		property string
+		oldValue any
+		text     string
+		want     any
+		err      bool
+	}{
+		// Schema types win over the current value's type
+		{world.ObjectTypeDoor, "openTime", "fast", " 1.5 ", 1.5, false},
+		{world.ObjectTypeDoor, "openTime", 0.25, "fast", nil, true},
+		{world.ObjectTypeDoor, "locked", false, "true", true, false},
+		{world.ObjectTypeDoor, "locked", false, "yes", nil, true},
+		{world.ObjectTypeDoor, "id", 12.0, "12", "12", false},
+		// Properties without a schema keep the current value's type
+		{"sign", "size", 2.0, "3", 3.0, false},
+		{"sign", "lit", true, "false", false, false},
+		{"sign", "text", "hi", " there ", " there ", false},
+	}
+	for _, tt := range tests {
+		got, err := convertReplacement(tt.typ, tt.property, tt.oldValue, tt.text)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("convertReplacement(%s, %q, %v, %q) = %v, %v; want %v, error %v",
+				tt.typ, tt.property, tt.oldValue, tt.text, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestReplacePropertiesActionUndo(t *testing.T) {
+	state := NewLevel(10, 10)
+	state.Objects = findReplaceObjects()
+
+	matches := FindPropertyMatches(state.Objects, FindReplaceQuery{Pattern: "d1", Replace: "d3"})
+	// A match that can't be converted is left alone
+	matches = append(matches, PropertyMatch{ObjectIndex: 1, Property: "locked", OldValue: false, Err: errors.New("not a bool")})
+	action := NewReplacePropertiesAction(matches)
+	if action == nil {
+		t.Fatal("NewReplacePropertiesAction = nil, want an action")
+	}
+
+	state.History.Do(action, state)
+	for _, c := range []struct {
+		object   int
+		property string
+	}{{0, "door_id"}, {1, "id"}, {3, "text"}} {
+		if got := state.Objects[c.object].Props[c.property]; got != "d3" {
+			t.Errorf("after replace object %d %s = %v, want d3", c.object, c.property, got)
+		}
+	}
+	if got := state.Objects[1].Props["locked"]; got != false {
+		t.Errorf("after replace locked = %v, want it unchanged", got)
+	}
+
+	// All replacements undo as one step
+	if !state.History.Undo(state) {
+		t.Fatal("Undo failed")
+	}
+	if !reflect.DeepEqual(state.Objects, findReplaceObjects()) {
+		t.Errorf("after undo objects = %v, want them as before", state.Objects)
+	}
+	if state.History.Undo(state) {
+		t.Error("a second Undo succeeded, want the replace to be one step")
+	}
+
+	if action := NewReplacePropertiesAction(matches[len(matches)-1:]); action != nil {
+		t.Error("NewReplacePropertiesAction of only failed matches is not nil")
+	}
+}
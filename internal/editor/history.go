@@ -15,6 +15,7 @@ type Action interface {
 type History struct {
 	actions []Action
 	index   int // Current position in history (points to next action to redo)
+	version int // Changes made through the history, see Version
 }
 
 // NewHistory creates a new history manager.
@@ -37,6 +38,7 @@ func (h *History) Do(action Action, state *EditorState) {
 	// Add to history and advance index
 	h.actions = append(h.actions, action)
 	h.index++
+	h.version++
 
 	// Mark state as modified
 	state.SetModified(true)
@@ -51,6 +53,7 @@ func (h *History) Undo(state *EditorState) bool {
 
 	h.index--
 	h.actions[h.index].Undo(state)
+	h.version++
 	state.SetModified(true)
 	return true
 }
@@ -64,6 +67,7 @@ func (h *History) Redo(state *EditorState) bool {
 
 	h.actions[h.index].Do(state)
 	h.index++
+	h.version++
 	state.SetModified(true)
 	return true
 }
@@ -82,6 +86,7 @@ func (h *History) CanRedo() bool {
 func (h *History) Clear() {
 	h.actions = make([]Action, 0)
 	h.index = 0
+	h.version++
 }
 
 // UndoDescription returns the description of the action that would be undone.
@@ -111,3 +116,10 @@ func (h *History) Count() int {
 func (h *History) Index() int {
 	return h.index
 }
+
+// Version returns a number that changes whenever an action is done, undone
+// or redone, or the history is cleared, so callers can tell that the level
+// changed since they last looked.
+func (h *History) Version() int {
+	return h.version
+}
@@ -0,0 +1,206 @@
+package editor
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Layers panel dimensions, relative to the panel's top left
+const (
+	layersWidth      = 260
+	layersListOffset = 26 // Distance from the panel top to the first row
+	layersRowHeight  = 18
+	layersFooter     = 24 // Space below the list for the hint line
+	layersEyeX       = 8
+	layersNameX      = 24
+	layersBarX       = 122
+	layersBarWidth   = 90
+	layersSwatchX    = 222
+	layersSwatchSize = 14
+)
+
+// opacityStep is what the layers panel rounds a clicked opacity to.
+const opacityStep = 0.05
+
+// layerTints are the tints clicking a layer's swatch cycles through, after
+// none: for dimming a background or setting a mood.
+var layerTints = []color.RGBA{
+	{},
+	{0x90, 0x90, 0xb0, 0xff}, // Dim
+	{0x50, 0x60, 0xa0, 0xff}, // Night
+	{0xff, 0xc8, 0x90, 0xff}, // Dusk
+	{0xa0, 0xe0, 0xa0, 0xff}, // Swamp
+	{0x30, 0x30, 0x40, 0xff}, // Silhouette
+}
+
+// LayersPanel lists the tile layers in the top right corner of the canvas.
+// Clicking a layer's name makes it the current layer, its box toggles its
+// visibility, its bar sets its opacity and its swatch cycles its tint
+// (right click removes it). Like the problems panel it doesn't block other
+// input outside the panel.
+type LayersPanel struct {
+	open bool
+}
+
+// NewLayersPanel creates a new, closed layers panel.
+func NewLayersPanel() *LayersPanel {
+	return &LayersPanel{}
+}
+
+// Toggle shows or hides the panel.
+func (p *LayersPanel) Toggle() {
+	p.open = !p.open
+}
+
+// IsOpen returns true if the panel is currently shown.
+func (p *LayersPanel) IsOpen() bool {
+	return p.open
+}
+
+// height returns the panel's height for the level's layers.
+func (p *LayersPanel) height(state *EditorState) int {
+	rows := 0
+	if state.MapData != nil {
+		rows = len(state.MapData.Layers())
+	}
+	return layersListOffset + rows*layersRowHeight + layersFooter
+}
+
+// Update handles clicks on the panel. It returns true if the mouse is over
+// the panel, so the canvas shouldn't get it.
+func (p *LayersPanel) Update(state *EditorState, canvasWidth int) bool {
+	if !p.open || state.MapData == nil {
+		return false
+	}
+
+	mx, my := ebiten.CursorPosition()
+	x, y := mx-(canvasWidth-layersWidth), my
+	if x < 0 || x >= layersWidth || y < 0 || y >= p.height(state) {
+		return false
+	}
+	left := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	right := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+	layers := state.MapData.Layers()
+	row := (y - layersListOffset) / layersRowHeight
+	if !left && !right || y < layersListOffset || row >= len(layers) {
+		return true
+	}
+	layer := layers[row]
+
+	switch {
+	case x < layersNameX:
+		if left {
+			state.SetLayerVisible(layer.Name(), !state.IsLayerVisible(layer.Name()))
+		}
+	case x < layersBarX-4:
+		if left {
+			state.SetLayer(layer.Name())
+		}
+	case x < layersSwatchX-4:
+		if left {
+			opacity := float64(x-layersBarX) / layersBarWidth
+			opacity = math.Round(opacity/opacityStep) * opacityStep
+			p.apply(state, NewSetLayerStyleAction(layer, min(max(opacity, 0), 1), layer.Tint()))
+		}
+	default:
+		tint := color.RGBA{}
+		if left {
+			tint = nextLayerTint(layer.Tint())
+		}
+		p.apply(state, NewSetLayerStyleAction(layer, layer.Opacity(), tint))
+	}
+	return true
+}
+
+// apply runs a style change unless it changes nothing.
+func (p *LayersPanel) apply(state *EditorState, action *SetLayerStyleAction) {
+	if action.NewOpacity == action.OldOpacity && action.NewTint == action.OldTint {
+		return
+	}
+	state.History.Do(action, state)
+	state.ShowStatusMessage(action.Description(), false)
+}
+
+// nextLayerTint returns the tint after tint in layerTints, or the first
+// one after none for a tint from elsewhere, like Tiled.
+func nextLayerTint(tint color.RGBA) color.RGBA {
+	for i, t := range layerTints {
+		if t == tint {
+			return layerTints[(i+1)%len(layerTints)]
+		}
+	}
+	return layerTints[1]
+}
+
+// Draw renders the panel in the top right corner of the canvas.
+func (p *LayersPanel) Draw(screen *ebiten.Image, state *EditorState, canvasWidth int) {
+	if !p.open || state.MapData == nil {
+		return
+	}
+
+	left := float64(canvasWidth - layersWidth)
+	height := float64(p.height(state))
+	draw.FillRect(screen, left, 0, layersWidth, height, color.RGBA{40, 40, 50, 240})
+	draw.FillRect(screen, left, 0, 2, height, color.RGBA{100, 100, 120, 255})
+	ebitenutil.DebugPrintAt(screen, "LAYERS", int(left)+10, 6)
+
+	for i, layer := range state.MapData.Layers() {
+		y := float64(layersListOffset + i*layersRowHeight)
+		if layer.Name() == state.CurrentLayer {
+			draw.FillRect(screen, left+4, y-2, layersWidth-8, layersRowHeight, propertyHoverColor)
+		}
+
+		// Visibility box, filled when visible
+		draw.StrokeRect(screen, left+layersEyeX, y+1, 10, 10, 1, color.RGBA{200, 200, 210, 255})
+		if state.IsLayerVisible(layer.Name()) {
+			draw.FillRect(screen, left+layersEyeX+2, y+3, 6, 6, color.RGBA{200, 200, 210, 255})
+		}
+
+		name := layer.Name()
+		if maxChars := (layersBarX - layersNameX - 8) / 6; len(name) > maxChars {
+			name = name[:maxChars-1] + "~"
+		}
+		ebitenutil.DebugPrintAt(screen, name, int(left)+layersNameX, int(y)-2)
+
+		// Opacity bar with its percentage
+		draw.FillRect(screen, left+layersBarX, y, layersBarWidth, 12, color.RGBA{25, 25, 30, 255})
+		draw.FillRect(screen, left+layersBarX, y, layersBarWidth*layer.Opacity(), 12, color.RGBA{80, 110, 170, 255})
+		percent := fmt.Sprintf("%d%%", int(math.Round(layer.Opacity()*100)))
+		ebitenutil.DebugPrintAt(screen, percent, int(left)+layersBarX+(layersBarWidth-len(percent)*6)/2, int(y)-2)
+
+		// Tint swatch, crossed out without a tint
+		sx := left + layersSwatchX
+		if tint := layer.Tint(); tint.A > 0 {
+			draw.FillRect(screen, sx, y-1, layersSwatchSize, layersSwatchSize, tint)
+		} else {
+			draw.Line(screen, sx, y+layersSwatchSize-1, sx+layersSwatchSize, y-1, 1, color.RGBA{150, 150, 160, 255})
+		}
+		draw.StrokeRect(screen, sx, y-1, layersSwatchSize, layersSwatchSize, 1, color.RGBA{150, 150, 160, 255})
+	}
+
+	hint := "Bar: Opacity  Swatch: Tint"
+	ebitenutil.DebugPrintAt(screen, hint, int(left)+10, int(height)-layersFooter+4)
+}
+
+// layerStyleSummary describes a layer's opacity and tint for the status bar,
+// or returns "" when it's opaque without a tint.
+func layerStyleSummary(layer *world.TileLayer) string {
+	var s string
+	if layer.Opacity() < 1 {
+		s = fmt.Sprintf("%d%%", int(math.Round(layer.Opacity()*100)))
+	}
+	if tint := world.FormatTintColor(layer.Tint()); tint != "" {
+		if s != "" {
+			s += " "
+		}
+		s += tint
+	}
+	return s
+}
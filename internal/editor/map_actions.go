@@ -0,0 +1,122 @@
+// Package editor provides the level editor functionality.
+package editor
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// SetMapPropertyAction represents changing a map-level custom property.
+// A nil value means the property is not set.
+type SetMapPropertyAction struct {
+	PropertyName string
+	OldValue     any
+	NewValue     any
+}
+
+// NewSetMapPropertyAction creates a new set map property action.
+// It captures the current property value before the change.
+func NewSetMapPropertyAction(state *EditorState, propertyName string, newValue any) *SetMapPropertyAction {
+	var oldValue any
+	if state.MapData != nil {
+		oldValue = state.MapData.Properties()[propertyName]
+	}
+
+	return &SetMapPropertyAction{
+		PropertyName: propertyName,
+		OldValue:     oldValue,
+		NewValue:     newValue,
+	}
+}
+
+// Do sets the property to the new value.
+func (a *SetMapPropertyAction) Do(state *EditorState) {
+	if state.MapData != nil {
+		state.MapData.SetProperty(a.PropertyName, a.NewValue)
+	}
+}
+
+// Undo sets the property back to the old value.
+func (a *SetMapPropertyAction) Undo(state *EditorState) {
+	if state.MapData != nil {
+		state.MapData.SetProperty(a.PropertyName, a.OldValue)
+	}
+}
+
+// Description returns a human-readable description.
+func (a *SetMapPropertyAction) Description() string {
+	return fmt.Sprintf("Set map %s property", a.PropertyName)
+}
+
+// newCycleBoundsPolicyAction creates an action that switches the level to the
+// next bounds policy (kill, clamp, wrap). Returns the action and the new policy.
+func newCycleBoundsPolicyAction(state *EditorState) (*SetMapPropertyAction, world.BoundsPolicy) {
+	current := world.NewLevelBounds(state.MapData.Properties(), nil, 0, 0).Policy
+
+	var next world.BoundsPolicy
+	switch current {
+	case world.BoundsKill:
+		next = world.BoundsClamp
+	case world.BoundsClamp:
+		next = world.BoundsWrap
+	default:
+		next = world.BoundsKill
+	}
+
+	return NewSetMapPropertyAction(state, world.PropBoundsPolicy, string(next)), next
+}
+
+// SetLayerStyleAction represents changing a tile layer's opacity and tint.
+type SetLayerStyleAction struct {
+	Layer      string
+	OldOpacity float64
+	NewOpacity float64
+	OldTint    color.RGBA
+	NewTint    color.RGBA
+}
+
+// NewSetLayerStyleAction creates an action giving the layer the opacity and
+// tint. It captures the layer's current ones before the change.
+func NewSetLayerStyleAction(layer *world.TileLayer, opacity float64, tint color.RGBA) *SetLayerStyleAction {
+	return &SetLayerStyleAction{
+		Layer:      layer.Name(),
+		OldOpacity: layer.Opacity(),
+		NewOpacity: opacity,
+		OldTint:    layer.Tint(),
+		NewTint:    tint,
+	}
+}
+
+// Do applies the new opacity and tint.
+func (a *SetLayerStyleAction) Do(state *EditorState) {
+	a.apply(state, a.NewOpacity, a.NewTint)
+}
+
+// Undo restores the old opacity and tint.
+func (a *SetLayerStyleAction) Undo(state *EditorState) {
+	a.apply(state, a.OldOpacity, a.OldTint)
+}
+
+func (a *SetLayerStyleAction) apply(state *EditorState, opacity float64, tint color.RGBA) {
+	if state.MapData == nil {
+		return
+	}
+	if layer := state.MapData.Layer(a.Layer); layer != nil {
+		layer.SetOpacity(opacity)
+		layer.SetTint(tint)
+	}
+}
+
+// Description returns a human-readable description.
+func (a *SetLayerStyleAction) Description() string {
+	if a.NewTint != a.OldTint {
+		if tint := world.FormatTintColor(a.NewTint); tint != "" {
+			return fmt.Sprintf("Tint layer %s %s", a.Layer, tint)
+		}
+		return fmt.Sprintf("Remove tint of layer %s", a.Layer)
+	}
+	return fmt.Sprintf("Set layer %s opacity to %d%%", a.Layer, int(math.Round(a.NewOpacity*100)))
+}
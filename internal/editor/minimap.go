@@ -5,126 +5,301 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/world"
 )
 
+// Minimap colors
+var (
+	minimapCollisionColor = color.RGBA{120, 60, 60, 255} // Solid tiles without art
+	minimapViewportColor  = color.RGBA{255, 255, 255, 150}
+	minimapViewportDrag   = color.RGBA{255, 255, 255, 230}
+)
+
+// minimapDotSize is the size of the dot marking an object.
+const minimapDotSize = 3.0
+
 // Minimap provides a small overview of the entire level.
+//
+// The tile layers are kept in a cached image with one pixel per tile in the
+// tiles' average color. Each frame the layers are compared with the last
+// copy, and only changed tiles are redrawn.
 type Minimap struct {
 	width  int
 	height int
 	x      int
 	y      int
+
+	// Tile cache
+	cache     *ebiten.Image
+	pixels    []byte             // RGBA pixels of the cache, one per tile
+	mapData   *world.MapData     // Map the cache was built for
+	layerData [][]int            // Copy of each layer's tiles at the last update
+	tileColor map[int]color.RGBA // Average color per tile ID
+
+	// Viewport dragging
+	dragging           bool
+	dragOffX, dragOffY float64 // Grab point inside the viewport, in world units
 }
 
 // NewMinimap creates a new minimap component.
 func NewMinimap() *Minimap {
 	return &Minimap{
-		width:  150,
-		height: 100,
-		x:      10,
-		y:      10,
+		width:     150,
+		height:    100,
+		x:         10,
+		y:         10,
+		tileColor: make(map[int]color.RGBA),
+	}
+}
+
+// scale returns the world-to-minimap scale for the level.
+func (m *Minimap) scale(state *EditorState) float64 {
+	mapWidth := state.MapData.Width() * state.MapData.TileWidth()
+	mapHeight := state.MapData.Height() * state.MapData.TileHeight()
+
+	scaleX := float64(m.width) / float64(mapWidth)
+	scaleY := float64(m.height) / float64(mapHeight)
+	if scaleY < scaleX {
+		return scaleY
+	}
+	return scaleX
+}
+
+// updateCache brings the tile cache up to date with the map, rebuilding it
+// when the map or its size changed and redrawing changed tiles otherwise.
+func (m *Minimap) updateCache(state *EditorState, tileset *Tileset) {
+	md := state.MapData
+	w, h := md.Width(), md.Height()
+	layers := md.Layers()
+
+	rebuild := m.cache == nil || m.mapData != md || len(m.layerData) != len(layers)
+	if !rebuild {
+		if bw, bh := m.cache.Bounds().Dx(), m.cache.Bounds().Dy(); bw != w || bh != h {
+			rebuild = true
+		}
+	}
+	if rebuild {
+		m.cache = ebiten.NewImage(w, h)
+		m.pixels = make([]byte, w*h*4)
+		m.mapData = md
+		m.layerData = make([][]int, len(layers))
+	}
+
+	changed := rebuild
+	for li, layer := range layers {
+		data := minimapTiles(layer, w, h)
+		if len(m.layerData[li]) != len(data) {
+			m.layerData[li] = make([]int, len(data))
+			for i := range m.layerData[li] {
+				m.layerData[li][i] = -1 // Force a redraw
+			}
+		}
+		for i, id := range data {
+			if m.layerData[li][i] == id {
+				continue
+			}
+			m.layerData[li][i] = id
+			m.setPixel(i, m.tilePixel(layers, i, tileset))
+			changed = true
+		}
+	}
+
+	if changed {
+		m.cache.WritePixels(m.pixels)
+	}
+}
+
+// minimapTiles returns the layer's tiles as a w x h grid: its data, or for
+// an infinite layer its chunks' tiles, which may not reach the map's extent.
+func minimapTiles(layer *world.TileLayer, w, h int) []int {
+	if !layer.Infinite() {
+		return layer.Data()
+	}
+	data := make([]int, w*h)
+	for _, chunk := range layer.Chunks() {
+		for i, id := range chunk.Data {
+			tx, ty := chunk.X+i%world.ChunkSize, chunk.Y+i/world.ChunkSize
+			if tx >= 0 && ty >= 0 && tx < w && ty < h {
+				data[ty*w+tx] = id
+			}
+		}
+	}
+	return data
+}
+
+// setPixel writes one tile's color into the pixel buffer.
+func (m *Minimap) setPixel(i int, c color.RGBA) {
+	m.pixels[i*4] = c.R
+	m.pixels[i*4+1] = c.G
+	m.pixels[i*4+2] = c.B
+	m.pixels[i*4+3] = c.A
+}
+
+// tilePixel returns the minimap color of tile i: the topmost art tile, or
+// the collision color for solid tiles without art.
+func (m *Minimap) tilePixel(layers []*world.TileLayer, i int, tileset *Tileset) color.RGBA {
+	var c color.RGBA
+	solid := false
+	w := m.mapData.Width()
+	for _, layer := range layers {
+		id := layer.TileAt(i%w, i/w)
+		if id == 0 {
+			continue
+		}
+		if layer.Name() == "Collision" {
+			solid = true
+			continue
+		}
+		c = m.averageTileColor(id, tileset)
+	}
+	if c.A == 0 && solid {
+		return minimapCollisionColor
+	}
+	return c
+}
+
+// averageTileColor returns the average color of a tile's opaque pixels,
+// computed once per tile ID.
+func (m *Minimap) averageTileColor(id int, tileset *Tileset) color.RGBA {
+	if c, ok := m.tileColor[id]; ok {
+		return c
 	}
+
+	c := objectDefaultColor
+	if tileset != nil {
+		if img := tileset.Tile(id - 1); img != nil {
+			b := img.Bounds()
+			buf := make([]byte, b.Dx()*b.Dy()*4)
+			img.ReadPixels(buf)
+			var r, g, bl, n int
+			for p := 0; p < len(buf); p += 4 {
+				if buf[p+3] < 128 {
+					continue
+				}
+				r += int(buf[p])
+				g += int(buf[p+1])
+				bl += int(buf[p+2])
+				n++
+			}
+			if n > 0 {
+				c = color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), 255}
+			}
+		}
+	}
+	m.tileColor[id] = c
+	return c
+}
+
+// viewport returns the camera's view in world coordinates.
+func viewport(camera *Camera, canvasWidth, canvasHeight int) (x, y, w, h float64) {
+	return camera.X, camera.Y, float64(canvasWidth) / camera.Zoom, float64(canvasHeight) / camera.Zoom
 }
 
 // Draw renders the minimap to the screen.
-func (m *Minimap) Draw(screen *ebiten.Image, state *EditorState, camera *Camera) {
+func (m *Minimap) Draw(screen *ebiten.Image, state *EditorState, camera *Camera, tileset *Tileset) {
 	if state == nil || !state.HasLevel() {
 		return
 	}
 
-	screenWidth, _ := screen.Size()
+	screenWidth, screenHeight := screen.Size()
+	canvasWidth := screenWidth - PaletteWidth - ObjectPaletteWidth
 
-	// Position minimap in top-left corner of canvas area
-	m.x = screenWidth - PaletteWidth - ObjectPaletteWidth - m.width - 10
+	// Position minimap in top-right corner of canvas area
+	m.x = canvasWidth - m.width - 10
 	m.y = 10
 
 	// Draw background
-	minimapImg := ebiten.NewImage(m.width, m.height)
-	minimapImg.Fill(color.RGBA{20, 20, 30, 200})
+	draw.FillRect(screen, float64(m.x), float64(m.y), float64(m.width), float64(m.height), color.RGBA{20, 20, 30, 200})
+
+	// Draw tiles
+	m.updateCache(state, tileset)
+	scale := m.scale(state)
 	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale*float64(state.MapData.TileWidth()), scale*float64(state.MapData.TileHeight()))
 	op.GeoM.Translate(float64(m.x), float64(m.y))
-	screen.DrawImage(minimapImg, op)
+	screen.DrawImage(m.cache, op)
 
 	// Draw border
 	borderColor := color.RGBA{80, 80, 100, 255}
-	ebitenutil.DrawRect(screen, float64(m.x), float64(m.y), float64(m.width), 1, borderColor)
-	ebitenutil.DrawRect(screen, float64(m.x), float64(m.y+m.height-1), float64(m.width), 1, borderColor)
-	ebitenutil.DrawRect(screen, float64(m.x), float64(m.y), 1, float64(m.height), borderColor)
-	ebitenutil.DrawRect(screen, float64(m.x+m.width-1), float64(m.y), 1, float64(m.height), borderColor)
+	draw.StrokeRect(screen, float64(m.x), float64(m.y), float64(m.width), float64(m.height), 1, borderColor)
 
-	// Calculate scale
-	mapWidth := state.MapData.Width() * state.MapData.TileWidth()
-	mapHeight := state.MapData.Height() * state.MapData.TileHeight()
-
-	scaleX := float64(m.width) / float64(mapWidth)
-	scaleY := float64(m.height) / float64(mapHeight)
-	scale := scaleX
-	if scaleY < scaleX {
-		scale = scaleY
-	}
-
-	// Draw objects as small rectangles
-	for _, obj := range state.Objects {
-		// Get color from schema
-		schema := GetSchema(obj.Type)
+	// Draw objects as colored dots at their centers
+	for i := range state.Objects {
+		obj := &state.Objects[i]
+		if !state.IsObjectVisible(obj) {
+			continue
+		}
 		objColor := objectDefaultColor
-		if schema != nil {
+		if schema := GetSchema(obj.Type); schema != nil {
 			objColor = parseColor(schema.Color)
 		}
+		cx := float64(m.x) + (obj.X+obj.W/2)*scale
+		cy := float64(m.y) + (obj.Y+obj.H/2)*scale
+		draw.FillRect(screen, cx-minimapDotSize/2, cy-minimapDotSize/2, minimapDotSize, minimapDotSize, objColor)
+	}
 
-		// Calculate minimap position
-		mx := m.x + int(obj.X*scale)
-		my := m.y + int(obj.Y*scale)
-		mw := int(obj.W * scale)
-		mh := int(obj.H * scale)
-
-		// Ensure minimum size of 2 pixels
-		if mw < 2 {
-			mw = 2
-		}
-		if mh < 2 {
-			mh = 2
+	// Draw viewport rectangle, clamped to the minimap
+	vx, vy, vw, vh := viewport(camera, canvasWidth, screenHeight)
+	x1 := max(float64(m.x), float64(m.x)+vx*scale)
+	y1 := max(float64(m.y), float64(m.y)+vy*scale)
+	x2 := min(float64(m.x+m.width), float64(m.x)+(vx+vw)*scale)
+	y2 := min(float64(m.y+m.height), float64(m.y)+(vy+vh)*scale)
+	if x2 > x1 && y2 > y1 {
+		c := minimapViewportColor
+		if m.dragging {
+			c = minimapViewportDrag
 		}
-
-		// Draw object
-		objRect := ebiten.NewImage(mw, mh)
-		objRect.Fill(objColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(mx), float64(my))
-		screen.DrawImage(objRect, op)
+		draw.StrokeRect(screen, x1, y1, x2-x1, y2-y1, 1, c)
 	}
+}
+
+// contains reports whether a screen position is on the minimap.
+func (m *Minimap) contains(x, y int) bool {
+	return x >= m.x && x < m.x+m.width && y >= m.y && y < m.y+m.height
+}
 
-	// Draw viewport rectangle
-	screenWidthActual, screenHeightActual := screen.Size()
-	viewportX := m.x + int(camera.X*scale)
-	viewportY := m.y + int(camera.Y*scale)
-	viewportW := int(float64(screenWidthActual-PaletteWidth-ObjectPaletteWidth) * scale / camera.Zoom)
-	viewportH := int(float64(screenHeightActual) * scale / camera.Zoom)
+// toWorld converts a screen position on the minimap to world coordinates.
+func (m *Minimap) toWorld(state *EditorState, x, y int) (float64, float64) {
+	scale := m.scale(state)
+	return float64(x-m.x) / scale, float64(y-m.y) / scale
+}
 
-	// Clamp viewport to minimap bounds
-	if viewportX < m.x {
-		viewportW -= m.x - viewportX
-		viewportX = m.x
+// HandleInput lets the viewport rectangle be dragged to scroll. Pressing
+// inside the rectangle grabs it where it was clicked; pressing elsewhere on
+// the minimap centers the view there first. Returns true while the minimap
+// has the mouse, so the canvas should ignore it.
+func (m *Minimap) HandleInput(mx, my int, state *EditorState, camera *Camera, canvasWidth, canvasHeight int) bool {
+	if state == nil || !state.HasLevel() {
+		m.dragging = false
+		return false
 	}
-	if viewportY < m.y {
-		viewportH -= m.y - viewportY
-		viewportY = m.y
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && m.contains(mx, my) {
+		wx, wy := m.toWorld(state, mx, my)
+		vx, vy, vw, vh := viewport(camera, canvasWidth, canvasHeight)
+		if wx < vx || wx >= vx+vw || wy < vy || wy >= vy+vh {
+			m.HandleClick(mx, my, state, camera, canvasWidth, canvasHeight)
+			vx, vy = camera.X, camera.Y
+		}
+		m.dragging = true
+		m.dragOffX, m.dragOffY = wx-vx, wy-vy
+		return true
 	}
-	if viewportX+viewportW > m.x+m.width {
-		viewportW = m.x + m.width - viewportX
+
+	if !m.dragging {
+		return false
 	}
-	if viewportY+viewportH > m.y+m.height {
-		viewportH = m.y + m.height - viewportY
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		m.dragging = false
+		return true
 	}
 
-	// Draw viewport rectangle
-	if viewportW > 0 && viewportH > 0 {
-		viewportColor := color.RGBA{255, 255, 255, 150}
-		ebitenutil.DrawRect(screen, float64(viewportX), float64(viewportY), float64(viewportW), 1, viewportColor)
-		ebitenutil.DrawRect(screen, float64(viewportX), float64(viewportY+viewportH-1), float64(viewportW), 1, viewportColor)
-		ebitenutil.DrawRect(screen, float64(viewportX), float64(viewportY), 1, float64(viewportH), viewportColor)
-		ebitenutil.DrawRect(screen, float64(viewportX+viewportW-1), float64(viewportY), 1, float64(viewportH), viewportColor)
-	}
+	wx, wy := m.toWorld(state, mx, my)
+	camera.X = wx - m.dragOffX
+	camera.Y = wy - m.dragOffY
+	return true
 }
 
 // HandleClick processes a click on the minimap to jump to that location.
@@ -135,31 +310,12 @@ func (m *Minimap) HandleClick(clickX, clickY int, state *EditorState, camera *Ca
 	}
 
 	// Check if click is within minimap bounds
-	if clickX < m.x || clickX >= m.x+m.width {
+	if !m.contains(clickX, clickY) {
 		return false
 	}
-	if clickY < m.y || clickY >= m.y+m.height {
-		return false
-	}
-
-	// Calculate scale
-	mapWidth := state.MapData.Width() * state.MapData.TileWidth()
-	mapHeight := state.MapData.Height() * state.MapData.TileHeight()
-
-	scaleX := float64(m.width) / float64(mapWidth)
-	scaleY := float64(m.height) / float64(mapHeight)
-	scale := scaleX
-	if scaleY < scaleX {
-		scale = scaleY
-	}
-
-	// Calculate position within minimap
-	minimapClickX := float64(clickX - m.x)
-	minimapClickY := float64(clickY - m.y)
 
 	// Convert to world coordinates
-	worldX := minimapClickX / scale
-	worldY := minimapClickY / scale
+	worldX, worldY := m.toWorld(state, clickX, clickY)
 
 	// Center camera on this position
 	camera.X = worldX - float64(canvasWidth)/2/camera.Zoom
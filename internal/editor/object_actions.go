@@ -4,6 +4,7 @@ package editor
 import (
 	"fmt"
 
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -141,19 +142,53 @@ func NewResizeObjectAction(objectIndex int, oldW, oldH, newW, newH float64) *Res
 // Do resizes the object to the new dimensions.
 func (a *ResizeObjectAction) Do(state *EditorState) {
 	if a.ObjectIndex >= 0 && a.ObjectIndex < len(state.Objects) {
-		state.Objects[a.ObjectIndex].W = a.NewW
-		state.Objects[a.ObjectIndex].H = a.NewH
+		setObjectSize(&state.Objects[a.ObjectIndex], a.NewW, a.NewH)
 	}
 }
 
 // Undo resizes the object back to the old dimensions.
 func (a *ResizeObjectAction) Undo(state *EditorState) {
 	if a.ObjectIndex >= 0 && a.ObjectIndex < len(state.Objects) {
-		state.Objects[a.ObjectIndex].W = a.OldW
-		state.Objects[a.ObjectIndex].H = a.OldH
+		setObjectSize(&state.Objects[a.ObjectIndex], a.OldW, a.OldH)
 	}
 }
 
+// setObjectSize resizes an object, scaling a polygon's or polyline's points
+// with it. A shape with no extent along an axis keeps it, and a point has
+// no size.
+func setObjectSize(obj *world.ObjectData, w, h float64) {
+	if obj.IsShape() {
+		sx, sy := 1.0, 1.0
+		if obj.W > 0 {
+			sx = w / obj.W
+		}
+		if obj.H > 0 {
+			sy = h / obj.H
+		}
+		scale := func(points []mathx.Vec2) []mathx.Vec2 {
+			if len(points) == 0 {
+				return nil
+			}
+			out := make([]mathx.Vec2, len(points))
+			for i, p := range points {
+				out[i] = mathx.V(p.X*sx, p.Y*sy)
+			}
+			return out
+		}
+		obj.Polygon, obj.Polyline = scale(obj.Polygon), scale(obj.Polyline)
+		if obj.W == 0 {
+			w = 0
+		}
+		if obj.H == 0 {
+			h = 0
+		}
+	}
+	if obj.Point {
+		w, h = 0, 0
+	}
+	obj.W, obj.H = w, h
+}
+
 // Description returns a human-readable description.
 func (a *ResizeObjectAction) Description() string {
 	return fmt.Sprintf("Resize object to %.0fx%.0f", a.NewW, a.NewH)
@@ -269,8 +304,7 @@ func (a *MoveAndResizeObjectAction) Do(state *EditorState) {
 	if a.ObjectIndex >= 0 && a.ObjectIndex < len(state.Objects) {
 		state.Objects[a.ObjectIndex].X = a.NewX
 		state.Objects[a.ObjectIndex].Y = a.NewY
-		state.Objects[a.ObjectIndex].W = a.NewW
-		state.Objects[a.ObjectIndex].H = a.NewH
+		setObjectSize(&state.Objects[a.ObjectIndex], a.NewW, a.NewH)
 	}
 }
 
@@ -279,8 +313,7 @@ func (a *MoveAndResizeObjectAction) Undo(state *EditorState) {
 	if a.ObjectIndex >= 0 && a.ObjectIndex < len(state.Objects) {
 		state.Objects[a.ObjectIndex].X = a.OldX
 		state.Objects[a.ObjectIndex].Y = a.OldY
-		state.Objects[a.ObjectIndex].W = a.OldW
-		state.Objects[a.ObjectIndex].H = a.OldH
+		setObjectSize(&state.Objects[a.ObjectIndex], a.OldW, a.OldH)
 	}
 }
 
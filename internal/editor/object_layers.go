@@ -0,0 +1,160 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// suggestedObjectLayers are the names AddObjectLayer picks first.
+var suggestedObjectLayers = []string{"Gameplay", "Triggers", "Decoration"}
+
+// ObjectLayerNames returns the object layers in order, with the default
+// layer if the level has none.
+func (s *EditorState) ObjectLayerNames() []string {
+	if len(s.ObjectLayers) == 0 {
+		return []string{world.DefaultObjectLayer}
+	}
+	return s.ObjectLayers
+}
+
+// CurrentObjectLayer returns the layer new objects are placed on.
+func (s *EditorState) CurrentObjectLayer() string {
+	if s.hasObjectLayer(s.ActiveObjectLayer) {
+		return s.ActiveObjectLayer
+	}
+	return s.ObjectLayerNames()[0]
+}
+
+// ObjectLayerOf returns the layer an object is on. Objects without a layer,
+// or on one that no longer exists, are on the first layer.
+func (s *EditorState) ObjectLayerOf(obj *world.ObjectData) string {
+	if s.hasObjectLayer(obj.Layer) {
+		return obj.Layer
+	}
+	return s.ObjectLayerNames()[0]
+}
+
+// hasObjectLayer returns whether name is one of the object layers.
+func (s *EditorState) hasObjectLayer(name string) bool {
+	for _, l := range s.ObjectLayerNames() {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsObjectLayerVisible returns whether the objects on a layer are shown.
+func (s *EditorState) IsObjectLayerVisible(name string) bool {
+	return !s.hiddenObjectLayers[name]
+}
+
+// IsObjectVisible returns whether an object's layer is shown. Hidden objects
+// aren't drawn and can't be clicked.
+func (s *EditorState) IsObjectVisible(obj *world.ObjectData) bool {
+	return s.IsObjectLayerVisible(s.ObjectLayerOf(obj))
+}
+
+// ToggleObjectLayerVisibility shows or hides the active object layer.
+func (s *EditorState) ToggleObjectLayerVisibility() {
+	if s.hiddenObjectLayers == nil {
+		s.hiddenObjectLayers = make(map[string]bool)
+	}
+	layer := s.CurrentObjectLayer()
+	s.hiddenObjectLayers[layer] = !s.hiddenObjectLayers[layer]
+}
+
+// CycleObjectLayer makes the next object layer the active one.
+func (s *EditorState) CycleObjectLayer() {
+	layers := s.ObjectLayerNames()
+	current := s.CurrentObjectLayer()
+	for i, layer := range layers {
+		if layer == current {
+			s.ActiveObjectLayer = layers[(i+1)%len(layers)]
+			return
+		}
+	}
+	s.ActiveObjectLayer = layers[0]
+}
+
+// AddObjectLayer adds a new object layer after the existing ones, makes it
+// active and returns its name.
+func (s *EditorState) AddObjectLayer() string {
+	s.ObjectLayers = append([]string(nil), s.ObjectLayerNames()...)
+
+	name := ""
+	for _, n := range suggestedObjectLayers {
+		if !s.hasObjectLayer(n) {
+			name = n
+			break
+		}
+	}
+	for i := len(s.ObjectLayers) + 1; name == ""; i++ {
+		if n := fmt.Sprintf("Layer %d", i); !s.hasObjectLayer(n) {
+			name = n
+		}
+	}
+
+	s.ObjectLayers = append(s.ObjectLayers, name)
+	s.ActiveObjectLayer = name
+	s.SetModified(true)
+	return name
+}
+
+// objectLayersFromTiled returns the names of a level's object layers in order,
+// including empty ones.
+func objectLayersFromTiled(tiledJSON *TiledJSON) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, layer := range tiledJSON.Layers {
+		if layer.Type != "objectgroup" || seen[layer.Name] {
+			continue
+		}
+		seen[layer.Name] = true
+		names = append(names, layer.Name)
+	}
+	return names
+}
+
+// NewMoveToLayerAction creates an action that moves objects onto a layer.
+func NewMoveToLayerAction(state *EditorState, indices []int, layer string) Action {
+	actions := make([]Action, 0, len(indices))
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(state.Objects) {
+			continue
+		}
+		actions = append(actions, &SetObjectLayerAction{
+			ObjectIndex: idx,
+			OldLayer:    state.Objects[idx].Layer,
+			NewLayer:    layer,
+		})
+	}
+	return NewCompositeAction(fmt.Sprintf("Move to layer %s", layer), actions...)
+}
+
+// SetObjectLayerAction moves an object onto another layer.
+type SetObjectLayerAction struct {
+	ObjectIndex int
+	OldLayer    string
+	NewLayer    string
+}
+
+// Do moves the object onto the new layer.
+func (a *SetObjectLayerAction) Do(state *EditorState) {
+	if a.ObjectIndex >= 0 && a.ObjectIndex < len(state.Objects) {
+		state.Objects[a.ObjectIndex].Layer = a.NewLayer
+	}
+}
+
+// Undo moves the object back onto its old layer.
+func (a *SetObjectLayerAction) Undo(state *EditorState) {
+	if a.ObjectIndex >= 0 && a.ObjectIndex < len(state.Objects) {
+		state.Objects[a.ObjectIndex].Layer = a.OldLayer
+	}
+}
+
+// Description returns a human-readable description.
+func (a *SetObjectLayerAction) Description() string {
+	return fmt.Sprintf("Move object to layer %s", a.NewLayer)
+}
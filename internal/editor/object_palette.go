@@ -1,11 +1,13 @@
 package editor
 
 import (
+	"fmt"
 	"image/color"
 	"strconv"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -18,24 +20,49 @@ const (
 	ObjectButtonHeight = 40
 	// ObjectButtonSpacing is the spacing between object buttons.
 	ObjectButtonSpacing = 4
+	// prefabTabX is the offset of the Prefabs tab from the palette's left edge.
+	prefabTabX = 80
+	// prefabNameWidth is the most characters of a prefab's name that fit on
+	// its button.
+	prefabNameWidth = 17
 )
 
 // ObjectPalette handles rendering and interaction for the object type palette.
+// Its Prefabs tab lists the prefab library instead of the object types.
 type ObjectPalette struct {
-	selectedType world.ObjectType
-	hoveredIndex int
-	schemas      []*ObjectSchema
+	selectedType   world.ObjectType
+	hoveredIndex   int
+	schemas        []*ObjectSchema
+	prefabs        *PrefabLibrary
+	showPrefabs    bool // Prefabs tab shown
+	selectedPrefab int  // Index in prefabs, -1 = none
+	linkedPair     bool // Placing a linked switch and door
 }
 
 // NewObjectPalette creates a new object palette.
 func NewObjectPalette() *ObjectPalette {
 	return &ObjectPalette{
-		selectedType: world.ObjectTypeSpawn,
-		hoveredIndex: -1,
-		schemas:      GetAllSchemas(),
+		selectedType:   world.ObjectTypeSpawn,
+		hoveredIndex:   -1,
+		schemas:        GetAllSchemas(),
+		selectedPrefab: -1,
 	}
 }
 
+// SetPrefabs sets the library listed in the Prefabs tab.
+func (p *ObjectPalette) SetPrefabs(lib *PrefabLibrary) {
+	p.prefabs = lib
+	p.selectedPrefab = -1
+}
+
+// SelectedPrefab returns the prefab selected for placing, if any.
+func (p *ObjectPalette) SelectedPrefab() (Prefab, bool) {
+	if p.prefabs == nil || p.selectedPrefab < 0 || p.selectedPrefab >= len(p.prefabs.Prefabs) {
+		return Prefab{}, false
+	}
+	return p.prefabs.Prefabs[p.selectedPrefab], true
+}
+
 // SelectedType returns the currently selected object type.
 func (p *ObjectPalette) SelectedType() world.ObjectType {
 	return p.selectedType
@@ -44,11 +71,51 @@ func (p *ObjectPalette) SelectedType() world.ObjectType {
 // SetSelectedType sets the selected object type.
 func (p *ObjectPalette) SetSelectedType(typ world.ObjectType) {
 	p.selectedType = typ
+	p.selectedPrefab = -1
+	p.linkedPair = false
+}
+
+// SelectLinkedPair selects placing a switch and a door linked to each other.
+func (p *ObjectPalette) SelectLinkedPair() {
+	p.selectedType = ""
+	p.selectedPrefab = -1
+	p.linkedPair = true
+}
+
+// IsLinkedPair returns whether a linked switch and door are being placed.
+func (p *ObjectPalette) IsLinkedPair() bool {
+	return p.linkedPair
 }
 
-// ClearSelection clears the current object type selection.
+// ClearSelection clears the current object type, prefab or linked pair
+// selection.
 func (p *ObjectPalette) ClearSelection() {
 	p.selectedType = ""
+	p.selectedPrefab = -1
+	p.linkedPair = false
+}
+
+// itemCount returns the number of buttons in the shown tab.
+func (p *ObjectPalette) itemCount() int {
+	if !p.showPrefabs {
+		return len(p.schemas)
+	}
+	if p.prefabs == nil {
+		return 0
+	}
+	return len(p.prefabs.Prefabs)
+}
+
+// itemAt returns the index of the button at screenY, or -1.
+func (p *ObjectPalette) itemAt(screenY, startY int) int {
+	buttonY := startY + ObjectPalettePadding + 20
+	for i := 0; i < p.itemCount(); i++ {
+		y := buttonY + i*(ObjectButtonHeight+ObjectButtonSpacing)
+		if screenY >= y && screenY < y+ObjectButtonHeight {
+			return i
+		}
+	}
+	return -1
 }
 
 // Draw renders the object palette to the screen.
@@ -61,24 +128,64 @@ func (p *ObjectPalette) Draw(screen *ebiten.Image, startY int) {
 	paletteX := screenWidth - ObjectPaletteWidth
 
 	// Draw palette background
-	paletteBg := ebiten.NewImage(ObjectPaletteWidth, screenHeight-startY)
-	paletteBg.Fill(objectPaletteBgColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(paletteX), float64(startY))
-	screen.DrawImage(paletteBg, op)
+	draw.FillRect(screen, float64(paletteX), float64(startY), float64(ObjectPaletteWidth), float64(screenHeight-startY), objectPaletteBgColor)
 
-	// Draw title
+	// Draw the tabs, underlining the shown one
 	titleY := startY + ObjectPalettePadding
 	ebitenutil.DebugPrintAt(screen, "Objects", paletteX+ObjectPalettePadding, titleY)
+	ebitenutil.DebugPrintAt(screen, "Prefabs", paletteX+prefabTabX, titleY)
+	tabX := paletteX + ObjectPalettePadding
+	if p.showPrefabs {
+		tabX = paletteX + prefabTabX
+	}
+	draw.FillRect(screen, float64(tabX), float64(titleY+15), 42, 1, objectButtonSelectedColor)
 
-	// Draw object type buttons
 	buttonY := titleY + 20
+	if p.showPrefabs {
+		p.drawPrefabButtons(screen, paletteX+ObjectPalettePadding, buttonY)
+		return
+	}
+
+	// Draw object type buttons
 	for i, schema := range p.schemas {
 		y := buttonY + i*(ObjectButtonHeight+ObjectButtonSpacing)
 		p.drawButton(screen, paletteX+ObjectPalettePadding, y, schema, i)
 	}
 }
 
+// drawPrefabButtons renders a button for each prefab in the library, with the
+// color of its first object's type and its object count.
+func (p *ObjectPalette) drawPrefabButtons(screen *ebiten.Image, x, y int) {
+	if p.prefabs == nil || len(p.prefabs.Prefabs) == 0 {
+		ebitenutil.DebugPrintAt(screen, "Select objects and\npress Ctrl+P to\nsave a prefab", x, y)
+		return
+	}
+
+	buttonWidth := ObjectPaletteWidth - 2*ObjectPalettePadding
+	for i, prefab := range p.prefabs.Prefabs {
+		by := y + i*(ObjectButtonHeight+ObjectButtonSpacing)
+		bgColor := objectButtonColor
+		if p.selectedPrefab == i {
+			bgColor = objectButtonSelectedColor
+		} else if p.hoveredIndex == i {
+			bgColor = objectButtonHoverColor
+		}
+		draw.FillRect(screen, float64(x), float64(by), float64(buttonWidth), float64(ObjectButtonHeight), bgColor)
+
+		indicatorColor := color.RGBA{128, 128, 128, 255}
+		if schema := GetSchema(prefab.Objects[0].Type); schema != nil {
+			indicatorColor = parseColor(schema.Color)
+		}
+		draw.FillRect(screen, float64(x+4), float64(by+12), 16, 16, indicatorColor)
+		name := prefab.Name
+		if len(name) > prefabNameWidth {
+			name = name[:prefabNameWidth-1] + "~"
+		}
+		ebitenutil.DebugPrintAt(screen, name, x+26, by+6)
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d objects", len(prefab.Objects)), x+26, by+20)
+	}
+}
+
 // drawButton renders a single object type button.
 func (p *ObjectPalette) drawButton(screen *ebiten.Image, x, y int, schema *ObjectSchema, index int) {
 	buttonWidth := ObjectPaletteWidth - 2*ObjectPalettePadding
@@ -92,22 +199,14 @@ func (p *ObjectPalette) drawButton(screen *ebiten.Image, x, y int, schema *Objec
 	}
 
 	// Draw button background
-	buttonImg := ebiten.NewImage(buttonWidth, ObjectButtonHeight)
-	buttonImg.Fill(bgColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	screen.DrawImage(buttonImg, op)
+	draw.FillRect(screen, float64(x), float64(y), float64(buttonWidth), float64(ObjectButtonHeight), bgColor)
 
 	// Draw color indicator (small colored rectangle)
 	indicatorSize := 16
 	indicatorX := x + 4
 	indicatorY := y + (ObjectButtonHeight-indicatorSize)/2
 	indicatorColor := parseColor(schema.Color)
-	indicatorImg := ebiten.NewImage(indicatorSize, indicatorSize)
-	indicatorImg.Fill(indicatorColor)
-	op = &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(indicatorX), float64(indicatorY))
-	screen.DrawImage(indicatorImg, op)
+	draw.FillRect(screen, float64(indicatorX), float64(indicatorY), float64(indicatorSize), float64(indicatorSize), indicatorColor)
 
 	// Draw object name
 	nameX := indicatorX + indicatorSize + 6
@@ -127,20 +226,34 @@ func (p *ObjectPalette) HandleClick(screenX, screenY, screenWidth, startY int) b
 		return false
 	}
 
-	// Calculate button positions
+	// Clicking a tab switches the list
 	titleY := startY + ObjectPalettePadding
-	buttonY := titleY + 20
+	if screenY < titleY+20 {
+		p.showPrefabs = screenX >= paletteX+prefabTabX-ObjectPalettePadding
+		p.hoveredIndex = -1
+		return false
+	}
 
-	// Check each button
-	for i, schema := range p.schemas {
-		y := buttonY + i*(ObjectButtonHeight+ObjectButtonSpacing)
-		if screenY >= y && screenY < y+ObjectButtonHeight {
-			p.selectedType = world.ObjectType(schema.Type)
-			return true
-		}
+	i := p.itemAt(screenY, startY)
+	if i < 0 {
+		return false
+	}
+	if p.showPrefabs {
+		p.ClearSelection()
+		p.selectedPrefab = i
+	} else {
+		p.SetSelectedType(world.ObjectType(p.schemas[i].Type))
 	}
+	return true
+}
 
-	return false
+// PrefabAt returns the index of the prefab button at the screen position, or
+// -1 if there is none (or the Prefabs tab isn't shown).
+func (p *ObjectPalette) PrefabAt(screenX, screenY, screenWidth, startY int) int {
+	if !p.showPrefabs || !p.IsInPalette(screenX, screenY, screenWidth, startY) {
+		return -1
+	}
+	return p.itemAt(screenY, startY)
 }
 
 // HandleMouseMove processes mouse movement for hover effects.
@@ -156,18 +269,7 @@ func (p *ObjectPalette) HandleMouseMove(screenX, screenY, screenWidth, startY in
 		return
 	}
 
-	// Calculate button positions
-	titleY := startY + ObjectPalettePadding
-	buttonY := titleY + 20
-
-	// Check each button
-	for i := range p.schemas {
-		y := buttonY + i*(ObjectButtonHeight+ObjectButtonSpacing)
-		if screenY >= y && screenY < y+ObjectButtonHeight {
-			p.hoveredIndex = i
-			return
-		}
-	}
+	p.hoveredIndex = p.itemAt(screenY, startY)
 }
 
 // IsInPalette returns true if the given screen coordinates are within the object palette area.
@@ -0,0 +1,105 @@
+package editor
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// onionAlpha is the opacity of the onion-skin layer.
+const onionAlpha = 0.4
+
+// onionSavedSource is the onion-skin source showing the level as last
+// saved rather than another layer.
+const onionSavedSource = "Saved"
+
+// onionSkin shows a second tile layer translucently under the active one:
+// another layer of the level, or the active layer as saved on disk to
+// compare edits against.
+type onionSkin struct {
+	source string         // Layer name, onionSavedSource, or "" when off
+	saved  *world.MapData // The level as saved, for onionSavedSource
+	level  *world.MapData // Level the onion skin was chosen for
+}
+
+// CycleOnionSkin switches the onion skin to the next source: each tile
+// layer other than the active one, then the level as last saved when it
+// has a file, then off.
+func (c *Canvas) CycleOnionSkin() {
+	md := c.state.MapData
+	if md == nil {
+		return
+	}
+	sources := []string{""}
+	for _, layer := range md.Layers() {
+		if layer.Name() != c.state.CurrentLayer {
+			sources = append(sources, layer.Name())
+		}
+	}
+	if c.state.FilePath != "" {
+		sources = append(sources, onionSavedSource)
+	}
+
+	next := 0
+	for i, s := range sources {
+		if s == c.onion.source && c.onion.level == md {
+			next = (i + 1) % len(sources)
+		}
+	}
+	c.onion = onionSkin{source: sources[next], level: md}
+	if c.onion.source == onionSavedSource && !c.loadSavedOnion() {
+		c.onion = onionSkin{}
+	}
+}
+
+// loadSavedOnion reads the level as saved for the onion skin. It reports
+// whether that worked.
+func (c *Canvas) loadSavedOnion() bool {
+	data, err := storage.ReadFile(c.state.FilePath)
+	if err != nil {
+		logger.Warnf("Failed to read the saved level for the onion skin: %v", err)
+		return false
+	}
+	saved, err := ParseLevel(data, c.state.FilePath)
+	if err != nil {
+		logger.Warnf("Failed to read the saved level for the onion skin: %v", err)
+		return false
+	}
+	c.onion.saved = saved.MapData
+	return true
+}
+
+// SetOnionSkinOff turns the onion skin off.
+func (c *Canvas) SetOnionSkinOff() {
+	c.onion = onionSkin{}
+}
+
+// OnionSkin returns the onion skin's source, a layer name or "Saved", or
+// "" when it's off or was chosen for another level.
+func (c *Canvas) OnionSkin() string {
+	if c.onion.level != c.state.MapData {
+		return ""
+	}
+	return c.onion.source
+}
+
+// onionLayer returns the layer the onion skin shows, or nil.
+func (c *Canvas) onionLayer() *world.TileLayer {
+	switch source := c.OnionSkin(); source {
+	case "", c.state.CurrentLayer:
+		return nil
+	case onionSavedSource:
+		return c.onion.saved.Layer(c.state.CurrentLayer)
+	default:
+		return c.state.MapData.Layer(source)
+	}
+}
+
+// drawOnionSkin draws the onion-skin layer translucently.
+func (c *Canvas) drawOnionSkin(screen *ebiten.Image, canvasWidth int) {
+	layer := c.onionLayer()
+	if layer == nil {
+		return
+	}
+	c.chunks.DrawLayerAlpha(screen, layer, c.camera.X, c.camera.Y, c.camera.Zoom, canvasWidth, screen.Bounds().Dy(), onionAlpha)
+}
@@ -0,0 +1,358 @@
+package editor
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Outliner panel dimensions
+const (
+	outlinerWidth      = 320
+	outlinerRowHeight  = 14
+	outlinerListOffset = 62 // Distance from the panel top to the first row
+	outlinerFooter     = 30 // Space below the list for the hint line
+)
+
+// OutlineGroup is the objects of one type in the outliner.
+type OutlineGroup struct {
+	Type    world.ObjectType
+	Indices []int // Object indices, in level order
+}
+
+// OutlineObjects groups the objects matching the search query by type, with
+// the types sorted by name. The query matches case-insensitively against the
+// type, the name and every property value, so "door", "exit" and "3" all
+// find things. An empty query matches everything.
+func OutlineObjects(objects []world.ObjectData, query string) []OutlineGroup {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	byType := make(map[world.ObjectType][]int)
+	for i := range objects {
+		if query == "" || objectMatchesSearch(&objects[i], query) {
+			byType[objects[i].Type] = append(byType[objects[i].Type], i)
+		}
+	}
+
+	groups := make([]OutlineGroup, 0, len(byType))
+	for typ, indices := range byType {
+		groups = append(groups, OutlineGroup{Type: typ, Indices: indices})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Type < groups[j].Type })
+	return groups
+}
+
+// objectMatchesSearch reports whether a lower-case query is part of the
+// object's type, name or any property value.
+func objectMatchesSearch(obj *world.ObjectData, query string) bool {
+	if strings.Contains(strings.ToLower(string(obj.Type)), query) ||
+		strings.Contains(strings.ToLower(obj.Name), query) {
+		return true
+	}
+	for _, v := range obj.Props {
+		if strings.Contains(strings.ToLower(formatPropertyValue(v)), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// outlineRow is one line of the outliner list: a type header, or an object.
+type outlineRow struct {
+	group       *OutlineGroup
+	objectIndex int // -1 for headers
+}
+
+// OutlinerPanel lists the level's objects grouped by type, with a search box.
+// Clicking an object selects it and moves the camera to it; F2 renames its id.
+type OutlinerPanel struct {
+	open     bool
+	search   string
+	groups   []OutlineGroup
+	rows     []outlineRow
+	cursor   int // Highlighted row
+	scroll   int // First visible row
+	renaming bool
+	rename   string // Text of the id being typed
+}
+
+// NewOutlinerPanel creates a new, closed outliner.
+func NewOutlinerPanel() *OutlinerPanel {
+	return &OutlinerPanel{}
+}
+
+// Open shows the panel, highlighting the selected object.
+func (p *OutlinerPanel) Open(state *EditorState) {
+	p.open = true
+	p.renaming = false
+	p.refresh(state)
+	for i, row := range p.rows {
+		if row.objectIndex >= 0 && row.objectIndex == state.SelectedObject {
+			p.cursor = i
+		}
+	}
+}
+
+// Close hides the panel.
+func (p *OutlinerPanel) Close() {
+	p.open = false
+	p.renaming = false
+}
+
+// IsOpen returns true if the panel is currently shown.
+func (p *OutlinerPanel) IsOpen() bool {
+	return p.open
+}
+
+// refresh rebuilds the rows from the current search.
+func (p *OutlinerPanel) refresh(state *EditorState) {
+	p.groups = OutlineObjects(state.Objects, p.search)
+	p.rows = p.rows[:0]
+	for gi := range p.groups {
+		g := &p.groups[gi]
+		p.rows = append(p.rows, outlineRow{group: g, objectIndex: -1})
+		for _, idx := range g.Indices {
+			p.rows = append(p.rows, outlineRow{group: g, objectIndex: idx})
+		}
+	}
+	p.cursor = mathx.Clamp(p.cursor, 0, len(p.rows)-1)
+}
+
+// visibleRows returns how many rows fit in a panel of the given height.
+func visibleRows(height int) int {
+	return max(1, (height-outlinerListOffset-outlinerFooter)/outlinerRowHeight)
+}
+
+// Update handles input for the panel. Up/Down move the highlight, Enter or a
+// click jumps to the object, F2 renames the highlighted object's id, and
+// Escape cancels a rename or closes the panel.
+func (p *OutlinerPanel) Update(state *EditorState, camera *Camera, canvasWidth, canvasHeight int) {
+	if !p.open {
+		return
+	}
+
+	if p.renaming {
+		p.updateRename(state)
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		p.Close()
+		return
+	}
+
+	// Search box
+	changed := false
+	for _, c := range ebiten.AppendInputChars(nil) {
+		p.search += string(c)
+		changed = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(p.search) > 0 {
+		p.search = p.search[:len(p.search)-1]
+		changed = true
+	}
+	if changed {
+		p.cursor = 0
+		p.scroll = 0
+		p.refresh(state)
+	}
+
+	// Keyboard navigation
+	if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		p.cursor = mathx.Clamp(p.cursor+1, 0, len(p.rows)-1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		p.cursor = mathx.Clamp(p.cursor-1, 0, len(p.rows)-1)
+	}
+
+	// Mouse wheel scrolls the list
+	rowsShown := visibleRows(canvasHeight)
+	if _, wy := ebiten.Wheel(); wy != 0 {
+		p.scroll -= int(wy * 3)
+	}
+
+	// Clicks on rows
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		row := p.scroll + (my-outlinerListOffset)/outlinerRowHeight
+		if mx < outlinerWidth && my >= outlinerListOffset && row < len(p.rows) && row-p.scroll < rowsShown {
+			p.cursor = row
+			p.jumpTo(state, camera, canvasWidth, canvasHeight)
+		}
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		p.jumpTo(state, camera, canvasWidth, canvasHeight)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+		if obj := p.cursorObject(state); obj != nil {
+			p.renaming = true
+			p.rename = obj.GetPropString("id", "")
+		}
+	}
+
+	// Keep the highlight on screen, and the scroll within the list
+	if p.cursor < p.scroll {
+		p.scroll = p.cursor
+	}
+	if p.cursor >= p.scroll+rowsShown {
+		p.scroll = p.cursor - rowsShown + 1
+	}
+	p.scroll = mathx.Clamp(p.scroll, 0, len(p.rows)-rowsShown)
+}
+
+// cursorObject returns the highlighted object, or nil on a header row.
+func (p *OutlinerPanel) cursorObject(state *EditorState) *world.ObjectData {
+	if p.cursor < 0 || p.cursor >= len(p.rows) {
+		return nil
+	}
+	idx := p.rows[p.cursor].objectIndex
+	if idx < 0 || idx >= len(state.Objects) {
+		return nil
+	}
+	return &state.Objects[idx]
+}
+
+// jumpTo selects the highlighted object and centers the camera on it.
+func (p *OutlinerPanel) jumpTo(state *EditorState, camera *Camera, canvasWidth, canvasHeight int) {
+	obj := p.cursorObject(state)
+	if obj == nil {
+		return
+	}
+	idx := p.rows[p.cursor].objectIndex
+
+	state.SelectObject(idx)
+	if selection := state.GetSelectionManager(); selection != nil {
+		selection.Select(idx)
+	}
+
+	camera.X = obj.X + obj.W/2 - float64(canvasWidth)/2/camera.Zoom
+	camera.Y = obj.Y + obj.H/2 - float64(canvasHeight)/2/camera.Zoom
+}
+
+// updateRename handles typing a new id. Enter applies it as an undoable
+// action unless another object already uses it; Escape cancels.
+func (p *OutlinerPanel) updateRename(state *EditorState) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		p.renaming = false
+		return
+	}
+
+	for _, c := range ebiten.AppendInputChars(nil) {
+		p.rename += string(c)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(p.rename) > 0 {
+		p.rename = p.rename[:len(p.rename)-1]
+	}
+
+	if !inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		return
+	}
+
+	obj := p.cursorObject(state)
+	if obj == nil {
+		p.renaming = false
+		return
+	}
+	idx := p.rows[p.cursor].objectIndex
+	newID := strings.TrimSpace(p.rename)
+
+	var oldID any
+	if v, ok := obj.Props["id"]; ok {
+		oldID = v
+	}
+	if formatPropertyValue(oldID) == newID {
+		p.renaming = false
+		return
+	}
+	if newID != "" {
+		for i := range state.Objects {
+			if i != idx && state.Objects[i].GetPropString("id", "") == newID {
+				state.ShowStatusMessage(i18n.T("editor.idTaken", newID), true)
+				return
+			}
+		}
+	}
+
+	state.History.Do(NewSetPropertyAction(idx, "id", oldID, newID), state)
+	logger.Infof("Renamed %s '%s' to '%s'", obj.Type, formatPropertyValue(oldID), newID)
+	state.ShowStatusMessage(i18n.T("editor.renamed", newID), false)
+	p.renaming = false
+	p.refresh(state)
+}
+
+// Draw renders the panel along the left edge of the canvas.
+func (p *OutlinerPanel) Draw(screen *ebiten.Image, state *EditorState) {
+	if !p.open {
+		return
+	}
+
+	height := screen.Bounds().Dy()
+
+	// Draw background
+	draw.FillRect(screen, 0, 0, outlinerWidth, float64(height), color.RGBA{40, 40, 50, 240})
+	draw.FillRect(screen, outlinerWidth-2, 0, 2, float64(height), color.RGBA{100, 100, 120, 255})
+
+	// Title and search box
+	count := 0
+	for _, g := range p.groups {
+		count += len(g.Indices)
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("OUTLINER  %d/%d objects", count, len(state.Objects)), 10, 8)
+	ebitenutil.DebugPrintAt(screen, "Search:", 10, 32)
+	draw.FillRect(screen, 60, 30, outlinerWidth-75, findReplaceFieldHeight, propertyHoverColor)
+	search := p.search
+	if !p.renaming {
+		search += "|"
+	}
+	ebitenutil.DebugPrintAt(screen, search, 64, 31)
+
+	// Rows
+	rowsShown := visibleRows(height)
+	selection := state.GetSelectionManager()
+	for i := p.scroll; i < len(p.rows) && i < p.scroll+rowsShown; i++ {
+		row := p.rows[i]
+		y := outlinerListOffset + (i-p.scroll)*outlinerRowHeight
+
+		if i == p.cursor {
+			draw.FillRect(screen, 4, float64(y), outlinerWidth-10, outlinerRowHeight, propertyHoverColor)
+		}
+
+		if row.objectIndex < 0 {
+			ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%s (%d)", row.group.Type, len(row.group.Indices)), 10, y-1)
+			continue
+		}
+
+		obj := &state.Objects[row.objectIndex]
+		id := obj.GetPropString("id", "")
+		if i == p.cursor && p.renaming {
+			id = p.rename + "|"
+		} else if id == "" {
+			id = "-"
+		}
+		marker := " "
+		if selection != nil && selection.IsSelected(row.objectIndex) {
+			marker = "*"
+		}
+		line := fmt.Sprintf("%s %-16s #%d (%.0f, %.0f)", marker, id, obj.ID, obj.X, obj.Y)
+		if !state.IsObjectVisible(obj) {
+			line += " hidden"
+		}
+		ebitenutil.DebugPrintAt(screen, line, 18, y-1)
+	}
+
+	// Hint
+	hint := "Click/Enter: Go to  F2: Rename id  Esc: Close"
+	if p.renaming {
+		hint = "Enter: Apply id  Esc: Cancel"
+	}
+	ebitenutil.DebugPrintAt(screen, hint, 10, height-22)
+}
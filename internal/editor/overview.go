@@ -0,0 +1,172 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// OverviewScales are the scales the overview export offers.
+var OverviewScales = []float64{0.25, 0.5, 1, 2, 4}
+
+// overviewMaxSize is the largest overview image side in pixels; scales
+// giving larger images aren't offered.
+const overviewMaxSize = 8192
+
+// overviewBackground fills the overview where no tile is drawn.
+var overviewBackground = color.RGBA{30, 30, 40, 255}
+
+// RenderOverview renders the whole level offscreen at scale: the visible
+// tile layers, the collision overlay if shown, and the visible objects, as
+// the canvas draws them but without the grid, selection and validation
+// markers.
+func (c *Canvas) RenderOverview(scale float64) *ebiten.Image {
+	md := c.state.MapData
+	w := int(math.Ceil(float64(md.Width()*md.TileWidth()) * scale))
+	h := int(math.Ceil(float64(md.Height()*md.TileHeight()) * scale))
+
+	img := ebiten.NewImage(w, h)
+	img.Fill(overviewBackground)
+
+	// A canvas of its own with a camera on the whole level, sharing the
+	// level, tileset and display options
+	oc := &Canvas{
+		state:         c.state,
+		camera:        &Camera{Zoom: scale},
+		tileset:       c.tileset,
+		tools:         c.tools,
+		showCollision: c.showCollision,
+		showAllLinks:  c.showAllLinks,
+		overview:      true,
+		hoveredTileX:  -1,
+		hoveredTileY:  -1,
+	}
+	oc.drawTileLayers(img, w)
+	if oc.showCollision {
+		oc.drawCollisionOverlay(img, w)
+	}
+	oc.drawObjects(img, w)
+	return img
+}
+
+// ExportOverview renders the level at scale and writes it as a PNG to path.
+// It returns the image size.
+func (c *Canvas) ExportOverview(path string, scale float64) (int, int, error) {
+	img := c.RenderOverview(scale)
+	defer img.Deallocate()
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	img.ReadPixels(rgba.Pix)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgba); err != nil {
+		return 0, 0, fmt.Errorf("failed to encode overview: %w", err)
+	}
+	if err := storage.WriteFile(path, buf.Bytes()); err != nil {
+		return 0, 0, fmt.Errorf("failed to write overview: %w", err)
+	}
+	return w, h, nil
+}
+
+// OverviewDialog picks the scale to export the level overview at. Left and
+// Right change the scale, Enter exports, Escape cancels.
+type OverviewDialog struct {
+	open     bool
+	path     string    // Image file to export to
+	scales   []float64 // Scales that fit overviewMaxSize
+	selected int
+	levelW   int // Level size in pixels
+	levelH   int
+}
+
+// NewOverviewDialog creates a closed overview dialog.
+func NewOverviewDialog() *OverviewDialog {
+	return &OverviewDialog{}
+}
+
+// IsOpen reports whether the dialog is shown.
+func (d *OverviewDialog) IsOpen() bool {
+	return d.open
+}
+
+// Open shows the dialog for the level in state, preselecting scale 1 (or
+// the largest one that fits).
+func (d *OverviewDialog) Open(state *EditorState) {
+	md := state.MapData
+	d.levelW, d.levelH = md.Width()*md.TileWidth(), md.Height()*md.TileHeight()
+	// Next to the level (world.OverviewPath) for the world map's thumbnail
+	d.path = "overview.png"
+	if state.FilePath != "" {
+		d.path = world.OverviewPath(state.FilePath)
+	}
+
+	d.scales = d.scales[:0]
+	d.selected = 0
+	for _, s := range OverviewScales {
+		if float64(max(d.levelW, d.levelH))*s > overviewMaxSize && len(d.scales) > 0 {
+			break
+		}
+		if s <= 1 {
+			d.selected = len(d.scales)
+		}
+		d.scales = append(d.scales, s)
+	}
+	d.open = true
+}
+
+// Update handles the dialog's keys. It returns the chosen scale and true
+// when the user confirms; the dialog is then closed.
+func (d *OverviewDialog) Update() (float64, bool) {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		d.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) && d.selected > 0:
+		d.selected--
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) && d.selected < len(d.scales)-1:
+		d.selected++
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		d.open = false
+		return d.scales[d.selected], true
+	}
+	return 0, false
+}
+
+// Path returns the file the overview is exported to.
+func (d *OverviewDialog) Path() string {
+	return d.path
+}
+
+// Draw draws the dialog centered on screen.
+func (d *OverviewDialog) Draw(screen *ebiten.Image) {
+	if !d.open {
+		return
+	}
+	screenWidth, screenHeight := screen.Size()
+
+	overlayWidth := 360
+	overlayHeight := 100
+	overlayX := (screenWidth - overlayWidth) / 2
+	overlayY := (screenHeight - overlayHeight) / 2
+
+	draw.FillRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), float64(overlayHeight), color.RGBA{40, 40, 50, 240})
+	draw.StrokeRect(screen, float64(overlayX), float64(overlayY), float64(overlayWidth), float64(overlayHeight), 2, color.RGBA{100, 150, 200, 255})
+
+	scale := d.scales[d.selected]
+	w := int(math.Ceil(float64(d.levelW) * scale))
+	h := int(math.Ceil(float64(d.levelH) * scale))
+	ebitenutil.DebugPrintAt(screen, "Export Overview Image", overlayX+20, overlayY+12)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Scale: < %gx >  (%dx%d)", scale, w, h), overlayX+20, overlayY+36)
+	ebitenutil.DebugPrintAt(screen, "To: "+d.path, overlayX+20, overlayY+54)
+	ebitenutil.DebugPrintAt(screen, "Enter: Export    Escape: Cancel", overlayX+20, overlayY+76)
+}
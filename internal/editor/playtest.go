@@ -1,23 +1,33 @@
 package editor
 
 import (
+	"bytes"
 	"fmt"
 	"image/color"
-	"log"
+	"sort"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/arena"
 	"github.com/torsten/GoP/internal/assets"
 	"github.com/torsten/GoP/internal/camera"
+	"github.com/torsten/GoP/internal/debugui"
 	"github.com/torsten/GoP/internal/entities"
 	"github.com/torsten/GoP/internal/game"
 	"github.com/torsten/GoP/internal/gameplay"
 	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
 	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/music"
 	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/sound"
+	"github.com/torsten/GoP/internal/theme"
 	timestep "github.com/torsten/GoP/internal/time"
+	"github.com/torsten/GoP/internal/tween"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -25,6 +35,12 @@ import (
 const (
 	playtestFrameDuration = 100 * time.Millisecond
 	playtestPlayerSize    = 12
+	playtestTraceSize     = 10                     // Number of rule firings shown in the tracer overlay
+	playtestFlashDuration = 150 * time.Millisecond // White flash shown when the player dies
+
+	playtestGoalMessageDuration = 2.0 // Seconds the "goal locked" message stays visible
+	playtestRespawnGlideTime    = 0.4 // Seconds the camera takes to glide back to the respawn point
+	playtestRulesPollInterval   = 1.0 // Seconds between checks of the rules file for edits
 )
 
 // Colors for playtest rendering
@@ -59,11 +75,31 @@ type PlaytestController struct {
 	playerBody   *physics.Body
 	playerCtrl   *physics.Controller
 	resolver     *physics.CollisionResolver
+	collisions   arena.Arena[physics.Collision] // Reset every FixedUpdate
+	solidAABBs   []physics.AABB                 // Reused by resolveSolidEntityCollisions
 	state        *gameplay.StateMachine
+	health       *gameplay.Health
 	tuning       game.Tuning
 	timestep     *timestep.Timestep
+	lastStep     time.Time // When Update last ran the fixed steps, for interpolation
 	sprite       *gfx.Sprite
 	animator     *gfx.Animator
+	deathBurst   *gfx.Burst // Particles where the player last died
+	ruleEngine   *rules.Engine
+	ruleTracer   *rules.Tracer
+	rulesPath    string  // Level's rules file, watched for edits
+	rulesData    []byte  // Rules file contents last loaded
+	rulesPoll    float64 // Seconds until the rules file is checked again
+	bounds       world.LevelBounds
+	skins        map[world.ObjectType]*entities.Skin
+	lighting     *gfx.LightLayer
+	ambient      float64 // Ambient darkness (0 = lighting off)
+	music        *music.Mixer
+	sounds       *sound.Soundscape
+	progress     *gameplay.LevelProgress
+	vars         *gameplay.Blackboard      // Gameplay variables shared with rules and the HUD
+	checkpoint   *gameplay.CheckpointState // Restored on respawn
+	levels       *gameplay.LevelManager    // Fades for exits within the level
 
 	// State
 	isActive      bool
@@ -71,6 +107,22 @@ type PlaytestController struct {
 	height        int
 	initialSpawnX float64
 	initialSpawnY float64
+	showRuleTrace bool                // Show the rules tracer overlay (F7)
+	paused        bool                // Debug pause (F8), F9 runs one fixed update
+	inspector     *entities.Inspector // Click an entity while paused to inspect it
+	tuningOverlay *TuningOverlay      // Live tuning adjustment (T)
+
+	recording  *PlaytestRecording // Path, jumps and deaths, handed to the canvas on exit
+	wasJumping bool               // Jump state last tick, to record jump starts
+
+	// Play from here: spawn at a chosen world position instead of the
+	// level's spawn point
+	spawnOverride  bool
+	spawnOverrideX float64
+	spawnOverrideY float64
+
+	goalMessage      string  // Why the goal is still locked
+	goalMessageTimer float64 // Seconds left to show goalMessage
 }
 
 // NewPlaytestController creates a new playtest controller.
@@ -81,9 +133,23 @@ func NewPlaytestController(editor *App) *PlaytestController {
 		tuning:   game.DefaultTuning(),
 		timestep: timestep.NewTimestep(),
 		state:    gameplay.NewStateMachine(),
+		health:   gameplay.NewHealth(game.DefaultTuning().Health),
+
+		tuningOverlay: NewTuningOverlay(""),
+		inspector:     entities.NewInspector(),
 	}
 }
 
+// SetTuning sets the player's movement tuning for playtests.
+func (p *PlaytestController) SetTuning(t game.Tuning) {
+	p.tuning = t
+}
+
+// SetTuningPath sets the file the tuning overlay saves to.
+func (p *PlaytestController) SetTuningPath(path string) {
+	p.tuningOverlay = NewTuningOverlay(path)
+}
+
 // IsActive returns true if playtest mode is currently active.
 func (p *PlaytestController) IsActive() bool {
 	return p.isActive
@@ -94,8 +160,27 @@ func (p *PlaytestController) StartPlaytest() error {
 	if p.isActive {
 		return nil
 	}
+	p.spawnOverride = false
+	return p.startPlaytest()
+}
+
+// StartPlaytestAt starts playtest mode with the player centered on a world
+// position instead of the level's spawn point. Restarts and respawns before
+// the first checkpoint return there too.
+func (p *PlaytestController) StartPlaytestAt(worldX, worldY float64) error {
+	if p.isActive {
+		return nil
+	}
+	p.spawnOverride = true
+	p.spawnOverrideX = worldX - playtestPlayerSize/2
+	p.spawnOverrideY = worldY - playtestPlayerSize/2
+	return p.startPlaytest()
+}
+
+// startPlaytest snapshots the editor and enters playtest mode.
+func (p *PlaytestController) startPlaytest() error {
 
-	log.Println("Entering playtest mode...")
+	logger.Infof("Entering playtest mode...")
 
 	// 1. Save current editor state
 	p.savedState = p.CreateSnapshot()
@@ -105,10 +190,15 @@ func (p *PlaytestController) StartPlaytest() error {
 		return fmt.Errorf("failed to build game scene: %w", err)
 	}
 
-	// 3. Transition to playtest mode
+	// 3. Start recording the player's path
+	p.recording = NewPlaytestRecording()
+	p.wasJumping = false
+
+	// 4. Transition to playtest mode
 	p.isActive = true
+	p.paused = false
 
-	log.Println("Playtest mode active. Press Escape to return to editor, R to restart.")
+	logger.Infof("Playtest mode active. Press Escape to return to editor, R to restart.")
 	return nil
 }
 
@@ -118,7 +208,7 @@ func (p *PlaytestController) EndPlaytest() {
 		return
 	}
 
-	log.Println("Exiting playtest mode...")
+	logger.Infof("Exiting playtest mode...")
 
 	// 1. Clean up game scene resources
 	p.cleanupGameScene()
@@ -129,10 +219,14 @@ func (p *PlaytestController) EndPlaytest() {
 	// 3. Clear saved state
 	p.savedState = nil
 
-	// 4. Return to edit mode
+	// 4. Show the recorded path on the canvas
+	p.editor.Canvas().SetPlaytestRecording(p.recording)
+	p.recording = nil
+
+	// 5. Return to edit mode
 	p.isActive = false
 
-	log.Println("Returned to editor mode.")
+	logger.Infof("Returned to editor mode.")
 }
 
 // RestartPlaytest restarts the playtest from the beginning.
@@ -141,20 +235,24 @@ func (p *PlaytestController) RestartPlaytest() {
 		return
 	}
 
-	log.Println("Restarting playtest...")
+	logger.Infof("Restarting playtest...")
 
 	// Reset player to initial spawn point
 	p.playerBody.PosX = p.initialSpawnX
 	p.playerBody.PosY = p.initialSpawnY
 	p.playerBody.VelX = 0
 	p.playerBody.VelY = 0
+	p.playerBody.SavePrevious()
 
 	// Reset game state
 	p.state = gameplay.NewStateMachine()
+	p.state.SetDeathTuning(p.tuning.Death)
 	p.state.SetRespawnPoint(p.initialSpawnX, p.initialSpawnY)
 
 	// Reset entities
 	p.rebuildEntities()
+
+	p.recording.BreakPath()
 }
 
 // CreateSnapshot captures the current editor state.
@@ -208,8 +306,33 @@ func (p *PlaytestController) Update() error {
 		return nil
 	}
 
+	if inpututil.IsKeyJustPressed(ebiten.KeyF7) {
+		p.showRuleTrace = !p.showRuleTrace
+	}
+	p.pollRules(1.0 / 60.0)
+
+	// Adjusted tuning applies right away and carries over to later playtests
+	if p.tuningOverlay.Update(&p.tuning) {
+		p.playerCtrl.Tuning = p.tuning
+	}
+
+	// Debug pause freezes the playtest; F9 steps one fixed update at a time
+	if inpututil.IsKeyJustPressed(ebiten.KeyF8) {
+		p.paused = !p.paused
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
+		p.paused = true
+		p.FixedUpdate()
+	}
+	if p.paused {
+		p.updateInspector()
+		p.inp.Update()
+		return nil
+	}
+
 	// Add frame time to timestep accumulator
 	p.timestep.AddFrameTime(time.Second / 60)
+	p.lastStep = time.Now()
 
 	// Run fixed timestep physics
 	for p.timestep.ShouldUpdate() {
@@ -224,20 +347,30 @@ func (p *PlaytestController) Update() error {
 	if p.state.IsRespawning() {
 		p.respawnPlayer()
 	}
+	if p.deathBurst != nil {
+		p.deathBurst.Update(time.Second / 60)
+	}
 
-	// Camera follows player
-	playerCenterX := p.playerBody.PosX + p.playerBody.W/2
-	playerCenterY := p.playerBody.PosY + p.playerBody.H/2
-	p.camera.Follow(playerCenterX, playerCenterY, p.playerBody.W, p.playerBody.H)
-	p.camera.Update(1.0 / 60.0)
-
-	// Update entities
+	// Update entities and tile animations
 	p.entityWorld.Update(1.0 / 60.0)
+	p.renderer.Update(time.Second / 60)
+
+	// Fade out the goal message
+	if p.goalMessageTimer > 0 {
+		p.goalMessageTimer -= 1.0 / 60.0
+	}
 
-	// Update animator
+	// Crossfade music layers from gameplay state
+	gameplay.UpdateMusic(p.music, p.state, p.playerBody, p.entityWorld, 1.0/60.0)
+	gameplay.UpdateSound(p.sounds, p.playerBody)
+
+	// Update animator and sprite effects
 	if p.animator != nil {
 		p.animator.Update(playtestFrameDuration)
 	}
+	if p.sprite != nil {
+		p.sprite.Update(time.Second / 60)
+	}
 
 	// Update input state
 	p.inp.Update()
@@ -253,8 +386,24 @@ func (p *PlaytestController) FixedUpdate() {
 
 	dt := p.timestep.TickDuration()
 
+	// Gameplay waits while the screen fades between spawns
+	if p.levels.Busy() {
+		if err := p.levels.Update(dt.Seconds()); err != nil {
+			logger.Errorf("Failed to use exit: %v", err)
+		}
+		return
+	}
+
+	p.playerBody.SavePrevious()
+	p.collisions.Reset()
+
+	// Advance the level timer
+	p.progress.Update(dt.Seconds())
+	p.health.Update(dt.Seconds())
+
 	// Step 1: Update kinematic entities FIRST
 	p.entityWorld.UpdateKinematics(p.collisionMap, dt.Seconds())
+	p.entityWorld.FixedUpdate(dt.Seconds()) // Animated doors
 
 	// Step 2: Clear previous platform reference and check for carry
 	p.playerCtrl.ClearPlatformCarry()
@@ -271,12 +420,38 @@ func (p *PlaytestController) FixedUpdate() {
 
 	// Step 4: Update player physics
 	p.playerCtrl.FixedUpdate(dt, p.inp, collisionFunc)
+	p.recordPlayer()
 
 	// Step 5: Resolve solid entity collisions
 	p.resolveSolidEntityCollisions()
 
-	// Step 6: Check triggers
+	// Step 6: Check triggers, then run the rules on events posted this tick
 	p.entityWorld.CheckTriggers(p.playerBody)
+	p.ruleEngine.Update(dt)
+
+	// Step 7: Enforce level bounds
+	if gameplay.ApplyLevelBounds(p.playerBody, p.bounds) {
+		p.killPlayer()
+	}
+
+	// Step 8: Camera follows player, at the physics rate so drawing can
+	// interpolate it along with the player
+	playerCenterX := p.playerBody.PosX + p.playerBody.W/2
+	playerCenterY := p.playerBody.PosY + p.playerBody.H/2
+	p.camera.Follow(playerCenterX, playerCenterY, p.playerBody.W, p.playerBody.H)
+	p.camera.Update(dt.Seconds())
+}
+
+// ProfilerStats implements debugui.StatsProvider.ProfilerStats.
+// Draw calls are estimated as one per map chunk, entity and the player.
+func (p *PlaytestController) ProfilerStats() []debugui.Stat {
+	entityCount := len(p.entityWorld.Entities())
+	chunks := p.renderer.DrawCalls()
+	return []debugui.Stat{
+		{Name: "entities", Value: entityCount},
+		{Name: "map chunks", Value: chunks},
+		{Name: "draw calls ~", Value: chunks + entityCount + 1},
+	}
 }
 
 // Draw renders the playtest mode.
@@ -288,17 +463,51 @@ func (p *PlaytestController) Draw(screen *ebiten.Image) {
 	// Fill background
 	screen.Fill(playtestBackgroundColor)
 
-	// Create render context
-	ctx := world.NewRenderContext(p.camera, screen, 1.0/60.0)
+	// Create render context, drawing between the last two physics steps
+	// (a paused playtest shows the latest step)
+	alpha := 1.0
+	if !p.paused {
+		alpha = p.timestep.AlphaAfter(time.Since(p.lastStep))
+	}
+	view := p.camera.Interpolated(alpha)
+	ctx := world.NewRenderContext(view, screen, 1.0/60.0)
+	ctx.Alpha = alpha
 
 	// Draw map
 	p.renderer.DrawWithContext(screen, ctx)
 
-	// Draw entities
-	p.entityWorld.DrawWithContext(screen, ctx)
+	// Draw entities, and the player at its place in their render order
+	p.entityWorld.DrawWithPlayer(screen, ctx, p.playerBody.AABB(), func() {
+		p.drawPlayer(screen, ctx)
+	})
+	if p.deathBurst != nil {
+		p.deathBurst.Draw(screen, view.X, view.Y)
+	}
+
+	// Composite lighting over the world
+	p.lighting.Draw(screen, p.ambient, p.entityWorld.PointLights(ctx))
+
+	// Draw goal requirement message
+	if p.goalMessageTimer > 0 {
+		p.drawGoalMessage(screen)
+	}
+
+	// Draw held keys in levels that have any
+	if p.progress != nil && p.progress.Keys > 0 {
+		entities.DrawKeyCount(screen, float64(p.width)-44, 10, p.progress.KeysHeld())
+	}
+
+	// Draw the variables the level shows on the HUD
+	if p.vars != nil {
+		for i, line := range p.vars.HUDLines(p.tileMap.Properties()) {
+			ebitenutil.DebugPrintAt(screen, line, p.width-120, 30+i*14)
+		}
+	}
 
-	// Draw player
-	p.drawPlayer(screen)
+	// Draw hearts when the health system is on
+	if p.health.Enabled() {
+		entities.DrawHearts(screen, (float64(p.width)-entities.HeartsWidth(p.health.Max()))/2, 10, p.health.HP, p.health.Max())
+	}
 
 	// Draw state overlay
 	if p.state.IsDead() {
@@ -307,6 +516,24 @@ func (p *PlaytestController) Draw(screen *ebiten.Image) {
 		p.drawCompleteOverlay(screen)
 	}
 
+	// Fade to black while using an exit and around respawns
+	if a := max(p.levels.FadeAlpha(), p.state.ScreenFade()); a > 0 {
+		draw.FillRect(screen, 0, 0, float64(p.width), float64(p.height), draw.Fade(color.Black, a))
+	}
+
+	// Draw rules tracer
+	if p.showRuleTrace {
+		p.drawRuleTrace(screen)
+	}
+
+	// Draw tuning overlay
+	p.tuningOverlay.Draw(screen, &p.tuning)
+
+	// Draw the entity inspector while paused
+	if p.paused {
+		p.inspector.Draw(screen, ctx, "Enter: toggle active")
+	}
+
 	// Draw playtest indicator
 	p.drawPlaytestIndicator(screen)
 }
@@ -369,6 +596,7 @@ func (p *PlaytestController) buildGameScene() error {
 
 	// Create entity world
 	p.entityWorld = entities.NewEntityWorld()
+	p.inspector.Clear()
 
 	// Create player
 	p.playerBody = &physics.Body{
@@ -382,8 +610,36 @@ func (p *PlaytestController) buildGameScene() error {
 
 	// Reset game state
 	p.state = gameplay.NewStateMachine()
+	p.state.SetDeathTuning(p.tuning.Death)
 	p.timestep = timestep.NewTimestep()
 
+	// Create rules tracer (kept across restarts, reset on rebuild)
+	p.ruleTracer = rules.NewTracer(playtestTraceSize)
+
+	// Level bounds policy and kill line
+	p.bounds = world.NewLevelBounds(state.MapData.Properties(), state.Objects,
+		float64(p.tileMap.PixelWidth()), float64(p.tileMap.PixelHeight()))
+
+	// Set up lighting
+	p.lighting = gfx.NewLightLayer()
+	p.ambient = world.AmbientDarkness(state.MapData.Properties())
+
+	// Set up music layers
+	p.music = music.NewDefaultMixer()
+
+	// Exits within the level fade like room changes in the game
+	p.levels = gameplay.NewLevelManager(state.FilePath, p.enterRoom)
+
+	// Load the level theme, if any
+	if name := theme.NameForLevel(state.MapData.Properties()); name != "" {
+		skins, err := theme.LoadSkins(assets.FS(), name)
+		if err != nil {
+			logger.Warnf("Failed to load theme: %v", err)
+		} else {
+			p.skins = skins
+		}
+	}
+
 	// Load entities from editor objects
 	p.loadEntitiesFromEditor(state.Objects)
 
@@ -394,7 +650,7 @@ func (p *PlaytestController) buildGameScene() error {
 
 	// Initialize sprite
 	if err := p.initSprite(); err != nil {
-		log.Printf("Warning: failed to load sprite: %v", err)
+		logger.Warnf("Failed to load sprite: %v", err)
 	}
 
 	return nil
@@ -403,35 +659,49 @@ func (p *PlaytestController) buildGameScene() error {
 // loadEntitiesFromEditor spawns entities from editor object data.
 func (p *PlaytestController) loadEntitiesFromEditor(objects []world.ObjectData) {
 	// Find spawn point
-	if spawnX, spawnY, found := world.FindSpawnPoint(objects); found {
-		p.playerBody.PosX = spawnX
-		p.playerBody.PosY = spawnY
+	if p.spawnOverride {
+		p.initialSpawnX = p.spawnOverrideX
+		p.initialSpawnY = p.spawnOverrideY
+	} else if spawnX, spawnY, found := world.FindSpawnPoint(objects); found {
 		p.initialSpawnX = spawnX
 		p.initialSpawnY = spawnY
 	} else {
 		p.initialSpawnX = 100
 		p.initialSpawnY = 100
 	}
+	p.playerBody.PosX = p.initialSpawnX
+	p.playerBody.PosY = p.initialSpawnY
 	p.state.SetRespawnPoint(p.initialSpawnX, p.initialSpawnY)
 
 	// Create spawn context
+	p.progress = gameplay.NewLevelProgress(objects)
+	p.vars = gameplay.NewBlackboard()
+	p.health = gameplay.NewHealth(p.tuning.Health)
 	ctx := gameplay.SpawnContext{
-		OnDeath: func() {
-			p.state.TriggerDeath()
-		},
+		OnDeath:  p.killPlayer,
+		OnDamage: p.damagePlayer,
 		OnCheckpoint: func(id string, x, y float64) {
 			p.state.SetRespawnPoint(x, y)
-			log.Printf("Checkpoint '%s' activated at (%.0f, %.0f)", id, x, y)
+			p.checkpoint = gameplay.SaveCheckpoint(id, p.entityWorld, p.progress, p.vars, p.ruleEngine)
+			logger.Infof("Checkpoint '%s' activated at (%.0f, %.0f)", id, x, y)
 		},
 		OnGoalReached: func() {
 			p.state.TriggerComplete()
-			log.Println("Level Complete!")
+			logger.Infof("Level Complete! (%.1fs)", p.progress.Elapsed)
 		},
-		Registry: p.entityWorld.TargetRegistry,
+		OnGoalBlocked: p.showGoalMessage,
+		OnLevelExit:   p.useExit,
+		Registry:      p.entityWorld.TargetRegistry,
+		Skins:         p.skins,
+		Progress:      p.progress,
+		Vars:          p.vars,
+		Player:        p.playerBody,
+		Blocked:       p.blockedAt,
+		OnSpawn:       p.entityWorld.SetSource,
 	}
 
 	// Spawn entities
-	_, triggers, solidEnts, kinematics, _ := gameplay.SpawnEntities(objects, ctx)
+	ents, triggers, solidEnts, kinematics, _ := gameplay.SpawnEntities(objects, ctx)
 
 	// Add entities to world
 	for _, t := range triggers {
@@ -443,31 +713,55 @@ func (p *PlaytestController) loadEntitiesFromEditor(objects []world.ObjectData)
 	for _, k := range kinematics {
 		p.entityWorld.AddKinematic(k)
 	}
+	gameplay.AddLights(p.entityWorld, ents)
+	gameplay.RegisterMusicTargets(p.entityWorld.TargetRegistry, p.music)
+	p.sounds = gameplay.NewSoundscape(objects)
+	p.playerCtrl.Zones = gameplay.ForceZones(objects)
+	p.entityWorld.YSort = world.YSort(p.tileMap.Properties())
+
+	p.setupRules()
+
+	// Respawning before any checkpoint restores the level start
+	p.checkpoint = gameplay.SaveCheckpoint("", p.entityWorld, p.progress, p.vars, p.ruleEngine)
 }
 
 // rebuildEntities recreates entities from editor data (for restart).
 func (p *PlaytestController) rebuildEntities() {
 	state := p.editor.State()
 
-	// Clear existing entities
+	// Clear existing entities and music signals
 	p.entityWorld = entities.NewEntityWorld()
-
-	// Recreate spawn context
+	p.inspector.Clear()
+	p.music.ResetSignals()
+
+	// Recreate spawn context with fresh progress
+	p.progress = gameplay.NewLevelProgress(state.Objects)
+	p.vars = gameplay.NewBlackboard()
+	p.goalMessageTimer = 0
+	p.health.Reset()
 	ctx := gameplay.SpawnContext{
-		OnDeath: func() {
-			p.state.TriggerDeath()
-		},
+		OnDeath:  p.killPlayer,
+		OnDamage: p.damagePlayer,
 		OnCheckpoint: func(id string, x, y float64) {
 			p.state.SetRespawnPoint(x, y)
+			p.checkpoint = gameplay.SaveCheckpoint(id, p.entityWorld, p.progress, p.vars, p.ruleEngine)
 		},
 		OnGoalReached: func() {
 			p.state.TriggerComplete()
 		},
-		Registry: p.entityWorld.TargetRegistry,
+		OnGoalBlocked: p.showGoalMessage,
+		OnLevelExit:   p.useExit,
+		Registry:      p.entityWorld.TargetRegistry,
+		Skins:         p.skins,
+		Progress:      p.progress,
+		Vars:          p.vars,
+		Player:        p.playerBody,
+		Blocked:       p.blockedAt,
+		OnSpawn:       p.entityWorld.SetSource,
 	}
 
 	// Spawn entities
-	_, triggers, solidEnts, kinematics, _ := gameplay.SpawnEntities(state.Objects, ctx)
+	ents, triggers, solidEnts, kinematics, _ := gameplay.SpawnEntities(state.Objects, ctx)
 
 	for _, t := range triggers {
 		p.entityWorld.AddTrigger(t)
@@ -478,6 +772,79 @@ func (p *PlaytestController) rebuildEntities() {
 	for _, k := range kinematics {
 		p.entityWorld.AddKinematic(k)
 	}
+	gameplay.AddLights(p.entityWorld, ents)
+	gameplay.RegisterMusicTargets(p.entityWorld.TargetRegistry, p.music)
+	p.sounds = gameplay.NewSoundscape(state.Objects)
+	p.playerCtrl.Zones = gameplay.ForceZones(state.Objects)
+	p.entityWorld.YSort = world.YSort(p.tileMap.Properties())
+
+	p.setupRules()
+
+	// Respawning before any checkpoint restores the level start
+	p.checkpoint = gameplay.SaveCheckpoint("", p.entityWorld, p.progress, p.vars, p.ruleEngine)
+}
+
+// setupRules creates the rules engine for the playtest and connects the
+// triggers to it. Rules are loaded from the level's "rules" property and its
+// rules file, if it has them.
+func (p *PlaytestController) setupRules() {
+	p.ruleEngine = rules.NewEngine(gameplay.NewTargetResolver(p.entityWorld.TargetRegistry))
+	if p.ruleTracer != nil {
+		p.ruleTracer.Reset()
+		p.ruleEngine.SetTracer(p.ruleTracer)
+	}
+	gameplay.ConnectRules(p.entityWorld, p.ruleEngine)
+	gameplay.ConnectBlackboard(p.vars, p.ruleEngine)
+
+	var data []byte
+	p.rulesPath, p.rulesPoll = "", playtestRulesPollInterval
+	if levelPath := p.editor.State().FilePath; levelPath != "" {
+		p.rulesPath = rules.PathForLevel(levelPath)
+		var err error
+		if data, err = rules.ReadLevelFile(levelPath); err != nil {
+			logger.Warnf("Failed to read rules: %v", err)
+		}
+	}
+	p.rulesData = data
+	if err := gameplay.LoadLevelRules(p.ruleEngine, p.tileMap.Properties(), data); err != nil {
+		logger.Warnf("Failed to load rules: %v", err)
+		return
+	}
+	if n := p.ruleEngine.RuleCount(); n > 0 {
+		logger.Infof("Loaded %d rules", n)
+	}
+}
+
+// pollRules checks the level's rules file for edits every
+// playtestRulesPollInterval and reloads the rules when it changed, keeping
+// which rules have fired so the level's progress isn't reset. A file that
+// doesn't parse leaves the rules as they were.
+func (p *PlaytestController) pollRules(dt float64) {
+	if p.rulesPath == "" {
+		return
+	}
+	if p.rulesPoll -= dt; p.rulesPoll > 0 {
+		return
+	}
+	p.rulesPoll = playtestRulesPollInterval
+
+	data, err := rules.ReadLevelFile(p.editor.State().FilePath)
+	if err != nil || bytes.Equal(data, p.rulesData) {
+		return
+	}
+	p.rulesData = data
+	ruleSet, err := gameplay.ParseLevelRules(p.tileMap.Properties(), data)
+	if err != nil {
+		logger.Warnf("Failed to reload rules: %v", err)
+		return
+	}
+	p.ruleEngine.Reload(ruleSet)
+	logger.Infof("Reloaded %d rules from %s", p.ruleEngine.RuleCount(), p.rulesPath)
+}
+
+// blockedAt reports whether an area overlaps solid tiles.
+func (p *PlaytestController) blockedAt(a physics.AABB) bool {
+	return p.collisionMap.OverlapsSolid(a.X, a.Y, a.W, a.H)
 }
 
 // cleanupGameScene releases game scene resources.
@@ -493,6 +860,15 @@ func (p *PlaytestController) cleanupGameScene() {
 	p.state = nil
 	p.sprite = nil
 	p.animator = nil
+	p.ruleEngine = nil
+	p.ruleTracer = nil
+	p.skins = nil
+	p.lighting = nil
+	p.music = nil
+	p.sounds = nil
+	p.progress = nil
+	p.vars = nil
+	p.levels = nil
 }
 
 // initSprite loads the player sprite.
@@ -522,32 +898,114 @@ func (p *PlaytestController) initSprite() error {
 	return nil
 }
 
-// drawPlayer renders the player.
-func (p *PlaytestController) drawPlayer(screen *ebiten.Image) {
-	screenX := p.playerBody.PosX + p.playerBody.W/2 - p.camera.X
-	screenY := p.playerBody.PosY + p.playerBody.H/2 - p.camera.Y
+// drawPlayer renders the player between its last two physics steps.
+func (p *PlaytestController) drawPlayer(screen *ebiten.Image, ctx *world.RenderContext) {
+	// Blink while invulnerable after a hit
+	if !p.health.Visible() {
+		return
+	}
+
+	drawX, drawY := ctx.WorldToScreen(p.playerBody.Interpolate(ctx.Alpha))
+	screenX := drawX + p.playerBody.W/2
+	screenY := drawY + p.playerBody.H/2
 
 	if p.sprite != nil && p.animator != nil {
 		p.sprite.Image = p.animator.CurrentFrame()
 		p.sprite.SetPosition(screenX, screenY)
+		p.sprite.SetScale(0.5, 0.5*p.playerBody.H/playtestPlayerSize) // Squashed while crouching
+		if p.playerBody.VelX < 0 {
+			p.sprite.SetFlipX(true)
+		} else if p.playerBody.VelX > 0 {
+			p.sprite.SetFlipX(false)
+		}
+		p.sprite.SetAlpha(p.state.DeathFade())
 		p.sprite.Draw(screen)
 	} else {
 		// Fallback rectangle
-		drawX := p.playerBody.PosX - p.camera.X
-		drawY := p.playerBody.PosY - p.camera.Y
-		ebitenutil.DrawRect(screen, drawX, drawY, p.playerBody.W, p.playerBody.H, playtestPlayerColor)
+		draw.FillRect(screen, drawX, drawY, p.playerBody.W, p.playerBody.H, playtestPlayerColor)
 	}
 }
 
 // drawPlaytestIndicator shows the playtest mode indicator.
 func (p *PlaytestController) drawPlaytestIndicator(screen *ebiten.Image) {
-	text := "PLAYTEST MODE | ESC: Exit | R: Restart"
+	text := i18n.T("playtest.banner")
+	if p.spawnOverride {
+		text = i18n.T("playtest.bannerFromHere")
+	}
+	if p.paused {
+		text += i18n.T("playtest.paused")
+	}
 	ebitenutil.DebugPrintAt(screen, text, 10, 10)
 }
 
+// updateInspector selects the entity under a left click and toggles its
+// active state with Enter.
+func (p *PlaytestController) updateInspector() {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		wx, wy := p.camera.ScreenToWorld(mx, my)
+		p.inspector.Pick(p.entityWorld, wx, wy)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		p.inspector.ToggleActive()
+	}
+}
+
+// drawRuleTrace shows the most recent rule firings and per-rule fire counters.
+func (p *PlaytestController) drawRuleTrace(screen *ebiten.Image) {
+	if p.ruleEngine == nil || p.ruleTracer == nil {
+		return
+	}
+
+	lines := []string{fmt.Sprintf("RULES | %s", p.ruleEngine.Stats())}
+
+	// Recent firings, newest first
+	recent := p.ruleTracer.Recent()
+	if len(recent) == 0 {
+		lines = append(lines, "  (no rules fired yet)")
+	}
+	for _, f := range recent {
+		lines = append(lines, fmt.Sprintf("> %s <- %s '%s' (%s)", f.RuleID, f.Event.Type, f.Event.RegionID, f.Event.ActorType))
+		for _, r := range f.Results {
+			if r.Err != nil {
+				lines = append(lines, fmt.Sprintf("    ! %s %s: %v", r.Spec.Type, r.Spec.TargetLabel(), r.Err))
+			} else {
+				lines = append(lines, fmt.Sprintf("    %s %s", r.Spec.Type, r.Spec.TargetLabel()))
+			}
+		}
+	}
+
+	// Per-rule fire counters, sorted by rule ID
+	counts := p.ruleTracer.Counts()
+	if len(counts) > 0 {
+		ids := make([]string, 0, len(counts))
+		for id := range counts {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		lines = append(lines, "Fire counts:")
+		for _, id := range ids {
+			lines = append(lines, fmt.Sprintf("  %s: %d", id, counts[id]))
+		}
+	}
+
+	// Draw background
+	lineHeight := 14
+	width := 360
+	height := len(lines)*lineHeight + 10
+	x := 10
+	y := 30
+	draw.FillRect(screen, float64(x), float64(y), float64(width), float64(height), color.RGBA{0, 0, 0, 180})
+
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, x+5, y+5+i*lineHeight)
+	}
+}
+
 // drawDeathOverlay shows death message.
 func (p *PlaytestController) drawDeathOverlay(screen *ebiten.Image) {
-	text := "YOU DIED"
+	text := i18n.T("hud.youDied")
 	x := p.width/2 - 30
 	y := p.height/2 - 10
 	ebitenutil.DebugPrintAt(screen, text, x, y)
@@ -555,43 +1013,145 @@ func (p *PlaytestController) drawDeathOverlay(screen *ebiten.Image) {
 
 // drawCompleteOverlay shows level complete message.
 func (p *PlaytestController) drawCompleteOverlay(screen *ebiten.Image) {
-	text := "LEVEL COMPLETE!"
+	text := i18n.T("playtest.levelComplete", p.progress.Elapsed, p.progress.Deaths)
 	x := p.width/2 - 50
 	y := p.height/2 - 10
 	ebitenutil.DebugPrintAt(screen, text, x, y)
 }
 
+// useExit follows an exit to a spawn in the edited level. Exits to other
+// level files only show where they lead: the playtest plays one level.
+func (p *PlaytestController) useExit(level, spawn string) {
+	path := p.levels.Path()
+	if target := world.ResolveLevelPath(path, level); target != path {
+		p.showGoalMessage(i18n.T("playtest.exit", level, spawn))
+		return
+	}
+	p.levels.Transition(level, spawn)
+}
+
+// enterRoom implements gameplay.RoomLoader for exits within the level: it
+// moves the player to the spawn with the given id.
+func (p *PlaytestController) enterRoom(path string, data []byte, spawn string) error {
+	x, y, found := world.FindSpawnByID(p.editor.State().Objects, spawn)
+	if !found {
+		return fmt.Errorf("no spawn %q", spawn)
+	}
+	p.playerBody.PosX, p.playerBody.PosY = x, y
+	p.playerBody.VelX, p.playerBody.VelY = 0, 0
+	p.playerBody.SavePrevious()
+	p.state.SetRespawnPoint(x, y)
+	p.camera.Follow(x+p.playerBody.W/2, y+p.playerBody.H/2, p.playerBody.W, p.playerBody.H)
+	p.camera.Snap()
+	return nil
+}
+
+// showGoalMessage shows why the goal can't be completed yet.
+func (p *PlaytestController) showGoalMessage(reason string) {
+	p.goalMessage = reason
+	p.goalMessageTimer = playtestGoalMessageDuration
+}
+
+// drawGoalMessage draws the goal locked message.
+func (p *PlaytestController) drawGoalMessage(screen *ebiten.Image) {
+	text := i18n.T("hud.goalLocked", p.goalMessage)
+	x := p.width/2 - len(text)*3
+	y := p.height/2 - 40
+	ebitenutil.DebugPrintAt(screen, text, x, y)
+}
+
+// killPlayer triggers death and flashes the player sprite.
+func (p *PlaytestController) killPlayer() {
+	if !p.state.IsRunning() {
+		return
+	}
+	p.state.TriggerDeath()
+	p.deathBurst = gameplay.NewDeathBurst(p.playerBody, p.tuning.Death)
+	if p.progress != nil {
+		p.progress.Deaths++
+	}
+	p.ruleEngine.ProcessEvent(rules.NewEvent(rules.EventDeath, "", "player"))
+	p.recording.AddDeath(p.playerBody.PosX+p.playerBody.W/2, p.playerBody.PosY+p.playerBody.H/2)
+	if p.sprite != nil {
+		p.sprite.FlashWhite(playtestFlashDuration)
+	}
+}
+
+// damagePlayer applies damage to the player's health, knocking them back,
+// and kills them when it runs out. Returns whether the hit landed.
+func (p *PlaytestController) damagePlayer(amount int, source physics.AABB) bool {
+	if !p.state.IsRunning() {
+		return false
+	}
+	hurt, dead := p.health.Damage(amount)
+	if dead {
+		p.killPlayer()
+		return true
+	}
+	if !hurt {
+		return false
+	}
+	p.health.Knockback(p.playerBody, source)
+	if p.sprite != nil {
+		p.sprite.FlashWhite(playtestFlashDuration)
+	}
+	return true
+}
+
+// recordPlayer adds the player's position, and jump starts, to the recording.
+func (p *PlaytestController) recordPlayer() {
+	cx := p.playerBody.PosX + p.playerBody.W/2
+	p.recording.AddPoint(cx, p.playerBody.PosY+p.playerBody.H/2)
+
+	jumping := p.playerCtrl.State.IsJumping
+	if jumping && !p.wasJumping {
+		p.recording.AddJump(cx, p.playerBody.PosY+p.playerBody.H)
+	}
+	p.wasJumping = jumping
+}
+
 // respawnPlayer resets player to respawn point.
 func (p *PlaytestController) respawnPlayer() {
 	p.playerBody.PosX = p.state.RespawnX
 	p.playerBody.PosY = p.state.RespawnY
 	p.playerBody.VelX = 0
 	p.playerBody.VelY = 0
+	p.playerBody.SavePrevious()
+	p.health.Reset()
+	p.checkpoint.Restore(p.entityWorld, p.progress, p.vars, p.ruleEngine)
 	p.state.FinishRespawn()
+
+	// Behind a fade the camera cuts to the respawn point, otherwise it glides
+	p.camera.Follow(p.playerBody.PosX+p.playerBody.W/2, p.playerBody.PosY+p.playerBody.H/2, p.playerBody.W, p.playerBody.H)
+	if p.state.FadeOutTime > 0 {
+		p.camera.Snap()
+	} else {
+		p.camera.Glide(playtestRespawnGlideTime, tween.InOutQuad)
+	}
 }
 
-// resolveCollisions checks for tile collisions.
+// resolveCollisions checks for tile collisions. The result is valid until
+// the next FixedUpdate.
 func (p *PlaytestController) resolveCollisions(aabb physics.AABB) []physics.Collision {
-	var collisions []physics.Collision
-
 	tileSize := 16
-	startTX := int(aabb.X) / tileSize
-	startTY := int(aabb.Y) / tileSize
-	endTX := int(aabb.X+aabb.W) / tileSize
-	endTY := int(aabb.Y+aabb.H) / tileSize
-
-	// Clamp to map bounds
-	if startTX < 0 {
-		startTX = 0
-	}
-	if startTY < 0 {
-		startTY = 0
-	}
-	if endTX >= p.tileMap.Width() {
-		endTX = p.tileMap.Width() - 1
+	startTX := world.TileIndex(aabb.X, tileSize)
+	startTY := world.TileIndex(aabb.Y, tileSize)
+	endTX := world.TileIndex(aabb.X+aabb.W, tileSize)
+	endTY := world.TileIndex(aabb.Y+aabb.H, tileSize)
+
+	// Clamp to map bounds. Infinite maps have tiles left of and above the
+	// origin, so there's nothing to clamp to.
+	if !p.tileMap.Infinite() {
+		startTX = max(startTX, 0)
+		startTY = max(startTY, 0)
+		endTX = min(endTX, p.tileMap.Width()-1)
+		endTY = min(endTY, p.tileMap.Height()-1)
 	}
-	if endTY >= p.tileMap.Height() {
-		endTY = p.tileMap.Height() - 1
+
+	// Room for every tile in range, so appending doesn't reallocate
+	var collisions []physics.Collision
+	if startTX <= endTX && startTY <= endTY {
+		collisions = p.collisions.Alloc((endTX - startTX + 1) * (endTY - startTY + 1))[:0]
 	}
 
 	// Check each tile
@@ -643,9 +1203,10 @@ func (p *PlaytestController) resolveCollisions(aabb physics.AABB) []physics.Coll
 
 // resolveSolidEntityCollisions resolves player collision against solid entities.
 func (p *PlaytestController) resolveSolidEntityCollisions() {
-	solidAABBs := p.entityWorld.ActiveSolidAABBs()
+	p.solidAABBs = p.entityWorld.AppendActiveSolidAABBs(p.solidAABBs[:0])
 
-	if len(solidAABBs) > 0 {
-		physics.ResolveSolids(p.playerBody, solidAABBs)
+	if len(p.solidAABBs) > 0 {
+		p.playerCtrl.StepOntoSolids(p.solidAABBs)
+		physics.ResolveSolids(p.playerBody, p.solidAABBs)
 	}
 }
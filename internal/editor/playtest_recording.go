@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+// recordMinDistance is how far the player must move before the next path
+// point is recorded.
+const recordMinDistance = 4.0
+
+// Playtest recording colors
+var (
+	recordPathColor  = color.RGBA{80, 220, 255, 160}
+	recordJumpColor  = color.RGBA{255, 230, 80, 220}
+	recordDeathColor = color.RGBA{255, 60, 60, 255}
+)
+
+// RecordedPoint is a world position recorded during a playtest.
+type RecordedPoint struct {
+	X, Y float64
+}
+
+// PlaytestRecording is the player's trajectory, jumps and deaths from a
+// playtest, drawn on the canvas afterwards to show where players struggle.
+type PlaytestRecording struct {
+	// Paths are the trajectory between teleports (deaths, respawns,
+	// restarts), so no line is drawn across a jump back to a spawn.
+	Paths  [][]RecordedPoint
+	Jumps  []RecordedPoint
+	Deaths []RecordedPoint
+}
+
+// NewPlaytestRecording creates an empty recording.
+func NewPlaytestRecording() *PlaytestRecording {
+	return &PlaytestRecording{}
+}
+
+// IsEmpty returns whether nothing was recorded.
+func (r *PlaytestRecording) IsEmpty() bool {
+	return len(r.Paths) == 0 && len(r.Jumps) == 0 && len(r.Deaths) == 0
+}
+
+// AddPoint extends the current path to x, y once the player has moved far
+// enough from the last point.
+func (r *PlaytestRecording) AddPoint(x, y float64) {
+	if len(r.Paths) == 0 {
+		r.Paths = append(r.Paths, nil)
+	}
+	path := &r.Paths[len(r.Paths)-1]
+	if n := len(*path); n > 0 {
+		last := (*path)[n-1]
+		if mathx.Distance(last.X, last.Y, x, y) < recordMinDistance {
+			return
+		}
+	}
+	*path = append(*path, RecordedPoint{x, y})
+}
+
+// BreakPath starts a new path at the next point.
+func (r *PlaytestRecording) BreakPath() {
+	if len(r.Paths) > 0 && len(r.Paths[len(r.Paths)-1]) > 0 {
+		r.Paths = append(r.Paths, nil)
+	}
+}
+
+// AddJump records a jump at x, y.
+func (r *PlaytestRecording) AddJump(x, y float64) {
+	r.Jumps = append(r.Jumps, RecordedPoint{x, y})
+}
+
+// AddDeath records a death at x, y and ends the current path.
+func (r *PlaytestRecording) AddDeath(x, y float64) {
+	r.Deaths = append(r.Deaths, RecordedPoint{x, y})
+	r.BreakPath()
+}
+
+// Draw renders the paths, jump dots and death crosses over the canvas.
+func (r *PlaytestRecording) Draw(screen *ebiten.Image, camera *Camera) {
+	toScreen := func(p RecordedPoint) (float64, float64) {
+		return (p.X - camera.X) * camera.Zoom, (p.Y - camera.Y) * camera.Zoom
+	}
+
+	for _, path := range r.Paths {
+		for i := 1; i < len(path); i++ {
+			x1, y1 := toScreen(path[i-1])
+			x2, y2 := toScreen(path[i])
+			draw.SmoothLine(screen, x1, y1, x2, y2, 1, recordPathColor)
+		}
+	}
+
+	for _, p := range r.Jumps {
+		x, y := toScreen(p)
+		draw.FillRect(screen, x-2, y-2, 4, 4, recordJumpColor)
+	}
+
+	size := 5 * math.Max(camera.Zoom, 1)
+	for _, p := range r.Deaths {
+		x, y := toScreen(p)
+		draw.SmoothLine(screen, x-size, y-size, x+size, y+size, 2, recordDeathColor)
+		draw.SmoothLine(screen, x-size, y+size, x+size, y-size, 2, recordDeathColor)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build display
+
+package editor
+
+import (
+	"image"
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestPlaytestResolveCollisionsLeftOfInfiniteOrigin(t *testing.T) {
+	// The floor runs from tile -16 to -1 on row 2, left of the origin
+	md, err := world.ParseTiledJSON([]byte(`{"width": 10, "height": 10, "tilewidth": 16, "tileheight": 16, "infinite": true, "layers": [
+	  {"name": "Collision", "type": "tilelayer", "chunks": [
+	    {"x": -16, "y": 2, "width": 16, "height": 1, "data": [1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1]}
+	  ]}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON failed: %v", err)
+	}
+	tileMap := world.NewMap(md, world.NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 16, 16)), 16, 16))
+	p := &PlaytestController{tileMap: tileMap, collisionMap: world.NewCollisionMapFromMap(tileMap, "Collision")}
+
+	collisions := p.resolveCollisions(physics.AABB{X: -110, Y: 20, W: 8, H: 14})
+	if len(collisions) != 1 {
+		t.Fatalf("got %d collisions on the floor left of the origin, want 1: %v", len(collisions), collisions)
+	}
+	if c := collisions[0]; c.TileX != -7 || c.TileY != 2 || c.NormalY != -1 {
+		t.Errorf("collision %+v, want tile -7, 2 pushing up", c)
+	}
+}
@@ -0,0 +1,206 @@
+package editor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// DefaultPrefabPath is where the prefab library is kept when the editor
+// wasn't given one.
+const DefaultPrefabPath = "prefabs.json"
+
+// prefabLinkProps are the properties holding the id of another object.
+var prefabLinkProps = []string{"target", world.PropSwitchTarget, "platform"}
+
+// Prefab is a named group of objects placed as a unit, such as a switch and
+// the door it opens. Object positions are relative to the group's top-left
+// corner.
+type Prefab struct {
+	Name    string             `json:"name"`
+	Objects []world.ObjectData `json:"objects"`
+}
+
+// NewPrefab makes a prefab named name from the objects at indices. Returns
+// false if none of the indices are valid.
+func NewPrefab(name string, objects []world.ObjectData, indices []int) (Prefab, bool) {
+	sorted := append([]int(nil), indices...)
+	sort.Ints(sorted)
+
+	p := Prefab{Name: name}
+	minX, minY := math.Inf(1), math.Inf(1)
+	for _, idx := range sorted {
+		if idx < 0 || idx >= len(objects) {
+			continue
+		}
+		obj := objects[idx]
+		props := make(map[string]any, len(obj.Props))
+		for k, v := range obj.Props {
+			props[k] = v
+		}
+		// Placing picks the Tiled ID and layer
+		obj.ID, obj.Layer, obj.Props = 0, "", props
+		p.Objects = append(p.Objects, obj)
+		minX, minY = math.Min(minX, obj.X), math.Min(minY, obj.Y)
+	}
+	if len(p.Objects) == 0 {
+		return Prefab{}, false
+	}
+	for i := range p.Objects {
+		p.Objects[i].X -= minX
+		p.Objects[i].Y -= minY
+	}
+	return p, true
+}
+
+// PrefabName suggests a name for a prefab of objects: the first one's name
+// if it has one, otherwise its types joined with "+", e.g. "switch+door".
+func PrefabName(objects []world.ObjectData) string {
+	if len(objects) > 0 && objects[0].Name != "" {
+		return objects[0].Name
+	}
+	types := make([]string, len(objects))
+	for i, obj := range objects {
+		types[i] = string(obj.Type)
+	}
+	return strings.Join(types, "+")
+}
+
+// Instantiate returns copies of the prefab's objects with the group's
+// top-left corner at x, y, on layer. Like DuplicateObjects, copies get new
+// Tiled object IDs and new id properties; links between the prefab's own
+// objects (a switch's door_id or targets, a hazard's platform) are rewritten
+// to the new ids, while links to other objects are kept.
+func (p Prefab) Instantiate(objects []world.ObjectData, x, y float64, layer string) []world.ObjectData {
+	combined := append(append([]world.ObjectData(nil), objects...), p.Objects...)
+	indices := make([]int, len(p.Objects))
+	for i := range indices {
+		indices[i] = len(objects) + i
+	}
+	copies := DuplicateObjects(combined, indices, x, y)
+
+	remap := make(map[string]string)
+	for i, src := range p.Objects {
+		oldID := src.GetPropString("id", "")
+		if newID := copies[i].GetPropString("id", ""); oldID != "" && newID != "" {
+			remap[oldID] = newID
+		}
+	}
+	for i := range copies {
+		copies[i].Layer = layer
+		for _, prop := range prefabLinkProps {
+			if id, ok := copies[i].Props[prop].(string); ok && remap[id] != "" {
+				copies[i].Props[prop] = remap[id]
+			}
+		}
+		if list, ok := copies[i].Props[world.PropSwitchTargets].(string); ok && list != "" {
+			ids := world.ParseTargetList(list)
+			for j, id := range ids {
+				if remap[id] != "" {
+					ids[j] = remap[id]
+				}
+			}
+			copies[i].Props[world.PropSwitchTargets] = strings.Join(ids, ",")
+		}
+	}
+	return copies
+}
+
+// NewPlacePrefabAction creates an action that adds an instance of p with its
+// top-left corner at x, y on the current object layer. Returns the action and
+// the indices the objects get.
+func NewPlacePrefabAction(state *EditorState, p Prefab, x, y float64) (*CompositeAction, []int) {
+	objects := p.Instantiate(state.Objects, x, y, state.CurrentObjectLayer())
+	return newAddObjectsAction(fmt.Sprintf("Place prefab %s", p.Name), len(state.Objects), objects)
+}
+
+// PrefabLibrary is the list of prefabs kept in a library file.
+type PrefabLibrary struct {
+	Prefabs []Prefab `json:"prefabs"`
+
+	path string
+}
+
+// NewPrefabLibrary creates an empty library saving to path, or to
+// DefaultPrefabPath if path is empty.
+func NewPrefabLibrary(path string) *PrefabLibrary {
+	if path == "" {
+		path = DefaultPrefabPath
+	}
+	return &PrefabLibrary{path: path}
+}
+
+// LoadPrefabLibrary reads the library at path (DefaultPrefabPath if empty).
+// A missing file is an empty library; it's created by the first Save.
+func LoadPrefabLibrary(path string) (*PrefabLibrary, error) {
+	lib := NewPrefabLibrary(path)
+	data, err := storage.ReadFile(lib.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lib, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("prefab library %s: %w", lib.path, err)
+	}
+	if err := json.Unmarshal(data, lib); err != nil {
+		return nil, fmt.Errorf("prefab library %s: %w", lib.path, err)
+	}
+	return lib, nil
+}
+
+// Path returns the library file.
+func (l *PrefabLibrary) Path() string {
+	return l.path
+}
+
+// Save writes the library file.
+func (l *PrefabLibrary) Save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("prefab library %s: %w", l.path, err)
+	}
+	if dir := filepath.Dir(l.path); dir != "." {
+		if err := storage.MkdirAll(dir); err != nil {
+			return fmt.Errorf("prefab library %s: %w", l.path, err)
+		}
+	}
+	if err := storage.WriteFile(l.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("prefab library %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// Add appends p to the library, renaming it "name 2", "name 3"... if the
+// name is taken. Returns the name it was added under.
+func (l *PrefabLibrary) Add(p Prefab) string {
+	name := p.Name
+	for n := 2; l.has(name); n++ {
+		name = fmt.Sprintf("%s %d", p.Name, n)
+	}
+	p.Name = name
+	l.Prefabs = append(l.Prefabs, p)
+	return name
+}
+
+// Remove deletes the prefab at index i.
+func (l *PrefabLibrary) Remove(i int) {
+	if i >= 0 && i < len(l.Prefabs) {
+		l.Prefabs = append(l.Prefabs[:i], l.Prefabs[i+1:]...)
+	}
+}
+
+// has returns whether a prefab is named name.
+func (l *PrefabLibrary) has(name string) bool {
+	for _, p := range l.Prefabs {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+//go:build display
+
+package editor
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// prefabLevel returns a level with a door outside any prefab, and the
+// objects the prefab tests group: a switch, the door it opens and a hazard
+// riding a platform.
+func prefabLevel() []world.ObjectData {
+	return []world.ObjectData{
+		{ID: 1, Type: world.ObjectTypeDoor, X: 0, Y: 0, W: 16, H: 48, Layer: "Objects", Props: map[string]any{"id": "door_outside"}},
+		{ID: 2, Type: world.ObjectTypeSwitch, X: 100, Y: 40, W: 16, H: 16, Layer: "Objects", Props: map[string]any{"door_id": "door_1"}},
+		{ID: 3, Type: world.ObjectTypeDoor, X: 140, Y: 8, W: 16, H: 48, Layer: "Objects", Props: map[string]any{"id": "door_1"}},
+		{ID: 4, Type: world.ObjectTypePlatform, X: 120, Y: 80, W: 48, H: 16, Layer: "Objects", Props: map[string]any{"id": "platform_1"}},
+		{ID: 5, Type: world.ObjectTypeHazard, X: 124, Y: 72, W: 16, H: 8, Layer: "Objects", Props: map[string]any{"platform": "platform_1"}},
+	}
+}
+
+func TestNewPrefab(t *testing.T) {
+	objects := prefabLevel()
+	p, ok := NewPrefab("gate", objects, []int{3, 2, 99})
+	if !ok {
+		t.Fatal("NewPrefab failed")
+	}
+	if len(p.Objects) != 2 || p.Objects[0].Type != world.ObjectTypeDoor || p.Objects[1].Type != world.ObjectTypePlatform {
+		t.Fatalf("prefab objects %+v, want the door then the platform", p.Objects)
+	}
+	// Positions are relative to the group's top-left corner, and placing
+	// picks the Tiled ID and layer
+	if o := p.Objects[0]; o.X != 20 || o.Y != 0 || o.ID != 0 || o.Layer != "" {
+		t.Errorf("door at %v, %v with ID %d on %q, want 20, 0 with no ID or layer", o.X, o.Y, o.ID, o.Layer)
+	}
+	if o := p.Objects[1]; o.X != 0 || o.Y != 72 {
+		t.Errorf("platform at %v, %v, want 0, 72", o.X, o.Y)
+	}
+	p.Objects[0].Props["id"] = "changed"
+	if objects[2].Props["id"] != "door_1" {
+		t.Error("the prefab shares properties with the level's object")
+	}
+
+	if _, ok := NewPrefab("none", objects, []int{-1, 5}); ok {
+		t.Error("NewPrefab of no valid indices succeeded")
+	}
+}
+
+func TestPrefabInstantiateLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		indices []int                          // Objects of prefabLevel the prefab groups
+		object  int                            // Index in the prefab of the object to check
+		prop    string                         // Link property to check
+		want    func(map[string]string) string // Wanted value, from old ids to the copies' ids
+	}{
+		{
+			name:    "door_id to a door in the prefab",
+			indices: []int{1, 2},
+			object:  0,
+			prop:    world.PropSwitchTarget,
+			want:    func(ids map[string]string) string { return ids["door_1"] },
+		},
+		{
+			name:    "door_id to a door outside",
+			indices: []int{1},
+			object:  0,
+			prop:    world.PropSwitchTarget,
+			want:    func(map[string]string) string { return "door_1" },
+		},
+		{
+			name:    "targets inside and outside",
+			indices: []int{1, 2, 3},
+			object:  0,
+			prop:    world.PropSwitchTargets,
+			want:    func(ids map[string]string) string { return ids["door_1"] + ",door_outside," + ids["platform_1"] },
+		},
+		{
+			name:    "hazard on a platform in the prefab",
+			indices: []int{3, 4},
+			object:  1,
+			prop:    "platform",
+			want:    func(ids map[string]string) string { return ids["platform_1"] },
+		},
+		{
+			name:    "hazard on a platform outside",
+			indices: []int{4},
+			object:  0,
+			prop:    "platform",
+			want:    func(map[string]string) string { return "platform_1" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := prefabLevel()
+			objects[1].Props[world.PropSwitchTargets] = "door_1, door_outside,platform_1"
+			p, ok := NewPrefab("test", objects, tt.indices)
+			if !ok {
+				t.Fatal("NewPrefab failed")
+			}
+			copies := p.Instantiate(objects, 300, 200, "Props")
+
+			ids := make(map[string]string)
+			for i, src := range p.Objects {
+				if old := src.GetPropString("id", ""); old != "" {
+					ids[old] = copies[i].GetPropString("id", "")
+					if ids[old] == "" || ids[old] == old {
+						t.Errorf("copy of %s got id %q, want a new one", old, ids[old])
+					}
+				}
+			}
+			if got, want := copies[tt.object].Props[tt.prop], tt.want(ids); got != want {
+				t.Errorf("%s = %v, want %q", tt.prop, got, want)
+			}
+			for _, c := range copies {
+				if c.Layer != "Props" {
+					t.Errorf("copy %d on layer %q, want Props", c.ID, c.Layer)
+				}
+			}
+		})
+	}
+}
+
+func TestPrefabInstancesDontCollide(t *testing.T) {
+	objects := prefabLevel()
+	p, ok := NewPrefab("gate", objects, []int{1, 2})
+	if !ok {
+		t.Fatal("NewPrefab failed")
+	}
+
+	first := p.Instantiate(objects, 300, 0, "Objects")
+	objects = append(objects, first...)
+	second := p.Instantiate(objects, 400, 0, "Objects")
+	objects = append(objects, second...)
+
+	tiledIDs := make(map[int]bool)
+	ids := make(map[string]bool)
+	for _, obj := range objects {
+		if tiledIDs[obj.ID] {
+			t.Errorf("Tiled object ID %d used twice", obj.ID)
+		}
+		tiledIDs[obj.ID] = true
+		if id := obj.GetPropString("id", ""); id != "" {
+			if ids[id] {
+				t.Errorf("id %q used twice", id)
+			}
+			ids[id] = true
+		}
+	}
+
+	// Each instance's switch opens its own door
+	for i, instance := range [][]world.ObjectData{first, second} {
+		if got, want := instance[0].Props[world.PropSwitchTarget], instance[1].Props["id"]; got != want {
+			t.Errorf("instance %d: switch opens %v, want its door %v", i+1, got, want)
+		}
+	}
+	if first[1].X != 340 || second[1].X != 440 {
+		t.Errorf("doors at x %v and %v, want 340 and 440", first[1].X, second[1].X)
+	}
+}
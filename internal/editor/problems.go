@@ -0,0 +1,190 @@
+package editor
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+// liveValidationDelay is how long the level has to stay unchanged before it
+// is validated again, in seconds, so a burst of edits validates once.
+const liveValidationDelay = 0.3
+
+// Problems panel dimensions
+const (
+	problemsWidth      = 360
+	problemsListOffset = 30 // Distance from the panel top to the first row
+)
+
+// LiveValidator validates the level again shortly after it changes, so
+// validation badges and problems stay current without pressing V.
+type LiveValidator struct {
+	history *History // History the level was last validated for
+	version int      // Its version then
+	pending float64  // Seconds until the next validation, 0 = none pending
+}
+
+// NewLiveValidator creates a validator. The level counts as changed on the
+// first Update, so it is validated shortly after any level is loaded.
+func NewLiveValidator() *LiveValidator {
+	return &LiveValidator{}
+}
+
+// Update advances the debounce by dt seconds and returns true when the level
+// in state should be validated now: liveValidationDelay after its last change.
+func (v *LiveValidator) Update(state *EditorState, dt float64) bool {
+	if state.History != v.history || state.History.Version() != v.version {
+		v.history, v.version = state.History, state.History.Version()
+		v.pending = liveValidationDelay
+		return false
+	}
+	if v.pending <= 0 {
+		return false
+	}
+	v.pending -= dt
+	return v.pending <= 0
+}
+
+// ProblemsPanel lists the errors and warnings of the last validation.
+// Clicking one selects its object and moves the camera to it. Unlike the
+// outliner it doesn't block other input, so the list can stay open while
+// the problems are fixed.
+type ProblemsPanel struct {
+	open   bool
+	cursor int // Last clicked row
+	scroll int // First visible row
+}
+
+// NewProblemsPanel creates a new, closed problems panel.
+func NewProblemsPanel() *ProblemsPanel {
+	return &ProblemsPanel{}
+}
+
+// Toggle shows or hides the panel.
+func (p *ProblemsPanel) Toggle() {
+	p.open = !p.open
+}
+
+// Close hides the panel.
+func (p *ProblemsPanel) Close() {
+	p.open = false
+}
+
+// IsOpen returns true if the panel is currently shown.
+func (p *ProblemsPanel) IsOpen() bool {
+	return p.open
+}
+
+// problems returns the errors followed by the warnings of result.
+func problems(result *ValidationResult) []ValidationError {
+	if result == nil {
+		return nil
+	}
+	return append(append([]ValidationError(nil), result.Errors...), result.Warnings...)
+}
+
+// Update handles input for the panel: the wheel scrolls it, and a click jumps
+// to the problem's object. It returns true if the mouse is over the panel, so
+// the canvas shouldn't get it.
+func (p *ProblemsPanel) Update(state *EditorState, camera *Camera, result *ValidationResult, canvasWidth, canvasHeight int) bool {
+	if !p.open {
+		return false
+	}
+
+	list := problems(result)
+	rowsShown := max(1, (canvasHeight-problemsListOffset-outlinerFooter)/outlinerRowHeight)
+	p.cursor = mathx.Clamp(p.cursor, 0, len(list)-1)
+
+	mx, my := ebiten.CursorPosition()
+	hovered := mx >= 0 && mx < problemsWidth && my >= 0 && my < canvasHeight
+	if hovered {
+		if _, wy := ebiten.Wheel(); wy != 0 {
+			p.scroll -= int(wy * 3)
+		}
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			row := p.scroll + (my-problemsListOffset)/outlinerRowHeight
+			if my >= problemsListOffset && row < len(list) && row-p.scroll < rowsShown {
+				p.cursor = row
+				p.jumpTo(state, camera, list, canvasWidth, canvasHeight)
+			}
+		}
+	}
+
+	// Keep the scroll within the list
+	p.scroll = mathx.Clamp(p.scroll, 0, len(list)-rowsShown)
+	return hovered
+}
+
+// jumpTo selects the clicked problem's object and centers the camera on
+// it. Level-wide problems have no object to jump to.
+func (p *ProblemsPanel) jumpTo(state *EditorState, camera *Camera, list []ValidationError, canvasWidth, canvasHeight int) {
+	if p.cursor < 0 || p.cursor >= len(list) {
+		return
+	}
+	idx := list[p.cursor].ObjectIndex
+	if idx < 0 || idx >= len(state.Objects) {
+		return
+	}
+	obj := state.Objects[idx]
+
+	state.SelectObject(idx)
+	if selection := state.GetSelectionManager(); selection != nil {
+		selection.Select(idx)
+	}
+
+	camera.X = obj.X + obj.W/2 - float64(canvasWidth)/2/camera.Zoom
+	camera.Y = obj.Y + obj.H/2 - float64(canvasHeight)/2/camera.Zoom
+}
+
+// Draw renders the panel along the left edge of the canvas, above the status
+// bar.
+func (p *ProblemsPanel) Draw(screen *ebiten.Image, state *EditorState, result *ValidationResult, canvasHeight int) {
+	if !p.open {
+		return
+	}
+
+	draw.FillRect(screen, 0, 0, problemsWidth, float64(canvasHeight), color.RGBA{40, 40, 50, 240})
+	draw.FillRect(screen, problemsWidth-2, 0, 2, float64(canvasHeight), color.RGBA{100, 100, 120, 255})
+
+	list := problems(result)
+	title := "PROBLEMS  none"
+	if result != nil && len(list) > 0 {
+		title = fmt.Sprintf("PROBLEMS  %d errors, %d warnings", result.ErrorCount(), result.WarningCount())
+	}
+	ebitenutil.DebugPrintAt(screen, title, 10, 8)
+
+	rowsShown := max(1, (canvasHeight-problemsListOffset-outlinerFooter)/outlinerRowHeight)
+	for i := p.scroll; i < len(list) && i < p.scroll+rowsShown; i++ {
+		problem := list[i]
+		y := problemsListOffset + (i-p.scroll)*outlinerRowHeight
+		if i == p.cursor {
+			draw.FillRect(screen, 4, float64(y), problemsWidth-10, outlinerRowHeight, propertyHoverColor)
+		}
+
+		marker := color.RGBA{255, 200, 0, 255}
+		if problem.Type == TypeError {
+			marker = color.RGBA{255, 60, 60, 255}
+		}
+		draw.FillRect(screen, 8, float64(y+3), 8, 8, marker)
+
+		where := "level"
+		if idx := problem.ObjectIndex; idx >= 0 && idx < len(state.Objects) {
+			where = string(state.Objects[idx].Type)
+			if id := state.Objects[idx].GetPropString("id", ""); id != "" {
+				where += " " + id
+			}
+		}
+		line := fmt.Sprintf("%s: %s", where, FormatValidationError(problem))
+		if maxChars := (problemsWidth - 30) / 6; len(line) > maxChars {
+			line = line[:maxChars-1] + "~"
+		}
+		ebitenutil.DebugPrintAt(screen, line, 22, y-1)
+	}
+
+	ebitenutil.DebugPrintAt(screen, "Click: Go to  V: Validate  Esc: Close", 10, canvasHeight-22)
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -47,6 +50,7 @@ type PropertiesPanel struct {
 	editingBuffer     string                // Text buffer for editing
 	editingProp       string                // Name of property being edited
 	editingBuiltIn    string                // Name of built-in property being edited ("X", "Y", "Width", "Height", or "")
+	editingComponent  int                   // Component of the vec2 being edited (0 = x, 1 = y)
 	scrollOffset      int                   // Scroll offset for long property lists
 	hoveredRow        int                   // Index of hovered property row (-1 if none)
 	hoveredBuiltInRow int                   // Index of hovered built-in row (-1 if none)
@@ -77,18 +81,10 @@ func (p *PropertiesPanel) Draw(screen *ebiten.Image, startY int) {
 	panelHeight := PropertiesPanelHeight
 
 	// Draw panel background
-	panelBg := ebiten.NewImage(ObjectPaletteWidth, panelHeight)
-	panelBg.Fill(propertiesPanelBgColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(panelX), float64(startY))
-	screen.DrawImage(panelBg, op)
+	draw.FillRect(screen, float64(panelX), float64(startY), float64(ObjectPaletteWidth), float64(panelHeight), propertiesPanelBgColor)
 
 	// Draw border at top
-	borderImg := ebiten.NewImage(ObjectPaletteWidth, 1)
-	borderImg.Fill(propertiesBorderColor)
-	op = &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(panelX), float64(startY))
-	screen.DrawImage(borderImg, op)
+	draw.FillRect(screen, float64(panelX), float64(startY), float64(ObjectPaletteWidth), 1, propertiesBorderColor)
 
 	// Draw title
 	titleY := startY + PropertyPadding
@@ -127,11 +123,7 @@ func (p *PropertiesPanel) Draw(screen *ebiten.Image, startY int) {
 
 	// Draw separator
 	sepY := propY + 5
-	sepImg := ebiten.NewImage(ObjectPaletteWidth-2*PropertyPadding, 1)
-	sepImg.Fill(propertiesSeparatorColor)
-	op = &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(panelX+PropertyPadding), float64(sepY))
-	screen.DrawImage(sepImg, op)
+	draw.FillRect(screen, float64(panelX+PropertyPadding), float64(sepY), float64(ObjectPaletteWidth-2*PropertyPadding), 1, propertiesSeparatorColor)
 
 	// Draw custom properties from schema
 	propY = sepY + 10
@@ -162,11 +154,7 @@ func (p *PropertiesPanel) drawBuiltInProperty(screen *ebiten.Image, panelX, y in
 	// Check if this row is being edited
 	if p.editorState == PropertyEditorActive && p.editingBuiltIn == name {
 		// Draw input field background
-		inputBg := ebiten.NewImage(valueWidth, PropertyRowHeight-4)
-		inputBg.Fill(propertyInputBgColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(valueX), float64(y))
-		screen.DrawImage(inputBg, op)
+		draw.FillRect(screen, float64(valueX), float64(y), float64(valueWidth), float64(PropertyRowHeight-4), propertyInputBgColor)
 
 		// Draw editing buffer with cursor
 		displayText := p.editingBuffer + "|"
@@ -174,11 +162,7 @@ func (p *PropertiesPanel) drawBuiltInProperty(screen *ebiten.Image, panelX, y in
 	} else {
 		// Check if hovered
 		if p.hoveredBuiltInRow == rowIndex {
-			hoverBg := ebiten.NewImage(valueWidth, PropertyRowHeight-4)
-			hoverBg.Fill(propertyHoverColor)
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(valueX), float64(y))
-			screen.DrawImage(hoverBg, op)
+			draw.FillRect(screen, float64(valueX), float64(y), float64(valueWidth), float64(PropertyRowHeight-4), propertyHoverColor)
 		}
 
 		// Draw value
@@ -211,27 +195,26 @@ func (p *PropertiesPanel) drawCustomProperty(screen *ebiten.Image, panelX, y int
 		valueWidth -= buttonWidth + 5
 	}
 
-	// Check if this row is being edited
-	if p.editorState == PropertyEditorActive && p.editingProp == propSchema.Name {
+	// Check if this row is being edited (vec2 rows draw their own fields)
+	if propSchema.Type == "vec2" {
+		p.drawVec2Value(screen, valueX, y, valueWidth, value, propSchema, index)
+	} else if p.editorState == PropertyEditorActive && p.editingProp == propSchema.Name {
 		// Draw input field background
-		inputBg := ebiten.NewImage(valueWidth, PropertyRowHeight-4)
-		inputBg.Fill(propertyInputBgColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(valueX), float64(y))
-		screen.DrawImage(inputBg, op)
+		draw.FillRect(screen, float64(valueX), float64(y), float64(valueWidth), float64(PropertyRowHeight-4), propertyInputBgColor)
 
 		// Draw editing buffer with cursor
 		displayText := p.editingBuffer + "|"
 		ebitenutil.DebugPrintAt(screen, displayText, valueX+2, y)
+
+		// Preview the color being typed
+		if propSchema.Type == "color" {
+			drawColorSwatch(screen, valueX+valueWidth-16, y, p.editingBuffer)
+		}
 	} else {
 		// Check if hovered
 		if p.hoveredRow == index {
 			// Draw hover background
-			hoverBg := ebiten.NewImage(valueWidth, PropertyRowHeight-4)
-			hoverBg.Fill(propertyHoverColor)
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(valueX), float64(y))
-			screen.DrawImage(hoverBg, op)
+			draw.FillRect(screen, float64(valueX), float64(y), float64(valueWidth), float64(PropertyRowHeight-4), propertyHoverColor)
 		}
 
 		// Draw value based on type
@@ -253,6 +236,16 @@ func (p *PropertiesPanel) drawCustomProperty(screen *ebiten.Image, panelX, y int
 				checkText = "[x]"
 			}
 			ebitenutil.DebugPrintAt(screen, checkText, valueX, y)
+		case "enum":
+			strVal, _ := value.(string)
+			if strVal == "" {
+				strVal = "(none)"
+			}
+			ebitenutil.DebugPrintAt(screen, "< "+strVal+" >", valueX, y)
+		case "color":
+			strVal, _ := value.(string)
+			drawColorSwatch(screen, valueX, y, strVal)
+			ebitenutil.DebugPrintAt(screen, strVal, valueX+18, y)
 		}
 	}
 
@@ -268,18 +261,11 @@ func (p *PropertiesPanel) drawCustomProperty(screen *ebiten.Image, panelX, y int
 		}
 
 		// Draw button background
-		buttonImg := ebiten.NewImage(buttonWidth, buttonHeight)
-		buttonImg.Fill(buttonColor)
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(buttonX), float64(y))
-		screen.DrawImage(buttonImg, op)
+		draw.FillRect(screen, float64(buttonX), float64(y), float64(buttonWidth), float64(buttonHeight), buttonColor)
 
 		// Draw button border
 		borderColor := color.RGBA{100, 100, 120, 255}
-		ebitenutil.DrawRect(screen, float64(buttonX), float64(y), float64(buttonWidth), 1, borderColor)
-		ebitenutil.DrawRect(screen, float64(buttonX), float64(y+buttonHeight-1), float64(buttonWidth), 1, borderColor)
-		ebitenutil.DrawRect(screen, float64(buttonX), float64(y), 1, float64(buttonHeight), borderColor)
-		ebitenutil.DrawRect(screen, float64(buttonX+buttonWidth-1), float64(y), 1, float64(buttonHeight), borderColor)
+		draw.StrokeRect(screen, float64(buttonX), float64(y), float64(buttonWidth), float64(buttonHeight), 1, borderColor)
 
 		// Draw button text
 		ebitenutil.DebugPrintAt(screen, "Link", buttonX+8, y+2)
@@ -288,8 +274,52 @@ func (p *PropertiesPanel) drawCustomProperty(screen *ebiten.Image, panelX, y int
 	return y + PropertyRowHeight
 }
 
-// getPropertyValue returns the value of a property from the object.
+// drawVec2Value draws a vec2 property as x and y fields side by side, the one
+// being edited as an input field.
+func (p *PropertiesPanel) drawVec2Value(screen *ebiten.Image, valueX, y, valueWidth int, value any, propSchema PropertySchema, index int) {
+	vec, _ := value.([2]float64)
+	half := valueWidth / 2
+	for c, axis := range []string{"x", "y"} {
+		x := valueX + c*half
+		ebitenutil.DebugPrintAt(screen, axis, x, y)
+		fieldX, fieldWidth := x+10, half-14
+		if p.editorState == PropertyEditorActive && p.editingProp == propSchema.Name && p.editingComponent == c {
+			draw.FillRect(screen, float64(fieldX), float64(y), float64(fieldWidth), float64(PropertyRowHeight-4), propertyInputBgColor)
+			ebitenutil.DebugPrintAt(screen, p.editingBuffer+"|", fieldX+2, y)
+			continue
+		}
+		if p.hoveredRow == index {
+			draw.FillRect(screen, float64(fieldX), float64(y), float64(fieldWidth), float64(PropertyRowHeight-4), propertyHoverColor)
+		}
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%.0f", vec[c]), fieldX+2, y)
+	}
+}
+
+// drawColorSwatch draws a small square filled with the hex color at x, y,
+// or crossed out if hex isn't a valid color.
+func drawColorSwatch(screen *ebiten.Image, x, y int, hex string) {
+	const size = 12
+	sx, sy := float64(x), float64(y+2)
+	c, err := gfx.ParseHexColor(hex)
+	if err != nil {
+		draw.FillRect(screen, sx, sy, size, size, propertyInputBgColor)
+		draw.Line(screen, sx, sy, sx+size, sy+size, 1, color.RGBA{255, 60, 60, 255})
+	} else {
+		draw.FillRect(screen, sx, sy, size, size, c)
+	}
+	draw.StrokeRect(screen, sx, sy, size, size, 1, color.RGBA{160, 160, 170, 255})
+}
+
+// getPropertyValue returns the value of a property from the object. A vec2
+// is returned as a [2]float64 of its stored x and y properties.
 func (p *PropertiesPanel) getPropertyValue(obj *world.ObjectData, propSchema PropertySchema) any {
+	if propSchema.Type == "vec2" {
+		var vec [2]float64
+		for c, field := range propSchema.stored() {
+			vec[c] = obj.GetPropFloat(field.Name, field.Default.(float64))
+		}
+		return vec
+	}
 	if obj.Props == nil {
 		return propSchema.Default
 	}
@@ -372,18 +402,28 @@ func (p *PropertiesPanel) startEdit(obj *world.ObjectData, propSchema PropertySc
 	// Get current value and convert to string for editing
 	value := p.getPropertyValue(obj, propSchema)
 	switch propSchema.Type {
-	case "string":
+	case "string", "color":
 		strVal, _ := value.(string)
 		p.editingBuffer = strVal
 	case "float":
 		floatVal, _ := value.(float64)
 		p.editingBuffer = fmt.Sprintf("%.2f", floatVal)
+	case "vec2":
+		// The component to edit was picked by the caller
+		vec, _ := value.([2]float64)
+		p.editingBuffer = fmt.Sprintf("%.2f", vec[p.editingComponent])
 	case "bool":
 		// Bool properties don't use text editing - they toggle directly
 		p.toggleBoolProperty(obj, propSchema)
 		p.editorState = PropertyEditorIdle
 		p.editingIndex = -1
 		p.editingProp = ""
+	case "enum":
+		// Enum properties cycle through their options, backwards with Shift
+		p.cycleEnumProperty(obj, propSchema, ebiten.IsKeyPressed(ebiten.KeyShift))
+		p.editorState = PropertyEditorIdle
+		p.editingIndex = -1
+		p.editingProp = ""
 	default:
 		p.editingBuffer = fmt.Sprintf("%v", value)
 	}
@@ -460,18 +500,31 @@ func (p *PropertiesPanel) confirmEdit() {
 		// Create and execute action
 		action := NewSetPropertyAction(p.state.SelectedObject, propSchema.Name, oldValue, p.editingBuffer)
 		p.state.History.Do(action, p.state)
+	case "color":
+		// Invalid colors are dropped like unparsable numbers
+		if _, err := gfx.ParseHexColor(p.editingBuffer); err == nil {
+			var oldValue any
+			if obj.Props != nil {
+				oldValue = obj.Props[propSchema.Name]
+			}
+			action := NewSetPropertyAction(p.state.SelectedObject, propSchema.Name, oldValue, p.editingBuffer)
+			p.state.History.Do(action, p.state)
+		}
+	case "vec2":
+		floatVal, err := strconv.ParseFloat(strings.TrimSpace(p.editingBuffer), 64)
+		if err == nil {
+			field := propSchema.stored()[p.editingComponent]
+			var oldValue any
+			if obj.Props != nil {
+				oldValue = obj.Props[field.Name]
+			}
+			action := NewSetPropertyAction(p.state.SelectedObject, field.Name, oldValue, clampProperty(floatVal, field))
+			p.state.History.Do(action, p.state)
+		}
 	case "float":
 		floatVal, err := strconv.ParseFloat(p.editingBuffer, 64)
 		if err == nil {
-			// Clamp to min/max
-			if propSchema.Min != 0 || propSchema.Max != 0 {
-				if floatVal < propSchema.Min {
-					floatVal = propSchema.Min
-				}
-				if floatVal > propSchema.Max {
-					floatVal = propSchema.Max
-				}
-			}
+			floatVal = clampProperty(floatVal, *propSchema)
 			// Get old value
 			var oldValue any
 			if obj.Props != nil {
@@ -489,6 +542,14 @@ func (p *PropertiesPanel) confirmEdit() {
 	p.editingBuffer = ""
 }
 
+// clampProperty clamps v to the property's range, if it has one.
+func clampProperty(v float64, propSchema PropertySchema) float64 {
+	if propSchema.Min == 0 && propSchema.Max == 0 {
+		return v
+	}
+	return mathx.Clamp(v, propSchema.Min, propSchema.Max)
+}
+
 // confirmBuiltInEdit applies the edited value for a built-in property.
 func (p *PropertiesPanel) confirmBuiltInEdit(obj *world.ObjectData) {
 	floatVal, err := strconv.ParseFloat(strings.TrimSpace(p.editingBuffer), 64)
@@ -556,6 +617,39 @@ func (p *PropertiesPanel) toggleBoolProperty(obj *world.ObjectData, propSchema P
 	p.state.History.Do(action, p.state)
 }
 
+// cycleEnumProperty sets an enum property to the option after its current
+// value, or before it if back is set. Unknown values go to the first option.
+func (p *PropertiesPanel) cycleEnumProperty(obj *world.ObjectData, propSchema PropertySchema, back bool) {
+	if len(propSchema.Options) == 0 {
+		return
+	}
+
+	var oldValue any
+	if obj.Props != nil {
+		oldValue = obj.Props[propSchema.Name]
+	}
+	current, ok := oldValue.(string)
+	if !ok {
+		current, _ = propSchema.Default.(string)
+	}
+
+	next := 0
+	for i, option := range propSchema.Options {
+		if option != current {
+			continue
+		}
+		step := 1
+		if back {
+			step = len(propSchema.Options) - 1
+		}
+		next = (i + step) % len(propSchema.Options)
+		break
+	}
+
+	action := NewSetPropertyAction(p.state.SelectedObject, propSchema.Name, oldValue, propSchema.Options[next])
+	p.state.History.Do(action, p.state)
+}
+
 // moveToNextProperty moves editing to the next property.
 // Cycles: X → Y → Width → Height → first custom property → ... → X
 func (p *PropertiesPanel) moveToNextProperty() {
@@ -597,6 +691,15 @@ func (p *PropertiesPanel) moveToNextProperty() {
 		return
 	}
 
+	// Go from a vec2's x field to its y field
+	if p.editingIndex >= 0 && p.editingIndex < len(schema.Properties) &&
+		schema.Properties[p.editingIndex].Type == "vec2" && p.editingComponent == 0 {
+		p.editingComponent = 1
+		p.startEdit(obj, schema.Properties[p.editingIndex], p.editingIndex)
+		return
+	}
+	p.editingComponent = 0
+
 	nextIndex := p.editingIndex + 1
 	if nextIndex >= len(schema.Properties) {
 		// Wrap to first built-in
@@ -689,7 +792,12 @@ func (p *PropertiesPanel) HandleClick(screenX, screenY, screenWidth, startY int)
 			}
 
 			if screenX >= valueX && screenX < valueX+valueWidth {
-				// Click is on the value area - start editing
+				// Click is on the value area - start editing, for a vec2 the
+				// half that was clicked
+				p.editingComponent = 0
+				if propSchema.Type == "vec2" && screenX >= valueX+valueWidth/2 {
+					p.editingComponent = 1
+				}
 				p.startEdit(obj, propSchema, i)
 			}
 			return true
@@ -828,11 +936,7 @@ func isEmptyString(s string) bool {
 func (p *PropertiesPanel) drawValidationIssues(screen *ebiten.Image, panelX, y int, issues []ValidationError) int {
 	// Draw separator
 	sepY := y + 5
-	sepImg := ebiten.NewImage(ObjectPaletteWidth-2*PropertyPadding, 1)
-	sepImg.Fill(propertiesSeparatorColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(panelX+PropertyPadding), float64(sepY))
-	screen.DrawImage(sepImg, op)
+	draw.FillRect(screen, float64(panelX+PropertyPadding), float64(sepY), float64(ObjectPaletteWidth-2*PropertyPadding), 1, propertiesSeparatorColor)
 
 	// Draw "Issues" header
 	headerY := sepY + 10
@@ -0,0 +1,355 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// extraFields holds the JSON fields of a Tiled element that the editor
+// doesn't model, such as "class", "parallaxx" or an object's "rotation",
+// so saving writes them back unchanged.
+type extraFields map[string]json.RawMessage
+
+// decodeWithExtra decodes data into v, a pointer to a struct without its
+// own UnmarshalJSON, and returns the fields v has no JSON tag for.
+func decodeWithExtra(data []byte, v any) (extraFields, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var all extraFields
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for _, name := range jsonFieldNames(reflect.TypeOf(v).Elem()) {
+		delete(all, name)
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+	return all, nil
+}
+
+// encodeWithExtra encodes v, a struct without its own MarshalJSON, with
+// the extra fields after its own in name order, so the output is the same
+// for the same data.
+func encodeWithExtra(v any, extra extraFields) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, name := range names {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(extra[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldNames returns the JSON names of a struct type's fields.
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (t *TiledJSON) UnmarshalJSON(data []byte) error {
+	type plain TiledJSON
+	extra, err := decodeWithExtra(data, (*plain)(t))
+	t.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (t TiledJSON) MarshalJSON() ([]byte, error) {
+	type plain TiledJSON
+	return encodeWithExtra(plain(t), t.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+// Layers missing "visible" or "opacity" default to visible and opaque, as
+// in Tiled, and layers of other types than tile layers and object groups
+// are kept as they are. Base64 tile data is decoded into Data and the
+// chunks' Data.
+func (l *TiledLayer) UnmarshalJSON(data []byte) error {
+	type plain TiledLayer
+	p := plain{Visible: true, Opacity: 1}
+	var format struct{ Type, Encoding, Compression string }
+	if err := json.Unmarshal(data, &format); err != nil {
+		return err
+	}
+	if format.Type == "tilelayer" && format.Encoding == world.EncodingBase64 {
+		var err error
+		data, err = recodeTileData(data, func(raw json.RawMessage) (any, error) {
+			return world.DecodeTileData(raw, format.Encoding, format.Compression)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	extra, err := decodeWithExtra(data, &p)
+	*l = TiledLayer(p)
+	l.extra = extra
+	if err == nil && l.Type != "tilelayer" && l.Type != "objectgroup" {
+		l.raw = append(json.RawMessage(nil), data...)
+	}
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back and
+// tile data base64 encoded if the layer's Encoding says so.
+func (l TiledLayer) MarshalJSON() ([]byte, error) {
+	if l.raw != nil {
+		return l.raw, nil
+	}
+	type plain TiledLayer
+	data, err := encodeWithExtra(plain(l), l.extra)
+	if err != nil || l.Type != "tilelayer" || l.Encoding != world.EncodingBase64 {
+		return data, err
+	}
+	return recodeTileData(data, func(raw json.RawMessage) (any, error) {
+		var ids []int
+		if err := json.Unmarshal(raw, &ids); err != nil {
+			return nil, err
+		}
+		return world.EncodeTileData(ids, l.Compression)
+	})
+}
+
+// recodeTileData rewrites the "data" of a tile layer in JSON, and of its
+// chunks, with what recode returns for it. The layer's fields come out in
+// name order.
+func recodeTileData(layer []byte, recode func(json.RawMessage) (any, error)) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(layer, &fields); err != nil {
+		return nil, err
+	}
+	replace := func(fields map[string]json.RawMessage) error {
+		raw, ok := fields["data"]
+		if !ok {
+			return nil
+		}
+		v, err := recode(raw)
+		if err == nil {
+			fields["data"], err = json.Marshal(v)
+		}
+		return err
+	}
+	if err := replace(fields); err != nil {
+		return nil, err
+	}
+	if raw, ok := fields["chunks"]; ok {
+		var chunks []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &chunks); err != nil {
+			return nil, err
+		}
+		for _, c := range chunks {
+			if err := replace(c); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if fields["chunks"], err = json.Marshal(chunks); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+// Objects missing "visible" default to visible, as in Tiled.
+func (o *TiledObject) UnmarshalJSON(data []byte) error {
+	type plain TiledObject
+	p := plain{Visible: true}
+	extra, err := decodeWithExtra(data, &p)
+	*o = TiledObject(p)
+	o.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (o TiledObject) MarshalJSON() ([]byte, error) {
+	type plain TiledObject
+	return encodeWithExtra(plain(o), o.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (p *TiledProperty) UnmarshalJSON(data []byte) error {
+	type plain TiledProperty
+	extra, err := decodeWithExtra(data, (*plain)(p))
+	p.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (p TiledProperty) MarshalJSON() ([]byte, error) {
+	type plain TiledProperty
+	return encodeWithExtra(plain(p), p.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the tileset as it is
+// to write it back, since the editor doesn't change tilesets.
+func (t *TiledTileset) UnmarshalJSON(data []byte) error {
+	type plain TiledTileset
+	if err := json.Unmarshal(data, (*plain)(t)); err != nil {
+		return err
+	}
+	t.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing a loaded tileset back as
+// it was.
+func (t TiledTileset) MarshalJSON() ([]byte, error) {
+	if t.raw != nil {
+		return t.raw, nil
+	}
+	type plain TiledTileset
+	return json.Marshal(plain(t))
+}
+
+// keepSaved carries over from the level as loaded (or last saved) what
+// the editor doesn't edit, so saving an untouched level writes the same
+// file: unknown fields, layer IDs, visibility and offsets, the opacity and
+// tint of object groups, hidden objects, the tilesets, the types of custom
+// properties, and the next free IDs. Layers are matched by name and type and objects by ID; layers the
+// editor doesn't show, such as image layers, stay where they were.
+func keepSaved(out, saved *TiledJSON) {
+	if saved == nil {
+		return
+	}
+	out.extra = saved.extra
+	out.CompressionLevel = saved.CompressionLevel
+	out.Orientation = saved.Orientation
+	out.RenderOrder = saved.RenderOrder
+	out.TiledVersion = saved.TiledVersion
+	out.Version = saved.Version
+	if len(saved.Tilesets) > 0 {
+		out.Tilesets = saved.Tilesets
+	}
+	out.Properties = keepPropertyTypes(out.Properties, saved.Properties)
+	out.NextObjectID = max(out.NextObjectID, saved.NextObjectID)
+
+	savedObjects := make(map[int]*TiledObject)
+	for i := range saved.Layers {
+		for j := range saved.Layers[i].Objects {
+			o := &saved.Layers[i].Objects[j]
+			savedObjects[o.ID] = o
+		}
+	}
+
+	// Keep the saved layer order and IDs; new layers go last with new IDs
+	built := out.Layers
+	used := make([]bool, len(built))
+	layers := make([]TiledLayer, 0, len(built))
+	nextLayerID := saved.NextLayerID
+	for _, sl := range saved.Layers {
+		nextLayerID = max(nextLayerID, sl.ID+1)
+		if sl.Type != "tilelayer" && sl.Type != "objectgroup" {
+			layers = append(layers, sl)
+			continue
+		}
+		for i := range built {
+			if used[i] || built[i].Name != sl.Name || built[i].Type != sl.Type {
+				continue
+			}
+			used[i] = true
+			l := built[i]
+			l.ID, l.Visible, l.X, l.Y = sl.ID, sl.Visible, sl.X, sl.Y
+			if l.Type == "objectgroup" {
+				l.Opacity, l.TintColor = sl.Opacity, sl.TintColor
+			}
+			l.Properties = sl.Properties
+			l.extra = sl.extra
+			layers = append(layers, l)
+			break
+		}
+	}
+	for i, l := range built {
+		if !used[i] {
+			l.ID = nextLayerID
+			nextLayerID++
+			layers = append(layers, l)
+		}
+	}
+	for i := range layers {
+		for j := range layers[i].Objects {
+			o := &layers[i].Objects[j]
+			if so, ok := savedObjects[o.ID]; ok {
+				o.extra = so.extra
+				o.Visible = so.Visible
+				o.Properties = keepPropertyTypes(o.Properties, so.Properties)
+			}
+		}
+	}
+	out.Layers = layers
+	out.NextLayerID = nextLayerID
+}
+
+// keepPropertyTypes gives properties the type and unknown fields they had
+// when saved, as long as the value still fits the type: an "int" property
+// read back as a whole float64 stays "int", a "color" or "file" stays one,
+// and a "class" property keeps its class name ("propertytype").
+func keepPropertyTypes(props, saved []TiledProperty) []TiledProperty {
+	if len(saved) == 0 {
+		return props
+	}
+	byName := make(map[string]*TiledProperty, len(saved))
+	for i := range saved {
+		byName[saved[i].Name] = &saved[i]
+	}
+	for i := range props {
+		sp, ok := byName[props[i].Name]
+		if !ok || !propertyFits(props[i].Value, sp.Type) {
+			continue
+		}
+		props[i].Type = sp.Type
+		props[i].extra = sp.extra
+	}
+	return props
+}
+
+// propertyFits reports whether value can be written as a property of the
+// given Tiled type.
+func propertyFits(value any, typ string) bool {
+	switch v := value.(type) {
+	case string:
+		return typ == "string" || typ == "color" || typ == "file"
+	case float64:
+		return typ == "float" || (typ == "int" || typ == "object") && v == math.Trunc(v)
+	case bool:
+		return typ == "bool"
+	case map[string]any:
+		return typ == "class"
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+//go:build display
+
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// resave loads level data, saves it unchanged and returns what was saved.
+func resave(t *testing.T, data []byte) []byte {
+	t.Helper()
+	state, err := ParseLevel(data, "")
+	if err != nil {
+		t.Fatalf("ParseLevel failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "level.json")
+	if err := SaveLevelAs(state, path); err != nil {
+		t.Fatalf("SaveLevelAs failed: %v", err)
+	}
+	saved, err := storage.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func TestSaveUnchangedLevels(t *testing.T) {
+	paths, err := filepath.Glob("../../assets/levels/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no levels in assets/levels")
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved := resave(t, data); !bytes.Equal(saved, data) {
+			t.Errorf("saving %s unchanged rewrote it:\n%s", path, saved)
+		}
+	}
+}
+
+func TestSaveKeepsUnknownFields(t *testing.T) {
+	data := []byte(`{"class": "castle", "height": 2, "infinite": false, "nextlayerid": 3, "nextobjectid": 2,
+	  "parallaxoriginx": 8, "tileheight": 16, "tilewidth": 16, "type": "map", "width": 2,
+	  "layers": [
+	    {"id": 1, "name": "Tiles", "type": "tilelayer", "width": 2, "height": 2, "data": [1, 0, 0, 1],
+	     "opacity": 1, "visible": true, "x": 0, "y": 0, "parallaxx": 0.5, "locked": true},
+	    {"id": 2, "name": "Objects", "type": "objectgroup", "opacity": 1, "visible": true, "x": 0, "y": 0,
+	     "draworder": "index", "objects": [
+	      {"id": 1, "name": "start", "type": "spawn", "x": 0, "y": 0, "width": 16, "height": 16,
+	       "visible": true, "rotation": 45, "template": "spawn.tx"}
+	    ]}
+	  ]}`)
+	saved := resave(t, data)
+
+	var level struct {
+		Fields map[string]json.RawMessage
+		Layers []map[string]json.RawMessage `json:"layers"`
+	}
+	if err := json.Unmarshal(saved, &level.Fields); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(saved, &level); err != nil {
+		t.Fatal(err)
+	}
+	layers := map[string]map[string]json.RawMessage{}
+	for _, l := range level.Layers {
+		var name string
+		json.Unmarshal(l["name"], &name)
+		layers[name] = l
+	}
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(layers["Objects"]["objects"], &objects); err != nil || len(objects) != 1 {
+		t.Fatalf("saved objects = %s, want one object", layers["Objects"]["objects"])
+	}
+
+	tests := []struct {
+		where  string
+		fields map[string]json.RawMessage
+		name   string
+		want   string
+	}{
+		{"map", level.Fields, "class", `"castle"`},
+		{"map", level.Fields, "parallaxoriginx", `8`},
+		{"tile layer", layers["Tiles"], "parallaxx", `0.5`},
+		{"tile layer", layers["Tiles"], "locked", `true`},
+		{"object group", layers["Objects"], "draworder", `"index"`},
+		{"object", objects[0], "rotation", `45`},
+		{"object", objects[0], "template", `"spawn.tx"`},
+	}
+	for _, tt := range tests {
+		if got := string(tt.fields[tt.name]); got != tt.want {
+			t.Errorf("%s field %q = %q, want %q", tt.where, tt.name, got, tt.want)
+		}
+	}
+
+	// Saving again writes the same bytes
+	if again := resave(t, saved); !bytes.Equal(again, saved) {
+		t.Errorf("saving the saved level again changed it:\n%s\nwant:\n%s", again, saved)
+	}
+}
+
+func TestSaveKeepsVisibilityAndClassProperties(t *testing.T) {
+	data := []byte(`{"height": 2, "infinite": false, "tileheight": 16, "tilewidth": 16, "width": 2, "layers": [
+	  {"id": 1, "name": "Tiles", "type": "tilelayer", "width": 2, "height": 2, "data": [0, 0, 0, 0], "visible": false},
+	  {"id": 2, "name": "Objects", "type": "objectgroup", "objects": [
+	    {"id": 1, "name": "hidden", "type": "checkpoint", "x": 0, "y": 0, "width": 16, "height": 16, "visible": false},
+	    {"id": 2, "name": "default", "type": "checkpoint", "x": 16, "y": 0, "width": 16, "height": 16},
+	    {"id": 3, "name": "gate", "type": "door", "x": 0, "y": 16, "width": 16, "height": 16, "properties": [
+	      {"name": "look", "type": "class", "propertytype": "DoorLook", "value": {"color": "#ff0000", "frames": 4}}
+	    ]}
+	  ]}
+	]}`)
+
+	var loaded TiledJSON
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if objects := loaded.Layers[1].Objects; !objects[1].Visible || !objects[2].Visible {
+		t.Error("objects without \"visible\" loaded hidden, want visible")
+	}
+	if !loaded.Layers[1].Visible {
+		t.Error("layer without \"visible\" loaded hidden, want visible")
+	}
+
+	var saved TiledJSON
+	if err := json.Unmarshal(resave(t, data), &saved); err != nil {
+		t.Fatal(err)
+	}
+	layers := map[string]TiledLayer{}
+	for _, l := range saved.Layers {
+		layers[l.Name] = l
+	}
+	if layers["Tiles"].Visible {
+		t.Error("hidden tile layer saved visible")
+	}
+	if !layers["Objects"].Visible {
+		t.Error("object group saved hidden, want visible")
+	}
+
+	objects := map[string]TiledObject{}
+	for _, o := range layers["Objects"].Objects {
+		objects[o.Name] = o
+	}
+	for name, want := range map[string]bool{"hidden": false, "default": true, "gate": true} {
+		if got := objects[name].Visible; got != want {
+			t.Errorf("object %q saved with visible %v, want %v", name, got, want)
+		}
+	}
+
+	props := objects["gate"].Properties
+	if len(props) != 1 {
+		t.Fatalf("gate saved with properties %+v, want one", props)
+	}
+	got, err := json.Marshal(props[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"look","type":"class","value":{"color":"#ff0000","frames":4},"propertytype":"DoorLook"}`; string(got) != want {
+		t.Errorf("class property saved as %s, want %s", got, want)
+	}
+}
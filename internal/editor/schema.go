@@ -4,17 +4,43 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/schema"
+	"github.com/torsten/GoP/internal/tween"
 	"github.com/torsten/GoP/internal/world"
 )
 
 // PropertySchema defines the schema for a single object property.
 type PropertySchema struct {
-	Name     string  // Property name
-	Type     string  // Property type: "string", "float", "bool", "int"
-	Required bool    // Whether the property is required
-	Default  any     // Default value if not specified
-	Min      float64 // Minimum value for float/int types
-	Max      float64 // Maximum value for float/int types
+	Name     string   // Property name
+	Type     string   // Property type: "string", "float", "bool", "int", "enum", "color", "vec2"
+	Required bool     // Whether the property is required
+	Default  any      // Default value if not specified ([2]float64 for vec2)
+	Min      float64  // Minimum value for float/int/vec2 types
+	Max      float64  // Maximum value for float/int/vec2 types
+	Options  []string // Allowed values for enum types, stored as strings
+}
+
+// stored returns the properties a value is stored as in the level file. A
+// vec2 is stored as two floats, <name>X and <name>Y, like a platform's
+// endX and endY; other types are stored as themselves.
+func (ps PropertySchema) stored() []PropertySchema {
+	if ps.Type != "vec2" {
+		return []PropertySchema{ps}
+	}
+	def, _ := ps.Default.([2]float64)
+	fields := make([]PropertySchema, 2)
+	for i, axis := range []string{"X", "Y"} {
+		fields[i] = PropertySchema{
+			Name:     ps.Name + axis,
+			Type:     "float",
+			Required: ps.Required,
+			Default:  def[i],
+			Min:      ps.Min,
+			Max:      ps.Max,
+		}
+	}
+	return fields
 }
 
 // ObjectSchema defines the schema for an object type.
@@ -26,19 +52,21 @@ type ObjectSchema struct {
 	DefaultH   float64          // Default height in pixels
 	Properties []PropertySchema // Property schemas
 	Color      string           // Color for rendering (hex string)
+	SpawnAs    world.ObjectType // Built-in type the game spawns a custom type as ("" = none)
 }
 
 // SchemaRegistry holds all object schemas.
 var SchemaRegistry = map[world.ObjectType]*ObjectSchema{
 	world.ObjectTypeSpawn: {
-		Type:       string(world.ObjectTypeSpawn),
-		Name:       "Player Spawn",
-		Icon:       "spawn",
-		DefaultW:   32,
-		DefaultH:   32,
-		Color:      "#00FF00", // Green
+		Type:     string(world.ObjectTypeSpawn),
+		Name:     "Player Spawn",
+		Icon:     "spawn",
+		DefaultW: 32,
+		DefaultH: 32,
+		Color:    "#00FF00", // Green
 		Properties: []PropertySchema{
-			// Spawn has no optional properties
+			// Exits from other rooms arrive at the spawn with their spawn id
+			{Name: "id", Type: "string", Required: false, Default: ""},
 		},
 	},
 	world.ObjectTypePlatform: {
@@ -50,11 +78,21 @@ var SchemaRegistry = map[world.ObjectType]*ObjectSchema{
 		Color:    "#8040C0", // Purple
 		Properties: []PropertySchema{
 			{Name: "id", Type: "string", Required: false, Default: ""},
-			{Name: "endX", Type: "float", Required: false, Default: 0.0, Min: 0, Max: 10000},
-			{Name: "endY", Type: "float", Required: false, Default: 0.0, Min: 0, Max: 10000},
+			// Comma-separated tags, so rules can act on all targets with a tag ("group: lights")
+			{Name: world.PropTags, Type: "string", Required: false, Default: ""},
+			// Offset of the far end of the path, stored as endX and endY
+			{Name: "end", Type: "vec2", Required: false, Default: [2]float64{0, 0}, Min: 0, Max: 10000},
+			// Id of a path object to follow instead, centered on it
+			{Name: world.PropPlatformPath, Type: "string", Required: false, Default: ""},
 			{Name: "speed", Type: "float", Required: false, Default: 100.0, Min: 0, Max: 1000},
 			{Name: "waitTime", Type: "float", Required: false, Default: 0.5, Min: 0, Max: 10},
+			// Easing of each trip: linear, inQuad, outQuad, inOutQuad, outCubic, or smooth
+			{Name: world.PropEase, Type: "enum", Required: false, Default: "linear", Options: tween.EaseNames},
 			{Name: "pushPlayer", Type: "bool", Required: false, Default: false},
+			// Stays put until a switch activates it
+			{Name: "startStopped", Type: "bool", Required: false, Default: false},
+			// Render order: lower draws first, the player at 0
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypePlatform)), Min: -100, Max: 100},
 		},
 	},
 	world.ObjectTypeSwitch: {
@@ -68,6 +106,12 @@ var SchemaRegistry = map[world.ObjectType]*ObjectSchema{
 			{Name: "door_id", Type: "string", Required: false, Default: ""},
 			{Name: "toggle", Type: "bool", Required: false, Default: true},
 			{Name: "once", Type: "bool", Required: false, Default: false},
+			// toggle (lever), plate (held while stood on), or timed (reverts after duration)
+			{Name: "mode", Type: "enum", Required: false, Default: "toggle", Options: []string{"toggle", "plate", "timed"}},
+			{Name: "duration", Type: "float", Required: false, Default: 3.0, Min: 0.1, Max: 600},
+			// Further door/platform IDs, comma-separated (Shift+click in link mode)
+			{Name: "targets", Type: "string", Required: false, Default: ""},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeSwitch)), Min: -100, Max: 100},
 		},
 	},
 	world.ObjectTypeDoor: {
@@ -79,18 +123,37 @@ var SchemaRegistry = map[world.ObjectType]*ObjectSchema{
 		Color:    "#0080FF", // Blue
 		Properties: []PropertySchema{
 			{Name: "id", Type: "string", Required: false, Default: ""},
+			// Comma-separated tags, so rules can act on all targets with a tag ("group: lights")
+			{Name: world.PropTags, Type: "string", Required: false, Default: ""},
 			{Name: "startOpen", Type: "bool", Required: false, Default: false},
+			// What happens when closing on the player: block, wait, or push
+			{Name: "obstruction", Type: "enum", Required: false, Default: "wait", Options: []string{"block", "wait", "push"}},
+			// Seconds to slide open or closed (0 snaps), toward up, down, left, or right
+			{Name: "openTime", Type: "float", Required: false, Default: 0.25, Min: 0, Max: 5},
+			{Name: world.PropEase, Type: "enum", Required: false, Default: "linear", Options: tween.EaseNames},
+			{Name: "openDirection", Type: "enum", Required: false, Default: "up", Options: []string{"up", "down", "left", "right"}},
+			// Locked doors open on contact with keys, using up "keys" keys or the key "key_id"
+			{Name: "locked", Type: "bool", Required: false, Default: false},
+			{Name: "keys", Type: "float", Required: false, Default: 1.0, Min: 1, Max: 99},
+			{Name: "key_id", Type: "string", Required: false, Default: ""},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeDoor)), Min: -100, Max: 100},
 		},
 	},
 	world.ObjectTypeHazard: {
-		Type:       string(world.ObjectTypeHazard),
-		Name:       "Hazard",
-		Icon:       "hazard",
-		DefaultW:   32,
-		DefaultH:   32,
-		Color:      "#FF0000", // Red
+		Type:     string(world.ObjectTypeHazard),
+		Name:     "Hazard",
+		Icon:     "hazard",
+		DefaultW: 32,
+		DefaultH: 32,
+		Color:    "#FF0000", // Red
 		Properties: []PropertySchema{
-			// Hazard has no optional properties
+			// Damage per touch, for the health system; without one any touch kills
+			{Name: "damage", Type: "float", Required: false, Default: 1.0, Min: 0, Max: 99},
+			// Spikes: up, down, left, or right hurt from that side only; empty hurts from all sides
+			{Name: "direction", Type: "enum", Required: false, Default: "", Options: []string{"", "up", "down", "left", "right"}},
+			// ID of a moving platform the hazard rides on
+			{Name: "platform", Type: "string", Required: false, Default: ""},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeHazard)), Min: -100, Max: 100},
 		},
 	},
 	world.ObjectTypeCheckpoint: {
@@ -102,17 +165,132 @@ var SchemaRegistry = map[world.ObjectType]*ObjectSchema{
 		Color:    "#00FFFF", // Cyan
 		Properties: []PropertySchema{
 			{Name: "id", Type: "string", Required: false, Default: ""},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeCheckpoint)), Min: -100, Max: 100},
 		},
 	},
 	world.ObjectTypeGoal: {
-		Type:       string(world.ObjectTypeGoal),
-		Name:       "Goal",
-		Icon:       "goal",
-		DefaultW:   48,
-		DefaultH:   64,
-		Color:      "#FFD700", // Gold
+		Type:     string(world.ObjectTypeGoal),
+		Name:     "Goal",
+		Icon:     "goal",
+		DefaultW: 48,
+		DefaultH: 64,
+		Color:    "#FFD700", // Gold
+		Properties: []PropertySchema{
+			// Completion requirements; all off means touching the goal is enough
+			{Name: world.PropRequireCheckpoints, Type: "bool", Required: false, Default: false},
+			{Name: world.PropRequiredCollectibles, Type: "float", Required: false, Default: 0.0, Min: 0, Max: 1000},
+			{Name: world.PropParTime, Type: "float", Required: false, Default: 0.0, Min: 0, Max: 3600},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeGoal)), Min: -100, Max: 100},
+		},
+	},
+	world.ObjectTypeKillPlane: {
+		Type:       string(world.ObjectTypeKillPlane),
+		Name:       "Kill Plane",
+		Icon:       "killplane",
+		DefaultW:   64,
+		DefaultH:   8,
+		Color:      "#FF4040", // Light red
+		Properties: []PropertySchema{
+			// Kill plane has no optional properties; its top edge is the kill line
+		},
+	},
+	world.ObjectTypeCollectible: {
+		Type:     string(world.ObjectTypeCollectible),
+		Name:     "Collectible",
+		Icon:     "collectible",
+		DefaultW: 12,
+		DefaultH: 12,
+		Color:    "#FFDC40", // Amber
+		Properties: []PropertySchema{
+			{Name: "id", Type: "string", Required: false, Default: ""},
+			// Gameplay variable the pickup adds one to, for rules and the HUD
+			{Name: world.PropCollectibleCounter, Type: "string", Required: false, Default: "collectibles"},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeCollectible)), Min: -100, Max: 100},
+		},
+	},
+	world.ObjectTypeKey: {
+		Type:     string(world.ObjectTypeKey),
+		Name:     "Key",
+		Icon:     "key",
+		DefaultW: 16,
+		DefaultH: 12,
+		Color:    "#FFC828", // Gold
+		Properties: []PropertySchema{
+			// Doors with a matching key_id need this key; others take any key
+			{Name: "id", Type: "string", Required: false, Default: ""},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeKey)), Min: -100, Max: 100},
+		},
+	},
+	world.ObjectTypeExit: {
+		Type:     string(world.ObjectTypeExit),
+		Name:     "Exit",
+		Icon:     "exit",
+		DefaultW: 32,
+		DefaultH: 64,
+		Color:    "#A050FF", // Violet
+		Properties: []PropertySchema{
+			// Level file relative to this one (empty = this level) and the spawn id to arrive at
+			{Name: world.PropExitLevel, Type: "string", Required: false, Default: ""},
+			{Name: world.PropExitSpawn, Type: "string", Required: false, Default: ""},
+			{Name: world.PropZ, Type: "float", Required: false, Default: float64(world.DefaultZ(world.ObjectTypeExit)), Min: -100, Max: 100},
+		},
+	},
+	world.ObjectTypeSound: {
+		Type:     string(world.ObjectTypeSound),
+		Name:     "Sound Emitter",
+		Icon:     "sound",
+		DefaultW: 16,
+		DefaultH: 16,
+		Color:    "#40C8C8", // Teal
+		Properties: []PropertySchema{
+			{Name: world.PropSoundClip, Type: "string", Required: false, Default: ""},
+			{Name: world.PropSoundRadius, Type: "float", Required: false, Default: world.DefaultSoundRadius, Min: 1, Max: 4000},
+			{Name: world.PropSoundVolume, Type: "float", Required: false, Default: world.DefaultSoundVolume, Min: 0, Max: 1},
+			// linear, quadratic, or none (ambient: full volume everywhere)
+			{Name: world.PropSoundFalloff, Type: "enum", Required: false, Default: "linear", Options: []string{"linear", "quadratic", "none"}},
+		},
+	},
+	world.ObjectTypeForceZone: {
+		Type:     string(world.ObjectTypeForceZone),
+		Name:     "Force Zone",
+		Icon:     "force",
+		DefaultW: 64,
+		DefaultH: 64,
+		Color:    "#60A0FF", // Sky blue
+		Properties: []PropertySchema{
+			// Gravity multiplier inside: 0.3 = low gravity, 0 = weightless, negative = pulls up
+			{Name: world.PropForceGravityScale, Type: "float", Required: false, Default: world.DefaultForceGravityScale, Min: -5, Max: 5},
+			// Constant acceleration in px/s², stored as windX and windY; negative y = updraft
+			{Name: "wind", Type: "vec2", Required: false, Default: [2]float64{0, 0}, Min: -5000, Max: 5000},
+		},
+	},
+	world.ObjectTypePath: {
+		Type:     string(world.ObjectTypePath),
+		Name:     "Path",
+		Icon:     "path",
+		DefaultW: 64,
+		DefaultH: 32,
+		Color:    "#E0E060", // Pale olive
+		Properties: []PropertySchema{
+			// Platforms name it in their path property
+			{Name: "id", Type: "string", Required: false, Default: ""},
+		},
+	},
+	world.ObjectTypeLight: {
+		Type:     string(world.ObjectTypeLight),
+		Name:     "Light",
+		Icon:     "light",
+		DefaultW: 16,
+		DefaultH: 16,
+		Color:    "#FFF0A0", // Pale yellow
 		Properties: []PropertySchema{
-			// Goal has no optional properties
+			{Name: "id", Type: "string", Required: false, Default: ""},
+			// Comma-separated tags, so rules can act on all targets with a tag ("group: lights")
+			{Name: world.PropTags, Type: "string", Required: false, Default: ""},
+			{Name: "radius", Type: "float", Required: false, Default: 64.0, Min: 1, Max: 1000},
+			{Name: "color", Type: "color", Required: false, Default: "#FFFFFF"},
+			{Name: "flicker", Type: "float", Required: false, Default: 0.0, Min: 0, Max: 1},
+			{Name: "startOn", Type: "bool", Required: false, Default: true},
 		},
 	},
 }
@@ -138,8 +316,19 @@ func GetAllSchemas() []*ObjectSchema {
 		world.ObjectTypeHazard,
 		world.ObjectTypeCheckpoint,
 		world.ObjectTypeGoal,
+		world.ObjectTypeKillPlane,
+		world.ObjectTypeLight,
+		world.ObjectTypeCollectible,
+		world.ObjectTypeKey,
+		world.ObjectTypeExit,
+		world.ObjectTypeSound,
+		world.ObjectTypeForceZone,
+		world.ObjectTypePath,
 	}
 
+	// Custom types follow in the order they were registered
+	order = append(order, customTypes...)
+
 	schemas := make([]*ObjectSchema, 0, len(order))
 	for _, typ := range order {
 		if schema, ok := SchemaRegistry[typ]; ok {
@@ -149,6 +338,31 @@ func GetAllSchemas() []*ObjectSchema {
 	return schemas
 }
 
+// SchemaObjectTypes returns the object schemas in the form used by the
+// exported level schema (see internal/schema).
+func SchemaObjectTypes() []schema.ObjectType {
+	all := GetAllSchemas()
+	types := make([]schema.ObjectType, len(all))
+	for i, s := range all {
+		var props []schema.Property
+		for _, ps := range s.Properties {
+			for _, p := range ps.stored() {
+				props = append(props, schema.Property{
+					Name:     p.Name,
+					Type:     p.Type,
+					Required: p.Required,
+					Default:  p.Default,
+					Min:      p.Min,
+					Max:      p.Max,
+					Options:  p.Options,
+				})
+			}
+		}
+		types[i] = schema.ObjectType{Type: s.Type, Name: s.Name, Properties: props}
+	}
+	return types
+}
+
 // CreateDefaultObject creates an ObjectData with default values for the given type.
 func CreateDefaultObject(typ world.ObjectType, x, y float64) world.ObjectData {
 	schema := GetSchema(typ)
@@ -165,13 +379,15 @@ func CreateDefaultObject(typ world.ObjectType, x, y float64) world.ObjectData {
 
 	// Create properties with default values
 	props := make(map[string]any)
-	for _, propSchema := range schema.Properties {
-		if propSchema.Default != nil {
-			props[propSchema.Name] = propSchema.Default
+	for _, ps := range schema.Properties {
+		for _, propSchema := range ps.stored() {
+			if propSchema.Default != nil {
+				props[propSchema.Name] = propSchema.Default
+			}
 		}
 	}
 
-	return world.ObjectData{
+	obj := world.ObjectData{
 		Type:  typ,
 		X:     x,
 		Y:     y,
@@ -179,13 +395,19 @@ func CreateDefaultObject(typ world.ObjectType, x, y float64) world.ObjectData {
 		H:     schema.DefaultH,
 		Props: props,
 	}
+	// Paths start as a diagonal line, sized in the properties panel; other
+	// polylines and polygons are drawn in Tiled
+	if typ == world.ObjectTypePath {
+		obj.Polyline = []mathx.Vec2{mathx.V(0, 0), mathx.V(obj.W, obj.H)}
+	}
+	return obj
 }
 
 // NeedsAutoID returns true if the object type needs an auto-generated ID property.
 // These are entities that can be targeted by other entities (e.g., doors, platforms, checkpoints).
 func NeedsAutoID(typ world.ObjectType) bool {
 	switch typ {
-	case world.ObjectTypeDoor, world.ObjectTypePlatform, world.ObjectTypeCheckpoint:
+	case world.ObjectTypeDoor, world.ObjectTypePlatform, world.ObjectTypeCheckpoint, world.ObjectTypePath:
 		return true
 	default:
 		return false
@@ -4,6 +4,7 @@ package editor
 import (
 	"sort"
 
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -177,23 +178,82 @@ func (sm *SelectionManager) GetSelectedObjects(objects []world.ObjectData) []*wo
 func (sm *SelectionManager) HitTest(worldX, worldY float64, objects []world.ObjectData) int {
 	// Check objects in reverse order (top-most first)
 	for i := len(objects) - 1; i >= 0; i-- {
-		obj := objects[i]
-		if sm.PointInRect(worldX, worldY, obj.X, obj.Y, obj.W, obj.H) {
+		if sm.PointOnObject(worldX, worldY, &objects[i]) {
 			return i
 		}
 	}
 	return -1
 }
 
+// HitTestVisible is like HitTest but skips objects on hidden layers.
+func (sm *SelectionManager) HitTestVisible(worldX, worldY float64, state *EditorState) int {
+	for i := len(state.Objects) - 1; i >= 0; i-- {
+		obj := &state.Objects[i]
+		if state.IsObjectVisible(obj) && sm.PointOnObject(worldX, worldY, obj) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IntersectingVisible returns the indices of objects on visible layers that
+// overlap the rectangle, in level order.
+func (sm *SelectionManager) IntersectingVisible(x, y, w, h float64, state *EditorState) []int {
+	var indices []int
+	for i := range state.Objects {
+		obj := &state.Objects[i]
+		if !state.IsObjectVisible(obj) {
+			continue
+		}
+		if obj.X < x+w && obj.X+obj.W > x && obj.Y < y+h && obj.Y+obj.H > y {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
 // PointInRect checks if a point is inside a rectangle.
 func (sm *SelectionManager) PointInRect(px, py, rx, ry, rw, rh float64) bool {
 	return px >= rx && px < rx+rw && py >= ry && py < ry+rh
 }
 
+// shapeHitDistance is how close, in world pixels, a click must be to a
+// polyline or a polygon's edge to hit it.
+const shapeHitDistance = 4.0
+
+// pointHitDistance is how close, in world pixels, a click must be to a
+// point object to hit it.
+const pointHitDistance = 8.0
+
+// PointOnObject checks if a point is on an object: inside its rectangle,
+// ellipse or polygon, or near its polyline, polygon edges or point.
+func (sm *SelectionManager) PointOnObject(px, py float64, obj *world.ObjectData) bool {
+	p := mathx.V(px, py)
+	if obj.Point {
+		return p.Dist(mathx.V(obj.X, obj.Y)) <= pointHitDistance
+	}
+	points := obj.Points()
+	if points == nil {
+		if obj.Ellipse {
+			return mathx.EllipseContains(obj.X, obj.Y, obj.W, obj.H, p)
+		}
+		return sm.PointInRect(px, py, obj.X, obj.Y, obj.W, obj.H)
+	}
+	if len(obj.Polygon) > 0 {
+		if mathx.PolygonContains(points, p) {
+			return true
+		}
+		points = append(points, points[0])
+	}
+	return mathx.PolylineDistance(points, p) <= shapeHitDistance
+}
+
 // GetHandleAtPosition checks if a point is on a resize handle of the primary selected object.
 // Returns HandleNone if no handle is hit.
+// Polygons and polylines have no handles; their size is set in the
+// properties panel, scaling their points. Points have no size.
 func (sm *SelectionManager) GetHandleAtPosition(worldX, worldY float64, obj *world.ObjectData, handleSize float64) HandlePosition {
-	if obj == nil {
+	if obj == nil || obj.IsShape() || obj.Point {
 		return HandleNone
 	}
 
@@ -252,7 +312,7 @@ func (sm *SelectionManager) IsOnObjectBody(worldX, worldY float64, obj *world.Ob
 	}
 
 	// Then check if we're on the object body
-	return sm.PointInRect(worldX, worldY, obj.X, obj.Y, obj.W, obj.H)
+	return sm.PointOnObject(worldX, worldY, obj)
 }
 
 // BeginMove starts a move operation for all selected objects.
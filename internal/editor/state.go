@@ -31,10 +31,11 @@ type StatusMessage struct {
 // EditorState holds all the state for the level editor.
 type EditorState struct {
 	// Level data
-	FilePath string             // Path to the current level file
-	MapData  *world.MapData     // Parsed map data
-	Objects  []world.ObjectData // Objects from the level
-	Tileset  *world.Tileset     // Loaded tileset
+	FilePath   string             // Path to the current level file
+	MapData    *world.MapData     // Parsed map data
+	Objects    []world.ObjectData // Objects from the level
+	Tileset    *world.Tileset     // Loaded tileset
+	TileFormat TileFormat         // How saving stores tile data, as loaded
 
 	// UI state
 	CurrentTool       Tool            // Currently selected tool
@@ -45,6 +46,11 @@ type EditorState struct {
 	SelectedObject    int             // Object index for selection (-1 if none)
 	SpacePressed      bool            // True when Space key is held (for drag-to-scroll)
 
+	// Object layers
+	ObjectLayers       []string        // Names of the object layers, in file order
+	ActiveObjectLayer  string          // Layer new objects are placed on
+	hiddenObjectLayers map[string]bool // Object layers hidden in the canvas
+
 	// View state
 	CameraX float64 // Camera X position in world coordinates
 	CameraY float64 // Camera Y position in world coordinates
@@ -54,7 +60,8 @@ type EditorState struct {
 	History *History // Undo/redo history manager
 
 	// Modification tracking
-	modified bool // True if there are unsaved changes
+	modified bool       // True if there are unsaved changes
+	saved    *TiledJSON // The level as loaded or last saved, see keepSaved
 
 	// Selection manager reference (set by tool manager)
 	selectionManager *SelectionManager
@@ -0,0 +1,119 @@
+package editor
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// StatusBarHeight is the height of the status bar at the bottom of the canvas.
+const StatusBarHeight = 20
+
+// Status bar layout
+const (
+	statusBarCharWidth = 6 // Width of a debug font character
+	statusSegmentPad   = 6 // Space left and right of a segment's text
+	statusTextOffsetY  = 3 // Text offset from the top of the bar
+	statusSeparatorW   = 1 // Width of the line between segments
+)
+
+// Status bar colors
+var (
+	statusBarColor       = color.RGBA{25, 25, 35, 240}
+	statusBarBorderColor = color.RGBA{70, 70, 90, 255}
+	statusErrorColor     = color.RGBA{160, 40, 40, 255}
+	statusWarningColor   = color.RGBA{150, 110, 30, 255}
+)
+
+// StatusSegment is one field of the status bar. Segments with OnClick act
+// as buttons.
+type StatusSegment struct {
+	Text    string
+	Color   color.Color // Background, nil for the bar color
+	OnClick func()
+}
+
+// StatusBar is the strip along the bottom of the canvas showing the cursor
+// position, tool, layers, zoom, selection and validation state.
+type StatusBar struct {
+	segments []StatusSegment
+	y        int
+	width    int
+	hovered  int // Index of the clickable segment under the cursor, -1 if none
+}
+
+// NewStatusBar creates a new status bar.
+func NewStatusBar() *StatusBar {
+	return &StatusBar{hovered: -1}
+}
+
+// SetSegments replaces the fields shown, left to right.
+func (b *StatusBar) SetSegments(segments []StatusSegment) {
+	b.segments = segments
+}
+
+// segmentBounds returns the x range of each segment.
+func (b *StatusBar) segmentBounds() [][2]int {
+	bounds := make([][2]int, len(b.segments))
+	x := 0
+	for i, s := range b.segments {
+		w := len(s.Text)*statusBarCharWidth + 2*statusSegmentPad
+		bounds[i] = [2]int{x, x + w}
+		x += w + statusSeparatorW
+	}
+	return bounds
+}
+
+// Update places the bar at the bottom of a canvas of the given size and
+// handles clicks on its buttons. Returns true if the mouse is over the
+// bar, so the canvas shouldn't handle it.
+func (b *StatusBar) Update(canvasWidth, screenHeight int) bool {
+	b.y = screenHeight - StatusBarHeight
+	b.width = canvasWidth
+	b.hovered = -1
+
+	mx, my := ebiten.CursorPosition()
+	if my < b.y || my >= screenHeight || mx < 0 || mx >= b.width {
+		return false
+	}
+
+	for i, r := range b.segmentBounds() {
+		if mx >= r[0] && mx < r[1] && b.segments[i].OnClick != nil {
+			b.hovered = i
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				b.segments[i].OnClick()
+			}
+			break
+		}
+	}
+	return true
+}
+
+// Draw renders the bar. Segments that don't fit are left out.
+func (b *StatusBar) Draw(screen *ebiten.Image) {
+	if b.width <= 0 {
+		return
+	}
+
+	draw.FillRect(screen, 0, float64(b.y), float64(b.width), StatusBarHeight, statusBarColor)
+	draw.FillRect(screen, 0, float64(b.y), float64(b.width), 1, statusBarBorderColor)
+
+	for i, r := range b.segmentBounds() {
+		if r[1] > b.width {
+			break
+		}
+		s := b.segments[i]
+		bg := s.Color
+		if i == b.hovered {
+			bg = propertyHoverColor
+		}
+		if bg != nil {
+			draw.FillRect(screen, float64(r[0]), float64(b.y+1), float64(r[1]-r[0]), StatusBarHeight-1, bg)
+		}
+		ebitenutil.DebugPrintAt(screen, s.Text, r[0]+statusSegmentPad, b.y+statusTextOffsetY)
+		draw.FillRect(screen, float64(r[1]), float64(b.y), statusSeparatorW, StatusBarHeight, statusBarBorderColor)
+	}
+}
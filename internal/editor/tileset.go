@@ -2,11 +2,11 @@ package editor
 
 import (
 	"image/color"
-	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/torsten/GoP/internal/assets"
+	"github.com/torsten/GoP/internal/gfx/draw"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -30,12 +30,17 @@ func NewTileset() *Tileset {
 	// Load tileset as raw image for pixel access (needed before game loop starts)
 	rawImg, err := assets.LoadTilesetRaw()
 	if err != nil {
-		log.Printf("Failed to load tileset: %v", err)
+		logger.Errorf("Failed to load tileset: %v", err)
 		return &Tileset{}
 	}
 
 	// Create world.Tileset (16x16 pixel tiles)
 	ts := world.NewTilesetFromImage(rawImg, DefaultTileSize, DefaultTileSize)
+	if data, err := assets.LoadTilesetDescriptor(); err != nil {
+		logger.Warnf("Failed to load tile animations: %v", err)
+	} else if err := ts.LoadAnimations(data); err != nil {
+		logger.Warnf("Failed to load tile animations: %v", err)
+	}
 
 	t := &Tileset{
 		tileset: ts,
@@ -145,11 +150,7 @@ func (t *Tileset) DrawCollisionPalette(screen *ebiten.Image, selectedSolid bool)
 // DrawCollisionPaletteAt renders the collision palette at a specific X position.
 func (t *Tileset) DrawCollisionPaletteAt(screen *ebiten.Image, selectedSolid bool, paletteX int) {
 	// Draw palette background
-	paletteBg := ebiten.NewImage(PaletteWidth, screen.Bounds().Dy())
-	paletteBg.Fill(paletteBgColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(paletteX), 0)
-	screen.DrawImage(paletteBg, op)
+	draw.FillRect(screen, float64(paletteX), 0, float64(PaletteWidth), float64(screen.Bounds().Dy()), paletteBgColor)
 
 	// Draw title
 	ebitenutil.DebugPrintAt(screen, "Collision", paletteX+PalettePadding, PalettePadding)
@@ -160,34 +161,26 @@ func (t *Tileset) DrawCollisionPaletteAt(screen *ebiten.Image, selectedSolid boo
 
 	// Solid option (red)
 	solidX := paletteX + PalettePadding
-	solidImg := ebiten.NewImage(optionSize, optionSize)
-	solidImg.Fill(collisionSolidColor)
-	op = &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(solidX), float64(optionY))
-	screen.DrawImage(solidImg, op)
+	draw.FillRect(screen, float64(solidX), float64(optionY), float64(optionSize), float64(optionSize), collisionSolidColor)
 
 	// Draw "Solid" label
 	ebitenutil.DebugPrintAt(screen, "Solid", solidX, optionY+optionSize+4)
 
 	// Highlight if selected
 	if selectedSolid {
-		ebitenutil.DrawRect(screen, float64(solidX)-2, float64(optionY)-2, float64(optionSize)+4, float64(optionSize)+4, selectionColor)
+		draw.FillRect(screen, float64(solidX)-2, float64(optionY)-2, float64(optionSize)+4, float64(optionSize)+4, selectionColor)
 	}
 
 	// Empty option (gray)
 	emptyX := paletteX + PalettePadding + optionSize + 16
-	emptyImg := ebiten.NewImage(optionSize, optionSize)
-	emptyImg.Fill(collisionEmptyColor)
-	op = &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(emptyX), float64(optionY))
-	screen.DrawImage(emptyImg, op)
+	draw.FillRect(screen, float64(emptyX), float64(optionY), float64(optionSize), float64(optionSize), collisionEmptyColor)
 
 	// Draw "Empty" label
 	ebitenutil.DebugPrintAt(screen, "Empty", emptyX, optionY+optionSize+4)
 
 	// Highlight if selected
 	if !selectedSolid {
-		ebitenutil.DrawRect(screen, float64(emptyX)-2, float64(optionY)-2, float64(optionSize)+4, float64(optionSize)+4, selectionColor)
+		draw.FillRect(screen, float64(emptyX)-2, float64(optionY)-2, float64(optionSize)+4, float64(optionSize)+4, selectionColor)
 	}
 }
 
@@ -198,14 +191,10 @@ func (t *Tileset) DrawPaletteAt(screen *ebiten.Image, selectedTile int, paletteX
 	}
 
 	// Draw palette background
-	paletteBg := ebiten.NewImage(PaletteWidth, screen.Bounds().Dy())
-	paletteBg.Fill(paletteBgColor)
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(paletteX), 0)
-	screen.DrawImage(paletteBg, op)
+	draw.FillRect(screen, float64(paletteX), 0, float64(PaletteWidth), float64(screen.Bounds().Dy()), paletteBgColor)
 
 	// Draw the pre-rendered palette image
-	op = &ebiten.DrawImageOptions{}
+	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(paletteX+PalettePadding), float64(PalettePadding))
 	screen.DrawImage(t.paletteImg, op)
 
@@ -226,13 +215,7 @@ func (t *Tileset) drawSelectionHighlight(screen *ebiten.Image, selectedTile int,
 	y := PalettePadding + ty*PaletteTileSize
 
 	// Draw highlight rectangle
-	highlight := ebiten.NewImage(PaletteTileSize, PaletteTileSize)
-	highlight.Fill(selectionColor)
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(x), float64(y))
-	op.ColorM.Scale(1, 1, 1, 0.3) // Semi-transparent
-	screen.DrawImage(highlight, op)
+	draw.FillRect(screen, float64(x), float64(y), PaletteTileSize, PaletteTileSize, draw.Fade(selectionColor, 0.3))
 }
 
 // TileAtPosition returns the tile ID at the given screen position within the palette.
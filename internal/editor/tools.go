@@ -2,9 +2,12 @@
 package editor
 
 import (
-	"log"
+	"fmt"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/world"
 )
 
@@ -80,7 +83,7 @@ func (t *PaintTool) recordPaintTile(state *EditorState, tileX, tileY int) {
 	}
 
 	// Check bounds
-	if tileX < 0 || tileX >= layer.Width() || tileY < 0 || tileY >= layer.Height() {
+	if !canPaint(layer, tileX, tileY) {
 		return
 	}
 
@@ -116,10 +119,20 @@ func (t *PaintTool) recordPaintTile(state *EditorState, tileX, tileY int) {
 	}
 }
 
+// canPaint returns whether the tile at tileX, tileY can be painted on the
+// layer: it's inside the layer, or right of and below the origin on an
+// infinite layer, whose chunks grow to fit.
+func canPaint(layer *world.TileLayer, tileX, tileY int) bool {
+	if layer.Infinite() {
+		return tileX >= 0 && tileY >= 0
+	}
+	return tileX >= 0 && tileX < layer.Width() && tileY >= 0 && tileY < layer.Height()
+}
+
 // drawLine draws a line of tiles using Bresenham's algorithm.
 func (t *PaintTool) drawLine(state *EditorState, x0, y0, x1, y1 int) {
-	dx := abs(x1 - x0)
-	dy := abs(y1 - y0)
+	dx := mathx.Abs(x1 - x0)
+	dy := mathx.Abs(y1 - y0)
 	sx := 1
 	sy := 1
 	if x0 > x1 {
@@ -204,7 +217,7 @@ func (t *EraseTool) recordEraseTile(state *EditorState, tileX, tileY int) {
 	}
 
 	// Check bounds
-	if tileX < 0 || tileX >= layer.Width() || tileY < 0 || tileY >= layer.Height() {
+	if !canPaint(layer, tileX, tileY) {
 		return
 	}
 
@@ -228,8 +241,8 @@ func (t *EraseTool) recordEraseTile(state *EditorState, tileX, tileY int) {
 
 // drawLine draws a line of erasure using Bresenham's algorithm.
 func (t *EraseTool) drawLine(state *EditorState, x0, y0, x1, y1 int) {
-	dx := abs(x1 - x0)
-	dy := abs(y1 - y0)
+	dx := mathx.Abs(x1 - x0)
+	dy := mathx.Abs(y1 - y0)
 	sx := 1
 	sy := 1
 	if x0 > x1 {
@@ -310,7 +323,8 @@ func (t *FillTool) OnMouseUp(state *EditorState, tileX, tileY int, worldX, world
 }
 
 // SelectTool handles selecting, moving, and resizing objects.
-// Supports multi-selection with Shift key.
+// Supports multi-selection with Shift key, box selection by dragging on empty
+// space, and Alt+drag to drag out copies.
 type SelectTool struct {
 	selection  *SelectionManager
 	handleSize float64 // Size of resize handles in screen pixels
@@ -319,6 +333,16 @@ type SelectTool struct {
 	originalX, originalY float64
 	originalW, originalH float64
 	dragStarted          bool
+	// Alt+drag copies: the copies are added to the level while dragging and
+	// recorded as one action on release
+	copying   bool
+	copyStart int // Index of the first copy
+	// Box selection: the corners of the band in world coordinates, and the
+	// selection it adds to (with Shift)
+	boxSelecting         bool
+	boxStartX, boxStartY float64
+	boxEndX, boxEndY     float64
+	boxBase              []int
 }
 
 // NewSelectTool creates a new select tool.
@@ -351,6 +375,15 @@ func (t *SelectTool) OnMouseDown(state *EditorState, tileX, tileY int, worldX, w
 	// Check if shift is held for multi-selection
 	shiftHeld := ebiten.IsKeyPressed(ebiten.KeyShift)
 
+	// Alt+drag on an object drags out a copy of it, or of the whole
+	// selection if it's part of it
+	if ebiten.IsKeyPressed(ebiten.KeyAlt) {
+		if hitIndex := t.selection.HitTestVisible(worldX, worldY, state); hitIndex >= 0 {
+			t.beginCopyDrag(state, hitIndex, worldX, worldY)
+			return
+		}
+	}
+
 	// Check if we clicked on a resize handle of the primary selected object
 	if t.selection.HasSelection() {
 		selectedObj := t.selection.GetSelectedObject(state.Objects)
@@ -382,7 +415,7 @@ func (t *SelectTool) OnMouseDown(state *EditorState, tileX, tileY int, worldX, w
 	}
 
 	// Check if we clicked on any object
-	hitIndex := t.selection.HitTest(worldX, worldY, state.Objects)
+	hitIndex := t.selection.HitTestVisible(worldX, worldY, state)
 	if hitIndex >= 0 {
 		if shiftHeld {
 			// Add/remove from selection (toggle behavior)
@@ -406,16 +439,106 @@ func (t *SelectTool) OnMouseDown(state *EditorState, tileX, tileY int, worldX, w
 		}
 	} else {
 		// Clicked on empty space - clear selection (unless shift is held)
+		// and start a box selection
 		if !shiftHeld {
 			t.selection.ClearSelection()
 			state.ClearSelection()
 		}
 		t.dragStarted = false
+		t.boxSelecting = true
+		t.boxStartX, t.boxStartY = worldX, worldY
+		t.boxEndX, t.boxEndY = worldX, worldY
+		t.boxBase = t.selection.SelectedIndices()
+	}
+}
+
+// BoxSelectRect returns the box selection band in world coordinates, and
+// whether a box selection is in progress.
+func (t *SelectTool) BoxSelectRect() (x, y, w, h float64, ok bool) {
+	if !t.boxSelecting {
+		return 0, 0, 0, 0, false
+	}
+	x, y = math.Min(t.boxStartX, t.boxEndX), math.Min(t.boxStartY, t.boxEndY)
+	w, h = math.Abs(t.boxEndX-t.boxStartX), math.Abs(t.boxEndY-t.boxStartY)
+	return x, y, w, h, true
+}
+
+// updateBoxSelect selects the objects touching the band, on top of the
+// selection the box started with.
+func (t *SelectTool) updateBoxSelect(state *EditorState, worldX, worldY float64) {
+	t.boxEndX, t.boxEndY = worldX, worldY
+	x, y, w, h, _ := t.BoxSelectRect()
+
+	t.selection.ClearSelection()
+	for _, idx := range t.boxBase {
+		t.selection.AddToSelection(idx)
+	}
+	for _, idx := range t.selection.IntersectingVisible(x, y, w, h, state) {
+		if !t.selection.IsSelected(idx) {
+			t.selection.AddToSelection(idx)
+		}
+	}
+
+	if t.selection.HasSelection() {
+		state.SelectObject(t.selection.SelectedIndex())
+	} else {
+		state.ClearSelection()
 	}
 }
 
+// beginCopyDrag adds copies of the object at hitIndex (and the rest of the
+// selection, if it's selected) in place, and starts moving the copies.
+func (t *SelectTool) beginCopyDrag(state *EditorState, hitIndex int, worldX, worldY float64) {
+	indices := []int{hitIndex}
+	if t.selection.IsSelected(hitIndex) {
+		indices = t.selection.SelectedIndices()
+	}
+
+	copies := DuplicateObjects(state.Objects, indices, 0, 0)
+	t.copying = true
+	t.copyStart = len(state.Objects)
+	state.Objects = append(state.Objects, copies...)
+
+	t.selection.ClearSelection()
+	for i := range copies {
+		t.selection.AddToSelection(t.copyStart + i)
+	}
+	state.SelectObject(t.copyStart)
+
+	t.selection.BeginMove(worldX, worldY, state.Objects)
+	t.dragStarted = true
+}
+
+// endCopyDrag records the dragged-out copies as an undoable action. Copies
+// that weren't moved off their originals are dropped.
+func (t *SelectTool) endCopyDrag(state *EditorState) {
+	t.copying = false
+
+	moved := false
+	for idx, pos := range t.selection.GetOriginalPositions() {
+		if idx < len(state.Objects) && (state.Objects[idx].X != pos.X || state.Objects[idx].Y != pos.Y) {
+			moved = true
+		}
+	}
+
+	copies := append([]world.ObjectData(nil), state.Objects[t.copyStart:]...)
+	state.Objects = state.Objects[:t.copyStart]
+	if !moved {
+		t.selection.ClearSelection()
+		state.ClearSelection()
+		return
+	}
+
+	action, _ := newAddObjectsAction(fmt.Sprintf("Duplicate %d objects", len(copies)), t.copyStart, copies)
+	state.History.Do(action, state)
+}
+
 // OnMouseMove handles dragging objects.
 func (t *SelectTool) OnMouseMove(state *EditorState, tileX, tileY int, worldX, worldY float64) {
+	if t.boxSelecting {
+		t.updateBoxSelect(state, worldX, worldY)
+		return
+	}
 	if !t.selection.IsDragging() {
 		return
 	}
@@ -432,8 +555,13 @@ func (t *SelectTool) OnMouseMove(state *EditorState, tileX, tileY int, worldX, w
 
 // OnMouseUp finalizes drag operations.
 func (t *SelectTool) OnMouseUp(state *EditorState, tileX, tileY int, worldX, worldY float64) {
-	// Create action if a drag occurred
-	if t.selection.IsDragging() && t.dragStarted {
+	if t.boxSelecting {
+		t.boxSelecting = false
+		t.boxBase = nil
+	} else if t.copying {
+		t.endCopyDrag(state)
+	} else if t.selection.IsDragging() && t.dragStarted {
+		// Create action if a drag occurred
 		selectedObj := t.selection.GetSelectedObject(state.Objects)
 		if selectedObj != nil && t.selection.SelectedIndex() >= 0 {
 			// Check if this was a move operation with multiple objects
@@ -505,6 +633,10 @@ func (t *SelectTool) GetHoverHandle(state *EditorState, worldX, worldY float64)
 type PlaceObjectTool struct {
 	objectPalette *ObjectPalette
 	state         *EditorState // Reference to editor state for tool switching
+
+	// Linked pair placement: the switch's position, picked by the first click
+	pairStarted  bool
+	pairX, pairY float64
 }
 
 // NewPlaceObjectTool creates a new place object tool.
@@ -525,6 +657,15 @@ func (t *PlaceObjectTool) OnMouseDown(state *EditorState, tileX, tileY int, worl
 		return
 	}
 
+	if prefab, ok := t.objectPalette.SelectedPrefab(); ok {
+		t.placePrefab(state, prefab, tileX, tileY)
+		return
+	}
+	if t.objectPalette.IsLinkedPair() {
+		t.placeLinkedPair(state, tileX, tileY)
+		return
+	}
+
 	// Get the selected object type from the palette
 	objType := t.objectPalette.SelectedType()
 
@@ -535,6 +676,7 @@ func (t *PlaceObjectTool) OnMouseDown(state *EditorState, tileX, tileY int, worl
 
 	// Create a new object with default properties and auto-generated ID
 	obj := CreateObjectWithAutoID(objType, worldX, worldY, state.Objects)
+	obj.Layer = state.CurrentObjectLayer()
 
 	// Generate a unique Tiled object ID for the object
 	obj.ID = t.generateObjectID(state)
@@ -548,9 +690,9 @@ func (t *PlaceObjectTool) OnMouseDown(state *EditorState, tileX, tileY int, worl
 
 	// Log the placement with ID info if applicable
 	if id, ok := obj.Props["id"].(string); ok && id != "" {
-		log.Printf("Placed %s with ID '%s' at (%.0f, %.0f)", objType, id, worldX, worldY)
+		logger.Debugf("Placed %s with ID '%s' at (%.0f, %.0f)", objType, id, worldX, worldY)
 	} else {
-		log.Printf("Placed %s at (%.0f, %.0f)", objType, worldX, worldY)
+		logger.Debugf("Placed %s at (%.0f, %.0f)", objType, worldX, worldY)
 	}
 
 	// Stay in Place Object mode if Shift is held (for placing multiple objects)
@@ -560,6 +702,79 @@ func (t *PlaceObjectTool) OnMouseDown(state *EditorState, tileX, tileY int, worl
 	}
 }
 
+// placePrefab places an instance of prefab with its top-left corner on the
+// tile at tileX, tileY, and selects it.
+func (t *PlaceObjectTool) placePrefab(state *EditorState, prefab Prefab, tileX, tileY int) {
+	if state.MapData == nil {
+		return
+	}
+	x := float64(tileX * state.MapData.TileWidth())
+	y := float64(tileY * state.MapData.TileHeight())
+	action, indices := NewPlacePrefabAction(state, prefab, x, y)
+	state.History.Do(action, state)
+
+	if selection := state.GetSelectionManager(); selection != nil {
+		selection.ClearSelection()
+		for _, idx := range indices {
+			selection.AddToSelection(idx)
+		}
+	}
+	state.SelectObject(indices[0])
+	logger.Debugf("Placed prefab %s at (%.0f, %.0f)", prefab.Name, x, y)
+
+	// Stay in Place Object mode if Shift is held (for placing multiple prefabs)
+	if !ebiten.IsKeyPressed(ebiten.KeyShift) {
+		t.objectPalette.ClearSelection()
+		state.SetTool(ToolSelect)
+	}
+}
+
+// placeLinkedPair handles a click while placing a linked switch and door on
+// the tile at tileX, tileY: the first click picks the switch's position, the
+// second places both, with the switch's door_id set to the door's new id, as
+// one undo step.
+func (t *PlaceObjectTool) placeLinkedPair(state *EditorState, tileX, tileY int) {
+	if state.MapData == nil {
+		return
+	}
+	x := float64(tileX * state.MapData.TileWidth())
+	y := float64(tileY * state.MapData.TileHeight())
+	if !t.pairStarted {
+		t.pairStarted = true
+		t.pairX, t.pairY = x, y
+		state.ShowStatusMessage(i18n.T("editor.placeDoorHint"), false)
+		return
+	}
+	t.pairStarted = false
+
+	layer := state.CurrentObjectLayer()
+	sw := CreateObjectWithAutoID(world.ObjectTypeSwitch, t.pairX, t.pairY, state.Objects)
+	sw.Layer = layer
+	sw.ID = t.generateObjectID(state)
+	door := CreateObjectWithAutoID(world.ObjectTypeDoor, x, y, state.Objects)
+	door.Layer = layer
+	door.ID = sw.ID + 1
+	doorID := door.GetPropString("id", "")
+	sw.Props[world.PropSwitchTarget] = doorID
+
+	action, indices := newAddObjectsAction("Place linked switch and door", len(state.Objects), []world.ObjectData{sw, door})
+	state.History.Do(action, state)
+	if selection := state.GetSelectionManager(); selection != nil {
+		selection.ClearSelection()
+		for _, idx := range indices {
+			selection.AddToSelection(idx)
+		}
+	}
+	state.SelectObject(indices[0])
+	state.ShowStatusMessage(i18n.T("editor.placedLinkedSwitch", doorID), false)
+
+	// Stay in linked pair mode if Shift is held (for placing multiple pairs)
+	if !ebiten.IsKeyPressed(ebiten.KeyShift) {
+		t.objectPalette.ClearSelection()
+		state.SetTool(ToolSelect)
+	}
+}
+
 // OnMouseMove does nothing for place object tool.
 func (t *PlaceObjectTool) OnMouseMove(state *EditorState, tileX, tileY int, worldX, worldY float64) {
 	// Place object tool doesn't support drag
@@ -625,6 +840,55 @@ func (tm *ToolManager) GetPlaceObjectType() world.ObjectType {
 	return ""
 }
 
+// StartLinkedPair switches to placing a switch and a door linked to it.
+func (tm *ToolManager) StartLinkedPair(state *EditorState) {
+	if tm.placeObjectTool == nil || tm.placeObjectTool.objectPalette == nil {
+		return
+	}
+	tm.placeObjectTool.objectPalette.SelectLinkedPair()
+	tm.placeObjectTool.pairStarted = false
+	state.SetTool(ToolPlaceObject)
+	state.ShowStatusMessage(i18n.T("editor.placeSwitchHint"), false)
+}
+
+// CancelLinkedPair stops placing a linked switch and door. Returns false if
+// none was being placed.
+func (tm *ToolManager) CancelLinkedPair(state *EditorState) bool {
+	if tm.placeObjectTool == nil || tm.placeObjectTool.objectPalette == nil ||
+		!tm.placeObjectTool.objectPalette.IsLinkedPair() {
+		return false
+	}
+	tm.placeObjectTool.objectPalette.ClearSelection()
+	tm.placeObjectTool.pairStarted = false
+	state.SetTool(ToolSelect)
+	return true
+}
+
+// LinkedPairSwitch returns where the switch of the linked pair being placed
+// goes, and false if its position hasn't been picked yet.
+func (tm *ToolManager) LinkedPairSwitch() (x, y float64, ok bool) {
+	if tm.placeObjectTool == nil || tm.placeObjectTool.objectPalette == nil ||
+		!tm.placeObjectTool.objectPalette.IsLinkedPair() || !tm.placeObjectTool.pairStarted {
+		return 0, 0, false
+	}
+	return tm.placeObjectTool.pairX, tm.placeObjectTool.pairY, true
+}
+
+// IsPlacingLinkedPair returns whether a linked switch and door are being
+// placed.
+func (tm *ToolManager) IsPlacingLinkedPair() bool {
+	return tm.placeObjectTool != nil && tm.placeObjectTool.objectPalette != nil &&
+		tm.placeObjectTool.objectPalette.IsLinkedPair()
+}
+
+// GetPlacePrefab returns the prefab selected in the object palette, if any.
+func (tm *ToolManager) GetPlacePrefab() (Prefab, bool) {
+	if tm.placeObjectTool != nil && tm.placeObjectTool.objectPalette != nil {
+		return tm.placeObjectTool.objectPalette.SelectedPrefab()
+	}
+	return Prefab{}, false
+}
+
 // GetTool returns the tool handler for the given tool type.
 func (tm *ToolManager) GetTool(tool Tool) ToolHandler {
 	switch tool {
@@ -667,13 +931,5 @@ func (tm *ToolManager) HandleMouseUp(state *EditorState, tileX, tileY int, world
 	tool.OnMouseUp(state, tileX, tileY, worldX, worldY)
 }
 
-// Helper function for absolute value
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
 // Ensure TileLayer has SetTile method available from world package
 var _ = (*world.TileLayer)(nil)
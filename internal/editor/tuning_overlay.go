@@ -0,0 +1,193 @@
+package editor
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/config"
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// DefaultTuningPath is where the tuning overlay saves when the editor wasn't
+// started with a tuning file.
+const DefaultTuningPath = "tuning.json"
+
+// Tuning overlay layout
+const (
+	tuningOverlayWidth      = 300
+	tuningOverlayLineHeight = 16
+	tuningOverlayMargin     = 10
+	tuningMessageDuration   = 3.0 // Seconds the save message stays up
+)
+
+// tuningField is one adjustable value of the tuning overlay.
+type tuningField struct {
+	name  string
+	value func(t *game.Tuning) any // *float64, *time.Duration or *bool
+	step  float64                  // Per key press; milliseconds for durations
+}
+
+// tuningFields are the values listed by the overlay, top to bottom.
+var tuningFields = []tuningField{
+	{"Acceleration", func(t *game.Tuning) any { return &t.Horizontal.Acceleration }, 50},
+	{"Deceleration", func(t *game.Tuning) any { return &t.Horizontal.Deceleration }, 50},
+	{"Max speed", func(t *game.Tuning) any { return &t.Horizontal.MaxSpeed }, 10},
+	{"Friction", func(t *game.Tuning) any { return &t.Horizontal.Friction }, 0.01},
+	{"Air control", func(t *game.Tuning) any { return &t.Horizontal.AirControl }, 0.05},
+	{"Max step height", func(t *game.Tuning) any { return &t.Horizontal.MaxStepHeight }, 1},
+	{"Jump velocity", func(t *game.Tuning) any { return &t.Jump.Velocity }, 10},
+	{"Coyote time", func(t *game.Tuning) any { return &t.Jump.CoyoteTime }, 10},
+	{"Jump buffer", func(t *game.Tuning) any { return &t.Jump.BufferTime }, 10},
+	{"Variable height", func(t *game.Tuning) any { return &t.Jump.VariableHeight }, 0},
+	{"Early release mult", func(t *game.Tuning) any { return &t.Jump.EarlyReleaseMult }, 0.1},
+	{"Apex threshold", func(t *game.Tuning) any { return &t.Jump.ApexThreshold }, 10},
+	{"Apex gravity mult", func(t *game.Tuning) any { return &t.Jump.ApexGravityMult }, 0.05},
+	{"Apex speed bonus", func(t *game.Tuning) any { return &t.Jump.ApexSpeedBonus }, 5},
+	{"Corner correction", func(t *game.Tuning) any { return &t.Jump.CornerCorrection }, 1},
+	{"Ledge forgiveness", func(t *game.Tuning) any { return &t.Jump.LedgeForgiveness }, 1},
+	{"Gravity", func(t *game.Tuning) any { return &t.Gravity.Base }, 50},
+	{"Fall mult", func(t *game.Tuning) any { return &t.Gravity.FallMult }, 0.1},
+	{"Max fall", func(t *game.Tuning) any { return &t.Gravity.MaxFall }, 10},
+	{"Crouch height mult", func(t *game.Tuning) any { return &t.Crouch.HeightMult }, 0.05},
+	{"Crouch speed mult", func(t *game.Tuning) any { return &t.Crouch.SpeedMult }, 0.05},
+}
+
+// adjust changes a field by steps steps. Booleans flip.
+func (f tuningField) adjust(t *game.Tuning, steps float64) {
+	switch v := f.value(t).(type) {
+	case *float64:
+		*v += f.step * steps
+	case *time.Duration:
+		*v += time.Duration(f.step*steps) * time.Millisecond
+		if *v < 0 {
+			*v = 0
+		}
+	case *bool:
+		*v = !*v
+	}
+}
+
+// format returns a field's current value as text.
+func (f tuningField) format(t *game.Tuning) string {
+	switch v := f.value(t).(type) {
+	case *float64:
+		return fmt.Sprintf("%.2f", *v)
+	case *time.Duration:
+		return v.String()
+	case *bool:
+		return fmt.Sprintf("%v", *v)
+	}
+	return ""
+}
+
+// TuningOverlay lists the movement tuning during playtests and lets it be
+// adjusted live and written back to a tuning file.
+type TuningOverlay struct {
+	visible  bool
+	selected int
+	path     string // Tuning file to save to
+
+	message      string
+	messageTimer float64
+}
+
+// NewTuningOverlay creates a hidden tuning overlay saving to path, or to
+// DefaultTuningPath if path is empty.
+func NewTuningOverlay(path string) *TuningOverlay {
+	if path == "" {
+		path = DefaultTuningPath
+	}
+	return &TuningOverlay{path: path}
+}
+
+// IsVisible returns whether the overlay is shown.
+func (o *TuningOverlay) IsVisible() bool {
+	return o.visible
+}
+
+// Update handles the overlay keys: T toggles it; while shown, [ and ] pick a
+// value, - and = change it (ten steps with Shift) and Ctrl+S saves. Returns
+// true if t changed.
+func (o *TuningOverlay) Update(t *game.Tuning) bool {
+	if o.messageTimer > 0 {
+		o.messageTimer -= 1.0 / 60.0
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		o.visible = !o.visible
+	}
+	if !o.visible {
+		return false
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft):
+		o.selected = (o.selected + len(tuningFields) - 1) % len(tuningFields)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketRight):
+		o.selected = (o.selected + 1) % len(tuningFields)
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		o.save(*t)
+		return false
+	}
+
+	steps := 0.0
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd) {
+		steps = 1
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract) {
+		steps = -1
+	}
+	if steps == 0 {
+		return false
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		steps *= 10
+	}
+	tuningFields[o.selected].adjust(t, steps)
+	return true
+}
+
+// save writes the tuning to the overlay's file.
+func (o *TuningOverlay) save(t game.Tuning) {
+	o.message = fmt.Sprintf("Saved tuning to %s", o.path)
+	if err := config.SaveTuning(o.path, t); err != nil {
+		o.message = err.Error()
+	}
+	o.messageTimer = tuningMessageDuration
+}
+
+// Draw renders the overlay along the right edge of the screen.
+func (o *TuningOverlay) Draw(screen *ebiten.Image, t *game.Tuning) {
+	if !o.visible {
+		return
+	}
+
+	screenWidth, _ := screen.Size()
+	x := screenWidth - tuningOverlayWidth - tuningOverlayMargin
+	y := 40
+	height := (len(tuningFields)+3)*tuningOverlayLineHeight + 8
+	draw.FillRect(screen, float64(x), float64(y), tuningOverlayWidth, float64(height), color.RGBA{0, 0, 0, 190})
+
+	ebitenutil.DebugPrintAt(screen, "TUNING  [ ] select  - = adjust  ^S save", x+6, y+4)
+	for i, f := range tuningFields {
+		ly := y + 4 + (i+1)*tuningOverlayLineHeight
+		if i == o.selected {
+			draw.FillRect(screen, float64(x+2), float64(ly), tuningOverlayWidth-4, tuningOverlayLineHeight, propertyHoverColor)
+		}
+		ebitenutil.DebugPrintAt(screen, f.name, x+6, ly)
+		value := f.format(t)
+		ebitenutil.DebugPrintAt(screen, value, x+tuningOverlayWidth-6-len(value)*6, ly)
+	}
+
+	footer := o.path
+	if o.messageTimer > 0 {
+		footer = o.message
+	}
+	ebitenutil.DebugPrintAt(screen, footer, x+6, y+4+(len(tuningFields)+2)*tuningOverlayLineHeight)
+}
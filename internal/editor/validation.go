@@ -2,318 +2,158 @@ package editor
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/levelcheck"
 	"github.com/torsten/GoP/internal/world"
 )
 
-// ErrorType represents the type of validation issue.
-type ErrorType string
+// Validation types are shared with the game and tools through levelcheck.
+type (
+	ErrorType        = levelcheck.ErrorType
+	ValidationError  = levelcheck.ValidationError
+	ValidationResult = levelcheck.ValidationResult
+)
 
 const (
 	// TypeError represents a critical error that must be fixed.
-	TypeError ErrorType = "error"
+	TypeError = levelcheck.TypeError
 	// TypeWarning represents a warning that should be fixed.
-	TypeWarning ErrorType = "warning"
+	TypeWarning = levelcheck.TypeWarning
 )
 
-// ValidationError represents a single validation issue found in the level.
-type ValidationError struct {
-	Type        ErrorType // "error" or "warning"
-	ObjectIndex int       // Index of the object with the issue (-1 for global errors)
-	Message     string    // Human-readable description of the issue
-	Property    string    // Property name if applicable (empty string if not)
-}
-
-// ValidationResult holds the result of level validation.
-type ValidationResult struct {
-	Errors   []ValidationError // Critical errors that must be fixed
-	Warnings []ValidationError // Warnings that should be fixed
-}
-
-// HasErrors returns true if there are any critical errors.
-func (r *ValidationResult) HasErrors() bool {
-	return len(r.Errors) > 0
-}
-
-// HasWarnings returns true if there are any warnings.
-func (r *ValidationResult) HasWarnings() bool {
-	return len(r.Warnings) > 0
-}
-
-// HasIssues returns true if there are any errors or warnings.
-func (r *ValidationResult) HasIssues() bool {
-	return r.HasErrors() || r.HasWarnings()
-}
-
-// AllIssues returns all errors and warnings combined.
-func (r *ValidationResult) AllIssues() []ValidationError {
-	all := make([]ValidationError, 0, len(r.Errors)+len(r.Warnings))
-	all = append(all, r.Errors...)
-	all = append(all, r.Warnings...)
-	return all
-}
-
-// ErrorCount returns the number of critical errors.
-func (r *ValidationResult) ErrorCount() int {
-	return len(r.Errors)
-}
-
-// WarningCount returns the number of warnings.
-func (r *ValidationResult) WarningCount() int {
-	return len(r.Warnings)
-}
-
-// GetObjectErrors returns all errors for a specific object index.
-func (r *ValidationResult) GetObjectErrors(objectIndex int) []ValidationError {
-	var errors []ValidationError
-	for _, e := range r.Errors {
-		if e.ObjectIndex == objectIndex {
-			errors = append(errors, e)
-		}
-	}
-	return errors
-}
-
-// GetObjectWarnings returns all warnings for a specific object index.
-func (r *ValidationResult) GetObjectWarnings(objectIndex int) []ValidationError {
-	var warnings []ValidationError
-	for _, w := range r.Warnings {
-		if w.ObjectIndex == objectIndex {
-			warnings = append(warnings, w)
-		}
-	}
-	return warnings
-}
-
-// GetObjectIssues returns all errors and warnings for a specific object index.
-func (r *ValidationResult) GetObjectIssues(objectIndex int) []ValidationError {
-	var issues []ValidationError
-	for _, e := range r.Errors {
-		if e.ObjectIndex == objectIndex {
-			issues = append(issues, e)
-		}
-	}
-	for _, w := range r.Warnings {
-		if w.ObjectIndex == objectIndex {
-			issues = append(issues, w)
-		}
-	}
-	return issues
-}
-
 // ValidateLevel validates the level data and returns a validation result.
+// It runs the shared levelcheck checks plus the editor's schema checks.
 func ValidateLevel(state *EditorState) *ValidationResult {
-	result := &ValidationResult{
-		Errors:   make([]ValidationError, 0),
-		Warnings: make([]ValidationError, 0),
-	}
-
 	if state == nil || state.Objects == nil {
-		return result
+		return &ValidationResult{
+			Errors:   make([]ValidationError, 0),
+			Warnings: make([]ValidationError, 0),
+		}
 	}
 
-	// Check for spawn points
-	validateSpawnPoints(state, result)
-
-	// Check for duplicate IDs
-	validateUniqueIDs(state, result)
-
-	// Check switch references
-	validateSwitchReferences(state, result)
-
-	// Check for doors without switches
-	validateDoorSwitches(state, result)
-
-	// Check for platforms with no movement
-	validatePlatforms(state, result)
+	result := levelcheck.Validate(levelForState(state, levelcheck.RuleCount(state.FilePath)))
 
 	// Check for required properties
 	validateRequiredProperties(state, result)
+	validatePropertyValues(state, result)
 
 	return result
 }
 
-// validateSpawnPoints checks for player spawn points.
-func validateSpawnPoints(state *EditorState, result *ValidationResult) {
-	spawns := world.FilterObjectsByType(state.Objects, world.ObjectTypeSpawn)
-
-	if len(spawns) == 0 {
-		result.Errors = append(result.Errors, ValidationError{
-			Type:        TypeError,
-			ObjectIndex: -1,
-			Message:     "No player spawn point defined",
-			Property:    "",
-		})
-	} else if len(spawns) > 1 {
-		result.Warnings = append(result.Warnings, ValidationError{
-			Type:        TypeWarning,
-			ObjectIndex: -1,
-			Message:     fmt.Sprintf("Multiple spawn points defined (%d), only the first will be used", len(spawns)),
-			Property:    "",
-		})
+// levelForState returns the level check input for the editor state.
+func levelForState(state *EditorState, ruleCount int) levelcheck.Level {
+	return levelcheck.Level{
+		Map:       state.MapData,
+		Objects:   state.Objects,
+		RuleCount: ruleCount,
+		Path:      state.FilePath,
 	}
 }
 
-// validateUniqueIDs checks for duplicate entity IDs.
-func validateUniqueIDs(state *EditorState, result *ValidationResult) {
-	// Map from ID to the first object index that uses it
-	idToIndex := make(map[string]int)
-
+// validateRequiredProperties checks that all required properties are set.
+func validateRequiredProperties(state *EditorState, result *ValidationResult) {
 	for i, obj := range state.Objects {
-		id := obj.GetPropString("id", "")
-		if id == "" {
+		schema := GetSchema(obj.Type)
+		if schema == nil {
 			continue
 		}
 
-		if firstIndex, exists := idToIndex[id]; exists {
-			// Add error for the duplicate
-			result.Errors = append(result.Errors, ValidationError{
-				Type:        TypeError,
-				ObjectIndex: i,
-				Message:     fmt.Sprintf("Duplicate ID '%s' (first used at object %d)", id, firstIndex),
-				Property:    "id",
-			})
-		} else {
-			idToIndex[id] = i
-		}
-	}
-}
+		for _, ps := range schema.Properties {
+			if !ps.Required {
+				continue
+			}
 
-// validateSwitchReferences checks that switches reference valid doors.
-func validateSwitchReferences(state *EditorState, result *ValidationResult) {
-	// Build a map of all door IDs
-	doorIDs := make(map[string]int)
-	for i, obj := range state.Objects {
-		if obj.Type == world.ObjectTypeDoor {
-			id := obj.GetPropString("id", "")
-			if id != "" {
-				doorIDs[id] = i
+			for _, propSchema := range ps.stored() {
+				// Check if the property is set
+				value := getPropertyValue(obj, propSchema.Name)
+
+				// Check if the value is the default (unset) or empty
+				if isZeroValue(value, propSchema) {
+					result.Errors = append(result.Errors, ValidationError{
+						Type:        TypeError,
+						ObjectIndex: i,
+						Message:     fmt.Sprintf("Required property '%s' is not set", propSchema.Name),
+						Property:    propSchema.Name,
+					})
+				}
 			}
 		}
 	}
+}
 
-	// Check each switch's door_id reference
+// validatePropertyValues warns about enum properties set to a value that
+// isn't one of their options and color properties that aren't hex colors.
+// Properties levelcheck already reported on are skipped, so a bad value isn't
+// reported twice.
+func validatePropertyValues(state *EditorState, result *ValidationResult) {
 	for i, obj := range state.Objects {
-		if obj.Type != world.ObjectTypeSwitch {
+		schema := GetSchema(obj.Type)
+		if schema == nil {
 			continue
 		}
 
-		doorID := obj.GetPropString("door_id", "")
-		if doorID == "" {
-			// Switch with no door_id - this could be a warning
-			result.Warnings = append(result.Warnings, ValidationError{
-				Type:        TypeWarning,
-				ObjectIndex: i,
-				Message:     "Switch has no door_id configured",
-				Property:    "door_id",
-			})
-			continue
-		}
+		for _, propSchema := range schema.Properties {
+			value := getPropertyValue(obj, propSchema.Name)
+			if value == nil || hasPropertyIssue(result, i, propSchema.Name) {
+				continue
+			}
 
-		if _, exists := doorIDs[doorID]; !exists {
-			result.Errors = append(result.Errors, ValidationError{
-				Type:        TypeError,
-				ObjectIndex: i,
-				Message:     fmt.Sprintf("Switch references non-existent door '%s'", doorID),
-				Property:    "door_id",
-			})
+			var message string
+			switch propSchema.Type {
+			case "enum":
+				if !isEnumOption(value, propSchema) {
+					message = fmt.Sprintf("Unknown %s '%v' (want %s)", propSchema.Name, value, strings.Join(propSchema.Options, ", "))
+				}
+			case "color":
+				if s, _ := value.(string); !isHexColor(s) {
+					message = fmt.Sprintf("Invalid %s '%v' (want #RRGGBB or #RRGGBBAA)", propSchema.Name, value)
+				}
+			}
+			if message != "" {
+				result.Warnings = append(result.Warnings, ValidationError{
+					Type:        TypeWarning,
+					ObjectIndex: i,
+					Message:     message,
+					Property:    propSchema.Name,
+				})
+			}
 		}
 	}
 }
 
-// validateDoorSwitches checks for doors without any switch to control them.
-func validateDoorSwitches(state *EditorState, result *ValidationResult) {
-	// Build a map of doors that are referenced by switches
-	referencedDoors := make(map[string]bool)
-
-	for _, obj := range state.Objects {
-		if obj.Type == world.ObjectTypeSwitch {
-			doorID := obj.GetPropString("door_id", "")
-			if doorID != "" {
-				referencedDoors[doorID] = true
-			}
-		}
+// isEnumOption returns whether value is one of the enum's options.
+func isEnumOption(value any, propSchema PropertySchema) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
 	}
-
-	// Check each door
-	for i, obj := range state.Objects {
-		if obj.Type != world.ObjectTypeDoor {
-			continue
-		}
-
-		id := obj.GetPropString("id", "")
-		if id == "" {
-			// Door with no ID - can't be referenced
-			result.Warnings = append(result.Warnings, ValidationError{
-				Type:        TypeWarning,
-				ObjectIndex: i,
-				Message:     "Door has no ID configured, cannot be controlled by switches",
-				Property:    "id",
-			})
-			continue
-		}
-
-		if !referencedDoors[id] {
-			result.Warnings = append(result.Warnings, ValidationError{
-				Type:        TypeWarning,
-				ObjectIndex: i,
-				Message:     fmt.Sprintf("Door '%s' has no switch to control it", id),
-				Property:    "id",
-			})
+	for _, option := range propSchema.Options {
+		if s == option {
+			return true
 		}
 	}
+	return false
 }
 
-// validatePlatforms checks for platforms with no movement.
-func validatePlatforms(state *EditorState, result *ValidationResult) {
-	for i, obj := range state.Objects {
-		if obj.Type != world.ObjectTypePlatform {
-			continue
-		}
-
-		// Get endX and endY relative to start position
-		endX := obj.GetPropFloat("endX", 0)
-		endY := obj.GetPropFloat("endY", 0)
-
-		// If both are 0, the platform won't move
-		if endX == 0 && endY == 0 {
-			result.Warnings = append(result.Warnings, ValidationError{
-				Type:        TypeWarning,
-				ObjectIndex: i,
-				Message:     "Platform has endX=0 and endY=0, it won't move",
-				Property:    "endX",
-			})
-		}
-	}
+// isHexColor returns whether s is a color in #RRGGBB or #RRGGBBAA form.
+func isHexColor(s string) bool {
+	_, err := gfx.ParseHexColor(s)
+	return err == nil
 }
 
-// validateRequiredProperties checks that all required properties are set.
-func validateRequiredProperties(state *EditorState, result *ValidationResult) {
-	for i, obj := range state.Objects {
-		schema := GetSchema(obj.Type)
-		if schema == nil {
-			continue
-		}
-
-		for _, propSchema := range schema.Properties {
-			if !propSchema.Required {
-				continue
-			}
-
-			// Check if the property is set
-			value := getPropertyValue(obj, propSchema.Name)
-
-			// Check if the value is the default (unset) or empty
-			if isZeroValue(value, propSchema) {
-				result.Errors = append(result.Errors, ValidationError{
-					Type:        TypeError,
-					ObjectIndex: i,
-					Message:     fmt.Sprintf("Required property '%s' is not set", propSchema.Name),
-					Property:    propSchema.Name,
-				})
+// hasPropertyIssue returns whether result has an error or warning about the
+// named property of the object at index.
+func hasPropertyIssue(result *ValidationResult, index int, name string) bool {
+	for _, issues := range [][]ValidationError{result.Errors, result.Warnings} {
+		for _, issue := range issues {
+			if issue.ObjectIndex == index && issue.Property == name {
+				return true
 			}
 		}
 	}
+	return false
 }
 
 // getPropertyValue returns the value of a property from the object.
@@ -331,7 +171,7 @@ func isZeroValue(value any, propSchema PropertySchema) bool {
 	}
 
 	switch propSchema.Type {
-	case "string":
+	case "string", "enum", "color":
 		if s, ok := value.(string); ok {
 			return s == ""
 		}
@@ -356,8 +196,5 @@ func isZeroValue(value any, propSchema PropertySchema) bool {
 
 // FormatValidationError formats a validation error for display.
 func FormatValidationError(err ValidationError) string {
-	if err.Property != "" {
-		return fmt.Sprintf("%s (property: %s)", err.Message, err.Property)
-	}
-	return err.Message
+	return levelcheck.Format(err)
 }
@@ -4,7 +4,7 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/world"
 )
@@ -14,7 +14,9 @@ type Checkpoint struct {
 	bounds    physics.AABB
 	id        string
 	state     TriggerState
+	zone      Zone
 	triggered bool // Has been activated at least once
+	skin      *Skin
 
 	// Callback when checkpoint is activated
 	OnActivate func(id string, x, y float64)
@@ -50,12 +52,15 @@ func (c *Checkpoint) Draw(screen *ebiten.Image, camX, camY float64) {
 		// Yellow when not yet activated
 		col = color.RGBA{255, 255, 0, 128}
 	}
-	ebitenutil.DrawRect(screen, x, y, c.bounds.W, c.bounds.H, col)
+	draw.FillRect(screen, x, y, c.bounds.W, c.bounds.H, col)
 }
 
 // DrawWithContext implements Entity.
 func (c *Checkpoint) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
 	x, y := ctx.WorldToScreen(c.bounds.X, c.bounds.Y)
+	if c.skin.draw(screen, x, y, c.bounds.W, c.bounds.H, c.triggered) {
+		return
+	}
 
 	// Draw checkpoint indicator
 	var col color.RGBA
@@ -66,7 +71,7 @@ func (c *Checkpoint) DrawWithContext(screen *ebiten.Image, ctx *world.RenderCont
 		// Yellow when not yet activated
 		col = color.RGBA{255, 255, 0, 128}
 	}
-	ebitenutil.DrawRect(screen, x, y, c.bounds.W, c.bounds.H, col)
+	draw.FillRect(screen, x, y, c.bounds.W, c.bounds.H, col)
 }
 
 // Bounds implements Entity.
@@ -74,6 +79,17 @@ func (c *Checkpoint) Bounds() physics.AABB {
 	return c.bounds
 }
 
+// SetZone limits the checkpoint to a polygon or ellipse within its bounds.
+func (c *Checkpoint) SetZone(z Zone) {
+	z.fit(&c.bounds)
+	c.zone = z
+}
+
+// Overlaps implements ShapedTrigger.
+func (c *Checkpoint) Overlaps(aabb physics.AABB) bool {
+	return c.zone.Overlaps(c.bounds, aabb)
+}
+
 // OnEnter implements Trigger.
 func (c *Checkpoint) OnEnter(player *physics.Body) {
 	if !c.triggered {
@@ -94,6 +110,11 @@ func (c *Checkpoint) IsActive() bool {
 	return c.state.IsActive()
 }
 
+// SetActive sets whether the checkpoint can be activated.
+func (c *Checkpoint) SetActive(active bool) {
+	c.state.SetActive(active)
+}
+
 // WasTriggered implements Trigger.
 func (c *Checkpoint) WasTriggered() bool {
 	return c.state.WasTriggered()
@@ -113,3 +134,8 @@ func (c *Checkpoint) ID() string {
 func (c *Checkpoint) IsTriggered() bool {
 	return c.triggered
 }
+
+// SetSkin implements Skinnable.
+func (c *Checkpoint) SetSkin(skin *Skin) {
+	c.skin = skin
+}
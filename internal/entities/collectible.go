@@ -0,0 +1,137 @@
+package entities
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Collectible is picked up when touched and disappears.
+// Goals can require a number of collectibles before the level completes.
+type Collectible struct {
+	bounds physics.AABB
+	id     string
+	state  TriggerState
+	zone   Zone
+	skin   *Skin
+
+	// Callback when the collectible is picked up
+	OnCollect func(id string)
+}
+
+// NewCollectible creates a new collectible at the given position.
+func NewCollectible(x, y, w, h float64, id string) *Collectible {
+	return &Collectible{
+		bounds: physics.AABB{X: x, Y: y, W: w, H: h},
+		id:     id,
+		state:  NewTriggerState(),
+	}
+}
+
+// Update implements Entity.
+func (c *Collectible) Update(dt float64) {
+	// Collectibles don't need per-frame updates
+}
+
+// Draw implements Entity.
+// Deprecated: Use DrawWithContext for new implementations.
+func (c *Collectible) Draw(screen *ebiten.Image, camX, camY float64) {
+	if !c.state.Active {
+		return
+	}
+	draw.FillRect(screen, c.bounds.X-camX, c.bounds.Y-camY, c.bounds.W, c.bounds.H, color.RGBA{255, 220, 64, 220})
+}
+
+// DrawWithContext implements Entity.
+func (c *Collectible) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
+	if !c.state.Active {
+		return
+	}
+
+	x, y := ctx.WorldToScreen(c.bounds.X, c.bounds.Y)
+	if c.skin.draw(screen, x, y, c.bounds.W, c.bounds.H, false) {
+		return
+	}
+
+	// Gold square with a highlight
+	draw.FillRect(screen, x, y, c.bounds.W, c.bounds.H, color.RGBA{255, 220, 64, 220})
+	draw.FillRect(screen, x+2, y+2, c.bounds.W/3, c.bounds.H/3, color.RGBA{255, 255, 200, 220})
+}
+
+// Bounds implements Entity.
+func (c *Collectible) Bounds() physics.AABB {
+	return c.bounds
+}
+
+// SetZone limits the collectible to a polygon or ellipse within its bounds.
+func (c *Collectible) SetZone(z Zone) {
+	z.fit(&c.bounds)
+	c.zone = z
+}
+
+// Overlaps implements ShapedTrigger.
+func (c *Collectible) Overlaps(aabb physics.AABB) bool {
+	return c.zone.Overlaps(c.bounds, aabb)
+}
+
+// OnEnter implements Trigger.
+func (c *Collectible) OnEnter(player *physics.Body) {
+	if !c.state.Active {
+		return
+	}
+	c.state.Active = false // Picked up once
+	if c.OnCollect != nil {
+		c.OnCollect(c.id)
+	}
+}
+
+// OnExit implements Trigger.
+func (c *Collectible) OnExit(player *physics.Body) {
+	// Nothing to do on exit
+}
+
+// IsActive implements Trigger.
+func (c *Collectible) IsActive() bool {
+	return c.state.IsActive()
+}
+
+// WasTriggered implements Trigger.
+func (c *Collectible) WasTriggered() bool {
+	return c.state.WasTriggered()
+}
+
+// SetTriggered implements Trigger.
+func (c *Collectible) SetTriggered(triggered bool) {
+	c.state.SetTriggered(triggered)
+}
+
+// SetActive sets whether the collectible can still be picked up.
+func (c *Collectible) SetActive(active bool) {
+	c.state.SetActive(active)
+}
+
+// SetSkin implements Skinnable.
+func (c *Collectible) SetSkin(skin *Skin) {
+	c.skin = skin
+}
+
+// ID returns the collectible's ID.
+func (c *Collectible) ID() string {
+	return c.id
+}
+
+// SaveState implements Snapshotter. The state is whether the collectible is still
+// there to pick up.
+func (c *Collectible) SaveState() any {
+	return c.state.Active
+}
+
+// RestoreState implements Snapshotter.
+func (c *Collectible) RestoreState(state any) {
+	if active, ok := state.(bool); ok {
+		c.state.Active = active
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/world"
 )
@@ -73,7 +73,7 @@ func (d *DebugRenderer) drawTriggers(screen *ebiten.Image, checker TriggerChecke
 			col = inactiveColor
 		}
 
-		ebitenutil.DrawRect(screen, x, y, bounds.W, bounds.H, col)
+		draw.FillRect(screen, x, y, bounds.W, bounds.H, col)
 	}
 }
 
@@ -98,7 +98,7 @@ func (d *DebugRenderer) drawTriggersWithContext(screen *ebiten.Image, checker Tr
 			col = inactiveColor
 		}
 
-		ebitenutil.DrawRect(screen, x, y, bounds.W, bounds.H, col)
+		draw.FillRect(screen, x, y, bounds.W, bounds.H, col)
 	}
 }
 
@@ -121,10 +121,10 @@ func (d *DebugRenderer) drawBounds(screen *ebiten.Image, checker TriggerChecker,
 		}
 
 		// Draw border only
-		ebitenutil.DrawRect(screen, x, y, bounds.W, borderWidth, col)
-		ebitenutil.DrawRect(screen, x, y+bounds.H-borderWidth, bounds.W, borderWidth, col)
-		ebitenutil.DrawRect(screen, x, y, borderWidth, bounds.H, col)
-		ebitenutil.DrawRect(screen, x+bounds.W-borderWidth, y, borderWidth, bounds.H, col)
+		draw.FillRect(screen, x, y, bounds.W, borderWidth, col)
+		draw.FillRect(screen, x, y+bounds.H-borderWidth, bounds.W, borderWidth, col)
+		draw.FillRect(screen, x, y, borderWidth, bounds.H, col)
+		draw.FillRect(screen, x+bounds.W-borderWidth, y, borderWidth, bounds.H, col)
 	}
 }
 
@@ -145,10 +145,10 @@ func (d *DebugRenderer) drawBoundsWithContext(screen *ebiten.Image, checker Trig
 		}
 
 		// Draw border only
-		ebitenutil.DrawRect(screen, x, y, bounds.W, borderWidth, col)
-		ebitenutil.DrawRect(screen, x, y+bounds.H-borderWidth, bounds.W, borderWidth, col)
-		ebitenutil.DrawRect(screen, x, y, borderWidth, bounds.H, col)
-		ebitenutil.DrawRect(screen, x+bounds.W-borderWidth, y, borderWidth, bounds.H, col)
+		draw.FillRect(screen, x, y, bounds.W, borderWidth, col)
+		draw.FillRect(screen, x, y+bounds.H-borderWidth, bounds.W, borderWidth, col)
+		draw.FillRect(screen, x, y, borderWidth, bounds.H, col)
+		draw.FillRect(screen, x+bounds.W-borderWidth, y, borderWidth, bounds.H, col)
 	}
 }
 
@@ -165,10 +165,10 @@ func (d *DebugRenderer) DrawPlayerDebug(screen *ebiten.Image, player *physics.Bo
 	borderWidth := 1.0
 
 	// Draw border
-	ebitenutil.DrawRect(screen, x, y, player.W, borderWidth, col)
-	ebitenutil.DrawRect(screen, x, y+player.H-borderWidth, player.W, borderWidth, col)
-	ebitenutil.DrawRect(screen, x, y, borderWidth, player.H, col)
-	ebitenutil.DrawRect(screen, x+player.W-borderWidth, y, borderWidth, player.H, col)
+	draw.FillRect(screen, x, y, player.W, borderWidth, col)
+	draw.FillRect(screen, x, y+player.H-borderWidth, player.W, borderWidth, col)
+	draw.FillRect(screen, x, y, borderWidth, player.H, col)
+	draw.FillRect(screen, x+player.W-borderWidth, y, borderWidth, player.H, col)
 }
 
 // DrawPlayerDebugWithContext draws debug info for the player body using RenderContext.
@@ -182,10 +182,10 @@ func (d *DebugRenderer) DrawPlayerDebugWithContext(screen *ebiten.Image, player
 	borderWidth := 1.0
 
 	// Draw border
-	ebitenutil.DrawRect(screen, x, y, player.W, borderWidth, col)
-	ebitenutil.DrawRect(screen, x, y+player.H-borderWidth, player.W, borderWidth, col)
-	ebitenutil.DrawRect(screen, x, y, borderWidth, player.H, col)
-	ebitenutil.DrawRect(screen, x+player.W-borderWidth, y, borderWidth, player.H, col)
+	draw.FillRect(screen, x, y, player.W, borderWidth, col)
+	draw.FillRect(screen, x, y+player.H-borderWidth, player.W, borderWidth, col)
+	draw.FillRect(screen, x, y, borderWidth, player.H, col)
+	draw.FillRect(screen, x+player.W-borderWidth, y, borderWidth, player.H, col)
 }
 
 // ToggleAll toggles all debug displays.
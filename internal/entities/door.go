@@ -1,22 +1,112 @@
 package entities
 
 import (
+	"fmt"
 	"image/color"
 
+	"math"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/tween"
 	"github.com/torsten/GoP/internal/world"
 )
 
+// DoorObstruction selects what a door does when it would close on the player.
+type DoorObstruction string
+
+const (
+	// DoorBlock cancels the close; the door stays open.
+	DoorBlock DoorObstruction = "block"
+	// DoorWait keeps the door open until the doorway is clear, then closes it.
+	DoorWait DoorObstruction = "wait"
+	// DoorPush closes the door and pushes the player out of the doorway.
+	// If every side is blocked it falls back to waiting.
+	DoorPush DoorObstruction = "push"
+)
+
+// ParseDoorObstruction parses an obstruction policy, defaulting to DoorWait.
+func ParseDoorObstruction(s string) DoorObstruction {
+	switch DoorObstruction(s) {
+	case DoorBlock, DoorWait, DoorPush:
+		return DoorObstruction(s)
+	}
+	return DoorWait
+}
+
+// DoorDirection is the side an animated door slides toward as it opens.
+type DoorDirection string
+
+const (
+	// DoorUp shrinks the door toward its top edge.
+	DoorUp DoorDirection = "up"
+	// DoorDown shrinks the door toward its bottom edge.
+	DoorDown DoorDirection = "down"
+	// DoorLeft shrinks the door toward its left edge.
+	DoorLeft DoorDirection = "left"
+	// DoorRight shrinks the door toward its right edge.
+	DoorRight DoorDirection = "right"
+)
+
+// ParseDoorDirection parses an opening direction, defaulting to DoorUp.
+func ParseDoorDirection(s string) DoorDirection {
+	switch DoorDirection(s) {
+	case DoorUp, DoorDown, DoorLeft, DoorRight:
+		return DoorDirection(s)
+	}
+	return DoorUp
+}
+
+// DoorState is where a door is in its open/close cycle.
+type DoorState string
+
+const (
+	DoorClosed  DoorState = "closed"
+	DoorOpening DoorState = "opening"
+	DoorOpen    DoorState = "open"
+	DoorClosing DoorState = "closing"
+)
+
 // Door is a SolidEntity that can open and close.
 // When closed, it blocks player movement. When open, it has no collision.
+// With an open time set, it slides open over that time in FixedUpdate, and
+// the part still in the doorway stays solid.
 type Door struct {
-	body     *physics.Body
-	id       string
-	isOpen   bool
-	closedW  float64 // Width when closed
-	closedH  float64 // Height when closed
+	body    *physics.Body
+	id      string
+	isOpen  bool    // Open or opening; false while closing
+	closedX float64 // Position when closed
+	closedY float64
+	closedW float64 // Width when closed
+	closedH float64 // Height when closed
+	skin    *Skin
+
+	openTime     float64 // Seconds to open or close fully; 0 snaps
+	direction    DoorDirection
+	progress     float64 // 0 closed to 1 open
+	prevProgress float64 // progress before the last FixedUpdate, for interpolation
+	ease         tween.Ease
+	slide        *tween.Tween[float64] // Current slide toward open or closed
+
+	obstruction  DoorObstruction
+	closePending bool // Waiting for the doorway to clear
+
+	// Lock state; a locked door ignores switches and opens on contact with keys
+	locked       bool
+	keysRequired int    // Number of keys needed (when keyID is empty)
+	keyID        string // Specific key needed, if any
+
+	// Occupant is the body checked for obstruction on close (usually the player)
+	Occupant *physics.Body
+	// Blocked reports whether an area overlaps level geometry, so pushing
+	// doesn't move the occupant into a wall. Optional.
+	Blocked func(physics.AABB) bool
+	// TryUnlock is called when the occupant touches a locked door. It returns
+	// whether the player has (and gives up) the keys the lock needs.
+	TryUnlock func(keys int, keyID string) bool
 }
 
 // NewDoor creates a new door at the given position.
@@ -29,69 +119,99 @@ func NewDoor(x, y, w, h float64, id string) *Door {
 			W:    w,
 			H:    h,
 		},
-		id:      id,
-		isOpen:  false,
-		closedW: w,
-		closedH: h,
+		id:          id,
+		isOpen:      false,
+		closedX:     x,
+		closedY:     y,
+		closedW:     w,
+		closedH:     h,
+		direction:   DoorUp,
+		obstruction: DoorWait,
 	}
 }
 
 // Update implements Entity.
+// A door waiting to close closes once the doorway is clear.
+// A locked door unlocks and opens when the occupant touches it with the keys.
 func (d *Door) Update(dt float64) {
-	// Doors don't need per-frame updates
+	if d.closePending && !d.obstructed() {
+		d.closePending = false
+		d.close()
+	}
+	if d.locked && !d.isOpen && d.touched() && d.TryUnlock != nil && d.TryUnlock(d.keysRequired, d.keyID) {
+		d.locked = false
+		d.Open()
+	}
 }
 
 // Draw implements Entity.
 // Deprecated: Use DrawWithContext for new implementations.
 func (d *Door) Draw(screen *ebiten.Image, camX, camY float64) {
-	if d.isOpen {
-		// Draw open door (outline only)
-		x := d.body.PosX - camX
-		y := d.body.PosY - camY
-		outlineColor := color.RGBA{100, 100, 100, 255}
-		ebitenutil.DrawRect(screen, x, y, d.closedW, 2, outlineColor)
-		ebitenutil.DrawRect(screen, x, y+d.closedH-2, d.closedW, 2, outlineColor)
-		ebitenutil.DrawRect(screen, x, y, 2, d.closedH, outlineColor)
-		ebitenutil.DrawRect(screen, x+d.closedW-2, y, 2, d.closedH, outlineColor)
-	} else {
-		// Draw closed door (solid)
-		x := d.body.PosX - camX
-		y := d.body.PosY - camY
-		doorColor := color.RGBA{139, 90, 43, 255} // Brown
-		ebitenutil.DrawRect(screen, x, y, d.body.W, d.body.H, doorColor)
-
-		// Draw border
-		borderColor := color.RGBA{80, 50, 20, 255}
-		ebitenutil.DrawRect(screen, x, y, d.body.W, 2, borderColor)
-		ebitenutil.DrawRect(screen, x, y+d.body.H-2, d.body.W, 2, borderColor)
-		ebitenutil.DrawRect(screen, x, y, 2, d.body.H, borderColor)
-		ebitenutil.DrawRect(screen, x+d.body.W-2, y, 2, d.body.H, borderColor)
-	}
+	d.drawDoor(screen, d.closedX-camX, d.closedY-camY, d.progress)
 }
 
 // DrawWithContext implements Entity.
+// A moving door is drawn between its last two physics steps using ctx.Alpha.
 func (d *Door) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
 	// Use WorldToScreen for coordinate conversion
-	x, y := ctx.WorldToScreen(d.body.PosX, d.body.PosY)
+	x, y := ctx.WorldToScreen(d.closedX, d.closedY)
+	d.drawDoor(screen, x, y, mathx.Lerp(d.prevProgress, d.progress, ctx.Alpha))
+}
 
-	if d.isOpen {
-		// Draw open door (outline only)
-		outlineColor := color.RGBA{100, 100, 100, 255}
-		ebitenutil.DrawRect(screen, x, y, d.closedW, 2, outlineColor)
-		ebitenutil.DrawRect(screen, x, y+d.closedH-2, d.closedW, 2, outlineColor)
-		ebitenutil.DrawRect(screen, x, y, 2, d.closedH, outlineColor)
-		ebitenutil.DrawRect(screen, x+d.closedW-2, y, 2, d.closedH, outlineColor)
+// drawDoor draws the door with its closed top-left corner at x, y on screen,
+// progress of the way open.
+func (d *Door) drawDoor(screen *ebiten.Image, x, y, progress float64) {
+	panel := d.boundsAt(progress)
+	px, py := x+panel.X-d.closedX, y+panel.Y-d.closedY
+
+	var skinned bool
+	if progress < 1 {
+		skinned = d.skin.draw(screen, px, py, panel.W, panel.H, false)
 	} else {
-		// Draw closed door (solid)
+		skinned = d.skin.draw(screen, x, y, d.closedW, d.closedH, true)
+	}
+	if skinned {
+		if d.locked {
+			d.drawLock(screen, x, y)
+		}
+		return
+	}
+
+	if progress > 0 {
+		// Draw the doorway (outline only, amber while waiting to close)
+		outlineColor := color.RGBA{100, 100, 100, 255}
+		if d.closePending {
+			outlineColor = color.RGBA{230, 170, 40, 255}
+		}
+		draw.StrokeRect(screen, x, y, d.closedW, d.closedH, 2, outlineColor)
+	}
+	if progress < 1 {
+		// Draw the closed part of the door (solid)
 		doorColor := color.RGBA{139, 90, 43, 255} // Brown
-		ebitenutil.DrawRect(screen, x, y, d.body.W, d.body.H, doorColor)
+		draw.FillRect(screen, px, py, panel.W, panel.H, doorColor)
 
 		// Draw border
 		borderColor := color.RGBA{80, 50, 20, 255}
-		ebitenutil.DrawRect(screen, x, y, d.body.W, 2, borderColor)
-		ebitenutil.DrawRect(screen, x, y+d.body.H-2, d.body.W, 2, borderColor)
-		ebitenutil.DrawRect(screen, x, y, 2, d.body.H, borderColor)
-		ebitenutil.DrawRect(screen, x+d.body.W-2, y, 2, d.body.H, borderColor)
+		draw.StrokeRect(screen, px, py, panel.W, panel.H, min(2, panel.W/2, panel.H/2), borderColor)
+	}
+
+	if d.locked {
+		d.drawLock(screen, x, y)
+	}
+}
+
+// drawLock draws a padlock in the middle of a locked door, with the number
+// of keys needed when it's more than one.
+func (d *Door) drawLock(screen *ebiten.Image, x, y float64) {
+	cx, cy := x+d.closedW/2, y+d.closedH/2
+	lockColor := color.RGBA{255, 200, 40, 255}
+	draw.FillRect(screen, cx-3, cy-7, 6, 2, lockColor)
+	draw.FillRect(screen, cx-3, cy-7, 2, 5, lockColor)
+	draw.FillRect(screen, cx+1, cy-7, 2, 5, lockColor)
+	draw.FillRect(screen, cx-5, cy-2, 10, 8, lockColor)
+	draw.FillRect(screen, cx-1, cy, 2, 3, color.RGBA{80, 50, 20, 255})
+	if d.keyID == "" && d.keysRequired > 1 {
+		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d", d.keysRequired), int(cx)-3, int(cy)+7)
 	}
 }
 
@@ -106,9 +226,9 @@ func (d *Door) GetBody() *physics.Body {
 }
 
 // IsActive implements SolidEntity.
-// A door is active when it is closed (blocking movement).
+// A door is active until it is fully open (blocking movement).
 func (d *Door) IsActive() bool {
-	return !d.isOpen
+	return d.progress < 1
 }
 
 // GetID returns the door's identifier.
@@ -116,36 +236,242 @@ func (d *Door) GetID() string {
 	return d.id
 }
 
-// IsOpen returns whether the door is open.
+// IsOpen returns whether the door is open or opening.
 func (d *Door) IsOpen() bool {
 	return d.isOpen
 }
 
-// Open opens the door (removes collision).
+// State returns whether the door is closed, opening, open, or closing.
+func (d *Door) State() DoorState {
+	switch {
+	case d.isOpen && d.progress < 1:
+		return DoorOpening
+	case d.isOpen:
+		return DoorOpen
+	case d.progress > 0:
+		return DoorClosing
+	}
+	return DoorClosed
+}
+
+// TargetState implements rules.StateReporter.
+func (d *Door) TargetState() string {
+	return string(d.State())
+}
+
+// SetOpenTime sets how many seconds the door takes to open or close. Zero
+// (the default) opens and closes instantly.
+func (d *Door) SetOpenTime(seconds float64) {
+	d.openTime = max(seconds, 0)
+}
+
+// SetEase sets the easing curve of the door's slide (nil = linear).
+func (d *Door) SetEase(ease tween.Ease) {
+	d.ease = ease
+}
+
+// SetDirection sets the side the door slides toward as it opens.
+func (d *Door) SetDirection(dir DoorDirection) {
+	d.direction = dir
+	d.updateBody()
+}
+
+// SetObstruction sets what the door does when closing on its occupant.
+func (d *Door) SetObstruction(o DoorObstruction) {
+	d.obstruction = o
+}
+
+// Obstruction returns the door's obstruction policy.
+func (d *Door) Obstruction() DoorObstruction {
+	return d.obstruction
+}
+
+// SetLock locks the door until the player touches it with keys: keyID names
+// a specific key, otherwise any keys count and keys are needed. The keys are
+// used up when the door unlocks.
+func (d *Door) SetLock(keys int, keyID string) {
+	if keys < 1 {
+		keys = 1
+	}
+	d.locked = true
+	d.keysRequired = keys
+	d.keyID = keyID
+}
+
+// Locked returns whether the door is locked.
+func (d *Door) Locked() bool {
+	return d.locked
+}
+
+// ClosePending returns whether the door is waiting for the doorway to clear.
+func (d *Door) ClosePending() bool {
+	return d.closePending
+}
+
+// Open opens the door (removes collision), instantly or over its open time.
+// A pending close is cancelled.
 func (d *Door) Open() {
 	d.isOpen = true
-	d.body.W = 0
-	d.body.H = 0
+	d.closePending = false
+	if d.openTime <= 0 {
+		d.snap(1)
+	}
 }
 
 // Close closes the door (restores collision).
+// If the occupant stands in the doorway, the obstruction policy decides
+// whether the close is cancelled, deferred, or pushes the occupant out.
 func (d *Door) Close() {
+	if d.obstructed() {
+		switch d.obstruction {
+		case DoorBlock:
+			return
+		case DoorPush:
+			if physics.PushOut(d.Occupant, d.closedBounds(), d.Blocked) {
+				break
+			}
+			d.closePending = true
+			return
+		default:
+			d.closePending = true
+			return
+		}
+	}
+	d.closePending = false
+	d.close()
+}
+
+// close restores the door's collision, instantly or over its open time.
+func (d *Door) close() {
 	d.isOpen = false
-	d.body.W = d.closedW
-	d.body.H = d.closedH
+	if d.openTime <= 0 {
+		d.snap(0)
+	}
+}
+
+// FixedUpdate implements FixedUpdater: an opening or closing door moves one
+// physics step, so its collision changes in step with the player's.
+// A closing door that would hit the occupant follows the obstruction policy:
+// DoorBlock opens again, DoorPush pushes the occupant out, and DoorWait (or a
+// push with nowhere to go) holds until the way is clear.
+func (d *Door) FixedUpdate(dt float64) {
+	d.prevProgress = d.progress
+	if d.openTime <= 0 {
+		return
+	}
+	target := 0.0
+	if d.isOpen {
+		target = 1
+	}
+	if d.progress == target {
+		return
+	}
+
+	// A slide that was reversed or snapped starts over from where the door is
+	if d.slide == nil || d.slide.To != target || d.slide.Value() != d.progress {
+		d.slide = tween.New(d.progress, target, d.openTime*math.Abs(target-d.progress), d.ease)
+	}
+	if !d.isOpen && d.closingBlocked(d.boundsAt(d.slide.Peek(dt))) {
+		return
+	}
+	d.slide.Update(dt)
+	d.progress = d.slide.Value()
+	d.updateBody()
+}
+
+// closingBlocked returns whether closing to panel must stop this step
+// because the occupant is in the way.
+func (d *Door) closingBlocked(panel physics.AABB) bool {
+	if d.Occupant == nil || !d.Occupant.AABB().Intersects(panel) {
+		return false
+	}
+	switch d.obstruction {
+	case DoorBlock:
+		d.isOpen = true
+		return true
+	case DoorPush:
+		return !physics.PushOut(d.Occupant, panel, d.Blocked)
+	}
+	return true
+}
+
+// snap moves the door straight to progress, with nothing to interpolate.
+func (d *Door) snap(progress float64) {
+	d.progress = progress
+	d.prevProgress = progress
+	d.updateBody()
+}
+
+// updateBody sizes the body to the part of the door still in the doorway.
+// A fully open door keeps its position with no size.
+func (d *Door) updateBody() {
+	if d.progress >= 1 {
+		d.body.PosX, d.body.PosY = d.closedX, d.closedY
+		d.body.W, d.body.H = 0, 0
+		return
+	}
+	b := d.boundsAt(d.progress)
+	d.body.PosX, d.body.PosY = b.X, b.Y
+	d.body.W, d.body.H = b.W, b.H
+}
+
+// boundsAt returns the part of the door in the doorway when it is progress
+// of the way open.
+func (d *Door) boundsAt(progress float64) physics.AABB {
+	b := d.closedBounds()
+	switch d.direction {
+	case DoorUp:
+		b.H *= 1 - progress
+	case DoorDown:
+		b.Y += b.H * progress
+		b.H *= 1 - progress
+	case DoorLeft:
+		b.W *= 1 - progress
+	case DoorRight:
+		b.X += b.W * progress
+		b.W *= 1 - progress
+	}
+	return b
+}
+
+// closedBounds returns the door's bounds when closed.
+func (d *Door) closedBounds() physics.AABB {
+	return physics.AABB{X: d.closedX, Y: d.closedY, W: d.closedW, H: d.closedH}
+}
+
+// obstructed returns whether the occupant stands in the open doorway.
+func (d *Door) obstructed() bool {
+	return d.isOpen && d.Occupant != nil && d.Occupant.AABB().Intersects(d.closedBounds())
+}
+
+// touched returns whether the occupant is up against the closed door.
+func (d *Door) touched() bool {
+	if d.Occupant == nil {
+		return false
+	}
+	b := d.closedBounds()
+	return d.Occupant.AABB().Intersects(physics.AABB{X: b.X - 1, Y: b.Y - 1, W: b.W + 2, H: b.H + 2})
 }
 
 // Toggle switches the door state.
+// Toggling a door that is waiting to close keeps it open.
+// Locked doors ignore toggles.
 func (d *Door) Toggle() {
-	if d.isOpen {
+	if d.locked {
+		return
+	}
+	if d.isOpen && !d.closePending {
 		d.Close()
 	} else {
 		d.Open()
 	}
 }
 
-// Activate implements Targetable - opens the door.
+// Activate implements Targetable - opens the door unless it is locked.
 func (d *Door) Activate() {
+	if d.locked {
+		return
+	}
 	d.Open()
 }
 
@@ -154,7 +480,40 @@ func (d *Door) Deactivate() {
 	d.Close()
 }
 
+// doorState is a door's saved state.
+type doorState struct {
+	open, closePending, locked bool
+}
+
+// SaveState implements Snapshotter.
+func (d *Door) SaveState() any {
+	return doorState{open: d.isOpen, closePending: d.closePending, locked: d.locked}
+}
+
+// RestoreState implements Snapshotter.
+// A door saved while moving is restored where it was heading.
+func (d *Door) RestoreState(state any) {
+	st, ok := state.(doorState)
+	if !ok {
+		return
+	}
+	d.locked = st.locked
+	d.isOpen = st.open
+	if st.open {
+		d.closePending = st.closePending
+		d.snap(1)
+		return
+	}
+	d.closePending = false
+	d.snap(0)
+}
+
 // TargetID implements Targetable - returns the door's unique identifier.
 func (d *Door) TargetID() string {
 	return d.id
 }
+
+// SetSkin implements Skinnable.
+func (d *Door) SetSkin(skin *Skin) {
+	d.skin = skin
+}
@@ -0,0 +1,313 @@
+//go:build display
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/tween"
+)
+
+// newDoorwayScene builds an open door with the player standing in it and a
+// toggle switch wired to the door.
+func newDoorwayScene(policy DoorObstruction) (*Door, *Switch, *physics.Body) {
+	player := &physics.Body{PosX: 106, PosY: 40, W: 12, H: 12}
+
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.SetObstruction(policy)
+	door.Occupant = player
+	door.Open()
+
+	registry := NewTargetRegistry()
+	registry.Register(door)
+	sw := NewSwitch(0, 40, 16, 16, "door1")
+	sw.SetRegistry(registry)
+
+	return door, sw, player
+}
+
+func TestDoorBlockKeepsDoorOpen(t *testing.T) {
+	door, sw, player := newDoorwayScene(DoorBlock)
+
+	sw.OnEnter(player)
+
+	if !door.IsOpen() {
+		t.Fatal("door closed on the player with DoorBlock")
+	}
+	if door.ClosePending() {
+		t.Error("DoorBlock should not leave a pending close")
+	}
+
+	// Leaving the doorway doesn't close it later
+	player.PosX = 150
+	door.Update(1.0 / 60.0)
+	if !door.IsOpen() {
+		t.Error("DoorBlock door closed after the player left")
+	}
+}
+
+func TestDoorWaitClosesWhenClear(t *testing.T) {
+	door, sw, player := newDoorwayScene(DoorWait)
+
+	sw.OnEnter(player)
+
+	if !door.IsOpen() || !door.ClosePending() {
+		t.Fatalf("door open=%v pending=%v, want open and pending", door.IsOpen(), door.ClosePending())
+	}
+
+	door.Update(1.0 / 60.0)
+	if !door.IsOpen() {
+		t.Fatal("door closed while the player was still in the doorway")
+	}
+
+	player.PosX = 150
+	door.Update(1.0 / 60.0)
+	if door.IsOpen() || door.ClosePending() {
+		t.Errorf("door open=%v pending=%v after the doorway cleared, want closed", door.IsOpen(), door.ClosePending())
+	}
+}
+
+func TestDoorWaitToggleCancelsPendingClose(t *testing.T) {
+	door, sw, player := newDoorwayScene(DoorWait)
+
+	sw.OnEnter(player) // Close requested, pending
+	sw.OnEnter(player) // Toggled again: stay open
+
+	if !door.IsOpen() || door.ClosePending() {
+		t.Fatalf("door open=%v pending=%v, want open with no pending close", door.IsOpen(), door.ClosePending())
+	}
+
+	player.PosX = 150
+	door.Update(1.0 / 60.0)
+	if !door.IsOpen() {
+		t.Error("cancelled close still happened")
+	}
+}
+
+func TestDoorPushMovesPlayerOut(t *testing.T) {
+	door, sw, player := newDoorwayScene(DoorPush)
+
+	sw.OnEnter(player)
+
+	if door.IsOpen() {
+		t.Fatal("DoorPush door didn't close")
+	}
+	if player.AABB().Intersects(door.Bounds()) {
+		t.Errorf("player at (%v, %v) still inside the door", player.PosX, player.PosY)
+	}
+	if player.PosX != 116 {
+		t.Errorf("player X = %v, want 116 (nearest side is right)", player.PosX)
+	}
+}
+
+func TestDoorPushFallsBackToWait(t *testing.T) {
+	door, sw, player := newDoorwayScene(DoorPush)
+	door.Blocked = func(physics.AABB) bool { return true }
+
+	sw.OnEnter(player)
+
+	if !door.IsOpen() || !door.ClosePending() {
+		t.Fatalf("door open=%v pending=%v, want open and pending when pushing is blocked", door.IsOpen(), door.ClosePending())
+	}
+	if player.PosX != 106 {
+		t.Errorf("player moved to X = %v, want unchanged", player.PosX)
+	}
+}
+
+func TestDoorClosesNormallyWhenClear(t *testing.T) {
+	for _, policy := range []DoorObstruction{DoorBlock, DoorWait, DoorPush} {
+		door, sw, player := newDoorwayScene(policy)
+		player.PosX = 0 // On the switch, away from the door
+
+		sw.OnEnter(player)
+
+		if door.IsOpen() {
+			t.Errorf("%s: door didn't close with a clear doorway", policy)
+		}
+	}
+}
+
+func TestParseDoorObstruction(t *testing.T) {
+	tests := map[string]DoorObstruction{
+		"block": DoorBlock,
+		"wait":  DoorWait,
+		"push":  DoorPush,
+		"":      DoorWait,
+		"slam":  DoorWait,
+	}
+	for in, want := range tests {
+		if got := ParseDoorObstruction(in); got != want {
+			t.Errorf("ParseDoorObstruction(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLockedDoorOpensOnContactWithKeys(t *testing.T) {
+	// Player flush against the door's left side
+	player := &physics.Body{PosX: 88, PosY: 40, W: 12, H: 12}
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.Occupant = player
+	door.SetLock(2, "")
+
+	held := 1
+	door.TryUnlock = func(keys int, keyID string) bool {
+		if held < keys {
+			return false
+		}
+		held -= keys
+		return true
+	}
+
+	door.Activate()
+	door.Update(1.0 / 60.0)
+	if door.IsOpen() {
+		t.Fatal("locked door opened with too few keys or by a switch")
+	}
+
+	held = 2
+	door.Update(1.0 / 60.0)
+	if !door.IsOpen() || door.Locked() {
+		t.Fatal("locked door did not unlock on contact with enough keys")
+	}
+	if held != 0 {
+		t.Errorf("held %d keys after unlocking, want 0", held)
+	}
+}
+
+func TestLockedDoorIgnoresDistantPlayer(t *testing.T) {
+	player := &physics.Body{PosX: 40, PosY: 40, W: 12, H: 12}
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.Occupant = player
+	door.SetLock(1, "")
+	door.TryUnlock = func(int, string) bool { return true }
+
+	door.Update(1.0 / 60.0)
+	if door.IsOpen() {
+		t.Error("locked door opened without contact")
+	}
+}
+
+func TestDoorAnimatesOpen(t *testing.T) {
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.SetOpenTime(0.5)
+
+	door.Open()
+	if door.State() != DoorOpening || !door.IsActive() || door.GetBody().H != 64 {
+		t.Fatalf("state %s active=%v h=%v right after Open, want opening, still solid at full height",
+			door.State(), door.IsActive(), door.GetBody().H)
+	}
+
+	door.FixedUpdate(0.25)
+	if b := door.GetBody(); b.PosY != 0 || b.H != 32 || !door.IsActive() {
+		t.Errorf("halfway body = %+v active=%v, want the top 32px still solid", *b, door.IsActive())
+	}
+
+	door.FixedUpdate(0.25)
+	if door.State() != DoorOpen || door.IsActive() {
+		t.Errorf("state %s active=%v after the open time, want open and not solid", door.State(), door.IsActive())
+	}
+
+	door.Close()
+	door.FixedUpdate(0.125)
+	if door.State() != DoorClosing || door.GetBody().H != 16 {
+		t.Errorf("state %s h=%v a quarter into closing, want closing at 16px", door.State(), door.GetBody().H)
+	}
+	door.FixedUpdate(1)
+	if door.State() != DoorClosed || door.GetBody().H != 64 {
+		t.Errorf("state %s h=%v after closing, want closed at 64px", door.State(), door.GetBody().H)
+	}
+}
+
+func TestDoorEasesSlide(t *testing.T) {
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.SetOpenTime(1)
+	door.SetEase(tween.InQuad)
+
+	door.Open()
+	door.FixedUpdate(0.5)
+	if door.progress != 0.25 || door.GetBody().H != 48 {
+		t.Errorf("progress %v h=%v halfway through an ease-in, want 0.25 at 48px", door.progress, door.GetBody().H)
+	}
+
+	// Reversing mid-slide heads back from where the door is
+	door.Close()
+	door.FixedUpdate(0.125)
+	if door.State() != DoorClosing || door.progress >= 0.25 {
+		t.Errorf("state %s progress %v after reversing, want closing below 0.25", door.State(), door.progress)
+	}
+	door.FixedUpdate(1)
+	if door.State() != DoorClosed {
+		t.Errorf("state %s, want closed", door.State())
+	}
+}
+
+func TestDoorSlideDirection(t *testing.T) {
+	door := NewDoor(100, 0, 32, 64, "door1")
+	door.SetOpenTime(1)
+	door.SetDirection(DoorRight)
+
+	door.Open()
+	door.FixedUpdate(0.25)
+	if b := door.GetBody(); b.PosX != 108 || b.W != 24 || b.H != 64 {
+		t.Errorf("body = %+v a quarter open to the right, want x 108, 24x64", *b)
+	}
+}
+
+func TestDoorClosingWaitsForOccupant(t *testing.T) {
+	// The player walks under a closing door that shuts toward the floor
+	player := &physics.Body{PosX: 102, PosY: 40, W: 12, H: 12}
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.SetOpenTime(0.5)
+	door.Open()
+	door.FixedUpdate(1)
+	door.Occupant = &physics.Body{PosX: 300}
+	door.Close()
+	door.Occupant = player
+
+	for i := 0; i < 60; i++ {
+		door.FixedUpdate(1.0 / 60.0)
+	}
+	if door.State() != DoorClosing || door.GetBody().H > 40 {
+		t.Fatalf("state %s h=%v, want closing and stopped above the player", door.State(), door.GetBody().H)
+	}
+
+	player.PosX = 150
+	door.FixedUpdate(1)
+	if door.State() != DoorClosed {
+		t.Errorf("state %s after the player left, want closed", door.State())
+	}
+}
+
+func TestDoorClosingBlockReopens(t *testing.T) {
+	player := &physics.Body{PosX: 102, PosY: 40, W: 12, H: 12}
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.SetObstruction(DoorBlock)
+	door.SetOpenTime(0.5)
+	door.Open()
+	door.FixedUpdate(1)
+	door.Occupant = &physics.Body{PosX: 300}
+	door.Close()
+	door.Occupant = player
+
+	for i := 0; i < 60; i++ {
+		door.FixedUpdate(1.0 / 60.0)
+	}
+	if door.State() != DoorOpen {
+		t.Errorf("state %s, want a blocked close to open again", door.State())
+	}
+}
+
+func TestDoorInterpolatesDrawing(t *testing.T) {
+	door := NewDoor(0, 0, 16, 64, "door1")
+	door.SetOpenTime(1)
+	door.Open()
+	door.FixedUpdate(0.5)
+
+	if door.prevProgress != 0 || door.progress != 0.5 {
+		t.Fatalf("progress %v -> %v, want 0 -> 0.5", door.prevProgress, door.progress)
+	}
+	if b := door.boundsAt(0.25); b.H != 48 {
+		t.Errorf("drawn halfway between steps at h=%v, want 48", b.H)
+	}
+}
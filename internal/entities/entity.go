@@ -25,6 +25,13 @@ type Entity interface {
 	Bounds() physics.AABB
 }
 
+// FixedUpdater is an entity whose collision changes over time, like an
+// animated door. It steps with physics at the fixed rate (see
+// EntityWorld.FixedUpdate) as well as getting Update once per frame.
+type FixedUpdater interface {
+	FixedUpdate(dt float64)
+}
+
 // Trigger is an entity that responds to player overlap.
 // Triggers do not have solid collision - they use AABB overlap tests.
 type Trigger interface {
@@ -46,6 +53,17 @@ type Trigger interface {
 	SetTriggered(triggered bool)
 }
 
+// ShapedTrigger is a trigger whose zone is a shape inside its bounds, like
+// a polygon hazard along a slope or a round checkpoint (see Zone). The
+// player is only inside it when their AABB overlaps the bounds and Overlaps
+// agrees.
+type ShapedTrigger interface {
+	Trigger
+
+	// Overlaps reports whether the AABB touches the trigger's shape.
+	Overlaps(aabb physics.AABB) bool
+}
+
 // SolidEntity is an entity with physics collision.
 // These entities participate in tile collision resolution.
 // This interface is compatible with physics.SolidEntity.
@@ -89,3 +107,16 @@ func (s *TriggerState) SetTriggered(triggered bool) {
 func (s *TriggerState) SetActive(active bool) {
 	s.Active = active
 }
+
+// entityID returns the entity's identifier from whichever ID method it has.
+func entityID(e Entity) string {
+	switch e := e.(type) {
+	case Targetable:
+		return e.TargetID()
+	case interface{ ID() string }:
+		return e.ID()
+	case interface{ GetID() string }:
+		return e.GetID()
+	}
+	return ""
+}
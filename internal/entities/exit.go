@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// LevelExit moves the player to another room when touched: a door or the
+// edge of the map leading to a spawn in another level, or the same one.
+type LevelExit struct {
+	bounds physics.AABB
+	level  string // Level file, relative to the current level ("" = same level)
+	spawn  string // Spawn id to arrive at ("" = the level's first spawn)
+	state  TriggerState
+	zone   Zone
+	skin   *Skin
+
+	// OnUse is called with the exit's level and spawn when the player
+	// enters it
+	OnUse func(level, spawn string)
+}
+
+// NewLevelExit creates an exit leading to the spawn with the given id in
+// level.
+func NewLevelExit(x, y, w, h float64, level, spawn string) *LevelExit {
+	return &LevelExit{
+		bounds: physics.AABB{X: x, Y: y, W: w, H: h},
+		level:  level,
+		spawn:  spawn,
+		state:  NewTriggerState(),
+	}
+}
+
+// Update implements Entity.
+func (e *LevelExit) Update(dt float64) {
+	// Exits don't need per-frame updates
+}
+
+// Draw implements Entity.
+// Deprecated: Use DrawWithContext for new implementations.
+func (e *LevelExit) Draw(screen *ebiten.Image, camX, camY float64) {
+	e.draw(screen, e.bounds.X-camX, e.bounds.Y-camY)
+}
+
+// DrawWithContext implements Entity.
+func (e *LevelExit) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
+	x, y := ctx.WorldToScreen(e.bounds.X, e.bounds.Y)
+	if e.skin.draw(screen, x, y, e.bounds.W, e.bounds.H, false) {
+		return
+	}
+	e.draw(screen, x, y)
+}
+
+// draw draws the exit as a translucent purple area at screen position x, y.
+func (e *LevelExit) draw(screen *ebiten.Image, x, y float64) {
+	if !e.state.Active {
+		return
+	}
+	draw.FillRect(screen, x, y, e.bounds.W, e.bounds.H, color.RGBA{160, 80, 255, 96})
+	draw.StrokeRect(screen, x, y, e.bounds.W, e.bounds.H, 1, color.RGBA{200, 160, 255, 200})
+}
+
+// Bounds implements Entity.
+func (e *LevelExit) Bounds() physics.AABB {
+	return e.bounds
+}
+
+// SetZone limits the exit to a polygon or ellipse within its bounds.
+func (e *LevelExit) SetZone(z Zone) {
+	z.fit(&e.bounds)
+	e.zone = z
+}
+
+// Overlaps implements ShapedTrigger.
+func (e *LevelExit) Overlaps(aabb physics.AABB) bool {
+	return e.zone.Overlaps(e.bounds, aabb)
+}
+
+// OnEnter implements Trigger.
+func (e *LevelExit) OnEnter(player *physics.Body) {
+	if e.state.Active && e.OnUse != nil {
+		e.OnUse(e.level, e.spawn)
+	}
+}
+
+// OnExit implements Trigger.
+func (e *LevelExit) OnExit(player *physics.Body) {
+	// Nothing to do on exit
+}
+
+// IsActive implements Trigger.
+func (e *LevelExit) IsActive() bool {
+	return e.state.IsActive()
+}
+
+// SetActive sets whether the exit can be used, e.g. locked until a rule
+// opens it.
+func (e *LevelExit) SetActive(active bool) {
+	e.state.SetActive(active)
+}
+
+// WasTriggered implements Trigger.
+func (e *LevelExit) WasTriggered() bool {
+	return e.state.WasTriggered()
+}
+
+// SetTriggered implements Trigger.
+func (e *LevelExit) SetTriggered(triggered bool) {
+	e.state.SetTriggered(triggered)
+}
+
+// Target returns the level and spawn id the exit leads to.
+func (e *LevelExit) Target() (level, spawn string) {
+	return e.level, e.spawn
+}
+
+// SetSkin implements Skinnable.
+func (e *LevelExit) SetSkin(skin *Skin) {
+	e.skin = skin
+}
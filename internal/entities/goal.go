@@ -4,7 +4,7 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/world"
 )
@@ -13,9 +13,18 @@ import (
 type Goal struct {
 	bounds physics.AABB
 	state  TriggerState
+	zone   Zone
+	skin   *Skin
 
 	// Callback when goal is reached
 	OnComplete func()
+
+	// Requirement returns why the goal can't complete yet, or "" if it can.
+	// Nil means touching the goal is enough.
+	Requirement func() string
+	// OnBlocked is called with the reason when the player touches a goal
+	// whose requirements aren't met
+	OnBlocked func(reason string)
 }
 
 // NewGoal creates a new goal at the given position.
@@ -43,14 +52,14 @@ func (g *Goal) Draw(screen *ebiten.Image, camX, camY float64) {
 
 	// Draw goal indicator (green/blue gradient effect)
 	goalColor := color.RGBA{0, 200, 255, 128}
-	ebitenutil.DrawRect(screen, x, y, g.bounds.W, g.bounds.H, goalColor)
+	draw.FillRect(screen, x, y, g.bounds.W, g.bounds.H, goalColor)
 
 	// Draw border
 	borderColor := color.RGBA{255, 255, 255, 255}
-	ebitenutil.DrawRect(screen, x, y, g.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y+g.bounds.H-2, g.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y, 2, g.bounds.H, borderColor)
-	ebitenutil.DrawRect(screen, x+g.bounds.W-2, y, 2, g.bounds.H, borderColor)
+	draw.FillRect(screen, x, y, g.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y+g.bounds.H-2, g.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y, 2, g.bounds.H, borderColor)
+	draw.FillRect(screen, x+g.bounds.W-2, y, 2, g.bounds.H, borderColor)
 }
 
 // DrawWithContext implements Entity.
@@ -60,17 +69,23 @@ func (g *Goal) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
 	}
 
 	x, y := ctx.WorldToScreen(g.bounds.X, g.bounds.Y)
+	if g.skin.draw(screen, x, y, g.bounds.W, g.bounds.H, false) {
+		return
+	}
 
-	// Draw goal indicator (green/blue gradient effect)
+	// Draw goal indicator (green/blue gradient effect, grey while locked)
 	goalColor := color.RGBA{0, 200, 255, 128}
-	ebitenutil.DrawRect(screen, x, y, g.bounds.W, g.bounds.H, goalColor)
+	if g.Locked() {
+		goalColor = color.RGBA{128, 128, 128, 128}
+	}
+	draw.FillRect(screen, x, y, g.bounds.W, g.bounds.H, goalColor)
 
 	// Draw border
 	borderColor := color.RGBA{255, 255, 255, 255}
-	ebitenutil.DrawRect(screen, x, y, g.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y+g.bounds.H-2, g.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y, 2, g.bounds.H, borderColor)
-	ebitenutil.DrawRect(screen, x+g.bounds.W-2, y, 2, g.bounds.H, borderColor)
+	draw.FillRect(screen, x, y, g.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y+g.bounds.H-2, g.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y, 2, g.bounds.H, borderColor)
+	draw.FillRect(screen, x+g.bounds.W-2, y, 2, g.bounds.H, borderColor)
 }
 
 // Bounds implements Entity.
@@ -78,14 +93,41 @@ func (g *Goal) Bounds() physics.AABB {
 	return g.bounds
 }
 
+// SetZone limits the goal to a polygon or ellipse within its bounds.
+func (g *Goal) SetZone(z Zone) {
+	z.fit(&g.bounds)
+	g.zone = z
+}
+
+// Overlaps implements ShapedTrigger.
+func (g *Goal) Overlaps(aabb physics.AABB) bool {
+	return g.zone.Overlaps(g.bounds, aabb)
+}
+
 // OnEnter implements Trigger.
 func (g *Goal) OnEnter(player *physics.Body) {
-	if g.state.Active && g.OnComplete != nil {
+	if !g.state.Active {
+		return
+	}
+	if g.Requirement != nil {
+		if reason := g.Requirement(); reason != "" {
+			if g.OnBlocked != nil {
+				g.OnBlocked(reason)
+			}
+			return
+		}
+	}
+	if g.OnComplete != nil {
 		g.OnComplete()
 		g.state.Active = false // Deactivate after triggering
 	}
 }
 
+// Locked returns true while the goal's requirements aren't met.
+func (g *Goal) Locked() bool {
+	return g.Requirement != nil && g.Requirement() != ""
+}
+
 // OnExit implements Trigger.
 func (g *Goal) OnExit(player *physics.Body) {
 	// Nothing to do on exit
@@ -110,3 +152,8 @@ func (g *Goal) SetTriggered(triggered bool) {
 func (g *Goal) SetActive(active bool) {
 	g.state.SetActive(active)
 }
+
+// SetSkin implements Skinnable.
+func (g *Goal) SetSkin(skin *Skin) {
+	g.skin = skin
+}
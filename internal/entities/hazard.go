@@ -4,18 +4,64 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/world"
 )
 
-// Hazard kills the player on touch.
+// HazardDirection is the side a hazard hurts from. Spikes point that way.
+type HazardDirection string
+
+const (
+	// HazardAll hurts from every side (the default).
+	HazardAll HazardDirection = ""
+	// HazardUp hurts from above, like spikes on the floor.
+	HazardUp HazardDirection = "up"
+	// HazardDown hurts from below, like spikes on the ceiling.
+	HazardDown HazardDirection = "down"
+	// HazardLeft hurts from the left.
+	HazardLeft HazardDirection = "left"
+	// HazardRight hurts from the right.
+	HazardRight HazardDirection = "right"
+)
+
+// ParseHazardDirection parses a hazard direction, defaulting to HazardAll.
+func ParseHazardDirection(s string) HazardDirection {
+	switch HazardDirection(s) {
+	case HazardUp, HazardDown, HazardLeft, HazardRight:
+		return HazardDirection(s)
+	}
+	return HazardAll
+}
+
+// Hazard hurts the player on touch.
+// Directional hazards (spikes) only hurt from the side they point to, a
+// hazard can ride along with a moving platform, and a polygon or ellipse
+// hazard only hurts inside its shape, such as along a slope.
 type Hazard struct {
-	bounds physics.AABB
-	state  TriggerState
+	bounds    physics.AABB
+	state     TriggerState
+	skin      *Skin
+	damage    int
+	direction HazardDirection
+	zone      Zone
+
+	// Platform the hazard rides on, and its offset from the platform
+	platform         *MovingPlatform
+	offsetX, offsetY float64
+
+	// Set when the last touch didn't hurt (harmless side, or the player was
+	// invulnerable), so the touch is checked again next frame instead of
+	// counting as entered
+	harmless bool
 
 	// Callback to trigger death
 	OnDeath func()
+
+	// Callback to deal damage from the hazard's bounds; replaces OnDeath when
+	// set. Returns whether the hit landed.
+	OnDamage func(amount int, source physics.AABB) bool
 }
 
 // NewHazard creates a new hazard at the given position.
@@ -23,12 +69,85 @@ func NewHazard(x, y, w, h float64) *Hazard {
 	return &Hazard{
 		bounds: physics.AABB{X: x, Y: y, W: w, H: h},
 		state:  NewTriggerState(),
+		damage: 1,
 	}
 }
 
+// SetDamage sets the damage dealt per touch.
+func (h *Hazard) SetDamage(amount int) {
+	h.damage = amount
+}
+
+// Damage returns the damage dealt per touch.
+func (h *Hazard) Damage() int {
+	return h.damage
+}
+
+// SetDirection sets the side the hazard hurts from.
+func (h *Hazard) SetDirection(d HazardDirection) {
+	h.direction = d
+}
+
+// Direction returns the side the hazard hurts from.
+func (h *Hazard) Direction() HazardDirection {
+	return h.direction
+}
+
+// SetZone limits the hazard to a polygon or ellipse, growing its bounds to
+// fit a polygon. Shaped hazards are drawn as their shape, without skin or
+// spikes.
+func (h *Hazard) SetZone(z Zone) {
+	z.fit(&h.bounds)
+	h.zone = z
+}
+
+// SetPolygon limits the hazard to a polygon, its points relative to the
+// hazard's position (see SetZone).
+func (h *Hazard) SetPolygon(points []mathx.Vec2) {
+	h.SetZone(Zone{Polygon: points})
+}
+
+// Polygon returns the hazard's polygon relative to its position, or nil.
+func (h *Hazard) Polygon() []mathx.Vec2 {
+	return h.zone.Polygon
+}
+
+// Overlaps implements ShapedTrigger.
+func (h *Hazard) Overlaps(aabb physics.AABB) bool {
+	return h.zone.Overlaps(h.bounds, aabb)
+}
+
+// drawZone fills the hazard's polygon or ellipse with its top left at x, y.
+func (h *Hazard) drawZone(screen *ebiten.Image, x, y float64) {
+	hazardColor := color.RGBA{255, 0, 0, 128}
+	if h.zone.Ellipse && h.zone.Polygon == nil {
+		draw.FillEllipse(screen, x, y, h.bounds.W, h.bounds.H, hazardColor)
+		return
+	}
+	points := make([]mathx.Vec2, len(h.zone.Polygon))
+	for i, p := range h.zone.Polygon {
+		points[i] = p.Add(mathx.V(x, y))
+	}
+	draw.FillPolygon(screen, points, hazardColor)
+}
+
+// AttachTo makes the hazard follow a moving platform, keeping its current
+// offset from the platform.
+func (h *Hazard) AttachTo(p *MovingPlatform) {
+	pb := p.Bounds()
+	h.platform = p
+	h.offsetX = h.bounds.X - pb.X
+	h.offsetY = h.bounds.Y - pb.Y
+}
+
 // Update implements Entity.
+// Attached hazards follow their platform.
 func (h *Hazard) Update(dt float64) {
-	// Hazards don't need per-frame updates
+	if h.platform != nil {
+		pb := h.platform.Bounds()
+		h.bounds.X = pb.X + h.offsetX
+		h.bounds.Y = pb.Y + h.offsetY
+	}
 }
 
 // Draw implements Entity.
@@ -38,18 +157,92 @@ func (h *Hazard) Draw(screen *ebiten.Image, camX, camY float64) {
 	if h.state.Active {
 		x := h.bounds.X - camX
 		y := h.bounds.Y - camY
+		if !h.zone.IsZero() {
+			h.drawZone(screen, x, y)
+			return
+		}
 		hazardColor := color.RGBA{255, 0, 0, 128}
-		ebitenutil.DrawRect(screen, x, y, h.bounds.W, h.bounds.H, hazardColor)
+		draw.FillRect(screen, x, y, h.bounds.W, h.bounds.H, hazardColor)
 	}
 }
 
 // DrawWithContext implements Entity.
+// Attached hazards are drawn at their platform's interpolated position, so
+// they don't lag behind it.
 func (h *Hazard) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
 	// Draw hazard indicator (red semi-transparent)
 	if h.state.Active {
-		x, y := ctx.WorldToScreen(h.bounds.X, h.bounds.Y)
+		wx, wy := h.bounds.X, h.bounds.Y
+		if h.platform != nil {
+			px, py := h.platform.body.Interpolate(ctx.Alpha)
+			wx, wy = px+h.offsetX, py+h.offsetY
+		}
+		x, y := ctx.WorldToScreen(wx, wy)
+		if !h.zone.IsZero() {
+			h.drawZone(screen, x, y)
+			return
+		}
+		if h.skin.draw(screen, x, y, h.bounds.W, h.bounds.H, false) {
+			return
+		}
+		if h.direction != HazardAll {
+			h.drawSpikes(screen, x, y)
+			return
+		}
 		hazardColor := color.RGBA{255, 0, 0, 128}
-		ebitenutil.DrawRect(screen, x, y, h.bounds.W, h.bounds.H, hazardColor)
+		draw.FillRect(screen, x, y, h.bounds.W, h.bounds.H, hazardColor)
+	}
+}
+
+// spikeSize is the width of one spike along the hazard's base.
+const spikeSize = 8.0
+
+// drawSpikes draws a row of spikes pointing in the hazard's direction.
+func (h *Hazard) drawSpikes(screen *ebiten.Image, x, y float64) {
+	spikeColor := color.RGBA{220, 220, 230, 255}
+	baseColor := color.RGBA{120, 30, 30, 255}
+	w, hh := h.bounds.W, h.bounds.H
+
+	// The base runs along the side opposite the direction
+	vertical := h.direction == HazardUp || h.direction == HazardDown
+	length := w
+	if !vertical {
+		length = hh
+	}
+	n := int(length / spikeSize)
+	if n < 1 {
+		n = 1
+	}
+	step := length / float64(n)
+
+	for i := 0; i < n; i++ {
+		a := float64(i) * step
+		mid := a + step/2
+		b := a + step
+		var bx1, by1, bx2, by2, tx, ty float64
+		switch h.direction {
+		case HazardUp:
+			bx1, by1, bx2, by2, tx, ty = x+a, y+hh, x+b, y+hh, x+mid, y
+		case HazardDown:
+			bx1, by1, bx2, by2, tx, ty = x+a, y, x+b, y, x+mid, y+hh
+		case HazardLeft:
+			bx1, by1, bx2, by2, tx, ty = x+w, y+a, x+w, y+b, x, y+mid
+		case HazardRight:
+			bx1, by1, bx2, by2, tx, ty = x, y+a, x, y+b, x+w, y+mid
+		}
+		draw.Line(screen, bx1, by1, tx, ty, 1, spikeColor)
+		draw.Line(screen, bx2, by2, tx, ty, 1, spikeColor)
+	}
+
+	switch h.direction {
+	case HazardUp:
+		draw.FillRect(screen, x, y+hh-2, w, 2, baseColor)
+	case HazardDown:
+		draw.FillRect(screen, x, y, w, 2, baseColor)
+	case HazardLeft:
+		draw.FillRect(screen, x+w-2, y, 2, hh, baseColor)
+	case HazardRight:
+		draw.FillRect(screen, x, y, 2, hh, baseColor)
 	}
 }
 
@@ -60,14 +253,50 @@ func (h *Hazard) Bounds() physics.AABB {
 
 // OnEnter implements Trigger.
 func (h *Hazard) OnEnter(player *physics.Body) {
+	h.harmless = !h.HurtsFrom(player)
+	if h.harmless {
+		return
+	}
+	if h.OnDamage != nil {
+		h.harmless = !h.OnDamage(h.damage, h.bounds)
+		return
+	}
 	if h.OnDeath != nil {
 		h.OnDeath()
 	}
 }
 
+// HurtsFrom returns whether touching the player hurts them. Directional
+// hazards only hurt when the player touches the pointed side, judged by the
+// side with the shallowest overlap, while moving towards it.
+func (h *Hazard) HurtsFrom(player *physics.Body) bool {
+	if h.direction == HazardAll {
+		return true
+	}
+
+	p := player.AABB()
+	fromTop := p.Y + p.H - h.bounds.Y
+	fromBottom := h.bounds.Y + h.bounds.H - p.Y
+	fromLeft := p.X + p.W - h.bounds.X
+	fromRight := h.bounds.X + h.bounds.W - p.X
+	shallowest := min(fromTop, fromBottom, fromLeft, fromRight)
+
+	switch h.direction {
+	case HazardUp:
+		return fromTop == shallowest && player.VelY >= 0
+	case HazardDown:
+		return fromBottom == shallowest && player.VelY <= 0
+	case HazardLeft:
+		return fromLeft == shallowest && player.VelX >= 0
+	case HazardRight:
+		return fromRight == shallowest && player.VelX <= 0
+	}
+	return true
+}
+
 // OnExit implements Trigger.
 func (h *Hazard) OnExit(player *physics.Body) {
-	// Nothing to do on exit
+	h.harmless = false
 }
 
 // IsActive implements Trigger.
@@ -81,7 +310,13 @@ func (h *Hazard) WasTriggered() bool {
 }
 
 // SetTriggered implements Trigger.
+// A harmless touch isn't recorded, so a player who slides from the side onto
+// the pointed side, or stays in the hazard past their invulnerability, still
+// gets hurt.
 func (h *Hazard) SetTriggered(triggered bool) {
+	if triggered && h.harmless {
+		return
+	}
 	h.state.SetTriggered(triggered)
 }
 
@@ -89,3 +324,8 @@ func (h *Hazard) SetTriggered(triggered bool) {
 func (h *Hazard) SetActive(active bool) {
 	h.state.SetActive(active)
 }
+
+// SetSkin implements Skinnable.
+func (h *Hazard) SetSkin(skin *Skin) {
+	h.skin = skin
+}
@@ -0,0 +1,96 @@
+//go:build display
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/physics"
+)
+
+func TestHazardSpikesHurtFromPointedSide(t *testing.T) {
+	// Floor spikes at y=100..108
+	spikes := NewHazard(0, 100, 32, 8)
+	spikes.SetDirection(HazardUp)
+
+	tests := []struct {
+		name   string
+		player physics.Body
+		want   bool
+	}{
+		{"falling onto", physics.Body{PosX: 8, PosY: 90, W: 12, H: 12, VelY: 120}, true},
+		{"walking into side", physics.Body{PosX: -10, PosY: 98, W: 12, H: 12, VelX: 80}, false},
+		{"jumping up from below", physics.Body{PosX: 8, PosY: 106, W: 12, H: 12, VelY: -200}, false},
+	}
+	for _, tt := range tests {
+		if got := spikes.HurtsFrom(&tt.player); got != tt.want {
+			t.Errorf("%s: HurtsFrom = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHazardHarmlessTouchIsCheckedAgain(t *testing.T) {
+	spikes := NewHazard(0, 100, 32, 8)
+	spikes.SetDirection(HazardUp)
+	hits := 0
+	spikes.OnDamage = func(amount int, source physics.AABB) bool {
+		hits += amount
+		return true
+	}
+	spikes.SetDamage(2)
+
+	player := &physics.Body{PosX: -10, PosY: 98, W: 12, H: 12, VelX: 80}
+	spikes.OnEnter(player)
+	spikes.SetTriggered(true)
+	if hits != 0 || spikes.WasTriggered() {
+		t.Fatalf("side touch: hits %d, triggered %v; want 0, false", hits, spikes.WasTriggered())
+	}
+
+	// Now on top of the spikes
+	player.PosX, player.PosY, player.VelX, player.VelY = 8, 90, 0, 120
+	spikes.OnEnter(player)
+	spikes.SetTriggered(true)
+	if hits != 2 || !spikes.WasTriggered() {
+		t.Fatalf("top touch: hits %d, triggered %v; want 2, true", hits, spikes.WasTriggered())
+	}
+}
+
+func TestHazardFollowsPlatform(t *testing.T) {
+	platform := NewMovingPlatform("lift", 0, 100, 32, 8, 100, 100, 60)
+	saw := NewHazard(8, 92, 16, 8)
+	saw.AttachTo(platform)
+
+	platform.MoveAndSlide(nil, 0.5)
+	saw.Update(0.5)
+
+	pb, hb := platform.Bounds(), saw.Bounds()
+	if hb.X-pb.X != 8 || hb.Y-pb.Y != -8 {
+		t.Fatalf("hazard offset from platform = (%v, %v), want (8, -8)", hb.X-pb.X, hb.Y-pb.Y)
+	}
+	if hb.X == 8 {
+		t.Fatal("hazard didn't move with the platform")
+	}
+}
+
+func TestPolygonHazardOnlyHurtsInsideShape(t *testing.T) {
+	// Lava filling the space under a slope rising to the right
+	lava := NewHazard(0, 100, 0, 0)
+	lava.SetPolygon([]mathx.Vec2{mathx.V(0, 32), mathx.V(64, 0), mathx.V(64, 32)})
+	if b := lava.Bounds(); b.W != 64 || b.H != 32 {
+		t.Fatalf("bounds %vx%v, want 64x32", b.W, b.H)
+	}
+	deaths := 0
+	lava.OnDeath = func() { deaths++ }
+
+	w := NewEntityWorld()
+	w.AddTrigger(lava)
+	above := &physics.Body{PosX: 4, PosY: 100, W: 12, H: 12} // In the bounds, above the slope
+	if w.CheckTriggers(above); deaths != 0 {
+		t.Fatal("player above the slope was hurt")
+	}
+	inside := &physics.Body{PosX: 48, PosY: 116, W: 12, H: 12}
+	if w.CheckTriggers(inside); deaths != 1 {
+		t.Fatalf("player in the lava: %d deaths, want 1", deaths)
+	}
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// Heart colors
+var (
+	heartColor      = color.RGBA{230, 40, 60, 255}
+	heartEmptyColor = color.RGBA{70, 30, 40, 200}
+)
+
+// heartSize is the width and height of one HUD heart.
+const heartSize = 10.0
+
+// DrawHearts draws the HUD health bar: maxHP hearts from x to the right, the
+// first hp of them filled.
+func DrawHearts(screen *ebiten.Image, x, y float64, hp, maxHP int) {
+	for i := 0; i < maxHP; i++ {
+		col := heartColor
+		if i >= hp {
+			col = heartEmptyColor
+		}
+		drawHeart(screen, x+float64(i)*(heartSize+3), y, heartSize, col)
+	}
+}
+
+// HeartsWidth returns the width DrawHearts uses for maxHP hearts.
+func HeartsWidth(maxHP int) float64 {
+	if maxHP <= 0 {
+		return 0
+	}
+	return float64(maxHP)*(heartSize+3) - 3
+}
+
+// drawHeart draws a blocky heart: two lobes over a tapering point.
+func drawHeart(screen *ebiten.Image, x, y, s float64, col color.Color) {
+	u := s / 5
+	draw.FillRect(screen, x, y+u, 2*u, u, col)
+	draw.FillRect(screen, x+3*u, y+u, 2*u, u, col)
+	draw.FillRect(screen, x+u/2, y+u/2, u, u/2, col)
+	draw.FillRect(screen, x+3.5*u, y+u/2, u, u/2, col)
+	draw.FillRect(screen, x, y+2*u, s, u, col)
+	draw.FillRect(screen, x+u/2, y+3*u, s-u, u, col)
+	draw.FillRect(screen, x+1.5*u, y+4*u, 2*u, u, col)
+}
@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Inspector panel layout
+const (
+	inspectorWidth      = 220
+	inspectorLineHeight = 14
+	inspectorMargin     = 10
+)
+
+var (
+	inspectorBackground = color.RGBA{0, 0, 0, 190}
+	inspectorHighlight  = color.RGBA{255, 220, 0, 255}
+)
+
+// Inspector shows the details of an entity picked with the mouse: its type,
+// id, bounds, velocity, active state and the custom properties of the object
+// it was spawned from (see EntityWorld.SetSource).
+type Inspector struct {
+	world    *EntityWorld
+	selected Entity
+}
+
+// NewInspector creates an inspector with nothing selected.
+func NewInspector() *Inspector {
+	return &Inspector{}
+}
+
+// Pick selects the topmost entity of w at world position x, y. Picking empty
+// space clears the selection. It returns whether an entity was selected.
+func (in *Inspector) Pick(w *EntityWorld, x, y float64) bool {
+	in.world = w
+	in.selected = nil
+	ents := w.Entities()
+	// Entities are drawn in order, so the last one is on top
+	for i := len(ents) - 1; i >= 0; i-- {
+		b := ents[i].Bounds()
+		if x >= b.X && x < b.X+b.W && y >= b.Y && y < b.Y+b.H {
+			in.selected = ents[i]
+			return true
+		}
+	}
+	return false
+}
+
+// Selected returns the selected entity, or nil.
+func (in *Inspector) Selected() Entity {
+	return in.selected
+}
+
+// Clear drops the selection, e.g. when the entities are respawned.
+func (in *Inspector) Clear() {
+	in.world = nil
+	in.selected = nil
+}
+
+// ToggleActive flips the selected entity's active state. Entities with
+// SetActive are enabled or disabled; targets without it (doors, lights) are
+// toggled as if by a switch. It returns false if the entity supports neither.
+func (in *Inspector) ToggleActive() bool {
+	switch e := in.selected.(type) {
+	case interface {
+		IsActive() bool
+		SetActive(bool)
+	}:
+		e.SetActive(!e.IsActive())
+	case Targetable:
+		e.Toggle()
+	default:
+		return false
+	}
+	return true
+}
+
+// Lines returns the panel text for the selected entity, or nil.
+func (in *Inspector) Lines() []string {
+	e := in.selected
+	if e == nil {
+		return nil
+	}
+
+	var src world.ObjectData
+	var hasSrc bool
+	if in.world != nil {
+		src, hasSrc = in.world.Source(e)
+	}
+
+	typeName := strings.TrimPrefix(fmt.Sprintf("%T", e), "*entities.")
+	if hasSrc && src.Type != "" {
+		typeName = string(src.Type)
+	}
+	header := typeName
+	if id := entityID(e); id != "" {
+		header += fmt.Sprintf(" %q", id)
+	} else if hasSrc && src.Name != "" {
+		header += fmt.Sprintf(" %q", src.Name)
+	}
+
+	b := e.Bounds()
+	lines := []string{
+		header,
+		fmt.Sprintf("bounds: %.1f, %.1f  %.0fx%.0f", b.X, b.Y, b.W, b.H),
+	}
+	if vx, vy, ok := entityVelocity(e); ok {
+		lines = append(lines, fmt.Sprintf("velocity: %.1f, %.1f", vx, vy))
+	}
+	if active, ok := entityActive(e); ok {
+		lines = append(lines, fmt.Sprintf("active: %v", active))
+	}
+	if s, ok := e.(interface{ TargetState() string }); ok {
+		lines = append(lines, "state: "+s.TargetState())
+	}
+
+	if hasSrc && len(src.Props) > 0 {
+		keys := make([]string, 0, len(src.Props))
+		for k := range src.Props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		lines = append(lines, "properties:")
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("  %s = %v", k, src.Props[k]))
+		}
+	}
+	return lines
+}
+
+// Draw outlines the selected entity and draws the panel in the top-right
+// corner of the screen, with hint as its last line.
+func (in *Inspector) Draw(screen *ebiten.Image, ctx *world.RenderContext, hint string) {
+	lines := in.Lines()
+	if lines == nil {
+		return
+	}
+
+	b := in.selected.Bounds()
+	x, y := ctx.WorldToScreen(b.X, b.Y)
+	draw.StrokeRect(screen, x-1, y-1, b.W+2, b.H+2, 1, inspectorHighlight)
+
+	if hint != "" {
+		lines = append(lines, "", hint)
+	}
+	panelX := screen.Bounds().Dx() - inspectorWidth - inspectorMargin
+	panelY := 30
+	height := len(lines)*inspectorLineHeight + 10
+	draw.FillRect(screen, float64(panelX), float64(panelY), inspectorWidth, float64(height), inspectorBackground)
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, panelX+5, panelY+5+i*inspectorLineHeight)
+	}
+}
+
+// entityVelocity returns the velocity of moving entities and bodies.
+func entityVelocity(e Entity) (vx, vy float64, ok bool) {
+	switch e := e.(type) {
+	case interface{ Velocity() (float64, float64) }:
+		vx, vy = e.Velocity()
+		return vx, vy, true
+	case SolidEntity:
+		if body := e.GetBody(); body != nil {
+			return body.VelX, body.VelY, true
+		}
+	}
+	return 0, 0, false
+}
+
+// entityActive returns whether the entity is active, or a light is on.
+func entityActive(e Entity) (active, ok bool) {
+	switch e := e.(type) {
+	case interface{ IsActive() bool }:
+		return e.IsActive(), true
+	case interface{ IsOn() bool }:
+		return e.IsOn(), true
+	}
+	return false, false
+}
@@ -0,0 +1,66 @@
+//go:build display
+
+package entities
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestInspectorPick(t *testing.T) {
+	w := NewEntityWorld()
+	hazard := NewHazard(0, 0, 64, 64)
+	door := NewDoor(16, 0, 16, 48, "door_1")
+	w.AddTrigger(hazard)
+	w.AddSolidEntity(door)
+	w.SetSource(door, world.ObjectData{Type: world.ObjectTypeDoor, Props: map[string]any{"openTime": 0.5, "id": "door_1"}})
+
+	in := NewInspector()
+	if !in.Pick(w, 20, 10) || in.Selected() != door {
+		t.Fatalf("picked %v where the door overlaps the hazard, want the door drawn on top", in.Selected())
+	}
+	want := []string{
+		`door "door_1"`,
+		"bounds: 16.0, 0.0  16x48",
+		"velocity: 0.0, 0.0",
+		"active: true",
+		"state: closed",
+		"properties:",
+		"  id = door_1",
+		"  openTime = 0.5",
+	}
+	if got := in.Lines(); !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+
+	if in.Pick(w, 100, 100) || in.Lines() != nil {
+		t.Errorf("picking empty space kept %v selected", in.Selected())
+	}
+}
+
+func TestInspectorToggleActive(t *testing.T) {
+	w := NewEntityWorld()
+	hazard := NewHazard(0, 0, 16, 16)
+	door := NewDoor(32, 0, 16, 16, "door_1")
+	w.AddTrigger(hazard)
+	w.AddSolidEntity(door)
+
+	in := NewInspector()
+	in.Pick(w, 8, 8)
+	if !in.ToggleActive() || hazard.IsActive() {
+		t.Errorf("hazard still active after toggling")
+	}
+
+	// Doors have no SetActive, so they open like a switch would open them
+	in.Pick(w, 40, 8)
+	if !in.ToggleActive() || door.TargetState() != string(DoorOpen) {
+		t.Errorf("door is %s after toggling, want open", door.TargetState())
+	}
+
+	in.Clear()
+	if in.ToggleActive() {
+		t.Error("ToggleActive succeeded with nothing selected")
+	}
+}
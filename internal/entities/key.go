@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Key colors
+var (
+	keyColor       = color.RGBA{255, 200, 40, 255}
+	keyShadowColor = color.RGBA{150, 100, 10, 255}
+)
+
+// Key is picked up when touched and opens locked doors.
+// An ID lets a door require this particular key.
+type Key struct {
+	bounds physics.AABB
+	id     string
+	state  TriggerState
+	zone   Zone
+	skin   *Skin
+
+	// Callback when the key is picked up
+	OnCollect func(id string)
+}
+
+// NewKey creates a new key at the given position.
+func NewKey(x, y, w, h float64, id string) *Key {
+	return &Key{
+		bounds: physics.AABB{X: x, Y: y, W: w, H: h},
+		id:     id,
+		state:  NewTriggerState(),
+	}
+}
+
+// Update implements Entity.
+func (k *Key) Update(dt float64) {
+	// Keys don't need per-frame updates
+}
+
+// Draw implements Entity.
+// Deprecated: Use DrawWithContext for new implementations.
+func (k *Key) Draw(screen *ebiten.Image, camX, camY float64) {
+	if !k.state.Active {
+		return
+	}
+	drawKeyIcon(screen, k.bounds.X-camX, k.bounds.Y-camY, k.bounds.W, k.bounds.H)
+}
+
+// DrawWithContext implements Entity.
+func (k *Key) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
+	if !k.state.Active {
+		return
+	}
+
+	x, y := ctx.WorldToScreen(k.bounds.X, k.bounds.Y)
+	if k.skin.draw(screen, x, y, k.bounds.W, k.bounds.H, false) {
+		return
+	}
+	drawKeyIcon(screen, x, y, k.bounds.W, k.bounds.H)
+}
+
+// drawKeyIcon draws a key shape filling the given box: a ring on the left
+// and a toothed shaft to the right.
+func drawKeyIcon(screen *ebiten.Image, x, y, w, h float64) {
+	ring := h * 0.6
+	ry := y + (h-ring)/2
+	draw.FillRect(screen, x, ry, ring, ring, keyColor)
+	draw.FillRect(screen, x+ring/3, ry+ring/3, ring/3, ring/3, keyShadowColor)
+
+	shaftY := y + h/2 - h/10
+	draw.FillRect(screen, x+ring, shaftY, w-ring, h/5, keyColor)
+	draw.FillRect(screen, x+w-w/5, shaftY, w/10, h/4+h/5, keyColor)
+	draw.FillRect(screen, x+w-w/2.5, shaftY, w/10, h/5+h/6, keyColor)
+}
+
+// DrawKeyCount draws the HUD key counter: a key icon followed by the count.
+func DrawKeyCount(screen *ebiten.Image, x, y float64, count int) {
+	drawKeyIcon(screen, x, y+2, 14, 10)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("x%d", count), int(x)+17, int(y))
+}
+
+// Bounds implements Entity.
+func (k *Key) Bounds() physics.AABB {
+	return k.bounds
+}
+
+// SetZone limits the key to a polygon or ellipse within its bounds.
+func (k *Key) SetZone(z Zone) {
+	z.fit(&k.bounds)
+	k.zone = z
+}
+
+// Overlaps implements ShapedTrigger.
+func (k *Key) Overlaps(aabb physics.AABB) bool {
+	return k.zone.Overlaps(k.bounds, aabb)
+}
+
+// OnEnter implements Trigger.
+func (k *Key) OnEnter(player *physics.Body) {
+	if !k.state.Active {
+		return
+	}
+	k.state.Active = false // Picked up once
+	if k.OnCollect != nil {
+		k.OnCollect(k.id)
+	}
+}
+
+// OnExit implements Trigger.
+func (k *Key) OnExit(player *physics.Body) {
+	// Nothing to do on exit
+}
+
+// IsActive implements Trigger.
+func (k *Key) IsActive() bool {
+	return k.state.IsActive()
+}
+
+// SetActive sets whether the key can still be picked up.
+func (k *Key) SetActive(active bool) {
+	k.state.SetActive(active)
+}
+
+// WasTriggered implements Trigger.
+func (k *Key) WasTriggered() bool {
+	return k.state.WasTriggered()
+}
+
+// SetTriggered implements Trigger.
+func (k *Key) SetTriggered(triggered bool) {
+	k.state.SetTriggered(triggered)
+}
+
+// SetSkin implements Skinnable.
+func (k *Key) SetSkin(skin *Skin) {
+	k.skin = skin
+}
+
+// ID returns the key's ID.
+func (k *Key) ID() string {
+	return k.id
+}
+
+// SaveState implements Snapshotter. The state is whether the key is still
+// there to pick up.
+func (k *Key) SaveState() any {
+	return k.state.Active
+}
+
+// RestoreState implements Snapshotter.
+func (k *Key) RestoreState(state any) {
+	if active, ok := state.(bool); ok {
+		k.state.Active = active
+	}
+}
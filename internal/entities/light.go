@@ -0,0 +1,135 @@
+package entities
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Light is a point light placed in the level.
+// It has no collision and isn't drawn by DrawWithContext; the scene's
+// gfx.LightLayer renders it. Lights are Targetable, so switches and
+// rules can turn them on and off.
+type Light struct {
+	id      string
+	x, y    float64 // Center position
+	radius  float64
+	color   color.RGBA
+	flicker float64 // Flicker amount in 0-1 range
+	on      bool
+	time    float64 // Elapsed time, drives flicker
+}
+
+// NewLight creates a light centered at the given position.
+// Lights start switched on with no flicker.
+func NewLight(id string, x, y, radius float64, col color.RGBA) *Light {
+	return &Light{
+		id:     id,
+		x:      x,
+		y:      y,
+		radius: radius,
+		color:  col,
+		on:     true,
+	}
+}
+
+// SetFlicker sets the flicker amount, clamped to the 0-1 range.
+// 0 is a steady light, 1 flickers down to fully dark.
+func (l *Light) SetFlicker(amount float64) {
+	l.flicker = math.Max(0, math.Min(amount, 1))
+}
+
+// SetOn switches the light on or off.
+func (l *Light) SetOn(on bool) {
+	l.on = on
+}
+
+// IsOn returns whether the light is switched on.
+func (l *Light) IsOn() bool {
+	return l.on
+}
+
+// Intensity returns the current brightness in 0-1 range, including flicker.
+func (l *Light) Intensity() float64 {
+	if !l.on {
+		return 0
+	}
+	if l.flicker == 0 {
+		return 1
+	}
+
+	// Sum of unrelated sines reads as irregular flicker; the position
+	// offsets the phase so neighbouring lights don't flicker in sync.
+	t := l.time + (l.x+l.y)*0.01
+	n := (math.Sin(t*13) + math.Sin(t*7.3+1.7) + math.Sin(t*23.1+0.5)) / 3
+	return 1 - l.flicker*(0.5+0.5*n)
+}
+
+// PointLight returns the light in screen coordinates for the light layer.
+func (l *Light) PointLight(ctx *world.RenderContext) gfx.PointLight {
+	x, y := ctx.WorldToScreen(l.x, l.y)
+	return gfx.PointLight{
+		X:         x,
+		Y:         y,
+		Radius:    l.radius,
+		Color:     l.color,
+		Intensity: l.Intensity(),
+	}
+}
+
+// Update implements Entity.
+func (l *Light) Update(dt float64) {
+	l.time += dt
+}
+
+// Draw implements Entity.
+// Deprecated: Use DrawWithContext for new implementations.
+func (l *Light) Draw(screen *ebiten.Image, camX, camY float64) {
+	// Lights are rendered by the light layer
+}
+
+// DrawWithContext implements Entity.
+func (l *Light) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
+	// Lights are rendered by the light layer
+}
+
+// Bounds implements Entity.
+func (l *Light) Bounds() physics.AABB {
+	return physics.AABB{X: l.x - l.radius, Y: l.y - l.radius, W: l.radius * 2, H: l.radius * 2}
+}
+
+// Activate implements Targetable.
+func (l *Light) Activate() {
+	l.on = true
+}
+
+// Deactivate implements Targetable.
+func (l *Light) Deactivate() {
+	l.on = false
+}
+
+// Toggle implements Targetable.
+func (l *Light) Toggle() {
+	l.on = !l.on
+}
+
+// TargetID implements Targetable.
+func (l *Light) TargetID() string {
+	return l.id
+}
+
+// SaveState implements Snapshotter. The state is whether the light is on.
+func (l *Light) SaveState() any {
+	return l.on
+}
+
+// RestoreState implements Snapshotter.
+func (l *Light) RestoreState(state any) {
+	if on, ok := state.(bool); ok {
+		l.on = on
+	}
+}
@@ -3,34 +3,44 @@ package entities
 import (
 	"fmt"
 	"image/color"
-	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/tween"
 	"github.com/torsten/GoP/internal/world"
 )
 
-// MovingPlatform is a solid entity that moves between two points (A and B).
+// MovingPlatform is a solid entity that moves between two points (A and B),
+// or along a path of more (see SetPath).
 // It implements the physics.Kinematic interface for integration with the physics system.
 type MovingPlatform struct {
 	id     string
 	body   physics.Body
 	active bool
 
-	// Path definition
-	startX, startY float64 // Initial position (point A)
-	endX, endY     float64 // Target position (point B)
+	// Path definition: positions visited in turn, from the initial position
+	// (point A) to the target (point B) for two
+	path []mathx.Vec2
+	loop bool // Around from the last point to the first, instead of back
 
 	// Movement state
 	velocityX, velocityY float64
 	speed                float64
-	goingToEnd           bool    // true = A→B, false = B→A
+	next                 int     // Index of the path point heading for
+	step                 int     // 1 along the path, -1 back
 	waitTimer            float64 // Time remaining before moving
 	waitTime             float64 // Time to wait at endpoints
+	ease                 tween.Ease
+	leg                  *tween.Tween[mathx.Vec2] // Current trip toward an endpoint
 
 	// Options
 	pushPlayer bool // Whether to push player sideways
+	stopped    bool // Halted by a switch or rule; still solid
+
+	// Appearance override from the level theme
+	skin *Skin
 }
 
 // NewMovingPlatform creates a new moving platform.
@@ -48,17 +58,30 @@ func NewMovingPlatform(id string, x, y, w, h, endX, endY, speed float64) *Moving
 			W:    w,
 			H:    h,
 		},
-		startX:     x,
-		startY:     y,
-		endX:       endX,
-		endY:       endY,
+		path:       []mathx.Vec2{{X: x, Y: y}, {X: endX, Y: endY}},
 		speed:      speed,
-		goingToEnd: true,
+		next:       1,
+		step:       1,
 		waitTime:   0.5, // Default wait time at endpoints
 		pushPlayer: false,
 	}
 }
 
+// SetPath makes the platform follow points (positions of its top left)
+// instead of moving between A and B: to the first point from wherever it
+// is, then along them and back, or around when loop is set. It waits at the
+// path's ends, or only at the first point of a loop. Paths of fewer than
+// two points are ignored.
+func (p *MovingPlatform) SetPath(points []mathx.Vec2, loop bool) {
+	if len(points) < 2 {
+		return
+	}
+	p.path = points
+	p.loop = loop
+	p.next, p.step = 0, 1
+	p.leg = nil
+}
+
 // GetBody returns a pointer to the platform's physics body.
 // Implements physics.SolidEntity interface.
 func (p *MovingPlatform) GetBody() *physics.Body {
@@ -66,12 +89,17 @@ func (p *MovingPlatform) GetBody() *physics.Body {
 }
 
 // IsActive returns whether the platform is active.
-// Platforms are always active for now.
 // Implements physics.SolidEntity interface.
 func (p *MovingPlatform) IsActive() bool {
 	return p.active
 }
 
+// SetActive sets whether the platform is active. An inactive platform
+// neither moves nor collides.
+func (p *MovingPlatform) SetActive(active bool) {
+	p.active = active
+}
+
 // Velocity returns the current velocity in pixels per second.
 // Implements physics.Kinematic interface.
 func (p *MovingPlatform) Velocity() (vx, vy float64) {
@@ -81,6 +109,13 @@ func (p *MovingPlatform) Velocity() (vx, vy float64) {
 // MoveAndSlide moves the platform and returns the actual displacement.
 // Implements physics.Kinematic interface.
 func (p *MovingPlatform) MoveAndSlide(collisionMap *world.CollisionMap, dt float64) (dx, dy float64) {
+	// A stopped platform holds its position
+	if p.stopped {
+		p.velocityX = 0
+		p.velocityY = 0
+		return 0, 0
+	}
+
 	// Step 1: If waiting at endpoint, decrement timer and return no movement
 	if p.waitTimer > 0 {
 		p.waitTimer -= dt
@@ -89,60 +124,55 @@ func (p *MovingPlatform) MoveAndSlide(collisionMap *world.CollisionMap, dt float
 		return 0, 0
 	}
 
-	// Step 2: Calculate direction toward target
-	targetX, targetY := p.endX, p.endY
-	if !p.goingToEnd {
-		targetX, targetY = p.startX, p.startY
+	// Step 2: Pick the path point to head for
+	target := p.path[p.next]
+	pos := mathx.V(p.body.PosX, p.body.PosY)
+
+	// Step 3: Start a trip to the target, taking as long as the distance
+	// needs at the platform's speed (or picking up a moved platform from
+	// where it is)
+	if p.leg == nil || p.leg.To != target || p.leg.Value() != pos {
+		dist := target.Sub(pos).Len()
+		if dist < 0.001 {
+			// Already at target, head for the next point
+			p.arrive()
+			return 0, 0
+		}
+		p.leg = tween.New(pos, target, dist/p.speed, p.ease)
 	}
 
-	// Direction vector from current position to target
-	dirX := targetX - p.body.PosX
-	dirY := targetY - p.body.PosY
-	dist := math.Sqrt(dirX*dirX + dirY*dirY)
-
-	// Avoid division by zero
-	if dist < 0.001 {
-		// Already at target, switch direction
-		p.switchDirection()
-		return 0, 0
+	// Step 4: Move along the trip (no tile collision for platforms in v1)
+	p.leg.Update(dt)
+	next := p.leg.Value()
+	dx, dy = next.X-pos.X, next.Y-pos.Y
+	p.body.PosX, p.body.PosY = next.X, next.Y
+	if dt > 0 {
+		p.velocityX, p.velocityY = dx/dt, dy/dt
 	}
 
-	// Normalize direction
-	dirX /= dist
-	dirY /= dist
-
-	// Step 3: Calculate velocity = direction * speed
-	p.velocityX = dirX * p.speed
-	p.velocityY = dirY * p.speed
-
-	// Step 4: Calculate potential movement
-	potentialDx := p.velocityX * dt
-	potentialDy := p.velocityY * dt
-
-	// Step 5: Check if we'd overshoot target
-	potentialDist := math.Sqrt(potentialDx*potentialDx + potentialDy*potentialDy)
-	if potentialDist >= dist {
-		// Snap to target and switch direction
-		dx = targetX - p.body.PosX
-		dy = targetY - p.body.PosY
-		p.body.PosX = targetX
-		p.body.PosY = targetY
-		p.switchDirection()
-		return dx, dy
+	// Step 5: Head for the next point, waiting first at an end
+	if p.leg.Done() {
+		p.arrive()
 	}
-
-	// Step 6: Apply movement (no tile collision for platforms in v1)
-	p.body.PosX += potentialDx
-	p.body.PosY += potentialDy
-
-	// Step 7: Return actual movement delta
-	return potentialDx, potentialDy
+	return dx, dy
 }
 
-// switchDirection reverses the platform's movement direction and starts wait timer.
-func (p *MovingPlatform) switchDirection() {
-	p.goingToEnd = !p.goingToEnd
-	p.waitTimer = p.waitTime
+// arrive moves on to the path point after the one reached, turning back at
+// the path's ends (or going around a loop), and starts the wait timer when
+// the point reached is an end.
+func (p *MovingPlatform) arrive() {
+	reached := p.next
+	if p.loop {
+		p.next = (reached + 1) % len(p.path)
+	} else {
+		if reached+p.step < 0 || reached+p.step >= len(p.path) {
+			p.step = -p.step
+		}
+		p.next = reached + p.step
+	}
+	if reached == 0 || !p.loop && reached == len(p.path)-1 {
+		p.waitTimer = p.waitTime
+	}
 	p.velocityX = 0
 	p.velocityY = 0
 }
@@ -161,35 +191,39 @@ func (p *MovingPlatform) Draw(screen *ebiten.Image, camX, camY float64) {
 
 	// Draw platform with a distinct purple/blue color
 	platformColor := color.RGBA{128, 64, 192, 255} // Purple
-	ebitenutil.DrawRect(screen, x, y, p.body.W, p.body.H, platformColor)
+	draw.FillRect(screen, x, y, p.body.W, p.body.H, platformColor)
 
 	// Draw border for visibility
 	borderColor := color.RGBA{80, 40, 140, 255}
-	ebitenutil.DrawRect(screen, x, y, p.body.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y+p.body.H-2, p.body.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y, 2, p.body.H, borderColor)
-	ebitenutil.DrawRect(screen, x+p.body.W-2, y, 2, p.body.H, borderColor)
+	draw.FillRect(screen, x, y, p.body.W, 2, borderColor)
+	draw.FillRect(screen, x, y+p.body.H-2, p.body.W, 2, borderColor)
+	draw.FillRect(screen, x, y, 2, p.body.H, borderColor)
+	draw.FillRect(screen, x+p.body.W-2, y, 2, p.body.H, borderColor)
 }
 
-// DrawWithContext renders the platform using a RenderContext.
+// DrawWithContext renders the platform using a RenderContext, between its
+// last two physics steps by ctx.Alpha.
 func (p *MovingPlatform) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
 	// Convert world coordinates to screen coordinates
-	x, y := ctx.WorldToScreen(p.body.PosX, p.body.PosY)
+	x, y := ctx.WorldToScreen(p.body.Interpolate(ctx.Alpha))
+	if p.skin.draw(screen, x, y, p.body.W, p.body.H, false) {
+		return
+	}
 
 	// Draw platform with a distinct purple/blue color
 	platformColor := color.RGBA{128, 64, 192, 255} // Purple
-	ebitenutil.DrawRect(screen, x, y, p.body.W, p.body.H, platformColor)
+	draw.FillRect(screen, x, y, p.body.W, p.body.H, platformColor)
 
 	// Draw border for visibility
 	borderColor := color.RGBA{80, 40, 140, 255}
-	ebitenutil.DrawRect(screen, x, y, p.body.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y+p.body.H-2, p.body.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y, 2, p.body.H, borderColor)
-	ebitenutil.DrawRect(screen, x+p.body.W-2, y, 2, p.body.H, borderColor)
+	draw.FillRect(screen, x, y, p.body.W, 2, borderColor)
+	draw.FillRect(screen, x, y+p.body.H-2, p.body.W, 2, borderColor)
+	draw.FillRect(screen, x, y, 2, p.body.H, borderColor)
+	draw.FillRect(screen, x+p.body.W-2, y, 2, p.body.H, borderColor)
 }
 
 // DrawDebug renders debug visualization for the platform.
-// Draws the platform path as a line from start to end point,
+// Draws the platform path as lines between its points,
 // and draws the platform bounds with a distinct debug color.
 func (p *MovingPlatform) DrawDebug(screen *ebiten.Image, ctx *world.RenderContext) {
 	// Debug colors
@@ -197,28 +231,31 @@ func (p *MovingPlatform) DrawDebug(screen *ebiten.Image, ctx *world.RenderContex
 	boundsColor := color.RGBA{0, 255, 255, 255} // Cyan for bounds
 
 	// Convert world coordinates to screen coordinates
-	startScreenX, startScreenY := ctx.WorldToScreen(p.startX, p.startY)
-	endScreenX, endScreenY := ctx.WorldToScreen(p.endX, p.endY)
 	platformScreenX, platformScreenY := ctx.WorldToScreen(p.body.PosX, p.body.PosY)
 
-	// Draw path line from start to end (center of platform positions)
-	startCenterX := startScreenX + p.body.W/2
-	startCenterY := startScreenY + p.body.H/2
-	endCenterX := endScreenX + p.body.W/2
-	endCenterY := endScreenY + p.body.H/2
-	ebitenutil.DrawLine(screen, startCenterX, startCenterY, endCenterX, endCenterY, pathColor)
-
-	// Draw small markers at start and end points
+	// Draw path lines through the centers of the platform's positions, with
+	// small markers at the points
 	markerSize := 4.0
-	ebitenutil.DrawRect(screen, startCenterX-markerSize/2, startCenterY-markerSize/2, markerSize, markerSize, pathColor)
-	ebitenutil.DrawRect(screen, endCenterX-markerSize/2, endCenterY-markerSize/2, markerSize, markerSize, pathColor)
+	centers := make([]mathx.Vec2, len(p.path))
+	for i, pt := range p.path {
+		x, y := ctx.WorldToScreen(pt.X, pt.Y)
+		centers[i] = mathx.V(x+p.body.W/2, y+p.body.H/2)
+		draw.FillRect(screen, centers[i].X-markerSize/2, centers[i].Y-markerSize/2, markerSize, markerSize, pathColor)
+	}
+	for i := 1; i < len(centers); i++ {
+		draw.Line(screen, centers[i-1].X, centers[i-1].Y, centers[i].X, centers[i].Y, 1, pathColor)
+	}
+	if p.loop {
+		last := centers[len(centers)-1]
+		draw.Line(screen, last.X, last.Y, centers[0].X, centers[0].Y, 1, pathColor)
+	}
 
 	// Draw platform bounds (border only)
 	borderWidth := 2.0
-	ebitenutil.DrawRect(screen, platformScreenX, platformScreenY, p.body.W, borderWidth, boundsColor)
-	ebitenutil.DrawRect(screen, platformScreenX, platformScreenY+p.body.H-borderWidth, p.body.W, borderWidth, boundsColor)
-	ebitenutil.DrawRect(screen, platformScreenX, platformScreenY, borderWidth, p.body.H, boundsColor)
-	ebitenutil.DrawRect(screen, platformScreenX+p.body.W-borderWidth, platformScreenY, borderWidth, p.body.H, boundsColor)
+	draw.FillRect(screen, platformScreenX, platformScreenY, p.body.W, borderWidth, boundsColor)
+	draw.FillRect(screen, platformScreenX, platformScreenY+p.body.H-borderWidth, p.body.W, borderWidth, boundsColor)
+	draw.FillRect(screen, platformScreenX, platformScreenY, borderWidth, p.body.H, boundsColor)
+	draw.FillRect(screen, platformScreenX+p.body.W-borderWidth, platformScreenY, borderWidth, p.body.H, boundsColor)
 }
 
 // Bounds returns the AABB for the platform.
@@ -229,9 +266,12 @@ func (p *MovingPlatform) Bounds() physics.AABB {
 
 // GetDebugInfo returns a string with debug information about the platform.
 func (p *MovingPlatform) GetDebugInfo() string {
-	direction := "A→B"
-	if !p.goingToEnd {
-		direction = "B→A"
+	direction := fmt.Sprintf("→%d/%d", p.next+1, len(p.path))
+	if len(p.path) == 2 && !p.loop {
+		direction = "A→B"
+		if p.next == 0 {
+			direction = "B→A"
+		}
 	}
 	return fmt.Sprintf("Platform[%s] %s vel=(%.0f,%.0f) wait=%.0fms",
 		p.id,
@@ -246,6 +286,14 @@ func (p *MovingPlatform) SetWaitTime(seconds float64) {
 	p.waitTime = seconds
 }
 
+// SetEase sets the easing curve of each trip between the endpoints, or
+// between path points (nil = linear). The platform still takes as long as its speed needs, but
+// speeds up and slows down along the way.
+func (p *MovingPlatform) SetEase(ease tween.Ease) {
+	p.ease = ease
+	p.leg = nil
+}
+
 // SetPushPlayer sets whether the platform should push the player sideways.
 func (p *MovingPlatform) SetPushPlayer(push bool) {
 	p.pushPlayer = push
@@ -256,7 +304,51 @@ func (p *MovingPlatform) PushPlayer() bool {
 	return p.pushPlayer
 }
 
+// Activate implements Targetable - starts the platform moving.
+func (p *MovingPlatform) Activate() {
+	p.stopped = false
+}
+
+// Deactivate implements Targetable - stops the platform where it is.
+func (p *MovingPlatform) Deactivate() {
+	p.stopped = true
+}
+
+// Toggle implements Targetable - starts or stops the platform.
+func (p *MovingPlatform) Toggle() {
+	p.stopped = !p.stopped
+}
+
+// TargetID implements Targetable.
+func (p *MovingPlatform) TargetID() string {
+	return p.id
+}
+
+// IsMoving returns whether the platform follows its path (it may be waiting
+// at an endpoint).
+func (p *MovingPlatform) IsMoving() bool {
+	return !p.stopped
+}
+
+// SaveState implements Snapshotter. The state is whether the platform is
+// stopped; its position along the path isn't restored.
+func (p *MovingPlatform) SaveState() any {
+	return p.stopped
+}
+
+// RestoreState implements Snapshotter.
+func (p *MovingPlatform) RestoreState(state any) {
+	if stopped, ok := state.(bool); ok {
+		p.stopped = stopped
+	}
+}
+
 // GetID returns the platform's identifier.
 func (p *MovingPlatform) GetID() string {
 	return p.id
 }
+
+// SetSkin implements Skinnable.
+func (p *MovingPlatform) SetSkin(skin *Skin) {
+	p.skin = skin
+}
@@ -0,0 +1,48 @@
+//go:build display
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+func TestPlatformFollowsPath(t *testing.T) {
+	p := NewMovingPlatform("lift", 0, 0, 16, 8, 0, 0, 100)
+	p.SetWaitTime(0)
+	p.SetPath([]mathx.Vec2{mathx.V(0, 0), mathx.V(100, 0), mathx.V(100, 50)}, false)
+
+	// Step through with a fixed tick and record the corners reached
+	var visited []mathx.Vec2
+	last := mathx.V(-1, -1)
+	for i := 0; i < 600; i++ {
+		p.MoveAndSlide(nil, 1.0/60)
+		pos := mathx.V(p.body.PosX, p.body.PosY)
+		for _, corner := range p.path {
+			if pos.Dist(corner) < 0.001 && corner != last {
+				visited = append(visited, corner)
+				last = corner
+			}
+		}
+	}
+	want := []mathx.Vec2{mathx.V(0, 0), mathx.V(100, 0), mathx.V(100, 50), mathx.V(100, 0), mathx.V(0, 0)}
+	if len(visited) < len(want) {
+		t.Fatalf("visited %v, want it to start with %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Fatalf("visited %v, want back and forth along the path as %v", visited, want)
+		}
+	}
+
+	// A loop goes from the last point straight to the first
+	p.SetPath([]mathx.Vec2{mathx.V(0, 0), mathx.V(100, 0), mathx.V(100, 50)}, true)
+	p.next = 2
+	p.body.PosX, p.body.PosY = 100, 50
+	p.waitTimer = 0
+	p.MoveAndSlide(nil, 1.0/60)
+	if p.next != 0 {
+		t.Errorf("after the last point of a loop, heading for point %d, want 0", p.next)
+	}
+}
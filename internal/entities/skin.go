@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// Skin overrides the built-in appearance of an entity, e.g. from a level theme.
+// The "active" variants are used for the entity's alternate state: door open,
+// switch used, checkpoint reached. Unset fields fall back to the built-in look.
+type Skin struct {
+	Color       color.RGBA    // Fill color (alpha 0 = unset)
+	ActiveColor color.RGBA    // Fill color in the alternate state (alpha 0 = unset)
+	Image       *ebiten.Image // Image stretched over the entity bounds
+	ActiveImage *ebiten.Image // Image in the alternate state
+}
+
+// Skinnable is implemented by entities whose appearance can be themed.
+type Skinnable interface {
+	SetSkin(skin *Skin)
+}
+
+// draw renders the skin into the given screen rectangle.
+// Returns false if the skin has nothing for the requested state, in which
+// case the caller should draw its built-in look.
+func (s *Skin) draw(screen *ebiten.Image, x, y, w, h float64, active bool) bool {
+	if s == nil || w <= 0 || h <= 0 {
+		return false
+	}
+
+	img, col := s.Image, s.Color
+	if active {
+		img, col = s.ActiveImage, s.ActiveColor
+	}
+
+	if img != nil {
+		bounds := img.Bounds()
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(w/float64(bounds.Dx()), h/float64(bounds.Dy()))
+		op.GeoM.Translate(x, y)
+		screen.DrawImage(img, op)
+		return true
+	}
+
+	if col.A > 0 {
+		draw.FillRect(screen, x, y, w, h, col)
+		return true
+	}
+
+	return false
+}
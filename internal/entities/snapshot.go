@@ -0,0 +1,37 @@
+package entities
+
+// Snapshotter is implemented by entities whose state checkpoints save and
+// restore: opened doors, used switches, picked-up keys, and so on.
+type Snapshotter interface {
+	// SaveState returns a copy of the entity's state.
+	SaveState() any
+	// RestoreState puts back a state returned by SaveState.
+	RestoreState(state any)
+}
+
+// WorldSnapshot is the saved state of a world's entities.
+type WorldSnapshot struct {
+	states map[Snapshotter]any
+}
+
+// Snapshot saves the state of every entity that implements Snapshotter.
+func (w *EntityWorld) Snapshot() *WorldSnapshot {
+	s := &WorldSnapshot{states: make(map[Snapshotter]any)}
+	for _, e := range w.entities {
+		if sn, ok := e.(Snapshotter); ok {
+			s.states[sn] = sn.SaveState()
+		}
+	}
+	return s
+}
+
+// Restore puts the entities back into the state saved by Snapshot.
+// Entities added after the snapshot are left alone. A nil snapshot does nothing.
+func (w *EntityWorld) Restore(s *WorldSnapshot) {
+	if s == nil {
+		return
+	}
+	for sn, state := range s.states {
+		sn.RestoreState(state)
+	}
+}
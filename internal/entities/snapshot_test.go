@@ -0,0 +1,68 @@
+//go:build display
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+)
+
+func TestWorldRestoreUndoesChangesSinceSnapshot(t *testing.T) {
+	w := NewEntityWorld()
+	door := NewDoor(100, 0, 16, 64, "door1")
+	w.AddSolidEntity(door)
+	locked := NewDoor(200, 0, 16, 64, "vault")
+	locked.SetLock(1, "")
+	w.AddSolidEntity(locked)
+	sw := NewSwitch(0, 40, 16, 16, "door1")
+	sw.SetOnce(true)
+	sw.SetRegistry(w.TargetRegistry)
+	w.AddTrigger(sw)
+	key := NewKey(50, 40, 16, 12, "")
+	w.AddTrigger(key)
+
+	snap := w.Snapshot()
+
+	player := &physics.Body{PosX: 0, PosY: 40, W: 12, H: 12}
+	sw.OnEnter(player)
+	key.OnEnter(player)
+	locked.locked = false // As if opened with the key
+	if !door.IsOpen() || sw.IsActive() || key.IsActive() {
+		t.Fatal("setup: switch and key weren't used")
+	}
+
+	w.Restore(snap)
+
+	if door.IsOpen() || door.GetBody().W != 16 {
+		t.Error("door wasn't closed again")
+	}
+	if !locked.Locked() {
+		t.Error("door wasn't locked again")
+	}
+	if !sw.IsActive() {
+		t.Error("once switch wasn't usable again")
+	}
+	if !key.IsActive() {
+		t.Error("key wasn't put back")
+	}
+
+	// The restored switch works again
+	sw.OnEnter(player)
+	if !door.IsOpen() {
+		t.Error("restored switch didn't open its door")
+	}
+}
+
+func TestWorldRestoreNilIsNoop(t *testing.T) {
+	w := NewEntityWorld()
+	door := NewDoor(100, 0, 16, 64, "door1")
+	door.Open()
+	w.AddSolidEntity(door)
+
+	w.Restore(nil)
+
+	if !door.IsOpen() {
+		t.Error("Restore(nil) changed the door")
+	}
+}
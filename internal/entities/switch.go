@@ -2,23 +2,55 @@ package entities
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/world"
 )
 
+// SwitchMode selects how a switch drives its targets.
+type SwitchMode string
+
+const (
+	// SwitchToggle is a lever: each touch toggles (or activates) the targets.
+	SwitchToggle SwitchMode = "toggle"
+	// SwitchPlate is a pressure plate: targets are active only while stood on.
+	SwitchPlate SwitchMode = "plate"
+	// SwitchTimed activates the targets and deactivates them after a duration.
+	SwitchTimed SwitchMode = "timed"
+)
+
+// ParseSwitchMode parses a switch mode, defaulting to SwitchToggle.
+func ParseSwitchMode(s string) SwitchMode {
+	switch SwitchMode(s) {
+	case SwitchToggle, SwitchPlate, SwitchTimed:
+		return SwitchMode(s)
+	}
+	return SwitchToggle
+}
+
 // Switch is a trigger that controls Targetable entities (doors, etc.).
-// When touched, it can toggle or set the state of a target.
+// When touched, it can toggle or set the state of its targets.
 type Switch struct {
 	bounds     physics.AABB
 	state      TriggerState
-	id         string // Unique identifier for this switch
-	targetID   string
-	toggleMode bool // true = toggle, false = one-shot open
+	zone       Zone
+	id         string   // Unique identifier for this switch
+	targetID   string   // Primary target
+	targetIDs  []string // All targets, starting with the primary one
+	mode       SwitchMode
+	toggleMode bool // true = toggle, false = one-shot open (toggle mode only)
 	once       bool // true = deactivate after use
 	used       bool // Has been used (for once mode)
+	skin       *Skin
+
+	// Plate and timer state
+	pressed   bool
+	duration  float64 // Seconds a timed switch stays on
+	remaining float64 // Seconds left on a running timer
+	elapsed   float64 // Drives the ticking blink
 
 	// Registry for resolving targets at runtime
 	registry *TargetRegistry
@@ -31,15 +63,65 @@ type Switch struct {
 
 // NewSwitch creates a new switch at the given position.
 func NewSwitch(x, y, w, h float64, targetID string) *Switch {
-	return &Switch{
+	s := &Switch{
 		bounds:     physics.AABB{X: x, Y: y, W: w, H: h},
 		state:      NewTriggerState(),
 		targetID:   targetID,
+		mode:       SwitchToggle,
 		toggleMode: true, // Default to toggle mode
 		once:       false,
+		duration:   defaultSwitchDuration,
+	}
+	if targetID != "" {
+		s.targetIDs = []string{targetID}
+	}
+	return s
+}
+
+// defaultSwitchDuration is how long timed switches stay on unless set.
+const defaultSwitchDuration = 3.0
+
+// SetMode sets how the switch drives its targets.
+func (s *Switch) SetMode(mode SwitchMode) {
+	s.mode = mode
+}
+
+// Mode returns the switch mode.
+func (s *Switch) Mode() SwitchMode {
+	return s.mode
+}
+
+// SetDuration sets how long a timed switch keeps its targets active, in seconds.
+func (s *Switch) SetDuration(seconds float64) {
+	if seconds > 0 {
+		s.duration = seconds
+	}
+}
+
+// SetTargets sets all target IDs. The first one becomes the primary target.
+func (s *Switch) SetTargets(ids []string) {
+	s.targetIDs = ids
+	s.targetID = ""
+	if len(ids) > 0 {
+		s.targetID = ids[0]
 	}
 }
 
+// TargetIDs returns all target IDs.
+func (s *Switch) TargetIDs() []string {
+	return s.targetIDs
+}
+
+// Remaining returns the seconds left on a running timed switch, or 0.
+func (s *Switch) Remaining() float64 {
+	return s.remaining
+}
+
+// IsPressed returns whether a pressure plate is held down.
+func (s *Switch) IsPressed() bool {
+	return s.pressed
+}
+
 // SetToggleMode sets whether the switch toggles or one-shot opens.
 func (s *Switch) SetToggleMode(toggle bool) {
 	s.toggleMode = toggle
@@ -71,8 +153,17 @@ func (s *Switch) GetTargetID() string {
 }
 
 // Update implements Entity.
+// A running timed switch counts down and releases its targets when it runs out.
 func (s *Switch) Update(dt float64) {
-	// Switches don't need per-frame updates
+	if s.remaining <= 0 {
+		return
+	}
+	s.elapsed += dt
+	s.remaining -= dt
+	if s.remaining <= 0 {
+		s.remaining = 0
+		s.forEachTarget(Targetable.Deactivate)
+	}
 }
 
 // Draw implements Entity.
@@ -94,20 +185,23 @@ func (s *Switch) Draw(screen *ebiten.Image, camX, camY float64) {
 	}
 
 	// Draw switch body
-	ebitenutil.DrawRect(screen, x, y, s.bounds.W, s.bounds.H, col)
+	draw.FillRect(screen, x, y, s.bounds.W, s.bounds.H, col)
 
 	// Draw border
 	borderColor := color.RGBA{50, 50, 50, 255}
-	ebitenutil.DrawRect(screen, x, y, s.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y+s.bounds.H-2, s.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y, 2, s.bounds.H, borderColor)
-	ebitenutil.DrawRect(screen, x+s.bounds.W-2, y, 2, s.bounds.H, borderColor)
+	draw.FillRect(screen, x, y, s.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y+s.bounds.H-2, s.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y, 2, s.bounds.H, borderColor)
+	draw.FillRect(screen, x+s.bounds.W-2, y, 2, s.bounds.H, borderColor)
 }
 
 // DrawWithContext implements Entity.
 func (s *Switch) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
 	// Use WorldToScreen for coordinate conversion
 	x, y := ctx.WorldToScreen(s.bounds.X, s.bounds.Y)
+	if s.skin.draw(screen, x, y, s.bounds.W, s.bounds.H, s.used || !s.state.Active) {
+		return
+	}
 
 	var col color.RGBA
 	if !s.state.Active {
@@ -121,15 +215,48 @@ func (s *Switch) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext)
 		col = color.RGBA{255, 200, 0, 255}
 	}
 
+	// Ticking: blink faster as the timer runs out
+	if s.remaining > 0 && s.blinkOff() {
+		col = color.RGBA{180, 120, 0, 255}
+	}
+
+	// A pressed plate sinks to half height
+	bodyY, bodyH := y, s.bounds.H
+	if s.pressed {
+		bodyY, bodyH = y+s.bounds.H/2, s.bounds.H/2
+	}
+
 	// Draw switch body
-	ebitenutil.DrawRect(screen, x, y, s.bounds.W, s.bounds.H, col)
+	draw.FillRect(screen, x, bodyY, s.bounds.W, bodyH, col)
 
 	// Draw border
 	borderColor := color.RGBA{50, 50, 50, 255}
-	ebitenutil.DrawRect(screen, x, y, s.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y+s.bounds.H-2, s.bounds.W, 2, borderColor)
-	ebitenutil.DrawRect(screen, x, y, 2, s.bounds.H, borderColor)
-	ebitenutil.DrawRect(screen, x+s.bounds.W-2, y, 2, s.bounds.H, borderColor)
+	draw.FillRect(screen, x, y, s.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y+s.bounds.H-2, s.bounds.W, 2, borderColor)
+	draw.FillRect(screen, x, y, 2, s.bounds.H, borderColor)
+	draw.FillRect(screen, x+s.bounds.W-2, y, 2, s.bounds.H, borderColor)
+
+	s.drawTimer(screen, x, y)
+}
+
+// drawTimer draws the time left on a running timed switch as a bar above it.
+func (s *Switch) drawTimer(screen *ebiten.Image, x, y float64) {
+	if s.remaining <= 0 {
+		return
+	}
+	w := s.bounds.W * s.remaining / s.duration
+	draw.FillRect(screen, x, y-4, s.bounds.W, 2, color.RGBA{50, 50, 50, 200})
+	draw.FillRect(screen, x, y-4, w, 2, color.RGBA{255, 200, 0, 255})
+}
+
+// blinkOff returns whether a running timer's blink is in its dark phase.
+// The blink speeds up from 2 to 8 Hz over the last half of the duration.
+func (s *Switch) blinkOff() bool {
+	rate := 2.0
+	if half := s.duration / 2; s.remaining < half {
+		rate += 6 * (1 - s.remaining/half)
+	}
+	return math.Mod(s.elapsed*rate, 1) >= 0.5
 }
 
 // Bounds implements Entity.
@@ -137,6 +264,17 @@ func (s *Switch) Bounds() physics.AABB {
 	return s.bounds
 }
 
+// SetZone limits the switch to a polygon or ellipse within its bounds.
+func (s *Switch) SetZone(z Zone) {
+	z.fit(&s.bounds)
+	s.zone = z
+}
+
+// Overlaps implements ShapedTrigger.
+func (s *Switch) Overlaps(aabb physics.AABB) bool {
+	return s.zone.Overlaps(s.bounds, aabb)
+}
+
 // OnEnter implements Trigger.
 func (s *Switch) OnEnter(player *physics.Body) {
 	if !s.state.Active {
@@ -148,21 +286,22 @@ func (s *Switch) OnEnter(player *physics.Body) {
 		s.OnTrigger(s.id)
 	}
 
-	// Legacy behavior: resolve target from registry
-	if s.registry == nil {
-		return
-	}
-
-	target := s.registry.Resolve(s.targetID)
-	if target == nil {
-		return
-	}
-
-	// Execute switch action
-	if s.toggleMode {
-		target.Toggle()
-	} else {
-		target.Activate()
+	// Legacy behavior: resolve targets from registry
+	switch s.mode {
+	case SwitchPlate:
+		s.pressed = true
+		s.forEachTarget(Targetable.Activate)
+	case SwitchTimed:
+		// Stepping on a running timer restarts it
+		s.remaining = s.duration
+		s.elapsed = 0
+		s.forEachTarget(Targetable.Activate)
+	default:
+		if s.toggleMode {
+			s.forEachTarget(Targetable.Toggle)
+		} else {
+			s.forEachTarget(Targetable.Activate)
+		}
 	}
 
 	// Mark as used if once mode
@@ -173,8 +312,51 @@ func (s *Switch) OnEnter(player *physics.Body) {
 }
 
 // OnExit implements Trigger.
+// Stepping off a pressure plate deactivates its targets. A once plate keeps
+// them active.
 func (s *Switch) OnExit(player *physics.Body) {
-	// Nothing to do on exit
+	if s.mode != SwitchPlate || !s.pressed {
+		return
+	}
+	s.pressed = false
+	if !s.once {
+		s.forEachTarget(Targetable.Deactivate)
+	}
+}
+
+// switchState is a switch's saved state.
+type switchState struct {
+	active, used, pressed bool
+	remaining             float64
+}
+
+// SaveState implements Snapshotter.
+func (s *Switch) SaveState() any {
+	return switchState{active: s.state.Active, used: s.used, pressed: s.pressed, remaining: s.remaining}
+}
+
+// RestoreState implements Snapshotter.
+func (s *Switch) RestoreState(state any) {
+	st, ok := state.(switchState)
+	if !ok {
+		return
+	}
+	s.state.Active = st.active
+	s.used = st.used
+	s.pressed = st.pressed
+	s.remaining = st.remaining
+}
+
+// forEachTarget applies fn to every target found in the registry.
+func (s *Switch) forEachTarget(fn func(Targetable)) {
+	if s.registry == nil {
+		return
+	}
+	for _, id := range s.targetIDs {
+		if target := s.registry.Resolve(id); target != nil {
+			fn(target)
+		}
+	}
 }
 
 // IsActive implements Trigger.
@@ -182,6 +364,11 @@ func (s *Switch) IsActive() bool {
 	return s.state.IsActive()
 }
 
+// SetActive sets whether the switch can be pressed.
+func (s *Switch) SetActive(active bool) {
+	s.state.SetActive(active)
+}
+
 // WasTriggered implements Trigger.
 func (s *Switch) WasTriggered() bool {
 	return s.state.WasTriggered()
@@ -191,3 +378,8 @@ func (s *Switch) WasTriggered() bool {
 func (s *Switch) SetTriggered(triggered bool) {
 	s.state.SetTriggered(triggered)
 }
+
+// SetSkin implements Skinnable.
+func (s *Switch) SetSkin(skin *Skin) {
+	s.skin = skin
+}
@@ -0,0 +1,122 @@
+//go:build display
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+)
+
+// newSwitchScene builds a switch wired to a closed door and a stopped platform.
+func newSwitchScene(mode SwitchMode) (*Switch, *Door, *MovingPlatform, *physics.Body) {
+	player := &physics.Body{PosX: 0, PosY: 40, W: 12, H: 12}
+
+	door := NewDoor(100, 0, 16, 64, "door1")
+	platform := NewMovingPlatform("lift", 200, 0, 32, 8, 200, 100, 60)
+	platform.Deactivate()
+
+	registry := NewTargetRegistry()
+	registry.Register(door)
+	registry.Register(platform)
+	sw := NewSwitch(0, 40, 16, 16, "")
+	sw.SetTargets([]string{"door1", "lift"})
+	sw.SetMode(mode)
+	sw.SetRegistry(registry)
+
+	return sw, door, platform, player
+}
+
+func TestSwitchToggleDrivesAllTargets(t *testing.T) {
+	sw, door, platform, player := newSwitchScene(SwitchToggle)
+
+	sw.OnEnter(player)
+	if !door.IsOpen() || !platform.IsMoving() {
+		t.Fatalf("after first touch: door open %v, platform moving %v; want both", door.IsOpen(), platform.IsMoving())
+	}
+
+	sw.OnExit(player)
+	sw.OnEnter(player)
+	if door.IsOpen() || platform.IsMoving() {
+		t.Fatalf("after second touch: door open %v, platform moving %v; want neither", door.IsOpen(), platform.IsMoving())
+	}
+}
+
+func TestSwitchPlateHoldsWhileStoodOn(t *testing.T) {
+	sw, door, platform, player := newSwitchScene(SwitchPlate)
+
+	sw.OnEnter(player)
+	if !sw.IsPressed() || !door.IsOpen() || !platform.IsMoving() {
+		t.Fatal("plate didn't activate its targets while stood on")
+	}
+
+	sw.OnExit(player)
+	if sw.IsPressed() || door.IsOpen() || platform.IsMoving() {
+		t.Fatal("plate kept its targets active after the player stepped off")
+	}
+}
+
+func TestSwitchPlateOnceStaysActive(t *testing.T) {
+	sw, door, _, player := newSwitchScene(SwitchPlate)
+	sw.SetOnce(true)
+
+	sw.OnEnter(player)
+	sw.OnExit(player)
+
+	if !door.IsOpen() {
+		t.Fatal("once plate closed its door on exit")
+	}
+}
+
+func TestSwitchTimedReverts(t *testing.T) {
+	sw, door, platform, player := newSwitchScene(SwitchTimed)
+	sw.SetDuration(1)
+
+	sw.OnEnter(player)
+	sw.OnExit(player)
+	if !door.IsOpen() || !platform.IsMoving() {
+		t.Fatal("timed switch didn't activate its targets")
+	}
+
+	for i := 0; i < 30; i++ {
+		sw.Update(1.0 / 60)
+	}
+	if !door.IsOpen() || sw.Remaining() <= 0 {
+		t.Fatal("timed switch reverted early")
+	}
+
+	// Stepping on again restarts the timer
+	sw.OnEnter(player)
+	sw.OnExit(player)
+	for i := 0; i < 45; i++ {
+		sw.Update(1.0 / 60)
+	}
+	if !door.IsOpen() {
+		t.Fatal("restarted timer reverted early")
+	}
+
+	for i := 0; i < 30; i++ {
+		sw.Update(1.0 / 60)
+	}
+	if door.IsOpen() || platform.IsMoving() || sw.Remaining() != 0 {
+		t.Fatal("timed switch didn't revert after its duration")
+	}
+}
+
+func TestParseSwitchMode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want SwitchMode
+	}{
+		{"", SwitchToggle},
+		{"toggle", SwitchToggle},
+		{"plate", SwitchPlate},
+		{"timed", SwitchTimed},
+		{"lever", SwitchToggle},
+	}
+	for _, tt := range tests {
+		if got := ParseSwitchMode(tt.in); got != tt.want {
+			t.Errorf("ParseSwitchMode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package entities
 
+import (
+	"path"
+	"sort"
+)
+
 // Targetable represents an entity that can be activated/deactivated by switches, etc.
 type Targetable interface {
 	// Activate triggers the target (e.g., open a door)
@@ -14,14 +19,19 @@ type Targetable interface {
 
 // TargetRegistry manages ID-to-target lookups.
 // It provides a decoupled way for switches and other triggers to find their targets.
+//
+// Targets can also carry tags ("lights", "wave1_doors") so a whole group can
+// be looked up at once with ResolveTag.
 type TargetRegistry struct {
 	targets map[string]Targetable
+	tags    map[string]map[string]bool // Tag -> IDs carrying it
 }
 
 // NewTargetRegistry creates a new registry.
 func NewTargetRegistry() *TargetRegistry {
 	return &TargetRegistry{
 		targets: make(map[string]Targetable),
+		tags:    make(map[string]map[string]bool),
 	}
 }
 
@@ -38,6 +48,44 @@ func (r *TargetRegistry) Register(t Targetable) {
 	r.targets[id] = t
 }
 
+// RegisterTagged adds a target to the registry and tags it with tags.
+func (r *TargetRegistry) RegisterTagged(t Targetable, tags ...string) {
+	r.Register(t)
+	if t != nil {
+		r.Tag(t.TargetID(), tags...)
+	}
+}
+
+// Tag adds tags to the target with the given ID. The target doesn't have to
+// be registered yet: tags are matched when resolving, so entities registered
+// after spawning (platforms, lights) can be tagged from their objects.
+func (r *TargetRegistry) Tag(id string, tags ...string) {
+	if id == "" {
+		return
+	}
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if r.tags[tag] == nil {
+			r.tags[tag] = make(map[string]bool)
+		}
+		r.tags[tag][id] = true
+	}
+}
+
+// Tags returns the tags of the target with the given ID, sorted.
+func (r *TargetRegistry) Tags(id string) []string {
+	var tags []string
+	for tag, ids := range r.tags {
+		if ids[id] {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 // Unregister removes a target from the registry.
 func (r *TargetRegistry) Unregister(t Targetable) {
 	if t == nil {
@@ -58,6 +106,42 @@ func (r *TargetRegistry) Resolve(id string) Targetable {
 	return r.targets[id]
 }
 
+// ResolveTag returns the registered targets tagged with tag, sorted by ID.
+func (r *TargetRegistry) ResolveTag(tag string) []Targetable {
+	var ids []string
+	for id := range r.tags[tag] {
+		if r.targets[id] != nil {
+			ids = append(ids, id)
+		}
+	}
+	return r.resolveSorted(ids)
+}
+
+// ResolvePattern returns the registered targets whose ID matches pattern,
+// sorted by ID. Patterns use path.Match syntax, so "door_*" matches every ID
+// starting with "door_"; a pattern without wildcards matches just that ID.
+// A malformed pattern matches nothing.
+func (r *TargetRegistry) ResolvePattern(pattern string) []Targetable {
+	var ids []string
+	for id := range r.targets {
+		if ok, _ := path.Match(pattern, id); ok {
+			ids = append(ids, id)
+		}
+	}
+	return r.resolveSorted(ids)
+}
+
+// resolveSorted returns the targets with the given registered IDs, sorted
+// by ID so group actions apply in a stable order.
+func (r *TargetRegistry) resolveSorted(ids []string) []Targetable {
+	sort.Strings(ids)
+	result := make([]Targetable, len(ids))
+	for i, id := range ids {
+		result[i] = r.targets[id]
+	}
+	return result
+}
+
 // HasTarget returns true if a target with the given ID exists.
 func (r *TargetRegistry) HasTarget(id string) bool {
 	_, exists := r.targets[id]
@@ -0,0 +1,61 @@
+//go:build display
+
+package entities
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// targetIDs returns the IDs of targets, in order.
+func targetIDs(targets []Targetable) []string {
+	ids := make([]string, len(targets))
+	for i, t := range targets {
+		ids[i] = t.TargetID()
+	}
+	return ids
+}
+
+func TestTargetRegistryTags(t *testing.T) {
+	registry := NewTargetRegistry()
+	registry.RegisterTagged(NewDoor(0, 0, 16, 64, "door_b"), "wave1")
+	registry.Register(NewDoor(0, 0, 16, 64, "door_a"))
+	// Tagged before it is registered, like platforms and lights at spawn
+	registry.Tag("lamp", "wave1", "lights")
+	registry.Register(NewLight("lamp", 0, 0, 64, color.RGBA{}))
+	registry.Tag("gone", "wave1")
+
+	if got, want := targetIDs(registry.ResolveTag("wave1")), []string{"door_b", "lamp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveTag(wave1) = %v, want %v (sorted, unregistered IDs skipped)", got, want)
+	}
+	if got := registry.ResolveTag("nothing"); len(got) != 0 {
+		t.Errorf("ResolveTag(nothing) = %v, want none", targetIDs(got))
+	}
+	if got, want := registry.Tags("lamp"), []string{"lights", "wave1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tags(lamp) = %v, want %v", got, want)
+	}
+}
+
+func TestTargetRegistryResolvePattern(t *testing.T) {
+	registry := NewTargetRegistry()
+	for _, id := range []string{"door_2", "door_1", "doorway", "lamp"} {
+		registry.Register(NewDoor(0, 0, 16, 64, id))
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"door_*", []string{"door_1", "door_2"}},
+		{"door?*", []string{"door_1", "door_2", "doorway"}},
+		{"lamp", []string{"lamp"}},
+		{"gate_*", []string{}},
+		{"[", []string{}}, // Malformed
+	}
+	for _, tt := range tests {
+		if got := targetIDs(registry.ResolvePattern(tt.pattern)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ResolvePattern(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
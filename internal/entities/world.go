@@ -1,7 +1,10 @@
 package entities
 
 import (
+	"sort"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/world"
 )
@@ -26,9 +29,29 @@ type EntityWorld struct {
 	triggers   []Trigger
 	solidEnts  []SolidEntity
 	kinematics []physics.Kinematic
+	lights     []*Light
+
+	// Objects the entities were spawned from, for the inspector
+	sources map[Entity]world.ObjectData
+
+	// Render order of the entities that have one (see SetZ); others draw at
+	// world.ZPlayer
+	z         map[Entity]int
+	drawOrder []drawItem // Reused by DrawWithPlayer
+
+	seenBodies map[*physics.Body]struct{} // Reused by AppendActiveSolidAABBs
+
+	// YSort draws entities with the same z, and the player among them, by
+	// their bottom edge (see world.PropYSort).
+	YSort bool
 
 	// TargetRegistry manages ID-to-target lookups for switches, etc.
 	TargetRegistry *TargetRegistry
+
+	// OnTriggerEvent, if set, is called when the player enters (before
+	// OnEnter) or leaves (after OnExit) a trigger with an id, e.g. to feed
+	// the rules engine. The id is the trigger's own, or its object's name.
+	OnTriggerEvent func(id string, entered bool)
 }
 
 // NewEntityWorld creates an empty entity world.
@@ -64,11 +87,65 @@ func (w *EntityWorld) AddSolidEntity(e SolidEntity) {
 	}
 }
 
+// AddLight adds a light to the world and registers it as a target.
+func (w *EntityWorld) AddLight(l *Light) {
+	w.lights = append(w.lights, l)
+	w.entities = append(w.entities, l) // Also add to general entities list
+	w.TargetRegistry.Register(l)
+}
+
+// Lights returns all lights.
+func (w *EntityWorld) Lights() []*Light {
+	return w.lights
+}
+
+// PointLights returns all lights in screen coordinates for the light layer.
+func (w *EntityWorld) PointLights(ctx *world.RenderContext) []gfx.PointLight {
+	lights := make([]gfx.PointLight, 0, len(w.lights))
+	for _, l := range w.lights {
+		lights = append(lights, l.PointLight(ctx))
+	}
+	return lights
+}
+
 // RegisterTarget adds a Targetable entity to the registry.
 func (w *EntityWorld) RegisterTarget(t Targetable) {
 	w.TargetRegistry.Register(t)
 }
 
+// SetSource records the level object e was spawned from, and takes e's
+// render order from it (see world.ObjectData.Z).
+func (w *EntityWorld) SetSource(e Entity, obj world.ObjectData) {
+	if w.sources == nil {
+		w.sources = make(map[Entity]world.ObjectData)
+	}
+	w.sources[e] = obj
+	w.SetZ(e, obj.Z())
+}
+
+// SetZ sets the render order of e: entities draw from the lowest z up, the
+// player at world.ZPlayer.
+func (w *EntityWorld) SetZ(e Entity, z int) {
+	if w.z == nil {
+		w.z = make(map[Entity]int)
+	}
+	w.z[e] = z
+}
+
+// Z returns the render order of e, world.ZPlayer if it has none.
+func (w *EntityWorld) Z(e Entity) int {
+	if z, ok := w.z[e]; ok {
+		return z
+	}
+	return world.ZPlayer
+}
+
+// Source returns the level object e was spawned from, if recorded.
+func (w *EntityWorld) Source(e Entity) (world.ObjectData, bool) {
+	obj, ok := w.sources[e]
+	return obj, ok
+}
+
 // Entities returns all entities.
 func (w *EntityWorld) Entities() []Entity {
 	return w.entities
@@ -97,17 +174,27 @@ func (w *EntityWorld) GetKinematics() []physics.Kinematic {
 // ActiveSolidAABBs returns unique AABBs for all active solid bodies.
 // Kinematics are included, but bodies already present in solid entities are deduplicated.
 func (w *EntityWorld) ActiveSolidAABBs() []physics.AABB {
-	var solids []physics.AABB
-	seenBodies := make(map[*physics.Body]struct{})
+	return w.AppendActiveSolidAABBs(nil)
+}
+
+// AppendActiveSolidAABBs appends the AABBs ActiveSolidAABBs returns to dst
+// and returns the extended slice. Passing last tick's slice truncated to
+// zero length makes it allocation free once the slice is big enough.
+func (w *EntityWorld) AppendActiveSolidAABBs(dst []physics.AABB) []physics.AABB {
+	solids := dst
+	if w.seenBodies == nil {
+		w.seenBodies = make(map[*physics.Body]struct{})
+	}
+	clear(w.seenBodies)
 
 	addBody := func(body *physics.Body) {
 		if body == nil || body.W <= 0 || body.H <= 0 {
 			return
 		}
-		if _, seen := seenBodies[body]; seen {
+		if _, seen := w.seenBodies[body]; seen {
 			return
 		}
-		seenBodies[body] = struct{}{}
+		w.seenBodies[body] = struct{}{}
 		solids = append(solids, body.AABB())
 	}
 
@@ -129,14 +216,26 @@ func (w *EntityWorld) ActiveSolidAABBs() []physics.AABB {
 }
 
 // UpdateKinematics updates all kinematic entities with collision detection.
+// Each body's position before the step is saved for interpolated drawing.
 func (w *EntityWorld) UpdateKinematics(collisionMap *world.CollisionMap, dt float64) {
 	for _, k := range w.kinematics {
+		k.GetBody().SavePrevious()
 		if k.IsActive() {
 			k.MoveAndSlide(collisionMap, dt)
 		}
 	}
 }
 
+// FixedUpdate steps every FixedUpdater entity. Call it once per fixed update,
+// before the player moves.
+func (w *EntityWorld) FixedUpdate(dt float64) {
+	for _, e := range w.entities {
+		if f, ok := e.(FixedUpdater); ok {
+			f.FixedUpdate(dt)
+		}
+	}
+}
+
 // Update updates all entities.
 func (w *EntityWorld) Update(dt float64) {
 	for _, e := range w.entities {
@@ -152,11 +251,46 @@ func (w *EntityWorld) Draw(screen *ebiten.Image, camX, camY float64) {
 	}
 }
 
-// DrawWithContext renders all entities using a RenderContext.
+// DrawWithContext renders all entities in render order using a RenderContext.
 func (w *EntityWorld) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
+	w.DrawWithPlayer(screen, ctx, physics.AABB{}, nil)
+}
+
+// drawItem is an entity, or the player if entity is nil, in render order.
+type drawItem struct {
+	entity Entity
+	z      int
+	bottom float64
+}
+
+// DrawWithPlayer renders all entities in render order (see SetZ), calling
+// drawPlayer, if set, at the player's place in it: at world.ZPlayer, after
+// the entities there, or with YSort by the bottom of the player's bounds.
+// Entities with the same order draw in the order they were added.
+func (w *EntityWorld) DrawWithPlayer(screen *ebiten.Image, ctx *world.RenderContext, player physics.AABB, drawPlayer func()) {
+	items := w.drawOrder[:0]
 	for _, e := range w.entities {
-		e.DrawWithContext(screen, ctx)
+		b := e.Bounds()
+		items = append(items, drawItem{entity: e, z: w.Z(e), bottom: b.Y + b.H})
+	}
+	if drawPlayer != nil {
+		items = append(items, drawItem{z: world.ZPlayer, bottom: player.Y + player.H})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].z != items[j].z {
+			return items[i].z < items[j].z
+		}
+		return w.YSort && items[i].bottom < items[j].bottom
+	})
+
+	for _, item := range items {
+		if item.entity == nil {
+			drawPlayer()
+		} else {
+			item.entity.DrawWithContext(screen, ctx)
+		}
 	}
+	w.drawOrder = items[:0]
 }
 
 // CheckTriggers tests player against all triggers.
@@ -171,10 +305,14 @@ func (w *EntityWorld) CheckTriggers(player *physics.Body) bool {
 		}
 
 		intersects := playerAABB.Intersects(t.Bounds())
+		if st, ok := t.(ShapedTrigger); ok && intersects {
+			intersects = st.Overlaps(playerAABB)
+		}
 		wasTriggered := t.WasTriggered()
 
 		if intersects && !wasTriggered {
 			// Player just entered the trigger
+			w.emitTriggerEvent(t, true)
 			t.OnEnter(player)
 			t.SetTriggered(true)
 			anyTriggered = true
@@ -182,12 +320,28 @@ func (w *EntityWorld) CheckTriggers(player *physics.Body) bool {
 			// Player just exited the trigger
 			t.OnExit(player)
 			t.SetTriggered(false)
+			w.emitTriggerEvent(t, false)
 		}
 	}
 
 	return anyTriggered
 }
 
+// emitTriggerEvent reports an enter or exit to OnTriggerEvent. Triggers
+// without an id or a named object are skipped.
+func (w *EntityWorld) emitTriggerEvent(t Trigger, entered bool) {
+	if w.OnTriggerEvent == nil {
+		return
+	}
+	id := entityID(t)
+	if id == "" {
+		id = w.sources[t].Name
+	}
+	if id != "" {
+		w.OnTriggerEvent(id, entered)
+	}
+}
+
 // FindDoorByID finds a solid entity that is a door with the given ID.
 // Returns nil if not found.
 func (w *EntityWorld) FindDoorByID(id string) SolidEntity {
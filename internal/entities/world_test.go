@@ -0,0 +1,88 @@
+//go:build display
+
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// drawRecorder is an entity that records when it's drawn.
+type drawRecorder struct {
+	name   string
+	bounds physics.AABB
+	log    *[]string
+}
+
+func (d *drawRecorder) Update(dt float64)                             {}
+func (d *drawRecorder) Draw(screen *ebiten.Image, camX, camY float64) {}
+func (d *drawRecorder) Bounds() physics.AABB                          { return d.bounds }
+func (d *drawRecorder) DrawWithContext(screen *ebiten.Image, ctx *world.RenderContext) {
+	*d.log = append(*d.log, d.name)
+}
+
+func TestDrawWithPlayerOrder(t *testing.T) {
+	var log []string
+	w := NewEntityWorld()
+	add := func(name string, y float64, obj world.ObjectData) {
+		e := &drawRecorder{name: name, bounds: physics.AABB{Y: y, W: 16, H: 16}, log: &log}
+		w.AddEntity(e)
+		w.SetSource(e, obj)
+	}
+	add("coin", 0, world.ObjectData{Type: world.ObjectTypeCollectible})
+	add("lower", 40, world.ObjectData{Type: world.ObjectTypeLight})
+	add("door", 0, world.ObjectData{Type: world.ObjectTypeDoor})
+	add("upper", 10, world.ObjectData{Type: world.ObjectTypeLight})
+	add("sign", 0, world.ObjectData{Type: world.ObjectTypeLight, Props: map[string]any{world.PropZ: 20.0}})
+
+	player := physics.AABB{Y: 20, W: 12, H: 12}
+	drawPlayer := func() { log = append(log, "player") }
+	ctx := world.NewRenderContext(nil, nil, 0)
+
+	tests := []struct {
+		ySort bool
+		want  string
+	}{
+		// Same z keeps the order the entities were added in, the player last
+		{false, "door lower upper player coin sign"},
+		// Same z sorts by bottom edge: upper (26), player (32), lower (56)
+		{true, "door upper player lower coin sign"},
+	}
+	for _, tt := range tests {
+		log = nil
+		w.YSort = tt.ySort
+		w.DrawWithPlayer(nil, ctx, player, drawPlayer)
+		if got := strings.Join(log, " "); got != tt.want {
+			t.Errorf("ySort %v: drew %q, want %q", tt.ySort, got, tt.want)
+		}
+	}
+}
+
+func TestAppendActiveSolidAABBs(t *testing.T) {
+	w := NewEntityWorld()
+	door := NewDoor(0, 0, 16, 48, "door")
+	platform := NewMovingPlatform("lift", 64, 0, 48, 8, 64, 96, 40)
+	w.AddSolidEntity(door)
+	w.AddSolidEntity(platform)
+	w.AddKinematic(platform) // Listed twice, like the scenes do
+
+	solids := w.AppendActiveSolidAABBs(nil)
+	want := []physics.AABB{door.GetBody().AABB(), platform.GetBody().AABB()}
+	if len(solids) != 2 || solids[0] != want[0] || solids[1] != want[1] {
+		t.Fatalf("AppendActiveSolidAABBs = %v, want %v", solids, want)
+	}
+
+	// Reusing last tick's slice
+	if allocs := testing.AllocsPerRun(100, func() {
+		solids = w.AppendActiveSolidAABBs(solids[:0])
+	}); allocs != 0 {
+		t.Errorf("AppendActiveSolidAABBs allocated %v times reusing its slice, want 0", allocs)
+	}
+	if len(solids) != 2 {
+		t.Errorf("reused slice holds %d solids, want 2", len(solids))
+	}
+}
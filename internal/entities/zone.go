@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/physics"
+)
+
+// Zone is the shape of a trigger within its bounds, from a Tiled polygon or
+// ellipse object: the player is only inside the trigger inside the shape.
+// The zero Zone is the whole bounds.
+type Zone struct {
+	Polygon []mathx.Vec2 // Relative to the bounds' top left; needs 3 points
+	Ellipse bool         // The ellipse filling the bounds, e.g. a round trigger
+}
+
+// Zoned is a trigger that can be limited to a Zone.
+type Zoned interface {
+	SetZone(z Zone)
+}
+
+// IsZero reports whether the zone is the whole bounds.
+func (z Zone) IsZero() bool {
+	return len(z.Polygon) < 3 && !z.Ellipse
+}
+
+// Overlaps reports whether aabb touches the zone of a trigger with the
+// given bounds.
+func (z Zone) Overlaps(bounds, aabb physics.AABB) bool {
+	switch {
+	case len(z.Polygon) >= 3:
+		return mathx.PolygonIntersectsRect(z.Polygon, aabb.X-bounds.X, aabb.Y-bounds.Y, aabb.W, aabb.H)
+	case z.Ellipse:
+		return mathx.EllipseIntersectsRect(bounds.X, bounds.Y, bounds.W, bounds.H, aabb.X, aabb.Y, aabb.W, aabb.H)
+	}
+	return aabb.Intersects(bounds)
+}
+
+// fit drops a polygon with too few points and grows bounds to fit the rest.
+func (z *Zone) fit(bounds *physics.AABB) {
+	if len(z.Polygon) < 3 {
+		z.Polygon = nil
+		return
+	}
+	_, hi := mathx.PointsBounds(z.Polygon)
+	bounds.W, bounds.H = max(bounds.W, hi.X), max(bounds.H, hi.Y)
+}
@@ -0,0 +1,27 @@
+//go:build display
+
+package entities
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+)
+
+func TestEllipseCheckpointOnlyTriggersInsideCircle(t *testing.T) {
+	checkpoint := NewCheckpoint(0, 0, 64, 64, "round")
+	checkpoint.SetZone(Zone{Ellipse: true})
+	reached := 0
+	checkpoint.OnActivate = func(id string, x, y float64) { reached++ }
+
+	w := NewEntityWorld()
+	w.AddTrigger(checkpoint)
+	corner := &physics.Body{PosX: -4, PosY: -4, W: 12, H: 12} // In the bounds' corner
+	if w.CheckTriggers(corner); reached != 0 {
+		t.Fatal("player in the corner reached the round checkpoint")
+	}
+	edge := &physics.Body{PosX: 56, PosY: 26, W: 12, H: 12}
+	if w.CheckTriggers(edge); reached != 1 {
+		t.Fatalf("player at the circle's edge: reached %d times, want 1", reached)
+	}
+}
@@ -16,6 +16,15 @@ type Tuning struct {
 
 	// Gravity parameters
 	Gravity GravityTuning
+
+	// Health parameters
+	Health HealthTuning
+
+	// Crouch parameters
+	Crouch CrouchTuning
+
+	// Death sequence parameters
+	Death DeathTuning
 }
 
 // HorizontalTuning controls left/right movement feel.
@@ -40,6 +49,11 @@ type HorizontalTuning struct {
 	// Applied to acceleration when in air.
 	// 1 = full air control, 0 = no air control.
 	AirControl float64
+
+	// MaxStepHeight is the tallest step (pixels) a walking player climbs or
+	// steps down without jumping or falling, for tile seams, stairs and
+	// slightly raised platforms. 0 disables it.
+	MaxStepHeight float64
 }
 
 // JumpTuning controls jump behavior.
@@ -61,6 +75,29 @@ type JumpTuning struct {
 	// EarlyReleaseMult is the gravity multiplier when jump button is released early.
 	// Higher = faster fall when button released early.
 	EarlyReleaseMult float64
+
+	// ApexThreshold is the vertical speed (pixels/second) below which a held
+	// jump counts as near its apex, where the apex modifiers apply.
+	// 0 disables them.
+	ApexThreshold float64
+
+	// ApexGravityMult is the gravity multiplier near the apex.
+	// < 1 = floatier hang time, 1 = no change.
+	ApexGravityMult float64
+
+	// ApexSpeedBonus is added to the max horizontal speed near the apex
+	// (pixels/second), for a little extra reach at the top of a jump.
+	ApexSpeedBonus float64
+
+	// CornerCorrection is how far (pixels) a rising player is nudged
+	// sideways around a ceiling corner they clip, instead of bonking.
+	// 0 disables it.
+	CornerCorrection float64
+
+	// LedgeForgiveness is how far (pixels) below a ledge's top an airborne
+	// player moving into it may be and still be lifted onto it.
+	// 0 disables it.
+	LedgeForgiveness float64
 }
 
 // GravityTuning controls falling behavior.
@@ -78,28 +115,91 @@ type GravityTuning struct {
 	MaxFall float64
 }
 
+// HealthTuning controls the optional health system.
+type HealthTuning struct {
+	// MaxHP is the player's hit points per life.
+	// 0 disables health: any damage kills instantly.
+	MaxHP int
+
+	// Invulnerability is how long the player can't be hurt again after a hit.
+	Invulnerability time.Duration
+
+	// Knockback is the horizontal speed away from the damage source (pixels/second).
+	Knockback float64
+
+	// KnockbackLift is the vertical speed on hit (pixels/second, negative = up).
+	KnockbackLift float64
+}
+
+// CrouchTuning controls crouching and crawling.
+type CrouchTuning struct {
+	// HeightMult is the player's height while crouched, as a fraction of
+	// their standing height. The feet stay in place.
+	HeightMult float64
+
+	// SpeedMult is the max horizontal speed multiplier while crouched.
+	SpeedMult float64
+}
+
+// DeathTuning controls the death and respawn sequence. Controls are locked
+// from death until the player respawns.
+type DeathTuning struct {
+	// HoldTime is how long the camera holds on the spot the player died
+	// while the death burst plays.
+	HoldTime time.Duration
+
+	// FadeOutTime is how long the screen then takes to fade to black before
+	// the player respawns. 0 skips the fade and glides the camera back.
+	FadeOutTime time.Duration
+
+	// FadeInTime is how long the screen takes to fade back in after the
+	// respawn.
+	FadeInTime time.Duration
+}
+
 // DefaultTuning returns tuning parameters with good default feel.
 // These values are based on common platformer conventions and can be tweaked.
 func DefaultTuning() Tuning {
 	return Tuning{
 		Horizontal: HorizontalTuning{
-			Acceleration: 1200.0, // Fast acceleration
-			Deceleration: 800.0,  // Quick stop
-			MaxSpeed:     150.0,  // Reasonable max speed
-			Friction:     0.15,   // Some ground friction
-			AirControl:   0.6,    // Reduced air control
+			Acceleration:  1200.0, // Fast acceleration
+			Deceleration:  800.0,  // Quick stop
+			MaxSpeed:      150.0,  // Reasonable max speed
+			Friction:      0.15,   // Some ground friction
+			AirControl:    0.6,    // Reduced air control
+			MaxStepHeight: 4,      // Walk over 4px steps
 		},
 		Jump: JumpTuning{
-			Velocity:         -280.0,            // Good jump height (negative = up)
+			Velocity:         -280.0,                 // Good jump height (negative = up)
 			CoyoteTime:       100 * time.Millisecond, // 100ms coyote time
 			BufferTime:       100 * time.Millisecond, // 100ms jump buffer
-			VariableHeight:   true,              // Enable variable jump height
-			EarlyReleaseMult: 2.5,               // Fall faster when released
+			VariableHeight:   true,                   // Enable variable jump height
+			EarlyReleaseMult: 2.5,                    // Fall faster when released
+			ApexThreshold:    0,                      // No apex hang time
+			ApexGravityMult:  1,                      // Full gravity at the apex
+			ApexSpeedBonus:   0,                      // No extra apex speed
+			CornerCorrection: 4,                      // Slip past ceiling corners up to 4px
+			LedgeForgiveness: 4,                      // Catch ledges missed by up to 4px
 		},
 		Gravity: GravityTuning{
-			Base:    900.0, // Moderate gravity
-			FallMult: 1.5,  // Faster falling
-			MaxFall: 400.0, // Terminal velocity
+			Base:     900.0, // Moderate gravity
+			FallMult: 1.5,   // Faster falling
+			MaxFall:  400.0, // Terminal velocity
+		},
+		Health: HealthTuning{
+			MaxHP:           0,                       // Instant death unless enabled
+			Invulnerability: 1000 * time.Millisecond, // 1s of i-frames after a hit
+			Knockback:       180.0,                   // Pushed away from the source
+			KnockbackLift:   -160.0,                  // Small hop on hit
+		},
+		Crouch: CrouchTuning{
+			HeightMult: 0.5, // Half height
+			SpeedMult:  0.4, // Slow crawl
+		},
+		Death: DeathTuning{
+			HoldTime:    600 * time.Millisecond, // Watch the burst
+			FadeOutTime: 400 * time.Millisecond, // Respawn 1s after death
+			FadeInTime:  300 * time.Millisecond, // Quick fade back in
 		},
 	}
 }
@@ -0,0 +1,211 @@
+package gameplay
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// VarCollectibles is the blackboard counter collectibles add to unless their
+// object names another with world.PropCollectibleCounter.
+const VarCollectibles = "collectibles"
+
+// HUDVarsProperty is the map property listing the blackboard variables the
+// HUD shows, separated by commas.
+const HUDVarsProperty = "hudVars"
+
+// SavedVarsProperty is the map property listing the blackboard variables
+// kept in the player's save between runs, separated by commas.
+const SavedVarsProperty = "savedVars"
+
+// Blackboard is a store of named gameplay variables (counters, flags and
+// text) shared by entities, rules, the HUD, checkpoints and the save (see
+// SavedVarsProperty). Values are int, float64, bool or string. Listeners
+// added with OnChange hear about every change, so a rule can open a door
+// once three gems are collected without any code for it (see
+// ConnectBlackboard).
+type Blackboard struct {
+	values    map[string]any
+	listeners []func(name string, old, value any)
+}
+
+// NewBlackboard creates an empty blackboard.
+func NewBlackboard() *Blackboard {
+	return &Blackboard{values: make(map[string]any)}
+}
+
+// OnChange adds a listener called after a variable changes, with its old
+// value (nil if it was unset) and its new one (nil if it was deleted).
+// Setting a variable to the value it already has is not a change.
+func (b *Blackboard) OnChange(fn func(name string, old, value any)) {
+	b.listeners = append(b.listeners, fn)
+}
+
+// set stores value, or deletes the variable if value is nil, and notifies
+// the listeners if that changed anything.
+func (b *Blackboard) set(name string, value any) {
+	old, had := b.values[name]
+	if had && old == value || !had && value == nil {
+		return
+	}
+	if value == nil {
+		delete(b.values, name)
+	} else {
+		b.values[name] = value
+	}
+	for _, fn := range b.listeners {
+		fn(name, old, value)
+	}
+}
+
+// SetInt sets an integer variable.
+func (b *Blackboard) SetInt(name string, v int) { b.set(name, v) }
+
+// SetFloat sets a number variable.
+func (b *Blackboard) SetFloat(name string, v float64) { b.set(name, v) }
+
+// SetBool sets a flag.
+func (b *Blackboard) SetBool(name string, v bool) { b.set(name, v) }
+
+// SetString sets a text variable.
+func (b *Blackboard) SetString(name string, v string) { b.set(name, v) }
+
+// Add adds delta to an integer counter, starting from 0 if it is unset, and
+// returns the new count.
+func (b *Blackboard) Add(name string, delta int) int {
+	n := b.Int(name) + delta
+	b.SetInt(name, n)
+	return n
+}
+
+// Delete unsets a variable.
+func (b *Blackboard) Delete(name string) {
+	b.set(name, nil)
+}
+
+// Get returns a variable's value and whether it is set. It implements
+// rules.Variables.
+func (b *Blackboard) Get(name string) (any, bool) {
+	v, ok := b.values[name]
+	return v, ok
+}
+
+// Int returns an integer variable, with numbers truncated. Unset and
+// non-number variables are 0.
+func (b *Blackboard) Int(name string) int {
+	switch v := b.values[name].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// Float returns a number variable. Unset and non-number variables are 0.
+func (b *Blackboard) Float(name string) float64 {
+	switch v := b.values[name].(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// Bool returns a flag. Unset and non-bool variables are false.
+func (b *Blackboard) Bool(name string) bool {
+	v, _ := b.values[name].(bool)
+	return v
+}
+
+// String returns a text variable. Unset and non-string variables are "".
+func (b *Blackboard) String(name string) string {
+	v, _ := b.values[name].(string)
+	return v
+}
+
+// Names returns the names of the set variables, sorted.
+func (b *Blackboard) Names() []string {
+	return slices.Sorted(maps.Keys(b.values))
+}
+
+// HUDLines returns a "name: value" line for each of the map's HUDVarsProperty
+// variables, unset ones included.
+func (b *Blackboard) HUDLines(mapProps map[string]any) []string {
+	list, _ := mapProps[HUDVarsProperty].(string)
+	var lines []string
+	for _, name := range world.ParseTargetList(list) {
+		v, ok := b.Get(name)
+		if !ok {
+			v = 0
+		}
+		lines = append(lines, fmt.Sprintf("%s: %v", name, v))
+	}
+	return lines
+}
+
+// LoadSaved sets the map's SavedVarsProperty variables to their values in
+// saved (see save.Data.Vars), as read back from JSON: whole numbers become
+// int variables. Variables saved has no value for are left alone.
+func (b *Blackboard) LoadSaved(mapProps map[string]any, saved map[string]any) {
+	list, _ := mapProps[SavedVarsProperty].(string)
+	for _, name := range world.ParseTargetList(list) {
+		switch v := saved[name].(type) {
+		case float64:
+			if v == math.Trunc(v) {
+				b.SetInt(name, int(v))
+			} else {
+				b.SetFloat(name, v)
+			}
+		case int, bool, string:
+			b.set(name, v)
+		}
+	}
+}
+
+// StoreSaved copies the map's SavedVarsProperty variables into saved,
+// deleting the ones that are unset.
+func (b *Blackboard) StoreSaved(mapProps map[string]any, saved map[string]any) {
+	list, _ := mapProps[SavedVarsProperty].(string)
+	for _, name := range world.ParseTargetList(list) {
+		if v, ok := b.values[name]; ok {
+			saved[name] = v
+		} else {
+			delete(saved, name)
+		}
+	}
+}
+
+// BlackboardSnapshot is the saved state of a Blackboard.
+type BlackboardSnapshot struct {
+	values map[string]any
+}
+
+// Snapshot saves every variable.
+func (b *Blackboard) Snapshot() BlackboardSnapshot {
+	return BlackboardSnapshot{values: maps.Clone(b.values)}
+}
+
+// Restore puts back the variables saved by Snapshot. Listeners aren't
+// notified: restoring is going back in time, not gameplay.
+func (b *Blackboard) Restore(s BlackboardSnapshot) {
+	b.values = maps.Clone(s.values)
+	if b.values == nil {
+		b.values = make(map[string]any)
+	}
+}
+
+// ConnectBlackboard lets engine's when.vars conditions read b and sends
+// every change to a variable to engine as a var_changed event, with the
+// variable's name as its region.
+func ConnectBlackboard(b *Blackboard, engine *rules.Engine) {
+	engine.SetVariables(b)
+	b.OnChange(func(name string, _, _ any) {
+		engine.ProcessEvent(rules.NewEvent(rules.EventVarChanged, name, ""))
+	})
+}
@@ -0,0 +1,131 @@
+//go:build display
+
+package gameplay
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestBlackboardNotifiesChanges(t *testing.T) {
+	b := NewBlackboard()
+	var changes []string
+	b.OnChange(func(name string, old, value any) {
+		changes = append(changes, name)
+	})
+
+	b.Add("gems", 1)
+	b.Add("gems", 2)
+	b.SetInt("gems", 3) // Unchanged
+	b.SetBool("boss_seen", true)
+	b.Delete("boss_seen")
+	b.Delete("never_set")
+
+	if want := []string{"gems", "gems", "boss_seen", "boss_seen"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+	if b.Int("gems") != 3 || b.Float("gems") != 3 || b.Bool("boss_seen") {
+		t.Errorf("gems = %d, boss_seen = %v, want 3 and false", b.Int("gems"), b.Bool("boss_seen"))
+	}
+}
+
+func TestBlackboardSnapshotRestore(t *testing.T) {
+	b := NewBlackboard()
+	b.SetString("phase", "intro")
+	snap := b.Snapshot()
+
+	b.SetString("phase", "boss")
+	b.Add("gems", 5)
+	b.Restore(snap)
+
+	if b.String("phase") != "intro" {
+		t.Errorf("phase = %q after restore, want intro", b.String("phase"))
+	}
+	if _, ok := b.Get("gems"); ok {
+		t.Error("gems still set after restoring a snapshot from before it")
+	}
+}
+
+func TestBlackboardSavedVars(t *testing.T) {
+	props := map[string]any{SavedVarsProperty: "gems, speed, boss_seen, door"}
+	saved := map[string]any{"gems": 3.0, "speed": 1.5, "boss_seen": true, "lives": 2.0, "door": []any{"red"}}
+
+	b := NewBlackboard()
+	b.LoadSaved(props, saved)
+	if v, _ := b.Get("gems"); v != 3 {
+		t.Errorf("gems = %#v, want int 3", v)
+	}
+	if b.Float("speed") != 1.5 || !b.Bool("boss_seen") {
+		t.Errorf("speed = %v, boss_seen = %v, want 1.5 and true", b.Float("speed"), b.Bool("boss_seen"))
+	}
+	if names := b.Names(); !reflect.DeepEqual(names, []string{"boss_seen", "gems", "speed"}) {
+		t.Errorf("loaded %v; want neither lives, which the level doesn't save, nor door, which isn't a variable", names)
+	}
+
+	b.Add("gems", 2)
+	b.Delete("boss_seen")
+	b.SetString("door", "blue")
+	b.Add("collectibles", 1)
+	b.StoreSaved(props, saved)
+	want := map[string]any{"gems": 5, "speed": 1.5, "lives": 2.0, "door": "blue"}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved = %v, want %v", saved, want)
+	}
+}
+
+func TestBlackboardHUDLines(t *testing.T) {
+	b := NewBlackboard()
+	b.Add("gems", 2)
+	got := b.HUDLines(map[string]any{HUDVarsProperty: "gems, lives"})
+	if want := []string{"gems: 2", "lives: 0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HUDLines = %v, want %v", got, want)
+	}
+}
+
+func TestCollectiblesCountTowardRules(t *testing.T) {
+	w := entities.NewEntityWorld()
+	vars := NewBlackboard()
+	objects := []world.ObjectData{
+		{Type: world.ObjectTypeDoor, X: 200, W: 16, H: 48, Props: map[string]any{"id": "door1"}},
+	}
+	for i := 0; i < 3; i++ {
+		objects = append(objects, world.ObjectData{Type: world.ObjectTypeCollectible, X: float64(i * 40), W: 12, H: 12,
+			Props: map[string]any{world.PropCollectibleCounter: "gems"}})
+	}
+	_, triggers, solids, _, _ := SpawnEntities(objects, SpawnContext{Registry: w.TargetRegistry, Vars: vars})
+	for _, tr := range triggers {
+		w.AddTrigger(tr)
+	}
+	for _, s := range solids {
+		w.AddSolidEntity(s)
+	}
+
+	engine := rules.NewEngine(NewTargetResolver(w.TargetRegistry))
+	engine.LoadRules([]rules.Rule{{
+		ID:      "gems_open_door",
+		When:    rules.WhenClause{Event: rules.EventVarChanged, Region: "gems", Vars: map[string]any{"gems": ">= 3"}},
+		Actions: []rules.ActionSpec{{Type: rules.ActionActivate, Target: "door1"}},
+	}})
+	ConnectBlackboard(vars, engine)
+
+	door := w.TargetRegistry.Resolve("door1").(*entities.Door)
+	player := &physics.Body{W: 8, H: 8}
+	for i := 0; i < 3; i++ {
+		if door.IsOpen() {
+			t.Fatalf("door opened after %d gems", i)
+		}
+		player.PosX = float64(i*40 + 2)
+		w.CheckTriggers(player)
+	}
+	if vars.Int("gems") != 3 {
+		t.Errorf("gems = %d, want 3", vars.Int("gems"))
+	}
+	if !door.IsOpen() {
+		t.Error("collecting 3 gems didn't open the door")
+	}
+}
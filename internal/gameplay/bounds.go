@@ -0,0 +1,58 @@
+package gameplay
+
+import (
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// ApplyLevelBounds enforces the level bounds policy on a body.
+// Clamp and wrap policies adjust the body in place.
+// Returns true if the body fell below an active kill line.
+func ApplyLevelBounds(body *physics.Body, b world.LevelBounds) bool {
+	if b.Kill && body.PosY > b.KillY {
+		return true
+	}
+
+	switch b.Policy {
+	case world.BoundsClamp:
+		if body.PosX < 0 {
+			body.PosX = 0
+			if body.VelX < 0 {
+				body.VelX = 0
+			}
+		} else if body.PosX+body.W > b.Width {
+			body.PosX = b.Width - body.W
+			if body.VelX > 0 {
+				body.VelX = 0
+			}
+		}
+		if body.PosY < 0 {
+			body.PosY = 0
+			if body.VelY < 0 {
+				body.VelY = 0
+			}
+		} else if body.PosY+body.H > b.Height {
+			// The bottom edge acts as a floor
+			body.PosY = b.Height - body.H
+			if body.VelY > 0 {
+				body.VelY = 0
+			}
+			body.OnGround = true
+		}
+
+	case world.BoundsWrap:
+		// Wrap once the body has fully left the level
+		if body.PosX+body.W < 0 {
+			body.PosX += b.Width + body.W
+		} else if body.PosX > b.Width {
+			body.PosX -= b.Width + body.W
+		}
+		if body.PosY+body.H < 0 {
+			body.PosY += b.Height + body.H
+		} else if body.PosY > b.Height {
+			body.PosY -= b.Height + body.H
+		}
+	}
+
+	return false
+}
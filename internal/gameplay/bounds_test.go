@@ -0,0 +1,147 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestApplyLevelBounds(t *testing.T) {
+	bounds := func(policy world.BoundsPolicy, kill bool) world.LevelBounds {
+		return world.LevelBounds{Policy: policy, Width: 320, Height: 240, KillY: 272, Kill: kill}
+	}
+	tests := []struct {
+		name   string
+		bounds world.LevelBounds
+		body   physics.Body
+		want   physics.Body
+		killed bool
+	}{
+		{
+			name:   "kill inside the level",
+			bounds: bounds(world.BoundsKill, true),
+			body:   physics.Body{PosX: 100, PosY: 100, VelX: 50, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 100, VelX: 50, W: 16, H: 16},
+		},
+		{
+			name:   "kill past the side edge",
+			bounds: bounds(world.BoundsKill, true),
+			body:   physics.Body{PosX: -40, PosY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: -40, PosY: 100, W: 16, H: 16},
+		},
+		{
+			name:   "kill below the kill line",
+			bounds: bounds(world.BoundsKill, true),
+			body:   physics.Body{PosX: 100, PosY: 273, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 273, W: 16, H: 16},
+			killed: true,
+		},
+		{
+			name:   "kill on the kill line",
+			bounds: bounds(world.BoundsKill, true),
+			body:   physics.Body{PosX: 100, PosY: 272, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 272, W: 16, H: 16},
+		},
+		{
+			name:   "clamp left edge",
+			bounds: bounds(world.BoundsClamp, false),
+			body:   physics.Body{PosX: -5, PosY: 100, VelX: -50, VelY: 10, W: 16, H: 16},
+			want:   physics.Body{PosX: 0, PosY: 100, VelX: 0, VelY: 10, W: 16, H: 16},
+		},
+		{
+			name:   "clamp right edge",
+			bounds: bounds(world.BoundsClamp, false),
+			body:   physics.Body{PosX: 310, PosY: 100, VelX: 50, W: 16, H: 16},
+			want:   physics.Body{PosX: 304, PosY: 100, W: 16, H: 16},
+		},
+		{
+			name:   "clamp keeps velocity away from the edge",
+			bounds: bounds(world.BoundsClamp, false),
+			body:   physics.Body{PosX: 310, PosY: 100, VelX: -50, W: 16, H: 16},
+			want:   physics.Body{PosX: 304, PosY: 100, VelX: -50, W: 16, H: 16},
+		},
+		{
+			name:   "clamp top edge",
+			bounds: bounds(world.BoundsClamp, false),
+			body:   physics.Body{PosX: 100, PosY: -8, VelY: -100, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 0, W: 16, H: 16},
+		},
+		{
+			name:   "clamp bottom edge is a floor",
+			bounds: bounds(world.BoundsClamp, false),
+			body:   physics.Body{PosX: 100, PosY: 230, VelY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 224, W: 16, H: 16, OnGround: true},
+		},
+		{
+			name:   "clamp corner",
+			bounds: bounds(world.BoundsClamp, false),
+			body:   physics.Body{PosX: -5, PosY: 230, VelX: -50, VelY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: 0, PosY: 224, W: 16, H: 16, OnGround: true},
+		},
+		{
+			name:   "wrap partly past the left edge",
+			bounds: bounds(world.BoundsWrap, false),
+			body:   physics.Body{PosX: -10, PosY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: -10, PosY: 100, W: 16, H: 16},
+		},
+		{
+			name:   "wrap past the left edge",
+			bounds: bounds(world.BoundsWrap, false),
+			body:   physics.Body{PosX: -17, PosY: 100, VelX: -50, W: 16, H: 16},
+			want:   physics.Body{PosX: 319, PosY: 100, VelX: -50, W: 16, H: 16},
+		},
+		{
+			name:   "wrap past the right edge",
+			bounds: bounds(world.BoundsWrap, false),
+			body:   physics.Body{PosX: 321, PosY: 100, VelX: 50, W: 16, H: 16},
+			want:   physics.Body{PosX: -15, PosY: 100, VelX: 50, W: 16, H: 16},
+		},
+		{
+			name:   "wrap past the top edge",
+			bounds: bounds(world.BoundsWrap, false),
+			body:   physics.Body{PosX: 100, PosY: -20, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 236, W: 16, H: 16},
+		},
+		{
+			name:   "wrap past the bottom edge",
+			bounds: bounds(world.BoundsWrap, false),
+			body:   physics.Body{PosX: 100, PosY: 250, VelY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: -6, VelY: 100, W: 16, H: 16},
+		},
+		{
+			// A kill plane under clamp: the kill line wins over the floor
+			name:   "kill line before clamp",
+			bounds: bounds(world.BoundsClamp, true),
+			body:   physics.Body{PosX: 100, PosY: 280, VelY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 280, VelY: 100, W: 16, H: 16},
+			killed: true,
+		},
+		{
+			name:   "kill line before wrap",
+			bounds: bounds(world.BoundsWrap, true),
+			body:   physics.Body{PosX: 100, PosY: 280, VelY: 100, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: 280, VelY: 100, W: 16, H: 16},
+			killed: true,
+		},
+		{
+			name:   "wrap above the kill line",
+			bounds: bounds(world.BoundsWrap, true),
+			body:   physics.Body{PosX: 100, PosY: 250, W: 16, H: 16},
+			want:   physics.Body{PosX: 100, PosY: -6, W: 16, H: 16},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.body
+			if killed := ApplyLevelBounds(&body, tt.bounds); killed != tt.killed {
+				t.Errorf("ApplyLevelBounds = %v, want %v", killed, tt.killed)
+			}
+			if body != tt.want {
+				t.Errorf("body = %+v, want %+v", body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+//go:build display
+
+package gameplay
+
+import (
+	"os"
+	"testing"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// The built-in level's switches open their doors through door_id and through
+// its rules file; the two mustn't cancel each other out.
+func TestBuiltinLevelSwitchesOpenDoors(t *testing.T) {
+	const level = "../../assets/levels/level_01.json"
+	data, err := os.ReadFile(level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := world.ParseTiledJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleData, err := os.ReadFile(rules.PathForLevel(level))
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, err := world.ParseObjects(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := entities.NewEntityWorld()
+	_, triggers, solids, _, _ := SpawnEntities(objects, SpawnContext{Registry: w.TargetRegistry})
+	for _, tr := range triggers {
+		w.AddTrigger(tr)
+	}
+	for _, s := range solids {
+		w.AddSolidEntity(s)
+	}
+	engine := rules.NewEngine(NewTargetResolver(w.TargetRegistry))
+	ConnectRules(w, engine)
+	if err := LoadLevelRules(engine, m.Properties(), ruleData); err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range objects {
+		if obj.Type != world.ObjectTypeSwitch {
+			continue
+		}
+		w.CheckTriggers(&physics.Body{PosX: obj.X + 1, PosY: obj.Y + 1, W: 4, H: 4})
+		w.CheckTriggers(&physics.Body{PosX: -1000, PosY: -1000, W: 4, H: 4})
+		door, _ := w.FindDoorByID(obj.GetPropString("door_id", "")).(*entities.Door)
+		if door == nil || !door.IsOpen() {
+			t.Errorf("switch %s leaves its door closed", obj.Name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package gameplay
+
+import (
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/rules"
+)
+
+// CheckpointState is the level state saved when a checkpoint activates, so
+// respawning there undoes everything since: doors opened, switches used,
+// keys and collectibles picked up, gameplay variables changed and rules
+// fired.
+type CheckpointState struct {
+	// ID is the checkpoint the state was saved at ("" for the level start)
+	ID string
+
+	world    *entities.WorldSnapshot
+	progress ProgressSnapshot
+	vars     BlackboardSnapshot
+	rules    rules.EngineSnapshot
+}
+
+// SaveCheckpoint saves the world, progress, variable and rule state for a
+// checkpoint. progress, vars and engine may be nil.
+func SaveCheckpoint(id string, w *entities.EntityWorld, progress *LevelProgress, vars *Blackboard, engine *rules.Engine) *CheckpointState {
+	c := &CheckpointState{ID: id, world: w.Snapshot()}
+	if progress != nil {
+		c.progress = progress.Snapshot()
+	}
+	if vars != nil {
+		c.vars = vars.Snapshot()
+	}
+	if engine != nil {
+		c.rules = engine.Snapshot()
+	}
+	return c
+}
+
+// Restore puts the world, progress, variables and rule firings back into the
+// saved state. A nil CheckpointState does nothing.
+func (c *CheckpointState) Restore(w *entities.EntityWorld, progress *LevelProgress, vars *Blackboard, engine *rules.Engine) {
+	if c == nil {
+		return
+	}
+	w.Restore(c.world)
+	if progress != nil {
+		progress.Restore(c.progress)
+	}
+	if vars != nil {
+		vars.Restore(c.vars)
+	}
+	if engine != nil {
+		engine.Restore(c.rules)
+	}
+}
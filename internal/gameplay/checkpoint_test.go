@@ -0,0 +1,67 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestCheckpointRestoresRuleFirings(t *testing.T) {
+	w := entities.NewEntityWorld()
+	door := entities.NewDoor(100, 0, 16, 48, "door1")
+	w.AddSolidEntity(door)
+	plate := entities.NewGoal(0, 0, 16, 16)
+	w.AddTrigger(plate)
+	w.SetSource(plate, world.ObjectData{Name: "plate"})
+
+	engine := rules.NewEngine(NewTargetResolver(w.TargetRegistry))
+	engine.LoadRules([]rules.Rule{
+		{ID: "open", Once: true, When: rules.WhenClause{Event: rules.EventEnterRegion, Region: "plate"},
+			Actions: []rules.ActionSpec{{Type: "activate", Target: "door1"}}},
+	})
+	ConnectRules(w, engine)
+	checkpoint := SaveCheckpoint("cp1", w, nil, nil, engine)
+
+	player := &physics.Body{PosX: 2, PosY: 2, W: 8, H: 8}
+	enter := func() {
+		player.PosX = 2
+		w.CheckTriggers(player)
+		player.PosX = 50
+		w.CheckTriggers(player)
+	}
+	enter()
+	if !door.IsOpen() {
+		t.Fatal("stepping on the plate didn't open the door")
+	}
+
+	// Respawning closes the door again and the once rule opens it again
+	for i := range 2 {
+		checkpoint.Restore(w, nil, nil, engine)
+		if door.IsOpen() {
+			t.Fatalf("respawn %d: door still open", i+1)
+		}
+		enter()
+		if !door.IsOpen() {
+			t.Fatalf("respawn %d: once rule fired before the checkpoint didn't fire again", i+1)
+		}
+	}
+
+	// Saved after the rule fired, the checkpoint keeps it spent
+	checkpoint = SaveCheckpoint("cp2", w, nil, nil, engine)
+	door.Deactivate()
+	enter()
+	if door.IsOpen() {
+		t.Error("once rule fired again before any respawn")
+	}
+	checkpoint.Restore(w, nil, nil, engine)
+	door.Deactivate()
+	enter()
+	if door.IsOpen() {
+		t.Error("respawning at a checkpoint saved after the rule fired let it fire again")
+	}
+}
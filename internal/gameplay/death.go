@@ -0,0 +1,28 @@
+package gameplay
+
+import (
+	"image/color"
+
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/physics"
+)
+
+// Death burst look
+const (
+	deathBurstCount   = 16
+	deathBurstSpeed   = 160.0 // pixels/second
+	deathBurstGravity = 400.0 // pixels/second²
+)
+
+// deathBurstColor is the color of the death burst's particles.
+var deathBurstColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+// NewDeathBurst creates the particle burst shown where the player died. It
+// fades out over the death sequence's camera hold.
+func NewDeathBurst(player *physics.Body, t game.DeathTuning) *gfx.Burst {
+	b := gfx.NewBurst(player.PosX+player.W/2, player.PosY+player.H/2,
+		deathBurstCount, deathBurstSpeed, t.HoldTime, deathBurstColor)
+	b.Gravity = deathBurstGravity
+	return b
+}
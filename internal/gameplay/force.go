@@ -0,0 +1,24 @@
+package gameplay
+
+import (
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// ForceZones returns the force zones of a level's force zone objects, for
+// the player controller's Zones.
+func ForceZones(objects []world.ObjectData) []physics.ForceZone {
+	var zones []physics.ForceZone
+	for _, obj := range objects {
+		if obj.Type != world.ObjectTypeForceZone {
+			continue
+		}
+		zones = append(zones, physics.ForceZone{
+			Area:         physics.AABB{X: obj.X, Y: obj.Y, W: obj.W, H: obj.H},
+			GravityScale: obj.GetPropFloat(world.PropForceGravityScale, world.DefaultForceGravityScale),
+			WindX:        obj.GetPropFloat(world.PropForceWindX, 0),
+			WindY:        obj.GetPropFloat(world.PropForceWindY, 0),
+		})
+	}
+	return zones
+}
@@ -0,0 +1,33 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestForceZones(t *testing.T) {
+	objects := []world.ObjectData{
+		{Type: world.ObjectTypeForceZone, X: 10, Y: 20, W: 30, H: 40, Props: map[string]any{
+			world.PropForceGravityScale: 0.3,
+			world.PropForceWindY:        -500.0,
+		}},
+		{Type: world.ObjectTypeForceZone},
+		{Type: world.ObjectTypeHazard},
+	}
+	zones := ForceZones(objects)
+
+	if len(zones) != 2 {
+		t.Fatalf("got %d zones, want 2", len(zones))
+	}
+	want := physics.ForceZone{Area: physics.AABB{X: 10, Y: 20, W: 30, H: 40}, GravityScale: 0.3, WindY: -500}
+	if zones[0] != want {
+		t.Errorf("zone = %+v, want %+v", zones[0], want)
+	}
+	if zones[1].GravityScale != world.DefaultForceGravityScale || zones[1].WindX != 0 || zones[1].WindY != 0 {
+		t.Errorf("zone without properties = %+v, want normal gravity and no wind", zones[1])
+	}
+}
@@ -0,0 +1,146 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// goalLevel is a spawned level with two checkpoints, three collectibles and
+// a goal, recording what the goal reports.
+type goalLevel struct {
+	progress     *LevelProgress
+	checkpoints  []*entities.Checkpoint
+	collectibles []*entities.Collectible
+	goal         *entities.Goal
+	player       *physics.Body
+
+	completed int
+	blocked   []string
+}
+
+// newGoalLevel spawns the level with the goal's properties set to props.
+func newGoalLevel(t *testing.T, props map[string]any) *goalLevel {
+	t.Helper()
+	objects := []world.ObjectData{
+		{ID: 1, Type: world.ObjectTypeCheckpoint, X: 0, Y: 0, W: 16, H: 32, Props: map[string]any{"id": "cp_1"}},
+		{ID: 2, Type: world.ObjectTypeCheckpoint, X: 32, Y: 0, W: 16, H: 32},
+		{ID: 3, Type: world.ObjectTypeCollectible, X: 64, Y: 0, W: 8, H: 8},
+		{ID: 4, Type: world.ObjectTypeCollectible, X: 80, Y: 0, W: 8, H: 8},
+		{ID: 5, Type: world.ObjectTypeCollectible, X: 96, Y: 0, W: 8, H: 8},
+		{ID: 6, Type: world.ObjectTypeGoal, X: 128, Y: 0, W: 16, H: 32, Props: props},
+	}
+	l := &goalLevel{progress: NewLevelProgress(objects), player: &physics.Body{W: 8, H: 8}}
+	_, triggers, _, _, _ := SpawnEntities(objects, SpawnContext{
+		Progress:      l.progress,
+		OnGoalReached: func() { l.completed++ },
+		OnGoalBlocked: func(reason string) { l.blocked = append(l.blocked, reason) },
+	})
+	for _, trigger := range triggers {
+		switch e := trigger.(type) {
+		case *entities.Checkpoint:
+			l.checkpoints = append(l.checkpoints, e)
+		case *entities.Collectible:
+			l.collectibles = append(l.collectibles, e)
+		case *entities.Goal:
+			l.goal = e
+		}
+	}
+	if len(l.checkpoints) != 2 || len(l.collectibles) != 3 || l.goal == nil {
+		t.Fatalf("spawned %d checkpoints, %d collectibles and goal %v", len(l.checkpoints), len(l.collectibles), l.goal)
+	}
+	return l
+}
+
+// touchGoal enters and leaves the goal.
+func (l *goalLevel) touchGoal() {
+	l.goal.OnEnter(l.player)
+	l.goal.OnExit(l.player)
+}
+
+func TestGoalRequirements(t *testing.T) {
+	tests := []struct {
+		name   string
+		props  map[string]any
+		locked bool               // Whether the goal starts locked
+		unmet  func(l *goalLevel) // Gets part of the way
+		reason string             // Why the goal is blocked after unmet
+		met    func(l *goalLevel) // Meets the requirement
+	}{
+		{
+			name:   "all checkpoints",
+			props:  map[string]any{world.PropRequireCheckpoints: true},
+			locked: true,
+			unmet:  func(l *goalLevel) { l.checkpoints[0].OnEnter(l.player) },
+			reason: "Checkpoints 1/2",
+			met: func(l *goalLevel) {
+				// The first one again doesn't count twice
+				l.checkpoints[0].OnEnter(l.player)
+				l.checkpoints[1].OnEnter(l.player)
+			},
+		},
+		{
+			name:   "collectibles",
+			props:  map[string]any{world.PropRequiredCollectibles: 2.0},
+			locked: true,
+			unmet: func(l *goalLevel) {
+				l.collectibles[0].OnEnter(l.player)
+				l.collectibles[0].OnEnter(l.player)
+			},
+			reason: "Collectibles 1/2",
+			met:    func(l *goalLevel) { l.collectibles[2].OnEnter(l.player) },
+		},
+		{
+			name:   "par time",
+			props:  map[string]any{world.PropParTime: 30.0},
+			unmet:  func(l *goalLevel) { l.progress.Update(31) },
+			reason: "Par time 30s exceeded",
+			met:    func(l *goalLevel) { l.progress.Elapsed = 29.5 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newGoalLevel(t, tt.props)
+			if l.goal.Locked() != tt.locked {
+				t.Errorf("goal locked %v at the start, want %v", l.goal.Locked(), tt.locked)
+			}
+
+			// Touched with the requirement unmet, the goal reports why and
+			// stays in the level
+			tt.unmet(l)
+			l.touchGoal()
+			if l.completed != 0 || len(l.blocked) != 1 || l.blocked[0] != tt.reason {
+				t.Fatalf("unmet: completed %d times, blocked %q; want blocked %q", l.completed, l.blocked, tt.reason)
+			}
+			if !l.goal.IsActive() || !l.goal.Locked() {
+				t.Error("blocked goal isn't active and locked")
+			}
+
+			tt.met(l)
+			if l.goal.Locked() {
+				t.Error("goal still locked with the requirement met")
+			}
+			l.touchGoal()
+			if l.completed != 1 || len(l.blocked) != 1 {
+				t.Errorf("met: completed %d times, blocked %q; want completed once", l.completed, l.blocked)
+			}
+		})
+	}
+}
+
+func TestGoalWithoutRequirements(t *testing.T) {
+	l := newGoalLevel(t, nil)
+	l.progress.Update(1000)
+	if l.goal.Locked() {
+		t.Error("goal without requirements is locked")
+	}
+	l.touchGoal()
+	l.touchGoal()
+	if l.completed != 1 || len(l.blocked) != 0 {
+		t.Errorf("completed %d times, blocked %q; want completed once", l.completed, l.blocked)
+	}
+}
@@ -0,0 +1,95 @@
+package gameplay
+
+import (
+	"math"
+
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/physics"
+)
+
+// blinkRate is how often the player blinks per second while invulnerable.
+const blinkRate = 10.0
+
+// Health tracks the player's hit points and invulnerability after a hit.
+// With MaxHP 0 health is disabled and every hit is fatal.
+type Health struct {
+	HP     int
+	Tuning game.HealthTuning
+
+	invulnerable float64 // Seconds of invulnerability left
+}
+
+// NewHealth creates full health for the tuning.
+func NewHealth(t game.HealthTuning) *Health {
+	return &Health{HP: t.MaxHP, Tuning: t}
+}
+
+// Enabled returns whether the health system is on.
+func (h *Health) Enabled() bool {
+	return h.Tuning.MaxHP > 0
+}
+
+// Max returns the maximum hit points.
+func (h *Health) Max() int {
+	return h.Tuning.MaxHP
+}
+
+// Damage takes amount hit points and starts the invulnerability window.
+// It reports whether the hit landed and whether it was fatal. Hits while
+// invulnerable, or of no damage, are ignored. With health disabled every
+// hit that lands is fatal.
+func (h *Health) Damage(amount int) (hurt, dead bool) {
+	if amount <= 0 || h.invulnerable > 0 {
+		return false, false
+	}
+	if !h.Enabled() {
+		return true, true
+	}
+
+	h.HP -= amount
+	if h.HP <= 0 {
+		h.HP = 0
+		return true, true
+	}
+	h.invulnerable = h.Tuning.Invulnerability.Seconds()
+	return true, false
+}
+
+// Update counts down the invulnerability window.
+func (h *Health) Update(dt float64) {
+	if h.invulnerable > 0 {
+		h.invulnerable = math.Max(0, h.invulnerable-dt)
+	}
+}
+
+// Reset restores full health, e.g. on respawn.
+func (h *Health) Reset() {
+	h.HP = h.Tuning.MaxHP
+	h.invulnerable = 0
+}
+
+// Invulnerable returns whether hits are currently ignored.
+func (h *Health) Invulnerable() bool {
+	return h.invulnerable > 0
+}
+
+// Visible returns whether the player should be drawn this frame. The player
+// blinks while invulnerable.
+func (h *Health) Visible() bool {
+	if h.invulnerable <= 0 {
+		return true
+	}
+	return math.Mod(h.invulnerable*blinkRate, 1) < 0.5
+}
+
+// Knockback pushes body away from the damage source: sideways at Knockback
+// speed and up by KnockbackLift.
+func (h *Health) Knockback(body *physics.Body, source physics.AABB) {
+	dir := 1.0
+	if body.PosX+body.W/2 < source.X+source.W/2 {
+		dir = -1
+	}
+	body.VelX = dir * h.Tuning.Knockback
+	body.VelY = h.Tuning.KnockbackLift
+	body.OnGround = false
+}
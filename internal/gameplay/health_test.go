@@ -0,0 +1,80 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/physics"
+)
+
+func testHealth() *Health {
+	t := game.DefaultTuning().Health
+	t.MaxHP = 3
+	t.Invulnerability = 500 * time.Millisecond
+	return NewHealth(t)
+}
+
+func TestHealthDisabledKillsOnHit(t *testing.T) {
+	h := NewHealth(game.HealthTuning{})
+	if hurt, dead := h.Damage(1); !hurt || !dead {
+		t.Fatalf("Damage with health disabled = (%v, %v), want (true, true)", hurt, dead)
+	}
+}
+
+func TestHealthInvulnerability(t *testing.T) {
+	h := testHealth()
+
+	if hurt, dead := h.Damage(1); !hurt || dead {
+		t.Fatalf("first hit = (%v, %v), want (true, false)", hurt, dead)
+	}
+	if hurt, _ := h.Damage(1); hurt {
+		t.Fatal("hit landed during invulnerability")
+	}
+	if h.HP != 2 {
+		t.Fatalf("HP = %d, want 2", h.HP)
+	}
+
+	for i := 0; i < 31; i++ {
+		h.Update(1.0 / 60)
+	}
+	if h.Invulnerable() || !h.Visible() {
+		t.Fatal("still invulnerable after the window")
+	}
+	if hurt, _ := h.Damage(1); !hurt {
+		t.Fatal("hit after invulnerability didn't land")
+	}
+}
+
+func TestHealthDeathAndReset(t *testing.T) {
+	h := testHealth()
+
+	if _, dead := h.Damage(5); !dead {
+		t.Fatal("overkill hit wasn't fatal")
+	}
+	if h.HP != 0 {
+		t.Fatalf("HP = %d, want 0", h.HP)
+	}
+
+	h.Reset()
+	if h.HP != 3 || h.Invulnerable() {
+		t.Fatalf("after Reset: HP %d, invulnerable %v; want 3, false", h.HP, h.Invulnerable())
+	}
+	if hurt, _ := h.Damage(0); hurt {
+		t.Fatal("zero damage landed")
+	}
+}
+
+func TestHealthKnockbackAwayFromSource(t *testing.T) {
+	h := testHealth()
+	body := &physics.Body{PosX: 0, PosY: 0, W: 12, H: 12, OnGround: true}
+
+	h.Knockback(body, physics.AABB{X: 10, Y: 0, W: 16, H: 16})
+
+	if body.VelX != -h.Tuning.Knockback || body.VelY != h.Tuning.KnockbackLift || body.OnGround {
+		t.Fatalf("knockback velocity (%v, %v), grounded %v; want (%v, %v), false",
+			body.VelX, body.VelY, body.OnGround, -h.Tuning.Knockback, h.Tuning.KnockbackLift)
+	}
+}
@@ -0,0 +1,144 @@
+package gameplay
+
+import (
+	"fmt"
+
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// DefaultFadeTime is how long a room transition takes to fade out, and
+// again to fade back in, in seconds.
+const DefaultFadeTime = 0.3
+
+// RoomLoader switches the scene to a room. data is the level to build,
+// or nil when the exit leads elsewhere in the current level; the player
+// goes to the spawn with the given id ("" = the first spawn).
+type RoomLoader func(path string, data []byte, spawn string) error
+
+// transitionPhase is the part of a room transition in progress.
+type transitionPhase int
+
+const (
+	phaseIdle transitionPhase = iota
+	phaseFadeOut
+	phaseFadeIn
+)
+
+// LevelManager runs the transitions between the rooms of a multi-room
+// level. Touching an exit starts one with Transition: the screen fades
+// out, the manager reads the exit's level and hands it to the scene's
+// RoomLoader, and the screen fades back in. Scenes call Update every
+// physics tick, skip gameplay while Busy, and draw FadeAlpha over the world.
+type LevelManager struct {
+	// FadeTime is the length of each fade, in seconds
+	FadeTime float64
+	// ReadFile reads a level file; nil uses storage.ReadFile
+	ReadFile func(path string) ([]byte, error)
+
+	load  RoomLoader
+	path  string // Current level
+	phase transitionPhase
+	timer float64
+
+	// Pending transition
+	target string
+	spawn  string
+}
+
+// NewLevelManager creates a manager for a scene playing the level at path,
+// switching rooms with load.
+func NewLevelManager(path string, load RoomLoader) *LevelManager {
+	return &LevelManager{
+		FadeTime: DefaultFadeTime,
+		load:     load,
+		path:     path,
+	}
+}
+
+// Path returns the current level's path.
+func (m *LevelManager) Path() string {
+	return m.path
+}
+
+// SetPath changes the current level's path without a transition, e.g. when
+// the scene loads another level directly.
+func (m *LevelManager) SetPath(path string) {
+	m.path = path
+}
+
+// Transition starts going to the spawn with the given id in level (relative
+// to the current level, "" = the current level). It returns false if a
+// transition is already running.
+func (m *LevelManager) Transition(level, spawn string) bool {
+	if m.Busy() {
+		return false
+	}
+	m.target = world.ResolveLevelPath(m.path, level)
+	m.spawn = spawn
+	m.phase = phaseFadeOut
+	m.timer = 0
+	return true
+}
+
+// Busy returns whether a transition is running.
+func (m *LevelManager) Busy() bool {
+	return m.phase != phaseIdle
+}
+
+// Update advances the transition by dt seconds. Once the screen is black it
+// loads the target room; if that fails the player stays in the current one
+// and the error is returned. The fade back in runs either way.
+func (m *LevelManager) Update(dt float64) error {
+	switch m.phase {
+	case phaseFadeOut:
+		m.timer += dt
+		if m.timer < m.FadeTime {
+			return nil
+		}
+		m.phase, m.timer = phaseFadeIn, 0
+		return m.switchRoom()
+	case phaseFadeIn:
+		m.timer += dt
+		if m.timer >= m.FadeTime {
+			m.phase, m.timer = phaseIdle, 0
+		}
+	}
+	return nil
+}
+
+// switchRoom loads the pending transition's room.
+func (m *LevelManager) switchRoom() error {
+	var data []byte
+	if m.target != m.path {
+		read := m.ReadFile
+		if read == nil {
+			read = storage.ReadFile
+		}
+		var err error
+		if data, err = read(m.target); err != nil {
+			return fmt.Errorf("room %s: %w", m.target, err)
+		}
+	}
+	if err := m.load(m.target, data, m.spawn); err != nil {
+		return fmt.Errorf("room %s: %w", m.target, err)
+	}
+	m.path = m.target
+	return nil
+}
+
+// FadeAlpha returns how dark the transition has made the screen, from 0
+// (not at all) to 1 (black).
+func (m *LevelManager) FadeAlpha() float64 {
+	if m.FadeTime <= 0 {
+		return 0
+	}
+	t := min(m.timer/m.FadeTime, 1)
+	switch m.phase {
+	case phaseFadeOut:
+		return t
+	case phaseFadeIn:
+		return 1 - t
+	}
+	return 0
+}
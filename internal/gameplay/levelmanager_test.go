@@ -0,0 +1,85 @@
+//go:build display
+
+package gameplay
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestLevelManagerTransition(t *testing.T) {
+	hall := filepath.Join("levels", "hall.json")
+	tower := filepath.Join("levels", "tower.json")
+
+	var loaded []string
+	var loadedData []byte
+	m := NewLevelManager(hall, func(path string, data []byte, spawn string) error {
+		loaded = append(loaded, path+"@"+spawn)
+		loadedData = data
+		return nil
+	})
+	m.FadeTime = 0.5
+	m.ReadFile = func(path string) ([]byte, error) {
+		if path != tower {
+			return nil, errors.New("not found")
+		}
+		return []byte("tower"), nil
+	}
+
+	if !m.Transition("tower.json", "from_hall") {
+		t.Fatal("Transition refused while idle")
+	}
+	if m.Transition("cellar.json", "") {
+		t.Error("Transition started a second transition while busy")
+	}
+
+	// Fading out: nothing loaded until the screen is black
+	m.Update(0.25)
+	if len(loaded) != 0 || m.FadeAlpha() != 0.5 {
+		t.Fatalf("halfway through the fade out: loaded %v, alpha %v", loaded, m.FadeAlpha())
+	}
+	if err := m.Update(0.25); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || loaded[0] != tower+"@from_hall" || string(loadedData) != "tower" {
+		t.Fatalf("loaded %v with %q, want the tower at from_hall", loaded, loadedData)
+	}
+	if m.Path() != tower || m.FadeAlpha() != 1 {
+		t.Errorf("after loading: path %q, alpha %v, want %q and 1", m.Path(), m.FadeAlpha(), tower)
+	}
+
+	// Fading back in
+	m.Update(0.5)
+	if m.Busy() || m.FadeAlpha() != 0 {
+		t.Errorf("still busy (alpha %v) after fading back in", m.FadeAlpha())
+	}
+
+	// Exits within the level don't read a file
+	m.Transition("", "balcony")
+	m.Update(0.5)
+	if loaded[1] != tower+"@balcony" || loadedData != nil {
+		t.Errorf("same-level exit loaded %v with %q, want the tower at balcony and no data", loaded[1], loadedData)
+	}
+}
+
+func TestLevelManagerMissingRoom(t *testing.T) {
+	hall := filepath.Join("levels", "hall.json")
+	m := NewLevelManager(hall, func(string, []byte, string) error {
+		t.Error("loaded a room that couldn't be read")
+		return nil
+	})
+	m.ReadFile = func(string) ([]byte, error) { return nil, errors.New("not found") }
+
+	m.Transition("cellar.json", "")
+	if err := m.Update(m.FadeTime); err == nil {
+		t.Error("Update didn't report the missing room")
+	}
+	if m.Path() != hall {
+		t.Errorf("path = %q after a failed transition, want %q", m.Path(), hall)
+	}
+	m.Update(m.FadeTime)
+	if m.Busy() {
+		t.Error("still busy after a failed transition faded back in")
+	}
+}
@@ -0,0 +1,60 @@
+package gameplay
+
+import (
+	"math"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/music"
+	"github.com/torsten/GoP/internal/physics"
+)
+
+// NearHazardRange is the distance in pixels at which hazards start raising
+// the near-hazard music signal. The signal reaches full strength on contact.
+const NearHazardRange = 96.0
+
+// RegisterMusicTargets registers the mixer's signals as rule targets
+// ("music.boss_arena", "music.low_hp", ...), so rules can raise and drop
+// music layers with the regular activate/deactivate/toggle actions.
+func RegisterMusicTargets(registry *entities.TargetRegistry, m *music.Mixer) {
+	for _, t := range m.SignalTargets() {
+		registry.Register(t)
+	}
+}
+
+// UpdateMusic drives the mixer's gameplay signals and advances its crossfade.
+// While running, the near-hazard signal follows the distance to the closest
+// active hazard. On death the near-hazard signal drops; on completion all
+// signals reset so the music settles back to the base stem.
+func UpdateMusic(m *music.Mixer, sm *StateMachine, player *physics.Body, w *entities.EntityWorld, dt float64) {
+	switch sm.Current {
+	case StateRunning:
+		m.SetSignal(music.SignalNearHazard, hazardProximity(player, w))
+	case StateDead, StateRespawning:
+		m.SetSignal(music.SignalNearHazard, 0)
+	case StateCompleted:
+		m.ResetSignals()
+	}
+	m.Update(dt)
+}
+
+// hazardProximity returns 1 when the player touches an active hazard,
+// falling off linearly to 0 at NearHazardRange.
+func hazardProximity(player *physics.Body, w *entities.EntityWorld) float64 {
+	best := 0.0
+	for _, t := range w.Triggers() {
+		h, ok := t.(*entities.Hazard)
+		if !ok || !h.IsActive() {
+			continue
+		}
+		d := aabbDistance(player.AABB(), h.Bounds())
+		best = math.Max(best, 1-d/NearHazardRange)
+	}
+	return best
+}
+
+// aabbDistance returns the gap between two boxes, or 0 if they overlap.
+func aabbDistance(a, b physics.AABB) float64 {
+	dx := math.Max(0, math.Max(b.X-(a.X+a.W), a.X-(b.X+b.W)))
+	dy := math.Max(0, math.Max(b.Y-(a.Y+a.H), a.Y-(b.Y+b.H)))
+	return math.Hypot(dx, dy)
+}
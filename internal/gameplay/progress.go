@@ -0,0 +1,123 @@
+package gameplay
+
+import (
+	"sort"
+
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// LevelProgress tracks what the player has achieved in the current attempt,
+// for checking goal completion requirements.
+type LevelProgress struct {
+	// Checkpoints is the number of checkpoints in the level
+	Checkpoints int
+	// Collectibles is the number of collectibles in the level
+	Collectibles int
+	// Collected is the number of collectibles picked up
+	Collected int
+	// Keys is the number of keys in the level
+	Keys int
+	// Elapsed is the play time in seconds
+	Elapsed float64
+	// Deaths is the number of times the player died
+	Deaths int
+
+	reached map[string]bool
+	keys    []string // IDs of keys held ("" for keys without an ID)
+}
+
+// NewLevelProgress creates progress tracking for a level's objects.
+func NewLevelProgress(objects []world.ObjectData) *LevelProgress {
+	return &LevelProgress{
+		Checkpoints:  len(world.FilterObjectsByType(objects, world.ObjectTypeCheckpoint)),
+		Collectibles: len(world.FilterObjectsByType(objects, world.ObjectTypeCollectible)),
+		Keys:         len(world.FilterObjectsByType(objects, world.ObjectTypeKey)),
+		reached:      make(map[string]bool),
+	}
+}
+
+// Update advances the play timer.
+func (p *LevelProgress) Update(dt float64) {
+	p.Elapsed += dt
+}
+
+// ReachCheckpoint records a checkpoint as reached. Repeat visits count once.
+func (p *LevelProgress) ReachCheckpoint(id string) {
+	p.reached[id] = true
+}
+
+// CheckpointsReached returns the number of distinct checkpoints reached.
+func (p *LevelProgress) CheckpointsReached() int {
+	return len(p.reached)
+}
+
+// Collect records a picked-up collectible.
+func (p *LevelProgress) Collect() {
+	p.Collected++
+}
+
+// PickUpKey records a key as held.
+func (p *LevelProgress) PickUpKey(id string) {
+	p.keys = append(p.keys, id)
+}
+
+// KeysHeld returns the number of keys held.
+func (p *LevelProgress) KeysHeld() int {
+	return len(p.keys)
+}
+
+// UseKeys gives up the keys a lock needs and reports whether they were held:
+// the key with the given ID, or any n keys when id is empty. Keys without an
+// ID are used before named ones. Nothing is given up if the keys aren't held.
+func (p *LevelProgress) UseKeys(n int, id string) bool {
+	if id != "" {
+		for i, k := range p.keys {
+			if k == id {
+				p.keys = append(p.keys[:i], p.keys[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+	if len(p.keys) < n {
+		return false
+	}
+
+	// Unnamed keys first, so named keys stay available for their doors
+	sort.SliceStable(p.keys, func(i, j int) bool { return p.keys[i] == "" && p.keys[j] != "" })
+	p.keys = p.keys[n:]
+	return true
+}
+
+// ProgressSnapshot is the saved collection state of a LevelProgress.
+type ProgressSnapshot struct {
+	collected int
+	keys      []string
+}
+
+// Snapshot saves the collectibles and keys picked up so far. Reached
+// checkpoints and the timer aren't part of it.
+func (p *LevelProgress) Snapshot() ProgressSnapshot {
+	return ProgressSnapshot{collected: p.Collected, keys: append([]string(nil), p.keys...)}
+}
+
+// Restore puts back the collectibles and keys saved by Snapshot.
+func (p *LevelProgress) Restore(s ProgressSnapshot) {
+	p.Collected = s.collected
+	p.keys = append([]string(nil), s.keys...)
+}
+
+// Unmet returns why the requirements aren't met yet, or "" if the goal can complete.
+func (p *LevelProgress) Unmet(req world.GoalRequirements) string {
+	if req.ParTime > 0 && p.Elapsed > req.ParTime {
+		return i18n.T("goal.parTime", req.ParTime)
+	}
+	if req.AllCheckpoints && p.CheckpointsReached() < p.Checkpoints {
+		return i18n.T("goal.checkpoints", p.CheckpointsReached(), p.Checkpoints)
+	}
+	if p.Collected < req.Collectibles {
+		return i18n.T("goal.collectibles", p.Collected, req.Collectibles)
+	}
+	return ""
+}
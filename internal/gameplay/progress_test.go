@@ -0,0 +1,63 @@
+//go:build display
+
+package gameplay
+
+import "testing"
+
+func TestUseKeysByCount(t *testing.T) {
+	p := NewLevelProgress(nil)
+	p.PickUpKey("")
+	if p.UseKeys(2, "") {
+		t.Fatal("UseKeys(2) succeeded with 1 key held")
+	}
+	if p.KeysHeld() != 1 {
+		t.Fatalf("a failed UseKeys used up keys: %d held", p.KeysHeld())
+	}
+	p.PickUpKey("")
+	if !p.UseKeys(2, "") || p.KeysHeld() != 0 {
+		t.Errorf("UseKeys(2) with 2 keys: held %d after", p.KeysHeld())
+	}
+}
+
+func TestUseKeysByID(t *testing.T) {
+	p := NewLevelProgress(nil)
+	p.PickUpKey("red")
+	if p.UseKeys(1, "blue") {
+		t.Error("UseKeys accepted the wrong key")
+	}
+	if !p.UseKeys(1, "red") || p.KeysHeld() != 0 {
+		t.Error("UseKeys did not use the red key")
+	}
+}
+
+func TestUseKeysPrefersUnnamed(t *testing.T) {
+	p := NewLevelProgress(nil)
+	p.PickUpKey("red")
+	p.PickUpKey("")
+	if !p.UseKeys(1, "") {
+		t.Fatal("UseKeys(1) failed with 2 keys held")
+	}
+	if !p.UseKeys(1, "red") {
+		t.Error("the red key was used for a door that takes any key")
+	}
+}
+
+func TestProgressRestore(t *testing.T) {
+	p := NewLevelProgress(nil)
+	p.PickUpKey("red")
+	p.Collect()
+	snap := p.Snapshot()
+
+	p.PickUpKey("")
+	p.Collect()
+	p.UseKeys(1, "red")
+	p.ReachCheckpoint("cp2")
+
+	p.Restore(snap)
+	if p.Collected != 1 || p.KeysHeld() != 1 || !p.UseKeys(1, "red") {
+		t.Errorf("after Restore: collected %d, keys %d; want 1 and the red key", p.Collected, p.KeysHeld())
+	}
+	if p.CheckpointsReached() != 1 {
+		t.Errorf("Restore changed reached checkpoints: %d, want 1", p.CheckpointsReached())
+	}
+}
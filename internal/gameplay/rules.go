@@ -0,0 +1,135 @@
+package gameplay
+
+import (
+	"fmt"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/rules"
+)
+
+// RulesProperty is the map property that embeds rules in a level, as YAML
+// text in the rules file format.
+const RulesProperty = "rules"
+
+// LoadLevelRules loads a level's rules into engine: first those embedded in
+// the map's RulesProperty, then those in fileData, the level's rules file.
+// Either may be missing.
+func LoadLevelRules(engine *rules.Engine, mapProps map[string]any, fileData []byte) error {
+	ruleSet, err := ParseLevelRules(mapProps, fileData)
+	if err != nil {
+		return err
+	}
+	engine.LoadRuleSet(ruleSet)
+	return nil
+}
+
+// ParseLevelRules parses a level's rules as LoadLevelRules loads them, for
+// rules.Engine.Reload.
+func ParseLevelRules(mapProps map[string]any, fileData []byte) (rules.RuleSet, error) {
+	var ruleSet rules.RuleSet
+	if v, ok := mapProps[RulesProperty]; ok {
+		text, ok := v.(string)
+		if !ok {
+			return rules.RuleSet{}, fmt.Errorf("map property %q must be a string, got %T", RulesProperty, v)
+		}
+		embedded, err := rules.ParseYAML([]byte(text))
+		if err != nil {
+			return rules.RuleSet{}, fmt.Errorf("embedded rules: %w", err)
+		}
+		ruleSet.Rules = append(ruleSet.Rules, embedded.Rules...)
+	}
+	if len(fileData) > 0 {
+		file, err := rules.ParseYAML(fileData)
+		if err != nil {
+			return rules.RuleSet{}, err
+		}
+		ruleSet.Rules = append(ruleSet.Rules, file.Rules...)
+	}
+	return ruleSet, nil
+}
+
+// ConnectRules sends the player entering and leaving w's triggers to engine
+// as enter_region and exit_region events.
+func ConnectRules(w *entities.EntityWorld, engine *rules.Engine) {
+	w.OnTriggerEvent = func(id string, entered bool) {
+		typ := rules.EventExitRegion
+		if entered {
+			typ = rules.EventEnterRegion
+		}
+		engine.ProcessEvent(rules.NewEvent(typ, id, "player"))
+	}
+}
+
+// targetResolver adapts entities.TargetRegistry to rules.TargetResolver and
+// rules.GroupResolver.
+type targetResolver struct {
+	registry *entities.TargetRegistry
+}
+
+// NewTargetResolver creates a rules.TargetResolver backed by the given registry.
+func NewTargetResolver(registry *entities.TargetRegistry) rules.TargetResolver {
+	return &targetResolver{registry: registry}
+}
+
+// Resolve implements rules.TargetResolver.
+func (r *targetResolver) Resolve(id string) rules.Targetable {
+	target := r.registry.Resolve(id)
+	if target == nil {
+		return nil
+	}
+	// Return a wrapper that adapts entities.Targetable to rules.Targetable
+	return &targetableAdapter{target: target}
+}
+
+// ResolveGroup implements rules.GroupResolver using the registry's tags.
+func (r *targetResolver) ResolveGroup(group string) []rules.Targetable {
+	return adaptTargets(r.registry.ResolveTag(group))
+}
+
+// ResolvePattern implements rules.GroupResolver.
+func (r *targetResolver) ResolvePattern(pattern string) []rules.Targetable {
+	return adaptTargets(r.registry.ResolvePattern(pattern))
+}
+
+// adaptTargets wraps each of targets in a targetableAdapter.
+func adaptTargets(targets []entities.Targetable) []rules.Targetable {
+	result := make([]rules.Targetable, len(targets))
+	for i, t := range targets {
+		result[i] = &targetableAdapter{target: t}
+	}
+	return result
+}
+
+// targetableAdapter adapts entities.Targetable to rules.Targetable.
+type targetableAdapter struct {
+	target entities.Targetable
+}
+
+// Activate implements rules.Targetable.
+func (a *targetableAdapter) Activate() {
+	a.target.Activate()
+}
+
+// Deactivate implements rules.Targetable.
+func (a *targetableAdapter) Deactivate() {
+	a.target.Deactivate()
+}
+
+// Toggle implements rules.Targetable.
+func (a *targetableAdapter) Toggle() {
+	a.target.Toggle()
+}
+
+// TargetID implements rules.Targetable.
+func (a *targetableAdapter) TargetID() string {
+	return a.target.TargetID()
+}
+
+// TargetState implements rules.StateReporter, passing through the entity's
+// state. Entities without one report "", which no when.states entry matches.
+func (a *targetableAdapter) TargetState() string {
+	if r, ok := a.target.(rules.StateReporter); ok {
+		return r.TargetState()
+	}
+	return ""
+}
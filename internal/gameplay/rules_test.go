@@ -0,0 +1,102 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestLoadLevelRules(t *testing.T) {
+	engine := rules.NewEngine(nil)
+	props := map[string]any{RulesProperty: "rules:\n  - id: embedded\n    when: {event: death}\n"}
+	file := []byte("rules:\n  - id: from_file\n    when: {event: enter_region}\n")
+	if err := LoadLevelRules(engine, props, file); err != nil {
+		t.Fatalf("LoadLevelRules failed: %v", err)
+	}
+	if got := engine.Rules(); len(got) != 2 || got[0].ID != "embedded" || got[1].ID != "from_file" {
+		t.Errorf("loaded %v, want the embedded rule then the file's", got)
+	}
+
+	if err := LoadLevelRules(rules.NewEngine(nil), map[string]any{RulesProperty: 3.0}, nil); err == nil {
+		t.Error("LoadLevelRules accepted a non-string rules property")
+	}
+	if err := LoadLevelRules(rules.NewEngine(nil), nil, []byte("rules: [")); err == nil {
+		t.Error("LoadLevelRules accepted a broken rules file")
+	}
+}
+
+func TestParseLevelRules(t *testing.T) {
+	props := map[string]any{RulesProperty: "rules:\n  - id: embedded\n    when: {event: death}\n"}
+	file := []byte("rules:\n  - id: from_file\n    when: {event: enter_region}\n")
+	ruleSet, err := ParseLevelRules(props, file)
+	if err != nil {
+		t.Fatalf("ParseLevelRules failed: %v", err)
+	}
+	if len(ruleSet.Rules) != 2 || ruleSet.Rules[0].ID != "embedded" || ruleSet.Rules[1].ID != "from_file" {
+		t.Errorf("parsed %v, want the embedded rule then the file's", ruleSet.Rules)
+	}
+	if _, err := ParseLevelRules(props, []byte("rules: [")); err == nil {
+		t.Error("ParseLevelRules accepted a broken rules file")
+	}
+}
+
+func TestConnectRules(t *testing.T) {
+	w := entities.NewEntityWorld()
+	door := entities.NewDoor(100, 0, 16, 48, "door1")
+	w.AddSolidEntity(door)
+	goal := entities.NewGoal(0, 0, 16, 16)
+	w.AddTrigger(goal)
+	w.SetSource(goal, world.ObjectData{Name: "exit"})
+
+	engine := rules.NewEngine(NewTargetResolver(w.TargetRegistry))
+	engine.LoadRules([]rules.Rule{
+		{ID: "enter", When: rules.WhenClause{Event: rules.EventEnterRegion, Region: "exit"}, Actions: []rules.ActionSpec{{Type: "activate", Target: "door1"}}},
+		{ID: "exit", When: rules.WhenClause{Event: rules.EventExitRegion, Region: "exit"}, Actions: []rules.ActionSpec{{Type: "deactivate", Target: "door1"}}},
+	})
+	ConnectRules(w, engine)
+
+	player := &physics.Body{PosX: 2, PosY: 2, W: 8, H: 8}
+	w.CheckTriggers(player)
+	if !door.IsOpen() {
+		t.Error("entering the goal named exit didn't fire the enter_region rule")
+	}
+	player.PosX = 50
+	w.CheckTriggers(player)
+	if door.IsOpen() {
+		t.Error("leaving the goal didn't fire the exit_region rule")
+	}
+}
+
+func TestRulesTargetSpawnedTags(t *testing.T) {
+	w := entities.NewEntityWorld()
+	objects := []world.ObjectData{
+		{Type: world.ObjectTypeDoor, W: 16, H: 48, Props: map[string]any{"id": "door_1", world.PropTags: "wave1, exits"}},
+		{Type: world.ObjectTypeDoor, X: 50, W: 16, H: 48, Props: map[string]any{"id": "door_2"}},
+		{Type: world.ObjectTypeDoor, X: 100, W: 16, H: 48, Props: map[string]any{"id": "gate", world.PropTags: "wave1"}},
+	}
+	_, _, solids, _, _ := SpawnEntities(objects, SpawnContext{Registry: w.TargetRegistry})
+	for _, s := range solids {
+		w.AddSolidEntity(s)
+	}
+
+	ctx := rules.NewActionContext(rules.Event{}, NewTargetResolver(w.TargetRegistry))
+	if err := rules.ExecuteAction(ctx, rules.ActionSpec{Type: rules.ActionActivate, Group: "wave1"}); err != nil {
+		t.Fatalf("group action failed: %v", err)
+	}
+	open := func(id string) bool { return w.TargetRegistry.Resolve(id).(*entities.Door).IsOpen() }
+	if !open("door_1") || open("door_2") || !open("gate") {
+		t.Errorf("group wave1 opened door_1=%v door_2=%v gate=%v, want door_1 and gate", open("door_1"), open("door_2"), open("gate"))
+	}
+
+	if err := rules.ExecuteAction(ctx, rules.ActionSpec{Type: rules.ActionActivate, Target: "door_*"}); err != nil {
+		t.Fatalf("pattern action failed: %v", err)
+	}
+	if !open("door_2") {
+		t.Error("target door_* didn't open door_2")
+	}
+}
@@ -0,0 +1,34 @@
+package gameplay
+
+import (
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/sound"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// NewSoundscape creates the soundscape of a level's sound emitter objects.
+// Emitters sit at their object's center.
+func NewSoundscape(objects []world.ObjectData) *sound.Soundscape {
+	s := sound.NewSoundscape()
+	for _, obj := range objects {
+		if obj.Type != world.ObjectTypeSound {
+			continue
+		}
+		s.Add(&sound.Emitter{
+			Clip:    obj.GetPropString(world.PropSoundClip, ""),
+			X:       obj.X + obj.W/2,
+			Y:       obj.Y + obj.H/2,
+			Radius:  obj.GetPropFloat(world.PropSoundRadius, world.DefaultSoundRadius),
+			Volume:  obj.GetPropFloat(world.PropSoundVolume, world.DefaultSoundVolume),
+			Falloff: sound.ParseFalloff(obj.GetPropString(world.PropSoundFalloff, "")),
+		})
+	}
+	return s
+}
+
+// UpdateSound mixes the soundscape for the player, who hears it from the
+// center of their body.
+func UpdateSound(s *sound.Soundscape, player *physics.Body) {
+	a := player.AABB()
+	s.Update(a.X+a.W/2, a.Y+a.H/2)
+}
@@ -0,0 +1,44 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/sound"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestNewSoundscape(t *testing.T) {
+	objects := []world.ObjectData{
+		{Type: world.ObjectTypeSound, X: 90, Y: 90, W: 20, H: 20, Props: map[string]any{
+			world.PropSoundClip:    "waterfall",
+			world.PropSoundRadius:  50.0,
+			world.PropSoundFalloff: "quadratic",
+		}},
+		{Type: world.ObjectTypeSound, Props: map[string]any{world.PropSoundFalloff: "none"}},
+		{Type: world.ObjectTypeLight},
+	}
+	s := NewSoundscape(objects)
+
+	emitters := s.Emitters()
+	if len(emitters) != 2 {
+		t.Fatalf("got %d emitters, want 2", len(emitters))
+	}
+	e := emitters[0]
+	if e.Clip != "waterfall" || e.X != 100 || e.Y != 100 || e.Radius != 50 || e.Volume != 1 || e.Falloff != sound.FalloffQuadratic {
+		t.Errorf("emitter = %+v, want waterfall at the object's center", *e)
+	}
+	if emitters[1].Radius != world.DefaultSoundRadius || emitters[1].Falloff != sound.FalloffNone {
+		t.Errorf("ambient emitter = %+v, want the default radius and no falloff", *emitters[1])
+	}
+
+	// The player hears from the center of their body
+	player := &physics.Body{PosX: 69, PosY: 94, W: 12, H: 12}
+	UpdateSound(s, player)
+	vol, pan := e.Mixed()
+	if vol != 0.25 || pan != 0.5 {
+		t.Errorf("mix 25px left of the emitter = %v, %v, want 0.25, 0.5", vol, pan)
+	}
+}
@@ -2,22 +2,73 @@ package gameplay
 
 import (
 	"fmt"
+	"image/color"
 
 	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/tween"
 	"github.com/torsten/GoP/internal/world"
 )
 
 // SpawnContext provides callbacks for entity spawning.
 type SpawnContext struct {
 	OnDeath       func()
+	OnDamage      func(amount int, source physics.AABB) bool // Optional; health system damage hook, returns whether the hit landed
 	OnCheckpoint  func(id string, x, y float64)
 	OnGoalReached func()
+	OnGoalBlocked func(reason string) // Player touched a goal whose requirements aren't met
+	OnCollect     func(id string)
+	OnKey         func(id string)           // Player picked up a key
+	OnLevelExit   func(level, spawn string) // Player entered an exit to another room (see LevelManager)
 	Registry      *entities.TargetRegistry
+	Skins         map[world.ObjectType]*entities.Skin           // Optional per-type skins from the level theme
+	Progress      *LevelProgress                                // Optional; enables goal requirements
+	Vars          *Blackboard                                   // Optional; counts collectibles (see world.PropCollectibleCounter)
+	Player        *physics.Body                                 // Optional; doors check it for obstruction on close
+	Blocked       func(physics.AABB) bool                       // Optional; reports level geometry for pushing the player out of doors
+	OnSpawn       func(e entities.Entity, obj world.ObjectData) // Optional; called with each entity and the object it came from
+}
+
+// Spawner creates the entity for an object of a registered type, or returns
+// nil to spawn nothing. SpawnEntities also returns the entity as a trigger,
+// solid entity or kinematic if it is one, and applies skins, tags and OnSpawn
+// as for built-in types; a spawner that wants to be targeted registers the
+// entity with ctx.Registry itself.
+type Spawner func(obj world.ObjectData, ctx SpawnContext) entities.Entity
+
+// spawners holds the spawners added with RegisterSpawner.
+var spawners = map[world.ObjectType]Spawner{}
+
+// RegisterSpawner makes SpawnEntities create objects of typ with spawn, so
+// games and tests can add entity types (enemies, custom triggers) without
+// changing this package. Built-in types always use their own spawning; a
+// registered spawner takes precedence over an object alias. A nil spawn
+// removes the registration.
+func RegisterSpawner(typ world.ObjectType, spawn Spawner) {
+	if spawn == nil {
+		delete(spawners, typ)
+		return
+	}
+	spawners[typ] = spawn
+}
+
+// objectAliases maps the types registered with RegisterObjectAlias to the
+// type their objects spawn as.
+var objectAliases = map[world.ObjectType]world.ObjectType{}
+
+// RegisterObjectAlias makes SpawnEntities spawn objects of typ as if they were
+// of type base, e.g. a project's "lava" objects as hazards. The editor
+// registers the spawnAs type of custom object types this way; games using
+// them call it before spawning a level.
+func RegisterObjectAlias(typ, base world.ObjectType) {
+	objectAliases[typ] = base
 }
 
 // SpawnEntities creates entities from object data and returns them.
 // Returns entities, triggers, solid entities, kinematics, and switches separately for the caller to add to the world.
+// Lights are only returned in the entity list; use AddLights to add them.
 func SpawnEntities(objects []world.ObjectData, ctx SpawnContext) ([]entities.Entity, []entities.Trigger, []entities.SolidEntity, []physics.Kinematic, []*entities.Switch) {
 	var entityList []entities.Entity
 	var triggers []entities.Trigger
@@ -26,12 +77,25 @@ func SpawnEntities(objects []world.ObjectData, ctx SpawnContext) ([]entities.Ent
 
 	// First pass: create all entities
 	var switches []*entities.Switch
+	platforms := make(map[string]*entities.MovingPlatform)
+	riders := make(map[*entities.Hazard]string) // hazard -> platform ID
 
 	for _, obj := range objects {
+		created := len(entityList)
+		if base, ok := objectAliases[obj.Type]; ok && spawners[obj.Type] == nil {
+			obj.Type = base
+		}
+
 		switch obj.Type {
 		case world.ObjectTypeHazard:
 			hazard := entities.NewHazard(obj.X, obj.Y, obj.W, obj.H)
+			hazard.SetDamage(int(obj.GetPropFloat("damage", 1)))
+			hazard.SetDirection(entities.ParseHazardDirection(obj.GetPropString("direction", "")))
 			hazard.OnDeath = ctx.OnDeath
+			hazard.OnDamage = ctx.OnDamage
+			if platformID := obj.GetPropString("platform", ""); platformID != "" {
+				riders[hazard] = platformID
+			}
 			triggers = append(triggers, hazard)
 			entityList = append(entityList, hazard)
 
@@ -39,28 +103,55 @@ func SpawnEntities(objects []world.ObjectData, ctx SpawnContext) ([]entities.Ent
 			id := obj.GetPropString("id", obj.Name)
 			checkpoint := entities.NewCheckpoint(obj.X, obj.Y, obj.W, obj.H, id)
 			checkpoint.OnActivate = ctx.OnCheckpoint
+			if ctx.Progress != nil {
+				// Track unnamed checkpoints by object ID so each counts once
+				key := id
+				if key == "" {
+					key = fmt.Sprintf("checkpoint_%d", obj.ID)
+				}
+				checkpoint.OnActivate = func(id string, x, y float64) {
+					ctx.Progress.ReachCheckpoint(key)
+					if ctx.OnCheckpoint != nil {
+						ctx.OnCheckpoint(id, x, y)
+					}
+				}
+			}
 			triggers = append(triggers, checkpoint)
 			entityList = append(entityList, checkpoint)
 
 		case world.ObjectTypeGoal:
 			goal := entities.NewGoal(obj.X, obj.Y, obj.W, obj.H)
 			goal.OnComplete = ctx.OnGoalReached
+			goal.OnBlocked = ctx.OnGoalBlocked
+			if req := world.NewGoalRequirements(obj); !req.IsZero() && ctx.Progress != nil {
+				progress := ctx.Progress
+				goal.Requirement = func() string {
+					return progress.Unmet(req)
+				}
+			}
 			triggers = append(triggers, goal)
 			entityList = append(entityList, goal)
 
+		case world.ObjectTypeExit:
+			level, spawn := world.ExitTarget(obj)
+			exit := entities.NewLevelExit(obj.X, obj.Y, obj.W, obj.H, level, spawn)
+			exit.OnUse = ctx.OnLevelExit
+			triggers = append(triggers, exit)
+			entityList = append(entityList, exit)
+
 		case world.ObjectTypeSwitch:
-			// Check both "target" and "door_id" properties for flexibility
-			targetID := obj.GetPropString("target", "")
-			if targetID == "" {
-				targetID = obj.GetPropString("door_id", "")
-			}
+			// Targets come from "target"/"door_id" plus the "targets" list
+			targetIDs := world.SwitchTargets(obj)
 			// Generate switch ID from name or object ID
 			switchID := obj.GetPropString("id", obj.Name)
 			if switchID == "" {
 				switchID = fmt.Sprintf("switch_%d", obj.ID)
 			}
-			sw := entities.NewSwitch(obj.X, obj.Y, obj.W, obj.H, targetID)
+			sw := entities.NewSwitch(obj.X, obj.Y, obj.W, obj.H, "")
+			sw.SetTargets(targetIDs)
 			sw.SetID(switchID)
+			sw.SetMode(entities.ParseSwitchMode(obj.GetPropString(world.PropSwitchMode, "")))
+			sw.SetDuration(obj.GetPropFloat(world.PropSwitchDuration, 3))
 			sw.SetToggleMode(obj.GetPropBool("toggle", true))
 			sw.SetOnce(obj.GetPropBool("once", false))
 			switches = append(switches, sw)
@@ -71,9 +162,23 @@ func SpawnEntities(objects []world.ObjectData, ctx SpawnContext) ([]entities.Ent
 			id := obj.GetPropString("id", obj.Name)
 			startOpen := obj.GetPropBool("startOpen", false)
 			door := entities.NewDoor(obj.X, obj.Y, obj.W, obj.H, id)
+			door.SetObstruction(entities.ParseDoorObstruction(obj.GetPropString("obstruction", "")))
+			door.Occupant = ctx.Player
+			door.Blocked = ctx.Blocked
+			if obj.GetPropBool("locked", false) {
+				door.SetLock(int(obj.GetPropFloat("keys", 1)), obj.GetPropString("key_id", ""))
+				if ctx.Progress != nil {
+					door.TryUnlock = ctx.Progress.UseKeys
+				}
+			}
 			if startOpen {
 				door.Open()
 			}
+			// Set after startOpen so doors start fully open
+			door.SetDirection(entities.ParseDoorDirection(obj.GetPropString("openDirection", "")))
+			door.SetOpenTime(obj.GetPropFloat("openTime", 0.25))
+			ease, _ := tween.ParseEase(obj.GetPropString(world.PropEase, ""))
+			door.SetEase(ease)
 			// Register door with registry if available
 			if ctx.Registry != nil {
 				ctx.Registry.Register(door)
@@ -100,22 +205,151 @@ func SpawnEntities(objects []world.ObjectData, ctx SpawnContext) ([]entities.Ent
 			endY := obj.Y + endYOffset
 
 			platform := entities.NewMovingPlatform(id, obj.X, obj.Y, obj.W, obj.H, endX, endY, speed)
+			if pathID := obj.GetPropString(world.PropPlatformPath, ""); pathID != "" {
+				if path := world.FindPath(objects, pathID); path != nil && path.IsShape() {
+					platform.SetPath(platformPath(path, obj.W, obj.H), len(path.Polygon) > 0)
+				}
+			}
 			platform.SetWaitTime(waitTime)
 			platform.SetPushPlayer(pushPlayer)
+			ease, _ := tween.ParseEase(obj.GetPropString(world.PropEase, ""))
+			platform.SetEase(ease)
+			if obj.GetPropBool("startStopped", false) {
+				platform.Deactivate()
+			}
+			// Register platform so switches can start and stop it
+			if ctx.Registry != nil {
+				ctx.Registry.Register(platform)
+			}
+
+			platforms[id] = platform
 
 			// Platform is both a solid entity and a kinematic
 			solidEnts = append(solidEnts, platform)
 			kinematics = append(kinematics, platform)
 			entityList = append(entityList, platform)
+
+		case world.ObjectTypeCollectible:
+			id := obj.GetPropString("id", obj.Name)
+			collectible := entities.NewCollectible(obj.X, obj.Y, obj.W, obj.H, id)
+			counter := obj.GetPropString(world.PropCollectibleCounter, VarCollectibles)
+			collectible.OnCollect = func(id string) {
+				if ctx.Progress != nil {
+					ctx.Progress.Collect()
+				}
+				if ctx.Vars != nil {
+					ctx.Vars.Add(counter, 1)
+				}
+				if ctx.OnCollect != nil {
+					ctx.OnCollect(id)
+				}
+			}
+			triggers = append(triggers, collectible)
+			entityList = append(entityList, collectible)
+
+		case world.ObjectTypeKey:
+			id := obj.GetPropString("id", obj.Name)
+			key := entities.NewKey(obj.X, obj.Y, obj.W, obj.H, id)
+			key.OnCollect = func(id string) {
+				if ctx.Progress != nil {
+					ctx.Progress.PickUpKey(id)
+				}
+				if ctx.OnKey != nil {
+					ctx.OnKey(id)
+				}
+			}
+			triggers = append(triggers, key)
+			entityList = append(entityList, key)
+
+		case world.ObjectTypeLight:
+			id := obj.GetPropString("id", obj.Name)
+			col, err := gfx.ParseHexColor(obj.GetPropString("color", "#FFFFFF"))
+			if err != nil {
+				col = color.RGBA{255, 255, 255, 255}
+			}
+
+			// Lights are positioned at the object's center
+			light := entities.NewLight(id, obj.X+obj.W/2, obj.Y+obj.H/2, obj.GetPropFloat("radius", 64), col)
+			light.SetFlicker(obj.GetPropFloat("flicker", 0))
+			light.SetOn(obj.GetPropBool("startOn", true))
+			entityList = append(entityList, light)
+
+		default:
+			spawn := spawners[obj.Type]
+			if spawn == nil {
+				break
+			}
+			e := spawn(obj, ctx)
+			if e == nil {
+				break
+			}
+			if trigger, ok := e.(entities.Trigger); ok {
+				triggers = append(triggers, trigger)
+			}
+			if solid, ok := e.(entities.SolidEntity); ok {
+				solidEnts = append(solidEnts, solid)
+			}
+			if kinematic, ok := e.(physics.Kinematic); ok {
+				kinematics = append(kinematics, kinematic)
+			}
+			entityList = append(entityList, e)
+		}
+
+		// Polygon and ellipse objects limit triggers to their shape
+		if zone := (entities.Zone{Polygon: obj.Polygon, Ellipse: obj.Ellipse}); !zone.IsZero() && len(entityList) > created {
+			if zoned, ok := entityList[len(entityList)-1].(entities.Zoned); ok {
+				zoned.SetZone(zone)
+			}
+		}
+		// Apply the theme skin to the entity created for this object
+		if skin := ctx.Skins[obj.Type]; skin != nil && len(entityList) > created {
+			if skinnable, ok := entityList[len(entityList)-1].(entities.Skinnable); ok {
+				skinnable.SetSkin(skin)
+			}
+		}
+		// Tag targets by ID; they may be registered later (platforms, lights)
+		if tags := obj.Tags(); len(tags) > 0 && ctx.Registry != nil && len(entityList) > created {
+			if target, ok := entityList[len(entityList)-1].(entities.Targetable); ok {
+				ctx.Registry.Tag(target.TargetID(), tags...)
+			}
+		}
+		if ctx.OnSpawn != nil && len(entityList) > created {
+			ctx.OnSpawn(entityList[len(entityList)-1], obj)
 		}
 	}
 
-	// Second pass: link switches to registry
+	// Second pass: attach hazards to their platforms
+	for hazard, platformID := range riders {
+		if platform := platforms[platformID]; platform != nil {
+			hazard.AttachTo(platform)
+		}
+	}
+
+	// Link switches to registry
 	for _, sw := range switches {
-		if sw.GetTargetID() != "" && ctx.Registry != nil {
+		if len(sw.TargetIDs()) > 0 && ctx.Registry != nil {
 			sw.SetRegistry(ctx.Registry)
 		}
 	}
 
 	return entityList, triggers, solidEnts, kinematics, switches
 }
+
+// platformPath returns the positions of a w×h platform's top left that put
+// its center on the path object's points.
+func platformPath(path *world.ObjectData, w, h float64) []mathx.Vec2 {
+	points := path.Points()
+	for i := range points {
+		points[i] = points[i].Sub(mathx.V(w/2, h/2))
+	}
+	return points
+}
+
+// AddLights adds all lights from an entity list to the world.
+func AddLights(w *entities.EntityWorld, entityList []entities.Entity) {
+	for _, e := range entityList {
+		if light, ok := e.(*entities.Light); ok {
+			w.AddLight(light)
+		}
+	}
+}
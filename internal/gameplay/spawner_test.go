@@ -0,0 +1,63 @@
+//go:build display
+
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestSpawnEntitiesUsesObjectAliases(t *testing.T) {
+	RegisterObjectAlias("lava", world.ObjectTypeHazard)
+	defer delete(objectAliases, "lava")
+
+	objects := []world.ObjectData{
+		{Type: "lava", X: 10, Y: 20, W: 32, H: 16},
+		{Type: "unregistered", X: 0, Y: 0, W: 16, H: 16},
+	}
+	ents, triggers, _, _, _ := SpawnEntities(objects, SpawnContext{})
+	if len(ents) != 1 || len(triggers) != 1 {
+		t.Fatalf("spawned %d entities and %d triggers, want the lava hazard only", len(ents), len(triggers))
+	}
+	if _, ok := ents[0].(*entities.Hazard); !ok {
+		t.Errorf("lava spawned as %T, want a hazard", ents[0])
+	}
+}
+
+func TestSpawnEntitiesUsesRegisteredSpawners(t *testing.T) {
+	var gotCtx bool
+	RegisterSpawner("beacon", func(obj world.ObjectData, ctx SpawnContext) entities.Entity {
+		gotCtx = ctx.Registry != nil
+		return entities.NewCheckpoint(obj.X, obj.Y, obj.W, obj.H, obj.GetPropString("id", ""))
+	})
+	defer RegisterSpawner("beacon", nil)
+	RegisterSpawner("nothing", func(world.ObjectData, SpawnContext) entities.Entity { return nil })
+	defer RegisterSpawner("nothing", nil)
+	// The spawner wins over an alias for the same type
+	RegisterObjectAlias("beacon", world.ObjectTypeHazard)
+	defer delete(objectAliases, "beacon")
+
+	var spawned []world.ObjectType
+	objects := []world.ObjectData{
+		{Type: "beacon", X: 10, Y: 20, W: 16, H: 16, Props: map[string]any{"id": "b1"}},
+		{Type: "nothing", W: 16, H: 16},
+	}
+	ents, triggers, _, _, _ := SpawnEntities(objects, SpawnContext{
+		Registry: entities.NewTargetRegistry(),
+		OnSpawn:  func(e entities.Entity, obj world.ObjectData) { spawned = append(spawned, obj.Type) },
+	})
+	if len(ents) != 1 || len(triggers) != 1 {
+		t.Fatalf("spawned %d entities and %d triggers, want the beacon only", len(ents), len(triggers))
+	}
+	if _, ok := ents[0].(*entities.Checkpoint); !ok {
+		t.Errorf("beacon spawned as %T, want the spawner's checkpoint", ents[0])
+	}
+	if !gotCtx {
+		t.Error("spawner didn't get the spawn context")
+	}
+	if len(spawned) != 1 || spawned[0] != "beacon" {
+		t.Errorf("OnSpawn called for %v, want the beacon", spawned)
+	}
+}
@@ -2,6 +2,7 @@
 package gameplay
 
 import (
+	"github.com/torsten/GoP/internal/game"
 	"github.com/torsten/GoP/internal/physics"
 )
 
@@ -44,25 +45,37 @@ type StateMachine struct {
 	RespawnX float64
 	RespawnY float64
 
-	// Death animation timing
+	// Death sequence timing, in seconds (see SetDeathTuning)
 	DeathTimer   float64 // Time since death
-	RespawnDelay float64 // Delay before respawn (seconds)
+	RespawnDelay float64 // Delay before respawn: the camera hold plus the fade out
+	FadeOutTime  float64 // Final part of RespawnDelay the screen fades to black over
+	FadeInTime   float64 // Fade back in after the respawn
+	fadeIn       float64 // Fade in time left
 
 	// Level completion callback
 	OnComplete func()
 }
 
-// NewStateMachine creates a new state machine with default settings.
+// NewStateMachine creates a new state machine with the default death
+// sequence timing.
 func NewStateMachine() *StateMachine {
-	return &StateMachine{
-		Current:      StateRunning,
-		RespawnDelay: 1.0, // 1 second delay before respawn
-	}
+	sm := &StateMachine{Current: StateRunning}
+	sm.SetDeathTuning(game.DefaultTuning().Death)
+	return sm
+}
+
+// SetDeathTuning sets the timing of the death sequence.
+func (sm *StateMachine) SetDeathTuning(t game.DeathTuning) {
+	sm.FadeOutTime = t.FadeOutTime.Seconds()
+	sm.RespawnDelay = t.HoldTime.Seconds() + sm.FadeOutTime
+	sm.FadeInTime = t.FadeInTime.Seconds()
 }
 
 // Update processes state transitions.
 func (sm *StateMachine) Update(dt float64) {
 	switch sm.Current {
+	case StateRunning:
+		sm.fadeIn = max(sm.fadeIn-dt, 0)
 	case StateDead:
 		sm.DeathTimer += dt
 		if sm.DeathTimer >= sm.RespawnDelay {
@@ -92,10 +105,14 @@ func (sm *StateMachine) TriggerComplete() {
 	}
 }
 
-// FinishRespawn completes the respawn and returns to running state.
+// FinishRespawn completes the respawn and returns to running state, fading
+// the screen back in.
 func (sm *StateMachine) FinishRespawn() {
 	sm.Current = StateRunning
 	sm.DeathTimer = 0
+	if sm.FadeOutTime > 0 {
+		sm.fadeIn = sm.FadeInTime
+	}
 }
 
 // SetRespawnPoint updates the respawn location.
@@ -124,6 +141,48 @@ func (sm *StateMachine) IsCompleted() bool {
 	return sm.Current == StateCompleted
 }
 
+// DeathFade returns the player opacity for the death fade-out.
+// Returns 1 while not dead, falling to 0 over the camera hold.
+func (sm *StateMachine) DeathFade() float64 {
+	switch sm.Current {
+	case StateDead:
+		hold := sm.RespawnDelay - sm.FadeOutTime
+		if hold <= 0 {
+			return 0
+		}
+		return max(1-sm.DeathTimer/hold, 0)
+	case StateRespawning:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// ScreenFade returns how far the screen is faded to black: 0 while playing,
+// rising to 1 over the end of the death sequence and falling back to 0 as the
+// respawned player fades in.
+func (sm *StateMachine) ScreenFade() float64 {
+	switch sm.Current {
+	case StateDead:
+		if sm.FadeOutTime <= 0 {
+			return 0
+		}
+		return min(max((sm.DeathTimer-(sm.RespawnDelay-sm.FadeOutTime))/sm.FadeOutTime, 0), 1)
+	case StateRespawning:
+		if sm.FadeOutTime <= 0 {
+			return 0
+		}
+		return 1
+	case StateRunning:
+		if sm.FadeInTime <= 0 {
+			return 0
+		}
+		return sm.fadeIn / sm.FadeInTime
+	default:
+		return 0
+	}
+}
+
 // GetRespawnAABB returns the respawn point as an AABB.
 func (sm *StateMachine) GetRespawnAABB(w, h float64) physics.AABB {
 	return physics.AABB{
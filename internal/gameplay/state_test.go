@@ -0,0 +1,67 @@
+//go:build display
+
+package gameplay
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/torsten/GoP/internal/game"
+)
+
+func TestDeathSequence(t *testing.T) {
+	sm := NewStateMachine()
+	sm.SetDeathTuning(game.DeathTuning{
+		HoldTime:    600 * time.Millisecond,
+		FadeOutTime: 400 * time.Millisecond,
+		FadeInTime:  300 * time.Millisecond,
+	})
+	sm.TriggerDeath()
+
+	steps := []struct {
+		name      string
+		dt        float64
+		finish    bool // Respawn the player before checking
+		state     State
+		fade      float64 // Player opacity
+		blackness float64 // Screen fade
+	}{
+		{"camera hold", 0.3, false, StateDead, 0.5, 0},
+		{"fading out", 0.5, false, StateDead, 0, 0.5},
+		{"respawning", 0.25, false, StateRespawning, 0, 1},
+		{"respawned", 0, true, StateRunning, 1, 1},
+		{"fading in", 0.15, false, StateRunning, 1, 0.5},
+		{"faded in", 0.2, false, StateRunning, 1, 0},
+	}
+	for _, step := range steps {
+		sm.Update(step.dt)
+		if step.finish {
+			sm.FinishRespawn()
+		}
+		if sm.Current != step.state {
+			t.Fatalf("%s: state = %v, want %v", step.name, sm.Current, step.state)
+		}
+		if got := sm.DeathFade(); math.Abs(got-step.fade) > 1e-9 {
+			t.Errorf("%s: DeathFade = %v, want %v", step.name, got, step.fade)
+		}
+		if got := sm.ScreenFade(); math.Abs(got-step.blackness) > 1e-9 {
+			t.Errorf("%s: ScreenFade = %v, want %v", step.name, got, step.blackness)
+		}
+	}
+}
+
+func TestDeathSequenceWithoutFade(t *testing.T) {
+	sm := NewStateMachine()
+	sm.SetDeathTuning(game.DeathTuning{HoldTime: 500 * time.Millisecond, FadeInTime: 300 * time.Millisecond})
+	sm.TriggerDeath()
+
+	sm.Update(0.6)
+	if !sm.IsRespawning() {
+		t.Fatalf("state = %v after the hold, want Respawning", sm.Current)
+	}
+	sm.FinishRespawn()
+	if got := sm.ScreenFade(); got != 0 {
+		t.Errorf("ScreenFade = %v without a fade out, want 0", got)
+	}
+}
@@ -34,6 +34,20 @@ func NewAnimationFromSheet(sheet *assets.Sheet, frameDuration time.Duration) *An
 	}
 }
 
+// NewAnimationFromAtlas creates a new animation from named atlas frames.
+// Frames are played in the order the names are given.
+func NewAnimationFromAtlas(atlas *assets.Atlas, names []string, frameDuration time.Duration) (*Animation, error) {
+	frames, err := atlas.Frames(names...)
+	if err != nil {
+		return nil, err
+	}
+	return &Animation{
+		Frames:        frames,
+		FrameDuration: frameDuration,
+		Loop:          true,
+	}, nil
+}
+
 // Length returns the total duration of the animation.
 // Returns 0 if there are no frames.
 func (a *Animation) Length() time.Duration {
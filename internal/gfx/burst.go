@@ -0,0 +1,79 @@
+package gfx
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// Burst is a one-shot spray of square particles flying out from a point,
+// e.g. when the player dies. The particles fall with Gravity and fade out
+// over the burst's duration.
+type Burst struct {
+	Color    color.Color
+	Size     float64 // Particle size in pixels
+	Gravity  float64 // Downward acceleration in pixels/second²
+	Duration time.Duration
+
+	particles []burstParticle
+	elapsed   time.Duration
+}
+
+// burstParticle is one particle of a Burst, in world pixels.
+type burstParticle struct {
+	x, y   float64
+	vx, vy float64
+}
+
+// NewBurst creates a burst of count particles at x, y, spread evenly around
+// the circle. Their speeds vary between half and all of speed (pixels/second)
+// so the burst doesn't look like a ring.
+func NewBurst(x, y float64, count int, speed float64, d time.Duration, c color.Color) *Burst {
+	b := &Burst{Color: c, Size: 3, Duration: d}
+	for i := 0; i < count; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(count)
+		// Golden ratio steps spread the speeds without a random source
+		_, frac := math.Modf(float64(i) * 0.618034)
+		v := speed * (0.5 + 0.5*frac)
+		b.particles = append(b.particles, burstParticle{
+			x: x, y: y,
+			vx: math.Cos(angle) * v,
+			vy: math.Sin(angle) * v,
+		})
+	}
+	return b
+}
+
+// Update advances the burst by the given delta time.
+func (b *Burst) Update(dt time.Duration) {
+	if b.Done() {
+		return
+	}
+	b.elapsed += dt
+	sec := dt.Seconds()
+	for i := range b.particles {
+		p := &b.particles[i]
+		p.vy += b.Gravity * sec
+		p.x += p.vx * sec
+		p.y += p.vy * sec
+	}
+}
+
+// Done returns true once the burst has faded out.
+func (b *Burst) Done() bool {
+	return b.elapsed >= b.Duration
+}
+
+// Draw renders the particles with the camera at camX, camY.
+func (b *Burst) Draw(screen *ebiten.Image, camX, camY float64) {
+	if b.Done() {
+		return
+	}
+	clr := draw.Fade(b.Color, 1-float64(b.elapsed)/float64(b.Duration))
+	for _, p := range b.particles {
+		draw.FillRect(screen, p.x-camX-b.Size/2, p.y-camY-b.Size/2, b.Size, b.Size, clr)
+	}
+}
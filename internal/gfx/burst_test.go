@@ -0,0 +1,43 @@
+//go:build display
+
+package gfx
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestBurstSpreadsAndFades(t *testing.T) {
+	b := NewBurst(100, 50, 8, 100, 500*time.Millisecond, color.White)
+	b.Update(100 * time.Millisecond)
+
+	// Particles fly out in every direction
+	var left, right, up, down bool
+	for _, p := range b.particles {
+		left = left || p.x < 100
+		right = right || p.x > 100
+		up = up || p.y < 50
+		down = down || p.y > 50
+	}
+	if !left || !right || !up || !down {
+		t.Errorf("particles moved left %v, right %v, up %v, down %v; want all", left, right, up, down)
+	}
+
+	if b.Done() {
+		t.Fatal("burst done after 100ms of 500ms")
+	}
+	b.Update(400 * time.Millisecond)
+	if !b.Done() {
+		t.Error("burst not done after its duration")
+	}
+}
+
+func TestBurstGravity(t *testing.T) {
+	b := NewBurst(0, 0, 1, 100, time.Second, color.White) // One particle, straight right
+	b.Gravity = 400
+	b.Update(500 * time.Millisecond)
+	if p := b.particles[0]; p.y <= 0 {
+		t.Errorf("particle y = %v with gravity, want it to fall", p.y)
+	}
+}
@@ -0,0 +1,26 @@
+package gfx
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseHexColor parses a color in #RRGGBB or #RRGGBBAA form.
+func ParseHexColor(hex string) (color.RGBA, error) {
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) != 6 && len(s) != 8 || len(s) == len(hex) {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected #RRGGBB or #RRGGBBAA", hex)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: %w", hex, err)
+	}
+	if len(s) == 6 {
+		v = v<<8 | 0xff
+	}
+
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
@@ -0,0 +1,120 @@
+// Package draw provides the shape helpers used by the game and editor.
+//
+// It wraps ebiten's vector package, so an ebiten upgrade that changes the
+// vector API only touches this package. Rectangles and Line are drawn without
+// anti-aliasing to keep pixel art crisp; SmoothLine, the circles, ellipses and
+// polygons are anti-aliased.
+package draw
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+// FillRect fills a rectangle with clr.
+func FillRect(dst *ebiten.Image, x, y, w, h float64, clr color.Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	vector.FillRect(dst, float32(x), float32(y), float32(w), float32(h), clr, false)
+}
+
+// StrokeRect draws a rectangle's outline, thickness pixels wide, inside the
+// rectangle. The sides don't overlap, so translucent corners aren't darker.
+func StrokeRect(dst *ebiten.Image, x, y, w, h, thickness float64, clr color.Color) {
+	FillRect(dst, x, y, w, thickness, clr)
+	FillRect(dst, x, y+h-thickness, w, thickness, clr)
+	FillRect(dst, x, y+thickness, thickness, h-2*thickness, clr)
+	FillRect(dst, x+w-thickness, y+thickness, thickness, h-2*thickness, clr)
+}
+
+// Line draws a line width pixels wide from x1, y1 to x2, y2.
+func Line(dst *ebiten.Image, x1, y1, x2, y2, width float64, clr color.Color) {
+	vector.StrokeLine(dst, float32(x1), float32(y1), float32(x2), float32(y2), float32(width), clr, false)
+}
+
+// SmoothLine draws an anti-aliased line width pixels wide from x1, y1 to
+// x2, y2. Use it for diagonal overlays like paths and links; it blurs
+// axis-aligned edges.
+func SmoothLine(dst *ebiten.Image, x1, y1, x2, y2, width float64, clr color.Color) {
+	vector.StrokeLine(dst, float32(x1), float32(y1), float32(x2), float32(y2), float32(width), clr, true)
+}
+
+// FillCircle fills an anti-aliased circle of radius r around cx, cy.
+func FillCircle(dst *ebiten.Image, cx, cy, r float64, clr color.Color) {
+	vector.FillCircle(dst, float32(cx), float32(cy), float32(r), clr, true)
+}
+
+// StrokeCircle draws an anti-aliased circle outline width pixels wide.
+func StrokeCircle(dst *ebiten.Image, cx, cy, r, width float64, clr color.Color) {
+	vector.StrokeCircle(dst, float32(cx), float32(cy), float32(r), float32(width), clr, true)
+}
+
+// FillEllipse fills the ellipse inside the rectangle x, y, w, h.
+func FillEllipse(dst *ebiten.Image, x, y, w, h float64, clr color.Color) {
+	FillPolygon(dst, ellipsePoints(x, y, w, h), clr)
+}
+
+// StrokeEllipse draws the outline of the ellipse inside the rectangle x, y,
+// w, h, width pixels wide.
+func StrokeEllipse(dst *ebiten.Image, x, y, w, h, width float64, clr color.Color) {
+	StrokePolyline(dst, ellipsePoints(x, y, w, h), true, width, clr)
+}
+
+// ellipseSegments is the number of lines ellipses are drawn with.
+const ellipseSegments = 48
+
+// ellipsePoints returns points around the ellipse inside the rectangle x,
+// y, w, h, or nil if it's empty.
+func ellipsePoints(x, y, w, h float64) []mathx.Vec2 {
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+	points := make([]mathx.Vec2, ellipseSegments)
+	for i := range points {
+		a := 2 * math.Pi * float64(i) / ellipseSegments
+		points[i] = mathx.V(x+w/2+w/2*math.Cos(a), y+h/2+h/2*math.Sin(a))
+	}
+	return points
+}
+
+// FillPolygon fills the polygon through points, closed back to the first,
+// with clr. Overlapping parts are filled by the even-odd rule.
+func FillPolygon(dst *ebiten.Image, points []mathx.Vec2, clr color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	var path vector.Path
+	path.MoveTo(float32(points[0].X), float32(points[0].Y))
+	for _, p := range points[1:] {
+		path.LineTo(float32(p.X), float32(p.Y))
+	}
+	path.Close()
+
+	opts := &vector.DrawPathOptions{AntiAlias: true}
+	opts.ColorScale.ScaleWithColor(clr)
+	vector.FillPath(dst, &path, &vector.FillOptions{FillRule: vector.FillRuleEvenOdd}, opts)
+}
+
+// StrokePolyline draws anti-aliased lines width pixels wide through points,
+// and back to the first when closed.
+func StrokePolyline(dst *ebiten.Image, points []mathx.Vec2, closed bool, width float64, clr color.Color) {
+	for i := 1; i < len(points); i++ {
+		SmoothLine(dst, points[i-1].X, points[i-1].Y, points[i].X, points[i].Y, width, clr)
+	}
+	if closed && len(points) > 2 {
+		last := points[len(points)-1]
+		SmoothLine(dst, last.X, last.Y, points[0].X, points[0].Y, width, clr)
+	}
+}
+
+// Fade returns clr with its opacity multiplied by alpha (0..1).
+func Fade(clr color.Color, alpha float64) color.Color {
+	r, g, b, a := clr.RGBA()
+	scale := func(v uint32) uint16 { return uint16(float64(v) * alpha) }
+	return color.RGBA64{R: scale(r), G: scale(g), B: scale(b), A: scale(a)}
+}
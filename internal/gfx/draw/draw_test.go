@@ -0,0 +1,26 @@
+//go:build display
+
+package draw
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestFade(t *testing.T) {
+	tests := []struct {
+		in    color.Color
+		alpha float64
+		want  color.RGBA
+	}{
+		{color.RGBA{255, 128, 0, 255}, 0.5, color.RGBA{127, 64, 0, 127}},
+		{color.RGBA{100, 100, 100, 200}, 1, color.RGBA{100, 100, 100, 200}},
+		{color.White, 0, color.RGBA{}},
+	}
+	for _, tt := range tests {
+		got := color.RGBAModel.Convert(Fade(tt.in, tt.alpha)).(color.RGBA)
+		if got != tt.want {
+			t.Errorf("Fade(%v, %v) = %v, want %v", tt.in, tt.alpha, got, tt.want)
+		}
+	}
+}
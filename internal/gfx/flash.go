@@ -0,0 +1,53 @@
+package gfx
+
+import (
+	"image/color"
+	"time"
+)
+
+// Flash is a timed color flash, e.g. a white blink when taking damage.
+// The flash starts at full intensity and fades out linearly over its duration.
+type Flash struct {
+	Color    color.Color
+	Duration time.Duration
+	elapsed  time.Duration
+}
+
+// Start begins a new flash, replacing any flash in progress.
+func (f *Flash) Start(c color.Color, d time.Duration) {
+	f.Color = c
+	f.Duration = d
+	f.elapsed = 0
+}
+
+// Stop ends the flash immediately.
+func (f *Flash) Stop() {
+	f.elapsed = f.Duration
+}
+
+// Update advances the flash by the given delta time.
+func (f *Flash) Update(dt time.Duration) {
+	if f.elapsed < f.Duration {
+		f.elapsed += dt
+	}
+}
+
+// Active returns true while the flash is visible.
+func (f *Flash) Active() bool {
+	return f.Color != nil && f.elapsed < f.Duration
+}
+
+// Intensity returns how strongly the flash color is applied,
+// from 1 at the start of the flash down to 0 when it ends.
+func (f *Flash) Intensity() float64 {
+	if !f.Active() {
+		return 0
+	}
+	return 1 - float64(f.elapsed)/float64(f.Duration)
+}
+
+// colorComponents returns the non-premultiplied components of c in 0-1 range.
+func colorComponents(c color.Color) (r, g, b, a float64) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return float64(n.R) / 255, float64(n.G) / 255, float64(n.B) / 255, float64(n.A) / 255
+}
@@ -0,0 +1,111 @@
+package gfx
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// lightFalloffSize is the resolution of the radial falloff texture.
+const lightFalloffSize = 128
+
+// lightTintStrength is how strongly a light's color is added to the scene.
+const lightTintStrength = 0.25
+
+// PointLight is a light to composite over the scene, in screen coordinates.
+type PointLight struct {
+	X, Y      float64    // Center in screen pixels
+	Radius    float64    // Radius in pixels
+	Color     color.RGBA // Light color
+	Intensity float64    // Brightness in 0-1 range (0 = off)
+}
+
+// LightLayer composites ambient darkness and point lights over a rendered scene.
+// Darkness is filled into an offscreen light buffer, each light cuts a soft
+// hole into it, and the buffer is drawn over the screen. Light colors are then
+// added on top so lit areas pick up the light's tint.
+type LightLayer struct {
+	buffer  *ebiten.Image
+	falloff *ebiten.Image
+}
+
+// NewLightLayer creates a light layer. The light buffer is allocated on first draw.
+func NewLightLayer() *LightLayer {
+	return &LightLayer{
+		falloff: newFalloffImage(lightFalloffSize),
+	}
+}
+
+// newFalloffImage creates a white radial gradient, opaque at the center and
+// transparent at the edge, with a quadratic falloff.
+func newFalloffImage(size int) *ebiten.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	center := float64(size) / 2
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := math.Hypot(float64(x)+0.5-center, float64(y)+0.5-center) / center
+			a := math.Max(0, 1-d)
+			v := uint8(a * a * 255)
+			img.SetRGBA(x, y, color.RGBA{v, v, v, v})
+		}
+	}
+
+	return ebiten.NewImageFromImage(img)
+}
+
+// Draw composites the lighting over screen.
+// ambient is the darkness level, from 0 (fully lit) to 1 (pitch black).
+// Does nothing if ambient is 0 or less.
+func (l *LightLayer) Draw(screen *ebiten.Image, ambient float64, lights []PointLight) {
+	if ambient <= 0 {
+		return
+	}
+	ambient = math.Min(ambient, 1)
+
+	// (Re)allocate the light buffer to match the screen
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if l.buffer == nil || l.buffer.Bounds().Dx() != w || l.buffer.Bounds().Dy() != h {
+		if l.buffer != nil {
+			l.buffer.Deallocate()
+		}
+		l.buffer = ebiten.NewImage(w, h)
+	}
+
+	// Fill with darkness, then cut out each light
+	l.buffer.Fill(color.RGBA{0, 0, 0, uint8(ambient * 255)})
+	for _, light := range lights {
+		if light.Intensity <= 0 || light.Radius <= 0 {
+			continue
+		}
+		op := l.lightOptions(light)
+		op.Blend = ebiten.BlendDestinationOut
+		op.ColorScale.ScaleAlpha(float32(light.Intensity))
+		l.buffer.DrawImage(l.falloff, op)
+	}
+	screen.DrawImage(l.buffer, nil)
+
+	// Add light colors on top
+	for _, light := range lights {
+		if light.Intensity <= 0 || light.Radius <= 0 {
+			continue
+		}
+		op := l.lightOptions(light)
+		op.Blend = ebiten.BlendLighter
+		op.ColorScale.ScaleWithColor(light.Color)
+		op.ColorScale.ScaleAlpha(float32(light.Intensity * lightTintStrength))
+		screen.DrawImage(l.falloff, op)
+	}
+}
+
+// lightOptions returns draw options placing the falloff texture over the light's area.
+func (l *LightLayer) lightOptions(light PointLight) *ebiten.DrawImageOptions {
+	scale := light.Radius * 2 / lightFalloffSize
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterLinear
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(light.X-light.Radius, light.Y-light.Radius)
+	return op
+}
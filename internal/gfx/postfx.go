@@ -0,0 +1,290 @@
+package gfx
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PostEffect identifies a full-screen post-processing shader.
+type PostEffect int
+
+const (
+	// EffectScanlines darkens every other row, like a CRT.
+	EffectScanlines PostEffect = iota
+	// EffectVignette darkens the screen edges.
+	EffectVignette
+	// EffectPaletteSwap maps the image onto a four-color palette by brightness.
+	EffectPaletteSwap
+	// EffectFlash tints the whole screen with a fading color (e.g. on damage).
+	EffectFlash
+)
+
+// String returns the effect name for debugging.
+func (e PostEffect) String() string {
+	switch e {
+	case EffectScanlines:
+		return "Scanlines"
+	case EffectVignette:
+		return "Vignette"
+	case EffectPaletteSwap:
+		return "PaletteSwap"
+	case EffectFlash:
+		return "Flash"
+	default:
+		return "Unknown"
+	}
+}
+
+// postEffectOrder is the order effects are applied in.
+// Palette swap runs first so scanlines and vignette darken the final colors,
+// and the flash goes last so it reads on top of everything.
+var postEffectOrder = []PostEffect{EffectPaletteSwap, EffectScanlines, EffectVignette, EffectFlash}
+
+// DefaultPalette is a four-tone green palette, darkest first.
+var DefaultPalette = [4]color.RGBA{
+	{15, 56, 15, 255},
+	{48, 98, 48, 255},
+	{139, 172, 15, 255},
+	{155, 188, 15, 255},
+}
+
+// Kage sources for each effect. All use pixel units and read the scene from image 0.
+var postEffectSources = map[PostEffect]string{
+	EffectScanlines: `//kage:unit pixels
+package main
+
+var Intensity float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	line := mod(floor(srcPos.y-imageSrc0Origin().y), 2)
+	return vec4(c.rgb*(1-Intensity*line), c.a)
+}
+`,
+	EffectVignette: `//kage:unit pixels
+package main
+
+var Strength float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	uv := (srcPos - imageSrc0Origin()) / imageSrc0Size()
+	d := distance(uv, vec2(0.5))
+	v := 1 - smoothstep(0.35, 0.75, d)*Strength
+	return vec4(c.rgb*v, c.a)
+}
+`,
+	EffectPaletteSwap: `//kage:unit pixels
+package main
+
+var Palette0 vec4
+var Palette1 vec4
+var Palette2 vec4
+var Palette3 vec4
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	if c.a == 0 {
+		return c
+	}
+	l := dot(c.rgb/c.a, vec3(0.299, 0.587, 0.114))
+	p := Palette0
+	if l >= 0.75 {
+		p = Palette3
+	} else if l >= 0.5 {
+		p = Palette2
+	} else if l >= 0.25 {
+		p = Palette1
+	}
+	return vec4(p.rgb*c.a, c.a)
+}
+`,
+	EffectFlash: `//kage:unit pixels
+package main
+
+var FlashColor vec4
+var Amount float
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	return vec4(mix(c.rgb, FlashColor.rgb*c.a, Amount), c.a)
+}
+`,
+}
+
+// PostProcessor renders a scene through a chain of full-screen shaders.
+// The scene is drawn into the image returned by Begin, and End composites it
+// onto the screen with every enabled effect applied. Shaders compile on first
+// use; with no effects enabled End is a plain copy.
+type PostProcessor struct {
+	// ScanlineIntensity is how much scanline rows are darkened (0-1)
+	ScanlineIntensity float64
+	// VignetteStrength is how dark the screen corners get (0-1)
+	VignetteStrength float64
+	// Palette is used by EffectPaletteSwap, darkest color first
+	Palette [4]color.RGBA
+
+	enabled map[PostEffect]bool
+	shaders map[PostEffect]*ebiten.Shader
+	flash   Flash
+
+	scene *ebiten.Image // Offscreen scene buffer
+	swap  *ebiten.Image // Ping-pong buffer for chained effects
+}
+
+// NewPostProcessor creates a post-processor with no effects enabled.
+func NewPostProcessor() *PostProcessor {
+	return &PostProcessor{
+		ScanlineIntensity: 0.3,
+		VignetteStrength:  0.6,
+		Palette:           DefaultPalette,
+		enabled:           make(map[PostEffect]bool),
+		shaders:           make(map[PostEffect]*ebiten.Shader),
+	}
+}
+
+// SetEnabled turns an effect on or off.
+// EffectFlash is always applied while a flash is active and ignores this setting.
+func (p *PostProcessor) SetEnabled(effect PostEffect, on bool) {
+	p.enabled[effect] = on
+}
+
+// Toggle flips an effect on or off.
+func (p *PostProcessor) Toggle(effect PostEffect) {
+	p.enabled[effect] = !p.enabled[effect]
+}
+
+// Enabled returns whether an effect is on.
+func (p *PostProcessor) Enabled(effect PostEffect) bool {
+	return p.enabled[effect]
+}
+
+// Flash starts a full-screen flash of the given color that fades out over d.
+func (p *PostProcessor) Flash(c color.Color, d time.Duration) {
+	p.flash.Start(c, d)
+}
+
+// Update advances timed effects by dt.
+func (p *PostProcessor) Update(dt time.Duration) {
+	p.flash.Update(dt)
+}
+
+// active returns the effects to apply this frame, in order.
+func (p *PostProcessor) active() []PostEffect {
+	var effects []PostEffect
+	for _, e := range postEffectOrder {
+		if e == EffectFlash {
+			if p.flash.Active() {
+				effects = append(effects, e)
+			}
+		} else if p.enabled[e] {
+			effects = append(effects, e)
+		}
+	}
+	return effects
+}
+
+// Begin returns the offscreen image to draw the scene into, sized like screen.
+// The image is cleared each call.
+func (p *PostProcessor) Begin(screen *ebiten.Image) *ebiten.Image {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	p.scene = resizeBuffer(p.scene, w, h)
+	p.scene.Clear()
+	return p.scene
+}
+
+// End draws the scene from Begin onto screen with all active effects applied.
+func (p *PostProcessor) End(screen *ebiten.Image) error {
+	if p.scene == nil {
+		return nil
+	}
+
+	effects := p.active()
+	if len(effects) == 0 {
+		screen.DrawImage(p.scene, nil)
+		return nil
+	}
+
+	w, h := p.scene.Bounds().Dx(), p.scene.Bounds().Dy()
+	p.swap = resizeBuffer(p.swap, w, h)
+
+	// Ping-pong between the two buffers; the last effect draws to the screen
+	src, dst := p.scene, p.swap
+	for i, e := range effects {
+		shader, err := p.shader(e)
+		if err != nil {
+			return err
+		}
+
+		target := dst
+		if i == len(effects)-1 {
+			target = screen
+		} else {
+			target.Clear()
+		}
+
+		op := &ebiten.DrawRectShaderOptions{}
+		op.Images[0] = src
+		op.Uniforms = p.uniforms(e)
+		target.DrawRectShader(w, h, shader, op)
+
+		src, dst = dst, src
+	}
+	return nil
+}
+
+// shader returns the compiled shader for an effect, compiling it on first use.
+func (p *PostProcessor) shader(e PostEffect) (*ebiten.Shader, error) {
+	if s, ok := p.shaders[e]; ok {
+		return s, nil
+	}
+	s, err := ebiten.NewShader([]byte(postEffectSources[e]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %s shader: %w", e, err)
+	}
+	p.shaders[e] = s
+	return s, nil
+}
+
+// uniforms returns the shader uniforms for an effect.
+func (p *PostProcessor) uniforms(e PostEffect) map[string]any {
+	switch e {
+	case EffectScanlines:
+		return map[string]any{"Intensity": float32(p.ScanlineIntensity)}
+	case EffectVignette:
+		return map[string]any{"Strength": float32(p.VignetteStrength)}
+	case EffectPaletteSwap:
+		u := make(map[string]any, len(p.Palette))
+		for i, c := range p.Palette {
+			u[fmt.Sprintf("Palette%d", i)] = rgbaUniform(c)
+		}
+		return u
+	case EffectFlash:
+		return map[string]any{
+			"FlashColor": rgbaUniform(p.flash.Color),
+			"Amount":     float32(p.flash.Intensity()),
+		}
+	}
+	return nil
+}
+
+// rgbaUniform converts a color to a non-premultiplied vec4 uniform.
+func rgbaUniform(c color.Color) []float32 {
+	r, g, b, a := colorComponents(c)
+	return []float32{float32(r), float32(g), float32(b), float32(a)}
+}
+
+// resizeBuffer returns img if it already has the given size,
+// otherwise deallocates it and returns a new image.
+func resizeBuffer(img *ebiten.Image, w, h int) *ebiten.Image {
+	if img != nil && img.Bounds().Dx() == w && img.Bounds().Dy() == h {
+		return img
+	}
+	if img != nil {
+		img.Deallocate()
+	}
+	return ebiten.NewImage(w, h)
+}
@@ -0,0 +1,47 @@
+//go:build display
+
+package gfx
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestPostEffectShadersCompile(t *testing.T) {
+	p := NewPostProcessor()
+	for _, e := range postEffectOrder {
+		if _, err := p.shader(e); err != nil {
+			t.Errorf("%s: %v", e, err)
+		}
+	}
+}
+
+func TestPostProcessorActiveEffects(t *testing.T) {
+	p := NewPostProcessor()
+
+	if effects := p.active(); len(effects) != 0 {
+		t.Fatalf("Expected no active effects, got %v", effects)
+	}
+
+	p.SetEnabled(EffectVignette, true)
+	p.SetEnabled(EffectScanlines, true)
+	p.Flash(color.White, 100*time.Millisecond)
+
+	want := []PostEffect{EffectScanlines, EffectVignette, EffectFlash}
+	effects := p.active()
+	if len(effects) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, effects)
+	}
+	for i := range want {
+		if effects[i] != want[i] {
+			t.Errorf("Effect %d: expected %s, got %s", i, want[i], effects[i])
+		}
+	}
+
+	// Flash drops out once it has faded
+	p.Update(100 * time.Millisecond)
+	if effects := p.active(); len(effects) != 2 {
+		t.Errorf("Expected flash to end, got %v", effects)
+	}
+}
@@ -2,19 +2,33 @@
 package gfx
 
 import (
+	"image/color"
+	"time"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
 )
 
 // Sprite represents a drawable image with transformation properties.
-// It supports position, scale, rotation, and origin point for transformations.
+// It supports position, scale, rotation, flipping, and origin point for
+// transformations, plus color multiply (tint), color add, alpha, and flashes.
+//
+// Color effects are applied at draw time and are independent of Image, so
+// an Animator can swap frames every update without interrupting a flash.
 type Sprite struct {
-	Image   *ebiten.Image
-	X, Y    float64
-	ScaleX  float64
-	ScaleY  float64
+	Image    *ebiten.Image
+	X, Y     float64
+	ScaleX   float64
+	ScaleY   float64
 	Rotation float64
-	OriginX float64
-	OriginY float64
+	OriginX  float64
+	OriginY  float64
+	FlipX    bool        // Mirror horizontally around the origin
+	FlipY    bool        // Mirror vertically around the origin
+	Tint     color.Color // Color multiplied with the image (white = no tint)
+	Add      color.Color // Color added after the tint (nil = none)
+	Alpha    float64     // Opacity in 0-1 range
+	flash    Flash
 }
 
 // NewSprite creates a new sprite with the given image and default values.
@@ -29,6 +43,8 @@ func NewSprite(img *ebiten.Image) *Sprite {
 		Rotation: 0,
 		OriginX:  0.5,
 		OriginY:  0.5,
+		Tint:     color.White,
+		Alpha:    1.0,
 	}
 }
 
@@ -56,15 +72,87 @@ func (s *Sprite) SetOrigin(ox, oy float64) {
 	s.OriginY = oy
 }
 
-// Draw renders the sprite to the target image with all transformations applied.
-// Uses nearest-neighbor filtering for pixel art.
-func (s *Sprite) Draw(screen *ebiten.Image) {
-	if s.Image == nil {
-		return
+// SetFlip sets horizontal and vertical mirroring.
+// Flipping happens around the origin point.
+func (s *Sprite) SetFlip(flipX, flipY bool) {
+	s.FlipX = flipX
+	s.FlipY = flipY
+}
+
+// SetFlipX sets horizontal mirroring, e.g. to face the movement direction.
+func (s *Sprite) SetFlipX(flip bool) {
+	s.FlipX = flip
+}
+
+// SetFlipY sets vertical mirroring.
+func (s *Sprite) SetFlipY(flip bool) {
+	s.FlipY = flip
+}
+
+// SetTint sets the color multiplied with the sprite image.
+// A nil color resets the tint to white (no tint).
+func (s *Sprite) SetTint(c color.Color) {
+	if c == nil {
+		c = color.White
 	}
+	s.Tint = c
+}
 
-	op := &ebiten.DrawImageOptions{}
-	op.Filter = ebiten.FilterNearest
+// ClearTint removes the color tint.
+func (s *Sprite) ClearTint() {
+	s.Tint = color.White
+}
+
+// SetAddColor sets a color added to the sprite after the tint.
+// A nil color removes the additive color.
+func (s *Sprite) SetAddColor(c color.Color) {
+	s.Add = c
+}
+
+// Flash starts a timed flash that blends the sprite toward c and fades out over d.
+// A new flash replaces one already in progress.
+func (s *Sprite) Flash(c color.Color, d time.Duration) {
+	s.flash.Start(c, d)
+}
+
+// FlashWhite starts a timed white flash, e.g. for damage feedback.
+func (s *Sprite) FlashWhite(d time.Duration) {
+	s.flash.Start(color.White, d)
+}
+
+// StopFlash ends the current flash immediately.
+func (s *Sprite) StopFlash() {
+	s.flash.Stop()
+}
+
+// IsFlashing returns true while a flash is in progress.
+func (s *Sprite) IsFlashing() bool {
+	return s.flash.Active()
+}
+
+// Update advances timed effects by the given delta time.
+// Call it once per update, alongside the sprite's Animator.
+func (s *Sprite) Update(dt time.Duration) {
+	s.flash.Update(dt)
+}
+
+// SetAlpha sets the sprite opacity, clamped to the 0-1 range.
+func (s *Sprite) SetAlpha(alpha float64) {
+	if alpha < 0 {
+		alpha = 0
+	} else if alpha > 1 {
+		alpha = 1
+	}
+	s.Alpha = alpha
+}
+
+// GeoM returns the geometry matrix used to draw the sprite.
+// Returns the identity matrix if the sprite has no image.
+func (s *Sprite) GeoM() ebiten.GeoM {
+	var geoM ebiten.GeoM
+	if s.Image == nil {
+		return geoM
+	}
 
 	// Get image bounds for origin calculation
 	bounds := s.Image.Bounds()
@@ -74,15 +162,86 @@ func (s *Sprite) Draw(screen *ebiten.Image) {
 	originOffsetX := w * s.OriginX
 	originOffsetY := h * s.OriginY
 
+	// Flipping is a negative scale around the origin
+	scaleX, scaleY := s.ScaleX, s.ScaleY
+	if s.FlipX {
+		scaleX = -scaleX
+	}
+	if s.FlipY {
+		scaleY = -scaleY
+	}
+
 	// Apply transformations in order:
 	// 1. Translate so origin point is at (0,0) - for rotation/scale around origin
-	op.GeoM.Translate(-originOffsetX, -originOffsetY)
-	// 2. Scale
-	op.GeoM.Scale(s.ScaleX, s.ScaleY)
+	geoM.Translate(-originOffsetX, -originOffsetY)
+	// 2. Scale (and flip)
+	geoM.Scale(scaleX, scaleY)
 	// 3. Rotate
-	op.GeoM.Rotate(s.Rotation)
+	geoM.Rotate(s.Rotation)
 	// 4. Translate to final position (s.X, s.Y is where the origin point should be)
-	op.GeoM.Translate(s.X, s.Y)
+	geoM.Translate(s.X, s.Y)
+
+	return geoM
+}
+
+// ColorScale returns the color scale used to draw the sprite,
+// combining the tint and alpha.
+func (s *Sprite) ColorScale() ebiten.ColorScale {
+	var cs ebiten.ColorScale
+	if s.Tint != nil {
+		cs.ScaleWithColor(s.Tint)
+	}
+	cs.ScaleAlpha(float32(s.Alpha))
+	return cs
+}
+
+// ColorM returns the color matrix used to draw the sprite when it has an
+// additive color or an active flash. The tint is multiplied first, then the
+// additive color is added, then the result is blended toward the flash color.
+func (s *Sprite) ColorM() colorm.ColorM {
+	var cm colorm.ColorM
+
+	tr, tg, tb, ta := 1.0, 1.0, 1.0, 1.0
+	if s.Tint != nil {
+		tr, tg, tb, ta = colorComponents(s.Tint)
+	}
+	cm.Scale(tr, tg, tb, ta*s.Alpha)
+
+	if s.Add != nil {
+		ar, ag, ab, _ := colorComponents(s.Add)
+		cm.Translate(ar, ag, ab, 0)
+	}
+
+	if intensity := s.flash.Intensity(); intensity > 0 {
+		fr, fg, fb, _ := colorComponents(s.flash.Color)
+		keep := 1 - intensity
+		cm.Scale(keep, keep, keep, 1)
+		cm.Translate(fr*intensity, fg*intensity, fb*intensity, 0)
+	}
+
+	return cm
+}
+
+// Draw renders the sprite to the target image with all transformations applied.
+// Uses nearest-neighbor filtering for pixel art.
+func (s *Sprite) Draw(screen *ebiten.Image) {
+	if s.Image == nil || s.Alpha <= 0 {
+		return
+	}
+
+	// Additive colors need a color matrix; plain tint/alpha uses the cheaper color scale
+	if s.Add != nil || s.flash.Active() {
+		op := &colorm.DrawImageOptions{}
+		op.Filter = ebiten.FilterNearest
+		op.GeoM = s.GeoM()
+		colorm.DrawImage(screen, s.Image, s.ColorM(), op)
+		return
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.Filter = ebiten.FilterNearest
+	op.GeoM = s.GeoM()
+	op.ColorScale = s.ColorScale()
 
 	screen.DrawImage(s.Image, op)
 }
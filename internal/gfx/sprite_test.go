@@ -0,0 +1,246 @@
+//go:build display
+
+package gfx
+
+import (
+	"image/color"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const epsilon = 1e-9
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func newTestSprite() *Sprite {
+	s := NewSprite(ebiten.NewImage(16, 8))
+	s.SetPosition(100, 50)
+	return s
+}
+
+// =============================================================================
+// Transform Tests
+// =============================================================================
+
+func TestSprite_GeoMPlacesOriginAtPosition(t *testing.T) {
+	s := newTestSprite()
+	geoM := s.GeoM()
+
+	// Top-left corner should be half the image size up-left of the position
+	x, y := geoM.Apply(0, 0)
+	if !approxEqual(x, 92) || !approxEqual(y, 46) {
+		t.Errorf("Expected top-left at (92, 46), got (%v, %v)", x, y)
+	}
+}
+
+func TestSprite_FlipXMirrorsAroundOrigin(t *testing.T) {
+	s := newTestSprite()
+	s.SetFlipX(true)
+	geoM := s.GeoM()
+
+	// Image left edge ends up on the right, vertical position unchanged
+	x, y := geoM.Apply(0, 0)
+	if !approxEqual(x, 108) || !approxEqual(y, 46) {
+		t.Errorf("Expected flipped top-left at (108, 46), got (%v, %v)", x, y)
+	}
+
+	// Origin stays in place
+	x, y = geoM.Apply(8, 4)
+	if !approxEqual(x, 100) || !approxEqual(y, 50) {
+		t.Errorf("Expected origin at (100, 50), got (%v, %v)", x, y)
+	}
+}
+
+func TestSprite_FlipYMirrorsAroundOrigin(t *testing.T) {
+	s := newTestSprite()
+	s.SetFlipY(true)
+
+	geoM := s.GeoM()
+	x, y := geoM.Apply(0, 0)
+	if !approxEqual(x, 92) || !approxEqual(y, 54) {
+		t.Errorf("Expected flipped top-left at (92, 54), got (%v, %v)", x, y)
+	}
+}
+
+func TestSprite_SetFlip(t *testing.T) {
+	s := newTestSprite()
+	s.SetFlip(true, true)
+	if !s.FlipX || !s.FlipY {
+		t.Errorf("Expected both flips set, got FlipX=%v FlipY=%v", s.FlipX, s.FlipY)
+	}
+
+	// Flipping both axes is the same as rotating by 180 degrees
+	geoM := s.GeoM()
+	x, y := geoM.Apply(0, 0)
+	if !approxEqual(x, 108) || !approxEqual(y, 54) {
+		t.Errorf("Expected top-left at (108, 54), got (%v, %v)", x, y)
+	}
+}
+
+func TestSprite_RotationAroundOrigin(t *testing.T) {
+	s := newTestSprite()
+	s.SetRotation(math.Pi / 2)
+
+	// Rotating 90 degrees clockwise maps the top-left corner (-8, -4) to (4, -8)
+	geoM := s.GeoM()
+	x, y := geoM.Apply(0, 0)
+	if !approxEqual(x, 104) || !approxEqual(y, 42) {
+		t.Errorf("Expected rotated top-left at (104, 42), got (%v, %v)", x, y)
+	}
+}
+
+func TestSprite_GeoMWithoutImage(t *testing.T) {
+	s := NewSprite(nil)
+	s.SetPosition(10, 10)
+
+	geoM := s.GeoM()
+	x, y := geoM.Apply(3, 4)
+	if x != 3 || y != 4 {
+		t.Errorf("Expected identity transform without image, got (%v, %v)", x, y)
+	}
+}
+
+// =============================================================================
+// Color Tests
+// =============================================================================
+
+func TestSprite_DefaultColorScaleIsIdentity(t *testing.T) {
+	s := newTestSprite()
+	cs := s.ColorScale()
+
+	if cs.R() != 1 || cs.G() != 1 || cs.B() != 1 || cs.A() != 1 {
+		t.Errorf("Expected identity color scale, got (%v, %v, %v, %v)", cs.R(), cs.G(), cs.B(), cs.A())
+	}
+}
+
+func TestSprite_SetTint(t *testing.T) {
+	s := newTestSprite()
+	s.SetTint(color.RGBA{255, 0, 0, 255})
+	cs := s.ColorScale()
+
+	if cs.R() != 1 || cs.G() != 0 || cs.B() != 0 || cs.A() != 1 {
+		t.Errorf("Expected red tint, got (%v, %v, %v, %v)", cs.R(), cs.G(), cs.B(), cs.A())
+	}
+
+	s.ClearTint()
+	cs = s.ColorScale()
+	if cs.R() != 1 || cs.G() != 1 || cs.B() != 1 {
+		t.Errorf("Expected tint cleared, got (%v, %v, %v)", cs.R(), cs.G(), cs.B())
+	}
+}
+
+func TestSprite_SetTintNilResets(t *testing.T) {
+	s := newTestSprite()
+	s.SetTint(color.RGBA{0, 0, 255, 255})
+	s.SetTint(nil)
+
+	if s.Tint != color.White {
+		t.Errorf("Expected nil tint to reset to white, got %v", s.Tint)
+	}
+}
+
+func TestSprite_SetAlpha(t *testing.T) {
+	s := newTestSprite()
+	s.SetAlpha(0.5)
+	cs := s.ColorScale()
+
+	// Color scale is premultiplied, so alpha scales all components
+	if cs.A() != 0.5 || cs.R() != 0.5 {
+		t.Errorf("Expected alpha 0.5, got A=%v R=%v", cs.A(), cs.R())
+	}
+}
+
+func TestSprite_SetAlphaClamps(t *testing.T) {
+	s := newTestSprite()
+
+	s.SetAlpha(-1)
+	if s.Alpha != 0 {
+		t.Errorf("Expected alpha clamped to 0, got %v", s.Alpha)
+	}
+
+	s.SetAlpha(2)
+	if s.Alpha != 1 {
+		t.Errorf("Expected alpha clamped to 1, got %v", s.Alpha)
+	}
+}
+
+// =============================================================================
+// Flash Tests
+// =============================================================================
+
+func TestFlash_FadesOutOverDuration(t *testing.T) {
+	var f Flash
+	f.Start(color.White, 100*time.Millisecond)
+
+	if !f.Active() || f.Intensity() != 1 {
+		t.Fatalf("Expected full intensity at start, got active=%v intensity=%v", f.Active(), f.Intensity())
+	}
+
+	f.Update(25 * time.Millisecond)
+	if !approxEqual(f.Intensity(), 0.75) {
+		t.Errorf("Expected intensity 0.75, got %v", f.Intensity())
+	}
+
+	f.Update(75 * time.Millisecond)
+	if f.Active() || f.Intensity() != 0 {
+		t.Errorf("Expected flash finished, got active=%v intensity=%v", f.Active(), f.Intensity())
+	}
+}
+
+func TestFlash_Stop(t *testing.T) {
+	var f Flash
+	f.Start(color.White, time.Second)
+	f.Stop()
+
+	if f.Active() {
+		t.Error("Expected flash to be inactive after Stop")
+	}
+}
+
+func TestSprite_FlashSurvivesFrameChanges(t *testing.T) {
+	s := newTestSprite()
+	anim := NewAnimation([]*ebiten.Image{ebiten.NewImage(16, 8), ebiten.NewImage(16, 8)}, 10*time.Millisecond)
+	animator := NewAnimator(anim)
+	animator.Play()
+
+	s.FlashWhite(100 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		animator.Update(10 * time.Millisecond)
+		s.Update(10 * time.Millisecond)
+		s.Image = animator.CurrentFrame()
+	}
+
+	if !s.IsFlashing() {
+		t.Error("Expected flash to continue across frame changes")
+	}
+}
+
+func TestSprite_ColorMFlashBlendsTowardFlashColor(t *testing.T) {
+	s := newTestSprite()
+	s.SetTint(color.RGBA{0, 0, 0, 255})
+	s.FlashWhite(100 * time.Millisecond)
+
+	// At full intensity a black-tinted sprite is drawn fully white
+	cm := s.ColorM()
+	r, g, b, a := cm.Apply(color.RGBA{128, 64, 32, 255}).RGBA()
+	if r != 0xffff || g != 0xffff || b != 0xffff || a != 0xffff {
+		t.Errorf("Expected white at full flash, got (%x, %x, %x, %x)", r, g, b, a)
+	}
+}
+
+func TestSprite_ColorMAddsColor(t *testing.T) {
+	s := newTestSprite()
+	s.SetTint(color.RGBA{0, 0, 0, 255})
+	s.SetAddColor(color.RGBA{255, 0, 0, 255})
+
+	cm := s.ColorM()
+	r, g, b, _ := cm.Apply(color.White).RGBA()
+	if r != 0xffff || g != 0 || b != 0 {
+		t.Errorf("Expected pure red, got (%x, %x, %x)", r, g, b)
+	}
+}
@@ -0,0 +1,144 @@
+// Package i18n looks up user-facing strings in the current language.
+//
+// Each language is a flat JSON string table in locales/<code>.json, keyed by
+// dotted names like "hud.youDied". Values are fmt format strings, so
+// T("results.time", 12.5) fills in "Time %.1fs". English is the fallback:
+// keys a language doesn't translate show the English string. The game draws
+// text with ebiten's debug font, which only has ASCII, so tables spell out
+// other letters (ue for ü).
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language used until SetLanguage picks another, and
+// the fallback for keys other languages lack.
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Table maps string keys to format strings in one language.
+type Table map[string]string
+
+var (
+	mu       sync.RWMutex
+	tables   = mustLoadTables()
+	language = DefaultLanguage
+)
+
+// Parse parses a string table from JSON.
+func Parse(data []byte) (Table, error) {
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse string table: %w", err)
+	}
+	return t, nil
+}
+
+// mustLoadTables reads the embedded string tables, keyed by language code.
+// Their JSON is checked by the tests, so a broken table is a build mistake.
+func mustLoadTables() map[string]Table {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	loaded := make(map[string]Table, len(files))
+	for _, f := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		t, err := Parse(data)
+		if err != nil {
+			panic(fmt.Sprintf("locale %s: %v", f.Name(), err))
+		}
+		loaded[strings.TrimSuffix(f.Name(), ".json")] = t
+	}
+	return loaded
+}
+
+// T returns the string for key in the current language, formatted with args
+// like fmt.Sprintf. Keys missing from the language fall back to English, and
+// keys missing there too are returned as they are, so they still show up.
+func T(key string, args ...any) string {
+	mu.RLock()
+	format, ok := tables[language][key]
+	if !ok {
+		format, ok = tables[DefaultLanguage][key]
+	}
+	mu.RUnlock()
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// SetLanguage switches the language T uses, e.g. "de". It takes effect for
+// every string looked up afterwards, so scenes show it on their next draw.
+func SetLanguage(code string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := tables[code]; !ok {
+		return fmt.Errorf("unknown language %q (want one of %s)", code, strings.Join(languages(), ", "))
+	}
+	language = code
+	return nil
+}
+
+// Language returns the code of the current language.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// Languages returns the codes of all languages with a string table, sorted.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return languages()
+}
+
+// languages returns the sorted language codes. The caller holds mu.
+func languages() []string {
+	codes := make([]string, 0, len(tables))
+	for code := range tables {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Name returns a language's name in that language, e.g. "Deutsch" for "de".
+func Name(code string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if name, ok := tables[code]["language.name"]; ok {
+		return name
+	}
+	return code
+}
+
+// NextLanguage returns the language after the current one in Languages
+// order, wrapping around, for a menu entry that cycles through them.
+func NextLanguage() string {
+	codes := Languages()
+	current := Language()
+	for i, code := range codes {
+		if code == current {
+			return codes[(i+1)%len(codes)]
+		}
+	}
+	return DefaultLanguage
+}
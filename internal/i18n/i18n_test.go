@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// verbPattern matches fmt verbs, so translations can be checked to take the
+// same arguments as the English string.
+var verbPattern = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func TestT(t *testing.T) {
+	defer SetLanguage(Language())
+
+	if err := SetLanguage("de"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("results.next", "Level 2"); got != "Weiter: Level 2" {
+		t.Errorf(`T("results.next") = %q in German`, got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("T of a missing key = %q, want the key", got)
+	}
+
+	if err := SetLanguage("xx"); err == nil {
+		t.Error("SetLanguage accepted an unknown language")
+	}
+	if Language() != "de" {
+		t.Errorf("Language() = %q after a failed switch, want de", Language())
+	}
+}
+
+func TestFallbackToEnglish(t *testing.T) {
+	defer SetLanguage(Language())
+
+	mu.Lock()
+	tables["test"] = Table{"language.name": "Test"}
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		delete(tables, "test")
+		mu.Unlock()
+	}()
+
+	if err := SetLanguage("test"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("hud.levelCompleteTime", 12.5); got != "LEVEL COMPLETE! 12.5s" {
+		t.Errorf("untranslated key = %q, want the English string", got)
+	}
+}
+
+func TestTablesMatchEnglish(t *testing.T) {
+	en := tables[DefaultLanguage]
+	for _, code := range Languages() {
+		table := tables[code]
+		for key, format := range table {
+			want, ok := en[key]
+			if !ok {
+				t.Errorf("%s: key %s isn't in the English table", code, key)
+				continue
+			}
+			if got, want := verbPattern.FindAllString(format, -1), verbPattern.FindAllString(want, -1); strings.Join(got, " ") != strings.Join(want, " ") {
+				t.Errorf("%s: %s has verbs %v, English has %v", code, key, got, want)
+			}
+			for _, r := range format {
+				if r > 127 {
+					t.Errorf("%s: %s has %q, which the debug font can't draw", code, key, r)
+					break
+				}
+			}
+		}
+		for key := range en {
+			if _, ok := table[key]; !ok {
+				t.Errorf("%s: missing key %s", code, key)
+			}
+		}
+	}
+}
+
+func TestUsedKeysExist(t *testing.T) {
+	call := regexp.MustCompile(`i18n\.T\("([^"]+)"`)
+	root := filepath.Join("..", "..")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range call.FindAllSubmatch(data, -1) {
+			if _, ok := tables[DefaultLanguage][string(m[1])]; !ok {
+				t.Errorf("%s: key %s isn't in the English table", path, m[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
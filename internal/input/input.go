@@ -2,6 +2,9 @@
 package input
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -14,14 +17,87 @@ const (
 	ActionMoveUp
 	ActionMoveDown
 	ActionJump
+	ActionCrouch
 	ActionQuit
 	ActionDebugToggle
+	ActionPostFXToggle
+	ActionFullscreenToggle
+	ActionEditToggle
+	ActionProfilerToggle
+	ActionPauseToggle
+	ActionStepFrame
 )
 
+// actionNames maps action names used in config files to actions.
+var actionNames = map[string]Action{
+	"moveLeft":         ActionMoveLeft,
+	"moveRight":        ActionMoveRight,
+	"moveUp":           ActionMoveUp,
+	"moveDown":         ActionMoveDown,
+	"jump":             ActionJump,
+	"crouch":           ActionCrouch,
+	"quit":             ActionQuit,
+	"debugToggle":      ActionDebugToggle,
+	"postfxToggle":     ActionPostFXToggle,
+	"fullscreenToggle": ActionFullscreenToggle,
+	"editToggle":       ActionEditToggle,
+	"profilerToggle":   ActionProfilerToggle,
+	"pauseToggle":      ActionPauseToggle,
+	"stepFrame":        ActionStepFrame,
+}
+
+// bindingOverrides replace the default keys of actions for every Input
+// created afterwards. Set with SetBindings.
+var bindingOverrides = map[Action][]ebiten.Key{}
+
+// SetBindings replaces the default keys for the named actions, e.g.
+// {"jump": {"Space", "Z"}}. Key names are ebiten key names. It affects every
+// Input created afterwards, so call it at startup before creating scenes.
+func SetBindings(bindings map[string][]string) error {
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parsed := make(map[Action][]ebiten.Key, len(bindings))
+	for _, name := range names {
+		action, ok := actionNames[name]
+		if !ok {
+			return fmt.Errorf("unknown action %q", name)
+		}
+		keys := make([]ebiten.Key, 0, len(bindings[name]))
+		for _, keyName := range bindings[name] {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(keyName)); err != nil {
+				return fmt.Errorf("action %q: unknown key %q", name, keyName)
+			}
+			keys = append(keys, key)
+		}
+		parsed[action] = keys
+	}
+
+	for action, keys := range parsed {
+		bindingOverrides[action] = keys
+	}
+	return nil
+}
+
 // Input manages keyboard input with action mappings.
 type Input struct {
 	keyMap      map[Action][]ebiten.Key
 	prevPressed map[ebiten.Key]bool
+
+	// cursorTransform maps window cursor positions to scene coordinates
+	cursorTransform func(x, y float64) (float64, float64)
+
+	// Gamepad buttons held last frame, for JustPressed
+	prevTouch map[Action]bool
+
+	// Scripted input (see NewScriptedInput); nil reads the keyboard
+	script *Script
+	tick   int
 }
 
 // NewInput creates a new Input manager with default key mappings.
@@ -29,6 +105,7 @@ func NewInput() *Input {
 	i := &Input{
 		keyMap:      make(map[Action][]ebiten.Key),
 		prevPressed: make(map[ebiten.Key]bool),
+		prevTouch:   make(map[Action]bool),
 	}
 
 	// Default key mappings
@@ -38,14 +115,33 @@ func NewInput() *Input {
 	i.keyMap[ActionMoveUp] = []ebiten.Key{ebiten.KeyArrowUp, ebiten.KeyW}
 	i.keyMap[ActionMoveDown] = []ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyS}
 	i.keyMap[ActionJump] = []ebiten.Key{ebiten.KeySpace, ebiten.KeyZ}
+	i.keyMap[ActionCrouch] = []ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyS}
 	i.keyMap[ActionQuit] = []ebiten.Key{ebiten.KeyEscape}
 	i.keyMap[ActionDebugToggle] = []ebiten.Key{ebiten.KeyF1}
+	i.keyMap[ActionPostFXToggle] = []ebiten.Key{ebiten.KeyF10}
+	i.keyMap[ActionFullscreenToggle] = []ebiten.Key{ebiten.KeyF11}
+	i.keyMap[ActionEditToggle] = []ebiten.Key{ebiten.KeyF12}
+	i.keyMap[ActionProfilerToggle] = []ebiten.Key{ebiten.KeyF7}
+	i.keyMap[ActionPauseToggle] = []ebiten.Key{ebiten.KeyF8}
+	i.keyMap[ActionStepFrame] = []ebiten.Key{ebiten.KeyF9}
+
+	// Keybinds from config
+	for action, keys := range bindingOverrides {
+		i.keyMap[action] = keys
+	}
 
 	return i
 }
 
-// Pressed returns true if any key mapped to the action is currently pressed.
+// Pressed returns true if any key mapped to the action is currently pressed,
+// or its touch button is held.
 func (i *Input) Pressed(action Action) bool {
+	if i.script != nil {
+		return i.script.Pressed(action, i.tick)
+	}
+	if gamepad.Pressed(action) {
+		return true
+	}
 	keys, ok := i.keyMap[action]
 	if !ok {
 		return false
@@ -59,8 +155,15 @@ func (i *Input) Pressed(action Action) bool {
 	return false
 }
 
-// JustPressed returns true if any key mapped to the action was just pressed this frame.
+// JustPressed returns true if any key mapped to the action was just pressed
+// this frame, or its touch button was just touched.
 func (i *Input) JustPressed(action Action) bool {
+	if i.script != nil {
+		return i.script.Pressed(action, i.tick) && !i.script.Pressed(action, i.tick-1)
+	}
+	if gamepad.Pressed(action) && !i.prevTouch[action] {
+		return true
+	}
 	keys, ok := i.keyMap[action]
 	if !ok {
 		return false
@@ -74,13 +177,50 @@ func (i *Input) JustPressed(action Action) bool {
 	return false
 }
 
+// SetCursorTransform sets how window cursor positions map to scene
+// coordinates, e.g. when the scene is scaled into the window.
+func (i *Input) SetCursorTransform(fn func(x, y float64) (float64, float64)) {
+	i.cursorTransform = fn
+}
+
+// CursorPosition returns the mouse cursor position in scene coordinates.
+func (i *Input) CursorPosition() (float64, float64) {
+	cx, cy := ebiten.CursorPosition()
+	x, y := float64(cx), float64(cy)
+	if i.cursorTransform != nil {
+		return i.cursorTransform(x, y)
+	}
+	return x, y
+}
+
 // Update updates the previous frame's key states.
 // This should be called once per frame, typically at the start of the game loop.
+// A scripted Input advances to its script's next tick instead.
 func (i *Input) Update() {
+	if i.script != nil {
+		i.tick++
+		return
+	}
+
 	// Clear previous pressed state and update with current state
 	for _, keys := range i.keyMap {
 		for _, key := range keys {
 			i.prevPressed[key] = ebiten.IsKeyPressed(key)
 		}
 	}
+	gamepad.update(i.prevTouch)
+}
+
+// Touch returns the on-screen gamepad.
+func Touch() *TouchControls {
+	return gamepad
+}
+
+// DrawTouch draws the on-screen gamepad over screen and lays its buttons out
+// for it, mapping touches with this Input's cursor transform; draw it on the
+// scene's screen with the Input that has one. It draws nothing until the
+// gamepad is active.
+func (i *Input) DrawTouch(screen *ebiten.Image) {
+	gamepad.transform = i.cursorTransform
+	gamepad.draw(screen)
 }
@@ -0,0 +1,77 @@
+package input
+
+// Script is a timeline of held actions that drives an Input instead of the
+// keyboard, so tests can play the same inputs tick by tick (see
+// NewScriptedInput). Scripts are built with Hold, or recorded from a played
+// Input with Record.
+type Script struct {
+	holds  []hold
+	length int // Ticks recorded so far
+}
+
+// hold is an action held from tick from up to, but not including, tick to.
+type hold struct {
+	action   Action
+	from, to int
+}
+
+// NewScript creates an empty script.
+func NewScript() *Script {
+	return &Script{}
+}
+
+// Hold holds action for ticks ticks starting at tick from; a 1-tick hold is a
+// tap. Returns the script for chaining.
+func (s *Script) Hold(action Action, from, ticks int) *Script {
+	if ticks > 0 {
+		s.holds = append(s.holds, hold{action: action, from: from, to: from + ticks})
+	}
+	return s
+}
+
+// Pressed returns whether action is held at tick.
+func (s *Script) Pressed(action Action, tick int) bool {
+	for _, h := range s.holds {
+		if h.action == action && tick >= h.from && tick < h.to {
+			return true
+		}
+	}
+	return false
+}
+
+// Record appends the actions held on inp this tick as the script's next
+// tick, so a played session can be replayed. Call it once per tick.
+func (s *Script) Record(inp *Input) {
+	for _, action := range actionNames {
+		if !inp.Pressed(action) {
+			continue
+		}
+		// Extend the action's hold if it was held last tick too
+		extended := false
+		for i := range s.holds {
+			if s.holds[i].action == action && s.holds[i].to == s.length {
+				s.holds[i].to++
+				extended = true
+				break
+			}
+		}
+		if !extended {
+			s.Hold(action, s.length, 1)
+		}
+	}
+	s.length++
+}
+
+// NewScriptedInput creates an Input whose actions come from script rather
+// than the keyboard. It starts at tick 0; each Update advances one tick.
+func NewScriptedInput(script *Script) *Input {
+	i := NewInput()
+	i.script = script
+	return i
+}
+
+// Tick returns the script tick a scripted Input is at, or 0 for a keyboard
+// Input.
+func (i *Input) Tick() int {
+	return i.tick
+}
@@ -0,0 +1,52 @@
+//go:build display
+
+package input
+
+import "testing"
+
+func TestScriptedInput(t *testing.T) {
+	inp := NewScriptedInput(NewScript().Hold(ActionJump, 2, 3).Hold(ActionMoveRight, 0, 1))
+
+	var pressed, just []bool
+	for tick := 0; tick < 6; tick++ {
+		pressed = append(pressed, inp.Pressed(ActionJump))
+		just = append(just, inp.JustPressed(ActionJump))
+		if got := inp.Pressed(ActionMoveRight); got != (tick == 0) {
+			t.Errorf("tick %d: move right pressed = %v", tick, got)
+		}
+		inp.Update()
+	}
+
+	wantPressed := []bool{false, false, true, true, true, false}
+	wantJust := []bool{false, false, true, false, false, false}
+	for tick := range wantPressed {
+		if pressed[tick] != wantPressed[tick] || just[tick] != wantJust[tick] {
+			t.Errorf("tick %d: jump pressed %v, just pressed %v; want %v, %v",
+				tick, pressed[tick], just[tick], wantPressed[tick], wantJust[tick])
+		}
+	}
+	if inp.Tick() != 6 {
+		t.Errorf("Tick() = %d after 6 updates, want 6", inp.Tick())
+	}
+}
+
+func TestScriptRecordReplays(t *testing.T) {
+	played := NewScriptedInput(NewScript().Hold(ActionJump, 1, 2).Hold(ActionMoveLeft, 0, 4))
+	recorded := NewScript()
+	for tick := 0; tick < 5; tick++ {
+		recorded.Record(played)
+		played.Update()
+	}
+
+	for tick := 0; tick < 5; tick++ {
+		for _, action := range []Action{ActionJump, ActionMoveLeft, ActionMoveRight} {
+			want := played.script.Pressed(action, tick)
+			if got := recorded.Pressed(action, tick); got != want {
+				t.Errorf("tick %d action %d: recorded %v, played %v", tick, action, got, want)
+			}
+		}
+	}
+	if len(recorded.holds) != 2 {
+		t.Errorf("recorded %d holds, want one per held action", len(recorded.holds))
+	}
+}
@@ -0,0 +1,202 @@
+package input
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+)
+
+// TouchMode selects when the on-screen gamepad is shown.
+type TouchMode string
+
+const (
+	// TouchAuto shows the gamepad from the first touch on, so it appears on
+	// touch devices only.
+	TouchAuto TouchMode = "auto"
+	// TouchOn always shows the gamepad.
+	TouchOn TouchMode = "on"
+	// TouchOff never shows the gamepad.
+	TouchOff TouchMode = "off"
+)
+
+// TouchOptions configure the on-screen gamepad. Zero values keep the
+// default: auto mode, 48px buttons at 40% opacity.
+type TouchOptions struct {
+	Mode    TouchMode
+	Size    float64 // Button diameter in scene pixels
+	Opacity float64 // 0-1
+}
+
+// gamepad is the on-screen gamepad. There is one screen, so every Input
+// shares it.
+var gamepad = newTouchControls(TouchOptions{Mode: TouchAuto, Size: 48, Opacity: 0.4})
+
+// SetTouchOptions configures the on-screen gamepad. Zero fields keep their
+// default.
+func SetTouchOptions(o TouchOptions) error {
+	switch o.Mode {
+	case "", TouchAuto, TouchOn, TouchOff:
+	default:
+		return fmt.Errorf("unknown touch mode %q", o.Mode)
+	}
+	if o.Size < 0 || o.Opacity < 0 || o.Opacity > 1 {
+		return fmt.Errorf("invalid touch buttons: size %g, opacity %g", o.Size, o.Opacity)
+	}
+	if o.Mode != "" {
+		gamepad.options.Mode = o.Mode
+		gamepad.active = o.Mode == TouchOn
+	}
+	if o.Size > 0 {
+		gamepad.options.Size = o.Size
+	}
+	if o.Opacity > 0 {
+		gamepad.options.Opacity = o.Opacity
+	}
+	return nil
+}
+
+// Touch buttons are hit a little outside the drawn circle, since fingers
+// are imprecise.
+const touchHitScale = 1.2
+
+var touchButtonColor = color.RGBA{255, 255, 255, 255}
+
+// touchButton is one button of the gamepad. Its position is in scene
+// coordinates and set by layout.
+type touchButton struct {
+	action Action
+	label  string
+	x, y   float64 // Center
+}
+
+// touchPoint is a touch position in scene coordinates.
+type touchPoint struct {
+	x, y float64
+}
+
+// TouchControls is an on-screen gamepad with left, right and jump buttons
+// for touch screens. Every finger on a button holds its action, so moving
+// and jumping at the same time works, and sliding a finger from one button
+// to the other switches between them. Inputs report its buttons through
+// Pressed and JustPressed like keys.
+type TouchControls struct {
+	options TouchOptions
+	buttons []touchButton
+	laidOut bool // Buttons have been placed by layout
+	active  bool // Shown; in auto mode set by the first touch
+
+	// transform maps touch positions to the coordinates the buttons were
+	// laid out in; set by the Input that draws the gamepad
+	transform func(x, y float64) (float64, float64)
+
+	// Buffers reused every query
+	ids    []ebiten.TouchID
+	points []touchPoint
+}
+
+// newTouchControls creates a gamepad with the given options.
+func newTouchControls(o TouchOptions) *TouchControls {
+	return &TouchControls{
+		options: o,
+		buttons: []touchButton{
+			{action: ActionMoveLeft, label: "<"},
+			{action: ActionMoveRight, label: ">"},
+			{action: ActionJump, label: "^"},
+		},
+		active: o.Mode == TouchOn,
+	}
+}
+
+// Active reports whether the gamepad is shown and its buttons work.
+func (t *TouchControls) Active() bool {
+	return t.active
+}
+
+// layout places the buttons in a w x h scene: left and right at the bottom
+// left, jump at the bottom right.
+func (t *TouchControls) layout(w, h float64) {
+	size := t.options.Size
+	margin := size / 2
+	y := h - margin - size/2
+	t.buttons[0].x, t.buttons[0].y = margin+size/2, y
+	t.buttons[1].x, t.buttons[1].y = margin+size*1.75, y
+	t.buttons[2].x, t.buttons[2].y = w-margin-size/2, y
+	t.laidOut = true
+}
+
+// Pressed returns whether a finger is on the button for action.
+func (t *TouchControls) Pressed(action Action) bool {
+	return t.pressedBy(action, t.touches())
+}
+
+// pressedBy returns whether a point in points is on the button for action.
+func (t *TouchControls) pressedBy(action Action, points []touchPoint) bool {
+	if !t.active || !t.laidOut {
+		return false
+	}
+	r := t.options.Size / 2 * touchHitScale
+	for _, b := range t.buttons {
+		if b.action != action {
+			continue
+		}
+		for _, p := range points {
+			if math.Hypot(p.x-b.x, p.y-b.y) <= r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// touches returns the current touch positions in scene coordinates.
+func (t *TouchControls) touches() []touchPoint {
+	t.ids = ebiten.AppendTouchIDs(t.ids[:0])
+	t.points = t.points[:0]
+	for _, id := range t.ids {
+		tx, ty := ebiten.TouchPosition(id)
+		x, y := float64(tx), float64(ty)
+		if t.transform != nil {
+			x, y = t.transform(x, y)
+		}
+		t.points = append(t.points, touchPoint{x, y})
+	}
+	return t.points
+}
+
+// update records the actions held this frame into held, and activates the
+// gamepad on the first touch in auto mode. Called by Input.Update.
+func (t *TouchControls) update(held map[Action]bool) {
+	points := t.touches()
+	if len(points) > 0 && t.options.Mode == TouchAuto {
+		t.active = true
+	}
+	for _, b := range t.buttons {
+		held[b.action] = t.pressedBy(b.action, points)
+	}
+}
+
+// draw lays the buttons out for screen and draws them, brighter while held.
+func (t *TouchControls) draw(screen *ebiten.Image) {
+	if !t.active {
+		return
+	}
+	points := t.touches()
+	bounds := screen.Bounds()
+	t.layout(float64(bounds.Dx()), float64(bounds.Dy()))
+
+	r := t.options.Size / 2
+	for _, b := range t.buttons {
+		alpha := t.options.Opacity
+		if t.pressedBy(b.action, points) {
+			alpha = math.Min(1, alpha*2)
+		}
+		draw.FillCircle(screen, b.x, b.y, r, draw.Fade(touchButtonColor, alpha*0.5))
+		draw.StrokeCircle(screen, b.x, b.y, r, 2, draw.Fade(touchButtonColor, alpha))
+		// The debug font is 6x16 pixels per character
+		ebitenutil.DebugPrintAt(screen, b.label, int(b.x)-3, int(b.y)-8)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build display
+
+package input
+
+import "testing"
+
+func TestTouchButtons(t *testing.T) {
+	pad := newTouchControls(TouchOptions{Mode: TouchOn, Size: 40, Opacity: 0.5})
+	pad.layout(640, 360)
+
+	// Left is centered at (40, 320), right at (90, 320), jump at (600, 320)
+	left := touchPoint{40, 320}
+	right := touchPoint{95, 325}
+	jump := touchPoint{600, 330}
+
+	tests := []struct {
+		name   string
+		points []touchPoint
+		want   map[Action]bool
+	}{
+		{"none", nil, map[Action]bool{}},
+		{"left", []touchPoint{left}, map[Action]bool{ActionMoveLeft: true}},
+		{"run and jump", []touchPoint{right, jump}, map[Action]bool{ActionMoveRight: true, ActionJump: true}},
+		{"just outside the circle", []touchPoint{{40, 297}}, map[Action]bool{ActionMoveLeft: true}},
+		{"far away", []touchPoint{{320, 100}}, map[Action]bool{}},
+	}
+	for _, tt := range tests {
+		for _, action := range []Action{ActionMoveLeft, ActionMoveRight, ActionJump} {
+			if got := pad.pressedBy(action, tt.points); got != tt.want[action] {
+				t.Errorf("%s: action %d pressed = %v, want %v", tt.name, action, got, tt.want[action])
+			}
+		}
+	}
+}
+
+func TestTouchInactive(t *testing.T) {
+	pad := newTouchControls(TouchOptions{Mode: TouchAuto, Size: 40, Opacity: 0.5})
+	pad.layout(640, 360)
+	if pad.Active() {
+		t.Fatal("auto gamepad is active before any touch")
+	}
+	if pad.pressedBy(ActionMoveLeft, []touchPoint{{40, 320}}) {
+		t.Error("inactive gamepad reported a press")
+	}
+}
+
+func TestSetTouchOptions(t *testing.T) {
+	defer func(o TouchOptions, active bool) {
+		gamepad.options, gamepad.active = o, active
+	}(gamepad.options, gamepad.active)
+
+	if err := SetTouchOptions(TouchOptions{Mode: TouchOn, Size: 64}); err != nil {
+		t.Fatal(err)
+	}
+	if o := gamepad.options; o.Mode != TouchOn || o.Size != 64 || o.Opacity != 0.4 || !gamepad.Active() {
+		t.Errorf("options = %+v, active %v; want on, 64px, default opacity", o, gamepad.Active())
+	}
+	for _, bad := range []TouchOptions{{Mode: "sometimes"}, {Opacity: 2}, {Size: -1}} {
+		if err := SetTouchOptions(bad); err == nil {
+			t.Errorf("SetTouchOptions(%+v) succeeded, want error", bad)
+		}
+	}
+}
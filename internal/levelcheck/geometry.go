@@ -0,0 +1,156 @@
+package levelcheck
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// maxSolidOverlap is how much of the smaller of two solid objects may be
+// covered by the other before it's reported, as a fraction of its area.
+const maxSolidOverlap = 0.5
+
+// validateGeometry checks where objects are placed: inside the map, not
+// buried in solid tiles, and not stacked on top of each other.
+func validateGeometry(level Level, result *ValidationResult) {
+	if level.Map == nil {
+		return
+	}
+	validateObjectBounds(level, result)
+	validateEmbeddedObjects(level, result)
+	validateSolidOverlaps(level, result)
+}
+
+// validateObjectBounds warns about objects outside the map. Kill planes are
+// skipped: they are often placed below the map on purpose.
+func validateObjectBounds(level Level, result *ValidationResult) {
+	mapW := float64(level.Map.Width() * level.Map.TileWidth())
+	mapH := float64(level.Map.Height() * level.Map.TileHeight())
+	for i, obj := range level.Objects {
+		if obj.Type == world.ObjectTypeKillPlane {
+			continue
+		}
+		switch {
+		case obj.X >= mapW || obj.Y >= mapH || obj.X+obj.W < 0 || obj.Y+obj.H < 0:
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Object is outside the map",
+				Property:    "",
+			})
+		case obj.X < 0 || obj.Y < 0 || obj.X+obj.W > mapW || obj.Y+obj.H > mapH:
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Object extends past the edge of the map",
+				Property:    "",
+			})
+		}
+	}
+}
+
+// validateEmbeddedObjects warns about spawns and goals whose center is in a
+// solid tile, where the player would be stuck or couldn't get to, and about
+// hazards entirely covered by solid tiles, which can never be touched.
+func validateEmbeddedObjects(level Level, result *ValidationResult) {
+	collision := level.Map.Layer("Collision")
+	if collision == nil {
+		return
+	}
+	g := newTileGrid(level.Map, collision)
+
+	for i, obj := range level.Objects {
+		var message string
+		switch obj.Type {
+		case world.ObjectTypeSpawn, world.ObjectTypeGoal:
+			if t := g.tileAt(obj.X+obj.W/2, obj.Y+obj.H/2); t >= 0 && g.solid[t] {
+				message = fmt.Sprintf("%s is inside solid collision tiles", objectLabel(obj.Type))
+			}
+		case world.ObjectTypeHazard:
+			if g.covered(obj) {
+				message = "Hazard is completely covered by solid tiles and can't be touched"
+			}
+		}
+		if message != "" {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     message,
+				Property:    "",
+			})
+		}
+	}
+}
+
+// objectLabel returns the name the geometry messages use for an object type.
+func objectLabel(typ world.ObjectType) string {
+	if typ == world.ObjectTypeSpawn {
+		return "Spawn point"
+	}
+	return "Goal"
+}
+
+// covered returns whether every tile under the object is solid. Objects
+// reaching outside the map aren't covered.
+func (g *tileGrid) covered(obj world.ObjectData) bool {
+	if obj.W <= 0 || obj.H <= 0 {
+		return false
+	}
+	for ty := int(math.Floor(obj.Y / g.tileH)); float64(ty)*g.tileH < obj.Y+obj.H; ty++ {
+		for tx := int(math.Floor(obj.X / g.tileW)); float64(tx)*g.tileW < obj.X+obj.W; tx++ {
+			if tx < 0 || ty < 0 || tx >= g.w || ty >= g.h || !g.solid[ty*g.w+tx] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validateSolidOverlaps warns about doors and platforms that mostly overlap
+// another one, usually a copy placed by accident. The later object gets the
+// warning.
+func validateSolidOverlaps(level Level, result *ValidationResult) {
+	for j, b := range level.Objects {
+		if !isSolidObject(b) {
+			continue
+		}
+		for i := 0; i < j; i++ {
+			a := level.Objects[i]
+			if !isSolidObject(a) {
+				continue
+			}
+			overlap := overlapArea(a, b)
+			smaller := math.Min(a.W*a.H, b.W*b.H)
+			if smaller <= 0 || overlap/smaller < maxSolidOverlap {
+				continue
+			}
+			other := string(a.Type)
+			if id := a.GetPropString("id", ""); id != "" {
+				other += fmt.Sprintf(" '%s'", id)
+			}
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: j,
+				Message:     fmt.Sprintf("Overlaps %s by %.0f%%", other, overlap/smaller*100),
+				Property:    "",
+			})
+			break
+		}
+	}
+}
+
+// isSolidObject returns whether the player collides with the object.
+func isSolidObject(obj world.ObjectData) bool {
+	return obj.Type == world.ObjectTypeDoor || obj.Type == world.ObjectTypePlatform
+}
+
+// overlapArea returns the area two objects' rectangles share.
+func overlapArea(a, b world.ObjectData) float64 {
+	w := math.Min(a.X+a.W, b.X+b.W) - math.Max(a.X, b.X)
+	h := math.Min(a.Y+a.H, b.Y+b.H) - math.Max(a.Y, b.Y)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
@@ -0,0 +1,256 @@
+package levelcheck
+
+import (
+	"fmt"
+
+	"github.com/torsten/GoP/internal/gameplay"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// lockedDoor is a locked door found in a level.
+type lockedDoor struct {
+	index int // Object index
+	obj   world.ObjectData
+	keys  int
+	keyID string
+}
+
+// lockedDoors returns the level's locked doors.
+func lockedDoors(level Level) []lockedDoor {
+	var doors []lockedDoor
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeDoor || !obj.GetPropBool("locked", false) {
+			continue
+		}
+		keys := int(obj.GetPropFloat("keys", 1))
+		if keys < 1 {
+			keys = 1
+		}
+		doors = append(doors, lockedDoor{index: i, obj: obj, keys: keys, keyID: obj.GetPropString("key_id", "")})
+	}
+	return doors
+}
+
+// validateLockedDoors checks that every locked door has matching keys and
+// that the keys can be reached.
+func validateLockedDoors(level Level, result *ValidationResult) {
+	doors := lockedDoors(level)
+	if len(doors) == 0 {
+		return
+	}
+
+	keyIDs := make(map[string]bool)
+	totalKeys := 0
+	for _, obj := range level.Objects {
+		if obj.Type == world.ObjectTypeKey {
+			keyIDs[obj.GetPropString("id", obj.Name)] = true
+			totalKeys++
+		}
+	}
+
+	needed := 0
+	for _, d := range doors {
+		if d.keyID == "" {
+			needed += d.keys
+			continue
+		}
+		needed++
+		if !keyIDs[d.keyID] {
+			result.Errors = append(result.Errors, ValidationError{
+				Type:        TypeError,
+				ObjectIndex: d.index,
+				Message:     fmt.Sprintf("Locked door needs key '%s', but no key has that ID", d.keyID),
+				Property:    "key_id",
+			})
+		}
+	}
+	if needed > totalKeys {
+		result.Errors = append(result.Errors, ValidationError{
+			Type:        TypeError,
+			ObjectIndex: -1,
+			Message:     fmt.Sprintf("Locked doors need %d keys in total, but the level has %d", needed, totalKeys),
+			Property:    "",
+		})
+		return
+	}
+
+	validateKeyReachability(level, doors, result)
+}
+
+// validateKeyReachability walks the open tiles from the spawn point,
+// picking up keys and unlocking doors as it goes, and warns about keys and
+// locked doors the walk never gets to. The walk ignores gravity and treats
+// switch doors as open, so it only catches keys that are certainly out of reach.
+func validateKeyReachability(level Level, doors []lockedDoor, result *ValidationResult) {
+	if level.Map == nil {
+		return
+	}
+	collision := level.Map.Layer("Collision")
+	spawnX, spawnY, found := world.FindSpawnPoint(level.Objects)
+	if collision == nil || !found {
+		return
+	}
+
+	g := newTileGrid(level.Map, collision)
+	for i, d := range doors {
+		g.markDoor(d.obj, i)
+	}
+
+	progress := gameplay.NewLevelProgress(nil)
+	collected := make(map[int]bool)
+	opened := make(map[int]bool)
+	for {
+		reached := g.fill(g.tileAt(spawnX+1, spawnY+1))
+
+		for i, obj := range level.Objects {
+			if obj.Type == world.ObjectTypeKey && !collected[i] && reached[g.tileAt(obj.X+obj.W/2, obj.Y+obj.H/2)] {
+				collected[i] = true
+				progress.PickUpKey(obj.GetPropString("id", obj.Name))
+			}
+		}
+
+		unlocked := false
+		for i, d := range doors {
+			if !opened[i] && g.doorTouches(i, reached) && progress.UseKeys(d.keys, d.keyID) {
+				opened[i] = true
+				g.openDoor(i)
+				unlocked = true
+			}
+		}
+		if !unlocked {
+			break
+		}
+	}
+
+	for i, obj := range level.Objects {
+		if obj.Type == world.ObjectTypeKey && !collected[i] {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Key can't be reached from the spawn point",
+				Property:    "",
+			})
+		}
+	}
+	for i, d := range doors {
+		if !opened[i] {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: d.index,
+				Message:     "Locked door can't be opened with the keys reachable before it",
+				Property:    "locked",
+			})
+		}
+	}
+}
+
+// tileGrid is the level's walkable area for the reachability walk.
+type tileGrid struct {
+	w, h         int
+	tileW, tileH float64
+	solid        []bool
+	door         []int // Locked door index per tile, or -1
+}
+
+func newTileGrid(m *world.MapData, collision *world.TileLayer) *tileGrid {
+	g := &tileGrid{
+		w:     m.Width(),
+		h:     m.Height(),
+		tileW: float64(m.TileWidth()),
+		tileH: float64(m.TileHeight()),
+	}
+	g.solid = make([]bool, g.w*g.h)
+	g.door = make([]int, g.w*g.h)
+	for ty := 0; ty < g.h; ty++ {
+		for tx := 0; tx < g.w; tx++ {
+			g.solid[ty*g.w+tx] = collision.TileAt(tx, ty) != 0
+			g.door[ty*g.w+tx] = -1
+		}
+	}
+	return g
+}
+
+// tileAt returns the tile index at a world position, or -1 outside the map.
+func (g *tileGrid) tileAt(x, y float64) int {
+	tx, ty := int(x/g.tileW), int(y/g.tileH)
+	if x < 0 || y < 0 || tx >= g.w || ty >= g.h {
+		return -1
+	}
+	return ty*g.w + tx
+}
+
+// markDoor marks the tiles a locked door covers.
+func (g *tileGrid) markDoor(obj world.ObjectData, door int) {
+	for ty := int(obj.Y / g.tileH); float64(ty)*g.tileH < obj.Y+obj.H && ty < g.h; ty++ {
+		for tx := int(obj.X / g.tileW); float64(tx)*g.tileW < obj.X+obj.W && tx < g.w; tx++ {
+			if tx >= 0 && ty >= 0 {
+				g.door[ty*g.w+tx] = door
+			}
+		}
+	}
+}
+
+// openDoor makes a door's tiles walkable.
+func (g *tileGrid) openDoor(door int) {
+	for i, d := range g.door {
+		if d == door {
+			g.door[i] = -1
+		}
+	}
+}
+
+// fill returns the tiles reachable from start without crossing solid tiles
+// or locked doors.
+func (g *tileGrid) fill(start int) map[int]bool {
+	reached := make(map[int]bool)
+	if start < 0 || g.solid[start] || g.door[start] >= 0 {
+		return reached
+	}
+	queue := []int{start}
+	reached[start] = true
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, n := range g.neighbors(t) {
+			if !reached[n] && !g.solid[n] && g.door[n] < 0 {
+				reached[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return reached
+}
+
+// doorTouches returns whether a locked door borders the reached tiles.
+func (g *tileGrid) doorTouches(door int, reached map[int]bool) bool {
+	for t, d := range g.door {
+		if d != door {
+			continue
+		}
+		for _, n := range g.neighbors(t) {
+			if reached[n] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// neighbors returns the tiles next to t in the four directions.
+func (g *tileGrid) neighbors(t int) []int {
+	x, y := t%g.w, t/g.w
+	var n []int
+	if x > 0 {
+		n = append(n, t-1)
+	}
+	if x < g.w-1 {
+		n = append(n, t+1)
+	}
+	if y > 0 {
+		n = append(n, t-g.w)
+	}
+	if y < g.h-1 {
+		n = append(n, t+g.w)
+	}
+	return n
+}
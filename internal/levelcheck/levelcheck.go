@@ -0,0 +1,692 @@
+// Package levelcheck validates level data: spawn points, IDs, switch and door
+// links, locked doors and their keys, goal requirements, object placement, and
+// complexity budgets.
+//
+// The editor, the game, and command-line tools share these checks so a level
+// that passes in one passes everywhere.
+package levelcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/music"
+	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/schema"
+	"github.com/torsten/GoP/internal/sound"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/tween"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// ErrorType represents the type of validation issue.
+type ErrorType string
+
+const (
+	// TypeError represents a critical error that must be fixed.
+	TypeError ErrorType = "error"
+	// TypeWarning represents a warning that should be fixed.
+	TypeWarning ErrorType = "warning"
+)
+
+// ValidationError represents a single validation issue found in the level.
+type ValidationError struct {
+	Type        ErrorType // "error" or "warning"
+	ObjectIndex int       // Index of the object with the issue (-1 for global errors)
+	Message     string    // Human-readable description of the issue
+	Property    string    // Property name if applicable (empty string if not)
+}
+
+// ValidationResult holds the result of level validation.
+type ValidationResult struct {
+	Errors   []ValidationError // Critical errors that must be fixed
+	Warnings []ValidationError // Warnings that should be fixed
+}
+
+// HasErrors returns true if there are any critical errors.
+func (r *ValidationResult) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// HasWarnings returns true if there are any warnings.
+func (r *ValidationResult) HasWarnings() bool {
+	return len(r.Warnings) > 0
+}
+
+// HasIssues returns true if there are any errors or warnings.
+func (r *ValidationResult) HasIssues() bool {
+	return r.HasErrors() || r.HasWarnings()
+}
+
+// AllIssues returns all errors and warnings combined.
+func (r *ValidationResult) AllIssues() []ValidationError {
+	all := make([]ValidationError, 0, len(r.Errors)+len(r.Warnings))
+	all = append(all, r.Errors...)
+	all = append(all, r.Warnings...)
+	return all
+}
+
+// ErrorCount returns the number of critical errors.
+func (r *ValidationResult) ErrorCount() int {
+	return len(r.Errors)
+}
+
+// WarningCount returns the number of warnings.
+func (r *ValidationResult) WarningCount() int {
+	return len(r.Warnings)
+}
+
+// GetObjectErrors returns all errors for a specific object index.
+func (r *ValidationResult) GetObjectErrors(objectIndex int) []ValidationError {
+	var errors []ValidationError
+	for _, e := range r.Errors {
+		if e.ObjectIndex == objectIndex {
+			errors = append(errors, e)
+		}
+	}
+	return errors
+}
+
+// GetObjectWarnings returns all warnings for a specific object index.
+func (r *ValidationResult) GetObjectWarnings(objectIndex int) []ValidationError {
+	var warnings []ValidationError
+	for _, w := range r.Warnings {
+		if w.ObjectIndex == objectIndex {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// GetObjectIssues returns all errors and warnings for a specific object index.
+func (r *ValidationResult) GetObjectIssues(objectIndex int) []ValidationError {
+	var issues []ValidationError
+	for _, e := range r.Errors {
+		if e.ObjectIndex == objectIndex {
+			issues = append(issues, e)
+		}
+	}
+	for _, w := range r.Warnings {
+		if w.ObjectIndex == objectIndex {
+			issues = append(issues, w)
+		}
+	}
+	return issues
+}
+
+// Level is the data a check runs on.
+type Level struct {
+	Map     *world.MapData
+	Objects []world.ObjectData
+	// RuleCount is the number of rules in the level's rules file (see RuleCount)
+	RuleCount int
+	// Path is the level's file, which exits to other levels are relative
+	// to. It may be empty, leaving those exits unchecked.
+	Path string
+}
+
+// ParseLevel parses Tiled JSON level data for checking. levelPath locates the
+// level's rules file and may be empty.
+func ParseLevel(data []byte, levelPath string) (Level, error) {
+	mapData, err := world.ParseTiledJSON(data)
+	if err != nil {
+		return Level{}, err
+	}
+	objects, err := world.ParseObjects(data)
+	if err != nil {
+		return Level{}, err
+	}
+	return Level{Map: mapData, Objects: objects, RuleCount: RuleCount(levelPath), Path: levelPath}, nil
+}
+
+// Validate checks the level and returns the issues found.
+func Validate(level Level) *ValidationResult {
+	result := &ValidationResult{
+		Errors:   make([]ValidationError, 0),
+		Warnings: make([]ValidationError, 0),
+	}
+
+	// Check for spawn points
+	validateSpawnPoints(level, result)
+
+	// Check for duplicate IDs
+	validateUniqueIDs(level, result)
+
+	// Check switch references
+	validateSwitchReferences(level, result)
+
+	// Check for doors without switches
+	validateDoorSwitches(level, result)
+
+	// Check locked doors have reachable keys
+	validateLockedDoors(level, result)
+
+	// Check for platforms with no movement
+	validatePlatforms(level, result)
+
+	// Check door and platform easing curves
+	validateEases(level, result)
+
+	// Check hazard directions and platforms
+	validateHazards(level, result)
+
+	// Check goal requirements can be met
+	validateGoalRequirements(level, result)
+
+	// Check exits lead to existing spawns
+	validateExits(level, result)
+
+	// Check sound emitters have a clip and falloff
+	validateSoundEmitters(level, result)
+
+	// Check objects are inside the map, clear of solid tiles and each other
+	validateGeometry(level, result)
+
+	// Check the level stays within its complexity budget
+	validateBudget(level, result)
+
+	return result
+}
+
+// validateSpawnPoints checks for player spawn points.
+func validateSpawnPoints(level Level, result *ValidationResult) {
+	spawns := world.FilterObjectsByType(level.Objects, world.ObjectTypeSpawn)
+
+	if len(spawns) == 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Type:        TypeError,
+			ObjectIndex: -1,
+			Message:     "No player spawn point defined",
+			Property:    "",
+		})
+		return
+	}
+
+	// Spawns with an id are arrival points for exits from other rooms
+	unnamed := 0
+	for _, obj := range spawns {
+		if obj.GetPropString("id", obj.Name) == "" {
+			unnamed++
+		}
+	}
+	if unnamed > 1 {
+		result.Warnings = append(result.Warnings, ValidationError{
+			Type:        TypeWarning,
+			ObjectIndex: -1,
+			Message:     fmt.Sprintf("Multiple spawn points defined (%d), only the first will be used", unnamed),
+			Property:    "",
+		})
+	}
+}
+
+// validateUniqueIDs checks for duplicate entity IDs.
+func validateUniqueIDs(level Level, result *ValidationResult) {
+	// Map from ID to the first object index that uses it
+	idToIndex := make(map[string]int)
+
+	for i, obj := range level.Objects {
+		id := obj.GetPropString("id", "")
+		if id == "" {
+			continue
+		}
+
+		if firstIndex, exists := idToIndex[id]; exists {
+			// Add error for the duplicate
+			result.Errors = append(result.Errors, ValidationError{
+				Type:        TypeError,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Duplicate ID '%s' (first used at object %d)", id, firstIndex),
+				Property:    "id",
+			})
+		} else {
+			idToIndex[id] = i
+		}
+	}
+}
+
+// runtimeTargets are the prefixes of target IDs registered with
+// RegisterRuntimeTargets.
+var runtimeTargets = []string{music.TargetPrefix}
+
+// RegisterRuntimeTargets makes validation accept switch targets whose IDs
+// start with prefix: targets the game creates while it runs instead of
+// reading them from the level, such as music.SignalTarget's
+// "music.boss_arena". Music signals are registered already; games adding
+// their own runtime targets call it before validating levels.
+func RegisterRuntimeTargets(prefix string) {
+	runtimeTargets = append(runtimeTargets, prefix)
+}
+
+// IsRuntimeTarget reports whether id is the ID of a target registered with
+// RegisterRuntimeTargets.
+func IsRuntimeTarget(id string) bool {
+	for _, prefix := range runtimeTargets {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSwitchReferences checks that switches reference valid doors.
+func validateSwitchReferences(level Level, result *ValidationResult) {
+	// Build a map of all door IDs (lights and platforms can be switch targets too)
+	doorIDs := make(map[string]int)
+	for i, obj := range level.Objects {
+		if obj.Type == world.ObjectTypeDoor || obj.Type == world.ObjectTypeLight || obj.Type == world.ObjectTypePlatform {
+			id := obj.GetPropString("id", "")
+			if id != "" {
+				doorIDs[id] = i
+			}
+		}
+	}
+
+	// Check each switch's door_id reference
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeSwitch {
+			continue
+		}
+
+		if mode := obj.GetPropString(world.PropSwitchMode, ""); mode != "" && entities.ParseSwitchMode(mode) != entities.SwitchMode(mode) {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Unknown switch mode '%s', using 'toggle' (want toggle, plate, or timed)", mode),
+				Property:    world.PropSwitchMode,
+			})
+		}
+
+		listed := make(map[string]bool)
+		for _, id := range world.ParseTargetList(obj.GetPropString(world.PropSwitchTargets, "")) {
+			listed[id] = true
+		}
+
+		targets := world.SwitchTargets(obj)
+		if len(targets) == 0 {
+			// Switch with no door_id - this could be a warning
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Switch has no door_id configured",
+				Property:    "door_id",
+			})
+			continue
+		}
+
+		for _, doorID := range targets {
+			if IsRuntimeTarget(doorID) {
+				continue
+			}
+
+			if _, exists := doorIDs[doorID]; !exists {
+				prop := "door_id"
+				if listed[doorID] {
+					prop = world.PropSwitchTargets
+				}
+				result.Errors = append(result.Errors, ValidationError{
+					Type:        TypeError,
+					ObjectIndex: i,
+					Message:     fmt.Sprintf("Switch references non-existent door '%s'", doorID),
+					Property:    prop,
+				})
+			}
+		}
+	}
+}
+
+// validateDoorSwitches checks for doors without any switch to control them.
+func validateDoorSwitches(level Level, result *ValidationResult) {
+	// Build a map of doors that are referenced by switches
+	referencedDoors := make(map[string]bool)
+
+	for _, obj := range level.Objects {
+		if obj.Type == world.ObjectTypeSwitch {
+			for _, doorID := range world.SwitchTargets(obj) {
+				referencedDoors[doorID] = true
+			}
+		}
+	}
+
+	// Check each door
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeDoor {
+			continue
+		}
+
+		if o := obj.GetPropString("obstruction", ""); o != "" && entities.ParseDoorObstruction(o) != entities.DoorObstruction(o) {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Unknown door obstruction '%s', using 'wait' (want block, wait, or push)", o),
+				Property:    "obstruction",
+			})
+		}
+		if dir := obj.GetPropString("openDirection", ""); dir != "" && entities.ParseDoorDirection(dir) != entities.DoorDirection(dir) {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Unknown door openDirection '%s', using 'up' (want up, down, left, or right)", dir),
+				Property:    "openDirection",
+			})
+		}
+
+		// Locked doors open with keys and don't need a switch
+		if obj.GetPropBool("locked", false) {
+			continue
+		}
+
+		id := obj.GetPropString("id", "")
+		if id == "" {
+			// Door with no ID - can't be referenced
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Door has no ID configured, cannot be controlled by switches",
+				Property:    "id",
+			})
+			continue
+		}
+
+		if !referencedDoors[id] {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Door '%s' has no switch to control it", id),
+				Property:    "id",
+			})
+		}
+	}
+}
+
+// validatePlatforms checks for platforms with no movement, and that the
+// paths platforms follow exist.
+func validatePlatforms(level Level, result *ValidationResult) {
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypePlatform {
+			continue
+		}
+
+		if id := obj.GetPropString(world.PropPlatformPath, ""); id != "" {
+			path := world.FindPath(level.Objects, id)
+			switch {
+			case path == nil:
+				result.Errors = append(result.Errors, ValidationError{
+					Type:        TypeError,
+					ObjectIndex: i,
+					Message:     fmt.Sprintf("Platform follows non-existent path '%s'", id),
+					Property:    world.PropPlatformPath,
+				})
+			case len(path.Points()) < 2:
+				result.Errors = append(result.Errors, ValidationError{
+					Type:        TypeError,
+					ObjectIndex: i,
+					Message:     fmt.Sprintf("Path '%s' isn't a polyline or polygon", id),
+					Property:    world.PropPlatformPath,
+				})
+			}
+			continue
+		}
+
+		// Get endX and endY relative to start position
+		endX := obj.GetPropFloat("endX", 0)
+		endY := obj.GetPropFloat("endY", 0)
+
+		// If both are 0, the platform won't move
+		if endX == 0 && endY == 0 {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Platform has endX=0 and endY=0, it won't move",
+				Property:    "endX",
+			})
+		}
+	}
+}
+
+// validateEases warns about doors and platforms with an unknown easing curve.
+func validateEases(level Level, result *ValidationResult) {
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeDoor && obj.Type != world.ObjectTypePlatform {
+			continue
+		}
+		name := obj.GetPropString(world.PropEase, "")
+		if _, ok := tween.ParseEase(name); !ok {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Unknown ease '%s', using 'linear' (want %s)", name, strings.Join(tween.EaseNames, ", ")),
+				Property:    world.PropEase,
+			})
+		}
+	}
+}
+
+// validateHazards checks hazard directions and the platforms hazards ride on.
+func validateHazards(level Level, result *ValidationResult) {
+	platforms := make(map[string]bool)
+	for _, obj := range level.Objects {
+		if obj.Type == world.ObjectTypePlatform {
+			platforms[obj.GetPropString("id", obj.Name)] = true
+		}
+	}
+
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeHazard {
+			continue
+		}
+
+		if d := obj.GetPropString("direction", ""); entities.ParseHazardDirection(d) != entities.HazardDirection(d) {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Unknown hazard direction '%s', hurting from all sides (want up, down, left, or right)", d),
+				Property:    "direction",
+			})
+		}
+
+		if id := obj.GetPropString("platform", ""); id != "" && !platforms[id] {
+			result.Errors = append(result.Errors, ValidationError{
+				Type:        TypeError,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Hazard rides on non-existent platform '%s'", id),
+				Property:    "platform",
+			})
+		}
+	}
+}
+
+// validateGoalRequirements checks that goal completion requirements can be met.
+func validateGoalRequirements(level Level, result *ValidationResult) {
+	checkpoints := len(world.FilterObjectsByType(level.Objects, world.ObjectTypeCheckpoint))
+	collectibles := len(world.FilterObjectsByType(level.Objects, world.ObjectTypeCollectible))
+
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeGoal {
+			continue
+		}
+		req := world.NewGoalRequirements(obj)
+
+		if req.Collectibles > collectibles {
+			result.Errors = append(result.Errors, ValidationError{
+				Type:        TypeError,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Goal requires %d collectibles but the level has %d", req.Collectibles, collectibles),
+				Property:    world.PropRequiredCollectibles,
+			})
+		}
+
+		if req.AllCheckpoints && checkpoints == 0 {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Goal requires all checkpoints but the level has none",
+				Property:    world.PropRequireCheckpoints,
+			})
+		}
+	}
+}
+
+// validateExits checks that exits lead to a spawn that exists: in this
+// level, or in the level file they name when the level's path is known.
+func validateExits(level Level, result *ValidationResult) {
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeExit {
+			continue
+		}
+		target, spawn := world.ExitTarget(obj)
+
+		objects := level.Objects
+		if target != "" {
+			if level.Path == "" {
+				continue
+			}
+			data, err := storage.ReadFile(world.ResolveLevelPath(level.Path, target))
+			if err == nil {
+				objects, err = world.ParseObjects(data)
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, ValidationError{
+					Type:        TypeError,
+					ObjectIndex: i,
+					Message:     fmt.Sprintf("Exit leads to unreadable level '%s': %v", target, err),
+					Property:    world.PropExitLevel,
+				})
+				continue
+			}
+		}
+
+		if _, _, found := world.FindSpawnByID(objects, spawn); !found {
+			where := "this level"
+			if target != "" {
+				where = fmt.Sprintf("'%s'", target)
+			}
+			msg := fmt.Sprintf("Exit leads to spawn '%s', which %s doesn't have", spawn, where)
+			if spawn == "" {
+				msg = fmt.Sprintf("Exit leads to %s, which has no spawn point", where)
+			}
+			result.Errors = append(result.Errors, ValidationError{
+				Type:        TypeError,
+				ObjectIndex: i,
+				Message:     msg,
+				Property:    world.PropExitSpawn,
+			})
+		}
+	}
+}
+
+// validateSoundEmitters warns about emitters without a clip, which stay
+// silent, and unknown falloffs.
+func validateSoundEmitters(level Level, result *ValidationResult) {
+	for i, obj := range level.Objects {
+		if obj.Type != world.ObjectTypeSound {
+			continue
+		}
+
+		if obj.GetPropString(world.PropSoundClip, "") == "" {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     "Sound emitter has no clip and will be silent",
+				Property:    world.PropSoundClip,
+			})
+		}
+
+		if f := obj.GetPropString(world.PropSoundFalloff, ""); f != "" && sound.ParseFalloff(f) != sound.Falloff(f) {
+			result.Warnings = append(result.Warnings, ValidationError{
+				Type:        TypeWarning,
+				ObjectIndex: i,
+				Message:     fmt.Sprintf("Unknown sound falloff '%s', using 'linear' (want linear, quadratic, or none)", f),
+				Property:    world.PropSoundFalloff,
+			})
+		}
+	}
+}
+
+// validateBudget warns when the level exceeds its complexity budget.
+func validateBudget(level Level, result *ValidationResult) {
+	budget, usage := Budget(level)
+	for _, issue := range budget.Check(usage) {
+		result.Warnings = append(result.Warnings, ValidationError{
+			Type:        TypeWarning,
+			ObjectIndex: -1,
+			Message:     fmt.Sprintf("Level budget: %s", issue),
+			Property:    issue.Property,
+		})
+	}
+}
+
+// Budget returns the level's complexity budget and current usage.
+func Budget(level Level) (world.LevelBudget, world.LevelUsage) {
+	var props map[string]any
+	if level.Map != nil {
+		props = level.Map.Properties()
+	}
+	return world.NewLevelBudget(props), world.CountUsage(level.Objects, level.RuleCount)
+}
+
+// RuleCount returns the number of rules in the rules file next to a level,
+// or 0 if the level has none.
+func RuleCount(levelPath string) int {
+	if levelPath == "" {
+		return 0
+	}
+	data, err := storage.ReadFile(rules.PathForLevel(levelPath))
+	if err != nil {
+		return 0
+	}
+	set, err := rules.ParseYAML(data)
+	if err != nil {
+		return 0
+	}
+	return len(set.Rules)
+}
+
+// Err returns an error listing the critical errors, or nil if there are none.
+// Warnings don't make a level unplayable and are left out.
+func (r *ValidationResult) Err() error {
+	if !r.HasErrors() {
+		return nil
+	}
+	msgs := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		msgs[i] = Format(e)
+	}
+	return fmt.Errorf("level has %d error(s): %s", len(r.Errors), strings.Join(msgs, "; "))
+}
+
+// Report converts the result to a validation report entry for the level at path.
+func (r *ValidationResult) Report(path string) schema.LevelReport {
+	return schema.LevelReport{
+		Path:     path,
+		Errors:   reportIssues(r.Errors),
+		Warnings: reportIssues(r.Warnings),
+	}
+}
+
+// reportIssues converts validation issues to report issues.
+func reportIssues(errs []ValidationError) []schema.Issue {
+	issues := make([]schema.Issue, len(errs))
+	for i, e := range errs {
+		issues[i] = schema.Issue{Message: e.Message, Object: e.ObjectIndex, Property: e.Property}
+	}
+	return issues
+}
+
+// Check parses and validates Tiled JSON level data, returning an error if the
+// level can't be played. levelPath locates the level's rule file and may be "".
+func Check(data []byte, levelPath string) error {
+	level, err := ParseLevel(data, levelPath)
+	if err != nil {
+		return err
+	}
+	return Validate(level).Err()
+}
+
+// Format formats a validation issue for display.
+func Format(err ValidationError) string {
+	if err.Property != "" {
+		return fmt.Sprintf("%s (property: %s)", err.Message, err.Property)
+	}
+	return err.Message
+}
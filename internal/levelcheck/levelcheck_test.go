@@ -0,0 +1,314 @@
+//go:build display
+
+package levelcheck
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// levelJSON builds a one-tile level with the given object layer entries.
+func levelJSON(objects string) []byte {
+	return []byte(`{"width":1,"height":1,"tilewidth":16,"tileheight":16,"layers":[
+		{"name":"Tiles","type":"tilelayer","width":1,"height":1,"data":[0]},
+		{"name":"Objects","type":"objectgroup","objects":[` + objects + `]}]}`)
+}
+
+// largeLevelJSON builds an empty 5x5 tile level with the given object layer
+// entries, for objects that don't fit on levelJSON's one tile.
+func largeLevelJSON(objects string) []byte {
+	return []byte(`{"width":5,"height":5,"tilewidth":16,"tileheight":16,"layers":[
+		{"name":"Tiles","type":"tilelayer","width":5,"height":5,"data":[` + strings.Repeat("0,", 24) + `0]},
+		{"name":"Objects","type":"objectgroup","objects":[` + objects + `]}]}`)
+}
+
+func TestCheckAcceptsBuiltinLevel(t *testing.T) {
+	data, err := os.ReadFile("../../assets/levels/level_01.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(data, ""); err != nil {
+		t.Errorf("Check(level_01) = %v, want nil", err)
+	}
+}
+
+func TestCheckRejectsMissingSpawn(t *testing.T) {
+	err := Check(levelJSON(""), "")
+	if err == nil || !strings.Contains(err.Error(), "No player spawn point") {
+		t.Errorf("Check = %v, want missing spawn error", err)
+	}
+}
+
+func TestCheckIgnoresWarnings(t *testing.T) {
+	// Two spawns is only a warning
+	spawns := `{"type":"spawn","x":0,"y":0},{"type":"spawn","x":8,"y":0}`
+	if err := Check(levelJSON(spawns), ""); err != nil {
+		t.Errorf("Check = %v, want nil for warnings only", err)
+	}
+}
+
+func TestCheckExitSpawns(t *testing.T) {
+	spawns := `{"type":"spawn","x":0,"y":0,"properties":[{"name":"id","type":"string","value":"hall"}]},` +
+		`{"type":"spawn","x":8,"y":0,"properties":[{"name":"id","type":"string","value":"balcony"}]}`
+	exit := func(spawn string) string {
+		return `,{"type":"exit","x":0,"y":0,"width":16,"height":16,"properties":[{"name":"spawn","type":"string","value":"` + spawn + `"}]}`
+	}
+
+	// Named spawns aren't ambiguous, so they don't warn
+	level, err := ParseLevel(levelJSON(spawns+exit("balcony")), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Validate(level)
+	if len(result.Errors) != 0 || len(result.Warnings) != 0 {
+		t.Errorf("exit to an existing spawn: errors %v, warnings %v, want none", result.Errors, result.Warnings)
+	}
+
+	err = Check(levelJSON(spawns+exit("cellar")), "")
+	if err == nil || !strings.Contains(err.Error(), "spawn 'cellar'") {
+		t.Errorf("Check = %v, want missing exit spawn error", err)
+	}
+}
+
+func TestValidateSoundEmitters(t *testing.T) {
+	emitter := `,{"type":"sound_emitter","x":0,"y":0,"width":16,"height":16,"properties":[` +
+		`{"name":"falloff","type":"string","value":"cubic"}]}`
+	level, err := ParseLevel(levelJSON(`{"type":"spawn","x":0,"y":0}`+emitter), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := Validate(level)
+	if len(result.Errors) != 0 || len(result.Warnings) != 2 {
+		t.Errorf("emitter without clip and with unknown falloff: errors %v, warnings %v, want two warnings", result.Errors, result.Warnings)
+	}
+}
+
+func TestValidatePlatformPaths(t *testing.T) {
+	platform := func(path string) string {
+		return `,{"type":"platform","x":0,"y":0,"width":32,"height":8,"properties":[` +
+			`{"name":"path","type":"string","value":"` + path + `"}]}`
+	}
+	paths := `,{"type":"path","name":"loop","x":0,"y":0,"polygon":[{"x":0,"y":0},{"x":64,"y":0},{"x":64,"y":64}]}` +
+		`,{"type":"path","name":"box","x":0,"y":0,"width":16,"height":16}`
+	spawn := `{"type":"spawn","x":0,"y":0}`
+
+	tests := []struct {
+		path   string
+		errors int
+	}{
+		{"loop", 0}, // No endX/endY warning either
+		{"box", 1},
+		{"nowhere", 1},
+	}
+	for _, tt := range tests {
+		level, err := ParseLevel(largeLevelJSON(spawn+paths+platform(tt.path)), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := Validate(level)
+		if len(result.Errors) != tt.errors || len(result.Warnings) != 0 {
+			t.Errorf("platform on path %q: errors %v, warnings %v, want %d errors", tt.path, result.Errors, result.Warnings, tt.errors)
+		}
+	}
+}
+
+func TestValidateBudget(t *testing.T) {
+	budgetLevel := func(props string) []byte {
+		return []byte(`{"width":1,"height":1,"tilewidth":16,"tileheight":16,"properties":[` + props + `],"layers":[
+			{"name":"Tiles","type":"tilelayer","width":1,"height":1,"data":[0]},
+			{"name":"Objects","type":"objectgroup","objects":[
+				{"type":"spawn","x":0,"y":0},
+				{"type":"collectible","x":0,"y":0,"width":8,"height":8},
+				{"type":"collectible","x":8,"y":0,"width":8,"height":8},
+				{"type":"collectible","x":0,"y":8,"width":8,"height":8}]}]}`)
+	}
+	prop := func(name string, value int) string {
+		return fmt.Sprintf(`{"name":%q,"type":"int","value":%d}`, name, value)
+	}
+
+	tests := []struct {
+		name  string
+		props string
+		want  []string // Warning messages
+	}{
+		{"default budget", "", nil},
+		{"over two limits", prop("maxObjects", 3) + "," + prop("maxTriggers", 2), []string{
+			"Level budget: 4 objects exceed the budget of 3",
+			"Level budget: 3 triggers exceed the budget of 2",
+		}},
+		{"at the limit", prop("maxTriggers", 3), nil},
+		{"0 disables", prop("maxObjects", 0), nil},
+		{"negative disables", prop("maxTriggers", -1), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(budgetLevel(tt.props), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			level.RuleCount = 2
+			var result ValidationResult
+			validateBudget(level, &result)
+			var got []string
+			for _, w := range result.Warnings {
+				got = append(got, w.Message)
+				if w.Type != TypeWarning || w.ObjectIndex != -1 || w.Property == "" {
+					t.Errorf("warning %+v, want a level-wide warning about a property", w)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("warnings %q, want %q", got, tt.want)
+			}
+			if len(result.Errors) != 0 {
+				t.Errorf("budget gave errors %v", result.Errors)
+			}
+		})
+	}
+
+	// Rules count against maxRules
+	level, err := ParseLevel(budgetLevel(prop("maxRules", 1)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	level.RuleCount = 2
+	if result := Validate(level); !hasIssue(result.Warnings, "2 rules exceed the budget of 1") {
+		t.Errorf("Validate warnings %v, want the rules budget", result.Warnings)
+	}
+}
+
+func TestCheckRejectsUnparsableData(t *testing.T) {
+	if err := Check([]byte("{"), ""); err == nil {
+		t.Error("Check accepted invalid JSON")
+	}
+}
+
+// keyLevelJSON builds a 6x3 level split by a wall at x=3 with a locked
+// door in the wall's middle row; objects go on the object layer.
+func keyLevelJSON(objects string) []byte {
+	return []byte(`{"width":6,"height":3,"tilewidth":16,"tileheight":16,"layers":[
+		{"name":"Collision","type":"tilelayer","width":6,"height":3,"data":[0,0,0,1,0,0, 0,0,0,0,0,0, 0,0,0,1,0,0]},
+		{"name":"Objects","type":"objectgroup","objects":[
+			{"type":"spawn","x":0,"y":16,"width":12,"height":12},` + objects + `]}]}`)
+}
+
+// lockedDoorJSON is a locked door in the wall gap at tile (3, 1).
+const lockedDoorJSON = `{"type":"door","x":48,"y":16,"width":16,"height":16,
+	"properties":[{"name":"locked","type":"bool","value":true}]}`
+
+// checkLevel parses and validates test level data.
+func checkLevel(t *testing.T, data []byte) *ValidationResult {
+	t.Helper()
+	level, err := ParseLevel(data, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Validate(level)
+}
+
+// hasIssue returns whether any issue message contains substr.
+func hasIssue(issues []ValidationError, substr string) bool {
+	for _, issue := range issues {
+		if strings.Contains(issue.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLockedDoorWithReachableKey(t *testing.T) {
+	result := checkLevel(t, keyLevelJSON(lockedDoorJSON+`,{"type":"key","x":16,"y":16,"width":16,"height":12}`))
+	if result.HasIssues() {
+		t.Errorf("unexpected issues: %v", result.AllIssues())
+	}
+}
+
+func TestLockedDoorWithoutKeys(t *testing.T) {
+	result := checkLevel(t, keyLevelJSON(lockedDoorJSON))
+	if !hasIssue(result.Errors, "need 1 keys in total, but the level has 0") {
+		t.Errorf("errors = %v, want missing key error", result.Errors)
+	}
+}
+
+func TestLockedDoorNeedsNamedKey(t *testing.T) {
+	door := `{"type":"door","x":48,"y":16,"width":16,"height":16,"properties":[
+		{"name":"locked","type":"bool","value":true},{"name":"key_id","type":"string","value":"red"}]}`
+	key := `{"type":"key","x":16,"y":16,"width":16,"height":12,"properties":[{"name":"id","type":"string","value":"blue"}]}`
+	result := checkLevel(t, keyLevelJSON(door+","+key))
+	if !hasIssue(result.Errors, "needs key 'red'") {
+		t.Errorf("errors = %v, want missing red key error", result.Errors)
+	}
+}
+
+func TestKeyBehindItsOwnDoor(t *testing.T) {
+	result := checkLevel(t, keyLevelJSON(lockedDoorJSON+`,{"type":"key","x":80,"y":16,"width":16,"height":12}`))
+	if result.HasErrors() {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+	if !hasIssue(result.Warnings, "Key can't be reached") || !hasIssue(result.Warnings, "Locked door can't be opened") {
+		t.Errorf("warnings = %v, want unreachable key and door", result.Warnings)
+	}
+}
+
+func TestValidateGeometry(t *testing.T) {
+	// A 4x2 level with solid tiles in the right half
+	data := []byte(`{"width":4,"height":2,"tilewidth":16,"tileheight":16,"layers":[
+		{"name":"Collision","type":"tilelayer","width":4,"height":2,"data":[0,0,1,1, 0,0,1,1]},
+		{"name":"Objects","type":"objectgroup","objects":[
+			{"type":"spawn","x":0,"y":0,"width":12,"height":12},
+			{"type":"goal","x":32,"y":0,"width":16,"height":16},
+			{"type":"hazard","x":32,"y":16,"width":32,"height":16},
+			{"type":"collectible","x":100,"y":0,"width":16,"height":16},
+			{"type":"collectible","x":56,"y":0,"width":16,"height":16},
+			{"type":"door","x":0,"y":16,"width":16,"height":16},
+			{"type":"door","x":4,"y":16,"width":16,"height":16}]}]}`)
+	result := checkLevel(t, data)
+
+	for _, want := range []string{
+		"Goal is inside solid collision tiles",
+		"Hazard is completely covered",
+		"Object is outside the map",
+		"Object extends past the edge of the map",
+		"Overlaps door by 75%",
+	} {
+		if !hasIssue(result.Warnings, want) {
+			t.Errorf("warnings = %v, want %q", result.Warnings, want)
+		}
+	}
+	if hasIssue(result.Warnings, "Spawn point is inside") {
+		t.Errorf("warnings = %v, spawn on open tiles reported as embedded", result.Warnings)
+	}
+}
+
+func TestSwitchToRuntimeTargets(t *testing.T) {
+	spawn := `{"type":"spawn","x":0,"y":0}`
+	switchTo := func(target string) string {
+		return `,{"type":"switch","x":0,"y":0,"width":16,"height":16,"properties":[` +
+			`{"name":"door_id","type":"string","value":"` + target + `"}]}`
+	}
+
+	for _, tt := range []struct {
+		target string
+		errors int
+	}{
+		{"music.boss_arena", 0},
+		{"fx.shake", 1},
+		{"door_1", 1},
+	} {
+		result := checkLevel(t, levelJSON(spawn+switchTo(tt.target)))
+		if len(result.Errors) != tt.errors {
+			t.Errorf("switch to %q before registering: errors %v, want %d", tt.target, result.Errors, tt.errors)
+		}
+	}
+
+	defer func(saved []string) { runtimeTargets = saved }(runtimeTargets)
+	RegisterRuntimeTargets("fx.")
+	if !IsRuntimeTarget("fx.shake") || IsRuntimeTarget("fxshake") {
+		t.Error("IsRuntimeTarget doesn't match the registered prefix")
+	}
+	if result := checkLevel(t, levelJSON(spawn+switchTo("fx.shake"))); len(result.Errors) != 0 {
+		t.Errorf("switch to a registered runtime target: errors %v, want none", result.Errors)
+	}
+}
@@ -0,0 +1,253 @@
+// Package logging provides leveled, module-tagged logging for the game and
+// editor.
+//
+// Every entry is kept in an in-memory ring buffer (read by the debug
+// console), and entries at or above the sink's level are also written to
+// stderr and, optionally, a log file.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+const (
+	// LevelDebug is for chatty details, e.g. every tool switch.
+	LevelDebug Level = iota
+	// LevelInfo is for normal events, e.g. a level being saved.
+	LevelInfo
+	// LevelWarn is for problems the program works around.
+	LevelWarn
+	// LevelError is for failed operations.
+	LevelError
+)
+
+var levelNames = [...]string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+// String returns the level's name, e.g. "WARN".
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name, ignoring case. "warning" is accepted for
+// LevelWarn.
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if name == "WARNING" {
+		return LevelWarn, nil
+	}
+	for i, n := range levelNames {
+		if n == name {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+}
+
+// timeFormat matches the standard library log package.
+const timeFormat = "2006/01/02 15:04:05"
+
+// Entry is one logged message.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Module  string
+	Message string
+}
+
+// String formats the entry as a log line, without a trailing newline.
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %-5s [%s] %s", e.Time.Format(timeFormat), e.Level, e.Module, e.Message)
+}
+
+// DefaultCapacity is the number of entries the default sink keeps.
+const DefaultCapacity = 500
+
+// Sink receives the entries of its loggers.
+type Sink struct {
+	mu    sync.Mutex
+	level Level
+	out   io.Writer
+	file  *os.File
+
+	ring []Entry // Ring buffer of the most recent entries
+	next int     // Index the next entry goes to
+	full bool    // Whether the ring has wrapped
+	now  func() time.Time
+}
+
+// NewSink creates a sink writing entries at LevelInfo and above to out and
+// keeping the last capacity entries.
+func NewSink(out io.Writer, capacity int) *Sink {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Sink{
+		level: LevelInfo,
+		out:   out,
+		ring:  make([]Entry, capacity),
+		now:   time.Now,
+	}
+}
+
+// SetLevel sets the lowest level written to the outputs. The ring buffer
+// keeps entries of every level.
+func (s *Sink) SetLevel(l Level) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.level = l
+}
+
+// Level returns the lowest level written to the outputs.
+func (s *Sink) Level() Level {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.level
+}
+
+// SetOutput replaces the main output. Nil discards output.
+func (s *Sink) SetOutput(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out = w
+}
+
+// SetFile also writes entries to the file at path, appending to it. An
+// empty path closes the current file.
+func (s *Sink) SetFile(path string) error {
+	var f *os.File
+	if path != "" {
+		var err error
+		if f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = f
+	return nil
+}
+
+// Recent returns up to n of the most recent entries at level min or above,
+// oldest first. n <= 0 returns all of them.
+func (s *Sink) Recent(n int, min Level) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := s.next
+	start := 0
+	if s.full {
+		count = len(s.ring)
+		start = s.next
+	}
+
+	var entries []Entry
+	for i := 0; i < count; i++ {
+		if e := s.ring[(start+i)%len(s.ring)]; e.Level >= min {
+			entries = append(entries, e)
+		}
+	}
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries
+}
+
+// Logger returns a logger tagging its entries with module.
+func (s *Sink) Logger(module string) *Logger {
+	return &Logger{sink: s, module: module}
+}
+
+// write records an entry and writes it to the outputs if its level is high
+// enough.
+func (s *Sink) write(level Level, module, msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := Entry{Time: s.now(), Level: level, Module: module, Message: msg}
+	s.ring[s.next] = e
+	s.next = (s.next + 1) % len(s.ring)
+	if s.next == 0 {
+		s.full = true
+	}
+
+	if level < s.level {
+		return
+	}
+	line := e.String() + "\n"
+	if s.out != nil {
+		io.WriteString(s.out, line)
+	}
+	if s.file != nil {
+		s.file.WriteString(line)
+	}
+}
+
+// Logger writes entries for one module.
+type Logger struct {
+	sink   *Sink
+	module string
+}
+
+// Debugf logs a LevelDebug entry.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.sink.write(LevelDebug, l.module, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a LevelInfo entry.
+func (l *Logger) Infof(format string, args ...any) {
+	l.sink.write(LevelInfo, l.module, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a LevelWarn entry.
+func (l *Logger) Warnf(format string, args ...any) {
+	l.sink.write(LevelWarn, l.module, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a LevelError entry.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.sink.write(LevelError, l.module, fmt.Sprintf(format, args...))
+}
+
+// std is the sink of the package-level functions, writing to stderr.
+var std = NewSink(os.Stderr, DefaultCapacity)
+
+// New returns a logger for module on the default sink.
+func New(module string) *Logger {
+	return std.Logger(module)
+}
+
+// SetLevel sets the lowest level the default sink writes out.
+func SetLevel(l Level) {
+	std.SetLevel(l)
+}
+
+// CurrentLevel returns the lowest level the default sink writes out.
+func CurrentLevel() Level {
+	return std.Level()
+}
+
+// SetFile makes the default sink also write to the file at path.
+func SetFile(path string) error {
+	return std.SetFile(path)
+}
+
+// Recent returns up to n recent entries of the default sink at level min or
+// above, oldest first.
+func Recent(n int, min Level) []Entry {
+	return std.Recent(n, min)
+}
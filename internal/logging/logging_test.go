@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSink(capacity int) (*Sink, *bytes.Buffer) {
+	var buf bytes.Buffer
+	s := NewSink(&buf, capacity)
+	s.now = func() time.Time { return time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC) }
+	return s, &buf
+}
+
+func TestSinkLevel(t *testing.T) {
+	s, buf := newTestSink(10)
+	log := s.Logger("editor")
+
+	log.Debugf("hidden %d", 1)
+	log.Infof("saved %s", "a.json")
+	log.Warnf("odd")
+	log.Errorf("failed: %v", "boom")
+
+	want := "2024/05/01 12:30:00 INFO  [editor] saved a.json\n" +
+		"2024/05/01 12:30:00 WARN  [editor] odd\n" +
+		"2024/05/01 12:30:00 ERROR [editor] failed: boom\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	// Debug entries are kept even though they're not written
+	if got := len(s.Recent(0, LevelDebug)); got != 4 {
+		t.Errorf("Recent kept %d entries, want 4", got)
+	}
+
+	buf.Reset()
+	s.SetLevel(LevelError)
+	log.Warnf("quiet")
+	if buf.Len() != 0 {
+		t.Errorf("warning written at LevelError: %q", buf.String())
+	}
+}
+
+func TestSinkRecent(t *testing.T) {
+	s, _ := newTestSink(3)
+	log := s.Logger("game")
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		log.Infof("%s", msg)
+	}
+	log.Errorf("e")
+
+	messages := func(entries []Entry) string {
+		var m []string
+		for _, e := range entries {
+			m = append(m, e.Message)
+		}
+		return strings.Join(m, ",")
+	}
+
+	tests := []struct {
+		n    int
+		min  Level
+		want string
+	}{
+		{0, LevelDebug, "c,d,e"}, // Oldest entries dropped
+		{2, LevelDebug, "d,e"},
+		{0, LevelError, "e"},
+	}
+	for _, tt := range tests {
+		if got := messages(s.Recent(tt.n, tt.min)); got != tt.want {
+			t.Errorf("Recent(%d, %v) = %s, want %s", tt.n, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestSinkFile(t *testing.T) {
+	s, _ := newTestSink(10)
+	path := filepath.Join(t.TempDir(), "gop.log")
+	if err := s.SetFile(path); err != nil {
+		t.Fatalf("SetFile failed: %v", err)
+	}
+	s.Logger("rules").Infof("fired")
+	if err := s.SetFile(""); err != nil {
+		t.Fatalf("SetFile(\"\") failed: %v", err)
+	}
+	s.Logger("rules").Infof("after close")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024/05/01 12:30:00 INFO  [rules] fired\n"; string(data) != want {
+		t.Errorf("log file = %q, want %q", data, want)
+	}
+
+	if err := s.SetFile(filepath.Join(t.TempDir(), "missing", "gop.log")); err == nil {
+		t.Error("SetFile in a missing directory succeeded, want error")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "Warn": LevelWarn, "warning": LevelWarn, " error ": LevelError} {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel(\"loud\") succeeded, want error")
+	}
+}
@@ -0,0 +1,38 @@
+package mathx
+
+// Easing functions map t in [0, 1] to an eased fraction in [0, 1], for use
+// with Lerp. Inputs outside [0, 1] are clamped.
+
+// EaseInQuad starts slow and accelerates.
+func EaseInQuad(t float64) float64 {
+	t = Clamp01(t)
+	return t * t
+}
+
+// EaseOutQuad starts fast and decelerates.
+func EaseOutQuad(t float64) float64 {
+	t = Clamp01(t)
+	return t * (2 - t)
+}
+
+// EaseInOutQuad accelerates through the first half and decelerates through
+// the second.
+func EaseInOutQuad(t float64) float64 {
+	t = Clamp01(t)
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseOutCubic decelerates more sharply than EaseOutQuad.
+func EaseOutCubic(t float64) float64 {
+	t = Clamp01(t) - 1
+	return t*t*t + 1
+}
+
+// SmoothStep is Hermite interpolation: zero slope at both ends.
+func SmoothStep(t float64) float64 {
+	t = Clamp01(t)
+	return t * t * (3 - 2*t)
+}
@@ -0,0 +1,26 @@
+package mathx
+
+// EllipseContains reports whether p is inside the ellipse filling the
+// rectangle x, y, w, h.
+func EllipseContains(x, y, w, h float64, p Vec2) bool {
+	if w <= 0 || h <= 0 {
+		return false
+	}
+	dx := (p.X - x - w/2) / (w / 2)
+	dy := (p.Y - y - h/2) / (h / 2)
+	return dx*dx+dy*dy < 1
+}
+
+// EllipseIntersectsRect reports whether the ellipse filling the rectangle
+// ex, ey, ew, eh overlaps the rectangle x, y, w, h. Touching edges don't
+// count, as with AABBs.
+func EllipseIntersectsRect(ex, ey, ew, eh, x, y, w, h float64) bool {
+	if w <= 0 || h <= 0 {
+		return false
+	}
+	// Scaling the ellipse to a circle keeps the rectangle a rectangle, so
+	// the point of it nearest the center decides
+	center := V(ex+ew/2, ey+eh/2)
+	nearest := V(Clamp(center.X, x, x+w), Clamp(center.Y, y, y+h))
+	return EllipseContains(ex, ey, ew, eh, nearest)
+}
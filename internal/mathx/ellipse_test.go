@@ -0,0 +1,46 @@
+package mathx
+
+import "testing"
+
+func TestEllipseContains(t *testing.T) {
+	// A 40x20 ellipse at 0, 0: center 20, 10, radii 20 and 10
+	tests := []struct {
+		p    Vec2
+		want bool
+	}{
+		{V(20, 10), true},
+		{V(39, 10), true},
+		{V(20, 1), true},
+		{V(1, 1), false}, // In the bounds' corner
+		{V(40, 10), false},
+	}
+	for _, tt := range tests {
+		if got := EllipseContains(0, 0, 40, 20, tt.p); got != tt.want {
+			t.Errorf("EllipseContains(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if EllipseContains(0, 0, 0, 20, V(0, 10)) {
+		t.Error("an ellipse without width contains a point")
+	}
+}
+
+func TestEllipseIntersectsRect(t *testing.T) {
+	// A circle of radius 16 around 16, 16
+	tests := []struct {
+		name       string
+		x, y, w, h float64
+		want       bool
+	}{
+		{"covering it", -4, -4, 40, 40, true},
+		{"inside it", 12, 12, 4, 4, true},
+		{"across its edge", 28, 12, 10, 8, true},
+		{"in the bounds' corner", 0, 0, 4, 4, false},
+		{"touching its side", 32, 8, 8, 16, false},
+		{"beside it", 40, 0, 8, 32, false},
+	}
+	for _, tt := range tests {
+		if got := EllipseIntersectsRect(0, 0, 32, 32, tt.x, tt.y, tt.w, tt.h); got != tt.want {
+			t.Errorf("%s: EllipseIntersectsRect = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
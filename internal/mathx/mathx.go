@@ -0,0 +1,114 @@
+// Package mathx provides the small vector, interpolation and easing helpers
+// shared by the camera, physics, entities and editor.
+package mathx
+
+import (
+	"cmp"
+	"math"
+)
+
+// Vec2 is a 2D vector in world or screen units.
+type Vec2 struct {
+	X, Y float64
+}
+
+// V returns the vector x, y.
+func V(x, y float64) Vec2 {
+	return Vec2{x, y}
+}
+
+// Add returns v + o.
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns v - o.
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{v.X - o.X, v.Y - o.Y}
+}
+
+// Scale returns v multiplied by s.
+func (v Vec2) Scale(s float64) Vec2 {
+	return Vec2{v.X * s, v.Y * s}
+}
+
+// Len returns the length of v.
+func (v Vec2) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Normalize returns v scaled to length 1, or the zero vector if v is zero.
+func (v Vec2) Normalize() Vec2 {
+	l := v.Len()
+	if l == 0 {
+		return Vec2{}
+	}
+	return Vec2{v.X / l, v.Y / l}
+}
+
+// Dist returns the distance between v and o.
+func (v Vec2) Dist(o Vec2) float64 {
+	return Distance(v.X, v.Y, o.X, o.Y)
+}
+
+// Lerp returns the point t of the way from v to o.
+func (v Vec2) Lerp(o Vec2, t float64) Vec2 {
+	return Vec2{Lerp(v.X, o.X, t), Lerp(v.Y, o.Y, t)}
+}
+
+// Distance returns the distance between x1, y1 and x2, y2.
+func Distance(x1, y1, x2, y2 float64) float64 {
+	return math.Hypot(x2-x1, y2-y1)
+}
+
+// Lerp returns the value t of the way from a to b. t isn't clamped.
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// InverseLerp returns how far v is from a to b, so that
+// Lerp(a, b, InverseLerp(a, b, v)) == v. It returns 0 if a == b.
+func InverseLerp(a, b, v float64) float64 {
+	if a == b {
+		return 0
+	}
+	return (v - a) / (b - a)
+}
+
+// Clamp limits v to [lo, hi], preferring lo if the range is empty.
+func Clamp[T cmp.Ordered](v, lo, hi T) T {
+	return max(min(v, hi), lo)
+}
+
+// Clamp01 limits v to [0, 1].
+func Clamp01(v float64) float64 {
+	return Clamp(v, 0, 1)
+}
+
+// Approach moves current toward target by at most maxDelta without
+// overshooting.
+func Approach(current, target, maxDelta float64) float64 {
+	if current < target {
+		return math.Min(current+maxDelta, target)
+	}
+	return math.Max(current-maxDelta, target)
+}
+
+// Abs returns the absolute value of v.
+func Abs[T int | float64](v T) T {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of v.
+func Sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,122 @@
+package mathx
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestVec2(t *testing.T) {
+	a, b := V(3, 4), V(1, 1)
+	if got := a.Add(b); got != V(4, 5) {
+		t.Errorf("Add = %v, want {4 5}", got)
+	}
+	if got := a.Sub(b); got != V(2, 3) {
+		t.Errorf("Sub = %v, want {2 3}", got)
+	}
+	if got := a.Scale(2); got != V(6, 8) {
+		t.Errorf("Scale = %v, want {6 8}", got)
+	}
+	if got := a.Len(); got != 5 {
+		t.Errorf("Len = %v, want 5", got)
+	}
+	if got := a.Normalize(); !near(got.X, 0.6) || !near(got.Y, 0.8) {
+		t.Errorf("Normalize = %v, want {0.6 0.8}", got)
+	}
+	if got := (Vec2{}).Normalize(); got != (Vec2{}) {
+		t.Errorf("zero Normalize = %v, want zero", got)
+	}
+	if got := V(0, 0).Dist(a); got != 5 {
+		t.Errorf("Dist = %v, want 5", got)
+	}
+	if got := V(0, 0).Lerp(a, 0.5); got != V(1.5, 2) {
+		t.Errorf("Lerp = %v, want {1.5 2}", got)
+	}
+}
+
+func TestLerp(t *testing.T) {
+	if got := Lerp(10, 20, 0.25); got != 12.5 {
+		t.Errorf("Lerp(10, 20, 0.25) = %v, want 12.5", got)
+	}
+	if got := InverseLerp(10, 20, 12.5); got != 0.25 {
+		t.Errorf("InverseLerp(10, 20, 12.5) = %v, want 0.25", got)
+	}
+	if got := InverseLerp(5, 5, 7); got != 0 {
+		t.Errorf("InverseLerp on an empty range = %v, want 0", got)
+	}
+}
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		v, lo, hi, want int
+	}{
+		{5, 0, 10, 5},
+		{-1, 0, 10, 0},
+		{11, 0, 10, 10},
+		{5, 3, 1, 3}, // Empty range prefers lo
+	}
+	for _, tt := range tests {
+		if got := Clamp(tt.v, tt.lo, tt.hi); got != tt.want {
+			t.Errorf("Clamp(%d, %d, %d) = %d, want %d", tt.v, tt.lo, tt.hi, got, tt.want)
+		}
+	}
+	if got := Clamp01(1.5); got != 1 {
+		t.Errorf("Clamp01(1.5) = %v, want 1", got)
+	}
+}
+
+func TestApproach(t *testing.T) {
+	tests := []struct {
+		current, target, delta, want float64
+	}{
+		{0, 10, 3, 3},
+		{9, 10, 3, 10},
+		{0, -10, 3, -3},
+		{-9, -10, 3, -10},
+		{5, 5, 3, 5},
+	}
+	for _, tt := range tests {
+		if got := Approach(tt.current, tt.target, tt.delta); got != tt.want {
+			t.Errorf("Approach(%v, %v, %v) = %v, want %v", tt.current, tt.target, tt.delta, got, tt.want)
+		}
+	}
+}
+
+func TestSignAbs(t *testing.T) {
+	if Sign(-2) != -1 || Sign(0) != 0 || Sign(3) != 1 {
+		t.Errorf("Sign = %v %v %v, want -1 0 1", Sign(-2), Sign(0), Sign(3))
+	}
+	if Abs(-3) != 3 || Abs(2.5) != 2.5 {
+		t.Errorf("Abs = %v %v, want 3 2.5", Abs(-3), Abs(2.5))
+	}
+}
+
+func TestEasing(t *testing.T) {
+	funcs := map[string]func(float64) float64{
+		"EaseInQuad":    EaseInQuad,
+		"EaseOutQuad":   EaseOutQuad,
+		"EaseInOutQuad": EaseInOutQuad,
+		"EaseOutCubic":  EaseOutCubic,
+		"SmoothStep":    SmoothStep,
+	}
+	for name, f := range funcs {
+		if got := f(0); !near(got, 0) {
+			t.Errorf("%s(0) = %v, want 0", name, got)
+		}
+		if got := f(1); !near(got, 1) {
+			t.Errorf("%s(1) = %v, want 1", name, got)
+		}
+		if got := f(2); !near(got, 1) {
+			t.Errorf("%s(2) = %v, want 1 (clamped)", name, got)
+		}
+	}
+	if got := EaseInOutQuad(0.5); !near(got, 0.5) {
+		t.Errorf("EaseInOutQuad(0.5) = %v, want 0.5", got)
+	}
+	if got := EaseInQuad(0.5); !near(got, 0.25) {
+		t.Errorf("EaseInQuad(0.5) = %v, want 0.25", got)
+	}
+}
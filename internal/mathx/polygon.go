@@ -0,0 +1,103 @@
+package mathx
+
+import "math"
+
+// PointsBounds returns the smallest and largest coordinates of points, or
+// zero vectors if there are none.
+func PointsBounds(points []Vec2) (lo, hi Vec2) {
+	if len(points) == 0 {
+		return Vec2{}, Vec2{}
+	}
+	lo, hi = points[0], points[0]
+	for _, p := range points[1:] {
+		lo = Vec2{math.Min(lo.X, p.X), math.Min(lo.Y, p.Y)}
+		hi = Vec2{math.Max(hi.X, p.X), math.Max(hi.Y, p.Y)}
+	}
+	return lo, hi
+}
+
+// PolygonContains reports whether p is inside the polygon (even-odd rule).
+// The polygon closes from its last point back to its first.
+func PolygonContains(poly []Vec2, p Vec2) bool {
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// PolygonIntersectsRect reports whether the polygon and the rectangle
+// x, y, w, h overlap. Touching edges don't count, as with AABBs.
+func PolygonIntersectsRect(poly []Vec2, x, y, w, h float64) bool {
+	if len(poly) < 3 || w <= 0 || h <= 0 {
+		return false
+	}
+	// A vertex inside the rectangle, or the rectangle's center inside the
+	// polygon, covers one containing the other
+	for _, p := range poly {
+		if p.X > x && p.X < x+w && p.Y > y && p.Y < y+h {
+			return true
+		}
+	}
+	if PolygonContains(poly, V(x+w/2, y+h/2)) {
+		return true
+	}
+	// Otherwise they overlap only if an edge crosses the rectangle
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		if segmentCrossesRect(poly[j], poly[i], x, y, w, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentCrossesRect reports whether the segment a-b passes through the
+// inside of the rectangle, clipping it to the rectangle (Liang-Barsky).
+func segmentCrossesRect(a, b Vec2, x, y, w, h float64) bool {
+	t0, t1 := 0.0, 1.0
+	d := b.Sub(a)
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q > 0
+		}
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		return t0 < t1
+	}
+	return clip(-d.X, a.X-x) && clip(d.X, x+w-a.X) &&
+		clip(-d.Y, a.Y-y) && clip(d.Y, y+h-a.Y)
+}
+
+// SegmentDistance returns the distance from p to the segment a-b.
+func SegmentDistance(p, a, b Vec2) float64 {
+	d := b.Sub(a)
+	lenSq := d.X*d.X + d.Y*d.Y
+	if lenSq == 0 {
+		return p.Dist(a)
+	}
+	t := Clamp01(((p.X-a.X)*d.X + (p.Y-a.Y)*d.Y) / lenSq)
+	return p.Dist(a.Lerp(b, t))
+}
+
+// PolylineDistance returns the distance from p to the nearest segment of
+// the polyline, or +Inf if it has no points.
+func PolylineDistance(points []Vec2, p Vec2) float64 {
+	switch len(points) {
+	case 0:
+		return math.Inf(1)
+	case 1:
+		return p.Dist(points[0])
+	}
+	dist := math.Inf(1)
+	for i := 1; i < len(points); i++ {
+		dist = math.Min(dist, SegmentDistance(p, points[i-1], points[i]))
+	}
+	return dist
+}
@@ -0,0 +1,86 @@
+package mathx
+
+import (
+	"math"
+	"testing"
+)
+
+// ramp is a right triangle rising from the bottom left to the top right of
+// a 32x16 box at 0, 0, like a slope.
+var ramp = []Vec2{{0, 16}, {32, 0}, {32, 16}}
+
+func TestPointsBounds(t *testing.T) {
+	lo, hi := PointsBounds([]Vec2{{4, -2}, {-3, 5}, {1, 1}})
+	if lo != V(-3, -2) || hi != V(4, 5) {
+		t.Errorf("PointsBounds = %v, %v, want {-3 -2}, {4 5}", lo, hi)
+	}
+	if lo, hi := PointsBounds(nil); lo != (Vec2{}) || hi != (Vec2{}) {
+		t.Errorf("PointsBounds(nil) = %v, %v, want zero", lo, hi)
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	tests := []struct {
+		p    Vec2
+		want bool
+	}{
+		{V(28, 12), true},
+		{V(16, 12), true}, // Just under the slope
+		{V(8, 4), false},  // Above it
+		{V(40, 8), false},
+		{V(-1, 15), false},
+	}
+	for _, tt := range tests {
+		if got := PolygonContains(ramp, tt.p); got != tt.want {
+			t.Errorf("PolygonContains(ramp, %v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPolygonIntersectsRect(t *testing.T) {
+	tests := []struct {
+		name       string
+		x, y, w, h float64
+		want       bool
+	}{
+		{"above the slope", 2, 0, 6, 6, false},
+		{"on the slope", 14, 4, 6, 6, true},
+		{"covering it all", -10, -10, 60, 40, true},
+		{"inside it", 28, 12, 2, 2, true},
+		{"right of it", 33, 0, 8, 16, false},
+		{"touching its side", 32, 0, 8, 16, false},
+		{"below", 0, 16, 32, 8, false},
+	}
+	for _, tt := range tests {
+		if got := PolygonIntersectsRect(ramp, tt.x, tt.y, tt.w, tt.h); got != tt.want {
+			t.Errorf("%s: PolygonIntersectsRect = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// An edge crossing the rectangle without a vertex inside either
+	thin := []Vec2{{-10, 4}, {50, 4}, {50, 6}, {-10, 6}}
+	if !PolygonIntersectsRect(thin, 0, 0, 10, 10) {
+		t.Error("a bar crossing the rectangle doesn't intersect it")
+	}
+}
+
+func TestPolylineDistance(t *testing.T) {
+	path := []Vec2{{0, 0}, {10, 0}, {10, 10}}
+	tests := []struct {
+		p    Vec2
+		want float64
+	}{
+		{V(5, 3), 3},
+		{V(13, 5), 3},
+		{V(-4, -3), 5}, // Past the first point
+		{V(10, 0), 0},
+	}
+	for _, tt := range tests {
+		if got := PolylineDistance(path, tt.p); !near(got, tt.want) {
+			t.Errorf("PolylineDistance(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := PolylineDistance(nil, V(0, 0)); !math.IsInf(got, 1) {
+		t.Errorf("PolylineDistance(nil) = %v, want +Inf", got)
+	}
+}
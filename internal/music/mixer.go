@@ -0,0 +1,189 @@
+// Package music provides layered background music driven by gameplay signals.
+//
+// A song is split into stems (base, tension, danger) that play in sync.
+// Gameplay code sets signals such as "near hazard" or "boss arena active",
+// and the Mixer crossfades each stem's volume toward the loudest signal bound
+// to it. The mixer only decides volumes; playback is done by whatever
+// implements VolumeSetter (e.g. an *audio.Player from ebiten/v2/audio).
+package music
+
+import (
+	"math"
+	"sort"
+
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+// Stem names for the default layered mix.
+const (
+	StemBase    = "base"
+	StemTension = "tension"
+	StemDanger  = "danger"
+)
+
+// Signal names understood by the default bindings.
+const (
+	SignalNearHazard = "near_hazard"
+	SignalBossArena  = "boss_arena"
+	SignalLowHP      = "low_hp"
+)
+
+// DefaultFadeRate is how fast stem volumes change, in volume units per second.
+const DefaultFadeRate = 1.5
+
+// VolumeSetter is implemented by a playing audio stream.
+type VolumeSetter interface {
+	SetVolume(volume float64)
+}
+
+// binding links a signal to a stem with a weight.
+type binding struct {
+	signal string
+	weight float64
+}
+
+// stem is a single music layer.
+type stem struct {
+	player   VolumeSetter
+	base     float64 // Volume when no signal is active
+	bindings []binding
+	volume   float64
+}
+
+// Mixer crossfades music stems based on gameplay signals.
+type Mixer struct {
+	// FadeRate is the maximum volume change per second
+	FadeRate float64
+
+	stems   map[string]*stem
+	signals map[string]float64
+}
+
+// NewMixer creates an empty mixer. Add stems with AddStem.
+func NewMixer() *Mixer {
+	return &Mixer{
+		FadeRate: DefaultFadeRate,
+		stems:    make(map[string]*stem),
+		signals:  make(map[string]float64),
+	}
+}
+
+// NewDefaultMixer creates a mixer with the standard base/tension/danger layout:
+// the base stem always plays, tension follows hazards and boss arenas, and
+// danger follows low HP and, at half strength, boss arenas.
+func NewDefaultMixer() *Mixer {
+	m := NewMixer()
+	m.AddStem(StemBase, 1)
+	m.AddStem(StemTension, 0)
+	m.AddStem(StemDanger, 0)
+	m.Bind(StemTension, SignalNearHazard, 1)
+	m.Bind(StemTension, SignalBossArena, 1)
+	m.Bind(StemDanger, SignalLowHP, 1)
+	m.Bind(StemDanger, SignalBossArena, 0.5)
+	return m
+}
+
+// AddStem adds a stem with the volume it plays at when no bound signal is active.
+// The stem starts at that volume. Adding an existing stem replaces it.
+func (m *Mixer) AddStem(name string, baseVolume float64) {
+	m.stems[name] = &stem{
+		base:   mathx.Clamp01(baseVolume),
+		volume: mathx.Clamp01(baseVolume),
+	}
+}
+
+// Bind makes a signal drive a stem. The stem's target volume is the largest
+// of its base volume and signal*weight over all bound signals.
+func (m *Mixer) Bind(stemName, signal string, weight float64) {
+	if s, ok := m.stems[stemName]; ok {
+		s.bindings = append(s.bindings, binding{signal: signal, weight: weight})
+	}
+}
+
+// SetPlayer attaches the audio stream that plays a stem.
+// The player's volume is set immediately to the stem's current volume.
+func (m *Mixer) SetPlayer(stemName string, player VolumeSetter) {
+	s, ok := m.stems[stemName]
+	if !ok {
+		return
+	}
+	s.player = player
+	if player != nil {
+		player.SetVolume(s.volume)
+	}
+}
+
+// SetSignal sets a signal's strength, clamped to the 0-1 range.
+func (m *Mixer) SetSignal(name string, value float64) {
+	m.signals[name] = mathx.Clamp01(value)
+}
+
+// Signal returns a signal's current strength (0 if never set).
+func (m *Mixer) Signal(name string) float64 {
+	return m.signals[name]
+}
+
+// Update moves every stem's volume toward its target by at most FadeRate*dt
+// and pushes changed volumes to the attached players.
+func (m *Mixer) Update(dt float64) {
+	step := m.FadeRate * dt
+	for _, s := range m.stems {
+		target := m.target(s)
+		next := s.volume
+		if next < target {
+			next = math.Min(next+step, target)
+		} else if next > target {
+			next = math.Max(next-step, target)
+		}
+		if next != s.volume {
+			s.volume = next
+			if s.player != nil {
+				s.player.SetVolume(next)
+			}
+		}
+	}
+}
+
+// Volume returns a stem's current volume (0 if the stem doesn't exist).
+func (m *Mixer) Volume(stemName string) float64 {
+	if s, ok := m.stems[stemName]; ok {
+		return s.volume
+	}
+	return 0
+}
+
+// StemNames returns all stem names in sorted order.
+func (m *Mixer) StemNames() []string {
+	names := make([]string, 0, len(m.stems))
+	for name := range m.stems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResetSignals sets every signal back to 0. Stems fade back to their base volume.
+func (m *Mixer) ResetSignals() {
+	for name := range m.signals {
+		m.signals[name] = 0
+	}
+}
+
+// target returns the volume a stem is fading toward.
+func (m *Mixer) target(s *stem) float64 {
+	target := s.base
+	for _, b := range s.bindings {
+		target = math.Max(target, m.signals[b.signal]*b.weight)
+	}
+	return mathx.Clamp01(target)
+}
+
+// sortedKeys returns the keys of a set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
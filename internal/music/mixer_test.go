@@ -0,0 +1,172 @@
+package music
+
+import (
+	"math"
+	"testing"
+)
+
+// mockPlayer implements VolumeSetter for testing.
+type mockPlayer struct {
+	volume float64
+	calls  int
+}
+
+func (p *mockPlayer) SetVolume(volume float64) {
+	p.volume = volume
+	p.calls++
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestDefaultMixerStartsOnBase(t *testing.T) {
+	m := NewDefaultMixer()
+
+	if v := m.Volume(StemBase); v != 1 {
+		t.Errorf("Expected base volume 1, got %v", v)
+	}
+	if v := m.Volume(StemTension); v != 0 {
+		t.Errorf("Expected tension volume 0, got %v", v)
+	}
+	if v := m.Volume(StemDanger); v != 0 {
+		t.Errorf("Expected danger volume 0, got %v", v)
+	}
+}
+
+func TestMixerCrossfadesAtFadeRate(t *testing.T) {
+	m := NewDefaultMixer()
+	m.FadeRate = 1
+
+	m.SetSignal(SignalNearHazard, 1)
+	m.Update(0.25)
+	if v := m.Volume(StemTension); !approxEqual(v, 0.25) {
+		t.Errorf("Expected tension volume 0.25 after 0.25s, got %v", v)
+	}
+
+	// Fading stops at the target
+	m.Update(2)
+	if v := m.Volume(StemTension); v != 1 {
+		t.Errorf("Expected tension volume 1, got %v", v)
+	}
+
+	// And fades back down when the signal drops
+	m.SetSignal(SignalNearHazard, 0)
+	m.Update(0.5)
+	if v := m.Volume(StemTension); !approxEqual(v, 0.5) {
+		t.Errorf("Expected tension volume 0.5 while fading out, got %v", v)
+	}
+}
+
+func TestMixerUsesStrongestBinding(t *testing.T) {
+	m := NewDefaultMixer()
+	m.FadeRate = 100
+
+	// Boss arena drives danger at half weight
+	m.SetSignal(SignalBossArena, 1)
+	m.Update(1)
+	if v := m.Volume(StemDanger); !approxEqual(v, 0.5) {
+		t.Errorf("Expected danger volume 0.5 from boss arena, got %v", v)
+	}
+
+	// Low HP outweighs it
+	m.SetSignal(SignalLowHP, 0.8)
+	m.Update(1)
+	if v := m.Volume(StemDanger); !approxEqual(v, 0.8) {
+		t.Errorf("Expected danger volume 0.8 from low HP, got %v", v)
+	}
+
+	// Base never drops below its base volume
+	if v := m.Volume(StemBase); v != 1 {
+		t.Errorf("Expected base volume 1, got %v", v)
+	}
+}
+
+func TestMixerClampsSignals(t *testing.T) {
+	m := NewDefaultMixer()
+
+	m.SetSignal(SignalLowHP, 5)
+	if v := m.Signal(SignalLowHP); v != 1 {
+		t.Errorf("Expected signal clamped to 1, got %v", v)
+	}
+	m.SetSignal(SignalLowHP, -1)
+	if v := m.Signal(SignalLowHP); v != 0 {
+		t.Errorf("Expected signal clamped to 0, got %v", v)
+	}
+}
+
+func TestMixerPushesVolumeToPlayer(t *testing.T) {
+	m := NewDefaultMixer()
+	m.FadeRate = 100
+	p := &mockPlayer{volume: -1}
+
+	m.SetPlayer(StemTension, p)
+	if p.volume != 0 || p.calls != 1 {
+		t.Fatalf("Expected player set to current volume 0 once, got %v after %d calls", p.volume, p.calls)
+	}
+
+	// No change, no call
+	m.Update(1)
+	if p.calls != 1 {
+		t.Errorf("Expected no SetVolume call without change, got %d calls", p.calls)
+	}
+
+	m.SetSignal(SignalBossArena, 1)
+	m.Update(1)
+	if p.volume != 1 || p.calls != 2 {
+		t.Errorf("Expected player volume 1 after 2 calls, got %v after %d calls", p.volume, p.calls)
+	}
+}
+
+func TestMixerResetSignals(t *testing.T) {
+	m := NewDefaultMixer()
+	m.SetSignal(SignalBossArena, 1)
+	m.SetSignal(SignalLowHP, 1)
+
+	m.ResetSignals()
+
+	if m.Signal(SignalBossArena) != 0 || m.Signal(SignalLowHP) != 0 {
+		t.Error("Expected all signals reset to 0")
+	}
+}
+
+func TestSignalTargets(t *testing.T) {
+	m := NewDefaultMixer()
+
+	targets := m.SignalTargets()
+	want := []string{"music.boss_arena", "music.low_hp", "music.near_hazard"}
+	if len(targets) != len(want) {
+		t.Fatalf("Expected %d targets, got %d", len(want), len(targets))
+	}
+	for i, target := range targets {
+		if target.TargetID() != want[i] {
+			t.Errorf("Target %d: expected ID %q, got %q", i, want[i], target.TargetID())
+		}
+	}
+}
+
+func TestSignalTargetActions(t *testing.T) {
+	m := NewDefaultMixer()
+	target := NewSignalTarget(m, SignalBossArena)
+
+	target.Activate()
+	if m.Signal(SignalBossArena) != 1 {
+		t.Error("Expected Activate to raise signal to 1")
+	}
+
+	target.Deactivate()
+	if m.Signal(SignalBossArena) != 0 {
+		t.Error("Expected Deactivate to drop signal to 0")
+	}
+
+	target.Toggle()
+	if m.Signal(SignalBossArena) != 1 {
+		t.Error("Expected Toggle to raise signal from 0")
+	}
+
+	m.SetSignal(SignalBossArena, 0.3)
+	target.Toggle()
+	if m.Signal(SignalBossArena) != 0 {
+		t.Error("Expected Toggle to drop a partial signal to 0")
+	}
+}
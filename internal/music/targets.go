@@ -0,0 +1,60 @@
+package music
+
+// TargetPrefix prefixes the target IDs of signal targets, so rules can
+// address a signal as e.g. "music.boss_arena".
+const TargetPrefix = "music."
+
+// SignalTarget exposes a mixer signal as a rule target.
+// Activate raises the signal to full strength, Deactivate drops it to zero.
+// It satisfies entities.Targetable and can be registered in a TargetRegistry.
+type SignalTarget struct {
+	mixer  *Mixer
+	signal string
+}
+
+// NewSignalTarget creates a rule target controlling the named signal.
+func NewSignalTarget(m *Mixer, signal string) *SignalTarget {
+	return &SignalTarget{mixer: m, signal: signal}
+}
+
+// Activate sets the signal to 1.
+func (t *SignalTarget) Activate() {
+	t.mixer.SetSignal(t.signal, 1)
+}
+
+// Deactivate sets the signal to 0.
+func (t *SignalTarget) Deactivate() {
+	t.mixer.SetSignal(t.signal, 0)
+}
+
+// Toggle switches the signal between 0 and full strength.
+// Any partial strength counts as on and toggles to 0.
+func (t *SignalTarget) Toggle() {
+	if t.mixer.Signal(t.signal) > 0 {
+		t.Deactivate()
+	} else {
+		t.Activate()
+	}
+}
+
+// TargetID returns the signal name with TargetPrefix.
+func (t *SignalTarget) TargetID() string {
+	return TargetPrefix + t.signal
+}
+
+// SignalTargets returns a target for every signal bound to any stem,
+// in sorted order of signal name.
+func (m *Mixer) SignalTargets() []*SignalTarget {
+	seen := make(map[string]bool)
+	for _, name := range m.StemNames() {
+		for _, b := range m.stems[name].bindings {
+			seen[b.signal] = true
+		}
+	}
+
+	targets := make([]*SignalTarget, 0, len(seen))
+	for _, name := range sortedKeys(seen) {
+		targets = append(targets, NewSignalTarget(m, name))
+	}
+	return targets
+}
@@ -1,6 +1,9 @@
 package physics
 
-import "github.com/torsten/GoP/internal/world"
+import (
+	"github.com/torsten/GoP/internal/mathx"
+	"github.com/torsten/GoP/internal/world"
+)
 
 // SolidEntity represents an entity with a physical body that can collide
 type SolidEntity interface {
@@ -22,6 +25,27 @@ type Body struct {
 	VelX, VelY float64 // Velocity in pixels per second
 	W, H       float64 // Size (width and height)
 	OnGround   bool    // True if standing on solid ground
+
+	prevX, prevY float64 // Position at the last SavePrevious
+	hasPrev      bool
+}
+
+// SavePrevious records the current position as the start of a physics step,
+// for Interpolate. Call it before each step moves the body, and again right
+// after teleporting it so drawing doesn't sweep across the jump.
+func (b *Body) SavePrevious() {
+	b.prevX, b.prevY = b.PosX, b.PosY
+	b.hasPrev = true
+}
+
+// Interpolate returns the position alpha (0 to 1) of the way from the last
+// SavePrevious to the current position, for drawing between physics steps.
+// A body that never saved a position returns its current one.
+func (b *Body) Interpolate(alpha float64) (x, y float64) {
+	if !b.hasPrev {
+		return b.PosX, b.PosY
+	}
+	return mathx.Lerp(b.prevX, b.PosX, alpha), mathx.Lerp(b.prevY, b.PosY, alpha)
 }
 
 // AABB returns the axis-aligned bounding box for this body.
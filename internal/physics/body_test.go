@@ -0,0 +1,28 @@
+//go:build display
+
+package physics
+
+import "testing"
+
+func TestBodyInterpolate(t *testing.T) {
+	b := &Body{PosX: 10, PosY: 20}
+	if x, y := b.Interpolate(0.5); x != 10 || y != 20 {
+		t.Errorf("Interpolate before SavePrevious = %v, %v, want the current 10, 20", x, y)
+	}
+
+	b.SavePrevious()
+	b.PosX, b.PosY = 20, 10
+	if x, y := b.Interpolate(0.25); x != 12.5 || y != 17.5 {
+		t.Errorf("Interpolate(0.25) = %v, %v, want 12.5, 17.5", x, y)
+	}
+	if x, y := b.Interpolate(1); x != 20 || y != 10 {
+		t.Errorf("Interpolate(1) = %v, %v, want the current 20, 10", x, y)
+	}
+
+	// Saving again after a teleport stops the sweep
+	b.PosX = 500
+	b.SavePrevious()
+	if x, _ := b.Interpolate(0); x != 500 {
+		t.Errorf("Interpolate(0) after teleporting = %v, want 500", x)
+	}
+}
@@ -1,13 +1,18 @@
 package physics
 
 import (
+	"math"
 	"time"
 
 	"github.com/torsten/GoP/internal/game"
 	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/mathx"
 	"github.com/torsten/GoP/internal/world"
 )
 
+// tileSize is the size of the tiles the collision function reports, in pixels.
+const tileSize = 16
+
 // Collision represents collision information returned by the collision function.
 type Collision struct {
 	// Tile coordinates
@@ -34,6 +39,13 @@ type PlayerState struct {
 
 	// Platform carry tracking
 	CurrentPlatform Kinematic // The platform the player is currently standing on (nil if none)
+
+	// Step tracking
+	Walking bool // On the ground when this tick's movement started, so small steps are climbed
+
+	// Crouch tracking
+	Crouching   bool    // True while crouched
+	StandHeight float64 // Body height when standing, saved on crouching
 }
 
 // Controller handles player input and physics with feel mechanics.
@@ -41,6 +53,7 @@ type Controller struct {
 	Body   *Body
 	Tuning game.Tuning
 	State  PlayerState
+	Zones  []ForceZone // The level's force zones, applied in the gravity step
 }
 
 // NewController creates a controller with tuning parameters.
@@ -102,13 +115,18 @@ func (c *Controller) Update(inp *input.Input, collisionMap *world.CollisionMap,
 // FixedUpdate processes input and updates physics with feel mechanics.
 // This method is designed to be called at a fixed timestep (e.g., 60Hz).
 // The collisionFunc should check for collisions at the given AABB and return
-// collision information for resolution.
+// collision information for resolution. The controller is done with each
+// result before its next call and may overwrite it, so the function can hand
+// out scratch memory (see the arena package) instead of allocating.
 func (c *Controller) FixedUpdate(dt time.Duration, inp *input.Input, collisionFunc func(AABB) []Collision) {
 	dtSeconds := dt.Seconds()
 
 	// Update state tracking
 	c.updateStateTracking(dt, inp)
 
+	// Crouch or stand up
+	c.updateCrouch(inp, collisionFunc)
+
 	// Process horizontal movement with acceleration
 	c.updateHorizontal(inp, dtSeconds)
 
@@ -123,6 +141,7 @@ func (c *Controller) FixedUpdate(dt time.Duration, inp *input.Input, collisionFu
 	dy := c.Body.VelY * dtSeconds
 
 	// Resolve collisions using the provided collision function
+	c.State.Walking = c.Body.OnGround
 	c.resolveCollisions(dx, dy, collisionFunc)
 
 	// Update ground state tracking after collision
@@ -155,6 +174,44 @@ func (c *Controller) updateStateTracking(dt time.Duration, inp *input.Input) {
 	}
 }
 
+// updateCrouch crouches the player while the crouch action is held on the
+// ground, shrinking the body to Crouch.HeightMult of its height, and stands
+// them back up once it's released and there is room above.
+func (c *Controller) updateCrouch(inp *input.Input, collisionFunc func(AABB) []Collision) {
+	held := inp.Pressed(input.ActionCrouch)
+	if !c.State.Crouching {
+		mult := c.Tuning.Crouch.HeightMult
+		if held && c.Body.OnGround && mult > 0 && mult < 1 {
+			c.State.Crouching = true
+			c.State.StandHeight = c.Body.H
+			c.setHeight(c.Body.H * mult)
+		}
+		return
+	}
+	if held {
+		return
+	}
+
+	// Keep crawling while a ceiling is in the way
+	stand := c.Body.AABB()
+	stand.Y += stand.H - c.State.StandHeight
+	stand.H = c.State.StandHeight
+	if blocked(stand, collisionFunc) {
+		return
+	}
+	c.setHeight(c.State.StandHeight)
+	c.State.Crouching = false
+}
+
+// setHeight resizes the body, keeping its feet in place. The interpolation
+// start moves along, so drawing doesn't sweep from the old top.
+func (c *Controller) setHeight(h float64) {
+	delta := c.Body.H - h
+	c.Body.PosY += delta
+	c.Body.prevY += delta
+	c.Body.H = h
+}
+
 // updateHorizontal handles horizontal movement with acceleration and friction.
 func (c *Controller) updateHorizontal(inp *input.Input, dt float64) {
 	tuning := c.Tuning.Horizontal
@@ -167,6 +224,16 @@ func (c *Controller) updateHorizontal(inp *input.Input, dt float64) {
 		inputDir = 1
 	}
 
+	// Near the apex of a jump the player may go a little faster, and
+	// crouched they crawl
+	maxSpeed := tuning.MaxSpeed
+	if c.nearApex() {
+		maxSpeed += c.Tuning.Jump.ApexSpeedBonus
+	}
+	if c.State.Crouching {
+		maxSpeed *= c.Tuning.Crouch.SpeedMult
+	}
+
 	// Apply acceleration or deceleration
 	if inputDir != 0 {
 		// Calculate acceleration (reduced in air)
@@ -179,11 +246,7 @@ func (c *Controller) updateHorizontal(inp *input.Input, dt float64) {
 		c.Body.VelX += inputDir * accel * dt
 
 		// Clamp to max speed
-		if c.Body.VelX > tuning.MaxSpeed {
-			c.Body.VelX = tuning.MaxSpeed
-		} else if c.Body.VelX < -tuning.MaxSpeed {
-			c.Body.VelX = -tuning.MaxSpeed
-		}
+		c.Body.VelX = mathx.Clamp(c.Body.VelX, -maxSpeed, maxSpeed)
 	} else {
 		// No input - apply deceleration or friction
 		if c.Body.OnGround {
@@ -195,31 +258,11 @@ func (c *Controller) updateHorizontal(inp *input.Input, dt float64) {
 			c.Body.VelX *= (1 - friction)
 
 			// Deceleration for more responsive stops
-			if c.Body.VelX > 0 {
-				c.Body.VelX -= tuning.Deceleration * dt
-				if c.Body.VelX < 0 {
-					c.Body.VelX = 0
-				}
-			} else if c.Body.VelX < 0 {
-				c.Body.VelX += tuning.Deceleration * dt
-				if c.Body.VelX > 0 {
-					c.Body.VelX = 0
-				}
-			}
+			c.Body.VelX = mathx.Approach(c.Body.VelX, 0, tuning.Deceleration*dt)
 		} else {
 			// Air deceleration (reduced)
 			airDecel := tuning.Deceleration * tuning.AirControl
-			if c.Body.VelX > 0 {
-				c.Body.VelX -= airDecel * dt
-				if c.Body.VelX < 0 {
-					c.Body.VelX = 0
-				}
-			} else if c.Body.VelX < 0 {
-				c.Body.VelX += airDecel * dt
-				if c.Body.VelX > 0 {
-					c.Body.VelX = 0
-				}
-			}
+			c.Body.VelX = mathx.Approach(c.Body.VelX, 0, airDecel*dt)
 		}
 	}
 }
@@ -242,6 +285,9 @@ func (c *Controller) updateJump(inp *input.Input, dt float64) {
 		c.executeJump()
 		c.State.JumpBuffered = false
 		c.State.JumpBufferTime = 0
+	} else if c.State.JumpBufferTime <= 0 {
+		// Without a buffer time the press only counts this tick
+		c.State.JumpBuffered = false
 	}
 
 	// Variable jump height - apply extra gravity if released early
@@ -279,12 +325,33 @@ func (c *Controller) applyGravity(dt float64) {
 		gravity *= jumpTuning.EarlyReleaseMult
 	}
 
-	c.Body.VelY += gravity * dt
+	// Hang near the apex
+	if c.nearApex() {
+		gravity *= jumpTuning.ApexGravityMult
+	}
+
+	// Force zones around the body's center scale gravity and add wind
+	a := c.Body.AABB()
+	scale, windX, windY, inZone := forcesAt(c.Zones, a.X+a.W/2, a.Y+a.H/2)
+
+	c.Body.VelX += windX * dt
+	c.Body.VelY += (gravity*scale + windY) * dt
 
-	// Clamp to max fall speed
+	// Clamp to max fall speed, and in a zone to the same speed upward
 	if c.Body.VelY > gravityTuning.MaxFall {
 		c.Body.VelY = gravityTuning.MaxFall
 	}
+	if inZone && c.Body.VelY < -gravityTuning.MaxFall {
+		c.Body.VelY = -gravityTuning.MaxFall
+	}
+}
+
+// nearApex returns true while a held jump is slow enough vertically to be at
+// its apex (see JumpTuning.ApexThreshold). Jumps released early don't hang.
+func (c *Controller) nearApex() bool {
+	threshold := c.Tuning.Jump.ApexThreshold
+	return threshold > 0 && c.State.IsJumping && !c.State.JumpReleased && !c.Body.OnGround &&
+		c.Body.VelY > -threshold && c.Body.VelY < threshold
 }
 
 // resolveCollisions handles collision resolution using axis-separated resolution.
@@ -294,6 +361,18 @@ func (c *Controller) resolveCollisions(dx, dy float64, collisionFunc func(AABB)
 	if dx != 0 {
 		c.Body.PosX += dx
 
+		// A walking player steps up small ledges, and an airborne one who
+		// barely missed a ledge is lifted onto it
+		maxLift := c.Tuning.Jump.LedgeForgiveness
+		if c.State.Walking {
+			maxLift = c.Tuning.Horizontal.MaxStepHeight
+		}
+		if c.liftOntoLedge(maxLift, collisionFunc) && c.Body.VelY >= 0 {
+			c.Body.OnGround = true
+			c.Body.VelY = 0
+			dy = 0
+		}
+
 		// Check for collision after X movement
 		collisions := collisionFunc(c.Body.AABB())
 		if len(collisions) > 0 {
@@ -321,6 +400,11 @@ func (c *Controller) resolveCollisions(dx, dy float64, collisionFunc func(AABB)
 		// Reset ground state before checking
 		c.Body.OnGround = false
 
+		// Slip around a ceiling corner clipped by a few pixels
+		if dy < 0 && c.correctCorner(c.Tuning.Jump.CornerCorrection, collisionFunc) {
+			return
+		}
+
 		// Check for collision after Y movement
 		collisions := collisionFunc(c.Body.AABB())
 		if len(collisions) > 0 {
@@ -342,6 +426,135 @@ func (c *Controller) resolveCollisions(dx, dy float64, collisionFunc func(AABB)
 	}
 }
 
+// correctCorner nudges the body sideways by up to maxShift pixels out of the
+// solid tiles it moved up into, if that frees it. Returns true if it did.
+func (c *Controller) correctCorner(maxShift float64, collisionFunc func(AABB) []Collision) bool {
+	if maxShift <= 0 {
+		return false
+	}
+	body := c.Body.AABB()
+	tiles := overlapping(body, collisionFunc(body))
+	if len(tiles) == 0 {
+		return false
+	}
+
+	// Horizontal extent of the tiles in the way
+	left, right := math.Inf(1), math.Inf(-1)
+	for _, col := range tiles {
+		left = math.Min(left, float64(col.TileX*tileSize))
+		right = math.Max(right, float64((col.TileX+1)*tileSize))
+	}
+
+	// Try the shorter way around first
+	shifts := []float64{left - (body.X + body.W), right - body.X}
+	if math.Abs(shifts[1]) < math.Abs(shifts[0]) {
+		shifts[0], shifts[1] = shifts[1], shifts[0]
+	}
+	for _, shift := range shifts {
+		if math.Abs(shift) > maxShift {
+			continue
+		}
+		moved := body
+		moved.X += shift
+		if !blocked(moved, collisionFunc) {
+			c.Body.PosX += shift
+			return true
+		}
+	}
+	return false
+}
+
+// liftOntoLedge lifts the body by up to maxLift pixels onto the top of the
+// solid tiles it moved into, if that frees it: the tiles only cover its
+// feet. Returns true if it did.
+func (c *Controller) liftOntoLedge(maxLift float64, collisionFunc func(AABB) []Collision) bool {
+	if maxLift <= 0 {
+		return false
+	}
+	body := c.Body.AABB()
+	tiles := overlapping(body, collisionFunc(body))
+	if len(tiles) == 0 {
+		return false
+	}
+
+	top := math.Inf(1)
+	for _, col := range tiles {
+		top = math.Min(top, float64(col.TileY*tileSize))
+	}
+	lift := body.Y + body.H - top
+	if lift <= 0 || lift > maxLift {
+		return false
+	}
+
+	moved := body
+	moved.Y = top - body.H
+	if blocked(moved, collisionFunc) {
+		return false
+	}
+	c.Body.PosY = moved.Y
+	return true
+}
+
+// StepOntoSolids lifts a walking player onto the solids (platforms, doors)
+// whose tops are at most Horizontal.MaxStepHeight above their feet, which
+// ResolveSolids would otherwise push them away from. Call it after
+// FixedUpdate and before ResolveSolids.
+func (c *Controller) StepOntoSolids(solids []AABB) {
+	maxStep := c.Tuning.Horizontal.MaxStepHeight
+	if !c.State.Walking || maxStep <= 0 || c.Body.VelY < 0 {
+		return
+	}
+	for _, solid := range solids {
+		body := c.Body.AABB()
+		lift := body.Bottom() - solid.Top()
+		if !body.Intersects(solid) || lift > maxStep {
+			continue
+		}
+
+		// There must be room above the step
+		moved := body
+		moved.Y = solid.Top() - body.H
+		free := true
+		for _, other := range solids {
+			free = free && !moved.Intersects(other)
+		}
+		if free {
+			c.Body.PosY = moved.Y
+			c.Body.OnGround = true
+			c.Body.VelY = 0
+		}
+	}
+}
+
+// overlapping returns the collisions whose tiles b overlaps. Collision
+// functions may also report tiles b only touches. It filters collisions in
+// place, so it doesn't allocate.
+func overlapping(b AABB, collisions []Collision) []Collision {
+	result := collisions[:0]
+	for _, col := range collisions {
+		if overlapsTile(b, col) {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// overlapsTile returns true if b overlaps the tile of col.
+func overlapsTile(b AABB, col Collision) bool {
+	tx, ty := float64(col.TileX*tileSize), float64(col.TileY*tileSize)
+	return b.X < tx+tileSize && b.X+b.W > tx && b.Y < ty+tileSize && b.Y+b.H > ty
+}
+
+// blocked returns true if b overlaps a solid tile.
+func blocked(b AABB, collisionFunc func(AABB) []Collision) bool {
+	for _, col := range collisionFunc(b) {
+		if overlapsTile(b, col) {
+			return true
+		}
+	}
+	return false
+}
+
 // ApplyPlatformCarry applies platform velocity to the player position.
 // This should be called BEFORE the player's own physics update.
 // The player is carried if standing on a platform.
@@ -0,0 +1,457 @@
+//go:build display
+
+package physics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/torsten/GoP/internal/arena"
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/input"
+)
+
+// tick is the fixed timestep the game runs the controller at.
+const tick = time.Second / 60
+
+// floorY is the top of the floor in the test scenarios: tile row 10.
+const floorY = 160
+
+// scenario is a player standing on (or above) a flat floor, driven by a
+// script.
+type scenario struct {
+	ctrl  *Controller
+	inp   *input.Input
+	floor bool     // Whether the floor is there
+	tiles [][2]int // Solid tiles besides the floor, as tile x and y
+
+	collisions arena.Arena[Collision] // Reset every step, like the scenes do
+}
+
+// newScenario creates a 16x32 player whose feet are above the floor by
+// height pixels, 0 = standing on it.
+func newScenario(tuning game.Tuning, script *input.Script, height float64) *scenario {
+	body := &Body{PosX: 100, PosY: floorY - 32 - height, W: 16, H: 32, OnGround: height == 0}
+	s := &scenario{ctrl: NewController(body, tuning), inp: input.NewScriptedInput(script), floor: true}
+	if height > 0 {
+		// In the air long enough ago that coyote time doesn't apply
+		s.ctrl.State.TimeSinceGrounded = time.Second
+	}
+	return s
+}
+
+// collide reports the floor under b, if it's there, and the solid tiles b
+// overlaps or touches, normal along the axis of least overlap like the
+// game's scenes.
+func (s *scenario) collide(b AABB) []Collision {
+	collisions := s.collisions.Alloc(len(s.tiles) + 1)[:0]
+	if s.floor && b.Y+b.H > floorY {
+		collisions = append(collisions, Collision{TileX: int(b.X / 16), TileY: floorY / 16, NormalY: -1})
+	}
+	for _, t := range s.tiles {
+		tx, ty := float64(t[0]*16), float64(t[1]*16)
+		if b.X > tx+16 || b.X+b.W < tx || b.Y > ty+16 || b.Y+b.H < ty {
+			continue
+		}
+		left, right := b.X+b.W-tx, tx+16-b.X
+		top, bottom := b.Y+b.H-ty, ty+16-b.Y
+		col := Collision{TileX: t[0], TileY: t[1]}
+		switch {
+		case min(left, right) < min(top, bottom) && left <= right:
+			col.NormalX = -1
+		case min(left, right) < min(top, bottom):
+			col.NormalX = 1
+		case top <= bottom:
+			col.NormalY = -1
+		default:
+			col.NormalY = 1
+		}
+		collisions = append(collisions, col)
+	}
+	return collisions
+}
+
+// step runs one tick.
+func (s *scenario) step() {
+	s.collisions.Reset()
+	s.ctrl.FixedUpdate(tick, s.inp, s.collide)
+	s.inp.Update()
+}
+
+// jumpRise returns how high the player gets above the floor in 2 seconds.
+func jumpRise(tuning game.Tuning, script *input.Script) float64 {
+	s := newScenario(tuning, script, 0)
+	startY, minY := s.ctrl.Body.PosY, s.ctrl.Body.PosY
+	for i := 0; i < 120; i++ {
+		s.step()
+		minY = min(minY, s.ctrl.Body.PosY)
+	}
+	return startY - minY
+}
+
+func TestJumpHeightMatchesTuning(t *testing.T) {
+	tuning := game.DefaultTuning()
+	held := jumpRise(tuning, input.NewScript().Hold(input.ActionJump, 0, 120))
+
+	// A held jump rises v²/2g, less up to one tick of movement for the
+	// fixed step
+	v, g := -tuning.Jump.Velocity, tuning.Gravity.Base
+	ideal := v * v / (2 * g)
+	if held > ideal || held < ideal-v*tick.Seconds() {
+		t.Errorf("held jump rose %.1fpx, want about %.1fpx", held, ideal)
+	}
+
+	// Releasing early cuts the jump short
+	tapped := jumpRise(tuning, input.NewScript().Hold(input.ActionJump, 0, 1))
+	if tapped >= held*0.8 {
+		t.Errorf("tapped jump rose %.1fpx, want well below the held %.1fpx", tapped, held)
+	}
+
+	tuning.Jump.VariableHeight = false
+	if fixed := jumpRise(tuning, input.NewScript().Hold(input.ActionJump, 0, 1)); fixed != held {
+		t.Errorf("tapped jump without variable height rose %.1fpx, want the held %.1fpx", fixed, held)
+	}
+}
+
+// jumpsAfterLeaving returns whether pressing jump at tick press, after the
+// floor disappears at tick 0, makes the player jump.
+func jumpsAfterLeaving(tuning game.Tuning, press int) bool {
+	s := newScenario(tuning, input.NewScript().Hold(input.ActionJump, press, 1), 0)
+	s.floor = false
+	for i := 0; i <= press; i++ {
+		s.step()
+	}
+	return s.ctrl.Body.VelY < 0
+}
+
+func TestCoyoteTimeWindow(t *testing.T) {
+	tuning := game.DefaultTuning()
+	coyoteTicks := int(tuning.Jump.CoyoteTime / tick)
+
+	if !jumpsAfterLeaving(tuning, coyoteTicks-1) {
+		t.Errorf("no jump %d ticks after leaving the ground, within the %v coyote time", coyoteTicks-1, tuning.Jump.CoyoteTime)
+	}
+	if jumpsAfterLeaving(tuning, coyoteTicks+2) {
+		t.Errorf("jumped %d ticks after leaving the ground, past the %v coyote time", coyoteTicks+2, tuning.Jump.CoyoteTime)
+	}
+
+	tuning.Jump.CoyoteTime = 0
+	if jumpsAfterLeaving(tuning, coyoteTicks-1) {
+		t.Error("jumped in the air without coyote time")
+	}
+}
+
+// landingTick returns the tick a player dropped from height lands on.
+func landingTick(tuning game.Tuning, height float64) int {
+	s := newScenario(tuning, input.NewScript(), height)
+	for i := 0; i < 600; i++ {
+		s.step()
+		if s.ctrl.Body.OnGround {
+			return i
+		}
+	}
+	return -1
+}
+
+// jumpsOnLanding returns whether pressing jump at tick press while falling
+// from height makes the player jump right after landing.
+func jumpsOnLanding(tuning game.Tuning, height float64, press int) bool {
+	landed := landingTick(tuning, height)
+	s := newScenario(tuning, input.NewScript().Hold(input.ActionJump, press, 1), height)
+	for i := 0; i <= landed+1; i++ {
+		s.step()
+	}
+	return s.ctrl.Body.VelY < 0
+}
+
+func TestJumpBufferWindow(t *testing.T) {
+	const height = 40
+	tuning := game.DefaultTuning()
+	landed := landingTick(tuning, height)
+	if landed < 0 {
+		t.Fatal("player never landed")
+	}
+	bufferTicks := int(tuning.Jump.BufferTime / tick)
+
+	if !jumpsOnLanding(tuning, height, landed-2) {
+		t.Errorf("jump pressed 2 ticks before landing wasn't buffered (buffer %v)", tuning.Jump.BufferTime)
+	}
+	if jumpsOnLanding(tuning, height, landed-bufferTicks-3) {
+		t.Errorf("jump pressed %d ticks before landing was still buffered (buffer %v)", bufferTicks+3, tuning.Jump.BufferTime)
+	}
+
+	tuning.Jump.BufferTime = 0
+	if jumpsOnLanding(tuning, height, landed-2) {
+		t.Error("jump was buffered without a buffer time")
+	}
+}
+
+func TestApexModifiers(t *testing.T) {
+	hold := func() *input.Script {
+		return input.NewScript().Hold(input.ActionJump, 0, 120).Hold(input.ActionMoveRight, 0, 120)
+	}
+	plain := game.DefaultTuning()
+	floaty := plain
+	floaty.Jump.ApexThreshold = 60
+	floaty.Jump.ApexGravityMult = 0.5
+	floaty.Jump.ApexSpeedBonus = 30
+
+	// Airtime and top speed of a held jump to the right
+	airtime := func(tuning game.Tuning) (ticks int, topSpeed float64) {
+		s := newScenario(tuning, hold(), 0)
+		for i := 0; i < 120; i++ {
+			s.step()
+			topSpeed = max(topSpeed, s.ctrl.Body.VelX)
+			if s.ctrl.Body.OnGround {
+				return i, topSpeed
+			}
+		}
+		return -1, topSpeed
+	}
+
+	plainTicks, plainSpeed := airtime(plain)
+	floatyTicks, floatySpeed := airtime(floaty)
+	if floatyTicks <= plainTicks {
+		t.Errorf("jump with apex hang lasted %d ticks, want longer than %d", floatyTicks, plainTicks)
+	}
+	if plainSpeed > plain.Horizontal.MaxSpeed {
+		t.Errorf("top speed without apex bonus %.1f, want at most %.1f", plainSpeed, plain.Horizontal.MaxSpeed)
+	}
+	if floatySpeed <= plain.Horizontal.MaxSpeed {
+		t.Errorf("top speed with apex bonus %.1f, want above %.1f", floatySpeed, plain.Horizontal.MaxSpeed)
+	}
+
+	// The modifiers don't change the jump's takeoff
+	s := newScenario(floaty, hold(), 0)
+	s.step()
+	if want := plain.Jump.Velocity + plain.Gravity.Base*tick.Seconds(); s.ctrl.Body.VelY != want {
+		t.Errorf("takeoff velocity %.1f, want %.1f", s.ctrl.Body.VelY, want)
+	}
+
+	// A jump released early doesn't hang
+	tapped := floaty
+	tapped.Jump.ApexSpeedBonus = 0
+	tap := func(tuning game.Tuning) float64 {
+		return jumpRise(tuning, input.NewScript().Hold(input.ActionJump, 0, 1))
+	}
+	if got, want := tap(tapped), tap(plain); got != want {
+		t.Errorf("tapped jump with apex hang rose %.1fpx, want the plain %.1fpx", got, want)
+	}
+}
+
+func TestCornerCorrection(t *testing.T) {
+	// A held jump whose right 6px clip a ceiling tile 16px above the head.
+	// Returns the highest the head got and where the player ended up.
+	jump := func(tuning game.Tuning) (minY, x float64) {
+		s := newScenario(tuning, input.NewScript().Hold(input.ActionJump, 0, 120), 0)
+		s.ctrl.Body.PosX = 102
+		s.tiles = [][2]int{{7, 6}}
+		minY = s.ctrl.Body.PosY
+		for i := 0; i < 30; i++ {
+			s.step()
+			minY = min(minY, s.ctrl.Body.PosY)
+		}
+		return minY, s.ctrl.Body.PosX
+	}
+
+	tuning := game.DefaultTuning()
+	if minY, x := jump(tuning); minY != 112 || x != 102 {
+		t.Errorf("6px clip with %vpx correction: head reached %.1f at x %.1f, want a bonk at 112", tuning.Jump.CornerCorrection, minY, x)
+	}
+
+	tuning.Jump.CornerCorrection = 8
+	if minY, x := jump(tuning); minY >= 112 || x != 96 {
+		t.Errorf("6px clip with 8px correction: head reached %.1f at x %.1f, want past 112 nudged to 96", minY, x)
+	}
+}
+
+func TestLedgeForgiveness(t *testing.T) {
+	// Falling right into a 16px step whose top is 2px above the feet
+	fall := func(tuning game.Tuning) *scenario {
+		s := newScenario(tuning, input.NewScript().Hold(input.ActionMoveRight, 0, 60), floorY-146)
+		s.ctrl.Body.PosX = 112
+		s.tiles = [][2]int{{8, 9}}
+		return s
+	}
+
+	s := fall(game.DefaultTuning())
+	s.step()
+	if !s.ctrl.Body.OnGround || s.ctrl.Body.PosY+s.ctrl.Body.H != 144 {
+		t.Errorf("player at y %.1f (grounded %v), want lifted onto the step at 144", s.ctrl.Body.PosY+s.ctrl.Body.H, s.ctrl.Body.OnGround)
+	}
+
+	tuning := game.DefaultTuning()
+	tuning.Jump.LedgeForgiveness = 0
+	s = fall(tuning)
+	for i := 0; i < 60; i++ {
+		s.step()
+	}
+	if s.ctrl.Body.PosY+s.ctrl.Body.H != floorY || s.ctrl.Body.PosX > 112 {
+		t.Errorf("player at %.1f, %.1f without forgiveness, want on the floor left of the step", s.ctrl.Body.PosX, s.ctrl.Body.PosY+s.ctrl.Body.H)
+	}
+}
+
+func TestStepUp(t *testing.T) {
+	walk := func() *input.Script {
+		return input.NewScript().Hold(input.ActionMoveRight, 0, 40)
+	}
+
+	// Walking into a 3px slab: the solids are resolved after the tiles, like
+	// the game's scenes
+	slab := []AABB{{X: 120, Y: floorY - 3, W: 200, H: 3}}
+	onSlab := func(tuning game.Tuning) *scenario {
+		s := newScenario(tuning, walk(), 0)
+		for i := 0; i < 40; i++ {
+			s.step()
+			s.ctrl.StepOntoSolids(slab)
+			ResolveSolids(s.ctrl.Body, slab)
+		}
+		return s
+	}
+
+	s := onSlab(game.DefaultTuning())
+	if b := s.ctrl.Body; b.PosY+b.H != floorY-3 || b.PosX <= 120 {
+		t.Errorf("player at %.1f, %.1f, want walked onto the slab at %d", b.PosX, b.PosY+b.H, floorY-3)
+	}
+	short := game.DefaultTuning()
+	short.Horizontal.MaxStepHeight = 2
+	s = onSlab(short)
+	if b := s.ctrl.Body; b.PosY+b.H != floorY || b.PosX != 104 {
+		t.Errorf("player at %.1f, %.1f with 2px steps, want stopped at the slab at 104, %d", b.PosX, b.PosY+b.H, floorY)
+	}
+
+	// Walking into a 16px tile step: the highest the feet got
+	climb := func(tuning game.Tuning) float64 {
+		s := newScenario(tuning, walk(), 0)
+		s.tiles = [][2]int{{8, 9}}
+		top := s.ctrl.Body.PosY + s.ctrl.Body.H
+		for i := 0; i < 40; i++ {
+			s.step()
+			top = min(top, s.ctrl.Body.PosY+s.ctrl.Body.H)
+		}
+		return top
+	}
+
+	if top := climb(game.DefaultTuning()); top != floorY {
+		t.Errorf("feet got to %.1f walking into a 16px step, want %d", top, floorY)
+	}
+	tall := game.DefaultTuning()
+	tall.Horizontal.MaxStepHeight = 16
+	if top := climb(tall); top != floorY-16 {
+		t.Errorf("feet got to %.1f walking into a 16px step with 16px steps, want %d", top, floorY-16)
+	}
+}
+
+func TestCrouch(t *testing.T) {
+	tuning := game.DefaultTuning()
+
+	// A 16px high gap under a ceiling from x 128 to 208
+	tunnel := func(script *input.Script) *scenario {
+		s := newScenario(tuning, script, 0)
+		for x := 8; x <= 12; x++ {
+			s.tiles = append(s.tiles, [2]int{x, 8})
+		}
+		return s
+	}
+
+	// Standing, the player doesn't fit
+	s := tunnel(input.NewScript().Hold(input.ActionMoveRight, 0, 60))
+	for i := 0; i < 60; i++ {
+		s.step()
+	}
+	if s.ctrl.Body.PosX != 112 {
+		t.Errorf("standing player at x %.1f, want stopped at the gap at 112", s.ctrl.Body.PosX)
+	}
+
+	// Crouched they crawl in, feet in place
+	s = tunnel(input.NewScript().Hold(input.ActionMoveRight, 0, 200).Hold(input.ActionCrouch, 0, 60))
+	s.step()
+	if b := s.ctrl.Body; !s.ctrl.State.Crouching || b.H != 16 || b.PosY+b.H != floorY {
+		t.Errorf("crouched body is %.0fpx high with feet at %.1f, want 16px with feet at %d", b.H, b.PosY+b.H, floorY)
+	}
+	topSpeed := 0.0
+	for i := 1; i < 60; i++ {
+		s.step()
+		topSpeed = max(topSpeed, s.ctrl.Body.VelX)
+	}
+	if want := tuning.Horizontal.MaxSpeed * tuning.Crouch.SpeedMult; topSpeed > want {
+		t.Errorf("crawled at %.1f, want at most %.1f", topSpeed, want)
+	}
+	if x := s.ctrl.Body.PosX; x < 128 || x > 192 {
+		t.Fatalf("crouched player at x %.1f, want in the gap", x)
+	}
+
+	// Releasing crouch under the ceiling keeps them crouched until they're out
+	s.step()
+	if !s.ctrl.State.Crouching {
+		t.Error("player stood up under the ceiling")
+	}
+	for i := 61; i < 200; i++ {
+		s.step()
+	}
+	if b := s.ctrl.Body; s.ctrl.State.Crouching || b.H != 32 || b.PosY+b.H != floorY || b.PosX < 208 {
+		t.Errorf("player at x %.1f, %.0fpx high (crouching %v), want standing past the gap", b.PosX, b.H, s.ctrl.State.Crouching)
+	}
+}
+
+func TestForceZones(t *testing.T) {
+	held := input.NewScript().Hold(input.ActionJump, 0, 120)
+	rise := func(zones ...ForceZone) float64 {
+		s := newScenario(game.DefaultTuning(), held, 0)
+		s.ctrl.Zones = zones
+		startY, minY := s.ctrl.Body.PosY, s.ctrl.Body.PosY
+		for i := 0; i < 120; i++ {
+			s.step()
+			minY = min(minY, s.ctrl.Body.PosY)
+		}
+		return startY - minY
+	}
+
+	everywhere := AABB{X: -1000, Y: -1000, W: 2000, H: 2000}
+	normal := rise()
+	if low := rise(ForceZone{Area: everywhere, GravityScale: 0.5}); low < normal*1.8 {
+		t.Errorf("jump in half gravity rose %.1fpx, want about twice the normal %.1fpx", low, normal)
+	}
+	if elsewhere := rise(ForceZone{Area: AABB{X: 500, Y: 0, W: 10, H: 10}, GravityScale: 0.5}); elsewhere != normal {
+		t.Errorf("jump next to a zone rose %.1fpx, want the normal %.1fpx", elsewhere, normal)
+	}
+
+	// An updraft stronger than gravity lifts a standing player
+	s := newScenario(game.DefaultTuning(), input.NewScript(), 0)
+	s.ctrl.Zones = []ForceZone{{Area: everywhere, GravityScale: 1, WindY: -1200}}
+	s.step()
+	if s.ctrl.Body.VelY >= 0 || s.ctrl.Body.OnGround {
+		t.Errorf("player in an updraft has VelY %.1f (grounded %v), want rising", s.ctrl.Body.VelY, s.ctrl.Body.OnGround)
+	}
+
+	// Wind pushes sideways
+	s = newScenario(game.DefaultTuning(), input.NewScript(), 40)
+	s.ctrl.Zones = []ForceZone{{Area: everywhere, GravityScale: 1, WindX: 600}}
+	startX := s.ctrl.Body.PosX
+	for i := 0; i < 20; i++ {
+		s.step()
+	}
+	if s.ctrl.Body.PosX <= startX {
+		t.Errorf("player in wind moved from x %.1f to %.1f, want pushed right", startX, s.ctrl.Body.PosX)
+	}
+}
+
+func TestFixedUpdateDoesNotAllocate(t *testing.T) {
+	// Walking into a wall, which tries to step up it every tick
+	s := newScenario(game.DefaultTuning(), input.NewScript().Hold(input.ActionMoveRight, 0, 1000), 0)
+	s.tiles = [][2]int{{8, 7}, {8, 8}, {8, 9}}
+	for i := 0; i < 60; i++ {
+		s.step()
+	}
+	if b := s.ctrl.Body; b.PosX+b.W != 128 {
+		t.Fatalf("player right edge at %.1f, want against the wall at 128", b.PosX+b.W)
+	}
+
+	// The input isn't advanced: move right stays held
+	if allocs := testing.AllocsPerRun(100, func() {
+		s.collisions.Reset()
+		s.ctrl.FixedUpdate(tick, s.inp, s.collide)
+	}); allocs != 0 {
+		t.Errorf("a fixed update allocated %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,37 @@
+package physics
+
+// ForceZone is an area that changes the forces on the player inside it: a
+// low-gravity room, or wind such as an updraft. Set the level's zones on
+// Controller.Zones.
+type ForceZone struct {
+	Area AABB
+
+	// GravityScale multiplies gravity inside the zone.
+	// 1 = unchanged, 0 = weightless, negative = pulls up.
+	GravityScale float64
+
+	// WindX and WindY are a constant acceleration inside the zone
+	// (pixels/second², negative Y = up).
+	WindX, WindY float64
+}
+
+// Contains returns true if the point is inside the zone.
+func (z ForceZone) Contains(x, y float64) bool {
+	return x >= z.Area.X && x < z.Area.X+z.Area.W && y >= z.Area.Y && y < z.Area.Y+z.Area.H
+}
+
+// forcesAt combines the zones containing the point: their gravity scales
+// multiply and their winds add up. Outside every zone the scale is 1.
+func forcesAt(zones []ForceZone, x, y float64) (gravityScale, windX, windY float64, inside bool) {
+	gravityScale = 1
+	for _, z := range zones {
+		if !z.Contains(x, y) {
+			continue
+		}
+		gravityScale *= z.GravityScale
+		windX += z.WindX
+		windY += z.WindY
+		inside = true
+	}
+	return gravityScale, windX, windY, inside
+}
@@ -0,0 +1,101 @@
+//go:build display
+
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Test helpers for geometry: collision maps drawn as text, random levels
+// and bodies, and checks of the invariants the collision code keeps.
+
+// testTile is the tile size of the test maps.
+const testTile = 16
+
+// gridMap builds a collision map from rows of text, one character per
+// tile: '#' is solid, anything else empty. Rows may differ in length; the
+// map is as wide as the longest.
+func gridMap(rows ...string) *world.CollisionMap {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	grid := world.NewSolidGrid(width, len(rows))
+	for ty, row := range rows {
+		for tx, ch := range row {
+			grid.SetSolid(tx, ty, ch == '#')
+		}
+	}
+	return world.NewCollisionMap(grid, testTile, testTile)
+}
+
+// randomMap returns a w×h tile map with about density of its tiles solid.
+func randomMap(r *rand.Rand, w, h int, density float64) *world.CollisionMap {
+	rows := make([]string, h)
+	for ty := range rows {
+		var sb strings.Builder
+		for tx := 0; tx < w; tx++ {
+			if r.Float64() < density {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		rows[ty] = sb.String()
+	}
+	return gridMap(rows...)
+}
+
+// randomFreeBody returns a body of a random size between 4 and 32px at a
+// random position on cm that doesn't overlap a solid tile, or nil if none
+// was found.
+func randomFreeBody(r *rand.Rand, cm *world.CollisionMap) *Body {
+	mapW := float64(cm.Grid().Width() * testTile)
+	mapH := float64(cm.Grid().Height() * testTile)
+	for tries := 0; tries < 100; tries++ {
+		b := &Body{W: 4 + r.Float64()*28, H: 4 + r.Float64()*28}
+		b.PosX = r.Float64() * (mapW - b.W)
+		b.PosY = r.Float64() * (mapH - b.H)
+		if !insideSolid(cm, b) {
+			return b
+		}
+	}
+	return nil
+}
+
+// insideSolid reports whether b overlaps a solid tile of cm. Touching a
+// tile's edge isn't overlapping.
+func insideSolid(cm *world.CollisionMap, b *Body) bool {
+	return cm.OverlapsSolid(b.PosX, b.PosY, b.W, b.H)
+}
+
+// finite reports whether none of b's position and velocity is NaN or
+// infinite.
+func finite(b *Body) bool {
+	for _, v := range []float64{b.PosX, b.PosY, b.VelX, b.VelY} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameBody reports whether a and b have exactly the same position,
+// velocity, size and ground state.
+func sameBody(a, b *Body) bool {
+	return a.PosX == b.PosX && a.PosY == b.PosY && a.VelX == b.VelX && a.VelY == b.VelY &&
+		a.W == b.W && a.H == b.H && a.OnGround == b.OnGround
+}
+
+// checkOutsideSolids fails t if b overlaps a solid tile of cm.
+func checkOutsideSolids(t *testing.T, cm *world.CollisionMap, b *Body, context string) {
+	t.Helper()
+	if insideSolid(cm, b) {
+		t.Errorf("%s: body %vx%v at (%v, %v) is inside a solid tile", context, b.W, b.H, b.PosX, b.PosY)
+	}
+}
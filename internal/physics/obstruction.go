@@ -0,0 +1,46 @@
+package physics
+
+import "math"
+
+// PushOut moves body out of solid along the direction needing the smallest
+// move. blocked reports whether a candidate position overlaps other geometry
+// and may be nil. Returns false and leaves body unchanged if body doesn't
+// overlap solid or every direction is blocked.
+func PushOut(body *Body, solid AABB, blocked func(AABB) bool) bool {
+	if !body.AABB().Intersects(solid) {
+		return false
+	}
+
+	type candidate struct {
+		x, y float64
+		dist float64
+	}
+	// Left, right, up, down; ties keep this order. An array so PushOut
+	// doesn't allocate.
+	candidates := [...]candidate{
+		{x: solid.Left() - body.W, y: body.PosY},
+		{x: solid.Right(), y: body.PosY},
+		{x: body.PosX, y: solid.Top() - body.H},
+		{x: body.PosX, y: solid.Bottom()},
+	}
+	for i := range candidates {
+		c := &candidates[i]
+		c.dist = math.Abs(c.x-body.PosX) + math.Abs(c.y-body.PosY)
+	}
+	// Insertion sort, which is stable
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && candidates[j].dist < candidates[j-1].dist; j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+
+	for _, c := range candidates {
+		if blocked != nil && blocked(AABB{X: c.x, Y: c.y, W: body.W, H: body.H}) {
+			continue
+		}
+		body.PosX = c.x
+		body.PosY = c.y
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+//go:build display
+
+package physics
+
+import "testing"
+
+func TestPushOutNearestSide(t *testing.T) {
+	// 16px wide door; player mostly on its right half
+	door := AABB{X: 100, Y: 0, W: 16, H: 64}
+	body := &Body{PosX: 110, PosY: 40, W: 12, H: 12}
+
+	if !PushOut(body, door, nil) {
+		t.Fatal("PushOut returned false for an overlapping body")
+	}
+	if body.PosX != 116 || body.PosY != 40 {
+		t.Errorf("body at (%v, %v), want (116, 40)", body.PosX, body.PosY)
+	}
+	if body.AABB().Intersects(door) {
+		t.Error("body still overlaps the door")
+	}
+}
+
+func TestPushOutSkipsBlockedSide(t *testing.T) {
+	door := AABB{X: 100, Y: 0, W: 16, H: 64}
+	body := &Body{PosX: 110, PosY: 40, W: 12, H: 12}
+	wallRight := AABB{X: 116, Y: 0, W: 16, H: 64}
+
+	blocked := func(a AABB) bool { return a.Intersects(wallRight) }
+	if !PushOut(body, door, blocked) {
+		t.Fatal("PushOut returned false with the left side free")
+	}
+	if body.PosX != 88 {
+		t.Errorf("body X = %v, want 88 (pushed left)", body.PosX)
+	}
+}
+
+func TestPushOutAllBlocked(t *testing.T) {
+	door := AABB{X: 100, Y: 0, W: 16, H: 64}
+	body := &Body{PosX: 102, PosY: 20, W: 12, H: 12}
+
+	if PushOut(body, door, func(AABB) bool { return true }) {
+		t.Error("PushOut returned true with every side blocked")
+	}
+	if body.PosX != 102 || body.PosY != 20 {
+		t.Errorf("body moved to (%v, %v), want unchanged", body.PosX, body.PosY)
+	}
+}
+
+func TestPushOutNoOverlap(t *testing.T) {
+	door := AABB{X: 100, Y: 0, W: 16, H: 64}
+	body := &Body{PosX: 50, PosY: 20, W: 12, H: 12}
+
+	if PushOut(body, door, nil) {
+		t.Error("PushOut returned true for a body outside the door")
+	}
+}
+
+func TestPushOutDoesNotAllocate(t *testing.T) {
+	door := AABB{X: 100, Y: 0, W: 16, H: 64}
+	wallRight := AABB{X: 116, Y: 0, W: 16, H: 64}
+	blocked := func(a AABB) bool { return a.Intersects(wallRight) }
+	if allocs := testing.AllocsPerRun(100, func() {
+		PushOut(&Body{PosX: 110, PosY: 40, W: 12, H: 12}, door, blocked)
+	}); allocs != 0 {
+		t.Errorf("PushOut allocated %v times, want 0", allocs)
+	}
+}
+
+func TestPushOutTiesKeepOrder(t *testing.T) {
+	// Centered in a square: every side is as far, left comes first
+	solid := AABB{X: 0, Y: 0, W: 32, H: 32}
+	body := &Body{PosX: 8, PosY: 8, W: 16, H: 16}
+	if !PushOut(body, solid, nil) || body.PosX != -16 || body.PosY != 8 {
+		t.Errorf("body at (%v, %v), want pushed left to (-16, 8)", body.PosX, body.PosY)
+	}
+}
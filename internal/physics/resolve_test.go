@@ -0,0 +1,201 @@
+//go:build display
+
+package physics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/torsten/GoP/internal/game"
+	"github.com/torsten/GoP/internal/input"
+)
+
+// resolveMap is a room with a floor, walls, a ledge and a pillar.
+var resolveMap = []string{
+	"##########",
+	"#........#",
+	"#....#...#",
+	"#..###...#",
+	"#........#",
+	"#.....#..#",
+	"##########",
+}
+
+func TestResolveCases(t *testing.T) {
+	cm := gridMap(resolveMap...)
+	r := NewCollisionResolver(testTile, testTile)
+
+	tests := []struct {
+		name         string
+		x, y, dx, dy float64
+		wantX, wantY float64
+		wantGround   bool
+	}{
+		{"free move", 24, 20, 3, 2, 27, 22, false},
+		{"land on floor", 20, 80, 0, 8, 20, 84, true},
+		{"walk into right wall", 140, 70, 6, 0, 132, 70, false},
+		{"walk into left wall", 18, 70, -6, 0, 16, 70, false},
+		{"hit ledge from below", 56, 67, 0, -6, 56, 64, false},
+		{"slide along floor into pillar", 80, 84, 8, 4, 84, 84, true},
+		{"exactly touching stays", 84, 84, 0, 0, 84, 84, false},
+	}
+	for _, tt := range tests {
+		b := &Body{PosX: tt.x, PosY: tt.y, W: 12, H: 12}
+		r.Resolve(b, cm, tt.dx, tt.dy)
+		if b.PosX != tt.wantX || b.PosY != tt.wantY || b.OnGround != tt.wantGround {
+			t.Errorf("%s: body at (%v, %v) grounded %v, want (%v, %v) grounded %v",
+				tt.name, b.PosX, b.PosY, b.OnGround, tt.wantX, tt.wantY, tt.wantGround)
+		}
+		checkOutsideSolids(t, cm, b, tt.name)
+	}
+}
+
+func TestResolveNeverEndsInSolid(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := NewCollisionResolver(testTile, testTile)
+
+	for level := 0; level < 50; level++ {
+		cm := randomMap(rng, 20, 15, 0.3)
+		for i := 0; i < 200; i++ {
+			b := randomFreeBody(rng, cm)
+			if b == nil {
+				continue
+			}
+			// Moves below a tile per step, as at the game's top speeds
+			dx := (rng.Float64()*2 - 1) * (testTile - 0.01)
+			dy := (rng.Float64()*2 - 1) * (testTile - 0.01)
+			start := *b
+			r.Resolve(b, cm, dx, dy)
+			checkOutsideSolids(t, cm, b, fmt.Sprintf("level %d: %vx%v from (%v, %v) by (%v, %v)",
+				level, start.W, start.H, start.PosX, start.PosY, dx, dy))
+		}
+	}
+}
+
+func TestControllerNeverEndsInSolid(t *testing.T) {
+	cm := gridMap(resolveMap...)
+	r := NewCollisionResolver(testTile, testTile)
+
+	// Run and jump around the room in both directions for 10 seconds
+	script := input.NewScript()
+	for i := 0; i < 10; i++ {
+		dir := input.ActionMoveRight
+		if i%2 == 1 {
+			dir = input.ActionMoveLeft
+		}
+		script.Hold(dir, i*60, 60).Hold(input.ActionJump, i*60+10, 20)
+	}
+	inp := input.NewScriptedInput(script)
+	ctrl := NewController(&Body{PosX: 20, PosY: 70, W: 12, H: 24}, game.DefaultTuning())
+
+	for i := 0; i < 600; i++ {
+		ctrl.Update(inp, cm, r, tick.Seconds())
+		inp.Update()
+		checkOutsideSolids(t, cm, ctrl.Body, fmt.Sprintf("tick %d", i))
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func TestResolveDeterministic(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	cm := randomMap(rng, 20, 15, 0.3)
+	r := NewCollisionResolver(testTile, testTile)
+
+	for i := 0; i < 200; i++ {
+		b := randomFreeBody(rng, cm)
+		if b == nil {
+			continue
+		}
+		dx, dy := rng.Float64()*30-15, rng.Float64()*30-15
+		first := *b
+		r.Resolve(&first, cm, dx, dy)
+		for run := 0; run < 3; run++ {
+			again := *b
+			r.Resolve(&again, cm, dx, dy)
+			if !sameBody(&first, &again) {
+				t.Fatalf("Resolve from %+v by (%v, %v) gave %+v, then %+v", *b, dx, dy, first, again)
+			}
+		}
+	}
+}
+
+func TestResolveSolidsOrder(t *testing.T) {
+	// Solids resolve one after the other in the order given, so a player
+	// overlapping a floor and a wall ends up the same way every time, and
+	// the same as resolving them one by one
+	floor := AABB{X: 0, Y: 100, W: 200, H: 16}
+	wall := AABB{X: 120, Y: 0, W: 16, H: 116}
+	start := Body{PosX: 112, PosY: 90, W: 12, H: 12}
+
+	for _, solids := range [][]AABB{{floor, wall}, {wall, floor}} {
+		want := start
+		for _, s := range solids {
+			ResolveSolid(&want, s)
+		}
+		for run := 0; run < 3; run++ {
+			got := start
+			ResolveSolids(&got, solids)
+			if !sameBody(&got, &want) {
+				t.Errorf("ResolveSolids(%v) = %+v, want %+v as resolved one by one", solids, got, want)
+			}
+		}
+	}
+}
+
+func FuzzResolveSolidsFinite(f *testing.F) {
+	f.Add(110.0, 40.0, 12.0, 12.0, 100.0, 0.0, 16.0, 64.0)
+	f.Add(0.0, 0.0, 16.0, 32.0, 0.0, 0.0, 16.0, 32.0) // Exactly on top of each other
+	f.Add(-5.5, 1e6, 0.5, 0.5, -6.0, 1e6-0.25, 1.0, 1.0)
+	f.Add(10.0, 10.0, 12.0, 12.0, 30.0, 30.0, 16.0, 16.0) // Apart
+
+	f.Fuzz(func(t *testing.T, px, py, pw, ph, sx, sy, sw, sh float64) {
+		for _, v := range []float64{px, py, pw, ph, sx, sy, sw, sh} {
+			// Positions and sizes a level can have
+			if math.IsNaN(v) || math.Abs(v) > 1e7 {
+				t.Skip()
+			}
+		}
+		if pw <= 0 || ph <= 0 || sw <= 0 || sh <= 0 {
+			t.Skip()
+		}
+
+		b := &Body{PosX: px, PosY: py, W: pw, H: ph}
+		solids := []AABB{{X: sx, Y: sy, W: sw, H: sh}, {X: sx + sw, Y: sy, W: sw, H: sh}}
+		ResolveSolids(b, solids)
+		if !finite(b) {
+			t.Errorf("ResolveSolids moved the body to (%v, %v), velocity (%v, %v)", b.PosX, b.PosY, b.VelX, b.VelY)
+		}
+	})
+}
+
+func FuzzResolveNeverEndsInSolid(f *testing.F) {
+	f.Add(24.0, 20.0, 3.0, 2.0)
+	f.Add(140.0, 70.0, 15.9, 0.0)
+	f.Add(56.0, 67.0, -4.0, -15.9)
+	f.Add(84.0, 84.0, 0.0, 0.0)
+
+	cm := gridMap(resolveMap...)
+	r := NewCollisionResolver(testTile, testTile)
+	f.Fuzz(func(t *testing.T, x, y, dx, dy float64) {
+		if math.IsNaN(x) || math.IsNaN(y) || math.Abs(x) > 1e6 || math.Abs(y) > 1e6 {
+			t.Skip()
+		}
+		// Below a tile per step; faster bodies can tunnel
+		if !(math.Abs(dx) < testTile) || !(math.Abs(dy) < testTile) {
+			t.Skip()
+		}
+		b := &Body{PosX: x, PosY: y, W: 12, H: 12}
+		if insideSolid(cm, b) {
+			t.Skip()
+		}
+		r.Resolve(b, cm, dx, dy)
+		checkOutsideSolids(t, cm, b, fmt.Sprintf("from (%v, %v) by (%v, %v)", x, y, dx, dy))
+		if !finite(b) {
+			t.Errorf("Resolve moved the body to (%v, %v)", b.PosX, b.PosY)
+		}
+	})
+}
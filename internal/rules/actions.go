@@ -2,7 +2,7 @@ package rules
 
 import (
 	"fmt"
-	"log"
+	"strings"
 )
 
 // Action type constants.
@@ -12,38 +12,98 @@ const (
 	ActionToggle     = "toggle"
 )
 
-// ExecuteAction executes a single action spec.
+// ExecuteAction executes a single action spec on each of its targets.
 func ExecuteAction(ctx ActionContext, spec ActionSpec) error {
 	if ctx.Resolver == nil {
 		return fmt.Errorf("no resolver in action context")
 	}
 
-	target := ctx.Resolver.Resolve(spec.Target)
-	if target == nil {
-		return fmt.Errorf("target not found: %s", spec.Target)
+	targets, err := resolveTargets(ctx.Resolver, spec)
+	if err != nil {
+		return err
 	}
 
+	var apply func(Targetable)
 	switch spec.Type {
 	case ActionActivate:
-		target.Activate()
-		return nil
+		apply = Targetable.Activate
 	case ActionDeactivate:
-		target.Deactivate()
-		return nil
+		apply = Targetable.Deactivate
 	case ActionToggle:
-		target.Toggle()
-		return nil
+		apply = Targetable.Toggle
 	default:
 		return fmt.Errorf("unknown action type: %s", spec.Type)
 	}
+	for _, target := range targets {
+		apply(target)
+	}
+	return nil
+}
+
+// resolveTargets returns the targets of an action: its target ID or the IDs
+// matching its target pattern, plus the members of its group. A target in
+// both is only returned once, so a toggle doesn't cancel itself out.
+func resolveTargets(resolver TargetResolver, spec ActionSpec) ([]Targetable, error) {
+	if spec.Target == "" && spec.Group == "" {
+		return nil, fmt.Errorf("action has no target or group")
+	}
+	group, canGroup := resolver.(GroupResolver)
+
+	var targets []Targetable
+	switch {
+	case isPattern(spec.Target):
+		if !canGroup {
+			return nil, fmt.Errorf("resolver does not support target patterns: %s", spec.Target)
+		}
+		targets = group.ResolvePattern(spec.Target)
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("no targets match: %s", spec.Target)
+		}
+	case spec.Target != "":
+		target := resolver.Resolve(spec.Target)
+		if target == nil {
+			return nil, fmt.Errorf("target not found: %s", spec.Target)
+		}
+		targets = append(targets, target)
+	}
+
+	if spec.Group != "" {
+		if !canGroup {
+			return nil, fmt.Errorf("resolver does not support groups: %s", spec.Group)
+		}
+		members := group.ResolveGroup(spec.Group)
+		if len(members) == 0 {
+			return nil, fmt.Errorf("no targets in group: %s", spec.Group)
+		}
+		seen := make(map[string]bool, len(targets))
+		for _, t := range targets {
+			seen[t.TargetID()] = true
+		}
+		for _, t := range members {
+			if !seen[t.TargetID()] {
+				targets = append(targets, t)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// isPattern returns whether a target ID contains wildcards.
+func isPattern(id string) bool {
+	return strings.ContainsAny(id, "*?[")
 }
 
 // ExecuteActions executes multiple actions in sequence.
 // If an action fails, it logs the error and continues with the next action.
-func ExecuteActions(ctx ActionContext, specs []ActionSpec) {
+// Returns the outcome of each action, in order.
+func ExecuteActions(ctx ActionContext, specs []ActionSpec) []ActionResult {
+	results := make([]ActionResult, 0, len(specs))
 	for _, spec := range specs {
-		if err := ExecuteAction(ctx, spec); err != nil {
-			log.Printf("[rules] action failed: %v (target=%s, type=%s)", err, spec.Target, spec.Type)
+		err := ExecuteAction(ctx, spec)
+		if err != nil {
+			logger.Warnf("action failed: %v (target=%s, type=%s)", err, spec.TargetLabel(), spec.Type)
 		}
+		results = append(results, ActionResult{Spec: spec, Err: err})
 	}
+	return results
 }
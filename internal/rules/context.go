@@ -9,6 +9,13 @@ type Targetable interface {
 	TargetID() string
 }
 
+// StateReporter is implemented by targets whose state rules can check with
+// when.states, e.g. a door reporting "closed", "opening", "open", or
+// "closing".
+type StateReporter interface {
+	TargetState() string
+}
+
 // TargetResolver resolves target IDs to Targetable entities.
 // This interface is implemented by entities.TargetRegistry.
 type TargetResolver interface {
@@ -16,6 +23,15 @@ type TargetResolver interface {
 	Resolve(id string) Targetable
 }
 
+// GroupResolver is implemented by TargetResolvers that can also look up
+// several targets at once, for actions with a group or a wildcard target.
+type GroupResolver interface {
+	// ResolveGroup returns the targets tagged with group
+	ResolveGroup(group string) []Targetable
+	// ResolvePattern returns the targets whose ID matches pattern
+	ResolvePattern(pattern string) []Targetable
+}
+
 // ActionContext provides context for action execution.
 type ActionContext struct {
 	// Event is the event that triggered this action
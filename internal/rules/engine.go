@@ -2,26 +2,61 @@ package rules
 
 import (
 	"fmt"
-	"log"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/torsten/GoP/internal/logging"
 )
 
+// logger logs rule firings and failed actions.
+var logger = logging.New("rules")
+
+// maxChainedEvents caps the events one ProcessEvent or Update handles, so
+// rules that keep triggering each other (a toggle on var_changed of the
+// variable it changes) can't hang the game. Events past the cap stay queued
+// for the next Update.
+const maxChainedEvents = 256
+
 // Engine processes events and executes matching rules.
+//
+// The engine belongs to the game loop: loading rules, ProcessEvent and
+// Update must be called from one goroutine. Post is safe from any
+// goroutine; posted events wait in a queue until the next Update. Events
+// raised while a rule's actions run (a door's state change, a variable set
+// by an action) are queued too and handled after the current event, so the
+// rules never see the engine change under them.
 type Engine struct {
 	rules    []Rule
 	resolver TargetResolver
+	vars     Variables       // Optional; read by when.vars conditions
 	fired    map[string]bool // Tracks which "once" rules have fired
+	tracer   *Tracer         // Optional tracer for debugging rule firings
+
+	// For cooldowns and max_fires, by rule ID
+	now       time.Duration            // Engine time, advanced by Update
+	fireCount map[string]int           // Times each rule fired
+	lastFired map[string]time.Duration // Engine time each rule last fired
+
+	mu         sync.Mutex // Guards queue
+	queue      []Event    // Posted events waiting to be processed
+	processing bool       // Inside ProcessEvent or Update
 }
 
 // NewEngine creates a new rule engine with the given target resolver.
 func NewEngine(resolver TargetResolver) *Engine {
 	return &Engine{
-		rules:    make([]Rule, 0),
-		resolver: resolver,
-		fired:    make(map[string]bool),
+		rules:     make([]Rule, 0),
+		resolver:  resolver,
+		fired:     make(map[string]bool),
+		fireCount: make(map[string]int),
+		lastFired: make(map[string]time.Duration),
 	}
 }
 
-// LoadRules adds rules to the engine.
+// LoadRules adds rules to the engine and sorts all rules by priority,
+// keeping the load order among equal priorities.
 func (e *Engine) LoadRules(rules []Rule) {
 	for i := range rules {
 		// Default to active if not specified
@@ -30,6 +65,9 @@ func (e *Engine) LoadRules(rules []Rule) {
 		}
 	}
 	e.rules = append(e.rules, rules...)
+	sort.SliceStable(e.rules, func(i, j int) bool {
+		return e.rules[i].Priority > e.rules[j].Priority
+	})
 }
 
 // LoadRuleSet loads all rules from a rule set.
@@ -37,15 +75,155 @@ func (e *Engine) LoadRuleSet(ruleSet RuleSet) {
 	e.LoadRules(ruleSet.Rules)
 }
 
-// Clear removes all rules from the engine.
+// Reload replaces the engine's rules with ruleSet, keeping what the engine
+// remembers of firings (once, cooldown and max_fires) for the rules whose
+// IDs are still there, so rules can be edited while a level is played
+// without resetting its progress. Queued events and the engine's time are
+// kept too.
+func (e *Engine) Reload(ruleSet RuleSet) {
+	keep := make(map[string]bool, len(ruleSet.Rules))
+	for _, r := range ruleSet.Rules {
+		if r.ID != "" {
+			keep[r.ID] = true
+		}
+	}
+	for id := range e.fired {
+		if !keep[id] {
+			delete(e.fired, id)
+		}
+	}
+	for id := range e.fireCount {
+		if !keep[id] {
+			delete(e.fireCount, id)
+			delete(e.lastFired, id)
+		}
+	}
+
+	e.rules = make([]Rule, 0, len(ruleSet.Rules))
+	e.LoadRuleSet(ruleSet)
+}
+
+// Clear removes all rules and queued events from the engine and resets
+// what it remembers of firings and its time.
 func (e *Engine) Clear() {
 	e.rules = make([]Rule, 0)
 	e.fired = make(map[string]bool)
+	e.fireCount = make(map[string]int)
+	e.lastFired = make(map[string]time.Duration)
+	e.now = 0
+	e.mu.Lock()
+	e.queue = nil
+	e.mu.Unlock()
+}
+
+// EngineSnapshot is the saved firing state of an Engine: which once rules
+// fired, how often each rule fired and when, and the engine's time.
+type EngineSnapshot struct {
+	now       time.Duration
+	fired     map[string]bool
+	fireCount map[string]int
+	lastFired map[string]time.Duration
+}
+
+// Snapshot saves what the engine remembers of firings, for going back to a
+// checkpoint. Rules and queued events aren't part of it.
+func (e *Engine) Snapshot() EngineSnapshot {
+	return EngineSnapshot{
+		now:       e.now,
+		fired:     maps.Clone(e.fired),
+		fireCount: maps.Clone(e.fireCount),
+		lastFired: maps.Clone(e.lastFired),
+	}
+}
+
+// Restore puts back the firing state saved by Snapshot, so once, cooldown
+// and max_fires rules fired since can fire again.
+func (e *Engine) Restore(s EngineSnapshot) {
+	e.now = s.now
+	e.fired = maps.Clone(s.fired)
+	e.fireCount = maps.Clone(s.fireCount)
+	e.lastFired = maps.Clone(s.lastFired)
+	if e.fired == nil {
+		e.fired = make(map[string]bool)
+	}
+	if e.fireCount == nil {
+		e.fireCount = make(map[string]int)
+	}
+	if e.lastFired == nil {
+		e.lastFired = make(map[string]time.Duration)
+	}
+}
+
+// Post queues an event for the next Update. It is safe to call from any
+// goroutine.
+func (e *Engine) Post(event Event) {
+	e.mu.Lock()
+	e.queue = append(e.queue, event)
+	e.mu.Unlock()
+}
+
+// Pending returns the number of queued events.
+func (e *Engine) Pending() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.queue)
 }
 
-// ProcessEvent checks all rules against the event and executes matching actions.
+// Update advances the engine's time by dt, for cooldowns, and processes
+// the queued events in the order they were posted, including those raised
+// while processing them, up to maxChainedEvents. Returns the number
+// processed. Call it once per tick.
+func (e *Engine) Update(dt time.Duration) int {
+	e.now += dt
+	if e.processing {
+		return 0
+	}
+	e.processing = true
+	defer func() { e.processing = false }()
+	return e.drain(maxChainedEvents)
+}
+
+// ProcessEvent checks all rules against the event and executes matching
+// actions right away, then processes the events that raised (see Update).
+// Called while another event is being processed, from a rule's actions, it
+// queues the event behind the current one instead.
 func (e *Engine) ProcessEvent(event Event) {
+	if e.processing {
+		e.Post(event)
+		return
+	}
+	e.processing = true
+	defer func() { e.processing = false }()
+	e.process(event)
+	e.drain(maxChainedEvents - 1)
+}
+
+// drain processes up to limit queued events and returns how many it did.
+func (e *Engine) drain(limit int) int {
+	n := 0
+	for ; n < limit; n++ {
+		e.mu.Lock()
+		if len(e.queue) == 0 {
+			e.mu.Unlock()
+			return n
+		}
+		event := e.queue[0]
+		e.queue = e.queue[1:]
+		e.mu.Unlock()
+		e.process(event)
+	}
+	if left := e.Pending(); left > 0 {
+		logger.Warnf("%d events chained from one update; %d left for the next one (rules triggering each other?)", limit, left)
+	}
+	return n
+}
+
+// process checks all rules against the event, in priority order, and
+// executes matching actions. Of the rules sharing an exclusive group only
+// the first that matches fires.
+func (e *Engine) process(event Event) {
 	ctx := NewActionContext(event, e.resolver)
+	var groupsFired map[string]bool
 
 	for i := range e.rules {
 		rule := &e.rules[i]
@@ -60,20 +238,115 @@ func (e *Engine) ProcessEvent(event Event) {
 			continue
 		}
 
+		// Check target states
+		if !e.statesMatch(rule) {
+			continue
+		}
+
+		// Check gameplay variables
+		if !e.varsMatch(rule) {
+			continue
+		}
+
 		// Check if this is a "once" rule that already fired
 		if rule.Once && e.fired[rule.ID] {
 			continue
 		}
 
+		// Check max_fires and cooldown
+		if !e.canFireAgain(rule) {
+			continue
+		}
+
+		// Check if another rule of its exclusive group fired for this event
+		if rule.ExclusiveGroup != "" {
+			if groupsFired[rule.ExclusiveGroup] {
+				continue
+			}
+			if groupsFired == nil {
+				groupsFired = make(map[string]bool)
+			}
+			groupsFired[rule.ExclusiveGroup] = true
+		}
+
 		// Execute actions
-		log.Printf("[rules] rule '%s' triggered by event '%s' from '%s' (actor: %s)", rule.ID, event.Type, event.RegionID, event.ActorType)
-		ExecuteActions(ctx, rule.Actions)
+		logger.Debugf("rule '%s' triggered by event '%s' from '%s' (actor: %s)", rule.ID, event.Type, event.RegionID, event.ActorType)
+		results := ExecuteActions(ctx, rule.Actions)
+
+		// Record firing for debugging
+		if e.tracer != nil {
+			e.tracer.Record(Firing{RuleID: rule.ID, Event: event, Results: results})
+		}
 
 		// Mark as fired if once rule
 		if rule.Once {
 			e.fired[rule.ID] = true
 		}
+		e.fireCount[rule.ID]++
+		e.lastFired[rule.ID] = e.now
+	}
+}
+
+// canFireAgain returns whether the rule is below its max_fires and past
+// its cooldown.
+func (e *Engine) canFireAgain(rule *Rule) bool {
+	count := e.fireCount[rule.ID]
+	if rule.MaxFires > 0 && count >= rule.MaxFires {
+		return false
+	}
+	if rule.Cooldown > 0 && count > 0 && e.now-e.lastFired[rule.ID] < time.Duration(rule.Cooldown) {
+		return false
+	}
+	return true
+}
+
+// statesMatch returns whether every target in the rule's when.states is in
+// the required state. Missing targets and targets that don't report a state
+// never match.
+func (e *Engine) statesMatch(rule *Rule) bool {
+	for id, want := range rule.When.States {
+		var target Targetable
+		if e.resolver != nil {
+			target = e.resolver.Resolve(id)
+		}
+		reporter, ok := target.(StateReporter)
+		if !ok || reporter.TargetState() != want {
+			return false
+		}
+	}
+	return true
+}
+
+// varsMatch returns whether every variable in the rule's when.vars meets
+// its condition. Without Variables (see SetVariables), every variable is
+// unset.
+func (e *Engine) varsMatch(rule *Rule) bool {
+	for name, cond := range rule.When.Vars {
+		var value any
+		if e.vars != nil {
+			value, _ = e.vars.Get(name)
+		}
+		if !MatchVar(value, cond) {
+			return false
+		}
 	}
+	return true
+}
+
+// SetVariables sets the gameplay variables when.vars conditions read.
+func (e *Engine) SetVariables(v Variables) {
+	e.vars = v
+}
+
+// SetTracer attaches a tracer that records rule firings.
+// Pass nil to disable tracing.
+func (e *Engine) SetTracer(t *Tracer) {
+	e.tracer = t
+}
+
+// Tracer returns the attached tracer, or nil if tracing is disabled.
+func (e *Engine) Tracer() *Tracer {
+	return e.tracer
 }
 
 // Rules returns the current rules (for debugging).
@@ -1,7 +1,13 @@
 package rules
 
 import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 )
 
 // mockTargetable implements Targetable for testing.
@@ -10,6 +16,7 @@ type mockTargetable struct {
 	activated   bool
 	deactivated bool
 	toggled     int
+	state       string // Reported through TargetState
 }
 
 func (m *mockTargetable) Activate() {
@@ -28,6 +35,10 @@ func (m *mockTargetable) TargetID() string {
 	return m.id
 }
 
+func (m *mockTargetable) TargetState() string {
+	return m.state
+}
+
 // mockResolver implements TargetResolver for testing.
 type mockResolver struct {
 	targets map[string]*mockTargetable
@@ -176,6 +187,67 @@ rules:
 	}
 }
 
+func TestParse_PriorityAndExclusiveGroup(t *testing.T) {
+	yamlData := `
+rules:
+  - id: big_reward
+    priority: 10
+    exclusive_group: reward
+    when: {event: var_changed}
+    actions: [{type: activate, target: chest}]
+`
+	jsonData := `{"rules": [{"id": "big_reward", "priority": 10, "exclusive_group": "reward",
+		"when": {"event": "var_changed"}, "actions": [{"type": "activate", "target": "chest"}]}]}`
+
+	fromYAML, err := ParseYAML([]byte(yamlData))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %v", err)
+	}
+	fromJSON, err := ParseJSON([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	for name, set := range map[string]RuleSet{"YAML": fromYAML, "JSON": fromJSON} {
+		if r := set.Rules[0]; r.Priority != 10 || r.ExclusiveGroup != "reward" {
+			t.Errorf("%s: priority %d, exclusive group %q; want 10, reward", name, r.Priority, r.ExclusiveGroup)
+		}
+	}
+}
+
+func TestParse_CooldownAndMaxFires(t *testing.T) {
+	yamlData := `
+rules:
+  - id: trap
+    cooldown: 3s
+    max_fires: 5
+    when: {event: enter_region, region: spikes}
+    actions: [{type: activate, target: trap_1}]
+`
+	jsonData := `{"rules": [{"id": "trap", "cooldown": "3s", "max_fires": 5,
+		"when": {"event": "enter_region", "region": "spikes"}, "actions": [{"type": "activate", "target": "trap_1"}]}]}`
+
+	fromYAML, err := ParseYAML([]byte(yamlData))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %v", err)
+	}
+	fromJSON, err := ParseJSON([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	for name, set := range map[string]RuleSet{"YAML": fromYAML, "JSON": fromJSON} {
+		if r := set.Rules[0]; time.Duration(r.Cooldown) != 3*time.Second || r.MaxFires != 5 {
+			t.Errorf("%s: cooldown %v, max fires %d; want 3s, 5", name, time.Duration(r.Cooldown), r.MaxFires)
+		}
+	}
+
+	for _, bad := range []string{"cooldown: 3", "cooldown: soon", "cooldown: -1s"} {
+		data := "rules:\n  - id: trap\n    " + bad + "\n"
+		if _, err := ParseYAML([]byte(data)); err == nil {
+			t.Errorf("ParseYAML accepted %q", bad)
+		}
+	}
+}
+
 func TestParseJSON_InvalidJSON(t *testing.T) {
 	jsonData := `{invalid json}`
 
@@ -543,6 +615,355 @@ func TestProcessEvent_InactiveRuleDoesNotFire(t *testing.T) {
 	}
 }
 
+func TestProcessEvent_StatesMustMatch(t *testing.T) {
+	resolver := newMockResolver()
+	door := resolver.addTarget("door_1")
+	lamp := resolver.addTarget("lamp_1")
+
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{
+		{
+			ID: "when_closed",
+			When: WhenClause{
+				Event:  EventEnterRegion,
+				States: map[string]string{"door_1": "closed"},
+			},
+			Actions: []ActionSpec{
+				{Type: ActionToggle, Target: "lamp_1"},
+			},
+		},
+	})
+	event := NewEvent(EventEnterRegion, "trigger_1", "player")
+
+	door.state = "opening"
+	engine.ProcessEvent(event)
+	if lamp.toggled != 0 {
+		t.Fatal("rule fired while door_1 was opening")
+	}
+
+	door.state = "closed"
+	engine.ProcessEvent(event)
+	if lamp.toggled != 1 {
+		t.Errorf("expected 1 toggle once door_1 was closed, got %d", lamp.toggled)
+	}
+
+	// Missing targets never match
+	engine.LoadRules([]Rule{
+		{
+			ID:      "missing",
+			When:    WhenClause{Event: EventEnterRegion, States: map[string]string{"ghost": "closed"}},
+			Actions: []ActionSpec{{Type: ActionActivate, Target: "lamp_1"}},
+		},
+	})
+	engine.ProcessEvent(event)
+	if lamp.activated {
+		t.Error("rule fired with a state on a missing target")
+	}
+}
+
+// mockVars implements Variables for testing.
+type mockVars map[string]any
+
+func (v mockVars) Get(name string) (any, bool) {
+	value, ok := v[name]
+	return value, ok
+}
+
+func TestProcessEvent_VarsMustMatch(t *testing.T) {
+	resolver := newMockResolver()
+	door := resolver.addTarget("door_1")
+	vars := mockVars{}
+
+	engine := NewEngine(resolver)
+	engine.SetVariables(vars)
+	engine.LoadRules([]Rule{
+		{
+			ID:      "three_gems",
+			When:    WhenClause{Event: EventVarChanged, Region: "gems", Vars: map[string]any{"gems": ">= 3"}},
+			Actions: []ActionSpec{{Type: ActionActivate, Target: "door_1"}},
+		},
+	})
+	event := NewEvent(EventVarChanged, "gems", "")
+
+	vars["gems"] = 2
+	engine.ProcessEvent(event)
+	if door.activated {
+		t.Fatal("rule fired with 2 gems")
+	}
+	vars["gems"] = 3
+	engine.ProcessEvent(event)
+	if !door.activated {
+		t.Error("rule didn't fire with 3 gems")
+	}
+}
+
+func TestLoadRules_SortsByPriority(t *testing.T) {
+	engine := NewEngine(newMockResolver())
+	engine.LoadRules([]Rule{{ID: "a"}, {ID: "b", Priority: 5}, {ID: "c"}})
+	engine.LoadRules([]Rule{{ID: "d", Priority: 5}, {ID: "e", Priority: -1}, {ID: "f", Priority: 10}})
+
+	var ids []string
+	for _, r := range engine.Rules() {
+		ids = append(ids, r.ID)
+	}
+	if want := []string{"f", "b", "d", "a", "c", "e"}; !slices.Equal(ids, want) {
+		t.Errorf("rule order = %v, want %v", ids, want)
+	}
+}
+
+func TestProcessEvent_ExclusiveGroupFiresFirstMatch(t *testing.T) {
+	// if gems >= 10: gold chest, else if gems >= 5: silver chest, else nothing;
+	// the lamp's rule isn't in the group and always fires
+	resolver := newMockResolver()
+	vars := mockVars{}
+	engine := NewEngine(resolver)
+	engine.SetVariables(vars)
+	engine.LoadRules([]Rule{
+		{ID: "silver", ExclusiveGroup: "chest", When: WhenClause{Event: EventVarChanged, Vars: map[string]any{"gems": ">= 5"}},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "silver"}}},
+		{ID: "gold", ExclusiveGroup: "chest", Priority: 1, When: WhenClause{Event: EventVarChanged, Vars: map[string]any{"gems": ">= 10"}},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "gold"}}},
+		{ID: "lamp", When: WhenClause{Event: EventVarChanged}, Actions: []ActionSpec{{Type: ActionToggle, Target: "lamp"}}},
+	})
+
+	tests := []struct {
+		gems                int
+		gold, silver, lamps int // Toggle counts so far
+	}{
+		{3, 0, 0, 1},
+		{7, 0, 1, 2},
+		{12, 1, 1, 3},
+	}
+	gold, silver, lamp := resolver.addTarget("gold"), resolver.addTarget("silver"), resolver.addTarget("lamp")
+	for _, tt := range tests {
+		vars["gems"] = tt.gems
+		engine.ProcessEvent(NewEvent(EventVarChanged, "gems", ""))
+		if gold.toggled != tt.gold || silver.toggled != tt.silver || lamp.toggled != tt.lamps {
+			t.Errorf("%d gems: gold %d, silver %d, lamp %d toggles; want %d, %d, %d",
+				tt.gems, gold.toggled, silver.toggled, lamp.toggled, tt.gold, tt.silver, tt.lamps)
+		}
+	}
+}
+
+func TestProcessEvent_ExclusiveGroupSkipsSpentOnceRule(t *testing.T) {
+	// A once rule that already fired no longer blocks the rest of its group
+	resolver := newMockResolver()
+	first := resolver.addTarget("first")
+	later := resolver.addTarget("later")
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{
+		{ID: "first_visit", Once: true, ExclusiveGroup: "greeting", When: WhenClause{Event: EventEnterRegion},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "first"}}},
+		{ID: "visit", ExclusiveGroup: "greeting", When: WhenClause{Event: EventEnterRegion},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "later"}}},
+	})
+
+	for i := 0; i < 3; i++ {
+		engine.ProcessEvent(NewEvent(EventEnterRegion, "hall", "player"))
+	}
+	if first.toggled != 1 || later.toggled != 2 {
+		t.Errorf("first toggled %d, later %d times; want 1 and 2", first.toggled, later.toggled)
+	}
+}
+
+func TestProcessEvent_Cooldown(t *testing.T) {
+	resolver := newMockResolver()
+	trap := resolver.addTarget("trap")
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{{
+		ID:       "trap",
+		Cooldown: Duration(3 * time.Second),
+		When:     WhenClause{Event: EventEnterRegion, Region: "spikes"},
+		Actions:  []ActionSpec{{Type: ActionToggle, Target: "trap"}},
+	}})
+	event := NewEvent(EventEnterRegion, "spikes", "player")
+
+	// Entering every second for 7 seconds fires at 0s, 3s and 6s
+	for second := 0; second <= 6; second++ {
+		engine.ProcessEvent(event)
+		engine.Update(time.Second)
+	}
+	if trap.toggled != 3 {
+		t.Errorf("trap fired %d times in 7 seconds with a 3s cooldown, want 3", trap.toggled)
+	}
+}
+
+func TestProcessEvent_MaxFires(t *testing.T) {
+	resolver := newMockResolver()
+	bell := resolver.addTarget("bell")
+	fallback := resolver.addTarget("fallback")
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{
+		{ID: "bell", MaxFires: 2, ExclusiveGroup: "ring", When: WhenClause{Event: EventEnterRegion},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "bell"}}},
+		{ID: "fallback", ExclusiveGroup: "ring", When: WhenClause{Event: EventEnterRegion},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "fallback"}}},
+	})
+
+	for i := 0; i < 5; i++ {
+		engine.ProcessEvent(NewEvent(EventEnterRegion, "tower", "player"))
+	}
+	if bell.toggled != 2 || fallback.toggled != 3 {
+		t.Errorf("bell rang %d, fallback %d times; want 2 then the fallback 3", bell.toggled, fallback.toggled)
+	}
+
+	// Clear forgets the firings along with the rules
+	engine.Clear()
+	engine.LoadRules([]Rule{{ID: "bell", MaxFires: 2, When: WhenClause{Event: EventEnterRegion},
+		Actions: []ActionSpec{{Type: ActionToggle, Target: "bell"}}}})
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "tower", "player"))
+	if bell.toggled != 3 {
+		t.Errorf("bell rang %d times after Clear and reloading, want 3", bell.toggled)
+	}
+}
+
+func TestReload_KeepsFiringsOfRemainingRules(t *testing.T) {
+	resolver := newMockResolver()
+	door := resolver.addTarget("door")
+	trap := resolver.addTarget("trap")
+	gone := resolver.addTarget("gone")
+	engine := NewEngine(resolver)
+	enter := NewEvent(EventEnterRegion, "hall", "player")
+	engine.LoadRules([]Rule{
+		{ID: "door", Once: true, When: WhenClause{Event: EventEnterRegion}, Actions: []ActionSpec{{Type: ActionToggle, Target: "door"}}},
+		{ID: "trap", Cooldown: Duration(3 * time.Second), When: WhenClause{Event: EventEnterRegion},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "trap"}}},
+		{ID: "removed", Once: true, When: WhenClause{Event: EventEnterRegion}, Actions: []ActionSpec{{Type: ActionToggle, Target: "gone"}}},
+	})
+	engine.ProcessEvent(enter)
+	engine.Update(time.Second)
+
+	// The door rule is edited and the trap's action changes; both keep
+	// their state. The removed rule's state goes with it.
+	engine.Reload(RuleSet{Rules: []Rule{
+		{ID: "trap", Cooldown: Duration(3 * time.Second), Priority: 1, When: WhenClause{Event: EventEnterRegion},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "trap"}, {Type: ActionToggle, Target: "trap"}}},
+		{ID: "door", Once: true, When: WhenClause{Event: EventEnterRegion, Region: "hall"},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "door"}}},
+	}})
+	if got := engine.Rules(); len(got) != 2 || got[0].ID != "trap" || !got[1].Active {
+		t.Fatalf("reloaded rules %v, want trap then door, active", got)
+	}
+	engine.ProcessEvent(enter)
+	if door.toggled != 1 || trap.toggled != 1 {
+		t.Errorf("after reload door toggled %d, trap %d times; want both still spent (1, 1)", door.toggled, trap.toggled)
+	}
+	engine.Update(2 * time.Second)
+	engine.ProcessEvent(enter)
+	if trap.toggled != 3 {
+		t.Errorf("trap toggled %d times after its cooldown, want 3", trap.toggled)
+	}
+
+	// Brought back, the removed rule starts over
+	engine.Reload(RuleSet{Rules: []Rule{{ID: "removed", Once: true, When: WhenClause{Event: EventEnterRegion},
+		Actions: []ActionSpec{{Type: ActionToggle, Target: "gone"}}}}})
+	engine.ProcessEvent(enter)
+	if gone.toggled != 2 {
+		t.Errorf("rule removed and added back fired %d times, want 2", gone.toggled)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	resolver := newMockResolver()
+	door := resolver.addTarget("door")
+	trap := resolver.addTarget("trap")
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{
+		{ID: "door", Once: true, When: WhenClause{Event: EventEnterRegion, Region: "hall"},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "door"}}},
+		{ID: "trap", Cooldown: Duration(3 * time.Second), When: WhenClause{Event: EventEnterRegion, Region: "pit"},
+			Actions: []ActionSpec{{Type: ActionToggle, Target: "trap"}}},
+	})
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "pit", "player"))
+	engine.Update(time.Second)
+	snap := engine.Snapshot()
+
+	// Fired after the snapshot, the door rule is spent and the trap waits
+	// for its cooldown
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "hall", "player"))
+	engine.Update(5 * time.Second)
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "pit", "player"))
+	if door.toggled != 1 || trap.toggled != 2 {
+		t.Fatalf("door toggled %d, trap %d times; want 1, 2", door.toggled, trap.toggled)
+	}
+
+	// Restored, the door rule fires again and the trap is back in the
+	// cooldown it was in when the snapshot was taken
+	engine.Restore(snap)
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "hall", "player"))
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "pit", "player"))
+	if door.toggled != 2 || trap.toggled != 2 {
+		t.Errorf("after restore door toggled %d, trap %d times; want 2, 2", door.toggled, trap.toggled)
+	}
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "hall", "player"))
+	if door.toggled != 2 {
+		t.Errorf("once rule fired twice after restore (%d toggles)", door.toggled)
+	}
+
+	// The snapshot isn't changed by firings after the restore
+	engine.Restore(snap)
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "hall", "player"))
+	if door.toggled != 3 {
+		t.Errorf("second restore left the once rule spent (%d toggles)", door.toggled)
+	}
+
+	// A zero snapshot forgets every firing
+	engine.Restore(EngineSnapshot{})
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "pit", "player"))
+	if trap.toggled != 3 {
+		t.Errorf("trap toggled %d times after restoring a zero snapshot, want 3", trap.toggled)
+	}
+}
+
+func TestMatchVar(t *testing.T) {
+	tests := []struct {
+		value any
+		cond  any
+		want  bool
+	}{
+		{3, ">= 3", true},
+		{2, ">=3", false},
+		{2.5, "< 3", true},
+		{3, 3, true},
+		{3, 3.0, true},
+		{3, "!= 3", false},
+		{nil, "0", true}, // Unset counts as 0
+		{nil, "> 0", false},
+		{nil, false, true}, // Unset counts as false
+		{true, true, true},
+		{true, "true", true},
+		{"open", "open", true},
+		{"open", "!= open", false},
+		{"open", "> 1", false}, // Ordering needs numbers
+		{nil, "", true},
+	}
+	for _, tt := range tests {
+		if got := MatchVar(tt.value, tt.cond); got != tt.want {
+			t.Errorf("MatchVar(%v, %v) = %v, want %v", tt.value, tt.cond, got, tt.want)
+		}
+	}
+}
+
+func TestParseYAML_States(t *testing.T) {
+	set, err := ParseYAML([]byte(`
+rules:
+  - id: r1
+    when:
+      event: enter_region
+      states:
+        door_1: open
+    actions:
+      - type: toggle
+        target: lamp_1
+`))
+	if err != nil {
+		t.Fatalf("ParseYAML failed: %v", err)
+	}
+	if got := set.Rules[0].When.States["door_1"]; got != "open" {
+		t.Errorf("States[door_1] = %q, want open", got)
+	}
+}
+
 // ============================================================================
 // Action Execution Tests
 // ============================================================================
@@ -637,6 +1058,85 @@ func TestExecuteAction_NilResolver(t *testing.T) {
 	}
 }
 
+// mockGroupResolver adds groups and ID patterns to mockResolver.
+type mockGroupResolver struct {
+	*mockResolver
+	groups map[string][]string
+}
+
+func (r *mockGroupResolver) ResolveGroup(group string) []Targetable {
+	var result []Targetable
+	for _, id := range r.groups[group] {
+		result = append(result, r.targets[id])
+	}
+	return result
+}
+
+func (r *mockGroupResolver) ResolvePattern(pattern string) []Targetable {
+	var result []Targetable
+	for id, t := range r.targets {
+		if ok, _ := path.Match(pattern, id); ok {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func TestExecuteAction_Pattern(t *testing.T) {
+	resolver := &mockGroupResolver{mockResolver: newMockResolver()}
+	door1 := resolver.addTarget("door_1")
+	door2 := resolver.addTarget("door_2")
+	light := resolver.addTarget("light_1")
+
+	ctx := NewActionContext(Event{}, resolver)
+	if err := ExecuteAction(ctx, ActionSpec{Type: ActionActivate, Target: "door_*"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !door1.activated || !door2.activated || light.activated {
+		t.Errorf("activated door_1=%v door_2=%v light_1=%v, want only the doors",
+			door1.activated, door2.activated, light.activated)
+	}
+
+	if err := ExecuteAction(ctx, ActionSpec{Type: ActionActivate, Target: "gate_*"}); err == nil {
+		t.Error("expected error for a pattern matching nothing")
+	}
+	if err := ExecuteAction(NewActionContext(Event{}, resolver.mockResolver), ActionSpec{Type: ActionActivate, Target: "door_*"}); err == nil {
+		t.Error("expected error for a pattern with a resolver that can't match patterns")
+	}
+}
+
+func TestExecuteAction_Group(t *testing.T) {
+	resolver := &mockGroupResolver{mockResolver: newMockResolver(), groups: map[string][]string{
+		"wave1": {"door_1", "light_1"},
+	}}
+	door1 := resolver.addTarget("door_1")
+	door2 := resolver.addTarget("door_2")
+	light := resolver.addTarget("light_1")
+
+	ctx := NewActionContext(Event{}, resolver)
+	if err := ExecuteAction(ctx, ActionSpec{Type: ActionToggle, Group: "wave1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if door1.toggled != 1 || door2.toggled != 0 || light.toggled != 1 {
+		t.Errorf("toggled door_1=%d door_2=%d light_1=%d, want 1, 0, 1", door1.toggled, door2.toggled, light.toggled)
+	}
+
+	// A target that is also in the group is only toggled once
+	if err := ExecuteAction(ctx, ActionSpec{Type: ActionToggle, Target: "door_*", Group: "wave1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if door1.toggled != 2 || door2.toggled != 1 || light.toggled != 2 {
+		t.Errorf("toggled door_1=%d door_2=%d light_1=%d, want 2, 1, 2", door1.toggled, door2.toggled, light.toggled)
+	}
+
+	if err := ExecuteAction(ctx, ActionSpec{Type: ActionToggle, Group: "wave2"}); err == nil {
+		t.Error("expected error for an empty group")
+	}
+	if err := ExecuteAction(ctx, ActionSpec{Type: ActionToggle}); err == nil {
+		t.Error("expected error for an action without target or group")
+	}
+}
+
 func TestExecuteActions_MultipleActions(t *testing.T) {
 	resolver := newMockResolver()
 	target1 := resolver.addTarget("target_1")
@@ -1040,3 +1540,249 @@ func TestNewEvent(t *testing.T) {
 		t.Errorf("expected actorType 'player', got '%s'", event.ActorType)
 	}
 }
+
+// ============================================================================
+// Event Queue Tests
+// ============================================================================
+
+// chainTarget raises an event on the engine when activated, like a door
+// whose state change is reported back to the rules.
+type chainTarget struct {
+	mockTargetable
+	onActivate func()
+}
+
+func (c *chainTarget) Activate() {
+	c.mockTargetable.Activate()
+	c.onActivate()
+}
+
+func TestPost_WaitsForUpdate(t *testing.T) {
+	resolver := newMockResolver()
+	door := resolver.addTarget("door_1")
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{{
+		ID:      "open",
+		When:    WhenClause{Event: EventEnterRegion, Region: "trigger_1"},
+		Actions: []ActionSpec{{Type: ActionActivate, Target: "door_1"}},
+	}})
+
+	engine.Post(NewEvent(EventEnterRegion, "trigger_1", "player"))
+	if door.activated {
+		t.Fatal("posted event fired before Update")
+	}
+	if engine.Pending() != 1 {
+		t.Errorf("Pending() = %d, want 1", engine.Pending())
+	}
+	if n := engine.Update(0); n != 1 {
+		t.Errorf("Update() = %d, want 1", n)
+	}
+	if !door.activated || engine.Pending() != 0 {
+		t.Errorf("after Update: activated %v, %d pending; want activated, none pending", door.activated, engine.Pending())
+	}
+}
+
+func TestProcessEvent_QueuesEventsRaisedByActions(t *testing.T) {
+	// enter_region on trigger_1 opens a door that raises enter_region on
+	// trigger_2; rule "after" has to see the first event fully processed
+	resolver := newMockResolver()
+	resolver.addTarget("lamp")
+	door := &chainTarget{mockTargetable: mockTargetable{id: "door_1"}}
+	engine := NewEngine(&chainResolver{mockResolver: resolver, extra: map[string]Targetable{"door_1": door}})
+	door.onActivate = func() {
+		engine.ProcessEvent(NewEvent(EventEnterRegion, "trigger_2", "player"))
+	}
+	tracer := NewTracer(10)
+	engine.SetTracer(tracer)
+
+	engine.LoadRules([]Rule{
+		{ID: "first", When: WhenClause{Event: EventEnterRegion, Region: "trigger_1"}, Actions: []ActionSpec{{Type: ActionActivate, Target: "door_1"}}},
+		{ID: "after", When: WhenClause{Event: EventEnterRegion, Region: "trigger_1"}, Actions: []ActionSpec{{Type: ActionToggle, Target: "lamp"}}},
+		{ID: "chained", When: WhenClause{Event: EventEnterRegion, Region: "trigger_2"}, Actions: []ActionSpec{{Type: ActionActivate, Target: "lamp"}}},
+	})
+
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "trigger_1", "player"))
+
+	var order []string
+	recent := tracer.Recent()
+	for i := len(recent) - 1; i >= 0; i-- {
+		order = append(order, recent[i].RuleID)
+	}
+	if !slices.Equal(order, []string{"first", "after", "chained"}) {
+		t.Errorf("rules fired in order %v, want [first after chained]", order)
+	}
+	if engine.Pending() != 0 {
+		t.Errorf("%d events left queued after ProcessEvent", engine.Pending())
+	}
+}
+
+// chainResolver resolves some IDs to targets of other types than
+// mockTargetable.
+type chainResolver struct {
+	*mockResolver
+	extra map[string]Targetable
+}
+
+func (r *chainResolver) Resolve(id string) Targetable {
+	if t, ok := r.extra[id]; ok {
+		return t
+	}
+	return r.mockResolver.Resolve(id)
+}
+
+func TestUpdate_StopsEndlessChains(t *testing.T) {
+	// A rule whose action raises the event that fires it again
+	loop := &chainTarget{mockTargetable: mockTargetable{id: "loop"}}
+	engine := NewEngine(&chainResolver{mockResolver: newMockResolver(), extra: map[string]Targetable{"loop": loop}})
+	loop.onActivate = func() {
+		engine.ProcessEvent(NewEvent(EventVarChanged, "x", ""))
+	}
+	engine.LoadRules([]Rule{{
+		ID:      "again",
+		When:    WhenClause{Event: EventVarChanged, Region: "x"},
+		Actions: []ActionSpec{{Type: ActionActivate, Target: "loop"}},
+	}})
+
+	engine.Post(NewEvent(EventVarChanged, "x", ""))
+	if n := engine.Update(0); n != maxChainedEvents {
+		t.Errorf("Update() = %d, want the cap %d", n, maxChainedEvents)
+	}
+	if engine.Pending() != 1 {
+		t.Errorf("Pending() = %d, want the next link of the chain queued", engine.Pending())
+	}
+}
+
+func TestPost_FromGoroutines(t *testing.T) {
+	resolver := newMockResolver()
+	lamp := resolver.addTarget("lamp")
+	engine := NewEngine(resolver)
+	engine.LoadRules([]Rule{{
+		ID:      "count",
+		When:    WhenClause{Event: EventVarChanged},
+		Actions: []ActionSpec{{Type: ActionToggle, Target: "lamp"}},
+	}})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				engine.Post(NewEvent(EventVarChanged, "x", ""))
+			}
+		}()
+	}
+	wg.Wait()
+
+	processed := 0
+	for engine.Pending() > 0 {
+		processed += engine.Update(0)
+	}
+	if processed != 800 || lamp.toggled != 800 {
+		t.Errorf("processed %d events, toggled %d times; want 800", processed, lamp.toggled)
+	}
+}
+
+// ============================================================================
+// Tracer Tests
+// ============================================================================
+
+func TestTracer_RecordsFiringsAndFailures(t *testing.T) {
+	resolver := newMockResolver()
+	resolver.addTarget("door_1")
+
+	engine := NewEngine(resolver)
+	tracer := NewTracer(4)
+	engine.SetTracer(tracer)
+	engine.LoadRules([]Rule{
+		{
+			ID:   "open_door",
+			When: WhenClause{Event: EventEnterRegion, Region: "switch_1"},
+			Actions: []ActionSpec{
+				{Type: ActionActivate, Target: "door_1"},
+				{Type: ActionActivate, Target: "missing"},
+			},
+			Active: true,
+		},
+	})
+
+	engine.ProcessEvent(NewEvent(EventEnterRegion, "switch_1", "player"))
+
+	recent := tracer.Recent()
+	if len(recent) != 1 {
+		t.Fatalf("expected 1 firing, got %d", len(recent))
+	}
+	f := recent[0]
+	if f.RuleID != "open_door" {
+		t.Errorf("expected rule 'open_door', got '%s'", f.RuleID)
+	}
+	if f.Event.RegionID != "switch_1" {
+		t.Errorf("expected region 'switch_1', got '%s'", f.Event.RegionID)
+	}
+	if len(f.Results) != 2 {
+		t.Fatalf("expected 2 action results, got %d", len(f.Results))
+	}
+	if f.Results[0].Err != nil {
+		t.Errorf("expected first action to succeed, got %v", f.Results[0].Err)
+	}
+	if f.FailedCount() != 1 {
+		t.Errorf("expected 1 failed action, got %d", f.FailedCount())
+	}
+}
+
+func TestTracer_KeepsLastNewestFirst(t *testing.T) {
+	tracer := NewTracer(3)
+	for _, id := range []string{"a", "b", "c", "d", "a"} {
+		tracer.Record(Firing{RuleID: id})
+	}
+
+	recent := tracer.Recent()
+	if len(recent) != 3 {
+		t.Fatalf("expected 3 firings, got %d", len(recent))
+	}
+	want := []string{"a", "d", "c"}
+	for i, id := range want {
+		if recent[i].RuleID != id {
+			t.Errorf("recent[%d]: expected '%s', got '%s'", i, id, recent[i].RuleID)
+		}
+	}
+
+	// Counters include firings that fell out of the ring buffer
+	if tracer.FireCount("a") != 2 {
+		t.Errorf("expected rule 'a' to have fired 2 times, got %d", tracer.FireCount("a"))
+	}
+	if tracer.FireCount("b") != 1 {
+		t.Errorf("expected rule 'b' to have fired 1 time, got %d", tracer.FireCount("b"))
+	}
+}
+
+func TestTracer_Reset(t *testing.T) {
+	tracer := NewTracer(2)
+	tracer.Record(Firing{RuleID: "a"})
+	tracer.Reset()
+
+	if len(tracer.Recent()) != 0 {
+		t.Error("expected no firings after reset")
+	}
+	if tracer.FireCount("a") != 0 {
+		t.Error("expected counters to be cleared after reset")
+	}
+}
+
+func TestReadLevelFile(t *testing.T) {
+	dir := t.TempDir()
+	level := filepath.Join(dir, "level_02.json")
+
+	data, err := ReadLevelFile(level)
+	if err != nil || data != nil {
+		t.Fatalf("ReadLevelFile without a rules file = %q, %v; want nil, nil", data, err)
+	}
+
+	want := "rules: []\n"
+	if err := os.WriteFile(filepath.Join(dir, "level_02_rules.yaml"), []byte(want), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ReadLevelFile(level); err != nil || string(data) != want {
+		t.Errorf("ReadLevelFile = %q, %v; want %q", data, err, want)
+	}
+}
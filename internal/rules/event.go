@@ -10,6 +10,11 @@ const (
 	EventEnterRegion EventType = "enter_region"
 	// EventExitRegion is emitted when a player exits a trigger region
 	EventExitRegion EventType = "exit_region"
+	// EventDeath is emitted when the player dies; it has no region
+	EventDeath EventType = "death"
+	// EventVarChanged is emitted when a gameplay variable changes; its region
+	// is the variable's name
+	EventVarChanged EventType = "var_changed"
 )
 
 // Event represents a game event that can trigger rules.
@@ -2,9 +2,15 @@ package rules
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/torsten/GoP/internal/storage"
 )
 
 // ParseYAML parses rules from YAML data.
@@ -44,3 +50,19 @@ func (e *Engine) LoadJSON(data []byte) error {
 	e.LoadRuleSet(ruleSet)
 	return nil
 }
+
+// PathForLevel returns the path of the rules file that accompanies a level.
+// For "assets/levels/level_01.json" this is "assets/levels/level_01_rules.yaml".
+func PathForLevel(levelPath string) string {
+	return strings.TrimSuffix(levelPath, filepath.Ext(levelPath)) + "_rules.yaml"
+}
+
+// ReadLevelFile reads the rules file that accompanies a level (see
+// PathForLevel). A missing file isn't an error; the data is then nil.
+func ReadLevelFile(levelPath string) ([]byte, error) {
+	data, err := storage.ReadFile(PathForLevel(levelPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
@@ -1,5 +1,13 @@
 package rules
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
 // WhenClause defines when a rule should trigger.
 type WhenClause struct {
 	// Event is the event type to match
@@ -10,18 +18,40 @@ type WhenClause struct {
 	// Actor is the actor type to match: "player", "enemy", etc.
 	// If empty, matches any actor
 	Actor string `yaml:"actor,omitempty"`
+	// States maps target IDs to the state each must be in for the rule to
+	// fire, e.g. {door1: closed}. Targets must implement StateReporter.
+	States map[string]string `yaml:"states,omitempty"`
+	// Vars maps gameplay variable names to conditions each must meet for
+	// the rule to fire, e.g. {gems: ">= 3"} (see MatchVar).
+	Vars map[string]any `yaml:"vars,omitempty"`
 }
 
 // ActionSpec defines an action to execute when a rule triggers.
 type ActionSpec struct {
 	// Type is the action type: activate, deactivate, toggle
 	Type string `yaml:"type"`
-	// Target is the target entity ID
-	Target string `yaml:"target"`
+	// Target is the target entity ID, or a pattern like "door_*" matching
+	// several IDs (* and ? wildcards)
+	Target string `yaml:"target,omitempty"`
+	// Group is a tag; the action applies to every target carrying it.
+	// It can be used instead of or as well as Target.
+	Group string `yaml:"group,omitempty"`
 	// Params contains optional action parameters
 	Params map[string]any `yaml:"params,omitempty"`
 }
 
+// TargetLabel describes what the action acts on, for logs and traces.
+func (s ActionSpec) TargetLabel() string {
+	switch {
+	case s.Group == "":
+		return s.Target
+	case s.Target == "":
+		return "group " + s.Group
+	default:
+		return s.Target + " + group " + s.Group
+	}
+}
+
 // Rule is a single rule definition.
 type Rule struct {
 	// ID is the unique identifier for this rule
@@ -34,6 +64,60 @@ type Rule struct {
 	Once bool `yaml:"once,omitempty"`
 	// Active indicates if this rule is enabled
 	Active bool `yaml:"active,omitempty"`
+	// Priority orders evaluation: higher priorities are checked first,
+	// rules of equal priority in the order they were loaded
+	Priority int `yaml:"priority,omitempty"`
+	// ExclusiveGroup names a group of rules of which only the first
+	// matching one fires per event, for if/else chains
+	ExclusiveGroup string `yaml:"exclusive_group,omitempty" json:"exclusive_group,omitempty"`
+	// Cooldown is the time after firing before the rule can fire again,
+	// in engine time (see Engine.Update)
+	Cooldown Duration `yaml:"cooldown,omitempty"`
+	// MaxFires limits how often the rule fires; 0 is unlimited
+	MaxFires int `yaml:"max_fires,omitempty" json:"max_fires,omitempty"`
+}
+
+// Duration is a time.Duration written as a string in rules files, e.g. "3s".
+type Duration time.Duration
+
+// parseDuration parses a rules file duration.
+func parseDuration(s string) (Duration, error) {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("negative duration %q", s)
+	}
+	return Duration(v), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("duration must be a string like \"3s\": %w", err)
+	}
+	v, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"3s\": %w", err)
+	}
+	v, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
 }
 
 // RuleSet is a collection of rules loaded from a file.
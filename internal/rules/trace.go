@@ -0,0 +1,103 @@
+package rules
+
+// DefaultTraceCapacity is the default number of firings kept by a Tracer.
+const DefaultTraceCapacity = 16
+
+// ActionResult is the outcome of a single action executed by a rule.
+type ActionResult struct {
+	// Spec is the action that was executed
+	Spec ActionSpec
+	// Err is set if the action failed (e.g., its target couldn't be resolved)
+	Err error
+}
+
+// Firing records a single rule firing for debugging.
+type Firing struct {
+	// RuleID is the ID of the rule that fired
+	RuleID string
+	// Event is the event that triggered the rule
+	Event Event
+	// Results holds the outcome of each action, in order
+	Results []ActionResult
+}
+
+// FailedCount returns the number of actions that failed in this firing.
+func (f Firing) FailedCount() int {
+	count := 0
+	for _, r := range f.Results {
+		if r.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Tracer records the most recent rule firings and counts firings per rule.
+// Attach it to an Engine with SetTracer.
+type Tracer struct {
+	firings []Firing // Ring buffer of recent firings
+	next    int      // Index of the next slot to write
+	full    bool     // True once the ring buffer has wrapped
+	counts  map[string]int
+}
+
+// NewTracer creates a tracer that keeps the last capacity firings.
+// A capacity <= 0 uses DefaultTraceCapacity.
+func NewTracer(capacity int) *Tracer {
+	if capacity <= 0 {
+		capacity = DefaultTraceCapacity
+	}
+	return &Tracer{
+		firings: make([]Firing, capacity),
+		counts:  make(map[string]int),
+	}
+}
+
+// Record adds a firing to the tracer.
+func (t *Tracer) Record(f Firing) {
+	t.firings[t.next] = f
+	t.next = (t.next + 1) % len(t.firings)
+	if t.next == 0 {
+		t.full = true
+	}
+	t.counts[f.RuleID]++
+}
+
+// Recent returns the recorded firings, newest first.
+func (t *Tracer) Recent() []Firing {
+	n := t.next
+	if t.full {
+		n = len(t.firings)
+	}
+
+	recent := make([]Firing, 0, n)
+	for i := 1; i <= n; i++ {
+		idx := (t.next - i + len(t.firings)) % len(t.firings)
+		recent = append(recent, t.firings[idx])
+	}
+	return recent
+}
+
+// FireCount returns how many times the rule with the given ID has fired.
+func (t *Tracer) FireCount(ruleID string) int {
+	return t.counts[ruleID]
+}
+
+// Counts returns a copy of the per-rule fire counters.
+func (t *Tracer) Counts() map[string]int {
+	counts := make(map[string]int, len(t.counts))
+	for id, n := range t.counts {
+		counts[id] = n
+	}
+	return counts
+}
+
+// Reset clears all recorded firings and counters.
+func (t *Tracer) Reset() {
+	for i := range t.firings {
+		t.firings[i] = Firing{}
+	}
+	t.next = 0
+	t.full = false
+	t.counts = make(map[string]int)
+}
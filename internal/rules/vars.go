@@ -0,0 +1,104 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Variables gives rules read access to gameplay variables, for when.vars
+// conditions. It is implemented by gameplay.Blackboard.
+type Variables interface {
+	// Get returns a variable's value and whether it is set
+	Get(name string) (any, bool)
+}
+
+// varOperators are the comparisons a when.vars condition can start with,
+// longest first so ">=" isn't read as ">".
+var varOperators = []string{">=", "<=", "!=", "==", ">", "<"}
+
+// MatchVar reports whether a variable's value meets a when.vars condition.
+// A string condition may start with a comparison (">= 3", "!= open"); one
+// without is an equality check, as is a non-string condition (3, true).
+// Unset variables compare as 0, false or "". Ordering comparisons need both
+// sides to be numbers.
+func MatchVar(value any, cond any) bool {
+	op, want := "==", cond
+	if s, ok := cond.(string); ok {
+		s = strings.TrimSpace(s)
+		want = s
+		for _, o := range varOperators {
+			if strings.HasPrefix(s, o) {
+				op, want = o, strings.TrimSpace(s[len(o):])
+				break
+			}
+		}
+	}
+
+	if value == nil {
+		value = zeroVar(want)
+	}
+
+	// Numbers compare as numbers, whatever their type
+	if a, ok := varNumber(value); ok {
+		if b, ok := varNumber(want); ok {
+			switch op {
+			case "==":
+				return a == b
+			case "!=":
+				return a != b
+			case ">":
+				return a > b
+			case ">=":
+				return a >= b
+			case "<":
+				return a < b
+			case "<=":
+				return a <= b
+			}
+		}
+	}
+
+	equal := varString(value) == varString(want)
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	default:
+		return false
+	}
+}
+
+// zeroVar returns the value an unset variable compares as against want.
+func zeroVar(want any) any {
+	if _, ok := varNumber(want); ok {
+		return 0
+	}
+	if s := varString(want); s == "true" || s == "false" {
+		return false
+	}
+	return ""
+}
+
+// varNumber converts a variable value or condition operand to a number.
+func varNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// varString formats a variable value or condition operand for equality
+// checks.
+func varString(v any) string {
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,332 @@
+// Package runedit is a small in-game level editor for quick fixes during
+// playtests. It paints tiles and collision and drags objects on a Tiled map,
+// then writes the level file back. It is a trimmed-down take on the tools in
+// internal/editor, without history, palettes, or property editing.
+package runedit
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/tiled"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Layer names the editor paints on.
+const (
+	LayerTiles     = "Tiles"
+	LayerCollision = "Collision"
+	LayerObjects   = "Objects"
+)
+
+// layers is the Tab cycle order.
+var layers = []string{LayerTiles, LayerCollision, LayerObjects}
+
+// minHitSize is the smallest clickable object size, so point objects can be dragged.
+const minHitSize = 8.0
+
+// Overlay colors
+var (
+	cursorColor    = color.RGBA{255, 255, 255, 160}
+	objectColor    = color.RGBA{80, 200, 255, 200}
+	selectedColor  = color.RGBA{255, 220, 64, 255}
+	hudBackground  = color.RGBA{0, 0, 0, 170}
+	collisionColor = color.RGBA{255, 60, 60, 90}
+)
+
+// Editor edits a level in place while the game runs.
+type Editor struct {
+	doc      *tiled.Map
+	path     string
+	layer    int // Index into layers
+	tile     int // GID painted on the Tiles layer
+	modified bool
+	revision int // Bumped on every change, saved or not
+	status   string
+
+	// Cursor in world coordinates
+	cursorX, cursorY float64
+
+	// Object drag state
+	dragging           bool
+	dragLayer          int
+	dragObject         int
+	dragOffX, dragOffY float64
+
+	// OnTileChanged is called after a tile is painted, so the live map and
+	// collision can follow without reloading the level.
+	OnTileChanged func(layer string, tx, ty, gid int)
+}
+
+// New creates an editor for Tiled JSON level data saved at path.
+func New(path string, data []byte) (*Editor, error) {
+	doc, err := tiled.ParseJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Editor{doc: doc, path: path, tile: 1}, nil
+}
+
+// Layer returns the name of the layer being edited.
+func (e *Editor) Layer() string {
+	return layers[e.layer]
+}
+
+// Modified returns whether there are changes since the last save.
+func (e *Editor) Modified() bool {
+	return e.modified
+}
+
+// Revision returns a counter that changes whenever the level is edited,
+// so callers can tell whether to reload since they last looked.
+func (e *Editor) Revision() int {
+	return e.revision
+}
+
+// LevelData returns the edited level as Tiled JSON.
+func (e *Editor) LevelData() ([]byte, error) {
+	return e.doc.EncodeJSON()
+}
+
+// Save writes the level back to its file.
+func (e *Editor) Save() error {
+	if e.path == "" {
+		return fmt.Errorf("level has no file path")
+	}
+	data, err := e.LevelData()
+	if err != nil {
+		return err
+	}
+	if err := storage.WriteFile(e.path, data); err != nil {
+		return fmt.Errorf("failed to save level: %w", err)
+	}
+	e.modified = false
+	return nil
+}
+
+// Update handles editing input. camX and camY are the camera's top-left
+// position in world coordinates.
+func (e *Editor) Update(inp *input.Input, camX, camY float64) {
+	mx, my := inp.CursorPosition()
+	e.cursorX, e.cursorY = mx+camX, my+camY
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		e.layer = (e.layer + 1) % len(layers)
+		e.dragging = false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) && e.tile > 1 {
+		e.tile--
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		e.tile++
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyControl) && inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		if err := e.Save(); err != nil {
+			e.status = err.Error()
+		} else {
+			e.status = "Saved " + e.path
+		}
+	}
+
+	if e.Layer() == LayerObjects {
+		e.updateObjects()
+	} else {
+		e.updateTiles()
+	}
+}
+
+// updateTiles paints with the left button, erases with the right, and picks
+// the tile under the cursor with the middle button.
+func (e *Editor) updateTiles() {
+	tx, ty, ok := e.cursorTile()
+	if !ok {
+		return
+	}
+	l := e.doc.Layer(e.Layer())
+	if l == nil {
+		return
+	}
+
+	gid := e.tile
+	if e.Layer() == LayerCollision {
+		gid = 1 // Collision is solid (1) or empty (0)
+	}
+
+	switch {
+	case ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft):
+		e.setTile(l, tx, ty, gid)
+	case ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight):
+		e.setTile(l, tx, ty, 0)
+	case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonMiddle) && e.Layer() == LayerTiles:
+		if picked := l.TileAt(tx, ty); picked != 0 {
+			e.tile = picked
+		}
+	}
+}
+
+// setTile changes one tile and notifies OnTileChanged.
+func (e *Editor) setTile(l *tiled.Layer, tx, ty, gid int) {
+	if !l.SetTile(tx, ty, gid) {
+		return
+	}
+	e.changed()
+	if e.OnTileChanged != nil {
+		e.OnTileChanged(l.Name, tx, ty, gid)
+	}
+}
+
+// changed marks the level as edited.
+func (e *Editor) changed() {
+	e.modified = true
+	e.revision++
+}
+
+// cursorTile returns the tile under the cursor. Infinite maps have tiles
+// everywhere; painting past their chunks adds one.
+func (e *Editor) cursorTile() (int, int, bool) {
+	tx := world.TileIndex(e.cursorX, e.doc.TileWidth)
+	ty := world.TileIndex(e.cursorY, e.doc.TileHeight)
+	if !e.doc.Infinite && (tx < 0 || ty < 0 || tx >= e.doc.Width || ty >= e.doc.Height) {
+		return 0, 0, false
+	}
+	return tx, ty, true
+}
+
+// updateObjects drags objects with the left button. Shift snaps to the tile grid.
+func (e *Editor) updateObjects() {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		e.dragging = false
+		if li, oi, ok := e.objectAt(e.cursorX, e.cursorY); ok {
+			obj := &e.doc.Layers[li].Objects[oi]
+			e.dragging = true
+			e.dragLayer, e.dragObject = li, oi
+			e.dragOffX, e.dragOffY = e.cursorX-obj.X, e.cursorY-obj.Y
+		}
+	}
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		e.dragging = false
+		return
+	}
+	if !e.dragging {
+		return
+	}
+
+	obj := &e.doc.Layers[e.dragLayer].Objects[e.dragObject]
+	x := math.Round(e.cursorX - e.dragOffX)
+	y := math.Round(e.cursorY - e.dragOffY)
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		x = snap(x, e.doc.TileWidth)
+		y = snap(y, e.doc.TileHeight)
+	}
+	if x != obj.X || y != obj.Y {
+		obj.X, obj.Y = x, y
+		e.changed()
+	}
+}
+
+// objectAt returns the topmost object under a world position.
+func (e *Editor) objectAt(x, y float64) (int, int, bool) {
+	for li := len(e.doc.Layers) - 1; li >= 0; li-- {
+		l := &e.doc.Layers[li]
+		if l.Type != tiled.LayerObjects {
+			continue
+		}
+		for oi := len(l.Objects) - 1; oi >= 0; oi-- {
+			obj := l.Objects[oi]
+			w, h := math.Max(obj.Width, minHitSize), math.Max(obj.Height, minHitSize)
+			if x >= obj.X && x < obj.X+w && y >= obj.Y && y < obj.Y+h {
+				return li, oi, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// snap rounds v to the nearest multiple of size.
+func snap(v float64, size int) float64 {
+	return math.Round(v/float64(size)) * float64(size)
+}
+
+// Draw renders the edit overlay: collision, objects, the cursor, and a help line.
+func (e *Editor) Draw(screen *ebiten.Image, ctx *world.RenderContext) {
+	tw, th := float64(e.doc.TileWidth), float64(e.doc.TileHeight)
+	camX, camY := ctx.WorldToScreen(0, 0)
+	camX, camY = -camX, -camY
+
+	if l := e.doc.Layer(LayerCollision); l != nil && e.Layer() == LayerCollision {
+		// The layer's tiles, or its chunks' on infinite maps
+		fill := func(data []int, width, tx, ty int) {
+			for i, gid := range data {
+				if gid != 0 {
+					x := float64(tx+i%width)*tw - camX
+					y := float64(ty+i/width)*th - camY
+					draw.FillRect(screen, x, y, tw, th, collisionColor)
+				}
+			}
+		}
+		fill(l.Data, l.Width, 0, 0)
+		for _, c := range l.Chunks {
+			fill(c.Data, c.Width, c.X, c.Y)
+		}
+	}
+
+	for li, l := range e.doc.Layers {
+		if l.Type != tiled.LayerObjects {
+			continue
+		}
+		for oi, obj := range l.Objects {
+			c := objectColor
+			if e.dragging && li == e.dragLayer && oi == e.dragObject {
+				c = selectedColor
+			}
+			w, h := math.Max(obj.Width, minHitSize), math.Max(obj.Height, minHitSize)
+			drawOutline(screen, obj.X-camX, obj.Y-camY, w, h, c)
+			if e.Layer() == LayerObjects {
+				ebitenutil.DebugPrintAt(screen, obj.Type, int(obj.X-camX), int(obj.Y-camY)-14)
+			}
+		}
+	}
+
+	if e.Layer() != LayerObjects {
+		if tx, ty, ok := e.cursorTile(); ok {
+			drawOutline(screen, float64(tx)*tw-camX, float64(ty)*th-camY, tw, th, cursorColor)
+		}
+	}
+
+	e.drawHUD(screen)
+}
+
+// drawHUD draws the mode line at the top of the screen.
+func (e *Editor) drawHUD(screen *ebiten.Image) {
+	mode := fmt.Sprintf("EDIT [%s]", e.Layer())
+	if e.Layer() == LayerTiles {
+		mode += fmt.Sprintf(" tile %d", e.tile)
+	}
+	if e.modified {
+		mode += " *"
+	}
+	help := "LMB paint/drag  RMB erase  MMB pick  [ ] tile  Tab layer  Ctrl+S save  F12 play"
+	if e.status != "" {
+		help = e.status
+	}
+
+	w := screen.Bounds().Dx()
+	draw.FillRect(screen, 0, 0, float64(w), 34, hudBackground)
+	ebitenutil.DebugPrintAt(screen, mode, 4, 0)
+	ebitenutil.DebugPrintAt(screen, help, 4, 16)
+}
+
+// drawOutline draws a 1px rectangle outline.
+func drawOutline(screen *ebiten.Image, x, y, w, h float64, c color.Color) {
+	draw.FillRect(screen, x, y, w, 1, c)
+	draw.FillRect(screen, x, y+h-1, w, 1, c)
+	draw.FillRect(screen, x, y, 1, h, c)
+	draw.FillRect(screen, x+w-1, y, 1, h, c)
+}
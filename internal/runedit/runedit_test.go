@@ -0,0 +1,43 @@
+//go:build display
+
+package runedit
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+func TestEditCompressedLevel(t *testing.T) {
+	for _, compression := range []string{"", tiled.CompressionZlib, tiled.CompressionGzip} {
+		data, err := tiled.EncodeTileData([]int{1, 0, 0, 2}, compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := New("", []byte(`{"width": 2, "height": 2, "tilewidth": 16, "tileheight": 16, "layers": [
+		  {"name": "Tiles", "type": "tilelayer", "width": 2, "height": 2, "encoding": "base64",
+		   "compression": "`+compression+`", "data": "`+data+`"}
+		]}`))
+		if err != nil {
+			t.Fatalf("%q: New failed: %v", compression, err)
+		}
+		e.setTile(e.doc.Layer(LayerTiles), 1, 0, 3)
+
+		saved, err := e.LevelData()
+		if err != nil {
+			t.Fatalf("%q: LevelData failed: %v", compression, err)
+		}
+		m, err := tiled.ParseJSON(saved)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := m.Layer(LayerTiles)
+		if l.Encoding != tiled.EncodingBase64 || l.Compression != compression {
+			t.Errorf("%q: saved as %q/%q, want %q/%q", compression, l.Encoding, l.Compression, tiled.EncodingBase64, compression)
+		}
+		if want := []int{1, 3, 0, 2}; !slices.Equal(l.Data, want) {
+			t.Errorf("%q: saved tiles = %v, want %v", compression, l.Data, want)
+		}
+	}
+}
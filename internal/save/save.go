@@ -0,0 +1,106 @@
+// Package save stores the player's progress between runs: which levels are
+// completed, their best times and the gameplay variables levels keep.
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// LevelRecord is the saved progress of one level.
+type LevelRecord struct {
+	Completed bool    `json:"completed"`
+	BestTime  float64 `json:"bestTime,omitempty"` // Fastest completion in seconds
+}
+
+// Data is the player's saved progress. Levels are keyed by their cleaned
+// file path, as passed to Complete.
+type Data struct {
+	Levels map[string]LevelRecord `json:"levels,omitempty"`
+	// Vars are the gameplay variables kept between runs, by name (see
+	// gameplay.SavedVarsProperty). Numbers read back as float64.
+	Vars map[string]any `json:"vars,omitempty"`
+}
+
+// DefaultPath returns the save file location in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := storage.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "save.json"), nil
+}
+
+// Load reads saved progress from path.
+// A missing file is not an error and returns empty progress.
+func Load(path string) (*Data, error) {
+	data, err := storage.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Data{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save: %w", err)
+	}
+
+	var d Data
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse save: %w", err)
+	}
+	return &d, nil
+}
+
+// Save writes the progress to path, creating the directory if needed.
+func (d *Data) Save(path string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := storage.MkdirAll(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+	if err := storage.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write save: %w", err)
+	}
+	return nil
+}
+
+// Complete records completing the level at path in elapsed seconds, keeping
+// the best time. It returns whether the time is a new best.
+func (d *Data) Complete(path string, elapsed float64) bool {
+	if d.Levels == nil {
+		d.Levels = make(map[string]LevelRecord)
+	}
+	key := filepath.Clean(path)
+	rec := d.Levels[key]
+	best := !rec.Completed || elapsed < rec.BestTime
+	rec.Completed = true
+	if best {
+		rec.BestTime = elapsed
+	}
+	d.Levels[key] = rec
+	return best
+}
+
+// IsCompleted reports whether the level at path has been completed.
+func (d *Data) IsCompleted(path string) bool {
+	return d.Levels[filepath.Clean(path)].Completed
+}
+
+// SavedVars returns Vars, creating it if needed, for levels to read their
+// saved variables from and write them back into.
+func (d *Data) SavedVars() map[string]any {
+	if d.Vars == nil {
+		d.Vars = make(map[string]any)
+	}
+	return d.Vars
+}
+
+// Record returns the saved progress of the level at path.
+func (d *Data) Record(path string) LevelRecord {
+	return d.Levels[filepath.Clean(path)]
+}
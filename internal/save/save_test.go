@@ -0,0 +1,81 @@
+package save
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "save.json")
+
+	want := &Data{}
+	want.Complete("assets/levels/level_01.json", 42.5)
+	if err := want.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if rec := got.Record("assets/levels/level_01.json"); !rec.Completed || rec.BestTime != 42.5 {
+		t.Errorf("Expected level_01 completed in 42.5s, got %+v", rec)
+	}
+}
+
+func TestSaveVarsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+
+	want := &Data{}
+	vars := want.SavedVars()
+	vars["gems"] = 3
+	vars["speed"] = 1.5
+	vars["boss_seen"] = true
+	vars["door"] = "red"
+	if err := want.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	// Numbers come back as float64
+	wantVars := map[string]any{"gems": 3.0, "speed": 1.5, "boss_seen": true, "door": "red"}
+	if !reflect.DeepEqual(got.Vars, wantVars) {
+		t.Errorf("Vars = %v, want %v", got.Vars, wantVars)
+	}
+	if len(got.Levels) != 0 {
+		t.Errorf("Levels = %v, want none", got.Levels)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	d, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+	if len(d.Levels) != 0 {
+		t.Errorf("Expected empty progress, got %+v", d)
+	}
+}
+
+func TestCompleteKeepsBestTime(t *testing.T) {
+	d := &Data{}
+	if !d.Complete("levels/a.json", 30) {
+		t.Error("First completion should be a best time")
+	}
+	if d.Complete("levels/./a.json", 35) {
+		t.Error("Slower completion should not be a best time")
+	}
+	if !d.Complete("levels/a.json", 20) {
+		t.Error("Faster completion should be a best time")
+	}
+	if got := d.Record("levels/a.json").BestTime; got != 20 {
+		t.Errorf("Expected best time 20, got %v", got)
+	}
+	if d.IsCompleted("levels/b.json") {
+		t.Error("Unplayed level should not be completed")
+	}
+}
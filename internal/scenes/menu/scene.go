@@ -0,0 +1,102 @@
+// Package menu provides the title menu scene.
+package menu
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/input"
+)
+
+// Item is a selectable menu entry.
+type Item struct {
+	Label    string
+	OnSelect func() error
+}
+
+// Scene is a title screen with a vertical list of items.
+// Up/Down (or Left/Right) moves the selection; Jump or Enter picks the item.
+type Scene struct {
+	title    string
+	items    []Item
+	selected int
+	width    int
+	height   int
+}
+
+// New creates a menu scene with the given title and items.
+func New(title string, items ...Item) *Scene {
+	return &Scene{
+		title:  title,
+		items:  items,
+		width:  640,
+		height: 360,
+	}
+}
+
+// Select moves the selection to the i-th item.
+func (s *Scene) Select(i int) {
+	if i >= 0 && i < len(s.items) {
+		s.selected = i
+	}
+}
+
+// Update implements app.Scene.Update.
+func (s *Scene) Update(inp *input.Input) error {
+	if len(s.items) == 0 {
+		return nil
+	}
+
+	// Left and right also move, for the touch gamepad that has no up and down
+	if inp.JustPressed(input.ActionMoveUp) || inp.JustPressed(input.ActionMoveLeft) {
+		s.selected = (s.selected + len(s.items) - 1) % len(s.items)
+	}
+	if inp.JustPressed(input.ActionMoveDown) || inp.JustPressed(input.ActionMoveRight) {
+		s.selected = (s.selected + 1) % len(s.items)
+	}
+
+	if inp.JustPressed(input.ActionJump) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		if item := s.items[s.selected]; item.OnSelect != nil {
+			return item.OnSelect()
+		}
+	}
+	return nil
+}
+
+// FixedUpdate implements app.Scene.FixedUpdate.
+func (s *Scene) FixedUpdate() error {
+	// Menus have no physics
+	return nil
+}
+
+// Draw implements app.Scene.Draw.
+func (s *Scene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{20, 24, 36, 255})
+
+	// Centered text; the debug font is 6px wide and 16px tall
+	ebitenutil.DebugPrintAt(screen, s.title, s.width/2-len(s.title)*3, s.height/3)
+	for i, item := range s.items {
+		label := "  " + item.Label
+		if i == s.selected {
+			label = "> " + item.Label
+		}
+		ebitenutil.DebugPrintAt(screen, label, s.width/2-len(label)*3, s.height/2+i*20)
+	}
+}
+
+// Layout implements app.Scene.Layout.
+func (s *Scene) Layout(outsideW, outsideH int) (int, int) {
+	s.width = outsideW
+	s.height = outsideH
+	return outsideW, outsideH
+}
+
+// DebugInfo implements app.Scene.DebugInfo.
+func (s *Scene) DebugInfo() string {
+	if len(s.items) == 0 {
+		return "Menu: no items"
+	}
+	return "Menu: " + s.items[s.selected].Label
+}
@@ -0,0 +1,261 @@
+// Package overworld provides the world map scene, where the player walks a
+// token between level nodes and picks the next level to play.
+package overworld
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/assets"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/save"
+	"github.com/torsten/GoP/internal/storage"
+	"github.com/torsten/GoP/internal/world"
+	"github.com/torsten/GoP/internal/worldmap"
+)
+
+const (
+	// Token speed along paths, in pixels per second.
+	tokenSpeed = 240.0
+	// Node and token radii in pixels.
+	nodeRadius  = 8.0
+	tokenRadius = 5.0
+	// Largest size of a level thumbnail in pixels.
+	thumbWidth  = 160.0
+	thumbHeight = 90.0
+)
+
+// Colors for the scene.
+var (
+	backgroundColor = color.RGBA{16, 32, 24, 255}
+	openPathColor   = color.RGBA{220, 200, 140, 255}
+	lockedPathColor = color.RGBA{80, 80, 80, 255}
+	lockedNodeColor = color.RGBA{70, 70, 70, 255}
+	openNodeColor   = color.RGBA{230, 230, 230, 255}
+	doneNodeColor   = color.RGBA{255, 200, 40, 255}
+	tokenColor      = color.RGBA{80, 160, 255, 255}
+	thumbFrameColor = color.RGBA{230, 230, 230, 255}
+)
+
+// Scene shows a worldmap. Paths open as levels are completed in the save
+// data; arrow keys walk the token along open paths and Jump or Enter plays
+// the level at the token's node.
+type Scene struct {
+	world *worldmap.Map
+	save  *save.Data
+
+	current string  // Node the token is at, or walking to
+	tokenX  float64 // Token position in logical pixels
+	tokenY  float64
+
+	// OnSelect is called with a node and its level's path when the player
+	// picks a node that has a level
+	OnSelect func(node worldmap.Node, level string) error
+
+	// Level thumbnails by level path, nil for levels without one
+	thumbs map[string]*ebiten.Image
+
+	width  int
+	height int
+}
+
+// New creates an overworld scene for m with the token at the node id, or at
+// the start node if id is not an unlocked node. Completion is read from data.
+func New(m *worldmap.Map, data *save.Data, id string) *Scene {
+	s := &Scene{
+		world:  m,
+		save:   data,
+		thumbs: make(map[string]*ebiten.Image),
+		width:  640,
+		height: 360,
+	}
+	if !s.unlocked()[id] {
+		id = m.Start
+	}
+	s.current = id
+	if n, ok := m.Node(id); ok {
+		s.tokenX, s.tokenY = n.X, n.Y
+	}
+	return s
+}
+
+// Current returns the ID of the node the token is at.
+func (s *Scene) Current() string {
+	return s.current
+}
+
+// completed reports whether a level is completed in the save data.
+func (s *Scene) completed(level string) bool {
+	return s.save.IsCompleted(level)
+}
+
+// unlocked returns the nodes the player can reach.
+func (s *Scene) unlocked() map[string]bool {
+	return s.world.Unlocked(s.completed)
+}
+
+// moving reports whether the token is still walking to the current node.
+func (s *Scene) moving() bool {
+	n, _ := s.world.Node(s.current)
+	return s.tokenX != n.X || s.tokenY != n.Y
+}
+
+// Update implements app.Scene.Update.
+func (s *Scene) Update(inp *input.Input) error {
+	s.moveToken(1.0 / 60.0)
+	if s.moving() {
+		return nil
+	}
+
+	var dx, dy float64
+	switch {
+	case inp.JustPressed(input.ActionMoveLeft):
+		dx = -1
+	case inp.JustPressed(input.ActionMoveRight):
+		dx = 1
+	case inp.JustPressed(input.ActionMoveUp):
+		dy = -1
+	case inp.JustPressed(input.ActionMoveDown):
+		dy = 1
+	}
+	if next, ok := s.world.Neighbor(s.current, dx, dy, s.completed); ok {
+		s.current = next
+		return nil
+	}
+
+	if inp.JustPressed(input.ActionJump) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		n, _ := s.world.Node(s.current)
+		if level := s.world.LevelPath(n); level != "" && s.OnSelect != nil {
+			return s.OnSelect(n, level)
+		}
+	}
+	return nil
+}
+
+// moveToken walks the token toward the current node for dt seconds.
+func (s *Scene) moveToken(dt float64) {
+	n, _ := s.world.Node(s.current)
+	dx, dy := n.X-s.tokenX, n.Y-s.tokenY
+	dist := math.Hypot(dx, dy)
+	step := tokenSpeed * dt
+	if dist <= step {
+		s.tokenX, s.tokenY = n.X, n.Y
+		return
+	}
+	s.tokenX += dx / dist * step
+	s.tokenY += dy / dist * step
+}
+
+// FixedUpdate implements app.Scene.FixedUpdate.
+func (s *Scene) FixedUpdate() error {
+	// The overworld has no physics
+	return nil
+}
+
+// Draw implements app.Scene.Draw.
+func (s *Scene) Draw(screen *ebiten.Image) {
+	screen.Fill(backgroundColor)
+	unlocked := s.unlocked()
+
+	for _, p := range s.world.Paths {
+		from, _ := s.world.Node(p.From)
+		to, _ := s.world.Node(p.To)
+		clr := lockedPathColor
+		if unlocked[p.From] && s.world.IsOpen(p, s.completed) {
+			clr = openPathColor
+		}
+		draw.SmoothLine(screen, from.X, from.Y, to.X, to.Y, 3, clr)
+	}
+
+	for _, n := range s.world.Nodes {
+		clr := lockedNodeColor
+		switch level := s.world.LevelPath(n); {
+		case level != "" && s.save.IsCompleted(level):
+			clr = doneNodeColor
+		case unlocked[n.ID]:
+			clr = openNodeColor
+		}
+		draw.FillCircle(screen, n.X, n.Y, nodeRadius, clr)
+	}
+	draw.FillCircle(screen, s.tokenX, s.tokenY, tokenRadius, tokenColor)
+
+	// Title and the current node's details; the debug font is 6px wide
+	ebitenutil.DebugPrintAt(screen, s.world.Name, s.width/2-len(s.world.Name)*3, 10)
+	if !s.moving() {
+		ebitenutil.DebugPrintAt(screen, s.nodeInfo(), 10, s.height-24)
+		s.drawThumbnail(screen)
+	}
+}
+
+// drawThumbnail draws the current node's level thumbnail, if it has one,
+// in the bottom right corner.
+func (s *Scene) drawThumbnail(screen *ebiten.Image) {
+	n, _ := s.world.Node(s.current)
+	level := s.world.LevelPath(n)
+	if level == "" {
+		return
+	}
+	img := s.thumbnail(level)
+	if img == nil {
+		return
+	}
+
+	// Scale down to fit, keeping the aspect ratio
+	w, h := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	scale := math.Min(1, math.Min(thumbWidth/w, thumbHeight/h))
+	w, h = w*scale, h*scale
+	x, y := float64(s.width)-10-w, float64(s.height)-10-h
+
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(img, op)
+	draw.StrokeRect(screen, x, y, w, h, 1, thumbFrameColor)
+}
+
+// thumbnail returns the overview image the editor exported for level (see
+// world.OverviewPath), loaded on first use; nil if there is none.
+func (s *Scene) thumbnail(level string) *ebiten.Image {
+	if img, ok := s.thumbs[level]; ok {
+		return img
+	}
+	var img *ebiten.Image
+	if data, err := storage.ReadFile(world.OverviewPath(level)); err == nil {
+		if img, err = assets.LoadImageFromBytes(data); err != nil {
+			img = nil
+		}
+	}
+	s.thumbs[level] = img
+	return img
+}
+
+// nodeInfo describes the node the token is at.
+func (s *Scene) nodeInfo() string {
+	n, _ := s.world.Node(s.current)
+	level := s.world.LevelPath(n)
+	if level == "" {
+		return n.Name
+	}
+	if rec := s.save.Record(level); rec.Completed {
+		return i18n.T("overworld.nodeBest", n.Name, rec.BestTime)
+	}
+	return i18n.T("overworld.node", n.Name)
+}
+
+// Layout implements app.Scene.Layout.
+func (s *Scene) Layout(outsideW, outsideH int) (int, int) {
+	s.width = outsideW
+	s.height = outsideH
+	return outsideW, outsideH
+}
+
+// DebugInfo implements app.Scene.DebugInfo.
+func (s *Scene) DebugInfo() string {
+	return fmt.Sprintf("Overworld: %s (%d/%d unlocked)", s.current, len(s.unlocked()), len(s.world.Nodes))
+}
@@ -0,0 +1,156 @@
+// Package results provides the level results scene shown after reaching a
+// goal.
+package results
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
+	"github.com/torsten/GoP/internal/input"
+)
+
+// Colors for the scene.
+var (
+	backgroundColor = color.RGBA{20, 24, 36, 255}
+	panelColor      = color.RGBA{36, 42, 60, 255}
+)
+
+// Stats is how a level was played.
+type Stats struct {
+	// Level is the name shown as the title
+	Level string
+	// Time is the completion time in seconds
+	Time float64
+	// BestTime is the saved best time in seconds (0 = none), and NewBest
+	// whether Time set it
+	BestTime float64
+	NewBest  bool
+	// Deaths is the number of times the player died
+	Deaths int
+	// Collected of Collectibles were picked up
+	Collected    int
+	Collectibles int
+}
+
+// Scene shows a level's stats and what comes next. The movement keys
+// choose between continuing and retrying the level (when OnRetry is set);
+// Jump or Enter picks it.
+type Scene struct {
+	stats Stats
+	next  string // Label of the continue choice, e.g. "Next: Level 2"
+
+	// OnContinue is called when the player continues, e.g. to play the next
+	// level; OnRetry, if set, to play the level again
+	OnContinue func() error
+	OnRetry    func() error
+
+	retry  bool // Retry is selected
+	width  int
+	height int
+}
+
+// New creates a results scene for stats. next labels the continue choice.
+func New(stats Stats, next string) *Scene {
+	return &Scene{
+		stats:  stats,
+		next:   next,
+		width:  640,
+		height: 360,
+	}
+}
+
+// Stats returns the stats shown.
+func (s *Scene) Stats() Stats {
+	return s.stats
+}
+
+// Update implements app.Scene.Update.
+func (s *Scene) Update(inp *input.Input) error {
+	// Left and right also toggle, for the touch gamepad
+	toggle := inp.JustPressed(input.ActionMoveUp) || inp.JustPressed(input.ActionMoveDown) ||
+		inp.JustPressed(input.ActionMoveLeft) || inp.JustPressed(input.ActionMoveRight)
+	if s.OnRetry != nil && toggle {
+		s.retry = !s.retry
+	}
+
+	if inp.JustPressed(input.ActionJump) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		if s.retry {
+			return s.OnRetry()
+		}
+		if s.OnContinue != nil {
+			return s.OnContinue()
+		}
+	}
+	return nil
+}
+
+// FixedUpdate implements app.Scene.FixedUpdate.
+func (s *Scene) FixedUpdate() error {
+	// The results screen has no physics
+	return nil
+}
+
+// Draw implements app.Scene.Draw.
+func (s *Scene) Draw(screen *ebiten.Image) {
+	screen.Fill(backgroundColor)
+
+	lines := s.lines()
+	panelW, panelH := 240, 60+len(lines)*16
+	px, py := s.width/2-panelW/2, s.height/3-20
+	draw.FillRect(screen, float64(px), float64(py), float64(panelW), float64(panelH), panelColor)
+
+	// Centered text; the debug font is 6px wide and 16px tall
+	title := i18n.T("results.title")
+	if s.stats.Level != "" {
+		title = i18n.T("results.levelTitle", s.stats.Level)
+	}
+	ebitenutil.DebugPrintAt(screen, title, s.width/2-len(title)*3, py+8)
+	for i, line := range lines {
+		ebitenutil.DebugPrintAt(screen, line, px+20, py+36+i*16)
+	}
+
+	choices := []string{s.next}
+	if s.OnRetry != nil {
+		choices = append(choices, i18n.T("results.retry"))
+	}
+	for i, choice := range choices {
+		label := "  " + choice
+		if (i == 1) == s.retry {
+			label = "> " + choice
+		}
+		ebitenutil.DebugPrintAt(screen, label, s.width/2-len(label)*3, py+panelH+20+i*20)
+	}
+}
+
+// lines returns the stats as lines of text.
+func (s *Scene) lines() []string {
+	time := i18n.T("results.time", s.stats.Time)
+	switch {
+	case s.stats.NewBest:
+		time += i18n.T("results.newBest")
+	case s.stats.BestTime > 0:
+		time += i18n.T("results.best", s.stats.BestTime)
+	}
+	lines := []string{time, i18n.T("results.deaths", s.stats.Deaths)}
+	if s.stats.Collectibles > 0 {
+		lines = append(lines, i18n.T("results.collected", s.stats.Collected, s.stats.Collectibles))
+	}
+	return lines
+}
+
+// Layout implements app.Scene.Layout.
+func (s *Scene) Layout(outsideW, outsideH int) (int, int) {
+	s.width = outsideW
+	s.height = outsideH
+	return outsideW, outsideH
+}
+
+// DebugInfo implements app.Scene.DebugInfo.
+func (s *Scene) DebugInfo() string {
+	return fmt.Sprintf("Results: %s %.1fs, %d deaths", s.stats.Level, s.stats.Time, s.stats.Deaths)
+}
@@ -0,0 +1,160 @@
+package sandbox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/torsten/GoP/internal/app"
+	"github.com/torsten/GoP/internal/config"
+	"github.com/torsten/GoP/internal/gameplay"
+)
+
+// ConsoleCommands implements app.ConsoleUser.ConsoleCommands.
+func (s *Scene) ConsoleCommands() []app.Command {
+	return []app.Command{
+		{
+			Name:  "teleport",
+			Usage: "teleport <x> <y>",
+			Help:  "Move the player to a world position",
+			Run:   s.teleport,
+		},
+		{
+			Name:  "give",
+			Usage: "give key [id] | give collectible",
+			Help:  "Give the player a key or a collectible",
+			Run:   s.give,
+			Complete: func(args []string) []string {
+				if len(args) > 1 {
+					return nil
+				}
+				return []string{"collectible", "key"}
+			},
+		},
+		{
+			Name:  "set",
+			Usage: "set tuning.<group>.<field> <value> | set var.<name> <value>",
+			Help:  "Change a movement tuning value (set tuning.jump.velocity -320) or a gameplay variable (set var.gems 3)",
+			Run:   s.set,
+			Complete: func(args []string) []string {
+				if len(args) > 1 {
+					return nil
+				}
+				paths := config.TuningPaths()
+				for i, p := range paths {
+					paths[i] = "tuning." + p
+				}
+				for _, name := range s.vars.Names() {
+					paths = append(paths, "var."+name)
+				}
+				return paths
+			},
+		},
+		{
+			Name:  "vars",
+			Usage: "vars",
+			Help:  "List the gameplay variables",
+			Run:   s.listVars,
+		},
+	}
+}
+
+// ConsoleToggles implements app.ConsoleUser.ConsoleToggles.
+func (s *Scene) ConsoleToggles() []app.Toggle {
+	return []app.Toggle{
+		{Name: "collision", Value: &s.showDebugCollision},
+		{Name: "deadzone", Value: &s.showDebugDeadzone},
+		{Name: "state", Value: &s.showDebugState},
+		{Name: "steps", Value: &s.showDebugSteps},
+		{Name: "entities", Value: &s.showDebugEntities},
+	}
+}
+
+// teleport runs the teleport command.
+func (s *Scene) teleport(args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("teleport takes x and y")
+	}
+	x, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return "", fmt.Errorf("bad x %q", args[0])
+	}
+	y, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return "", fmt.Errorf("bad y %q", args[1])
+	}
+	s.playerBody.PosX, s.playerBody.PosY = x, y
+	s.playerBody.VelX, s.playerBody.VelY = 0, 0
+	s.playerBody.SavePrevious()
+	return fmt.Sprintf("teleported to %.0f, %.0f", x, y), nil
+}
+
+// give runs the give command.
+func (s *Scene) give(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("give what?")
+	}
+	switch args[0] {
+	case "key":
+		id := ""
+		if len(args) > 1 {
+			id = args[1]
+		}
+		s.progress.PickUpKey(id)
+		return fmt.Sprintf("%d keys held", s.progress.KeysHeld()), nil
+	case "collectible":
+		s.progress.Collect()
+		s.vars.Add(gameplay.VarCollectibles, 1)
+		return fmt.Sprintf("collected %d/%d", s.progress.Collected, s.progress.Collectibles), nil
+	}
+	return "", fmt.Errorf("can't give %q", args[0])
+}
+
+// set runs the set command.
+func (s *Scene) set(args []string) (string, error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("set takes a name and a value")
+	}
+	if name, ok := strings.CutPrefix(args[0], "var."); ok {
+		setVar(s.vars, name, args[1])
+		return fmt.Sprintf("%s = %s", args[0], args[1]), nil
+	}
+	path, ok := strings.CutPrefix(args[0], "tuning.")
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", args[0])
+	}
+	t := s.tuning
+	if err := config.SetTuningValue(&t, path, args[1]); err != nil {
+		return "", err
+	}
+	s.SetTuning(t)
+	return fmt.Sprintf("%s = %s", args[0], args[1]), nil
+}
+
+// setVar sets a gameplay variable from console text: an integer, a number,
+// true or false, or else a string.
+func setVar(vars *gameplay.Blackboard, name, value string) {
+	if n, err := strconv.Atoi(value); err == nil {
+		vars.SetInt(name, n)
+	} else if f, err := strconv.ParseFloat(value, 64); err == nil {
+		vars.SetFloat(name, f)
+	} else if b, err := strconv.ParseBool(value); err == nil {
+		vars.SetBool(name, b)
+	} else {
+		vars.SetString(name, value)
+	}
+}
+
+// listVars runs the vars command.
+func (s *Scene) listVars(args []string) (string, error) {
+	names := s.vars.Names()
+	if len(names) == 0 {
+		return "no variables set", nil
+	}
+	lines := make([]string, len(names))
+	for i, name := range names {
+		v, _ := s.vars.Get(name)
+		lines[i] = fmt.Sprintf("%s = %v", name, v)
+	}
+	return strings.Join(lines, "\n"), nil
+}
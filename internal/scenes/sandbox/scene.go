@@ -4,29 +4,58 @@ package sandbox
 import (
 	"fmt"
 	"image/color"
+	"math"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/torsten/GoP/internal/arena"
 	"github.com/torsten/GoP/internal/assets"
 	"github.com/torsten/GoP/internal/camera"
+	"github.com/torsten/GoP/internal/debugui"
 	"github.com/torsten/GoP/internal/entities"
 	"github.com/torsten/GoP/internal/game"
 	"github.com/torsten/GoP/internal/gameplay"
 	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/gfx/draw"
+	"github.com/torsten/GoP/internal/i18n"
 	"github.com/torsten/GoP/internal/input"
+	"github.com/torsten/GoP/internal/logging"
+	"github.com/torsten/GoP/internal/music"
 	"github.com/torsten/GoP/internal/physics"
 	"github.com/torsten/GoP/internal/rules"
+	"github.com/torsten/GoP/internal/runedit"
+	"github.com/torsten/GoP/internal/sound"
+	"github.com/torsten/GoP/internal/theme"
 	timestep "github.com/torsten/GoP/internal/time"
+	"github.com/torsten/GoP/internal/tween"
 	"github.com/torsten/GoP/internal/world"
 )
 
+// logger logs level loading and gameplay events.
+var logger = logging.New("sandbox")
+
 const (
 	// Animation frame duration.
 	frameDuration = 100 * time.Millisecond
 	// Player size in pixels.
 	playerSize = 12
+	// Duration of the white flash when the player dies.
+	deathFlashDuration = 150 * time.Millisecond
+	// Duration of the full-screen red flash when the player dies.
+	damageFlashDuration = 250 * time.Millisecond
+	// Duration of the white flash and red screen flash when the player is hurt.
+	hitFlashDuration = 100 * time.Millisecond
+	// How long the "goal locked" message stays on screen, in seconds.
+	goalMessageDuration = 2.0
+	// How long the level complete message shows before the completion
+	// screen set with SetOnComplete takes over, in seconds.
+	completeDelay = 1.0
+	// Camera pan speed in edit mode, in pixels per frame.
+	editPanSpeed = 4.0
+	// How long the camera takes to glide back to the respawn point, in seconds.
+	respawnGlideTime = 0.4
 )
 
 // Colors for the scene.
@@ -35,6 +64,7 @@ var (
 	collisionColor  = color.RGBA{0xff, 0x00, 0x00, 0x80}
 	playerColor     = color.RGBA{0x00, 0xff, 0x00, 0xff}
 	deadzoneColor   = color.RGBA{0xff, 0xff, 0x00, 0x60}
+	damageColor     = color.RGBA{0xff, 0x20, 0x20, 0xff}
 )
 
 // Scene represents the sandbox test scene with tilemap and physics.
@@ -48,6 +78,7 @@ type Scene struct {
 	collisionMap *world.CollisionMap
 	entityWorld  *entities.EntityWorld
 	levelData    []byte // Store raw level data for object parsing
+	bounds       world.LevelBounds
 
 	// Camera (enhanced with deadzone)
 	camera *camera.Camera
@@ -57,8 +88,13 @@ type Scene struct {
 	playerController *physics.Controller
 	resolver         *physics.CollisionResolver
 
+	// Scratch memory, so a fixed update doesn't allocate
+	collisions arena.Arena[physics.Collision] // Reset every FixedUpdate
+	solidAABBs []physics.AABB                 // Reused by resolveSolidEntityCollisions
+
 	// Gameplay state
-	state *gameplay.StateMachine
+	state  *gameplay.StateMachine
+	health *gameplay.Health
 
 	// Tuning parameters
 	tuning game.Tuning
@@ -89,10 +125,67 @@ type Scene struct {
 
 	// Rules engine for data-driven entity interactions
 	ruleEngine *rules.Engine
+	rulesData  []byte // The level's rules file, loaded after its embedded rules
+
+	// Lighting (enabled when the level sets ambient darkness)
+	lighting        *gfx.LightLayer
+	ambientDarkness float64
+
+	// Layered background music (stems attach once an audio backend exists)
+	music *music.Mixer
+	// Sound emitters placed in the level (players attach with the backend too)
+	sounds *sound.Soundscape
+
+	// Post-processor set by the app (nil when running without one)
+	postfx *gfx.PostProcessor
+
+	// Interpolation between fixed updates, set by the app before each draw
+	renderAlpha float64
+
+	// Debug pause (F8): FixedUpdate only runs when a step (F9) is pending
+	paused      bool
+	stepPending bool
+	inspector   *entities.Inspector // Click an entity while paused to inspect it
+
+	// Particles where the player last died
+	deathBurst *gfx.Burst
+
+	// Room transitions through exits in multi-room levels
+	levels *gameplay.LevelManager
+
+	// Level progress for goal requirements
+	progress         *gameplay.LevelProgress
+	vars             *gameplay.Blackboard      // Gameplay variables shared with rules and the HUD
+	checkpoint       *gameplay.CheckpointState // Restored on respawn
+	goalMessage      string
+	goalMessageTimer float64
+
+	// Completion hooks for scenes that launched the level (see SetOnComplete)
+	onComplete   func(progress *gameplay.LevelProgress)
+	savedVars    map[string]any // Variables kept between runs, see SetSavedVars
+	onContinue   func() error
+	completedFor float64 // Seconds since the goal was reached
+
+	// Runtime edit mode (debug builds only, see EnableEditMode)
+	editPath     string
+	editor       *runedit.Editor
+	editing      bool
+	editRevision int // Editor revision the entities were spawned from
 }
 
-// New creates a new sandbox scene.
+// New creates a new sandbox scene with the built-in level.
 func New() *Scene {
+	levelData, err := assets.LoadLevelJSON()
+	if err != nil {
+		panic(fmt.Sprintf("failed to load level: %v", err))
+	}
+	s := NewWithLevel(levelData)
+	s.SetLevelPath(assets.AssetsDir + "/levels/level_01.json")
+	return s
+}
+
+// NewWithLevel creates a new sandbox scene playing the given Tiled JSON level.
+func NewWithLevel(levelData []byte) *Scene {
 	s := &Scene{
 		inp:           input.NewInput(),
 		width:         640,
@@ -100,8 +193,12 @@ func New() *Scene {
 		tuning:        game.DefaultTuning(),
 		timestep:      timestep.NewTimestep(),
 		state:         gameplay.NewStateMachine(),
+		health:        gameplay.NewHealth(game.DefaultTuning().Health),
 		debugRenderer: entities.NewDebugRenderer(),
+		inspector:     entities.NewInspector(),
+		music:         music.NewDefaultMixer(),
 	}
+	s.levels = gameplay.NewLevelManager("", s.enterRoom)
 
 	// Load tileset image
 	tilesetImg, err := assets.LoadTileset()
@@ -109,32 +206,21 @@ func New() *Scene {
 		panic(fmt.Sprintf("failed to load tileset: %v", err))
 	}
 	tileset := world.NewTilesetFromImage(tilesetImg, 16, 16)
-
-	// Load map data
-	s.levelData, err = assets.LoadLevelJSON()
-	if err != nil {
-		panic(fmt.Sprintf("failed to load level: %v", err))
+	if data, err := assets.LoadTilesetDescriptor(); err != nil {
+		logger.Warnf("Failed to load tile animations: %v", err)
+	} else if err := tileset.LoadAnimations(data); err != nil {
+		logger.Warnf("Failed to load tile animations: %v", err)
 	}
-	mapData, err := world.ParseTiledJSON(s.levelData)
-	if err != nil {
-		panic(fmt.Sprintf("failed to parse level: %v", err))
-	}
-	s.tileMap = world.NewMap(mapData, tileset)
-
-	// Create renderer
-	s.renderer = world.NewMapRenderer(s.tileMap)
 
 	// Create enhanced camera with deadzone
 	s.camera = camera.NewCamera(s.width, s.height)
 	s.camera.SetDeadzoneCentered(0.25, 0.4) // 25% width, 40% height deadzone
-	s.camera.SetLevelBounds(float64(s.tileMap.PixelWidth()), float64(s.tileMap.PixelHeight()))
 	s.camera.PixelPerfect = true
 
-	// Create collision map from "Collision" layer
-	s.collisionMap = world.NewCollisionMapFromMap(s.tileMap, "Collision")
-
-	// Create entity world
-	s.entityWorld = entities.NewEntityWorld()
+	// Parse map data
+	if err := s.loadMap(levelData, tileset); err != nil {
+		panic(fmt.Sprintf("failed to parse level: %v", err))
+	}
 
 	// Create player
 	s.playerBody = &physics.Body{
@@ -149,23 +235,120 @@ func New() *Scene {
 	// Load entities from level
 	s.loadEntities()
 
+	// Set up lighting
+	s.lighting = gfx.NewLightLayer()
+
 	// Load player sprite (reuse existing ball sprite)
 	if err := s.initSprite(); err != nil {
-		fmt.Printf("Failed to load sprite: %v\n", err)
+		logger.Warnf("Failed to load sprite: %v", err)
 	}
 
 	return s
 }
 
+// loadMap builds the tile map, renderer and collision from level data and
+// starts an empty entity world for it. Entities are spawned separately by
+// loadEntities.
+func (s *Scene) loadMap(levelData []byte, tileset *world.Tileset) error {
+	mapData, err := world.ParseTiledJSON(levelData)
+	if err != nil {
+		return err
+	}
+	s.levelData = levelData
+	s.tileMap = world.NewMap(mapData, tileset)
+	s.renderer = world.NewMapRenderer(s.tileMap)
+	s.camera.SetLevelBounds(float64(s.tileMap.PixelWidth()), float64(s.tileMap.PixelHeight()))
+	s.ambientDarkness = world.AmbientDarkness(s.tileMap.Properties())
+
+	// Create collision map from "Collision" layer
+	s.collisionMap = world.NewCollisionMapFromMap(s.tileMap, "Collision")
+
+	// Create entity world
+	s.entityWorld = entities.NewEntityWorld()
+	s.inspector.Clear()
+	return nil
+}
+
+// SetLevelPath sets the file the level was loaded from. Exits to other rooms
+// are relative to it, and entering one loads its rules file (see
+// rules.ReadLevelFile).
+func (s *Scene) SetLevelPath(path string) {
+	s.levels.SetPath(path)
+}
+
+// SetOnComplete sets the hooks for finishing the level: record is called
+// with the level's progress (time, deaths, collectibles) as soon as the goal
+// is reached, and cont a moment later, e.g. to show the results screen.
+// Either may be nil; without cont the level complete message stays up.
+func (s *Scene) SetOnComplete(record func(progress *gameplay.LevelProgress), cont func() error) {
+	s.onComplete = record
+	s.onContinue = cont
+}
+
+// SetSavedVars sets the variables kept between runs (see
+// gameplay.SavedVarsProperty): the level's saved variables are read from
+// vars now, counting as part of the level start, and written back to vars
+// when its goal is reached, before the SetOnComplete record hook runs.
+func (s *Scene) SetSavedVars(vars map[string]any) {
+	s.savedVars = vars
+	s.vars.LoadSaved(s.tileMap.Properties(), vars)
+	s.checkpoint = gameplay.SaveCheckpoint("", s.entityWorld, s.progress, s.vars, s.ruleEngine)
+}
+
+// enterRoom implements gameplay.RoomLoader: it builds the level in data, if
+// any, and moves the player to the spawn with the given id. Gameplay
+// variables and health carry over; everything else starts afresh.
+func (s *Scene) enterRoom(path string, data []byte, spawn string) error {
+	if data != nil {
+		rulesData, err := rules.ReadLevelFile(path)
+		if err != nil {
+			return err
+		}
+		if err := s.loadMap(data, s.tileMap.Tileset()); err != nil {
+			return err
+		}
+		s.rulesData = rulesData
+		s.music.ResetSignals()
+		s.loadEntities()
+		if s.editPath != "" {
+			// Runtime edits now go to the new room
+			s.EnableEditMode(path)
+			s.editor, s.editRevision = nil, 0
+		}
+	}
+
+	objects, err := world.ParseObjects(s.levelData)
+	if err != nil {
+		return err
+	}
+	x, y, found := world.FindSpawnByID(objects, spawn)
+	if !found {
+		logger.Warnf("Room %s has no spawn %q, using the first spawn", path, spawn)
+		x, y, _ = world.FindSpawnPoint(objects)
+	}
+	s.playerBody.PosX, s.playerBody.PosY = x, y
+	s.playerBody.VelX, s.playerBody.VelY = 0, 0
+	s.playerBody.SavePrevious()
+	s.state.SetRespawnPoint(x, y)
+	s.camera.Follow(x+s.playerBody.W/2, y+s.playerBody.H/2, s.playerBody.W, s.playerBody.H)
+	s.camera.Snap()
+	logger.Infof("Entered room %s at spawn %q", path, spawn)
+	return nil
+}
+
 // loadEntities parses the level data and spawns entities.
 func (s *Scene) loadEntities() {
 	// Parse objects from level data
 	objects, err := world.ParseObjects(s.levelData)
 	if err != nil {
-		fmt.Printf("Failed to parse objects: %v\n", err)
+		logger.Errorf("Failed to parse objects: %v", err)
 		return
 	}
 
+	// Level bounds policy and kill line
+	s.bounds = world.NewLevelBounds(s.tileMap.Properties(), objects,
+		float64(s.tileMap.PixelWidth()), float64(s.tileMap.PixelHeight()))
+
 	// Find spawn point
 	if spawnX, spawnY, found := world.FindSpawnPoint(objects); found {
 		s.playerBody.PosX = spawnX
@@ -173,24 +356,69 @@ func (s *Scene) loadEntities() {
 		s.state.SetRespawnPoint(spawnX, spawnY)
 	}
 
+	// Track progress for goal requirements
+	s.progress = gameplay.NewLevelProgress(objects)
+
+	// Gameplay variables carry over when entities respawn (room changes, edits)
+	vars := gameplay.NewBlackboard()
+	if s.vars != nil {
+		vars.Restore(s.vars.Snapshot())
+	}
+	s.vars = vars
+
 	// Create spawn context with callbacks
 	ctx := gameplay.SpawnContext{
-		OnDeath: func() {
-			s.state.TriggerDeath()
-		},
+		OnDeath:  s.killPlayer,
+		OnDamage: s.damagePlayer,
 		OnCheckpoint: func(id string, x, y float64) {
 			s.state.SetRespawnPoint(x, y)
-			fmt.Printf("Checkpoint '%s' activated at (%.0f, %.0f)\n", id, x, y)
+			s.checkpoint = gameplay.SaveCheckpoint(id, s.entityWorld, s.progress, s.vars, s.ruleEngine)
+			logger.Infof("Checkpoint '%s' activated at (%.0f, %.0f)", id, x, y)
 		},
 		OnGoalReached: func() {
 			s.state.TriggerComplete()
-			fmt.Println("Level Complete!")
+			s.completedFor = 0
+			logger.Infof("Level Complete! (%.1fs)", s.progress.Elapsed)
+			if s.savedVars != nil {
+				s.vars.StoreSaved(s.tileMap.Properties(), s.savedVars)
+			}
+			if s.onComplete != nil {
+				s.onComplete(s.progress)
+			}
+		},
+		OnGoalBlocked: func(reason string) {
+			s.goalMessage = reason
+			s.goalMessageTimer = goalMessageDuration
+		},
+		OnCollect: func(id string) {
+			logger.Infof("Collected %d/%d", s.progress.Collected, s.progress.Collectibles)
+		},
+		OnKey: func(id string) {
+			logger.Infof("Picked up key %q (%d held)", id, s.progress.KeysHeld())
+		},
+		OnLevelExit: func(level, spawn string) {
+			s.levels.Transition(level, spawn)
 		},
 		Registry: s.entityWorld.TargetRegistry,
+		Progress: s.progress,
+		Vars:     s.vars,
+		Player:   s.playerBody,
+		Blocked:  s.blockedAt,
+		OnSpawn:  s.entityWorld.SetSource,
+	}
+
+	// Apply the level theme, if any
+	if name := theme.NameForLevel(s.tileMap.Properties()); name != "" {
+		skins, err := theme.LoadSkins(assets.FS(), name)
+		if err != nil {
+			logger.Warnf("Failed to load theme: %v", err)
+		} else {
+			ctx.Skins = skins
+		}
 	}
 
 	// Spawn entities
-	_, triggers, solidEnts, kinematics, switches := gameplay.SpawnEntities(objects, ctx)
+	ents, triggers, solidEnts, kinematics, _ := gameplay.SpawnEntities(objects, ctx)
 
 	// Add entities to world
 	for _, t := range triggers {
@@ -202,33 +430,41 @@ func (s *Scene) loadEntities() {
 	for _, k := range kinematics {
 		s.entityWorld.AddKinematic(k)
 	}
-
-	// Initialize rules engine with target registry
-	resolver := newTargetResolver(s.entityWorld.TargetRegistry)
+	gameplay.AddLights(s.entityWorld, ents)
+	gameplay.RegisterMusicTargets(s.entityWorld.TargetRegistry, s.music)
+	s.sounds = gameplay.NewSoundscape(objects)
+	s.playerController.Zones = gameplay.ForceZones(objects)
+	s.entityWorld.YSort = world.YSort(s.tileMap.Properties())
+
+	// Initialize rules engine with target registry, fed by the triggers
+	resolver := gameplay.NewTargetResolver(s.entityWorld.TargetRegistry)
 	s.ruleEngine = rules.NewEngine(resolver)
-
-	// Connect switches to rules engine
-	for _, sw := range switches {
-		sw.OnTrigger = func(switchID string) {
-			s.ruleEngine.ProcessEvent(rules.NewEvent(rules.EventEnterRegion, switchID, "player"))
-		}
-	}
-
-	// Load rules from level data (if embedded in properties)
-	// TODO: Load from separate file or embedded level data
-	// For now, we'll add example rules programmatically for testing
+	gameplay.ConnectRules(s.entityWorld, s.ruleEngine)
+	gameplay.ConnectBlackboard(s.vars, s.ruleEngine)
 	s.loadRules()
+
+	// Respawning before any checkpoint restores the level start
+	s.checkpoint = gameplay.SaveCheckpoint("", s.entityWorld, s.progress, s.vars, s.ruleEngine)
 }
 
-// loadRules loads rules for the current level.
-// In the future, this will load from embedded level data or separate YAML files.
+// loadRules loads the rules embedded in the level and those from its rules
+// file (see SetRules).
 func (s *Scene) loadRules() {
-	// Example: Load rules from a YAML file if it exists
-	// For MVP, rules can be embedded in level JSON properties or loaded separately
-	// ruleData, err := assets.LoadRules("level_01_rules.yaml")
-	// if err == nil {
-	//     s.ruleEngine.LoadYAML(ruleData)
-	// }
+	if err := gameplay.LoadLevelRules(s.ruleEngine, s.tileMap.Properties(), s.rulesData); err != nil {
+		logger.Warnf("Failed to load rules: %v", err)
+		return
+	}
+	if n := s.ruleEngine.RuleCount(); n > 0 {
+		logger.Infof("Loaded %d rules", n)
+	}
+}
+
+// SetRules sets the contents of the level's rules file (YAML, see
+// rules.ReadLevelFile) and reloads the rules.
+func (s *Scene) SetRules(data []byte) {
+	s.rulesData = data
+	s.ruleEngine.Clear()
+	s.loadRules()
 }
 
 // initSprite loads the spritesheet and creates the sprite animation.
@@ -261,15 +497,39 @@ func (s *Scene) initSprite() error {
 // FixedUpdate handles physics updates at fixed rate.
 // This is called multiple times per frame if needed.
 func (s *Scene) FixedUpdate() error {
-	// Skip physics during death/respawn/completed states
-	if !s.state.IsRunning() {
+	// Skip physics during death/respawn/completed states and while editing
+	if !s.state.IsRunning() || s.editing {
 		return nil
 	}
+	if s.paused {
+		if !s.stepPending {
+			return nil
+		}
+		s.stepPending = false
+	}
 
 	dt := s.timestep.TickDuration()
 
+	// Gameplay waits while the screen fades between rooms
+	if s.levels.Busy() {
+		if err := s.levels.Update(dt.Seconds()); err != nil {
+			logger.Errorf("Failed to change room: %v", err)
+		}
+		return nil
+	}
+
+	s.playerBody.SavePrevious()
+	s.collisions.Reset()
+
+	// Advance the level timer
+	if s.progress != nil {
+		s.progress.Update(dt.Seconds())
+	}
+	s.health.Update(dt.Seconds())
+
 	// Step 1: Update kinematic entities FIRST (platforms move before player physics)
 	s.entityWorld.UpdateKinematics(s.collisionMap, dt.Seconds())
+	s.entityWorld.FixedUpdate(dt.Seconds()) // Animated doors
 
 	// Step 2: Clear previous platform reference and check for carry
 	s.playerController.ClearPlatformCarry()
@@ -290,35 +550,54 @@ func (s *Scene) FixedUpdate() error {
 	// Step 5: Resolve player collision against solid entities (including platforms)
 	s.resolveSolidEntityCollisions()
 
-	// Step 6: Check triggers after movement
+	// Step 6: Check triggers after movement, then run the rules on events
+	// posted this tick
 	s.entityWorld.CheckTriggers(s.playerBody)
+	s.ruleEngine.Update(dt)
+
+	// Step 7: Enforce level bounds (kill line, clamp or wrap)
+	if gameplay.ApplyLevelBounds(s.playerBody, s.bounds) {
+		s.killPlayer()
+	}
+
+	// Step 8: Camera follows player, at the physics rate so drawing can
+	// interpolate it along with the player
+	s.followPlayer(dt.Seconds())
 
 	return nil
 }
 
+// followPlayer moves the camera toward the player.
+func (s *Scene) followPlayer(dt float64) {
+	playerCenterX := s.playerBody.PosX + s.playerBody.W/2
+	playerCenterY := s.playerBody.PosY + s.playerBody.H/2
+	s.camera.Follow(playerCenterX, playerCenterY, s.playerBody.W, s.playerBody.H)
+	s.camera.Update(dt)
+}
+
 // resolveCollisions checks for collisions at the given AABB and returns collision info.
+// The result is valid until the next FixedUpdate.
 func (s *Scene) resolveCollisions(aabb physics.AABB) []physics.Collision {
-	var collisions []physics.Collision
-
 	// Get tile range to check
 	tileSize := 16
-	startTX := int(aabb.X) / tileSize
-	startTY := int(aabb.Y) / tileSize
-	endTX := int(aabb.X+aabb.W) / tileSize
-	endTY := int(aabb.Y+aabb.H) / tileSize
-
-	// Clamp to map bounds
-	if startTX < 0 {
-		startTX = 0
-	}
-	if startTY < 0 {
-		startTY = 0
-	}
-	if endTX >= s.tileMap.Width() {
-		endTX = s.tileMap.Width() - 1
+	startTX := world.TileIndex(aabb.X, tileSize)
+	startTY := world.TileIndex(aabb.Y, tileSize)
+	endTX := world.TileIndex(aabb.X+aabb.W, tileSize)
+	endTY := world.TileIndex(aabb.Y+aabb.H, tileSize)
+
+	// Clamp to map bounds. Infinite maps have tiles left of and above the
+	// origin, so there's nothing to clamp to.
+	if !s.tileMap.Infinite() {
+		startTX = max(startTX, 0)
+		startTY = max(startTY, 0)
+		endTX = min(endTX, s.tileMap.Width()-1)
+		endTY = min(endTY, s.tileMap.Height()-1)
 	}
-	if endTY >= s.tileMap.Height() {
-		endTY = s.tileMap.Height() - 1
+
+	// Room for every tile in range, so appending doesn't reallocate
+	var collisions []physics.Collision
+	if startTX <= endTX && startTY <= endTY {
+		collisions = s.collisions.Alloc((endTX - startTX + 1) * (endTY - startTY + 1))[:0]
 	}
 
 	// Check each tile
@@ -372,44 +651,95 @@ func (s *Scene) resolveCollisions(aabb physics.AABB) []physics.Collision {
 	return collisions
 }
 
+// blockedAt reports whether an area overlaps solid tiles.
+func (s *Scene) blockedAt(a physics.AABB) bool {
+	return s.collisionMap.OverlapsSolid(a.X, a.Y, a.W, a.H)
+}
+
 // resolveSolidEntityCollisions resolves player collision against solid entities.
 // This is called after tile collision to handle entity-specific collision.
 func (s *Scene) resolveSolidEntityCollisions() {
-	solidAABBs := s.entityWorld.ActiveSolidAABBs()
+	s.solidAABBs = s.entityWorld.AppendActiveSolidAABBs(s.solidAABBs[:0])
 
 	// Resolve against all solids
-	if len(solidAABBs) > 0 {
-		physics.ResolveSolids(s.playerBody, solidAABBs)
+	if len(s.solidAABBs) > 0 {
+		s.playerController.StepOntoSolids(s.solidAABBs)
+		physics.ResolveSolids(s.playerBody, s.solidAABBs)
 	}
 }
 
 // Update implements app.Scene.Update.
 // This handles non-physics updates and input.
 func (s *Scene) Update(inp *input.Input) error {
+	// Runtime edit mode pauses the game
+	if s.editPath != "" && inp.JustPressed(input.ActionEditToggle) {
+		s.toggleEditMode()
+	}
+	if s.editing {
+		s.updateEditMode(inp)
+		s.inp.Update()
+		return nil
+	}
+
+	// Debug pause freezes the game; each step runs one fixed update
+	if inp.JustPressed(input.ActionPauseToggle) {
+		s.paused = !s.paused
+		s.stepPending = false
+	}
+	if inp.JustPressed(input.ActionStepFrame) {
+		s.paused = true
+		s.stepPending = true
+	}
+	if s.paused {
+		s.updateInspector(inp)
+		s.handleDebugToggles()
+		s.updateDebugText()
+		s.inp.Update()
+		return nil
+	}
+
 	// Update state machine
 	s.state.Update(1.0 / 60.0)
 
+	// Move on to the completion screen
+	if s.state.IsCompleted() && s.onContinue != nil {
+		s.completedFor += 1.0 / 60.0
+		if s.completedFor >= completeDelay {
+			return s.onContinue()
+		}
+	}
+
 	// Handle respawn
 	if s.state.IsRespawning() {
 		s.respawnPlayer()
 	}
+	if s.deathBurst != nil {
+		s.deathBurst.Update(time.Second / 60)
+	}
 
 	// Handle debug toggles
 	s.handleDebugToggles()
 
-	// Camera follows player
-	playerCenterX := s.playerBody.PosX + s.playerBody.W/2
-	playerCenterY := s.playerBody.PosY + s.playerBody.H/2
-	s.camera.Follow(playerCenterX, playerCenterY, s.playerBody.W, s.playerBody.H)
-	s.camera.Update(1.0 / 60.0)
-
-	// Update entities
+	// Update entities and tile animations
 	s.entityWorld.Update(1.0 / 60.0)
+	s.renderer.Update(time.Second / 60)
 
-	// Update animator (non-physics)
+	// Fade out the goal message
+	if s.goalMessageTimer > 0 {
+		s.goalMessageTimer -= 1.0 / 60.0
+	}
+
+	// Crossfade music layers from gameplay state
+	gameplay.UpdateMusic(s.music, s.state, s.playerBody, s.entityWorld, 1.0/60.0)
+	gameplay.UpdateSound(s.sounds, s.playerBody)
+
+	// Update animator and sprite effects (non-physics)
 	if s.animator != nil {
 		s.animator.Update(time.Second / 60)
 	}
+	if s.sprite != nil {
+		s.sprite.Update(time.Second / 60)
+	}
 
 	// Update debug text
 	s.updateDebugText()
@@ -420,13 +750,185 @@ func (s *Scene) Update(inp *input.Input) error {
 	return nil
 }
 
+// EnableEditMode lets the edit toggle (F12) pause the game and edit the
+// level in place. Saves go to path, which should hold the level being played.
+func (s *Scene) EnableEditMode(path string) {
+	s.editPath = path
+}
+
+// toggleEditMode enters or leaves edit mode.
+func (s *Scene) toggleEditMode() {
+	if !s.editing {
+		if s.editor == nil {
+			ed, err := runedit.New(s.editPath, s.levelData)
+			if err != nil {
+				logger.Errorf("Failed to start edit mode: %v", err)
+				return
+			}
+			ed.OnTileChanged = s.applyTileEdit
+			s.editor = ed
+		}
+		s.editing = true
+		return
+	}
+
+	s.editing = false
+	if s.editor.Revision() != s.editRevision {
+		s.editRevision = s.editor.Revision()
+		s.reloadEntities()
+	}
+}
+
+// updateEditMode pans the camera with the movement keys and runs the editor.
+func (s *Scene) updateEditMode(inp *input.Input) {
+	speed := editPanSpeed
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		speed *= 3
+	}
+	if inp.Pressed(input.ActionMoveLeft) {
+		s.camera.X -= speed
+	}
+	if inp.Pressed(input.ActionMoveRight) {
+		s.camera.X += speed
+	}
+	if inp.Pressed(input.ActionMoveUp) {
+		s.camera.Y -= speed
+	}
+	if inp.Pressed(input.ActionMoveDown) {
+		s.camera.Y += speed
+	}
+	s.camera.X = clampPan(s.camera.X, s.camera.LevelW, s.camera.ViewportW)
+	s.camera.Y = clampPan(s.camera.Y, s.camera.LevelH, s.camera.ViewportH)
+
+	s.editor.Update(inp, s.camera.X, s.camera.Y)
+}
+
+// clampPan keeps a camera coordinate inside the level.
+func clampPan(v, level float64, viewport int) float64 {
+	maxV := level - float64(viewport)
+	if maxV < 0 {
+		return maxV / 2
+	}
+	return math.Max(0, math.Min(v, maxV))
+}
+
+// applyTileEdit mirrors a painted tile into the live map and collision.
+func (s *Scene) applyTileEdit(layer string, tx, ty, gid int) {
+	if l := s.tileMap.Layer(layer); l != nil {
+		l.SetTile(tx, ty, gid)
+	}
+	if layer == runedit.LayerCollision {
+		s.collisionMap.Grid().SetSolid(tx, ty, gid != 0)
+	}
+}
+
+// reloadEntities respawns the level's entities from the edited level,
+// keeping the player where they are.
+func (s *Scene) reloadEntities() {
+	data, err := s.editor.LevelData()
+	if err != nil {
+		logger.Errorf("Failed to apply edits: %v", err)
+		return
+	}
+	s.levelData = data
+
+	x, y := s.playerBody.PosX, s.playerBody.PosY
+	respawnX, respawnY := s.state.RespawnX, s.state.RespawnY
+	s.entityWorld = entities.NewEntityWorld()
+	s.inspector.Clear()
+	s.loadEntities()
+	s.playerBody.PosX, s.playerBody.PosY = x, y
+	s.playerBody.SavePrevious()
+	s.state.SetRespawnPoint(respawnX, respawnY)
+}
+
+// killPlayer triggers death and flashes the player sprite.
+func (s *Scene) killPlayer() {
+	if !s.state.IsRunning() {
+		return
+	}
+	s.state.TriggerDeath()
+	s.deathBurst = gameplay.NewDeathBurst(s.playerBody, s.tuning.Death)
+	if s.progress != nil {
+		s.progress.Deaths++
+	}
+	s.ruleEngine.ProcessEvent(rules.NewEvent(rules.EventDeath, "", "player"))
+	if s.sprite != nil {
+		s.sprite.FlashWhite(deathFlashDuration)
+	}
+	if s.postfx != nil {
+		s.postfx.Flash(damageColor, damageFlashDuration)
+	}
+}
+
+// damagePlayer applies damage from a source to the player's health, knocking
+// them back, and kills them when it runs out. Returns whether the hit landed.
+func (s *Scene) damagePlayer(amount int, source physics.AABB) bool {
+	if !s.state.IsRunning() {
+		return false
+	}
+	hurt, dead := s.health.Damage(amount)
+	if dead {
+		s.killPlayer()
+		return true
+	}
+	if !hurt {
+		return false
+	}
+	s.health.Knockback(s.playerBody, source)
+	if s.sprite != nil {
+		s.sprite.FlashWhite(hitFlashDuration)
+	}
+	if s.postfx != nil {
+		s.postfx.Flash(damageColor, hitFlashDuration)
+	}
+	return true
+}
+
+// SetPostProcessor implements app.PostFXUser.
+func (s *Scene) SetPostProcessor(p *gfx.PostProcessor) {
+	s.postfx = p
+}
+
+// SetTimestep implements app.TimestepUser, so FixedUpdate steps by the app's
+// configured tick.
+func (s *Scene) SetTimestep(ts *timestep.Timestep) {
+	s.timestep = ts
+}
+
+// SetInterpolation implements app.Interpolator.
+func (s *Scene) SetInterpolation(alpha float64) {
+	s.renderAlpha = alpha
+}
+
+// SetTuning replaces the player's movement tuning.
+func (s *Scene) SetTuning(t game.Tuning) {
+	s.tuning = t
+	s.health = gameplay.NewHealth(t.Health)
+	s.state.SetDeathTuning(t.Death)
+	if s.playerController != nil {
+		s.playerController.Tuning = t
+	}
+}
+
 // respawnPlayer resets player position to the respawn point.
 func (s *Scene) respawnPlayer() {
 	s.playerBody.PosX = s.state.RespawnX
 	s.playerBody.PosY = s.state.RespawnY
 	s.playerBody.VelX = 0
 	s.playerBody.VelY = 0
+	s.playerBody.SavePrevious()
+	s.health.Reset()
+	s.checkpoint.Restore(s.entityWorld, s.progress, s.vars, s.ruleEngine)
 	s.state.FinishRespawn()
+
+	// Behind a fade the camera cuts to the respawn point, otherwise it glides
+	s.camera.Follow(s.playerBody.PosX+s.playerBody.W/2, s.playerBody.PosY+s.playerBody.H/2, s.playerBody.W, s.playerBody.H)
+	if s.state.FadeOutTime > 0 {
+		s.camera.Snap()
+	} else {
+		s.camera.Glide(respawnGlideTime, tween.InOutQuad)
+	}
 }
 
 // handleDebugToggles processes debug key bindings.
@@ -448,7 +950,7 @@ func (s *Scene) handleDebugToggles() {
 	}
 	// Force respawn with R
 	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
-		s.state.TriggerDeath()
+		s.killPlayer()
 	}
 }
 
@@ -465,12 +967,15 @@ func (s *Scene) updateDebugText() {
 		}
 	}
 
-	s.debugText = fmt.Sprintf("pos: (%.1f, %.1f)\nvel: (%.1f, %.1f)\ngrounded: %v\n%s\nstate: %s\nF2: collision | F3: deadzone | F4: state | F5: steps | F6: entities | R: respawn",
+	s.debugText = fmt.Sprintf("pos: (%.1f, %.1f)\nvel: (%.1f, %.1f)\ngrounded: %v\n%s\nstate: %s\nF2: collision | F3: deadzone | F4: state | F5: steps | F6: entities | F7: profiler | F8: pause | F9: step | R: respawn",
 		s.playerBody.PosX, s.playerBody.PosY,
 		s.playerBody.VelX, s.playerBody.VelY,
 		s.playerBody.OnGround,
 		platformInfo,
 		s.state.Current.String())
+	if s.editPath != "" {
+		s.debugText += " | F12: edit"
+	}
 }
 
 // Draw implements app.Scene.Draw.
@@ -478,18 +983,32 @@ func (s *Scene) Draw(screen *ebiten.Image) {
 	// Fill background
 	screen.Fill(backgroundColor)
 
-	// Create render context
-	ctx := world.NewRenderContext(s.camera, screen, 1.0/60.0)
+	// Create render context, drawing between the last two physics steps
+	// (edit mode pans the camera directly and a paused game shows the step
+	// being inspected, so both draw the latest state)
+	view := s.camera
+	if !s.editing && !s.paused {
+		view = s.camera.Interpolated(s.renderAlpha)
+	}
+	ctx := world.NewRenderContext(view, screen, 1.0/60.0)
 	ctx.Debug = s.showDebugEntities
+	if !s.editing && !s.paused {
+		ctx.Alpha = s.renderAlpha
+	}
 
 	// Draw map with camera offset
 	s.renderer.DrawWithContext(screen, ctx)
 
-	// Draw entities
-	s.entityWorld.DrawWithContext(screen, ctx)
+	// Draw entities, and the player at its place in their render order
+	s.entityWorld.DrawWithPlayer(screen, ctx, s.playerBody.AABB(), func() {
+		s.drawPlayer(screen, ctx)
+	})
+	if s.deathBurst != nil {
+		s.deathBurst.Draw(screen, view.X, view.Y)
+	}
 
-	// Draw player
-	s.drawPlayer(screen)
+	// Composite lighting over the world
+	s.lighting.Draw(screen, s.ambientDarkness, s.entityWorld.PointLights(ctx))
 
 	// Draw debug overlays
 	if s.showDebugCollision {
@@ -512,6 +1031,28 @@ func (s *Scene) Draw(screen *ebiten.Image) {
 		s.entityWorld.DrawKinematicsDebug(screen, ctx)
 	}
 
+	// Draw goal requirement message
+	if s.goalMessageTimer > 0 {
+		s.drawGoalMessage(screen)
+	}
+
+	// Draw held keys in levels that have any
+	if s.progress != nil && s.progress.Keys > 0 {
+		entities.DrawKeyCount(screen, float64(s.width)-44, 4, s.progress.KeysHeld())
+	}
+
+	// Draw the variables the level shows on the HUD
+	if s.vars != nil {
+		for i, line := range s.vars.HUDLines(s.tileMap.Properties()) {
+			ebitenutil.DebugPrintAt(screen, line, s.width-120, 24+i*14)
+		}
+	}
+
+	// Draw hearts when the health system is on
+	if s.health.Enabled() {
+		entities.DrawHearts(screen, (float64(s.width)-entities.HeartsWidth(s.health.Max()))/2, 4, s.health.HP, s.health.Max())
+	}
+
 	// Draw state overlay
 	if s.state.IsDead() {
 		s.drawDeathOverlay(screen)
@@ -519,34 +1060,80 @@ func (s *Scene) Draw(screen *ebiten.Image) {
 		s.drawCompleteOverlay(screen)
 	}
 
+	// Fade to black between rooms and around respawns
+	if a := max(s.levels.FadeAlpha(), s.state.ScreenFade()); a > 0 {
+		draw.FillRect(screen, 0, 0, float64(s.width), float64(s.height), draw.Fade(color.Black, a))
+	}
+
+	// Draw the edit overlay in place of the debug text
+	if s.editing {
+		s.editor.Draw(screen, ctx)
+		return
+	}
+
 	// Draw debug text
 	ebitenutil.DebugPrint(screen, s.debugText)
+	if s.paused {
+		s.inspector.Draw(screen, ctx, "Enter: toggle active")
+		s.drawPausedIndicator(screen)
+	}
+}
+
+// updateInspector selects the entity under a left click and toggles its
+// active state with Enter.
+func (s *Scene) updateInspector(inp *input.Input) {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := inp.CursorPosition()
+		s.inspector.Pick(s.entityWorld, s.camera.X+mx, s.camera.Y+my)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		s.inspector.ToggleActive()
+	}
+}
+
+// drawPausedIndicator shows the debug pause controls in the bottom-left
+// corner.
+func (s *Scene) drawPausedIndicator(screen *ebiten.Image) {
+	ebitenutil.DebugPrintAt(screen, "PAUSED | F8: resume | F9: step | click: inspect", 8, s.height-20)
 }
 
-// drawPlayer renders the player sprite or a fallback rectangle.
-func (s *Scene) drawPlayer(screen *ebiten.Image) {
+// drawPlayer renders the player sprite or a fallback rectangle, between its
+// last two physics steps by ctx.Alpha.
+func (s *Scene) drawPlayer(screen *ebiten.Image, ctx *world.RenderContext) {
+	// Blink while invulnerable after a hit
+	if !s.health.Visible() {
+		return
+	}
+
 	// Calculate screen position (center of player body)
-	screenX := s.playerBody.PosX + s.playerBody.W/2 - s.camera.X
-	screenY := s.playerBody.PosY + s.playerBody.H/2 - s.camera.Y
+	drawX, drawY := ctx.WorldToScreen(s.playerBody.Interpolate(ctx.Alpha))
+	screenX := drawX + s.playerBody.W/2
+	screenY := drawY + s.playerBody.H/2
 
 	if s.sprite != nil && s.animator != nil {
 		// Update sprite image from current animation frame
 		s.sprite.Image = s.animator.CurrentFrame()
-		// Update position
+		// Update position, squashed while crouching
 		s.sprite.SetPosition(screenX, screenY)
+		s.sprite.SetScale(0.5, 0.5*s.playerBody.H/playerSize)
+		// Face the movement direction and fade out on death
+		if s.playerBody.VelX < 0 {
+			s.sprite.SetFlipX(true)
+		} else if s.playerBody.VelX > 0 {
+			s.sprite.SetFlipX(false)
+		}
+		s.sprite.SetAlpha(s.state.DeathFade())
 		// Draw sprite
 		s.sprite.Draw(screen)
 	} else {
 		// Fallback: draw a rectangle
-		drawX := s.playerBody.PosX - s.camera.X
-		drawY := s.playerBody.PosY - s.camera.Y
-		ebitenutil.DrawRect(screen, drawX, drawY, s.playerBody.W, s.playerBody.H, playerColor)
+		draw.FillRect(screen, drawX, drawY, s.playerBody.W, s.playerBody.H, playerColor)
 	}
 }
 
 // drawDeathOverlay shows a death message.
 func (s *Scene) drawDeathOverlay(screen *ebiten.Image) {
-	text := "YOU DIED"
+	text := i18n.T("hud.youDied")
 	x := s.width/2 - 30
 	y := s.height/2 - 10
 	ebitenutil.DebugPrintAt(screen, text, x, y)
@@ -554,19 +1141,30 @@ func (s *Scene) drawDeathOverlay(screen *ebiten.Image) {
 
 // drawCompleteOverlay shows a level complete message.
 func (s *Scene) drawCompleteOverlay(screen *ebiten.Image) {
-	text := "LEVEL COMPLETE!"
+	text := i18n.T("hud.levelComplete")
+	if s.progress != nil {
+		text = i18n.T("hud.levelCompleteTime", s.progress.Elapsed)
+	}
 	x := s.width/2 - 50
 	y := s.height/2 - 10
 	ebitenutil.DebugPrintAt(screen, text, x, y)
 }
 
+// drawGoalMessage shows why the goal can't be completed yet.
+func (s *Scene) drawGoalMessage(screen *ebiten.Image) {
+	text := i18n.T("hud.goalLocked", s.goalMessage)
+	x := s.width/2 - len(text)*3
+	y := s.height/2 - 40
+	ebitenutil.DebugPrintAt(screen, text, x, y)
+}
+
 // drawCollisionDebug draws the collision overlay.
 func (s *Scene) drawCollisionDebug(screen *ebiten.Image) {
 	// Draw solid tiles as semi-transparent red rectangles
 	tileSize := 16
 
-	startX := int(s.camera.X) / tileSize
-	startY := int(s.camera.Y) / tileSize
+	startX := world.TileIndex(s.camera.X, tileSize)
+	startY := world.TileIndex(s.camera.Y, tileSize)
 	endX := startX + s.width/tileSize + 2
 	endY := startY + s.height/tileSize + 2
 
@@ -589,7 +1187,7 @@ func (s *Scene) drawCollisionDebug(screen *ebiten.Image) {
 			if s.collisionMap.IsSolidAtTile(tx, ty) {
 				x := float64(tx*tileSize) - s.camera.X
 				y := float64(ty*tileSize) - s.camera.Y
-				ebitenutil.DrawRect(screen, x, y, float64(tileSize), float64(tileSize), collisionColor)
+				draw.FillRect(screen, x, y, float64(tileSize), float64(tileSize), collisionColor)
 			}
 		}
 	}
@@ -601,19 +1199,19 @@ func (s *Scene) drawCollisionDebug(screen *ebiten.Image) {
 	borderWidth := 1.0
 
 	// Top border
-	ebitenutil.DrawRect(screen, playerScreenX, playerScreenY, s.playerBody.W, borderWidth, borderColor)
+	draw.FillRect(screen, playerScreenX, playerScreenY, s.playerBody.W, borderWidth, borderColor)
 	// Bottom border
-	ebitenutil.DrawRect(screen, playerScreenX, playerScreenY+s.playerBody.H-borderWidth, s.playerBody.W, borderWidth, borderColor)
+	draw.FillRect(screen, playerScreenX, playerScreenY+s.playerBody.H-borderWidth, s.playerBody.W, borderWidth, borderColor)
 	// Left border
-	ebitenutil.DrawRect(screen, playerScreenX, playerScreenY, borderWidth, s.playerBody.H, borderColor)
+	draw.FillRect(screen, playerScreenX, playerScreenY, borderWidth, s.playerBody.H, borderColor)
 	// Right border
-	ebitenutil.DrawRect(screen, playerScreenX+s.playerBody.W-borderWidth, playerScreenY, borderWidth, s.playerBody.H, borderColor)
+	draw.FillRect(screen, playerScreenX+s.playerBody.W-borderWidth, playerScreenY, borderWidth, s.playerBody.H, borderColor)
 }
 
 // drawDeadzone visualizes the camera deadzone.
 func (s *Scene) drawDeadzone(screen *ebiten.Image) {
 	// Draw deadzone rectangle in screen space
-	ebitenutil.DrawRect(screen,
+	draw.FillRect(screen,
 		s.camera.DeadzoneX, s.camera.DeadzoneY,
 		s.camera.DeadzoneW, s.camera.DeadzoneH,
 		deadzoneColor)
@@ -629,12 +1227,13 @@ func (s *Scene) drawDeadzone(screen *ebiten.Image) {
 func (s *Scene) drawPlayerState(screen *ebiten.Image) {
 	state := s.playerController.State
 	info := fmt.Sprintf(
-		"Vel: (%.1f, %.1f)\nGrounded: %v\nCoyote: %.0fms\nBuffer: %.0fms\nJumping: %v",
+		"Vel: (%.1f, %.1f)\nGrounded: %v\nCoyote: %.0fms\nBuffer: %.0fms\nJumping: %v\nCrouching: %v",
 		s.playerBody.VelX, s.playerBody.VelY,
 		s.playerBody.OnGround,
 		state.TimeSinceGrounded.Seconds()*1000,
 		state.JumpBufferTime.Seconds()*1000,
 		state.IsJumping,
+		state.Crouching,
 	)
 	ebitenutil.DebugPrintAt(screen, info, s.width-120, 10)
 }
@@ -663,6 +1262,19 @@ func (s *Scene) DebugInfo() string {
 	return s.debugText
 }
 
+// ProfilerStats implements debugui.StatsProvider.ProfilerStats.
+// Draw calls are estimated as one per map chunk, entity and the player.
+func (s *Scene) ProfilerStats() []debugui.Stat {
+	entityCount := len(s.entityWorld.Entities())
+	chunks := s.renderer.DrawCalls()
+	return []debugui.Stat{
+		{Name: "entities", Value: entityCount},
+		{Name: "triggers", Value: len(s.entityWorld.Triggers())},
+		{Name: "map chunks", Value: chunks},
+		{Name: "draw calls ~", Value: chunks + entityCount + 1},
+	}
+}
+
 // DrawDebug implements app.SceneDebugger.DrawDebug.
 func (s *Scene) DrawDebug(screen *ebiten.Image) {
 	// Just use the collision debug overlay
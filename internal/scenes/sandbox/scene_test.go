@@ -0,0 +1,45 @@
+//go:build display
+
+package sandbox
+
+import (
+	"image"
+	"testing"
+
+	"github.com/torsten/GoP/internal/physics"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// infiniteFloorLevel is an infinite map whose floor runs from tile -16 to
+// -1 on row 2, left of the origin.
+const infiniteFloorLevel = `{"width": 10, "height": 10, "tilewidth": 16, "tileheight": 16, "infinite": true, "layers": [
+  {"name": "Collision", "type": "tilelayer", "chunks": [
+    {"x": -16, "y": 0, "width": 16, "height": 3, "data": [
+      0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+      0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+      1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1]}
+  ]}
+]}`
+
+func TestResolveCollisionsLeftOfInfiniteOrigin(t *testing.T) {
+	md, err := world.ParseTiledJSON([]byte(infiniteFloorLevel))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON failed: %v", err)
+	}
+	tileMap := world.NewMap(md, world.NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 16, 16)), 16, 16))
+	s := &Scene{tileMap: tileMap, collisionMap: world.NewCollisionMapFromMap(tileMap, "Collision")}
+
+	// A player standing on the floor at x = -110 sinks into tile -7, 2
+	collisions := s.resolveCollisions(physics.AABB{X: -110, Y: 20, W: 8, H: 14})
+	if len(collisions) != 1 {
+		t.Fatalf("got %d collisions on the floor left of the origin, want 1: %v", len(collisions), collisions)
+	}
+	if c := collisions[0]; c.TileX != -7 || c.TileY != 2 || c.NormalY != -1 {
+		t.Errorf("collision %+v, want tile -7, 2 pushing up", c)
+	}
+
+	// Right of the origin there's no floor
+	if collisions := s.resolveCollisions(physics.AABB{X: 100, Y: 20, W: 8, H: 14}); len(collisions) != 0 {
+		t.Errorf("got %d collisions right of the origin, want none", len(collisions))
+	}
+}
@@ -0,0 +1,232 @@
+package schema
+
+import "sort"
+
+// ObjectType describes the custom properties of one level object type,
+// mirroring the editor's object schemas.
+type ObjectType struct {
+	Type       string
+	Name       string
+	Properties []Property
+}
+
+// Property describes one custom property of an object type.
+type Property struct {
+	Name     string
+	Type     string // "string", "float", "int", "bool", "enum", or "color" (a hex string)
+	Required bool
+	Default  any
+	Min, Max float64  // Range for numbers; both zero means unbounded
+	Options  []string // Allowed values of an enum
+}
+
+// Level returns the schema of a level file: the subset of the Tiled JSON map
+// format the game reads, with the custom properties of each object type.
+func Level(objects []ObjectType) Document {
+	objects = append([]ObjectType(nil), objects...)
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Type < objects[j].Type })
+
+	types := make([]string, len(objects))
+	var rules []any
+	defs := Document{
+		"property":    tiledPropertySchema(),
+		"properties":  Document{"type": "array", "items": Document{"$ref": "#/$defs/property"}},
+		"tileLayer":   tileLayerSchema(),
+		"objectGroup": objectGroupSchema(),
+	}
+	for i, o := range objects {
+		types[i] = o.Type
+		def := o.Type + "Properties"
+		defs[def] = objectPropertiesSchema(o)
+		rules = append(rules, Document{
+			"if":   Document{"properties": Document{"type": Document{"const": o.Type}}},
+			"then": Document{"properties": Document{"properties": Document{"$ref": "#/$defs/" + def}}},
+		})
+	}
+
+	object := Document{
+		"type":     "object",
+		"required": []string{"type", "x", "y"},
+		"properties": Document{
+			"id":         Document{"type": "integer"},
+			"name":       Document{"type": "string"},
+			"type":       Document{"enum": append([]string{""}, types...), "description": "Object type; untyped objects are ignored by the game"},
+			"x":          Document{"type": "number"},
+			"y":          Document{"type": "number"},
+			"width":      Document{"type": "number", "minimum": 0},
+			"height":     Document{"type": "number", "minimum": 0},
+			"visible":    Document{"type": "boolean", "default": true},
+			"properties": Document{"$ref": "#/$defs/properties"},
+		},
+	}
+	if len(rules) > 0 {
+		object["allOf"] = rules
+	}
+	defs["object"] = object
+
+	doc := newDocument(NameLevel, "GoP level",
+		"A Tiled JSON map. Tile layers hold tile IDs (the \"Collision\" layer marks solid tiles); object layers hold gameplay objects.")
+	doc["type"] = "object"
+	doc["required"] = []string{"width", "height", "tilewidth", "tileheight", "layers"}
+	doc["properties"] = Document{
+		"width":      Document{"type": "integer", "minimum": 1},
+		"height":     Document{"type": "integer", "minimum": 1},
+		"tilewidth":  Document{"type": "integer", "minimum": 1},
+		"tileheight": Document{"type": "integer", "minimum": 1},
+		"properties": Document{"$ref": "#/$defs/properties", "description": "Map properties such as budgets, theme, and lighting"},
+		"layers": Document{
+			"type": "array",
+			"items": Document{"oneOf": []any{
+				Document{"$ref": "#/$defs/tileLayer"},
+				Document{"$ref": "#/$defs/objectGroup"},
+				Document{
+					"description": "Other layer kinds are ignored by the game",
+					"properties":  Document{"type": Document{"not": Document{"enum": []string{"tilelayer", "objectgroup"}}}},
+				},
+			}},
+		},
+	}
+	doc["$defs"] = defs
+	return doc
+}
+
+// tiledPropertySchema describes a Tiled custom property entry.
+func tiledPropertySchema() Document {
+	return Document{
+		"type":     "object",
+		"required": []string{"name", "value"},
+		"properties": Document{
+			"name":  Document{"type": "string"},
+			"type":  Document{"enum": []string{"string", "int", "float", "bool", "color", "file", "object", "class"}},
+			"value": Document{},
+		},
+	}
+}
+
+func tileLayerSchema() Document {
+	// Tile IDs, or a base64 string of them with the "base64" encoding
+	data := Document{"oneOf": []any{
+		Document{"type": "array", "items": Document{"type": "integer", "minimum": 0}},
+		Document{"type": "string", "contentEncoding": "base64"},
+	}}
+	return Document{
+		"type":     "object",
+		"required": []string{"name", "type"},
+		"anyOf": []any{
+			Document{"required": []string{"data"}},
+			Document{"required": []string{"chunks"}},
+		},
+		"properties": Document{
+			"name":     Document{"type": "string"},
+			"type":     Document{"const": "tilelayer"},
+			"width":    Document{"type": "integer", "minimum": 0},
+			"height":   Document{"type": "integer", "minimum": 0},
+			"data":     data,
+			"encoding": Document{"enum": []string{"csv", "base64"}},
+			"compression": Document{
+				"enum":        []string{"", "zlib", "gzip"},
+				"description": "Compression of base64 tile data",
+			},
+			"chunks": Document{
+				"type":        "array",
+				"description": "Tiles of infinite maps, in blocks anywhere on the map",
+				"items": Document{
+					"type":     "object",
+					"required": []string{"x", "y", "width", "height", "data"},
+					"properties": Document{
+						"x":      Document{"type": "integer"},
+						"y":      Document{"type": "integer"},
+						"width":  Document{"type": "integer", "minimum": 1},
+						"height": Document{"type": "integer", "minimum": 1},
+						"data":   data,
+					},
+				},
+			},
+			"visible": Document{"type": "boolean"},
+			"opacity": Document{"type": "number", "minimum": 0, "maximum": 1},
+			"tintcolor": Document{
+				"type":        "string",
+				"pattern":     "^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$",
+				"description": "Color the layer's tiles are multiplied with, #RRGGBB or #AARRGGBB",
+			},
+		},
+	}
+}
+
+func objectGroupSchema() Document {
+	return Document{
+		"type":     "object",
+		"required": []string{"name", "type", "objects"},
+		"properties": Document{
+			"name":    Document{"type": "string"},
+			"type":    Document{"const": "objectgroup"},
+			"objects": Document{"type": "array", "items": Document{"$ref": "#/$defs/object"}},
+		},
+	}
+}
+
+// objectPropertiesSchema describes the properties array of one object type.
+// Known properties are checked by value; other names are allowed so levels
+// can carry extra data for tools.
+func objectPropertiesSchema(o ObjectType) Document {
+	known := make([]string, 0, len(o.Properties))
+	items := make([]any, 0, len(o.Properties)+1)
+	var required []any
+	for _, p := range o.Properties {
+		known = append(known, p.Name)
+		items = append(items, Document{
+			"type":     "object",
+			"required": []string{"name", "value"},
+			"properties": Document{
+				"name":  Document{"const": p.Name},
+				"value": valueSchema(p),
+			},
+		})
+		if p.Required {
+			required = append(required, Document{
+				"contains": Document{"properties": Document{"name": Document{"const": p.Name}}},
+			})
+		}
+	}
+	items = append(items, Document{
+		"properties": Document{"name": Document{"not": Document{"enum": known}}},
+	})
+
+	doc := Document{
+		"description": o.Name + " properties",
+		"type":        "array",
+		"items":       Document{"anyOf": items},
+	}
+	if len(required) > 0 {
+		doc["allOf"] = required
+	}
+	return doc
+}
+
+// valueSchema describes a property value.
+func valueSchema(p Property) Document {
+	v := Document{}
+	switch p.Type {
+	case "float":
+		v["type"] = "number"
+	case "int":
+		v["type"] = "integer"
+	case "bool":
+		v["type"] = "boolean"
+	default:
+		v["type"] = "string"
+	}
+	if p.Min != 0 || p.Max != 0 {
+		v["minimum"] = p.Min
+		v["maximum"] = p.Max
+	}
+	if len(p.Options) > 0 {
+		// The game falls back to a default for unknown values and levelcheck
+		// warns about them, so the options are suggested rather than enforced.
+		v["examples"] = append([]string(nil), p.Options...)
+	}
+	if p.Default != nil {
+		v["default"] = p.Default
+	}
+	return v
+}
@@ -0,0 +1,60 @@
+package schema
+
+// Report is a validation report for one or more levels, as written by
+// "leveltool validate -json".
+type Report struct {
+	Version int           `json:"version"`
+	Levels  []LevelReport `json:"levels"`
+}
+
+// LevelReport holds the issues found in one level.
+type LevelReport struct {
+	Path     string  `json:"path"`
+	Errors   []Issue `json:"errors"`
+	Warnings []Issue `json:"warnings"`
+}
+
+// Issue is a single validation issue.
+type Issue struct {
+	Message  string `json:"message"`
+	Object   int    `json:"object"`             // Object index, or -1 for the whole level
+	Property string `json:"property,omitempty"` // Property the issue is about, if any
+}
+
+// NewReport creates an empty report at the current version.
+func NewReport() *Report {
+	return &Report{Version: Version, Levels: []LevelReport{}}
+}
+
+// ReportSchema returns the schema of a validation report.
+func ReportSchema() Document {
+	issue := Document{
+		"type":     "object",
+		"required": []string{"message", "object"},
+		"properties": Document{
+			"message":  Document{"type": "string"},
+			"object":   Document{"type": "integer", "minimum": -1, "description": "Object index, or -1 for the whole level"},
+			"property": Document{"type": "string"},
+		},
+	}
+	level := Document{
+		"type":     "object",
+		"required": []string{"path", "errors", "warnings"},
+		"properties": Document{
+			"path":     Document{"type": "string"},
+			"errors":   Document{"type": "array", "items": Document{"$ref": "#/$defs/issue"}},
+			"warnings": Document{"type": "array", "items": Document{"$ref": "#/$defs/issue"}},
+		},
+	}
+
+	doc := newDocument(NameReport, "GoP validation report",
+		"Validation results for levels. Errors make a level unplayable; warnings don't.")
+	doc["type"] = "object"
+	doc["required"] = []string{"version", "levels"}
+	doc["properties"] = Document{
+		"version": Document{"const": Version},
+		"levels":  Document{"type": "array", "items": Document{"$ref": "#/$defs/level"}},
+	}
+	doc["$defs"] = Document{"issue": issue, "level": level}
+	return doc
+}
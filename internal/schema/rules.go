@@ -0,0 +1,76 @@
+package schema
+
+import "github.com/torsten/GoP/internal/rules"
+
+// Rules returns the schema of a rules file (<level>_rules.yaml, or the same
+// structure as JSON).
+func Rules() Document {
+	action := Document{
+		"type":     "object",
+		"required": []string{"type"},
+		"anyOf":    []Document{{"required": []string{"target"}}, {"required": []string{"group"}}},
+		"properties": Document{
+			"type":   Document{"enum": []string{rules.ActionActivate, rules.ActionDeactivate, rules.ActionToggle}},
+			"target": Document{"type": "string", "description": "ID of the entity to act on, or a pattern like door_* (* and ? wildcards)"},
+			"group":  Document{"type": "string", "description": "Tag to act on every target carrying it (the object's tags property)"},
+			"params": Document{"type": "object", "description": "Optional action parameters"},
+		},
+	}
+	rule := Document{
+		"type":     "object",
+		"required": []string{"id", "when", "actions"},
+		"properties": Document{
+			"id": Document{"type": "string"},
+			"when": Document{
+				"type":     "object",
+				"required": []string{"event"},
+				"properties": Document{
+					"event":  Document{"enum": []string{string(rules.EventEnterRegion), string(rules.EventExitRegion), string(rules.EventDeath), string(rules.EventVarChanged)}},
+					"region": Document{"type": "string", "description": "Trigger ID to match; empty matches any"},
+					"actor":  Document{"type": "string", "description": "Actor type to match, e.g. \"player\"; empty matches any"},
+					"states": Document{
+						"type":                 "object",
+						"description":          "Target IDs and the state each must be in, e.g. a door's closed, opening, open, or closing",
+						"additionalProperties": Document{"type": "string"},
+					},
+					"vars": Document{
+						"type":        "object",
+						"description": "Gameplay variables and the condition each must meet, e.g. gems: \">= 3\" or a plain value to compare with",
+					},
+				},
+			},
+			"actions": Document{"type": "array", "items": Document{"$ref": "#/$defs/action"}},
+			"once":    Document{"type": "boolean", "default": false},
+			"active":  Document{"type": "boolean", "description": "Rules loaded from files are always active"},
+			"priority": Document{
+				"type":        "integer",
+				"default":     0,
+				"description": "Rules with higher priorities are checked first; equal priorities keep the file order",
+			},
+			"exclusive_group": Document{
+				"type":        "string",
+				"description": "Only the first matching rule of the group fires per event, for if/else chains",
+			},
+			"cooldown": Document{
+				"type":        "string",
+				"pattern":     `^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`,
+				"description": "Time after firing before the rule can fire again, e.g. \"3s\" or \"500ms\"",
+			},
+			"max_fires": Document{
+				"type":        "integer",
+				"minimum":     0,
+				"description": "How often the rule can fire; 0 is unlimited",
+			},
+		},
+	}
+
+	doc := newDocument(NameRules, "GoP rules",
+		"Data-driven rules that run actions on entities when events happen.")
+	doc["type"] = "object"
+	doc["required"] = []string{"rules"}
+	doc["properties"] = Document{
+		"rules": Document{"type": "array", "items": Document{"$ref": "#/$defs/rule"}},
+	}
+	doc["$defs"] = Document{"rule": rule, "action": action}
+	return doc
+}
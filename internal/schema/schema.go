@@ -0,0 +1,77 @@
+// Package schema describes GoP's file formats as JSON Schema documents for
+// external tools: the level object format, the rules format, and the
+// validation report written by cmd/leveltool.
+//
+// The documents are versioned. Version changes whenever a document changes
+// in a way that could reject files or reports that were valid before; adding
+// optional fields keeps the version. Each document's $id carries the version.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Version is the schema version shared by all documents.
+const Version = 1
+
+// Draft is the JSON Schema dialect the documents use.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Document names.
+const (
+	NameLevel  = "level"
+	NameRules  = "rules"
+	NameReport = "report"
+)
+
+// Document is a JSON Schema document.
+type Document map[string]any
+
+// ID returns the $id of the named document.
+func ID(name string) string {
+	return fmt.Sprintf("https://github.com/torsten/GoP/schema/v%d/%s.json", Version, name)
+}
+
+// FileName returns the file name a document is written to, e.g. "level.v1.json".
+func FileName(name string) string {
+	return fmt.Sprintf("%s.v%d.json", name, Version)
+}
+
+// JSON encodes the document with stable key order and indentation.
+func (d Document) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// All returns every document by name, with the level document built from
+// the given object types.
+func All(objects []ObjectType) map[string]Document {
+	return map[string]Document{
+		NameLevel:  Level(objects),
+		NameRules:  Rules(),
+		NameReport: ReportSchema(),
+	}
+}
+
+// Names returns the document names in sorted order.
+func Names() []string {
+	names := []string{NameLevel, NameRules, NameReport}
+	sort.Strings(names)
+	return names
+}
+
+// newDocument starts a document with the common header fields.
+func newDocument(name, title, description string) Document {
+	return Document{
+		"$schema":     Draft,
+		"$id":         ID(name),
+		"title":       title,
+		"description": description,
+		"x-version":   Version,
+	}
+}
@@ -0,0 +1,119 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testObjects = []ObjectType{
+	{Type: "door", Name: "Door", Properties: []Property{
+		{Name: "id", Type: "string", Default: ""},
+		{Name: "startOpen", Type: "bool", Default: false},
+	}},
+	{Type: "platform", Name: "Platform", Properties: []Property{
+		{Name: "speed", Type: "float", Default: 100.0, Min: 0, Max: 1000},
+	}},
+}
+
+func TestDocumentsAreVersioned(t *testing.T) {
+	for name, doc := range All(testObjects) {
+		data, err := doc.JSON()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", name, err)
+		}
+		if decoded["$schema"] != Draft {
+			t.Errorf("%s: $schema = %v", name, decoded["$schema"])
+		}
+		if id, _ := decoded["$id"].(string); !strings.Contains(id, "/v1/"+name+".json") {
+			t.Errorf("%s: $id = %q, want versioned id", name, id)
+		}
+		if decoded["x-version"] != float64(Version) {
+			t.Errorf("%s: x-version = %v, want %d", name, decoded["x-version"], Version)
+		}
+	}
+}
+
+func TestDocumentJSONIsStable(t *testing.T) {
+	a, _ := Level(testObjects).JSON()
+	b, _ := Level([]ObjectType{testObjects[1], testObjects[0]}).JSON()
+	if string(a) != string(b) {
+		t.Error("level schema depends on object order")
+	}
+}
+
+func TestLevelDescribesObjectProperties(t *testing.T) {
+	defs := Level(testObjects)["$defs"].(Document)
+
+	types := defs["object"].(Document)["properties"].(Document)["type"].(Document)["enum"].([]string)
+	if strings.Join(types, ",") != ",door,platform" {
+		t.Errorf("object types = %v", types)
+	}
+
+	props, ok := defs["platformProperties"].(Document)
+	if !ok {
+		t.Fatal("missing platformProperties definition")
+	}
+	items := props["items"].(Document)["anyOf"].([]any)
+	if len(items) != 2 {
+		t.Fatalf("got %d property items, want speed plus the fallback", len(items))
+	}
+	speed := items[0].(Document)["properties"].(Document)["value"].(Document)
+	if speed["type"] != "number" || speed["maximum"] != 1000.0 || speed["default"] != 100.0 {
+		t.Errorf("speed value schema = %v", speed)
+	}
+}
+
+func TestValueSchemaOmitsUnboundedRange(t *testing.T) {
+	v := valueSchema(Property{Name: "id", Type: "string"})
+	if _, ok := v["minimum"]; ok {
+		t.Errorf("string property got a range: %v", v)
+	}
+}
+
+func TestValueSchemaSuggestsEnumOptions(t *testing.T) {
+	v := valueSchema(Property{Name: "mode", Type: "enum", Default: "toggle", Options: []string{"toggle", "plate"}})
+	if v["type"] != "string" {
+		t.Errorf("enum type = %v, want string", v["type"])
+	}
+	examples, _ := v["examples"].([]string)
+	if len(examples) != 2 || examples[0] != "toggle" || examples[1] != "plate" {
+		t.Errorf("enum examples = %v, want the options", v["examples"])
+	}
+	if _, ok := v["enum"]; ok {
+		t.Errorf("enum options are enforced: %v", v)
+	}
+}
+
+func TestReportMatchesSchema(t *testing.T) {
+	r := NewReport()
+	r.Levels = append(r.Levels, LevelReport{
+		Path:     "level.json",
+		Errors:   []Issue{{Message: "No player spawn point defined", Object: -1}},
+		Warnings: []Issue{},
+	})
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]any
+	json.Unmarshal(data, &decoded)
+	for _, key := range ReportSchema()["required"].([]string) {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("report is missing required field %q", key)
+		}
+	}
+	level := decoded["levels"].([]any)[0].(map[string]any)
+	issue := level["errors"].([]any)[0].(map[string]any)
+	if _, ok := issue["property"]; ok {
+		t.Error("empty property should be omitted")
+	}
+	if issue["object"] != -1.0 {
+		t.Errorf("object = %v, want -1", issue["object"])
+	}
+}
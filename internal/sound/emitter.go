@@ -0,0 +1,132 @@
+// Package sound provides positional sound emitters placed in levels.
+//
+// Each emitter loops a clip. Positional emitters get quieter with the
+// listener's distance, falling to silence at their radius, and pan toward
+// the side they are on; ambient emitters (no falloff) play at full volume
+// everywhere. The soundscape only decides volumes and pans; playback is done
+// by whatever implements Player (e.g. a wrapped *audio.Player from
+// ebiten/v2/audio).
+package sound
+
+import (
+	"math"
+
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+// Falloff is how an emitter's volume drops with distance.
+type Falloff string
+
+const (
+	// FalloffLinear drops the volume evenly to 0 at the radius.
+	FalloffLinear Falloff = "linear"
+	// FalloffQuadratic drops the volume quickly near the emitter and slowly
+	// toward the radius.
+	FalloffQuadratic Falloff = "quadratic"
+	// FalloffNone plays at full volume everywhere, without panning: an
+	// ambient sound.
+	FalloffNone Falloff = "none"
+)
+
+// ParseFalloff returns the falloff with the given name, or FalloffLinear if
+// the name is unknown.
+func ParseFalloff(name string) Falloff {
+	switch f := Falloff(name); f {
+	case FalloffQuadratic, FalloffNone:
+		return f
+	}
+	return FalloffLinear
+}
+
+// Player is implemented by a playing, looping audio stream.
+type Player interface {
+	SetVolume(volume float64)
+	// SetPan sets the stereo balance, from -1 (left) to 1 (right).
+	SetPan(pan float64)
+}
+
+// Emitter is a looping sound at a point in the level.
+type Emitter struct {
+	Clip    string  // Clip name, resolved by the audio backend
+	X, Y    float64 // Position in world pixels
+	Radius  float64 // Distance at which positional emitters fall silent
+	Volume  float64 // Volume at the emitter, 0-1
+	Falloff Falloff
+
+	player Player
+	volume float64 // Last mixed volume and pan
+	pan    float64
+}
+
+// Mix returns the emitter's volume and pan for a listener at x, y.
+func (e *Emitter) Mix(x, y float64) (volume, pan float64) {
+	vol := mathx.Clamp01(e.Volume)
+	if e.Falloff == FalloffNone {
+		return vol, 0
+	}
+	if e.Radius <= 0 {
+		return 0, 0
+	}
+
+	dx := e.X - x
+	t := math.Hypot(dx, e.Y-y) / e.Radius
+	if t >= 1 {
+		return 0, 0
+	}
+	gain := 1 - t
+	if e.Falloff == FalloffQuadratic {
+		gain *= gain
+	}
+	return vol * gain, mathx.Clamp(dx/e.Radius, -1, 1)
+}
+
+// Mixed returns the volume and pan from the last Soundscape.Update.
+func (e *Emitter) Mixed() (volume, pan float64) {
+	return e.volume, e.pan
+}
+
+// SetPlayer attaches the audio stream that plays the emitter's clip. The
+// player gets the emitter's last mixed volume and pan immediately.
+func (e *Emitter) SetPlayer(p Player) {
+	e.player = p
+	if p != nil {
+		p.SetVolume(e.volume)
+		p.SetPan(e.pan)
+	}
+}
+
+// Soundscape is the set of sound emitters in a level.
+type Soundscape struct {
+	emitters []*Emitter
+}
+
+// NewSoundscape creates an empty soundscape.
+func NewSoundscape() *Soundscape {
+	return &Soundscape{}
+}
+
+// Add adds an emitter.
+func (s *Soundscape) Add(e *Emitter) {
+	s.emitters = append(s.emitters, e)
+}
+
+// Emitters returns the emitters in the order they were added.
+func (s *Soundscape) Emitters() []*Emitter {
+	return s.emitters
+}
+
+// Update mixes every emitter for a listener at x, y and pushes changed
+// volumes and pans to the attached players.
+func (s *Soundscape) Update(x, y float64) {
+	for _, e := range s.emitters {
+		vol, pan := e.Mix(x, y)
+		if vol == e.volume && pan == e.pan {
+			continue
+		}
+		e.volume, e.pan = vol, pan
+		if e.player != nil {
+			e.player.SetVolume(vol)
+			e.player.SetPan(pan)
+		}
+	}
+}
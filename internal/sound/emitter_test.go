@@ -0,0 +1,81 @@
+package sound
+
+import (
+	"math"
+	"testing"
+)
+
+// mockPlayer implements Player for testing.
+type mockPlayer struct {
+	volume, pan float64
+	calls       int
+}
+
+func (p *mockPlayer) SetVolume(volume float64) {
+	p.volume = volume
+	p.calls++
+}
+
+func (p *mockPlayer) SetPan(pan float64) {
+	p.pan = pan
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEmitterFalloff(t *testing.T) {
+	tests := []struct {
+		falloff Falloff
+		x       float64 // Listener position; the emitter is at 0, 0
+		wantVol float64
+		wantPan float64
+	}{
+		{FalloffLinear, 0, 0.8, 0},
+		{FalloffLinear, 50, 0.4, -0.5}, // Emitter on the listener's left
+		{FalloffLinear, -50, 0.4, 0.5},
+		{FalloffLinear, 100, 0, 0},
+		{FalloffQuadratic, 50, 0.2, -0.5},
+		{FalloffNone, 500, 0.8, 0}, // Ambient: everywhere, centered
+	}
+	for _, tt := range tests {
+		e := &Emitter{Radius: 100, Volume: 0.8, Falloff: tt.falloff}
+		vol, pan := e.Mix(tt.x, 0)
+		if !approxEqual(vol, tt.wantVol) || !approxEqual(pan, tt.wantPan) {
+			t.Errorf("%s at %v: got volume %v pan %v, want %v %v", tt.falloff, tt.x, vol, pan, tt.wantVol, tt.wantPan)
+		}
+	}
+}
+
+func TestParseFalloff(t *testing.T) {
+	for name, want := range map[string]Falloff{
+		"quadratic": FalloffQuadratic,
+		"none":      FalloffNone,
+		"":          FalloffLinear,
+		"cubic":     FalloffLinear,
+	} {
+		if got := ParseFalloff(name); got != want {
+			t.Errorf("ParseFalloff(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSoundscapeUpdatesPlayers(t *testing.T) {
+	s := NewSoundscape()
+	e := &Emitter{X: 100, Radius: 100, Volume: 1}
+	s.Add(e)
+	p := &mockPlayer{}
+	e.SetPlayer(p)
+
+	s.Update(50, 0)
+	if !approxEqual(p.volume, 0.5) || !approxEqual(p.pan, 0.5) {
+		t.Errorf("Expected volume 0.5 pan 0.5, got %v %v", p.volume, p.pan)
+	}
+
+	// Unchanged mixes aren't pushed again
+	calls := p.calls
+	s.Update(50, 0)
+	if p.calls != calls {
+		t.Errorf("Expected no SetVolume for an unchanged mix, got %d more", p.calls-calls)
+	}
+}
@@ -0,0 +1,71 @@
+// Package storage reads and writes the game's files by path through a
+// Backend: the OS file system on desktop, the browser's localStorage in the
+// WebAssembly build (GOOS=js). Saves, settings, config and tuning files, and
+// levels saved by the editors all go through it, so they work in both.
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// Backend stores files by path.
+type Backend interface {
+	// ReadFile returns the contents of the file at name. A missing file
+	// returns an error wrapping fs.ErrNotExist.
+	ReadFile(name string) ([]byte, error)
+	// WriteFile replaces the file at name. Its directory must exist.
+	WriteFile(name string, data []byte) error
+	// MkdirAll creates the directory dir and any parents it needs.
+	MkdirAll(dir string) error
+}
+
+// Default is the backend of the platform the game runs on.
+var Default Backend = platformBackend()
+
+// ReadFile reads the file at name from Default. Files missing there are
+// read from the assets bundled into the build, if it has any (the browser
+// build does), so paths like "assets/levels/level_01.json" work everywhere.
+func ReadFile(name string) ([]byte, error) {
+	data, err := Default.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if bundled, ok := readBundled(name); ok {
+			return bundled, nil
+		}
+	}
+	return data, err
+}
+
+// WriteFile writes the file at name to Default.
+func WriteFile(name string, data []byte) error {
+	return Default.WriteFile(name, data)
+}
+
+// MkdirAll creates the directory dir in Default.
+func MkdirAll(dir string) error {
+	return Default.MkdirAll(dir)
+}
+
+// ConfigDir returns the directory for the user's saves and settings.
+func ConfigDir() (string, error) {
+	return configDir()
+}
+
+// OS is the Backend for the OS file system.
+type OS struct{}
+
+// ReadFile implements Backend.ReadFile.
+func (OS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// WriteFile implements Backend.WriteFile.
+func (OS) WriteFile(name string, data []byte) error {
+	return os.WriteFile(name, data, 0o644)
+}
+
+// MkdirAll implements Backend.MkdirAll.
+func (OS) MkdirAll(dir string) error {
+	return os.MkdirAll(dir, 0o755)
+}
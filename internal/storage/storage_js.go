@@ -0,0 +1,102 @@
+//go:build js
+
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"syscall/js"
+	"unicode/utf8"
+
+	bundle "github.com/torsten/GoP/assets"
+)
+
+// Browser is true in the browser build, where files are kept in
+// localStorage instead of on disk.
+const Browser = true
+
+func platformBackend() Backend {
+	return LocalStorage{Prefix: "gop:"}
+}
+
+// The browser has no config directory; save and settings paths are just
+// localStorage keys.
+func configDir() (string, error) {
+	return "GoP", nil
+}
+
+// LocalStorage is the Backend for the browser's localStorage. Files are
+// items keyed by Prefix and their cleaned path. localStorage holds strings,
+// so only text files (JSON, YAML) can be stored.
+type LocalStorage struct {
+	Prefix string
+}
+
+// key returns the item key for the file at name.
+func (s LocalStorage) key(name string) string {
+	return s.Prefix + path.Clean(strings.TrimPrefix(name, "./"))
+}
+
+// ReadFile implements Backend.ReadFile.
+func (s LocalStorage) ReadFile(name string) ([]byte, error) {
+	item := js.Global().Get("localStorage").Call("getItem", s.key(name))
+	if item.IsNull() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return []byte(item.String()), nil
+}
+
+// WriteFile implements Backend.WriteFile.
+func (s LocalStorage) WriteFile(name string, data []byte) (err error) {
+	if !utf8.Valid(data) {
+		return &fs.PathError{Op: "write", Path: name, Err: fmt.Errorf("localStorage only holds text")}
+	}
+	// setItem throws when the storage quota is used up
+	defer func() {
+		if r := recover(); r != nil {
+			err = &fs.PathError{Op: "write", Path: name, Err: fmt.Errorf("%v", r)}
+		}
+	}()
+	js.Global().Get("localStorage").Call("setItem", s.key(name), string(data))
+	return nil
+}
+
+// MkdirAll implements Backend.MkdirAll. Keys are whole paths, so there are
+// no directories to create.
+func (LocalStorage) MkdirAll(dir string) error {
+	return nil
+}
+
+// bundledPath returns the path in the asset bundle of the file at name,
+// which must be under assets/.
+func bundledPath(name string) (string, bool) {
+	name = path.Clean(strings.TrimPrefix(name, "./"))
+	if name == "assets" {
+		return ".", true
+	}
+	rest, ok := strings.CutPrefix(name, "assets/")
+	return rest, ok
+}
+
+func readBundled(name string) ([]byte, bool) {
+	p, ok := bundledPath(name)
+	if !ok {
+		return nil, false
+	}
+	data, err := fs.ReadFile(bundle.FS, p)
+	return data, err == nil
+}
+
+// DirFS returns the files under dir. The browser reads them from the asset
+// bundle, so dir must be assets/ or a directory in it.
+func DirFS(dir string) fs.FS {
+	if p, ok := bundledPath(dir); ok {
+		if sub, err := fs.Sub(bundle.FS, p); err == nil {
+			return sub
+		}
+	}
+	return os.DirFS(dir)
+}
@@ -0,0 +1,35 @@
+//go:build !js
+
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Browser is true in the browser build, where files are kept in
+// localStorage instead of on disk.
+const Browser = false
+
+func platformBackend() Backend {
+	return OS{}
+}
+
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "GoP"), nil
+}
+
+// Desktop builds load assets from disk and bundle none.
+func readBundled(name string) ([]byte, bool) {
+	return nil, false
+}
+
+// DirFS returns the files under dir.
+func DirFS(dir string) fs.FS {
+	return os.DirFS(dir)
+}
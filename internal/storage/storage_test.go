@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+)
+
+// memory is a Backend keeping files in a map.
+type memory map[string][]byte
+
+func (m memory) ReadFile(name string) ([]byte, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return data, nil
+}
+
+func (m memory) WriteFile(name string, data []byte) error {
+	m[name] = data
+	return nil
+}
+
+func (m memory) MkdirAll(dir string) error {
+	return nil
+}
+
+func TestOSWriteFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "dir")
+	path := filepath.Join(dir, "save.json")
+	if err := (OS{}).WriteFile(path, []byte("{}")); err == nil {
+		t.Error("WriteFile into a missing directory succeeded, want error")
+	}
+	if err := (OS{}).MkdirAll(dir); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := (OS{}).WriteFile(path, []byte("{}")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := OS{}.ReadFile(path)
+	if err != nil || string(data) != "{}" {
+		t.Errorf("ReadFile = %q, %v, want {}", data, err)
+	}
+}
+
+func TestOSReadMissingFile(t *testing.T) {
+	_, err := OS{}.ReadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("err = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestDefaultBackend(t *testing.T) {
+	defer func(b Backend) { Default = b }(Default)
+	m := memory{}
+	Default = m
+
+	if err := WriteFile("GoP/settings.json", []byte(`{"fullscreen":true}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["GoP/settings.json"]; !ok {
+		t.Error("WriteFile didn't go to Default")
+	}
+	data, err := ReadFile("GoP/settings.json")
+	if err != nil || string(data) != `{"fullscreen":true}` {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	if _, err := ReadFile("GoP/missing.json"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile of a missing file: err = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,75 @@
+package theme
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/assets"
+	"github.com/torsten/GoP/internal/entities"
+	"github.com/torsten/GoP/internal/gfx"
+	"github.com/torsten/GoP/internal/world"
+)
+
+// Skins resolves the theme into entity skins, loading images and the atlas from fsys.
+// Entity types missing from the theme have no skin and keep their built-in look.
+func (t *Theme) Skins(fsys fs.FS) (map[world.ObjectType]*entities.Skin, error) {
+	var atlas *assets.Atlas
+	if t.Atlas != "" {
+		var err error
+		atlas, err = assets.LoadAtlas(fsys, t.Atlas)
+		if err != nil {
+			return nil, fmt.Errorf("theme %q: %w", t.Name, err)
+		}
+	}
+
+	skins := make(map[world.ObjectType]*entities.Skin, len(t.Entities))
+	for typ, entry := range t.Entities {
+		skin := &entities.Skin{}
+
+		// Colors were validated by ParseYAML
+		if entry.Color != "" {
+			skin.Color, _ = gfx.ParseHexColor(entry.Color)
+		}
+		if entry.ActiveColor != "" {
+			skin.ActiveColor, _ = gfx.ParseHexColor(entry.ActiveColor)
+		}
+
+		var err error
+		if skin.Image, err = resolveImage(fsys, atlas, entry.Image, entry.Frame); err != nil {
+			return nil, fmt.Errorf("theme %q, entity %q: %w", t.Name, typ, err)
+		}
+		if skin.ActiveImage, err = resolveImage(fsys, atlas, entry.ActiveImage, entry.ActiveFrame); err != nil {
+			return nil, fmt.Errorf("theme %q, entity %q: %w", t.Name, typ, err)
+		}
+
+		skins[world.ObjectType(typ)] = skin
+	}
+
+	return skins, nil
+}
+
+// resolveImage returns the atlas frame if one is named, otherwise loads the image path.
+// Returns nil if neither is set.
+func resolveImage(fsys fs.FS, atlas *assets.Atlas, imagePath, frame string) (*ebiten.Image, error) {
+	if frame != "" {
+		img := atlas.Frame(frame)
+		if img == nil {
+			return nil, fmt.Errorf("atlas has no frame %q", frame)
+		}
+		return img, nil
+	}
+	if imagePath != "" {
+		return assets.LoadImage(fsys, imagePath)
+	}
+	return nil, nil
+}
+
+// LoadSkins loads the named theme from fsys and resolves its skins.
+func LoadSkins(fsys fs.FS, name string) (map[world.ObjectType]*entities.Skin, error) {
+	t, err := Load(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return t.Skins(fsys)
+}
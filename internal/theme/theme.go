@@ -0,0 +1,90 @@
+// Package theme provides data-driven entity skins per level.
+// A theme maps entity types to colors and sprites, so the same object data
+// can render with different art per world (e.g. "cave", "factory").
+package theme
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	"github.com/torsten/GoP/internal/gfx"
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is the assets subdirectory containing theme files.
+const Dir = "themes"
+
+// PropTheme is the map property selecting a level's theme by name.
+const PropTheme = "theme"
+
+// NameForLevel returns the theme name set in the level's map properties,
+// or "" if the level uses the built-in look.
+func NameForLevel(props map[string]any) string {
+	name, _ := props[PropTheme].(string)
+	return name
+}
+
+// Theme describes how each entity type is drawn in a level.
+type Theme struct {
+	// Name is the theme identifier, e.g. "cave"
+	Name string `yaml:"name"`
+	// Atlas is an optional atlas manifest path (relative to assets) for Frame lookups
+	Atlas string `yaml:"atlas,omitempty"`
+	// Entities maps object types ("door", "hazard", ...) to their skin
+	Entities map[string]EntitySkin `yaml:"entities"`
+}
+
+// EntitySkin is the theme entry for a single entity type.
+// The "active" variants are used for the entity's alternate state
+// (door open, switch used, checkpoint reached).
+type EntitySkin struct {
+	Color       string `yaml:"color,omitempty"`       // Fill color as #RRGGBB or #RRGGBBAA
+	ActiveColor string `yaml:"activeColor,omitempty"` // Fill color in the alternate state
+	Image       string `yaml:"image,omitempty"`       // Image path relative to assets
+	ActiveImage string `yaml:"activeImage,omitempty"` // Image path in the alternate state
+	Frame       string `yaml:"frame,omitempty"`       // Frame name in the theme atlas
+	ActiveFrame string `yaml:"activeFrame,omitempty"` // Atlas frame in the alternate state
+}
+
+// ParseYAML parses a theme from YAML data.
+// Colors are validated so mistakes are reported at load time.
+func ParseYAML(data []byte) (*Theme, error) {
+	var t Theme
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse theme: %w", err)
+	}
+
+	for typ, skin := range t.Entities {
+		for _, hex := range []string{skin.Color, skin.ActiveColor} {
+			if hex == "" {
+				continue
+			}
+			if _, err := gfx.ParseHexColor(hex); err != nil {
+				return nil, fmt.Errorf("theme %q, entity %q: %w", t.Name, typ, err)
+			}
+		}
+		if (skin.Frame != "" || skin.ActiveFrame != "") && t.Atlas == "" {
+			return nil, fmt.Errorf("theme %q, entity %q: frame set but theme has no atlas", t.Name, typ)
+		}
+	}
+
+	return &t, nil
+}
+
+// Load loads the theme with the given name from themes/<name>.yaml in fsys.
+func Load(fsys fs.FS, name string) (*Theme, error) {
+	data, err := fs.ReadFile(fsys, path.Join(Dir, name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theme %s: %w", name, err)
+	}
+
+	t, err := ParseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	if t.Name == "" {
+		t.Name = name
+	}
+	return t, nil
+}
@@ -0,0 +1,77 @@
+//go:build display
+
+package theme
+
+import (
+	"image/color"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/torsten/GoP/internal/world"
+)
+
+func TestLoadBuiltinThemes(t *testing.T) {
+	fsys := os.DirFS("../../assets")
+	for _, name := range []string{"cave", "factory"} {
+		th, err := Load(fsys, name)
+		if err != nil {
+			t.Fatalf("Load(%s) failed: %v", name, err)
+		}
+		if th.Name != name || th.Entities["switch"].Color == "" || th.Entities["switch"].ActiveColor == "" {
+			t.Errorf("theme %s = %+v, want its name and switch colors", name, th)
+		}
+		if _, err := th.Skins(fsys); err != nil {
+			t.Errorf("Skins(%s) failed: %v", name, err)
+		}
+	}
+}
+
+func TestLoadFallsBack(t *testing.T) {
+	fsys := fstest.MapFS{"themes/moss.yaml": {Data: []byte("entities:\n  door:\n    color: \"#336633\"\n")}}
+	th, err := Load(fsys, "moss")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if th.Name != "moss" {
+		t.Errorf("theme without a name is called %q, want the file's name moss", th.Name)
+	}
+
+	skins, err := th.Skins(fsys)
+	if err != nil {
+		t.Fatalf("Skins failed: %v", err)
+	}
+	door := skins[world.ObjectTypeDoor]
+	if door == nil || door.Color != (color.RGBA{0x33, 0x66, 0x33, 0xff}) || door.ActiveColor.A != 0 || door.Image != nil {
+		t.Errorf("door skin %+v, want only a color", door)
+	}
+	if _, ok := skins[world.ObjectTypeSwitch]; ok {
+		t.Error("switch missing from the theme got a skin instead of its built-in look")
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string // Part of the error
+	}{
+		{"malformed YAML", "name: [cave\nentities: {", "failed to parse theme"},
+		{"wrong shape", "entities: [door, switch]", "failed to parse theme"},
+		{"bad color", "name: cave\nentities:\n  door:\n    color: \"#GG0000\"\n", `entity "door"`},
+		{"frame without atlas", "name: cave\nentities:\n  door:\n    frame: door_01\n", "no atlas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th, err := ParseYAML([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseYAML = %+v, %v; want an error with %q", th, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load(fstest.MapFS{}, "missing"); err == nil {
+		t.Error("Load of a missing theme succeeded")
+	}
+}
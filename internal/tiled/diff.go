@@ -0,0 +1,295 @@
+package tiled
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff is what changed between two versions of a map. Its JSON form is the
+// report of leveltool diff -json.
+type Diff struct {
+	Size       *SizeChange      `json:"size,omitempty"`
+	Properties []PropertyChange `json:"properties,omitempty"` // Map properties
+	Layers     []LayerDiff      `json:"layers,omitempty"`     // Changed tile layers
+	Objects    []ObjectChange   `json:"objects,omitempty"`    // By object ID
+}
+
+// SizeChange is a change of the map size in tiles.
+type SizeChange struct {
+	OldWidth  int `json:"oldWidth"`
+	OldHeight int `json:"oldHeight"`
+	NewWidth  int `json:"newWidth"`
+	NewHeight int `json:"newHeight"`
+}
+
+// PropertyChange is a custom property that was added, removed, or changed.
+// Old or New is nil when the property is missing on that side.
+type PropertyChange struct {
+	Name string `json:"name"`
+	Old  any    `json:"old"`
+	New  any    `json:"new"`
+}
+
+// Change kinds of layers and objects.
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+// LayerDiff is a tile layer whose tiles or properties changed, or that was
+// added or removed.
+type LayerDiff struct {
+	Name       string           `json:"name"`
+	Change     string           `json:"change"`  // Added, Removed, or Modified
+	Tiles      int              `json:"tiles"`   // Number of changed tiles
+	Regions    []TileRegion     `json:"regions"` // Where they are
+	Properties []PropertyChange `json:"properties,omitempty"`
+}
+
+// TileRegion is the bounding box, in tiles, of a group of changed tiles
+// touching each other (also diagonally).
+type TileRegion struct {
+	X       int `json:"x"`
+	Y       int `json:"y"`
+	W       int `json:"w"`
+	H       int `json:"h"`
+	Changed int `json:"changed"` // Changed tiles in the box
+}
+
+// ObjectChange is an object that was added, removed, or modified. Objects
+// are matched by ID; Fields lists the changed fields of modified objects,
+// including "layer" when it moved to another object group.
+type ObjectChange struct {
+	ID         int              `json:"id"`
+	Type       string           `json:"type"`
+	Name       string           `json:"name,omitempty"`
+	X          float64          `json:"x"` // Position, before for removed objects
+	Y          float64          `json:"y"`
+	Change     string           `json:"change"` // Added, Removed, or Modified
+	Fields     []FieldChange    `json:"fields,omitempty"`
+	Properties []PropertyChange `json:"properties,omitempty"`
+}
+
+// FieldChange is a changed object field.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Empty reports whether nothing changed.
+func (d *Diff) Empty() bool {
+	return d.Size == nil && len(d.Properties) == 0 && len(d.Layers) == 0 && len(d.Objects) == 0
+}
+
+// Compare returns what changed from before to after. Tiles are compared by
+// position, so a resized map reports the tiles it gained or lost as
+// changed. Tile layers are matched by name and objects by ID.
+func Compare(before, after *Map) *Diff {
+	d := &Diff{Properties: compareProperties(before.Properties, after.Properties)}
+	if before.Width != after.Width || before.Height != after.Height {
+		d.Size = &SizeChange{OldWidth: before.Width, OldHeight: before.Height, NewWidth: after.Width, NewHeight: after.Height}
+	}
+
+	for _, ol := range before.Layers {
+		if ol.Type != LayerTiles {
+			continue
+		}
+		nl := after.Layer(ol.Name)
+		if nl == nil || nl.Type != LayerTiles {
+			d.Layers = append(d.Layers, compareLayers(&ol, &Layer{Name: ol.Name}, Removed))
+			continue
+		}
+		if ld := compareLayers(&ol, nl, Modified); ld.Tiles > 0 || len(ld.Properties) > 0 {
+			d.Layers = append(d.Layers, ld)
+		}
+	}
+	for _, nl := range after.Layers {
+		if ol := before.Layer(nl.Name); nl.Type == LayerTiles && (ol == nil || ol.Type != LayerTiles) {
+			d.Layers = append(d.Layers, compareLayers(&Layer{Name: nl.Name}, &nl, Added))
+		}
+	}
+
+	d.Objects = compareObjects(before, after)
+	return d
+}
+
+// compareLayers compares the tiles and properties of two versions of a
+// tile layer.
+func compareLayers(before, after *Layer, change string) LayerDiff {
+	ld := LayerDiff{
+		Name:       after.Name,
+		Change:     change,
+		Regions:    []TileRegion{},
+		Properties: compareProperties(before.Properties, after.Properties),
+	}
+
+	// Chunked layers of infinite maps may start left of or above the origin
+	x1, y1 := min(before.StartX, after.StartX), min(before.StartY, after.StartY)
+	x2 := max(before.StartX+before.Width, after.StartX+after.Width)
+	y2 := max(before.StartY+before.Height, after.StartY+after.Height)
+	w, h := x2-x1, y2-y1
+	changed := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if before.TileAt(x1+x, y1+y) != after.TileAt(x1+x, y1+y) {
+				changed[y*w+x] = true
+				ld.Tiles++
+			}
+		}
+	}
+	ld.Regions = changedRegions(changed, w, h)
+	for i := range ld.Regions {
+		ld.Regions[i].X += x1
+		ld.Regions[i].Y += y1
+	}
+	return ld
+}
+
+// changedRegions groups the changed tiles of a w×h grid into regions of
+// tiles touching each other, ordered top to bottom, left to right.
+func changedRegions(changed []bool, w, h int) []TileRegion {
+	regions := []TileRegion{}
+	seen := make([]bool, len(changed))
+	var stack []int
+	for start := range changed {
+		if !changed[start] || seen[start] {
+			continue
+		}
+		r := TileRegion{X: start % w, Y: start / w, W: 1, H: 1}
+		x2, y2 := r.X, r.Y
+		seen[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%w, i/w
+			r.Changed++
+			r.X, r.Y = min(r.X, x), min(r.Y, y)
+			x2, y2 = max(x2, x), max(y2, y)
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= w || ny >= h {
+						continue
+					}
+					if j := ny*w + nx; changed[j] && !seen[j] {
+						seen[j] = true
+						stack = append(stack, j)
+					}
+				}
+			}
+		}
+		r.W, r.H = x2-r.X+1, y2-r.Y+1
+		regions = append(regions, r)
+	}
+	return regions
+}
+
+// placedObject is an object and the object group it's in.
+type placedObject struct {
+	Object
+	layer string
+}
+
+// objectsByID returns the map's objects by ID.
+func objectsByID(m *Map) map[int]placedObject {
+	objects := make(map[int]placedObject)
+	for _, l := range m.Layers {
+		for _, o := range l.Objects {
+			objects[o.ID] = placedObject{o, l.Name}
+		}
+	}
+	return objects
+}
+
+// compareObjects compares the objects of two versions of a map, ordered
+// by ID.
+func compareObjects(before, after *Map) []ObjectChange {
+	oldObjects, newObjects := objectsByID(before), objectsByID(after)
+	ids := make([]int, 0, len(oldObjects)+len(newObjects))
+	for id := range oldObjects {
+		ids = append(ids, id)
+	}
+	for id := range newObjects {
+		if _, ok := oldObjects[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	var changes []ObjectChange
+	for _, id := range ids {
+		o, inOld := oldObjects[id]
+		n, inNew := newObjects[id]
+		switch {
+		case !inNew:
+			changes = append(changes, ObjectChange{ID: id, Type: o.Type, Name: o.Name, X: o.X, Y: o.Y, Change: Removed})
+		case !inOld:
+			changes = append(changes, ObjectChange{ID: id, Type: n.Type, Name: n.Name, X: n.X, Y: n.Y, Change: Added})
+		default:
+			c := ObjectChange{ID: id, Type: n.Type, Name: n.Name, X: n.X, Y: n.Y, Change: Modified}
+			field := func(name string, before, after any) {
+				if before != after {
+					c.Fields = append(c.Fields, FieldChange{Field: name, Old: before, New: after})
+				}
+			}
+			field("layer", o.layer, n.layer)
+			field("type", o.Type, n.Type)
+			field("name", o.Name, n.Name)
+			field("x", o.X, n.X)
+			field("y", o.Y, n.Y)
+			field("width", o.Width, n.Width)
+			field("height", o.Height, n.Height)
+			field("rotation", o.Rotation, n.Rotation)
+			field("visible", o.Visible, n.Visible)
+			c.Properties = compareProperties(o.Properties, n.Properties)
+			if len(c.Fields) > 0 || len(c.Properties) > 0 {
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// compareProperties returns the properties added, removed, or changed
+// from before to after, ordered by name.
+func compareProperties(before, after []Property) []PropertyChange {
+	values := func(props []Property) map[string]any {
+		m := make(map[string]any, len(props))
+		for _, p := range props {
+			m[p.Name] = p.Value
+		}
+		return m
+	}
+	oldValues, newValues := values(before), values(after)
+
+	var names []string
+	for name := range oldValues {
+		names = append(names, name)
+	}
+	for name := range newValues {
+		if _, ok := oldValues[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []PropertyChange
+	for _, name := range names {
+		o, inOld := oldValues[name]
+		n, inNew := newValues[name]
+		if inOld && inNew && reflect.DeepEqual(o, n) {
+			continue
+		}
+		changes = append(changes, PropertyChange{Name: name, Old: o, New: n})
+	}
+	return changes
+}
+
+// String formats the region as "x,y WxH".
+func (r TileRegion) String() string {
+	return fmt.Sprintf("%d,%d %dx%d", r.X, r.Y, r.W, r.H)
+}
@@ -0,0 +1,126 @@
+package tiled
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Crop cuts the map to the w×h tile area whose top-left tile is (x, y).
+// The area may extend past the map edges, which grows the map with empty
+// tiles. Objects are shifted with the tiles; objects whose top-left corner
+// ends up outside the new map are removed. Returns the number removed.
+// Infinite maps have no size to crop to.
+func (m *Map) Crop(x, y, w, h int) (int, error) {
+	if w <= 0 || h <= 0 {
+		return 0, fmt.Errorf("invalid size %dx%d", w, h)
+	}
+	if m.Infinite {
+		return 0, fmt.Errorf("infinite maps can't be cropped or resized")
+	}
+
+	dx := float64(x * m.TileWidth)
+	dy := float64(y * m.TileHeight)
+	maxX := float64(w * m.TileWidth)
+	maxY := float64(h * m.TileHeight)
+	removed := 0
+
+	for i := range m.Layers {
+		l := &m.Layers[i]
+		switch l.Type {
+		case LayerTiles:
+			l.Data = cropTiles(l.Data, l.Width, l.Height, x, y, w, h)
+			l.Width = w
+			l.Height = h
+		case LayerObjects:
+			kept := l.Objects[:0]
+			for _, obj := range l.Objects {
+				obj.X -= dx
+				obj.Y -= dy
+				if obj.X < 0 || obj.Y < 0 || obj.X >= maxX || obj.Y >= maxY {
+					removed++
+					continue
+				}
+				kept = append(kept, obj)
+			}
+			l.Objects = kept
+		}
+	}
+
+	m.Width = w
+	m.Height = h
+	return removed, nil
+}
+
+// Resize changes the map size to w×h tiles, keeping the top-left corner.
+func (m *Map) Resize(w, h int) (int, error) {
+	return m.Crop(0, 0, w, h)
+}
+
+// cropTiles copies the w×h area at (x, y) out of a width×height grid.
+// Cells outside the source grid are empty.
+func cropTiles(data []int, width, height, x, y, w, h int) []int {
+	out := make([]int, w*h)
+	for row := 0; row < h; row++ {
+		sy := row + y
+		if sy < 0 || sy >= height {
+			continue
+		}
+		for col := 0; col < w; col++ {
+			sx := col + x
+			if sx < 0 || sx >= width || sy*width+sx >= len(data) {
+				continue
+			}
+			out[row*w+col] = data[sy*width+sx]
+		}
+	}
+	return out
+}
+
+// TileUsage counts how often each GID appears in a tile layer, skipping empty tiles.
+func (l *Layer) TileUsage() map[int]int {
+	usage := make(map[int]int)
+	count := func(data []int) {
+		for _, gid := range data {
+			if gid != 0 {
+				usage[gid]++
+			}
+		}
+	}
+	count(l.Data)
+	for _, c := range l.Chunks {
+		count(c.Data)
+	}
+	return usage
+}
+
+// StripProperties removes the named custom properties from the map, its
+// layers and its objects. Returns the number removed.
+func (m *Map) StripProperties(names ...string) int {
+	strip := func(props []Property) ([]Property, int) {
+		kept := props[:0]
+		for _, p := range props {
+			if !slices.Contains(names, p.Name) {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		return kept, len(props) - len(kept)
+	}
+
+	removed := 0
+	var n int
+	m.Properties, n = strip(m.Properties)
+	removed += n
+	for i := range m.Layers {
+		l := &m.Layers[i]
+		l.Properties, n = strip(l.Properties)
+		removed += n
+		for j := range l.Objects {
+			l.Objects[j].Properties, n = strip(l.Objects[j].Properties)
+			removed += n
+		}
+	}
+	return removed
+}
@@ -0,0 +1,107 @@
+package tiled
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Stress level tiles: the game tileset's grass and dirt.
+const (
+	stressGrass = 1
+	stressDirt  = 2
+)
+
+// stressObjects are the object types a stress level cycles through, with
+// their sizes in pixels.
+var stressObjects = []struct {
+	typ  string
+	w, h float64
+}{
+	{"collectible", 16, 16},
+	{"hazard", 32, 16},
+	{"checkpoint", 32, 64},
+	{"light", 16, 16},
+}
+
+// StressMap generates a width x height tile level with the given number
+// of objects besides its spawn and goal, for benchmarks and for trying the
+// game and editor on huge levels. The ground runs along the bottom with
+// platforms above it; objects are collectibles, hazards, checkpoints and
+// lights spread over the level. The same seed gives the same level.
+func StressMap(width, height, objects int, seed int64) (*Map, error) {
+	if width < 8 || height < 8 {
+		return nil, fmt.Errorf("stress levels are at least 8x8 tiles, not %dx%d", width, height)
+	}
+	if objects < 0 {
+		return nil, fmt.Errorf("negative object count %d", objects)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	const tileSize = 16
+
+	tiles := make([]int, width*height)
+	for x := 0; x < width; x++ {
+		tiles[(height-2)*width+x] = stressGrass
+		tiles[(height-1)*width+x] = stressDirt
+	}
+	// A platform every 8 columns, 3 to 6 tiles long, somewhere above the
+	// ground
+	for x := 4; x+6 < width; x += 8 {
+		y := 2 + rng.Intn(height-6)
+		for i := rng.Intn(4) + 3; i > 0; i-- {
+			tiles[y*width+x+i] = stressGrass
+		}
+	}
+	collision := make([]int, len(tiles))
+	for i, id := range tiles {
+		if id != 0 {
+			collision[i] = 1
+		}
+	}
+
+	groundY := float64((height - 2) * tileSize)
+	objs := []Object{
+		{ID: 1, Type: "spawn", X: 2 * tileSize, Y: groundY - 32, Width: 32, Height: 32, Visible: true},
+		{ID: 2, Type: "goal", X: float64((width - 3) * tileSize), Y: groundY - 64, Width: 32, Height: 64, Visible: true},
+	}
+	for i := 0; i < objects; i++ {
+		kind := stressObjects[i%len(stressObjects)]
+		o := Object{
+			ID:      len(objs) + 1,
+			Name:    fmt.Sprintf("%s_%d", kind.typ, i),
+			Type:    kind.typ,
+			X:       float64(rng.Intn(width*tileSize - int(kind.w))),
+			Y:       float64(rng.Intn(int(groundY - kind.h))),
+			Width:   kind.w,
+			Height:  kind.h,
+			Visible: true,
+		}
+		if kind.typ == "checkpoint" {
+			o.Properties = []Property{{Name: "id", Type: "string", Value: fmt.Sprintf("cp_%d", i)}}
+		}
+		objs = append(objs, o)
+	}
+
+	return &Map{
+		CompressionLevel: -1,
+		Height:           height,
+		Layers: []Layer{
+			{Data: tiles, Height: height, ID: 1, Name: "Tiles", Opacity: 1, Type: LayerTiles, Visible: true, Width: width},
+			{Data: collision, Height: height, ID: 2, Name: "Collision", Opacity: 1, Type: LayerTiles, Visible: true, Width: width},
+			{ID: 3, Name: "Objects", Objects: objs, Opacity: 1, Type: LayerObjects, Visible: true},
+		},
+		NextLayerID:  4,
+		NextObjectID: len(objs) + 1,
+		Orientation:  "orthogonal",
+		RenderOrder:  "right-down",
+		TiledVersion: "1.10.2",
+		TileHeight:   tileSize,
+		Tilesets: []Tileset{{
+			Columns: 8, FirstGID: 1, Image: "../tiles/tiles.png", ImageHeight: 128, ImageWidth: 128,
+			Name: "tiles", TileCount: 64, TileHeight: tileSize, TileWidth: tileSize,
+		}},
+		TileWidth: tileSize,
+		Type:      "map",
+		Version:   "1.10",
+		Width:     width,
+	}, nil
+}
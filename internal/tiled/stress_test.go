@@ -0,0 +1,47 @@
+package tiled
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStressMap(t *testing.T) {
+	m, err := StressMap(200, 30, 1000, 1)
+	if err != nil {
+		t.Fatalf("StressMap failed: %v", err)
+	}
+	if m.Width != 200 || m.Height != 30 {
+		t.Errorf("map is %dx%d, want 200x30", m.Width, m.Height)
+	}
+	for _, name := range []string{"Tiles", "Collision"} {
+		if l := m.Layer(name); l == nil || len(l.Data) != 200*30 {
+			t.Fatalf("%s layer missing or not 200x30", name)
+		}
+	}
+	if got := m.Layer("Collision").Data[29*200]; got != 1 {
+		t.Errorf("bottom left collision tile = %d, want solid", got)
+	}
+
+	objects := m.Layer("Objects").Objects
+	if len(objects) != 1002 {
+		t.Fatalf("%d objects, want 1000 plus spawn and goal", len(objects))
+	}
+	counts := make(map[string]int)
+	for _, o := range objects {
+		counts[o.Type]++
+		if o.X < 0 || o.Y < 0 || o.X+o.Width > 200*16 || o.Y+o.Height > 28*16 {
+			t.Errorf("%s %d at %v, %v sticks out of the level above the ground", o.Type, o.ID, o.X, o.Y)
+		}
+	}
+	if counts["spawn"] != 1 || counts["goal"] != 1 || counts["hazard"] != 250 {
+		t.Errorf("object counts = %v, want one spawn and goal and 250 of each other type", counts)
+	}
+
+	again, _ := StressMap(200, 30, 1000, 1)
+	if !reflect.DeepEqual(m, again) {
+		t.Error("the same seed gave a different level")
+	}
+	if _, err := StressMap(4, 30, 10, 1); err == nil {
+		t.Error("a 4 tile wide stress level was generated")
+	}
+}
@@ -0,0 +1,403 @@
+// Package tiled reads and writes Tiled maps in JSON and TMX (XML) form.
+//
+// It is a plain data model with no rendering or ebiten dependency, for
+// command-line tools that convert or reshape level files. The game itself
+// loads levels through world.ParseTiledJSON.
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Layer types.
+const (
+	LayerTiles   = "tilelayer"
+	LayerObjects = "objectgroup"
+)
+
+// Map is a Tiled map. Field names and JSON tags follow the Tiled JSON format.
+// Maps, layers, objects, properties and tilesets keep the JSON fields they
+// don't model and write them back (see extraFields).
+type Map struct {
+	CompressionLevel int        `json:"compressionlevel"`
+	Height           int        `json:"height"`
+	Infinite         bool       `json:"infinite"`
+	Layers           []Layer    `json:"layers"`
+	NextLayerID      int        `json:"nextlayerid"`
+	NextObjectID     int        `json:"nextobjectid"`
+	Orientation      string     `json:"orientation"`
+	Properties       []Property `json:"properties,omitempty"`
+	RenderOrder      string     `json:"renderorder"`
+	TiledVersion     string     `json:"tiledversion"`
+	TileHeight       int        `json:"tileheight"`
+	Tilesets         []Tileset  `json:"tilesets"`
+	TileWidth        int        `json:"tilewidth"`
+	Type             string     `json:"type"`
+	Version          string     `json:"version"`
+	Width            int        `json:"width"`
+
+	extra extraFields // Such as "class" or "backgroundcolor"
+}
+
+// Layer is a tile layer or object group. Tile layers of infinite maps keep
+// their tiles in Chunks instead of Data; Width, Height, StartX and StartY
+// are then the bounds of the chunks. Data and Chunks are decoded; they're
+// written back with the layer's Encoding and Compression.
+type Layer struct {
+	Chunks      []Chunk    `json:"chunks,omitempty"`      // Infinite maps' tile layers
+	Compression string     `json:"compression,omitempty"` // Of base64 data: CompressionZlib, CompressionGzip or none
+	Data        []int      `json:"data,omitempty"`        // Tile GIDs, row-major (tile layers)
+	Encoding    string     `json:"encoding,omitempty"`    // EncodingBase64, or a JSON array if empty
+	Height      int        `json:"height"`
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Objects     []Object   `json:"objects,omitempty"` // Object groups
+	Opacity     float64    `json:"opacity"`
+	Properties  []Property `json:"properties,omitempty"`
+	StartX      int        `json:"startx,omitempty"` // Top left of the chunks, in tiles
+	StartY      int        `json:"starty,omitempty"`
+	TintColor   string     `json:"tintcolor,omitempty"` // #RRGGBB or #AARRGGBB
+	Type        string     `json:"type"`                // LayerTiles or LayerObjects
+	Visible     bool       `json:"visible"`
+	Width       int        `json:"width"`
+	X           int        `json:"x"`
+	Y           int        `json:"y"`
+
+	extra extraFields // Such as "parallaxx", "offsetx" or an image layer's "image"
+}
+
+// jsonLayer is a Layer as stored in JSON, its tiles encoded.
+type jsonLayer struct {
+	Chunks      []jsonChunk     `json:"chunks,omitempty"`
+	Compression string          `json:"compression,omitempty"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	Encoding    string          `json:"encoding,omitempty"`
+	Height      int             `json:"height"`
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Objects     []Object        `json:"objects,omitempty"`
+	Opacity     float64         `json:"opacity"`
+	Properties  []Property      `json:"properties,omitempty"`
+	StartX      int             `json:"startx,omitempty"`
+	StartY      int             `json:"starty,omitempty"`
+	TintColor   string          `json:"tintcolor,omitempty"`
+	Type        string          `json:"type"`
+	Visible     bool            `json:"visible"`
+	Width       int             `json:"width"`
+	X           int             `json:"x"`
+	Y           int             `json:"y"`
+}
+
+// jsonChunk is a Chunk as stored in JSON.
+type jsonChunk struct {
+	Data   json.RawMessage `json:"data"`
+	Height int             `json:"height"`
+	Width  int             `json:"width"`
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+}
+
+// Chunk is a block of an infinite map's tile layer.
+type Chunk struct {
+	Data   []int `json:"data"` // Tile GIDs, row-major
+	Height int   `json:"height"`
+	Width  int   `json:"width"`
+	X      int   `json:"x"` // Top left, in tiles
+	Y      int   `json:"y"`
+}
+
+// ChunkSize is the width and height of the chunks SetTile adds, as in Tiled.
+const ChunkSize = 16
+
+// Object is an object in an object group. Polygon and polyline objects have
+// their points relative to X, Y; point objects have no size.
+type Object struct {
+	Ellipse    bool       `json:"ellipse,omitempty"`
+	Height     float64    `json:"height"`
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Point      bool       `json:"point,omitempty"`
+	Polygon    []Point    `json:"polygon,omitempty"`  // Closed shape
+	Polyline   []Point    `json:"polyline,omitempty"` // Open path
+	Properties []Property `json:"properties,omitempty"`
+	Rotation   float64    `json:"rotation,omitempty"`
+	Type       string     `json:"type"`
+	Visible    bool       `json:"visible"`
+	Width      float64    `json:"width"`
+	X          float64    `json:"x"`
+	Y          float64    `json:"y"`
+
+	extra extraFields // Such as "gid" or "template"
+}
+
+// Point is a point of a polygon or polyline object.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Property is a custom property. Value is a string, float64, or bool.
+type Property struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+
+	extra extraFields // Such as "propertytype"
+}
+
+// Tileset is an embedded tileset or a reference to an external one (Source).
+type Tileset struct {
+	Columns     int    `json:"columns,omitempty"`
+	FirstGID    int    `json:"firstgid"`
+	Image       string `json:"image,omitempty"`
+	ImageHeight int    `json:"imageheight,omitempty"`
+	ImageWidth  int    `json:"imagewidth,omitempty"`
+	Margin      int    `json:"margin,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Spacing     int    `json:"spacing,omitempty"`
+	TileCount   int    `json:"tilecount,omitempty"`
+	TileHeight  int    `json:"tileheight,omitempty"`
+	TileWidth   int    `json:"tilewidth,omitempty"`
+
+	extra extraFields // Such as "tiles" or "wangsets"
+}
+
+// ParseJSON parses a Tiled JSON map.
+func ParseJSON(data []byte) (*Map, error) {
+	var m Map
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse Tiled JSON: %w", err)
+	}
+	return &m, nil
+}
+
+// EncodeJSON encodes the map as indented Tiled JSON.
+func (m *Map) EncodeJSON() ([]byte, error) {
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (m *Map) UnmarshalJSON(data []byte) error {
+	type plain Map
+	extra, err := decodeWithExtra(data, (*plain)(m))
+	m.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (m Map) MarshalJSON() ([]byte, error) {
+	type plain Map
+	return encodeWithExtra(plain(m), m.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+// Layers missing "visible" or "opacity" default to visible and opaque, as
+// in Tiled. Tile data is decoded (see DecodeTileData).
+func (l *Layer) UnmarshalJSON(data []byte) error {
+	j := jsonLayer{Visible: true, Opacity: 1}
+	extra, err := decodeWithExtra(data, &j)
+	if err != nil {
+		return err
+	}
+	*l = Layer{
+		Compression: j.Compression, Encoding: j.Encoding, Height: j.Height, ID: j.ID, Name: j.Name,
+		Objects: j.Objects, Opacity: j.Opacity, Properties: j.Properties, StartX: j.StartX, StartY: j.StartY,
+		TintColor: j.TintColor, Type: j.Type, Visible: j.Visible, Width: j.Width, X: j.X, Y: j.Y,
+		extra: extra,
+	}
+
+	if l.Data, err = DecodeTileData(j.Data, j.Encoding, j.Compression); err != nil {
+		return fmt.Errorf("layer %q: %w", j.Name, err)
+	}
+	for _, jc := range j.Chunks {
+		c := Chunk{Height: jc.Height, Width: jc.Width, X: jc.X, Y: jc.Y}
+		if c.Data, err = DecodeTileData(jc.Data, j.Encoding, j.Compression); err != nil {
+			return fmt.Errorf("layer %q, chunk at %d, %d: %w", j.Name, jc.X, jc.Y, err)
+		}
+		l.Chunks = append(l.Chunks, c)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the tiles with the
+// layer's Encoding and Compression and writing unknown fields back.
+func (l Layer) MarshalJSON() ([]byte, error) {
+	j := jsonLayer{
+		Compression: l.Compression, Encoding: l.Encoding, Height: l.Height, ID: l.ID, Name: l.Name,
+		Objects: l.Objects, Opacity: l.Opacity, Properties: l.Properties, StartX: l.StartX, StartY: l.StartY,
+		TintColor: l.TintColor, Type: l.Type, Visible: l.Visible, Width: l.Width, X: l.X, Y: l.Y,
+	}
+
+	var err error
+	if len(l.Data) > 0 {
+		if j.Data, err = encodeTiles(l.Data, l.Encoding, l.Compression); err != nil {
+			return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+	}
+	for _, c := range l.Chunks {
+		jc := jsonChunk{Height: c.Height, Width: c.Width, X: c.X, Y: c.Y}
+		if jc.Data, err = encodeTiles(c.Data, l.Encoding, l.Compression); err != nil {
+			return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+		if jc.Data == nil {
+			jc.Data = json.RawMessage("[]")
+		}
+		j.Chunks = append(j.Chunks, jc)
+	}
+	return encodeWithExtra(j, l.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+// Objects missing "visible" default to visible, as in Tiled.
+func (o *Object) UnmarshalJSON(data []byte) error {
+	type plain Object
+	p := plain{Visible: true}
+	extra, err := decodeWithExtra(data, &p)
+	*o = Object(p)
+	o.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (o Object) MarshalJSON() ([]byte, error) {
+	type plain Object
+	return encodeWithExtra(plain(o), o.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (p *Property) UnmarshalJSON(data []byte) error {
+	type plain Property
+	extra, err := decodeWithExtra(data, (*plain)(p))
+	p.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (p Property) MarshalJSON() ([]byte, error) {
+	type plain Property
+	return encodeWithExtra(plain(p), p.extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping unknown fields.
+func (t *Tileset) UnmarshalJSON(data []byte) error {
+	type plain Tileset
+	extra, err := decodeWithExtra(data, (*plain)(t))
+	t.extra = extra
+	return err
+}
+
+// MarshalJSON implements json.Marshaler, writing unknown fields back.
+func (t Tileset) MarshalJSON() ([]byte, error) {
+	type plain Tileset
+	return encodeWithExtra(plain(t), t.extra)
+}
+
+// Parse parses a map in the format given by the file name's extension
+// (".tmx" for TMX, anything else for JSON).
+func Parse(name string, data []byte) (*Map, error) {
+	if IsTMX(name) {
+		return ParseTMX(data)
+	}
+	return ParseJSON(data)
+}
+
+// Encode encodes the map in the format given by the file name's extension.
+func (m *Map) Encode(name string) ([]byte, error) {
+	if IsTMX(name) {
+		return m.EncodeTMX()
+	}
+	return m.EncodeJSON()
+}
+
+// IsTMX reports whether the file name has a .tmx extension.
+func IsTMX(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".tmx")
+}
+
+// Layer returns the layer with the given name, or nil.
+func (m *Map) Layer(name string) *Layer {
+	for i := range m.Layers {
+		if m.Layers[i].Name == name {
+			return &m.Layers[i]
+		}
+	}
+	return nil
+}
+
+// TileAt returns the GID at tile x, y, or 0 outside the layer.
+func (l *Layer) TileAt(x, y int) int {
+	if l.Chunks != nil {
+		for _, c := range l.Chunks {
+			if x >= c.X && y >= c.Y && x < c.X+c.Width && y < c.Y+c.Height && (y-c.Y)*c.Width+x-c.X < len(c.Data) {
+				return c.Data[(y-c.Y)*c.Width+x-c.X]
+			}
+		}
+		return 0
+	}
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height || y*l.Width+x >= len(l.Data) {
+		return 0
+	}
+	return l.Data[y*l.Width+x]
+}
+
+// SetTile sets the GID at tile x, y and reports whether it changed. Outside
+// a finite layer it does nothing; a chunked layer gets a ChunkSize chunk
+// for a tile outside its chunks.
+func (l *Layer) SetTile(x, y, gid int) bool {
+	if l.Chunks == nil {
+		if x < 0 || y < 0 || x >= l.Width || y >= l.Height || y*l.Width+x >= len(l.Data) || l.Data[y*l.Width+x] == gid {
+			return false
+		}
+		l.Data[y*l.Width+x] = gid
+		return true
+	}
+
+	for _, c := range l.Chunks {
+		if x >= c.X && y >= c.Y && x < c.X+c.Width && y < c.Y+c.Height && (y-c.Y)*c.Width+x-c.X < len(c.Data) {
+			i := (y-c.Y)*c.Width + x - c.X
+			if c.Data[i] == gid {
+				return false
+			}
+			c.Data[i] = gid
+			return true
+		}
+	}
+	if gid == 0 {
+		return false
+	}
+	c := Chunk{X: floorDiv(x, ChunkSize) * ChunkSize, Y: floorDiv(y, ChunkSize) * ChunkSize, Width: ChunkSize, Height: ChunkSize}
+	c.Data = make([]int, ChunkSize*ChunkSize)
+	c.Data[(y-c.Y)*ChunkSize+x-c.X] = gid
+	l.Chunks = append(l.Chunks, c)
+	l.fitChunks()
+	return true
+}
+
+// fitChunks sets the layer's bounds to those of its chunks.
+func (l *Layer) fitChunks() {
+	if len(l.Chunks) == 0 {
+		return
+	}
+	x1, y1 := l.Chunks[0].X, l.Chunks[0].Y
+	x2, y2 := x1, y1
+	for _, c := range l.Chunks {
+		x1, y1 = min(x1, c.X), min(y1, c.Y)
+		x2, y2 = max(x2, c.X+c.Width), max(y2, c.Y+c.Height)
+	}
+	l.StartX, l.StartY = x1, y1
+	l.Width, l.Height = x2-x1, y2-y1
+}
+
+// floorDiv divides rounding toward negative infinity, so tile -1 is in
+// chunk -1 rather than 0.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
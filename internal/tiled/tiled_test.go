@@ -0,0 +1,560 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// testMap returns a 4x3 map with a tile layer and an object group.
+func testMap() *Map {
+	return &Map{
+		CompressionLevel: -1,
+		Width:            4,
+		Height:           3,
+		TileWidth:        16,
+		TileHeight:       16,
+		Orientation:      "orthogonal",
+		RenderOrder:      "right-down",
+		Type:             "map",
+		Version:          "1.10",
+		NextLayerID:      3,
+		NextObjectID:     3,
+		Properties: []Property{
+			{Name: "boundsPolicy", Type: "string", Value: "clamp"},
+			{Name: "ambientDarkness", Type: "float", Value: 0.5},
+		},
+		Tilesets: []Tileset{{
+			FirstGID: 1, Name: "tiles", Image: "../tiles/tiles.png",
+			ImageWidth: 128, ImageHeight: 128, TileWidth: 16, TileHeight: 16,
+			TileCount: 64, Columns: 8,
+		}},
+		Layers: []Layer{
+			{
+				ID: 1, Name: "Tiles", Type: LayerTiles, Width: 4, Height: 3,
+				Visible: true, Opacity: 1, TintColor: "#80ff8000",
+				Data: []int{
+					1, 2, 3, 4,
+					5, 6, 7, 8,
+					9, 10, 11, 12,
+				},
+			},
+			{
+				ID: 2, Name: "Objects", Type: LayerObjects, Visible: true, Opacity: 1,
+				Objects: []Object{
+					{ID: 1, Name: "Spawn", Type: "spawn", X: 16, Y: 16, Width: 16, Height: 16, Visible: true},
+					{ID: 2, Name: "Door", Type: "door", X: 48, Y: 0, Width: 16, Height: 32, Visible: true,
+						Properties: []Property{
+							{Name: "id", Type: "string", Value: "door1"},
+							{Name: "startOpen", Type: "bool", Value: true},
+						}},
+				},
+			},
+		},
+	}
+}
+
+func TestTMXRoundTrip(t *testing.T) {
+	m := testMap()
+
+	data, err := m.EncodeTMX()
+	if err != nil {
+		t.Fatalf("EncodeTMX failed: %v", err)
+	}
+	got, err := ParseTMX(data)
+	if err != nil {
+		t.Fatalf("ParseTMX failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v\nTMX:\n%s", got, m, data)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	m := testMap()
+
+	data, err := m.EncodeJSON()
+	if err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+	got, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v", got, m)
+	}
+}
+
+// kitchenSinkJSON is a map using Tiled features the model doesn't have.
+const kitchenSinkJSON = `{
+  "backgroundcolor": "#202040", "class": "cave", "compressionlevel": -1, "height": 2, "infinite": false,
+  "nextlayerid": 5, "nextobjectid": 3, "orientation": "orthogonal", "parallaxoriginx": 8,
+  "properties": [{"name": "enemy", "propertytype": "EnemyKind", "type": "class", "value": {"hp": 3}}],
+  "renderorder": "right-down", "tiledversion": "1.10.2", "tileheight": 16, "tilewidth": 16, "type": "map",
+  "version": "1.10", "width": 2,
+  "editorsettings": {"export": {"format": "json", "target": "level.json"}},
+  "layers": [
+    {"class": "ground", "data": [1, 2, 0, 3], "height": 2, "id": 1, "locked": true, "name": "Tiles",
+     "offsetx": 4, "offsety": -2, "opacity": 1, "parallaxx": 0.5, "parallaxy": 0.75, "type": "tilelayer",
+     "visible": true, "width": 2, "x": 0, "y": 0},
+    {"height": 0, "id": 2, "image": "../backgrounds/sky.png", "imageheight": 180, "imagewidth": 320,
+     "name": "Sky", "opacity": 0.8, "repeatx": true, "transparentcolor": "#ff00ff", "type": "imagelayer",
+     "visible": true, "width": 0, "x": 0, "y": 0},
+    {"draworder": "topdown", "height": 0, "id": 3, "name": "Objects", "opacity": 1, "type": "objectgroup",
+     "visible": true, "width": 0, "x": 0, "y": 0, "objects": [
+       {"class": "crate", "gid": 2147483650, "height": 16, "id": 1, "name": "Crate", "rotation": 90, "type": "crate",
+        "visible": true, "width": 16, "x": 8, "y": 16},
+       {"height": 0, "id": 2, "name": "Enemy", "template": "../templates/bat.tx", "type": "", "visible": true,
+        "width": 0, "x": 24, "y": 8}
+     ]},
+    {"id": 4, "layers": [{"id": 5, "name": "Inner", "type": "objectgroup", "objects": []}], "name": "Group",
+     "opacity": 1, "height": 0, "type": "group", "visible": false, "width": 0, "x": 0, "y": 0}
+  ],
+  "tilesets": [
+    {"columns": 2, "firstgid": 1, "image": "../tiles/tiles.png", "imageheight": 32, "imagewidth": 32,
+     "margin": 1, "name": "tiles", "spacing": 2, "tilecount": 4, "tileheight": 16, "tilewidth": 16,
+     "tiles": [{"id": 0, "animation": [{"tileid": 0, "duration": 100}, {"tileid": 1, "duration": 100}]}],
+     "wangsets": [{"name": "terrain", "type": "corner", "tile": -1, "colors": [], "wangtiles": []}]},
+    {"firstgid": 5, "source": "../tiles/props.tsj"}
+  ]
+}`
+
+func TestJSONRoundTripKeepsUnknownFields(t *testing.T) {
+	m, err := ParseJSON([]byte(kitchenSinkJSON))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	if got := m.Layer("Tiles").Data; !reflect.DeepEqual(got, []int{1, 2, 0, 3}) {
+		t.Errorf("Tiles data = %v, want [1 2 0 3]", got)
+	}
+	data, err := m.EncodeJSON()
+	if err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal([]byte(kitchenSinkJSON), &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("encoded map isn't JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the map:\n%s", data)
+	}
+
+	// Written twice, the fields come out in the same order
+	again, err := m.EncodeJSON()
+	if err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+	if !bytes.Equal(again, data) {
+		t.Error("encoding the map twice gave different output")
+	}
+}
+
+func TestParseJSONDefaults(t *testing.T) {
+	data := []byte(`{"width": 1, "height": 1, "layers": [
+		{"name": "Objects", "type": "objectgroup", "objects": [{"id": 1, "type": "spawn"}]}
+	]}`)
+	m, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	l := m.Layer("Objects")
+	if l == nil || !l.Visible || l.Opacity != 1 {
+		t.Fatalf("layer defaults wrong: %+v", l)
+	}
+	if !l.Objects[0].Visible {
+		t.Error("object without \"visible\" should default to visible")
+	}
+}
+
+func TestParseJSONShapes(t *testing.T) {
+	data := []byte(`{"width": 4, "height": 4, "layers": [{"name": "Objects", "type": "objectgroup", "objects": [
+		{"id": 1, "type": "hazard", "x": 16, "y": 32, "width": 0, "height": 0,
+		 "polygon": [{"x": 0, "y": 0}, {"x": 32, "y": 0}, {"x": 16, "y": -16}]},
+		{"id": 2, "type": "platform", "x": 0, "y": 48, "width": 0, "height": 0,
+		 "polyline": [{"x": 0, "y": 0}, {"x": 64.5, "y": -8}]},
+		{"id": 3, "type": "checkpoint", "x": 32, "y": 0, "width": 32, "height": 32, "ellipse": true},
+		{"id": 4, "type": "spawn", "x": 8, "y": 40, "width": 0, "height": 0, "point": true}
+	]}]}`)
+	m, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	out, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	// The shapes come back out as they went in
+	objects := func(data []byte) []any {
+		var doc struct {
+			Layers []struct {
+				Objects []map[string]any `json:"objects"`
+			} `json:"layers"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		var shapes []any
+		for _, o := range doc.Layers[0].Objects {
+			shapes = append(shapes, o["polygon"], o["polyline"], o["ellipse"], o["point"])
+		}
+		return shapes
+	}
+	if got, want := objects(out), objects(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("shapes after a round trip = %v, want %v", got, want)
+	}
+
+	tmx, err := m.EncodeTMX()
+	if err != nil {
+		t.Fatalf("EncodeTMX failed: %v", err)
+	}
+	back, err := ParseTMX(tmx)
+	if err != nil {
+		t.Fatalf("ParseTMX failed: %v", err)
+	}
+	if got, want := back.Layers[0].Objects, m.Layers[0].Objects; !reflect.DeepEqual(got, want) {
+		t.Errorf("objects after a TMX round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTMXEncodings(t *testing.T) {
+	// GIDs 1..4 as little-endian uint32, zlib compressed
+	var raw bytes.Buffer
+	for gid := uint32(1); gid <= 4; gid++ {
+		binary.Write(&raw, binary.LittleEndian, gid)
+	}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(raw.Bytes())
+	zw.Close()
+
+	tests := map[string]string{
+		"csv":    `<data encoding="csv">1,2,` + "\n" + `3,4</data>`,
+		"base64": `<data encoding="base64">` + base64.StdEncoding.EncodeToString(raw.Bytes()) + `</data>`,
+		"zlib":   `<data encoding="base64" compression="zlib">` + base64.StdEncoding.EncodeToString(compressed.Bytes()) + `</data>`,
+		"xml":    `<data><tile gid="1"/><tile gid="2"/><tile gid="3"/><tile gid="4"/></data>`,
+	}
+	for name, data := range tests {
+		tmx := `<map orientation="orthogonal" width="2" height="2" tilewidth="16" tileheight="16">
+			<layer id="1" name="Tiles" width="2" height="2">` + data + `</layer></map>`
+		m, err := ParseTMX([]byte(tmx))
+		if err != nil {
+			t.Errorf("%s: ParseTMX failed: %v", name, err)
+			continue
+		}
+		if got := m.Layers[0].Data; !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+			t.Errorf("%s: data = %v, want [1 2 3 4]", name, got)
+		}
+	}
+}
+
+func TestParseTMXWrongTileCount(t *testing.T) {
+	tmx := `<map width="2" height="2"><layer name="Tiles" width="2" height="2"><data encoding="csv">1,2,3</data></layer></map>`
+	if _, err := ParseTMX([]byte(tmx)); err == nil {
+		t.Error("ParseTMX accepted a layer with too few tiles")
+	}
+}
+
+func TestCrop(t *testing.T) {
+	m := testMap()
+
+	removed, err := m.Crop(1, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("Crop failed: %v", err)
+	}
+
+	if m.Width != 2 || m.Height != 2 {
+		t.Errorf("size = %dx%d, want 2x2", m.Width, m.Height)
+	}
+	if got := m.Layer("Tiles").Data; !reflect.DeepEqual(got, []int{6, 7, 10, 11}) {
+		t.Errorf("tiles = %v, want [6 7 10 11]", got)
+	}
+
+	// The door at (48, 0) moves to (32, -16), outside the new map
+	if removed != 1 {
+		t.Errorf("removed = %d, want 1", removed)
+	}
+	objs := m.Layer("Objects").Objects
+	if len(objs) != 1 || objs[0].Name != "Spawn" || objs[0].X != 0 || objs[0].Y != 0 {
+		t.Errorf("objects = %+v, want Spawn at (0, 0)", objs)
+	}
+}
+
+func TestResizeGrows(t *testing.T) {
+	m := testMap()
+
+	removed, err := m.Resize(5, 4)
+	if err != nil {
+		t.Fatalf("Resize failed: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("removed = %d, want 0", removed)
+	}
+
+	want := []int{
+		1, 2, 3, 4, 0,
+		5, 6, 7, 8, 0,
+		9, 10, 11, 12, 0,
+		0, 0, 0, 0, 0,
+	}
+	if got := m.Layer("Tiles").Data; !reflect.DeepEqual(got, want) {
+		t.Errorf("tiles = %v, want %v", got, want)
+	}
+}
+
+func TestCropInvalidSize(t *testing.T) {
+	if _, err := testMap().Crop(0, 0, 0, 3); err == nil {
+		t.Error("Crop accepted a zero width")
+	}
+}
+
+func TestTileUsage(t *testing.T) {
+	l := Layer{Data: []int{0, 1, 1, 2, 0}}
+	if got := l.TileUsage(); !reflect.DeepEqual(got, map[int]int{1: 2, 2: 1}) {
+		t.Errorf("TileUsage() = %v", got)
+	}
+}
+
+func TestStripProperties(t *testing.T) {
+	m := testMap()
+	if removed := m.StripProperties("ambientDarkness", "startOpen", "missing"); removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	if want := []Property{{Name: "boundsPolicy", Type: "string", Value: "clamp"}}; !reflect.DeepEqual(m.Properties, want) {
+		t.Errorf("map properties = %+v, want %+v", m.Properties, want)
+	}
+	if want := []Property{{Name: "id", Type: "string", Value: "door1"}}; !reflect.DeepEqual(m.Layer("Objects").Objects[1].Properties, want) {
+		t.Errorf("door properties = %+v, want %+v", m.Layer("Objects").Objects[1].Properties, want)
+	}
+
+	// Removing the last property drops the list, so the JSON omits it
+	m.StripProperties("boundsPolicy")
+	if m.Properties != nil {
+		t.Errorf("map properties = %+v, want nil", m.Properties)
+	}
+}
+
+func TestLevelFileRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("../../assets/levels/level_01.json")
+	if err != nil {
+		t.Skipf("level not available: %v", err)
+	}
+	m, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	tmx, err := m.EncodeTMX()
+	if err != nil {
+		t.Fatalf("EncodeTMX failed: %v", err)
+	}
+	back, err := ParseTMX(tmx)
+	if err != nil {
+		t.Fatalf("ParseTMX failed: %v", err)
+	}
+	if !reflect.DeepEqual(back, m) {
+		t.Error("level changed through a TMX round trip")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before, after := testMap(), testMap()
+	if d := Compare(before, after); !d.Empty() {
+		t.Fatalf("Compare of equal maps = %+v, want empty", d)
+	}
+
+	tiles := after.Layer("Tiles")
+	tiles.Data[0], tiles.Data[5] = 0, 60 // Touching diagonally
+	tiles.Data[3] = 20                   // Apart
+	after.Properties[1].Value = 0.8
+	after.Properties = append(after.Properties, Property{Name: "music", Type: "string", Value: "cave"})
+	objects := after.Layer("Objects")
+	objects.Objects[0].X = 32
+	objects.Objects[1].Properties[1].Value = false
+	objects.Objects = append(objects.Objects, Object{ID: 3, Type: "coin", X: 8, Y: 8})
+	after.Layers = append(after.Layers, Layer{Name: "Deco", Type: LayerTiles, Width: 4, Height: 3, Data: make([]int, 12)})
+	after.Layers[2].Data[11] = 7
+
+	d := Compare(before, after)
+	wantProps := []PropertyChange{{Name: "ambientDarkness", Old: 0.5, New: 0.8}, {Name: "music", New: "cave"}}
+	if !reflect.DeepEqual(d.Properties, wantProps) {
+		t.Errorf("property changes %+v, want %+v", d.Properties, wantProps)
+	}
+	wantLayers := []LayerDiff{
+		{Name: "Tiles", Change: Modified, Tiles: 3, Regions: []TileRegion{{X: 0, Y: 0, W: 2, H: 2, Changed: 2}, {X: 3, Y: 0, W: 1, H: 1, Changed: 1}}},
+		{Name: "Deco", Change: Added, Tiles: 1, Regions: []TileRegion{{X: 3, Y: 2, W: 1, H: 1, Changed: 1}}},
+	}
+	if !reflect.DeepEqual(d.Layers, wantLayers) {
+		t.Errorf("layer changes %+v, want %+v", d.Layers, wantLayers)
+	}
+	wantObjects := []ObjectChange{
+		{ID: 1, Type: "spawn", Name: "Spawn", X: 32, Y: 16, Change: Modified, Fields: []FieldChange{{Field: "x", Old: 16.0, New: 32.0}}},
+		{ID: 2, Type: "door", Name: "Door", X: 48, Change: Modified, Properties: []PropertyChange{{Name: "startOpen", Old: true, New: false}}},
+		{ID: 3, Type: "coin", X: 8, Y: 8, Change: Added},
+	}
+	if !reflect.DeepEqual(d.Objects, wantObjects) {
+		t.Errorf("object changes %+v, want %+v", d.Objects, wantObjects)
+	}
+	if d.Size != nil {
+		t.Errorf("size change %+v for maps of the same size", d.Size)
+	}
+
+	// Growing the map changes nothing but the size: new tiles are empty
+	if _, err := after.Resize(6, 3); err != nil {
+		t.Fatal(err)
+	}
+	d = Compare(testMap(), after)
+	if d.Size == nil || d.Size.NewWidth != 6 || d.Layers[0].Tiles != 3 {
+		t.Errorf("after resize, size %+v and %d changed tiles, want 4x3 -> 6x3 and 3", d.Size, d.Layers[0].Tiles)
+	}
+}
+
+// infiniteMap returns an infinite map whose tile layer has a chunk left of
+// and above the origin and one right of it.
+func infiniteMap() *Map {
+	m := testMap()
+	m.Infinite = true
+	tiles := m.Layer("Tiles")
+	tiles.Data = nil
+	tiles.Chunks = []Chunk{
+		{X: -16, Y: -16, Width: 16, Height: 16, Data: make([]int, 256)},
+		{X: 16, Y: 0, Width: 16, Height: 16, Data: make([]int, 256)},
+	}
+	tiles.Chunks[0].Data[255] = 3 // Tile -1, -1
+	tiles.Chunks[1].Data[1] = 4   // Tile 17, 0
+	tiles.fitChunks()
+	return m
+}
+
+func TestInfiniteRoundTrip(t *testing.T) {
+	m := infiniteMap()
+	if l := m.Layer("Tiles"); l.StartX != -16 || l.StartY != -16 || l.Width != 48 || l.Height != 32 {
+		t.Fatalf("chunk bounds = %d, %d %dx%d, want -16, -16 48x32", l.StartX, l.StartY, l.Width, l.Height)
+	}
+
+	data, err := m.EncodeJSON()
+	if err != nil {
+		t.Fatalf("EncodeJSON failed: %v", err)
+	}
+	got, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("JSON round trip mismatch\ngot:  %+v\nwant: %+v", got.Layers[0], m.Layers[0])
+	}
+
+	tmx, err := m.EncodeTMX()
+	if err != nil {
+		t.Fatalf("EncodeTMX failed: %v", err)
+	}
+	if got, err = ParseTMX(tmx); err != nil {
+		t.Fatalf("ParseTMX failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("TMX round trip mismatch\ngot:  %+v\nwant: %+v", got.Layers[0], m.Layers[0])
+	}
+}
+
+func TestInfiniteTiles(t *testing.T) {
+	m := infiniteMap()
+	tiles := m.Layer("Tiles")
+	if got := tiles.TileAt(-1, -1); got != 3 {
+		t.Errorf("TileAt(-1, -1) = %d, want 3", got)
+	}
+	if got := tiles.TileAt(17, 0); got != 4 {
+		t.Errorf("TileAt(17, 0) = %d, want 4", got)
+	}
+
+	// Painting past the chunks adds one
+	if !tiles.SetTile(40, 20, 5) || tiles.TileAt(40, 20) != 5 {
+		t.Fatal("SetTile(40, 20) didn't add a chunk")
+	}
+	if len(tiles.Chunks) != 3 || tiles.Width != 64 || tiles.Height != 48 {
+		t.Errorf("%d chunks, %dx%d, want 3 chunks and 64x48", len(tiles.Chunks), tiles.Width, tiles.Height)
+	}
+	if tiles.SetTile(100, 100, 0) || tiles.SetTile(40, 20, 5) {
+		t.Error("SetTile reported a change that wasn't one")
+	}
+	if got := tiles.TileUsage(); !reflect.DeepEqual(got, map[int]int{3: 1, 4: 1, 5: 1}) {
+		t.Errorf("TileUsage = %v", got)
+	}
+
+	if _, err := m.Resize(10, 10); err == nil {
+		t.Error("resizing an infinite map succeeded")
+	}
+
+	// Diffs cover the chunks left of and above the origin
+	after := infiniteMap()
+	after.Layer("Tiles").SetTile(-2, -1, 7)
+	d := Compare(infiniteMap(), after)
+	if len(d.Layers) != 1 || d.Layers[0].Tiles != 1 || d.Layers[0].Regions[0].X != -2 || d.Layers[0].Regions[0].Y != -1 {
+		t.Errorf("diff = %+v, want tile -2, -1 changed", d.Layers)
+	}
+}
+
+func TestCompressedRoundTrip(t *testing.T) {
+	for _, compression := range []string{"", CompressionZlib, CompressionGzip} {
+		for _, m := range []*Map{testMap(), infiniteMap()} {
+			tiles := m.Layer("Tiles")
+			tiles.Encoding, tiles.Compression = EncodingBase64, compression
+
+			data, err := m.EncodeJSON()
+			if err != nil {
+				t.Fatalf("%q: EncodeJSON failed: %v", compression, err)
+			}
+			if bytes.Contains(data, []byte(`"data": [`)) {
+				t.Errorf("%q: tiles written as an array", compression)
+			}
+			got, err := ParseJSON(data)
+			if err != nil {
+				t.Fatalf("%q: ParseJSON failed: %v", compression, err)
+			}
+			if !reflect.DeepEqual(got, m) {
+				t.Errorf("%q: JSON round trip (infinite %v) changed the tile layer\ngot:  %+v\nwant: %+v", compression, m.Infinite, got.Layers[0], m.Layers[0])
+			}
+
+			tmx, err := m.EncodeTMX()
+			if err != nil {
+				t.Fatalf("%q: EncodeTMX failed: %v", compression, err)
+			}
+			if got, err = ParseTMX(tmx); err != nil {
+				t.Fatalf("%q: ParseTMX failed: %v", compression, err)
+			}
+			if !reflect.DeepEqual(got, m) {
+				t.Errorf("%q: TMX round trip (infinite %v) changed the tile layer\ngot:  %+v\nwant: %+v", compression, m.Infinite, got.Layers[0], m.Layers[0])
+			}
+		}
+	}
+}
+
+func TestParseJSONCompressed(t *testing.T) {
+	zlibData, _ := EncodeTileData([]int{1, 0, 0, 2}, CompressionZlib)
+	data := []byte(`{"width": 2, "height": 2, "layers": [
+	  {"name": "Tiles", "type": "tilelayer", "width": 2, "height": 2, "encoding": "base64", "compression": "zlib", "data": "` + zlibData + `"}
+	]}`)
+	m, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	if got := m.Layer("Tiles").Data; !reflect.DeepEqual(got, []int{1, 0, 0, 2}) {
+		t.Errorf("tiles = %v, want [1 0 0 2]", got)
+	}
+
+	bad := []byte(`{"layers": [{"name": "Tiles", "type": "tilelayer", "encoding": "base64", "compression": "zstd", "data": "AAAA"}]}`)
+	if _, err := ParseJSON(bad); err == nil {
+		t.Error("a zstd compressed layer parsed")
+	}
+}
@@ -0,0 +1,132 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Tiled stores a tile layer's (or chunk's) tiles as a JSON array of tile
+// IDs, or, with the "base64" encoding, as a base64 string of little-endian
+// uint32 IDs, compressed first if the layer's "compression" says so. Large
+// levels shrink to a fraction of their size that way. TMX files encode
+// their <data> the same way.
+
+// EncodingBase64 is the "encoding" of tile layers stored as strings.
+const EncodingBase64 = "base64"
+
+// Compressions of base64 encoded tile data.
+const (
+	CompressionZlib = "zlib"
+	CompressionGzip = "gzip"
+)
+
+// DecodeTileData returns the tile IDs of a tile layer's or chunk's "data"
+// stored with the given encoding and compression. Data of other encodings
+// than base64 is a JSON array; missing data has no tiles.
+func DecodeTileData(data json.RawMessage, encoding, compression string) ([]int, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	if encoding != EncodingBase64 {
+		var ids []int
+		if err := json.Unmarshal(data, &ids); err != nil {
+			return nil, fmt.Errorf("invalid tile data: %w", err)
+		}
+		return ids, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("base64 tile data is not a string: %w", err)
+	}
+	return decodeBase64Tiles(s, compression)
+}
+
+// decodeBase64Tiles decodes base64 tile data, compressed with compression.
+func decodeBase64Tiles(s, compression string) ([]int, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 tile data: %w", err)
+	}
+
+	var r io.ReadCloser
+	switch compression {
+	case "":
+	case CompressionZlib:
+		r, err = zlib.NewReader(bytes.NewReader(raw))
+	case CompressionGzip:
+		r, err = gzip.NewReader(bytes.NewReader(raw))
+	default:
+		return nil, fmt.Errorf("unsupported tile data compression %q", compression)
+	}
+	if err == nil && r != nil {
+		raw, err = io.ReadAll(r)
+		r.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s tile data: %w", compression, err)
+	}
+
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("tile data is %d bytes, not a multiple of 4", len(raw))
+	}
+	ids := make([]int, len(raw)/4)
+	for i := range ids {
+		ids[i] = int(binary.LittleEndian.Uint32(raw[i*4:]))
+	}
+	return ids, nil
+}
+
+// EncodeTileData returns ids as base64 encoded tile data, compressed with
+// compression ("" for none), as DecodeTileData reads it.
+func EncodeTileData(ids []int, compression string) (string, error) {
+	raw := make([]byte, len(ids)*4)
+	for i, id := range ids {
+		binary.LittleEndian.PutUint32(raw[i*4:], uint32(id))
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case "":
+		buf.Write(raw)
+	case CompressionZlib:
+		w = zlib.NewWriter(&buf)
+	case CompressionGzip:
+		w = gzip.NewWriter(&buf)
+	default:
+		return "", fmt.Errorf("unsupported tile data compression %q", compression)
+	}
+	if w != nil {
+		if _, err := w.Write(raw); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// encodeTiles returns ids as a layer's or chunk's "data": a JSON array, or
+// a base64 string with the base64 encoding.
+func encodeTiles(ids []int, encoding, compression string) (json.RawMessage, error) {
+	if encoding != EncodingBase64 {
+		if ids == nil {
+			return nil, nil
+		}
+		return json.Marshal(ids)
+	}
+	s, err := EncodeTileData(ids, compression)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
@@ -0,0 +1,537 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TMX XML structures. Layers and object groups share one slice so their
+// order is kept through a round trip.
+type tmxMap struct {
+	XMLName      xml.Name      `xml:"map"`
+	Version      string        `xml:"version,attr,omitempty"`
+	TiledVersion string        `xml:"tiledversion,attr,omitempty"`
+	Orientation  string        `xml:"orientation,attr"`
+	RenderOrder  string        `xml:"renderorder,attr,omitempty"`
+	Width        int           `xml:"width,attr"`
+	Height       int           `xml:"height,attr"`
+	TileWidth    int           `xml:"tilewidth,attr"`
+	TileHeight   int           `xml:"tileheight,attr"`
+	Infinite     int           `xml:"infinite,attr"`
+	NextLayerID  int           `xml:"nextlayerid,attr,omitempty"`
+	NextObjectID int           `xml:"nextobjectid,attr,omitempty"`
+	Properties   *tmxProps     `xml:"properties,omitempty"`
+	Tilesets     []tmxTileset  `xml:"tileset"`
+	Layers       []tmxLayerAny `xml:",any"`
+}
+
+type tmxProps struct {
+	Properties []tmxProperty `xml:"property"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:"value,attr"`
+}
+
+type tmxTileset struct {
+	FirstGID   int       `xml:"firstgid,attr"`
+	Source     string    `xml:"source,attr,omitempty"`
+	Name       string    `xml:"name,attr,omitempty"`
+	TileWidth  int       `xml:"tilewidth,attr,omitempty"`
+	TileHeight int       `xml:"tileheight,attr,omitempty"`
+	Spacing    int       `xml:"spacing,attr,omitempty"`
+	Margin     int       `xml:"margin,attr,omitempty"`
+	TileCount  int       `xml:"tilecount,attr,omitempty"`
+	Columns    int       `xml:"columns,attr,omitempty"`
+	Image      *tmxImage `xml:"image,omitempty"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr,omitempty"`
+	Height int    `xml:"height,attr,omitempty"`
+}
+
+// tmxLayerAny is a <layer> or <objectgroup> element.
+type tmxLayerAny struct {
+	XMLName    xml.Name
+	ID         int         `xml:"id,attr,omitempty"`
+	Name       string      `xml:"name,attr"`
+	Width      int         `xml:"width,attr,omitempty"`
+	Height     int         `xml:"height,attr,omitempty"`
+	Visible    *int        `xml:"visible,attr"`
+	Opacity    *float64    `xml:"opacity,attr"`
+	TintColor  string      `xml:"tintcolor,attr,omitempty"`
+	OffsetX    int         `xml:"offsetx,attr,omitempty"`
+	OffsetY    int         `xml:"offsety,attr,omitempty"`
+	Properties *tmxProps   `xml:"properties,omitempty"`
+	Data       *tmxData    `xml:"data,omitempty"`
+	Objects    []tmxObject `xml:"object"`
+}
+
+type tmxData struct {
+	Encoding    string     `xml:"encoding,attr,omitempty"`
+	Compression string     `xml:"compression,attr,omitempty"`
+	Tiles       []tmxTile  `xml:"tile"`
+	Chunks      []tmxChunk `xml:"chunk"` // Infinite maps
+	Text        string     `xml:",chardata"`
+}
+
+// tmxChunk is a block of an infinite map's layer, encoded like its <data>.
+type tmxChunk struct {
+	X      int       `xml:"x,attr"`
+	Y      int       `xml:"y,attr"`
+	Width  int       `xml:"width,attr"`
+	Height int       `xml:"height,attr"`
+	Tiles  []tmxTile `xml:"tile"`
+	Text   string    `xml:",chardata"`
+}
+
+type tmxTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmxObject struct {
+	ID         int        `xml:"id,attr"`
+	Name       string     `xml:"name,attr,omitempty"`
+	Type       string     `xml:"type,attr,omitempty"`
+	Class      string     `xml:"class,attr,omitempty"` // Tiled 1.9 name for type
+	X          float64    `xml:"x,attr"`
+	Y          float64    `xml:"y,attr"`
+	Width      float64    `xml:"width,attr,omitempty"`
+	Height     float64    `xml:"height,attr,omitempty"`
+	Rotation   float64    `xml:"rotation,attr,omitempty"`
+	Visible    *int       `xml:"visible,attr"`
+	Properties *tmxProps  `xml:"properties,omitempty"`
+	Polygon    *tmxPoints `xml:"polygon,omitempty"`
+	Polyline   *tmxPoints `xml:"polyline,omitempty"`
+	Ellipse    *struct{}  `xml:"ellipse,omitempty"`
+	Point      *struct{}  `xml:"point,omitempty"`
+}
+
+// tmxPoints are the points of a <polygon> or <polyline>: "x,y x,y ...".
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+// ParseTMX parses a TMX (XML) map. Tile data may be CSV, base64 (optionally
+// gzip or zlib compressed), or <tile> elements. External tilesets are kept
+// as references.
+func ParseTMX(data []byte) (*Map, error) {
+	var tm tmxMap
+	if err := xml.Unmarshal(data, &tm); err != nil {
+		return nil, fmt.Errorf("failed to parse TMX: %w", err)
+	}
+
+	m := &Map{
+		CompressionLevel: -1,
+		Width:            tm.Width,
+		Height:           tm.Height,
+		TileWidth:        tm.TileWidth,
+		TileHeight:       tm.TileHeight,
+		Infinite:         tm.Infinite != 0,
+		NextLayerID:      tm.NextLayerID,
+		NextObjectID:     tm.NextObjectID,
+		Orientation:      tm.Orientation,
+		RenderOrder:      tm.RenderOrder,
+		TiledVersion:     tm.TiledVersion,
+		Version:          tm.Version,
+		Type:             "map",
+	}
+
+	var err error
+	if m.Properties, err = fromTMXProps(tm.Properties); err != nil {
+		return nil, err
+	}
+
+	for _, ts := range tm.Tilesets {
+		t := Tileset{
+			FirstGID:   ts.FirstGID,
+			Source:     ts.Source,
+			Name:       ts.Name,
+			TileWidth:  ts.TileWidth,
+			TileHeight: ts.TileHeight,
+			Spacing:    ts.Spacing,
+			Margin:     ts.Margin,
+			TileCount:  ts.TileCount,
+			Columns:    ts.Columns,
+		}
+		if ts.Image != nil {
+			t.Image = ts.Image.Source
+			t.ImageWidth = ts.Image.Width
+			t.ImageHeight = ts.Image.Height
+		}
+		m.Tilesets = append(m.Tilesets, t)
+	}
+
+	for _, tl := range tm.Layers {
+		var layer Layer
+		switch tl.XMLName.Local {
+		case "layer":
+			layer.Type = LayerTiles
+		case "objectgroup":
+			layer.Type = LayerObjects
+		default:
+			continue // Image layers, groups, etc. aren't used by the game
+		}
+
+		layer.ID = tl.ID
+		layer.Name = tl.Name
+		layer.Width = tl.Width
+		layer.Height = tl.Height
+		layer.X = tl.OffsetX
+		layer.Y = tl.OffsetY
+		layer.Visible = tl.Visible == nil || *tl.Visible != 0
+		layer.TintColor = tl.TintColor
+		layer.Opacity = 1
+		if tl.Opacity != nil {
+			layer.Opacity = *tl.Opacity
+		}
+		if layer.Properties, err = fromTMXProps(tl.Properties); err != nil {
+			return nil, err
+		}
+
+		if layer.Type == LayerTiles && tl.Data != nil && tl.Data.Encoding == EncodingBase64 {
+			layer.Encoding, layer.Compression = EncodingBase64, tl.Data.Compression
+		}
+		if layer.Type == LayerTiles && m.Infinite {
+			if layer.Chunks, err = decodeTMXChunks(tl.Data); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+			}
+			layer.fitChunks()
+		} else if layer.Type == LayerTiles {
+			if layer.Data, err = decodeTMXData(tl.Data, tl.Width*tl.Height); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+			}
+		}
+
+		for _, to := range tl.Objects {
+			obj := Object{
+				ID:       to.ID,
+				Name:     to.Name,
+				Type:     to.Type,
+				X:        to.X,
+				Y:        to.Y,
+				Width:    to.Width,
+				Height:   to.Height,
+				Rotation: to.Rotation,
+				Visible:  to.Visible == nil || *to.Visible != 0,
+				Ellipse:  to.Ellipse != nil,
+				Point:    to.Point != nil,
+			}
+			if obj.Type == "" {
+				obj.Type = to.Class
+			}
+			if obj.Properties, err = fromTMXProps(to.Properties); err != nil {
+				return nil, fmt.Errorf("object %d: %w", to.ID, err)
+			}
+			if obj.Polygon, err = fromTMXPoints(to.Polygon); err != nil {
+				return nil, fmt.Errorf("object %d: %w", to.ID, err)
+			}
+			if obj.Polyline, err = fromTMXPoints(to.Polyline); err != nil {
+				return nil, fmt.Errorf("object %d: %w", to.ID, err)
+			}
+			layer.Objects = append(layer.Objects, obj)
+		}
+
+		m.Layers = append(m.Layers, layer)
+	}
+
+	return m, nil
+}
+
+// EncodeTMX encodes the map as TMX, with CSV tile data unless a layer's
+// Encoding is base64.
+func (m *Map) EncodeTMX() ([]byte, error) {
+	tm := tmxMap{
+		Version:      m.Version,
+		TiledVersion: m.TiledVersion,
+		Orientation:  m.Orientation,
+		RenderOrder:  m.RenderOrder,
+		Width:        m.Width,
+		Height:       m.Height,
+		TileWidth:    m.TileWidth,
+		TileHeight:   m.TileHeight,
+		NextLayerID:  m.NextLayerID,
+		NextObjectID: m.NextObjectID,
+		Properties:   toTMXProps(m.Properties),
+	}
+	if m.Infinite {
+		tm.Infinite = 1
+	}
+
+	for _, t := range m.Tilesets {
+		ts := tmxTileset{
+			FirstGID:   t.FirstGID,
+			Source:     t.Source,
+			Name:       t.Name,
+			TileWidth:  t.TileWidth,
+			TileHeight: t.TileHeight,
+			Spacing:    t.Spacing,
+			Margin:     t.Margin,
+			TileCount:  t.TileCount,
+			Columns:    t.Columns,
+		}
+		if t.Image != "" {
+			ts.Image = &tmxImage{Source: t.Image, Width: t.ImageWidth, Height: t.ImageHeight}
+		}
+		tm.Tilesets = append(tm.Tilesets, ts)
+	}
+
+	for _, l := range m.Layers {
+		tl := tmxLayerAny{
+			ID:         l.ID,
+			Name:       l.Name,
+			OffsetX:    l.X,
+			OffsetY:    l.Y,
+			TintColor:  l.TintColor,
+			Properties: toTMXProps(l.Properties),
+		}
+		if !l.Visible {
+			tl.Visible = new(int)
+		}
+		if l.Opacity != 1 {
+			opacity := l.Opacity
+			tl.Opacity = &opacity
+		}
+
+		switch l.Type {
+		case LayerTiles:
+			tl.XMLName.Local = "layer"
+			tl.Width = l.Width
+			tl.Height = l.Height
+			tl.Data = &tmxData{Encoding: "csv"}
+			if l.Encoding == EncodingBase64 {
+				tl.Data.Encoding, tl.Data.Compression = EncodingBase64, l.Compression
+			}
+			var err error
+			if !m.Infinite {
+				tl.Data.Text, err = encodeTMXTiles(l.Data, l.Width, l.Encoding, l.Compression)
+			}
+			for _, c := range l.Chunks {
+				tc := tmxChunk{X: c.X, Y: c.Y, Width: c.Width, Height: c.Height}
+				if tc.Text, err = encodeTMXTiles(c.Data, c.Width, l.Encoding, l.Compression); err != nil {
+					break
+				}
+				tl.Data.Chunks = append(tl.Data.Chunks, tc)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+			}
+		case LayerObjects:
+			tl.XMLName.Local = "objectgroup"
+		default:
+			return nil, fmt.Errorf("layer %q: unsupported type %q", l.Name, l.Type)
+		}
+
+		for _, obj := range l.Objects {
+			to := tmxObject{
+				ID:         obj.ID,
+				Name:       obj.Name,
+				Type:       obj.Type,
+				X:          obj.X,
+				Y:          obj.Y,
+				Width:      obj.Width,
+				Height:     obj.Height,
+				Rotation:   obj.Rotation,
+				Properties: toTMXProps(obj.Properties),
+				Polygon:    toTMXPoints(obj.Polygon),
+				Polyline:   toTMXPoints(obj.Polyline),
+			}
+			if !obj.Visible {
+				to.Visible = new(int)
+			}
+			if obj.Ellipse {
+				to.Ellipse = &struct{}{}
+			}
+			if obj.Point {
+				to.Point = &struct{}{}
+			}
+			tl.Objects = append(tl.Objects, to)
+		}
+
+		tm.Layers = append(tm.Layers, tl)
+	}
+
+	out, err := xml.MarshalIndent(tm, "", " ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode TMX: %w", err)
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// decodeTMXData decodes a tile layer's data into n GIDs.
+func decodeTMXData(d *tmxData, n int) ([]int, error) {
+	if d == nil {
+		return make([]int, n), nil
+	}
+	return decodeTMXTiles(d.Encoding, d.Compression, d.Tiles, d.Text, n)
+}
+
+// decodeTMXChunks decodes the chunks of an infinite map's tile layer.
+func decodeTMXChunks(d *tmxData) ([]Chunk, error) {
+	if d == nil {
+		return nil, nil
+	}
+	chunks := make([]Chunk, 0, len(d.Chunks))
+	for _, tc := range d.Chunks {
+		gids, err := decodeTMXTiles(d.Encoding, d.Compression, tc.Tiles, tc.Text, tc.Width*tc.Height)
+		if err != nil {
+			return nil, fmt.Errorf("chunk at %d, %d: %w", tc.X, tc.Y, err)
+		}
+		chunks = append(chunks, Chunk{Data: gids, Height: tc.Height, Width: tc.Width, X: tc.X, Y: tc.Y})
+	}
+	return chunks, nil
+}
+
+// decodeTMXTiles decodes n GIDs given as <tile> elements or as encoded text.
+func decodeTMXTiles(encoding, compression string, tiles []tmxTile, text string, n int) ([]int, error) {
+	var gids []int
+	switch encoding {
+	case "":
+		for _, t := range tiles {
+			gids = append(gids, int(t.GID))
+		}
+	case "csv":
+		for _, field := range strings.Split(text, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV tile %q", field)
+			}
+			gids = append(gids, int(gid))
+		}
+	case EncodingBase64:
+		var err error
+		if gids, err = decodeBase64Tiles(text, compression); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tile encoding %q", encoding)
+	}
+
+	if len(gids) != n {
+		return nil, fmt.Errorf("got %d tiles, want %d", len(gids), n)
+	}
+	return gids, nil
+}
+
+// encodeTMXTiles returns GIDs as the text of a <data> or <chunk>: CSV, or
+// base64 with the base64 encoding.
+func encodeTMXTiles(gids []int, width int, encoding, compression string) (string, error) {
+	if encoding == EncodingBase64 {
+		s, err := EncodeTileData(gids, compression)
+		return "\n" + s + "\n", err
+	}
+	return encodeCSV(gids, width), nil
+}
+
+// encodeCSV writes GIDs as CSV with one map row per line, like Tiled.
+func encodeCSV(gids []int, width int) string {
+	var b strings.Builder
+	b.WriteByte('\n')
+	for i, gid := range gids {
+		b.WriteString(strconv.Itoa(gid))
+		if i < len(gids)-1 {
+			b.WriteByte(',')
+		}
+		if width > 0 && (i+1)%width == 0 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// fromTMXPoints parses the points of a polygon or polyline.
+func fromTMXPoints(p *tmxPoints) ([]Point, error) {
+	if p == nil {
+		return nil, nil
+	}
+	var points []Point
+	for _, pair := range strings.Fields(p.Points) {
+		xs, ys, ok := strings.Cut(pair, ",")
+		x, errX := strconv.ParseFloat(xs, 64)
+		y, errY := strconv.ParseFloat(ys, 64)
+		if !ok || errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		points = append(points, Point{X: x, Y: y})
+	}
+	return points, nil
+}
+
+// toTMXPoints formats the points of a polygon or polyline for TMX output.
+func toTMXPoints(points []Point) *tmxPoints {
+	if len(points) == 0 {
+		return nil
+	}
+	pairs := make([]string, len(points))
+	for i, pt := range points {
+		pairs[i] = strconv.FormatFloat(pt.X, 'f', -1, 64) + "," + strconv.FormatFloat(pt.Y, 'f', -1, 64)
+	}
+	return &tmxPoints{Points: strings.Join(pairs, " ")}
+}
+
+// fromTMXProps converts TMX properties, parsing values by their type.
+func fromTMXProps(p *tmxProps) ([]Property, error) {
+	if p == nil {
+		return nil, nil
+	}
+	props := make([]Property, 0, len(p.Properties))
+	for _, tp := range p.Properties {
+		prop := Property{Name: tp.Name, Type: tp.Type}
+		switch tp.Type {
+		case "int", "float", "object":
+			v, err := strconv.ParseFloat(tp.Value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: invalid %s %q", tp.Name, tp.Type, tp.Value)
+			}
+			prop.Value = v
+		case "bool":
+			v, err := strconv.ParseBool(tp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("property %q: invalid bool %q", tp.Name, tp.Value)
+			}
+			prop.Value = v
+		case "":
+			prop.Type = "string"
+			prop.Value = tp.Value
+		default:
+			prop.Value = tp.Value
+		}
+		props = append(props, prop)
+	}
+	return props, nil
+}
+
+// toTMXProps converts properties for TMX output.
+func toTMXProps(props []Property) *tmxProps {
+	if len(props) == 0 {
+		return nil
+	}
+	out := &tmxProps{}
+	for _, p := range props {
+		tp := tmxProperty{Name: p.Name, Type: p.Type}
+		if tp.Type == "string" {
+			tp.Type = "" // TMX omits the default type
+		}
+		switch v := p.Value.(type) {
+		case float64:
+			tp.Value = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			tp.Value = strconv.FormatBool(v)
+		case nil:
+		default:
+			tp.Value = fmt.Sprint(v)
+		}
+		out.Properties = append(out.Properties, tp)
+	}
+	return out
+}
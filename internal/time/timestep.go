@@ -15,8 +15,40 @@ const (
 	// MaxFrameTime prevents the spiral of death by clamping frame time.
 	// If a frame takes longer than this, extra time is discarded.
 	MaxFrameTime = 250 * time.Millisecond
+
+	// MaxStepsPerFrame is the default limit on fixed updates per frame.
+	// Time that doesn't fit waits for the following frames.
+	MaxStepsPerFrame = 5
+
+	// PanicThreshold is the default accumulator size at which the
+	// simulation gives up catching up and drops the backlog.
+	PanicThreshold = time.Second
 )
 
+// Config configures a Timestep. Zero fields use the defaults above.
+type Config struct {
+	// TickRate is the number of fixed updates per second, e.g. 60 or 120.
+	TickRate int
+
+	// MaxStepsPerFrame limits the fixed updates run in one frame, so a slow
+	// frame can't trigger ever more physics steps (the spiral of death).
+	MaxStepsPerFrame int
+
+	// PanicThreshold is how far the simulation may fall behind before the
+	// accumulator is reset instead of caught up.
+	PanicThreshold time.Duration
+}
+
+// DefaultConfig returns the default timestep settings: 60Hz, 5 steps per
+// frame, and a one second panic threshold.
+func DefaultConfig() Config {
+	return Config{
+		TickRate:         TargetFPS,
+		MaxStepsPerFrame: MaxStepsPerFrame,
+		PanicThreshold:   PanicThreshold,
+	}
+}
+
 // Timestep implements an accumulator pattern for fixed timestep updates.
 // This ensures physics runs at a consistent rate regardless of frame rate.
 type Timestep struct {
@@ -29,6 +61,15 @@ type Timestep struct {
 	// maxFrameTime is the maximum time that can be added per frame.
 	maxFrameTime time.Duration
 
+	// maxSteps is the maximum number of ticks consumed per frame.
+	maxSteps int
+
+	// panicThreshold is the accumulator size that triggers a panic reset.
+	panicThreshold time.Duration
+
+	// panics counts panic resets for debugging.
+	panics int
+
 	// stepsThisFrame tracks how many physics steps occurred in the current frame.
 	stepsThisFrame int
 
@@ -38,18 +79,35 @@ type Timestep struct {
 
 // NewTimestep creates a new fixed timestep controller with default settings.
 func NewTimestep() *Timestep {
+	return NewTimestepWithConfig(DefaultConfig())
+}
+
+// NewTimestepWithConfig creates a fixed timestep controller with the given
+// settings. Zero fields use the defaults.
+func NewTimestepWithConfig(cfg Config) *Timestep {
+	def := DefaultConfig()
+	if cfg.TickRate <= 0 {
+		cfg.TickRate = def.TickRate
+	}
+	if cfg.MaxStepsPerFrame <= 0 {
+		cfg.MaxStepsPerFrame = def.MaxStepsPerFrame
+	}
+	if cfg.PanicThreshold <= 0 {
+		cfg.PanicThreshold = def.PanicThreshold
+	}
 	return &Timestep{
-		accumulator:   0,
-		tick:          FixedTick,
-		maxFrameTime:  MaxFrameTime,
-		stepsThisFrame: 0,
-		totalTicks:    0,
+		tick:           time.Second / time.Duration(cfg.TickRate),
+		maxFrameTime:   MaxFrameTime,
+		maxSteps:       cfg.MaxStepsPerFrame,
+		panicThreshold: cfg.PanicThreshold,
 	}
 }
 
 // AddFrameTime adds elapsed time to the accumulator.
 // Call this once per frame with the frame delta time.
 // The time is clamped by maxFrameTime to prevent spiral of death.
+// If the accumulator still grows past the panic threshold because updates
+// can't keep up, the backlog is dropped (a panic reset).
 func (t *Timestep) AddFrameTime(dt time.Duration) {
 	// Reset step counter for this frame
 	t.stepsThisFrame = 0
@@ -59,12 +117,19 @@ func (t *Timestep) AddFrameTime(dt time.Duration) {
 		dt = t.maxFrameTime
 	}
 	t.accumulator += dt
+
+	if t.accumulator > t.panicThreshold {
+		t.accumulator = 0
+		t.panics++
+	}
 }
 
 // ShouldUpdate returns true if a fixed update should run.
 // Call this in a loop: for t.ShouldUpdate() { /* physics step */ t.ConsumeTick() }
+// It returns false once maxSteps ticks ran this frame; the remaining time
+// carries over to the next frame.
 func (t *Timestep) ShouldUpdate() bool {
-	return t.accumulator >= t.tick
+	return t.accumulator >= t.tick && t.stepsThisFrame < t.maxSteps
 }
 
 // ConsumeTick consumes one fixed tick from the accumulator.
@@ -77,9 +142,18 @@ func (t *Timestep) ConsumeTick() {
 
 // Alpha returns the interpolation factor for rendering.
 // This is used to interpolate between the previous and current physics state.
-// Value is in range [0.0, 1.0), where 0 = just stepped, ~1 = about to step.
+// Value is in range [0.0, 1.0], where 0 = just stepped, ~1 = about to step.
+// It's 1 when maxSteps left time for more ticks in the accumulator.
 func (t *Timestep) Alpha() float64 {
-	return float64(t.accumulator) / float64(t.tick)
+	return min(float64(t.accumulator)/float64(t.tick), 1)
+}
+
+// AlphaAfter returns Alpha as it would be elapsed after the last
+// AddFrameTime, capped at 1. Rendering may run more often than updates (e.g.
+// on a 144Hz display), so Draw uses the time since the last update to keep
+// interpolating between them.
+func (t *Timestep) AlphaAfter(elapsed time.Duration) float64 {
+	return min(float64(t.accumulator+elapsed)/float64(t.tick), 1)
 }
 
 // ResetFrame resets the per-frame step counter.
@@ -88,6 +162,11 @@ func (t *Timestep) ResetFrame() {
 	t.stepsThisFrame = 0
 }
 
+// TickRate returns the number of fixed updates per second.
+func (t *Timestep) TickRate() int {
+	return int(time.Second / t.tick)
+}
+
 // TickDuration returns the fixed tick duration.
 func (t *Timestep) TickDuration() time.Duration {
 	return t.tick
@@ -109,6 +188,11 @@ func (t *Timestep) TotalTicks() int {
 	return t.totalTicks
 }
 
+// Panics returns the number of panic resets since creation.
+func (t *Timestep) Panics() int {
+	return t.panics
+}
+
 // Accumulator returns the current accumulator value for debugging.
 func (t *Timestep) Accumulator() time.Duration {
 	return t.accumulator
@@ -0,0 +1,70 @@
+package time
+
+import (
+	"testing"
+	"time"
+)
+
+// runFrame adds dt and returns the number of fixed updates run.
+func runFrame(ts *Timestep, dt time.Duration) int {
+	ts.AddFrameTime(dt)
+	steps := 0
+	for ts.ShouldUpdate() {
+		ts.ConsumeTick()
+		steps++
+	}
+	return steps
+}
+
+func TestTimestepTickRate(t *testing.T) {
+	ts := NewTimestepWithConfig(Config{TickRate: 120})
+	if ts.TickRate() != 120 {
+		t.Errorf("TickRate() = %d, want 120", ts.TickRate())
+	}
+	if got := runFrame(ts, time.Second/60); got != 2 {
+		t.Errorf("ran %d steps in a 60Hz frame at 120Hz, want 2", got)
+	}
+
+	if got := NewTimestepWithConfig(Config{}).TickRate(); got != TargetFPS {
+		t.Errorf("zero config TickRate() = %d, want the default %d", got, TargetFPS)
+	}
+}
+
+func TestTimestepMaxSteps(t *testing.T) {
+	ts := NewTimestepWithConfig(Config{TickRate: 60, MaxStepsPerFrame: 3})
+
+	// A 100ms hitch is 6 ticks: 3 now, the rest over the next frames
+	if got := runFrame(ts, 100*time.Millisecond); got != 3 {
+		t.Errorf("ran %d steps after a hitch, want 3", got)
+	}
+	if ts.Alpha() != 1 {
+		t.Errorf("Alpha() = %v with ticks left over, want 1", ts.Alpha())
+	}
+	if got := runFrame(ts, 0); got != 3 {
+		t.Errorf("ran %d steps on the next frame, want the remaining 3", got)
+	}
+	if got := runFrame(ts, 0); got != 0 {
+		t.Errorf("ran %d steps once caught up, want 0", got)
+	}
+	if ts.Panics() != 0 {
+		t.Errorf("Panics() = %d, want 0", ts.Panics())
+	}
+}
+
+func TestTimestepPanicReset(t *testing.T) {
+	ts := NewTimestepWithConfig(Config{TickRate: 60, MaxStepsPerFrame: 1, PanicThreshold: 300 * time.Millisecond})
+
+	// Each slow frame adds 250ms but only one 16ms step runs, so the
+	// backlog grows until it passes the threshold and is dropped
+	runFrame(ts, 250*time.Millisecond)
+	if ts.Panics() != 0 {
+		t.Fatalf("Panics() = %d after one slow frame, want 0", ts.Panics())
+	}
+	runFrame(ts, 250*time.Millisecond)
+	if ts.Panics() != 1 {
+		t.Errorf("Panics() = %d after falling behind, want 1", ts.Panics())
+	}
+	if ts.Accumulator() != 0 {
+		t.Errorf("Accumulator() = %v after a panic reset, want 0", ts.Accumulator())
+	}
+}
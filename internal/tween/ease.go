@@ -0,0 +1,41 @@
+package tween
+
+import "github.com/torsten/GoP/internal/mathx"
+
+// Ease maps a tween's linear progress t in [0, 1] to an eased fraction.
+type Ease func(t float64) float64
+
+// Easing curves, by the names level properties use for them.
+var (
+	Linear    Ease = mathx.Clamp01
+	InQuad    Ease = mathx.EaseInQuad
+	OutQuad   Ease = mathx.EaseOutQuad
+	InOutQuad Ease = mathx.EaseInOutQuad
+	OutCubic  Ease = mathx.EaseOutCubic
+	Smooth    Ease = mathx.SmoothStep
+)
+
+// eases maps names to curves for ParseEase.
+var eases = map[string]Ease{
+	"linear":    Linear,
+	"inQuad":    InQuad,
+	"outQuad":   OutQuad,
+	"inOutQuad": InOutQuad,
+	"outCubic":  OutCubic,
+	"smooth":    Smooth,
+}
+
+// EaseNames lists the names ParseEase accepts.
+var EaseNames = []string{"linear", "inQuad", "outQuad", "inOutQuad", "outCubic", "smooth"}
+
+// ParseEase returns the curve with the given name. "" and unknown names
+// return Linear; ok is false only for unknown names.
+func ParseEase(name string) (ease Ease, ok bool) {
+	if name == "" {
+		return Linear, true
+	}
+	if e, ok := eases[name]; ok {
+		return e, true
+	}
+	return Linear, false
+}
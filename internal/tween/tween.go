@@ -0,0 +1,226 @@
+// Package tween animates values over time with easing curves.
+//
+// A Tween moves a float64 or mathx.Vec2 from one value to another over a
+// duration, optionally after a delay, and calls OnComplete when it gets
+// there. Tweens and the Wait and Call steps are Animations, which a Sequence
+// plays one after another. Nothing here runs by itself: the owner calls
+// Update with the elapsed time, usually from a fixed update.
+package tween
+
+import "github.com/torsten/GoP/internal/mathx"
+
+// Value is a type a Tween can animate.
+type Value interface {
+	float64 | mathx.Vec2
+}
+
+// Lerp returns the value t of the way from a to b. t isn't clamped.
+func Lerp[T Value](a, b T, t float64) T {
+	switch a := any(a).(type) {
+	case float64:
+		return any(mathx.Lerp(a, any(b).(float64), t)).(T)
+	case mathx.Vec2:
+		return any(a.Lerp(any(b).(mathx.Vec2), t)).(T)
+	}
+	panic("unreachable")
+}
+
+// Animation is something that plays out over time.
+type Animation interface {
+	// Update advances the animation by dt seconds and returns whether it is
+	// done.
+	Update(dt float64) bool
+	// Done returns whether the animation has finished.
+	Done() bool
+	// Reset rewinds the animation to its start.
+	Reset()
+}
+
+// overtimer is implemented by animations that take time, to report how far
+// the last Update ran past their end. Sequences pass that time on to the
+// next step; steps without it take no time.
+type overtimer interface {
+	overtime() float64
+}
+
+// Tween animates a value from From to To.
+type Tween[T Value] struct {
+	From, To T
+	// Duration is how long the change takes, in seconds; 0 jumps to To
+	Duration float64
+	// Delay is how long to hold From before starting, in seconds
+	Delay float64
+	// Ease shapes the change; nil is Linear
+	Ease Ease
+	// OnComplete is called once when the tween reaches To
+	OnComplete func()
+
+	elapsed float64 // Including the delay
+	done    bool
+}
+
+// New creates a tween from from to to over duration seconds.
+func New[T Value](from, to T, duration float64, ease Ease) *Tween[T] {
+	return &Tween[T]{From: from, To: to, Duration: duration, Ease: ease}
+}
+
+// Update implements Animation.
+func (tw *Tween[T]) Update(dt float64) bool {
+	if tw.done {
+		return true
+	}
+	tw.elapsed += dt
+	if tw.elapsed >= tw.Delay+tw.Duration {
+		tw.done = true
+		if tw.OnComplete != nil {
+			tw.OnComplete()
+		}
+	}
+	return tw.done
+}
+
+// Done implements Animation.
+func (tw *Tween[T]) Done() bool {
+	return tw.done
+}
+
+// Reset implements Animation.
+func (tw *Tween[T]) Reset() {
+	tw.elapsed = 0
+	tw.done = false
+}
+
+func (tw *Tween[T]) overtime() float64 {
+	return max(0, tw.elapsed-tw.Delay-tw.Duration)
+}
+
+// Value returns the current value.
+func (tw *Tween[T]) Value() T {
+	return tw.valueAt(tw.elapsed)
+}
+
+// Peek returns the value the tween will have after another dt seconds,
+// without advancing it, e.g. to check a move is allowed first.
+func (tw *Tween[T]) Peek(dt float64) T {
+	return tw.valueAt(tw.elapsed + dt)
+}
+
+// Retarget restarts the tween from its current value toward to, taking
+// duration seconds and skipping the delay. The ease and callback stay.
+func (tw *Tween[T]) Retarget(to T, duration float64) {
+	tw.From = tw.Value()
+	tw.To = to
+	tw.Duration = duration
+	tw.Delay = 0
+	tw.Reset()
+}
+
+// valueAt returns the value elapsed seconds after the start.
+func (tw *Tween[T]) valueAt(elapsed float64) T {
+	t := 0.0
+	switch {
+	case elapsed < tw.Delay:
+	case elapsed >= tw.Delay+tw.Duration:
+		t = 1
+	default:
+		t = (elapsed - tw.Delay) / tw.Duration
+	}
+	ease := tw.Ease
+	if ease == nil {
+		ease = Linear
+	}
+	return Lerp(tw.From, tw.To, ease(t))
+}
+
+// wait is an Animation that does nothing for a while.
+type wait struct {
+	duration, elapsed float64
+}
+
+// Wait returns an Animation that lasts seconds and does nothing, for delays
+// in a Sequence.
+func Wait(seconds float64) Animation {
+	return &wait{duration: seconds}
+}
+
+func (w *wait) Update(dt float64) bool {
+	w.elapsed += dt
+	return w.Done()
+}
+
+func (w *wait) Done() bool { return w.elapsed >= w.duration }
+
+func (w *wait) Reset() { w.elapsed = 0 }
+
+func (w *wait) overtime() float64 { return max(0, w.elapsed-w.duration) }
+
+// call is an Animation that runs a function once.
+type call struct {
+	fn   func()
+	done bool
+}
+
+// Call returns an Animation that calls fn and is done straight away, for
+// callbacks in a Sequence.
+func Call(fn func()) Animation {
+	return &call{fn: fn}
+}
+
+func (c *call) Update(float64) bool {
+	if !c.done {
+		c.done = true
+		c.fn()
+	}
+	return true
+}
+
+func (c *call) Done() bool { return c.done }
+
+func (c *call) Reset() { c.done = false }
+
+// Sequence plays animations one after another. It is itself an Animation,
+// so sequences nest.
+type Sequence struct {
+	steps   []Animation
+	current int
+	over    float64 // Time the last step ran past the end
+}
+
+// NewSequence creates a sequence of steps.
+func NewSequence(steps ...Animation) *Sequence {
+	return &Sequence{steps: steps}
+}
+
+// Update implements Animation. Time left over when a step finishes goes to
+// the next one, so steps that take no time (Call, zero-length tweens) run in
+// the same update as the step before them.
+func (s *Sequence) Update(dt float64) bool {
+	for s.current < len(s.steps) {
+		step := s.steps[s.current]
+		if !step.Update(dt) {
+			return false
+		}
+		s.current++
+		if o, ok := step.(overtimer); ok {
+			dt = o.overtime()
+		}
+	}
+	s.over = dt
+	return true
+}
+
+func (s *Sequence) overtime() float64 { return s.over }
+
+// Done implements Animation.
+func (s *Sequence) Done() bool {
+	return s.current >= len(s.steps)
+}
+
+// Reset implements Animation, rewinding every step.
+func (s *Sequence) Reset() {
+	for _, step := range s.steps {
+		step.Reset()
+	}
+	s.current = 0
+	s.over = 0
+}
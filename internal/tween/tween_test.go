@@ -0,0 +1,99 @@
+package tween
+
+import (
+	"math"
+	"testing"
+
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestTweenFloat(t *testing.T) {
+	tw := New(10.0, 20.0, 1, nil)
+	completed := 0
+	tw.OnComplete = func() { completed++ }
+
+	if tw.Update(0.25) || !approxEqual(tw.Value(), 12.5) {
+		t.Errorf("Expected 12.5 and not done a quarter in, got %v (done %v)", tw.Value(), tw.Done())
+	}
+	if !approxEqual(tw.Peek(0.25), 15) || !approxEqual(tw.Value(), 12.5) {
+		t.Errorf("Expected Peek to see 15 without moving, got %v (value %v)", tw.Peek(0.25), tw.Value())
+	}
+	if !tw.Update(1) || tw.Value() != 20 {
+		t.Errorf("Expected 20 and done past the end, got %v (done %v)", tw.Value(), tw.Done())
+	}
+	tw.Update(1)
+	if completed != 1 {
+		t.Errorf("Expected OnComplete once, got %d", completed)
+	}
+}
+
+func TestTweenVec2WithEaseAndDelay(t *testing.T) {
+	tw := New(mathx.V(0, 0), mathx.V(100, 50), 1, InQuad)
+	tw.Delay = 0.5
+
+	tw.Update(0.5)
+	if tw.Value() != (mathx.Vec2{}) {
+		t.Errorf("Expected the start during the delay, got %v", tw.Value())
+	}
+	tw.Update(0.5)
+	if v := tw.Value(); !approxEqual(v.X, 25) || !approxEqual(v.Y, 12.5) {
+		t.Errorf("Expected InQuad at a quarter of the way halfway through, got %v", v)
+	}
+}
+
+func TestTweenRetarget(t *testing.T) {
+	tw := New(0.0, 1.0, 1, nil)
+	tw.Update(0.5)
+	tw.Retarget(0, 0.25)
+	if tw.Done() || tw.From != 0.5 {
+		t.Fatalf("Expected a fresh tween from 0.5, got from %v (done %v)", tw.From, tw.Done())
+	}
+	tw.Update(0.125)
+	if !approxEqual(tw.Value(), 0.25) {
+		t.Errorf("Expected 0.25 halfway back, got %v", tw.Value())
+	}
+}
+
+func TestSequence(t *testing.T) {
+	var log []string
+	fade := New(0.0, 1.0, 1, nil)
+	seq := NewSequence(
+		Call(func() { log = append(log, "start") }),
+		Wait(0.5),
+		fade,
+		Call(func() { log = append(log, "end") }),
+	)
+
+	seq.Update(0.25)
+	if len(log) != 1 || fade.Value() != 0 {
+		t.Fatalf("Expected only the start callback during the wait, got %v (fade %v)", log, fade.Value())
+	}
+	seq.Update(0.25) // Wait ends
+	seq.Update(0.5)
+	if !approxEqual(fade.Value(), 0.5) || seq.Done() {
+		t.Errorf("Expected the fade halfway, got %v (done %v)", fade.Value(), seq.Done())
+	}
+	if !seq.Update(0.5) || len(log) != 2 {
+		t.Errorf("Expected the end callback with the fade, got %v (done %v)", log, seq.Done())
+	}
+
+	seq.Reset()
+	if seq.Done() || fade.Done() {
+		t.Error("Expected Reset to rewind every step")
+	}
+}
+
+func TestParseEase(t *testing.T) {
+	for _, name := range EaseNames {
+		if _, ok := ParseEase(name); !ok {
+			t.Errorf("ParseEase(%q) not found", name)
+		}
+	}
+	if e, ok := ParseEase("bounce"); ok || e(0.5) != 0.5 {
+		t.Errorf("Expected unknown eases to fall back to linear and report it")
+	}
+}
@@ -0,0 +1,118 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TileFrame is one frame of an animated tile: the tile shown and for how
+// long.
+type TileFrame struct {
+	TileID   int // Local tile ID (0-indexed)
+	Duration time.Duration
+}
+
+// tsjTileset is the part of a Tiled tileset file (.tsj) holding tile
+// animations.
+type tsjTileset struct {
+	Tiles []struct {
+		ID        int `json:"id"`
+		Animation []struct {
+			TileID   int `json:"tileid"`
+			Duration int `json:"duration"` // Milliseconds
+		} `json:"animation"`
+	} `json:"tiles"`
+}
+
+// ParseTileAnimations reads the tile animations of a Tiled tileset file
+// (.tsj), by local tile ID.
+func ParseTileAnimations(data []byte) (map[int][]TileFrame, error) {
+	var ts tsjTileset
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return nil, fmt.Errorf("failed to parse tileset: %w", err)
+	}
+	anims := make(map[int][]TileFrame)
+	for _, tile := range ts.Tiles {
+		if len(tile.Animation) == 0 {
+			continue
+		}
+		frames := make([]TileFrame, len(tile.Animation))
+		for i, f := range tile.Animation {
+			if f.Duration <= 0 {
+				return nil, fmt.Errorf("tile %d: frame %d has no duration", tile.ID, i)
+			}
+			frames[i] = TileFrame{TileID: f.TileID, Duration: time.Duration(f.Duration) * time.Millisecond}
+		}
+		anims[tile.ID] = frames
+	}
+	return anims, nil
+}
+
+// LoadAnimations sets the tileset's tile animations from a Tiled tileset
+// file (.tsj), replacing any it had.
+func (t *Tileset) LoadAnimations(data []byte) error {
+	anims, err := ParseTileAnimations(data)
+	if err != nil {
+		return err
+	}
+	t.SetAnimations(anims)
+	return nil
+}
+
+// SetAnimations sets the tileset's tile animations, by local tile ID.
+// Frames showing tiles outside the tileset are dropped.
+func (t *Tileset) SetAnimations(anims map[int][]TileFrame) {
+	t.animations = make(map[int]tileAnimation, len(anims))
+	for id, frames := range anims {
+		var anim tileAnimation
+		for _, f := range frames {
+			if f.TileID < 0 || f.TileID >= len(t.tiles) || f.Duration <= 0 {
+				continue
+			}
+			anim.frames = append(anim.frames, f)
+			anim.total += f.Duration
+		}
+		if len(anim.frames) > 0 {
+			t.animations[id] = anim
+		}
+	}
+}
+
+// HasAnimations reports whether any tile of the tileset is animated.
+func (t *Tileset) HasAnimations() bool {
+	return len(t.animations) > 0
+}
+
+// Animated reports whether the tile with the given local ID is animated.
+func (t *Tileset) Animated(id int) bool {
+	_, ok := t.animations[id]
+	return ok
+}
+
+// FrameAt returns the local ID of the tile shown for the tile with the
+// given local ID after elapsed time: the animation frame looping from time
+// 0, or the tile itself if it isn't animated.
+func (t *Tileset) FrameAt(id int, elapsed time.Duration) int {
+	anim, ok := t.animations[id]
+	if !ok {
+		return id
+	}
+	at := elapsed % anim.total
+	if at < 0 {
+		at += anim.total
+	}
+	for _, f := range anim.frames {
+		if at < f.Duration {
+			return f.TileID
+		}
+		at -= f.Duration
+	}
+	return anim.frames[len(anim.frames)-1].TileID
+}
+
+// tileAnimation is a tile's frames and their total duration.
+type tileAnimation struct {
+	frames []TileFrame
+	total  time.Duration
+}
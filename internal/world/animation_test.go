@@ -0,0 +1,93 @@
+//go:build display
+
+package world
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const animatedTSJ = `{
+  "type": "tileset",
+  "tiles": [
+    {"id": 0, "properties": [{"name": "name", "type": "string", "value": "grass"}]},
+    {"id": 1, "animation": [{"tileid": 1, "duration": 300}, {"tileid": 2, "duration": 100}]}
+  ]
+}`
+
+func TestParseTileAnimations(t *testing.T) {
+	anims, err := ParseTileAnimations([]byte(animatedTSJ))
+	if err != nil {
+		t.Fatalf("ParseTileAnimations failed: %v", err)
+	}
+	want := []TileFrame{{TileID: 1, Duration: 300 * time.Millisecond}, {TileID: 2, Duration: 100 * time.Millisecond}}
+	if len(anims) != 1 || len(anims[1]) != 2 || anims[1][0] != want[0] || anims[1][1] != want[1] {
+		t.Errorf("parsed %v, want tile 1 animated as %v", anims, want)
+	}
+
+	if _, err := ParseTileAnimations([]byte(`{"tiles": [{"id": 1, "animation": [{"tileid": 1}]}]}`)); err == nil {
+		t.Error("ParseTileAnimations accepted a frame without duration")
+	}
+}
+
+func TestTilesetFrameAt(t *testing.T) {
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 48, 16)), 16, 16)
+	if err := tileset.LoadAnimations([]byte(animatedTSJ)); err != nil {
+		t.Fatalf("LoadAnimations failed: %v", err)
+	}
+
+	tests := []struct {
+		id      int
+		elapsed time.Duration
+		want    int
+	}{
+		{0, time.Second, 0}, // Not animated
+		{1, 0, 1},
+		{1, 299 * time.Millisecond, 1},
+		{1, 300 * time.Millisecond, 2},
+		{1, 400 * time.Millisecond, 1}, // Loops
+		{1, 750 * time.Millisecond, 2},
+	}
+	for _, tt := range tests {
+		if got := tileset.FrameAt(tt.id, tt.elapsed); got != tt.want {
+			t.Errorf("FrameAt(%d, %v) = %d, want %d", tt.id, tt.elapsed, got, tt.want)
+		}
+	}
+
+	// Frames outside the tileset are dropped
+	tileset.SetAnimations(map[int][]TileFrame{0: {{TileID: 9, Duration: time.Second}}})
+	if tileset.HasAnimations() {
+		t.Error("animation showing only a missing tile was kept")
+	}
+}
+
+func TestChunkCacheDrawsAnimatedTiles(t *testing.T) {
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 48, 16)), 16, 16)
+	if err := tileset.LoadAnimations([]byte(animatedTSJ)); err != nil {
+		t.Fatalf("LoadAnimations failed: %v", err)
+	}
+	layer := &TileLayer{name: "Tiles", width: 16, height: 16, data: make([]int, 16*16)}
+	layer.SetTile(0, 0, 1) // Static
+	layer.SetTile(3, 2, 2) // Animated
+
+	cache := NewChunkCache(tileset, 16, 16)
+	cache.BeginFrame()
+	cache.DrawLayer(ebiten.NewImage(256, 256), layer, 0, 0, 1, 256, 256)
+	if got := cache.DrawCalls(); got != 2 {
+		t.Errorf("drew %d images, want the chunk and the animated tile on top", got)
+	}
+	if ch := cache.layers[layer][chunkKey{0, 0}]; len(ch.animated) != 1 || ch.animated[0] != 2*16+3 {
+		t.Errorf("chunk's animated tiles are %v, want [%d]", ch.animated, 2*16+3)
+	}
+
+	// A chunk of animated tiles only has no image of its own
+	layer.SetTile(0, 0, 0)
+	cache.BeginFrame()
+	cache.DrawLayer(ebiten.NewImage(256, 256), layer, 0, 0, 1, 256, 256)
+	if got := cache.DrawCalls(); got != 1 {
+		t.Errorf("drew %d images for a chunk of one animated tile, want 1", got)
+	}
+}
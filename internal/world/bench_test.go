@@ -0,0 +1,118 @@
+//go:build display
+
+package world
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+// stressLevel returns the JSON of a generated width x height level with
+// objects objects.
+func stressLevel(b *testing.B, width, height, objects int) []byte {
+	b.Helper()
+	m, err := tiled.StressMap(width, height, objects, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	data, err := m.EncodeJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+// stressCollision returns the collision map of a generated level.
+func stressCollision(b *testing.B, width, height int) *CollisionMap {
+	b.Helper()
+	md, err := ParseTiledJSON(stressLevel(b, width, height, 0))
+	if err != nil {
+		b.Fatal(err)
+	}
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 128, 128)), 16, 16)
+	return NewCollisionMapFromMap(NewMap(md, tileset), "Collision")
+}
+
+func BenchmarkParseTiledJSON1000x1000(b *testing.B) {
+	data := stressLevel(b, 1000, 1000, 0)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseTiledJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseObjects10000(b *testing.B) {
+	data := stressLevel(b, 200, 50, 10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseObjects(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewCollisionMapFromMap1000x1000(b *testing.B) {
+	md, err := ParseTiledJSON(stressLevel(b, 1000, 1000, 0))
+	if err != nil {
+		b.Fatal(err)
+	}
+	m := NewMap(md, NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 128, 128)), 16, 16))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewCollisionMapFromMap(m, "Collision")
+	}
+}
+
+func BenchmarkCollisionMapOverlapsSolid(b *testing.B) {
+	cm := stressCollision(b, 1000, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// A player-sized box sweeping over the level
+		x := float64(i*7%15000) + 0.5
+		y := float64(i*13%15000) + 0.5
+		cm.OverlapsSolid(x, y, 14, 30)
+	}
+}
+
+func BenchmarkCollisionMapIsSolidAtWorld(b *testing.B) {
+	cm := stressCollision(b, 1000, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cm.IsSolidAtWorld(float64(i*7%16000), float64(i*13%16000))
+	}
+}
+
+func BenchmarkSparseCollisionMapOverlapsSolid(b *testing.B) {
+	grid := NewSparseSolidGrid()
+	for tx := -500; tx < 500; tx += 3 {
+		grid.SetSolid(tx, tx/2, true)
+	}
+	cm := NewCollisionMap(grid, 16, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := float64(i*7%16000 - 8000)
+		cm.OverlapsSolid(x, x/2, 14, 30)
+	}
+}
+
+func BenchmarkChunkCacheDrawLayer(b *testing.B) {
+	md, err := ParseTiledJSON(stressLevel(b, 1000, 1000, 0))
+	if err != nil {
+		b.Fatal(err)
+	}
+	layer := md.Layer("Tiles")
+	cache := NewChunkCache(NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 128, 128)), 16, 16), 16, 16)
+	screen := ebiten.NewImage(1280, 720)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Scrolling right, so chunks come into view and get rendered
+		cache.BeginFrame()
+		cache.DrawLayer(screen, layer, float64(i*4%15000), 15400, 1, 1280, 720)
+	}
+}
@@ -0,0 +1,68 @@
+package world
+
+// BoundsPolicy defines what happens when the player leaves the level area.
+type BoundsPolicy string
+
+const (
+	// BoundsKill kills the player when they fall below the kill line.
+	BoundsKill BoundsPolicy = "kill"
+	// BoundsClamp keeps the player inside the level rectangle.
+	BoundsClamp BoundsPolicy = "clamp"
+	// BoundsWrap wraps the player around to the opposite edge.
+	BoundsWrap BoundsPolicy = "wrap"
+)
+
+// Map property names for level bounds configuration.
+const (
+	// PropBoundsPolicy is the map property selecting the BoundsPolicy.
+	PropBoundsPolicy = "boundsPolicy"
+	// PropKillY is the map property overriding the kill line Y position.
+	PropKillY = "killY"
+)
+
+// DefaultKillMargin is how far below the bottom of the map the default kill line sits.
+const DefaultKillMargin = 32.0
+
+// LevelBounds describes the playable area of a level and how its edges behave.
+type LevelBounds struct {
+	Policy BoundsPolicy
+	Width  float64 // Level width in pixels
+	Height float64 // Level height in pixels
+	KillY  float64 // Y position below which the player dies (if Kill is set)
+	Kill   bool    // True if the kill line is active
+}
+
+// NewLevelBounds builds the bounds configuration for a level.
+// The policy and kill line are read from map properties. Kill plane objects
+// mark a kill line at their top edge that spans the whole level width and
+// applies under any policy; the highest one wins.
+func NewLevelBounds(props map[string]any, objects []ObjectData, width, height float64) LevelBounds {
+	b := LevelBounds{
+		Policy: BoundsKill,
+		Width:  width,
+		Height: height,
+		KillY:  height + DefaultKillMargin,
+	}
+
+	if policy, ok := props[PropBoundsPolicy].(string); ok {
+		switch BoundsPolicy(policy) {
+		case BoundsKill, BoundsClamp, BoundsWrap:
+			b.Policy = BoundsPolicy(policy)
+		}
+	}
+	b.Kill = b.Policy == BoundsKill
+
+	if killY, ok := props[PropKillY].(float64); ok {
+		b.KillY = killY
+	}
+
+	// Kill planes always kill, regardless of policy
+	for _, obj := range FilterObjectsByType(objects, ObjectTypeKillPlane) {
+		if !b.Kill || obj.Y < b.KillY {
+			b.KillY = obj.Y
+		}
+		b.Kill = true
+	}
+
+	return b
+}
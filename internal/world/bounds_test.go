@@ -0,0 +1,83 @@
+//go:build display
+
+package world
+
+import "testing"
+
+func TestNewLevelBounds(t *testing.T) {
+	killPlane := func(y float64) ObjectData {
+		return ObjectData{Type: ObjectTypeKillPlane, Y: y, W: 320, H: 16}
+	}
+	tests := []struct {
+		name    string
+		props   map[string]any
+		objects []ObjectData
+		want    LevelBounds
+	}{
+		{
+			name: "no properties",
+			want: LevelBounds{Policy: BoundsKill, Width: 320, Height: 240, KillY: 240 + DefaultKillMargin, Kill: true},
+		},
+		{
+			name:  "clamp",
+			props: map[string]any{PropBoundsPolicy: "clamp"},
+			want:  LevelBounds{Policy: BoundsClamp, Width: 320, Height: 240, KillY: 240 + DefaultKillMargin},
+		},
+		{
+			name:  "wrap",
+			props: map[string]any{PropBoundsPolicy: "wrap"},
+			want:  LevelBounds{Policy: BoundsWrap, Width: 320, Height: 240, KillY: 240 + DefaultKillMargin},
+		},
+		{
+			name:  "unknown policy",
+			props: map[string]any{PropBoundsPolicy: "bounce"},
+			want:  LevelBounds{Policy: BoundsKill, Width: 320, Height: 240, KillY: 240 + DefaultKillMargin, Kill: true},
+		},
+		{
+			name:  "kill line property",
+			props: map[string]any{PropKillY: 200.0},
+			want:  LevelBounds{Policy: BoundsKill, Width: 320, Height: 240, KillY: 200, Kill: true},
+		},
+		{
+			name:  "kill line property that isn't a number",
+			props: map[string]any{PropKillY: "200"},
+			want:  LevelBounds{Policy: BoundsKill, Width: 320, Height: 240, KillY: 240 + DefaultKillMargin, Kill: true},
+		},
+		{
+			name:    "kill plane under clamp",
+			props:   map[string]any{PropBoundsPolicy: "clamp"},
+			objects: []ObjectData{killPlane(300)},
+			want:    LevelBounds{Policy: BoundsClamp, Width: 320, Height: 240, KillY: 300, Kill: true},
+		},
+		{
+			name:    "kill plane under wrap",
+			props:   map[string]any{PropBoundsPolicy: "wrap", PropKillY: 100.0},
+			objects: []ObjectData{killPlane(180)},
+			want:    LevelBounds{Policy: BoundsWrap, Width: 320, Height: 240, KillY: 180, Kill: true},
+		},
+		{
+			name:    "kill plane above the kill line",
+			objects: []ObjectData{killPlane(150)},
+			want:    LevelBounds{Policy: BoundsKill, Width: 320, Height: 240, KillY: 150, Kill: true},
+		},
+		{
+			name:    "kill plane below the kill line",
+			props:   map[string]any{PropKillY: 100.0},
+			objects: []ObjectData{killPlane(150)},
+			want:    LevelBounds{Policy: BoundsKill, Width: 320, Height: 240, KillY: 100, Kill: true},
+		},
+		{
+			name:    "highest kill plane wins",
+			props:   map[string]any{PropBoundsPolicy: "clamp"},
+			objects: []ObjectData{killPlane(220), killPlane(120), killPlane(180)},
+			want:    LevelBounds{Policy: BoundsClamp, Width: 320, Height: 240, KillY: 120, Kill: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLevelBounds(tt.props, tt.objects, 320, 240); got != tt.want {
+				t.Errorf("NewLevelBounds = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
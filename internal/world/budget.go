@@ -0,0 +1,123 @@
+package world
+
+import "fmt"
+
+// Map property names for level complexity budgets.
+const (
+	PropMaxObjects    = "maxObjects"    // Max number of objects
+	PropMaxKinematics = "maxKinematics" // Max number of moving platforms
+	PropMaxTriggers   = "maxTriggers"   // Max number of trigger objects
+	PropMaxRules      = "maxRules"      // Max number of rules in the level's rules file
+)
+
+// BudgetProperties are the budget map properties. Only the editor and level
+// checks read them, so shipped levels can leave them out.
+var BudgetProperties = []string{PropMaxObjects, PropMaxKinematics, PropMaxTriggers, PropMaxRules}
+
+// DefaultBudget keeps levels within the performance targets for low-end hardware.
+var DefaultBudget = LevelBudget{
+	MaxObjects:    256,
+	MaxKinematics: 16,
+	MaxTriggers:   96,
+	MaxRules:      64,
+}
+
+// LevelBudget limits how complex a level may get. A limit of 0 means unlimited.
+type LevelBudget struct {
+	MaxObjects    int
+	MaxKinematics int
+	MaxTriggers   int
+	MaxRules      int
+}
+
+// NewLevelBudget builds the budget for a level from its map properties.
+// Limits that aren't set keep DefaultBudget; zero or a negative value disables the limit.
+func NewLevelBudget(props map[string]any) LevelBudget {
+	b := DefaultBudget
+	readLimit(props, PropMaxObjects, &b.MaxObjects)
+	readLimit(props, PropMaxKinematics, &b.MaxKinematics)
+	readLimit(props, PropMaxTriggers, &b.MaxTriggers)
+	readLimit(props, PropMaxRules, &b.MaxRules)
+	return b
+}
+
+// readLimit reads a numeric budget property into dst.
+func readLimit(props map[string]any, name string, dst *int) {
+	switch v := props[name].(type) {
+	case float64:
+		*dst = max(int(v), 0)
+	case int:
+		*dst = max(v, 0)
+	}
+}
+
+// LevelUsage counts what a level uses of its budget.
+type LevelUsage struct {
+	Objects    int
+	Kinematics int
+	Triggers   int
+	Rules      int
+}
+
+// CountUsage counts a level's objects by budget category.
+// Kinematics are moving platforms; triggers are the objects the player can
+// touch to set something off.
+func CountUsage(objects []ObjectData, rules int) LevelUsage {
+	u := LevelUsage{Objects: len(objects), Rules: rules}
+	for _, obj := range objects {
+		switch obj.Type {
+		case ObjectTypePlatform:
+			u.Kinematics++
+		case ObjectTypeHazard, ObjectTypeCheckpoint, ObjectTypeGoal, ObjectTypeSwitch, ObjectTypeCollectible, ObjectTypeKey, ObjectTypeExit:
+			u.Triggers++
+		}
+	}
+	return u
+}
+
+// BudgetIssue describes a budget limit the level exceeds.
+type BudgetIssue struct {
+	Property string // Map property of the limit
+	Used     int
+	Limit    int
+	What     string // Human-readable name, e.g. "objects"
+}
+
+// String returns a description like "42 objects exceed the budget of 40".
+func (i BudgetIssue) String() string {
+	return fmt.Sprintf("%d %s exceed the budget of %d", i.Used, i.What, i.Limit)
+}
+
+// Check returns the limits that u exceeds.
+func (b LevelBudget) Check(u LevelUsage) []BudgetIssue {
+	var issues []BudgetIssue
+	check := func(prop, what string, used, limit int) {
+		if limit > 0 && used > limit {
+			issues = append(issues, BudgetIssue{Property: prop, Used: used, Limit: limit, What: what})
+		}
+	}
+	check(PropMaxObjects, "objects", u.Objects, b.MaxObjects)
+	check(PropMaxKinematics, "kinematics", u.Kinematics, b.MaxKinematics)
+	check(PropMaxTriggers, "triggers", u.Triggers, b.MaxTriggers)
+	check(PropMaxRules, "rules", u.Rules, b.MaxRules)
+	return issues
+}
+
+// Summary returns a compact usage line like "Obj 12/256 Kin 2/16 Trig 5/96 Rules 0/64".
+// Exceeded counts are marked with "!".
+func (b LevelBudget) Summary(u LevelUsage) string {
+	part := func(label string, used, limit int) string {
+		if limit <= 0 {
+			return fmt.Sprintf("%s %d", label, used)
+		}
+		mark := ""
+		if used > limit {
+			mark = "!"
+		}
+		return fmt.Sprintf("%s %d/%d%s", label, used, limit, mark)
+	}
+	return part("Obj", u.Objects, b.MaxObjects) + " " +
+		part("Kin", u.Kinematics, b.MaxKinematics) + " " +
+		part("Trig", u.Triggers, b.MaxTriggers) + " " +
+		part("Rules", u.Rules, b.MaxRules)
+}
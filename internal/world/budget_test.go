@@ -0,0 +1,91 @@
+//go:build display
+
+package world
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewLevelBudget(t *testing.T) {
+	tests := []struct {
+		name  string
+		props map[string]any
+		want  LevelBudget
+	}{
+		{"no properties", nil, DefaultBudget},
+		{
+			"Tiled numbers",
+			map[string]any{PropMaxObjects: 40.0, PropMaxKinematics: 2.0, PropMaxTriggers: 10.0, PropMaxRules: 5.0},
+			LevelBudget{MaxObjects: 40, MaxKinematics: 2, MaxTriggers: 10, MaxRules: 5},
+		},
+		{
+			"ints, others default",
+			map[string]any{PropMaxTriggers: 12},
+			LevelBudget{MaxObjects: 256, MaxKinematics: 16, MaxTriggers: 12, MaxRules: 64},
+		},
+		{
+			"zero and negative disable",
+			map[string]any{PropMaxObjects: 0.0, PropMaxRules: -3.0, PropMaxKinematics: -1},
+			LevelBudget{MaxObjects: 0, MaxKinematics: 0, MaxTriggers: 96, MaxRules: 0},
+		},
+		{
+			"not a number",
+			map[string]any{PropMaxObjects: "10", PropMaxTriggers: true},
+			DefaultBudget,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLevelBudget(tt.props); got != tt.want {
+				t.Errorf("NewLevelBudget = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountUsage(t *testing.T) {
+	objects := []ObjectData{
+		{Type: ObjectTypeSpawn},
+		{Type: ObjectTypePlatform},
+		{Type: ObjectTypePlatform},
+		{Type: ObjectTypeHazard},
+		{Type: ObjectTypeCheckpoint},
+		{Type: ObjectTypeGoal},
+		{Type: ObjectTypeSwitch},
+		{Type: ObjectTypeCollectible},
+		{Type: ObjectTypeKey},
+		{Type: ObjectTypeExit},
+		{Type: ObjectTypeDoor},
+		{Type: ObjectTypePath},
+	}
+	want := LevelUsage{Objects: 12, Kinematics: 2, Triggers: 7, Rules: 3}
+	if got := CountUsage(objects, 3); got != want {
+		t.Errorf("CountUsage = %+v, want %+v", got, want)
+	}
+}
+
+func TestLevelBudgetCheck(t *testing.T) {
+	b := LevelBudget{MaxObjects: 10, MaxKinematics: 0, MaxTriggers: 4, MaxRules: 2}
+	u := LevelUsage{Objects: 12, Kinematics: 50, Triggers: 4, Rules: 3}
+
+	// At the limit is fine and 0 is unlimited
+	want := []BudgetIssue{
+		{Property: PropMaxObjects, Used: 12, Limit: 10, What: "objects"},
+		{Property: PropMaxRules, Used: 3, Limit: 2, What: "rules"},
+	}
+	issues := b.Check(u)
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("Check = %+v, want %+v", issues, want)
+	}
+	if got := issues[0].String(); got != "12 objects exceed the budget of 10" {
+		t.Errorf("issue reads %q", got)
+	}
+	if issues := DefaultBudget.Check(LevelUsage{Objects: 256}); len(issues) != 0 {
+		t.Errorf("Check within the default budget = %+v, want none", issues)
+	}
+
+	if got, want := b.Summary(u), "Obj 12/10! Kin 50 Trig 4/4 Rules 3/2!"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,287 @@
+package world
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ChunkSize is the width and height of a render chunk in tiles.
+const ChunkSize = 16
+
+// Chunk eviction: chunks not drawn for chunkMaxAge frames are dropped, checked
+// every chunkSweepInterval frames.
+const (
+	chunkMaxAge        = 600
+	chunkSweepInterval = 60
+)
+
+// ChunkCache draws tile layers in ChunkSize x ChunkSize tile chunks. Each
+// chunk is rendered to an offscreen image once and drawn with a single
+// DrawImage call until its tiles change.
+//
+// Like the editor's minimap, every visible chunk is compared with a copy of
+// its tiles each frame, so edits through SetTile or Data are picked up
+// without any explicit invalidation.
+//
+// Animated tiles (see Tileset.SetAnimations) are left out of the chunk
+// images and drawn on top of them each frame, showing the frame for the
+// cache's time.
+type ChunkCache struct {
+	tileset      *Tileset
+	tileW, tileH int
+
+	layers map[*TileLayer]map[chunkKey]*chunk
+	time   time.Duration // Animation time, see SetTime
+
+	frame     int
+	drawCalls int // DrawImage calls onto the screen since BeginFrame
+}
+
+// chunkKey is a chunk's position in chunks.
+type chunkKey struct {
+	cx, cy int
+}
+
+// chunk is one cached block of a layer.
+type chunk struct {
+	img      *ebiten.Image // Nil when every tile is empty
+	tiles    []int         // The layer's tiles when img was rendered, row-major
+	animated []int         // Indexes in tiles of the animated tiles, not in img
+	lastUsed int           // Frame the chunk was last drawn in
+}
+
+// NewChunkCache creates an empty cache drawing tiles of the given size from
+// tileset.
+func NewChunkCache(tileset *Tileset, tileW, tileH int) *ChunkCache {
+	return &ChunkCache{
+		tileset: tileset,
+		tileW:   tileW,
+		tileH:   tileH,
+		layers:  make(map[*TileLayer]map[chunkKey]*chunk),
+	}
+}
+
+// BeginFrame starts a frame: it resets the draw call count and drops chunks
+// that haven't been drawn for a while.
+func (c *ChunkCache) BeginFrame() {
+	c.frame++
+	c.drawCalls = 0
+	if c.frame%chunkSweepInterval != 0 {
+		return
+	}
+	for layer, chunks := range c.layers {
+		for key, ch := range chunks {
+			if c.frame-ch.lastUsed > chunkMaxAge {
+				ch.release()
+				delete(chunks, key)
+			}
+		}
+		if len(chunks) == 0 {
+			delete(c.layers, layer)
+		}
+	}
+}
+
+// DrawCalls returns the number of DrawImage calls made onto the screen since
+// BeginFrame.
+func (c *ChunkCache) DrawCalls() int {
+	return c.drawCalls
+}
+
+// SetTime sets the time animated tiles are drawn at; 0 shows their first
+// frame.
+func (c *ChunkCache) SetTime(t time.Duration) {
+	c.time = t
+}
+
+// Time returns the time animated tiles are drawn at.
+func (c *ChunkCache) Time() time.Duration {
+	return c.time
+}
+
+// Clear drops every cached chunk.
+func (c *ChunkCache) Clear() {
+	for _, chunks := range c.layers {
+		for _, ch := range chunks {
+			ch.release()
+		}
+	}
+	c.layers = make(map[*TileLayer]map[chunkKey]*chunk)
+}
+
+// DrawLayer draws the chunks of layer that are visible in a viewW x viewH
+// pixel view at world position camX, camY, scaled by zoom.
+func (c *ChunkCache) DrawLayer(screen *ebiten.Image, layer *TileLayer, camX, camY, zoom float64, viewW, viewH int) {
+	c.DrawLayerAlpha(screen, layer, camX, camY, zoom, viewW, viewH, 1)
+}
+
+// DrawLayerAlpha is DrawLayer with the layer drawn at the given opacity,
+// from 0 to 1. Both apply the layer's own opacity and tint.
+func (c *ChunkCache) DrawLayerAlpha(screen *ebiten.Image, layer *TileLayer, camX, camY, zoom float64, viewW, viewH int, alpha float32) {
+	if c.tileset == nil || c.tileW <= 0 || c.tileH <= 0 || zoom <= 0 {
+		return
+	}
+	var scale ebiten.ColorScale
+	if layer.tint.A > 0 {
+		scale.ScaleWithColor(layer.tint)
+	}
+	scale.ScaleAlpha(alpha * float32(layer.opacity))
+
+	chunkW := float64(ChunkSize * c.tileW)
+	chunkH := float64(ChunkSize * c.tileH)
+	cx1 := max(int(camX/chunkW), 0)
+	cy1 := max(int(camY/chunkH), 0)
+	cx2 := min(int((camX+float64(viewW)/zoom)/chunkW)+1, (layer.width+ChunkSize-1)/ChunkSize)
+	cy2 := min(int((camY+float64(viewH)/zoom)/chunkH)+1, (layer.height+ChunkSize-1)/ChunkSize)
+	if layer.Infinite() {
+		// Sparse layers have chunks anywhere; only those with tiles are drawn
+		cx1, cy1 = int(math.Floor(camX/chunkW)), int(math.Floor(camY/chunkH))
+		cx2 = int(math.Floor((camX+float64(viewW)/zoom)/chunkW)) + 1
+		cy2 = int(math.Floor((camY+float64(viewH)/zoom)/chunkH)) + 1
+	}
+
+	chunks := c.layers[layer]
+	if chunks == nil {
+		chunks = make(map[chunkKey]*chunk)
+		c.layers[layer] = chunks
+	}
+
+	for cy := cy1; cy < cy2; cy++ {
+		for cx := cx1; cx < cx2; cx++ {
+			if !layer.hasChunk(cx, cy) {
+				continue
+			}
+			key := chunkKey{cx, cy}
+			ch := chunks[key]
+			if ch == nil {
+				ch = &chunk{}
+				chunks[key] = ch
+			}
+			ch.lastUsed = c.frame
+			if !ch.matches(layer, cx, cy) {
+				c.render(ch, layer, cx, cy)
+			}
+			originX, originY := float64(cx)*chunkW-camX, float64(cy)*chunkH-camY
+			if ch.img != nil {
+				op := &ebiten.DrawImageOptions{}
+				op.GeoM.Scale(zoom, zoom)
+				op.GeoM.Translate(originX*zoom, originY*zoom)
+				op.Filter = ebiten.FilterNearest
+				op.ColorScale = scale
+				screen.DrawImage(ch.img, op)
+				c.drawCalls++
+			}
+			c.drawAnimated(screen, ch, layer, cx, originX, originY, zoom, scale)
+		}
+	}
+}
+
+// drawAnimated draws the chunk's animated tiles at their current frame.
+// originX, originY is the chunk's top left relative to the camera.
+func (c *ChunkCache) drawAnimated(screen *ebiten.Image, ch *chunk, layer *TileLayer, cx int, originX, originY, zoom float64, scale ebiten.ColorScale) {
+	if len(ch.animated) == 0 {
+		return
+	}
+	tx1, _, tx2, _ := chunkBounds(layer, cx, 0)
+	w := tx2 - tx1
+	for _, i := range ch.animated {
+		tile := c.tileset.Tile(c.tileset.FrameAt(ch.tiles[i]-1, c.time))
+		if tile == nil {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64((i%w)*c.tileW), float64((i/w)*c.tileH))
+		op.GeoM.Translate(originX, originY)
+		op.GeoM.Scale(zoom, zoom)
+		op.Filter = ebiten.FilterNearest
+		op.ColorScale = scale
+		screen.DrawImage(tile, op)
+		c.drawCalls++
+	}
+}
+
+// chunkBounds returns the tile range of chunk cx, cy clipped to the layer;
+// the chunks of infinite layers are never clipped.
+func chunkBounds(layer *TileLayer, cx, cy int) (tx1, ty1, tx2, ty2 int) {
+	tx1, ty1 = cx*ChunkSize, cy*ChunkSize
+	if layer.Infinite() {
+		return tx1, ty1, tx1 + ChunkSize, ty1 + ChunkSize
+	}
+	tx2 = min(tx1+ChunkSize, layer.width)
+	ty2 = min(ty1+ChunkSize, layer.height)
+	return tx1, ty1, tx2, ty2
+}
+
+// matches returns whether the chunk was rendered from the layer's current
+// tiles.
+func (ch *chunk) matches(layer *TileLayer, cx, cy int) bool {
+	tx1, ty1, tx2, ty2 := chunkBounds(layer, cx, cy)
+	if len(ch.tiles) != (tx2-tx1)*(ty2-ty1) {
+		return false
+	}
+	i := 0
+	for ty := ty1; ty < ty2; ty++ {
+		for _, id := range layer.row(ty, tx1, tx2) {
+			if ch.tiles[i] != id {
+				return false
+			}
+			i++
+		}
+	}
+	return true
+}
+
+// render redraws the chunk from the layer's tiles.
+func (c *ChunkCache) render(ch *chunk, layer *TileLayer, cx, cy int) {
+	tx1, ty1, tx2, ty2 := chunkBounds(layer, cx, cy)
+	w, h := tx2-tx1, ty2-ty1
+
+	ch.tiles = ch.tiles[:0]
+	ch.animated = ch.animated[:0]
+	empty := true
+	for ty := ty1; ty < ty2; ty++ {
+		for _, id := range layer.row(ty, tx1, tx2) {
+			if id != 0 && c.tileset.Animated(id-1) {
+				ch.animated = append(ch.animated, len(ch.tiles))
+			} else if id != 0 {
+				empty = false
+			}
+			ch.tiles = append(ch.tiles, id)
+		}
+	}
+	if empty {
+		ch.release()
+		return
+	}
+
+	if ch.img != nil && ch.img.Bounds().Dx() == w*c.tileW && ch.img.Bounds().Dy() == h*c.tileH {
+		ch.img.Clear()
+	} else {
+		ch.release()
+		ch.img = ebiten.NewImage(w*c.tileW, h*c.tileH)
+	}
+
+	for i, id := range ch.tiles {
+		if id == 0 || c.tileset.Animated(id-1) {
+			continue
+		}
+		// Tiled uses 1-based IDs, convert to 0-based
+		tile := c.tileset.Tile(id - 1)
+		if tile == nil {
+			continue
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64((i%w)*c.tileW), float64((i/w)*c.tileH))
+		ch.img.DrawImage(tile, op)
+	}
+}
+
+// release frees the chunk's image.
+func (ch *chunk) release() {
+	if ch.img != nil {
+		ch.img.Deallocate()
+		ch.img = nil
+	}
+}
@@ -0,0 +1,66 @@
+//go:build display
+
+package world
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestChunkCacheDraw(t *testing.T) {
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 32, 16)), 16, 16)
+	layer := &TileLayer{name: "Tiles", width: 40, height: 20, data: make([]int, 40*20)}
+	layer.SetTile(1, 1, 1)   // Chunk 0, 0
+	layer.SetTile(35, 18, 2) // Chunk 2, 1, clipped to 8x4 tiles
+
+	cache := NewChunkCache(tileset, 16, 16)
+	screen := ebiten.NewImage(640, 320)
+	draw := func() int {
+		cache.BeginFrame()
+		cache.DrawLayer(screen, layer, 0, 0, 1, 640, 320)
+		return cache.DrawCalls()
+	}
+
+	if got := draw(); got != 2 {
+		t.Fatalf("drew %d chunks, want 2 (empty chunks skipped)", got)
+	}
+	edge := cache.layers[layer][chunkKey{2, 1}]
+	if w, h := edge.img.Bounds().Dx(), edge.img.Bounds().Dy(); w != 8*16 || h != 4*16 {
+		t.Errorf("edge chunk image is %dx%d, want 128x64", w, h)
+	}
+
+	// Edits are picked up on the next draw
+	layer.SetTile(20, 0, 1)
+	if got := draw(); got != 3 {
+		t.Errorf("drew %d chunks after painting a new one, want 3", got)
+	}
+	layer.Data()[1*40+1] = 0
+	if got := draw(); got != 2 {
+		t.Errorf("drew %d chunks after erasing one, want 2", got)
+	}
+
+	// Scrolled so only the right column of chunks is visible
+	cache.BeginFrame()
+	cache.DrawLayer(screen, layer, 520, 0, 1, 120, 320)
+	if got := cache.DrawCalls(); got != 1 {
+		t.Errorf("drew %d chunks with the right column visible, want 1", got)
+	}
+}
+
+func TestChunkCacheEviction(t *testing.T) {
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 16, 16)), 16, 16)
+	layer := &TileLayer{name: "Tiles", width: 16, height: 16, data: make([]int, 16*16)}
+	layer.SetTile(0, 0, 1)
+
+	cache := NewChunkCache(tileset, 16, 16)
+	cache.BeginFrame()
+	cache.DrawLayer(ebiten.NewImage(256, 256), layer, 0, 0, 1, 256, 256)
+	for i := 0; i <= chunkMaxAge+chunkSweepInterval; i++ {
+		cache.BeginFrame()
+	}
+	if len(cache.layers) != 0 {
+		t.Errorf("%d layers still cached after %d frames unused", len(cache.layers), chunkMaxAge+chunkSweepInterval)
+	}
+}
@@ -1,15 +1,11 @@
 package world
 
-// TileCoord represents a tile coordinate.
-type TileCoord struct {
-	X, Y int
-}
-
 // SolidGrid represents a grid of solid tiles for collision detection.
 type SolidGrid struct {
-	width  int    // Width in tiles
-	height int    // Height in tiles
-	data   []bool // true = solid, false = empty
+	width  int                 // Width in tiles
+	height int                 // Height in tiles
+	data   []bool              // true = solid, false = empty
+	chunks map[chunkKey][]bool // Solid tiles of sparse grids, instead of data
 }
 
 // NewSolidGrid creates a new empty grid with the given dimensions.
@@ -55,6 +51,9 @@ func (g *SolidGrid) Height() int {
 // IsSolid returns true if the tile at (tx, ty) is solid.
 // Returns false for out-of-bounds coordinates.
 func (g *SolidGrid) IsSolid(tx, ty int) bool {
+	if g.chunks != nil {
+		return g.solidChunkTile(tx, ty)
+	}
 	if tx < 0 || tx >= g.width || ty < 0 || ty >= g.height {
 		return false
 	}
@@ -64,6 +63,10 @@ func (g *SolidGrid) IsSolid(tx, ty int) bool {
 // SetSolid sets the solid state at the given tile coordinates.
 // Does nothing if coordinates are out of bounds.
 func (g *SolidGrid) SetSolid(tx, ty int, solid bool) {
+	if g.chunks != nil {
+		g.setSolidChunkTile(tx, ty, solid)
+		return
+	}
 	if tx < 0 || tx >= g.width || ty < 0 || ty >= g.height {
 		return
 	}
@@ -72,9 +75,9 @@ func (g *SolidGrid) SetSolid(tx, ty int, solid bool) {
 
 // CollisionMap provides collision detection for a loaded map.
 type CollisionMap struct {
-	grid    *SolidGrid
-	tileW   int
-	tileH   int
+	grid  *SolidGrid
+	tileW int
+	tileH int
 }
 
 // NewCollisionMap creates a collision map from a solid grid and tile dimensions.
@@ -90,9 +93,18 @@ func NewCollisionMap(grid *SolidGrid, tileW, tileH int) *CollisionMap {
 // If no collision layer exists, an empty collision map is returned.
 func NewCollisionMapFromMap(m *Map, collisionLayerName string) *CollisionMap {
 	grid := NewSolidGrid(m.Width(), m.Height())
-	
+
 	layer := m.Layer(collisionLayerName)
-	if layer != nil {
+	if layer != nil && layer.Infinite() {
+		grid = NewSparseSolidGrid()
+		for _, chunk := range layer.Chunks() {
+			for i, id := range chunk.Data {
+				if id != 0 {
+					grid.SetSolid(chunk.X+i%ChunkSize, chunk.Y+i/ChunkSize, true)
+				}
+			}
+		}
+	} else if layer != nil {
 		for ty := 0; ty < m.Height(); ty++ {
 			for tx := 0; tx < m.Width(); tx++ {
 				if layer.TileAt(tx, ty) != 0 {
@@ -133,8 +145,8 @@ func (c *CollisionMap) IsSolidAtTile(tx, ty int) bool {
 // IsSolidAtWorld returns true if the world position is in a solid tile.
 // Returns false for out-of-bounds coordinates.
 func (c *CollisionMap) IsSolidAtWorld(x, y float64) bool {
-	tx := int(x) / c.tileW
-	ty := int(y) / c.tileH
+	tx := TileIndex(x, c.tileW)
+	ty := TileIndex(y, c.tileH)
 	return c.grid.IsSolid(tx, ty)
 }
 
@@ -142,12 +154,12 @@ func (c *CollisionMap) IsSolidAtWorld(x, y float64) bool {
 // The AABB is defined by top-left corner (x, y) and dimensions (w, h).
 func (c *CollisionMap) OverlapsSolid(x, y, w, h float64) bool {
 	// Get the tile range that the AABB overlaps
-	tx1 := int(x) / c.tileW
-	ty1 := int(y) / c.tileH
+	tx1 := TileIndex(x, c.tileW)
+	ty1 := TileIndex(y, c.tileH)
 	// Use floor division to avoid including tiles at exact boundaries
 	// Subtract a small epsilon to handle exact boundary cases
-	tx2 := int(x+w-0.001) / c.tileW
-	ty2 := int(y+h-0.001) / c.tileH
+	tx2 := TileIndex(x+w-0.001, c.tileW)
+	ty2 := TileIndex(y+h-0.001, c.tileH)
 
 	// Check each tile in the range
 	for ty := ty1; ty <= ty2; ty++ {
@@ -164,11 +176,11 @@ func (c *CollisionMap) OverlapsSolid(x, y, w, h float64) bool {
 // GetOverlappingTiles returns all tiles that overlap the given AABB.
 // The AABB is defined by top-left corner (x, y) and dimensions (w, h).
 func (c *CollisionMap) GetOverlappingTiles(x, y, w, h float64) []TileCoord {
-	tx1 := int(x) / c.tileW
-	ty1 := int(y) / c.tileH
+	tx1 := TileIndex(x, c.tileW)
+	ty1 := TileIndex(y, c.tileH)
 	// Use floor division to avoid including tiles at exact boundaries
-	tx2 := int(x+w-0.001) / c.tileW
-	ty2 := int(y+h-0.001) / c.tileH
+	tx2 := TileIndex(x+w-0.001, c.tileW)
+	ty2 := TileIndex(y+h-0.001, c.tileH)
 
 	var tiles []TileCoord
 	for ty := ty1; ty <= ty2; ty++ {
@@ -182,7 +194,7 @@ func (c *CollisionMap) GetOverlappingTiles(x, y, w, h float64) []TileCoord {
 
 // WorldToTile converts world coordinates to tile coordinates.
 func WorldToTile(x, y float64, tileW, tileH int) (tx, ty int) {
-	return int(x) / tileW, int(y) / tileH
+	return TileIndex(x, tileW), TileIndex(y, tileH)
 }
 
 // TileToWorld converts tile coordinates to world coordinates (top-left corner).
@@ -0,0 +1,33 @@
+package world
+
+import "math"
+
+// TileCoord is a position in tiles.
+type TileCoord struct {
+	X, Y int
+}
+
+// WorldCoord is a position in world pixels.
+type WorldCoord struct {
+	X, Y float64
+}
+
+// Tile returns the tile containing the point. Points left of or above the
+// map land in negative tiles rather than tile 0.
+func (w WorldCoord) Tile(tileW, tileH int) TileCoord {
+	return TileCoord{TileIndex(w.X, tileW), TileIndex(w.Y, tileH)}
+}
+
+// World returns the tile's top-left corner in world pixels.
+func (t TileCoord) World(tileW, tileH int) WorldCoord {
+	x, y := TileToWorld(t.X, t.Y, tileW, tileH)
+	return WorldCoord{x, y}
+}
+
+// TileIndex converts a world pixel coordinate to the index of the tile
+// containing it, along an axis with tiles size pixels long. Use it instead
+// of int(v)/size, which truncates toward zero and puts -0.5 in tile 0
+// instead of -1.
+func TileIndex(v float64, size int) int {
+	return int(math.Floor(v / float64(size)))
+}
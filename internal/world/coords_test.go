@@ -0,0 +1,50 @@
+//go:build display
+
+package world
+
+import "testing"
+
+func TestTileIndex(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want int
+	}{
+		{0, 0},
+		{15.9, 0},
+		{16, 1},
+		{-0.5, -1},
+		{-16, -1},
+		{-16.5, -2},
+	}
+	for _, tt := range tests {
+		if got := TileIndex(tt.v, 16); got != tt.want {
+			t.Errorf("TileIndex(%v, 16) = %d, want %d", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestCoordConversion(t *testing.T) {
+	if got := (WorldCoord{-1, 40}).Tile(16, 8); got != (TileCoord{-1, 5}) {
+		t.Errorf("WorldCoord{-1, 40}.Tile = %v, want {-1 5}", got)
+	}
+	if got := (TileCoord{-1, 5}).World(16, 8); got != (WorldCoord{-16, 40}) {
+		t.Errorf("TileCoord{-1, 5}.World = %v, want {-16 40}", got)
+	}
+}
+
+func TestCollisionLeftOfMap(t *testing.T) {
+	grid := NewSolidGrid(4, 4)
+	grid.SetSolid(0, 0, true)
+	cm := NewCollisionMap(grid, 16, 16)
+
+	// Just left of the map is outside, not in the solid tile 0
+	if cm.IsSolidAtWorld(-0.5, 4) {
+		t.Error("IsSolidAtWorld(-0.5, 4) = true, want false outside the map")
+	}
+	if cm.OverlapsSolid(-10, 4, 8, 8) {
+		t.Error("OverlapsSolid for a box entirely left of the map = true, want false")
+	}
+	if !cm.OverlapsSolid(-4, 4, 8, 8) {
+		t.Error("OverlapsSolid for a box straddling the left edge = false, want true")
+	}
+}
@@ -0,0 +1,42 @@
+package world
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Exit object property names. An exit moves the player to another room: a
+// spawn in another level file, or in the same level.
+const (
+	// PropExitLevel is the level file to go to, relative to the current
+	// level's directory. Empty stays in the current level.
+	PropExitLevel = "level"
+	// PropExitSpawn is the id of the spawn to arrive at. Empty uses the
+	// level's first spawn.
+	PropExitSpawn = "spawn"
+)
+
+// ExitTarget returns where an exit object leads: its level file and spawn id.
+func ExitTarget(obj ObjectData) (level, spawn string) {
+	return obj.GetPropString(PropExitLevel, ""), obj.GetPropString(PropExitSpawn, "")
+}
+
+// ResolveLevelPath returns the path of the level an exit in the level at
+// from leads to. level is relative to from's directory; an empty level is
+// from itself.
+func ResolveLevelPath(from, level string) string {
+	if level == "" {
+		return from
+	}
+	if filepath.IsAbs(level) {
+		return level
+	}
+	return filepath.Join(filepath.Dir(from), level)
+}
+
+// OverviewPath returns the overview image of the level at levelPath that
+// the editor exports next to it, e.g. level_01_overview.png. The world map
+// shows it as the level's thumbnail.
+func OverviewPath(levelPath string) string {
+	return strings.TrimSuffix(levelPath, filepath.Ext(levelPath)) + "_overview.png"
+}
@@ -0,0 +1,55 @@
+//go:build display
+
+package world
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveLevelPath(t *testing.T) {
+	from := filepath.Join("assets", "levels", "castle", "hall.json")
+	tests := []struct {
+		level, want string
+	}{
+		{"", from},
+		{"tower.json", filepath.Join("assets", "levels", "castle", "tower.json")},
+		{"../level_01.json", filepath.Join("assets", "levels", "level_01.json")},
+	}
+	for _, tt := range tests {
+		if got := ResolveLevelPath(from, tt.level); got != tt.want {
+			t.Errorf("ResolveLevelPath(%q) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestOverviewPath(t *testing.T) {
+	level := filepath.Join("assets", "levels", "level_01.json")
+	if got, want := OverviewPath(level), filepath.Join("assets", "levels", "level_01_overview.png"); got != want {
+		t.Errorf("OverviewPath(%q) = %q, want %q", level, got, want)
+	}
+}
+
+func TestFindSpawnByID(t *testing.T) {
+	objects := []ObjectData{
+		{Type: ObjectTypeSpawn, X: 1},
+		{Type: ObjectTypeSpawn, X: 2, Props: map[string]any{"id": "from_tower"}},
+		{Type: ObjectTypeSpawn, X: 3, Name: "from_hall"},
+	}
+	tests := []struct {
+		id    string
+		wantX float64
+		found bool
+	}{
+		{"", 1, true},
+		{"from_tower", 2, true},
+		{"from_hall", 3, true}, // By name
+		{"from_cellar", 0, false},
+	}
+	for _, tt := range tests {
+		x, _, found := FindSpawnByID(objects, tt.id)
+		if x != tt.wantX || found != tt.found {
+			t.Errorf("FindSpawnByID(%q) = %v, %v, want %v, %v", tt.id, x, found, tt.wantX, tt.found)
+		}
+	}
+}
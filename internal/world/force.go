@@ -0,0 +1,17 @@
+package world
+
+// Force zone object property names. A force zone changes the forces on the
+// player inside it, e.g. a low-gravity room or an updraft.
+const (
+	// PropForceGravityScale multiplies gravity inside the zone: 1 leaves it
+	// unchanged, 0 is weightless, and a negative value pulls up.
+	PropForceGravityScale = "gravityScale"
+	// PropForceWindX and PropForceWindY are a constant acceleration inside
+	// the zone in pixels/second², negative Y pushing up. The editor edits
+	// them as one "wind" vector.
+	PropForceWindX = "windX"
+	PropForceWindY = "windY"
+)
+
+// DefaultForceGravityScale leaves gravity unchanged.
+const DefaultForceGravityScale = 1.0
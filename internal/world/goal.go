@@ -0,0 +1,56 @@
+package world
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Goal object property names for completion requirements.
+const (
+	// PropRequireCheckpoints requires every checkpoint to be reached first.
+	PropRequireCheckpoints = "requireCheckpoints"
+	// PropRequiredCollectibles is the number of collectibles needed to finish.
+	PropRequiredCollectibles = "collectibles"
+	// PropParTime is the time limit in seconds (0 = no limit).
+	PropParTime = "parTime"
+)
+
+// GoalRequirements are the conditions a goal checks before completing the level.
+// The zero value only requires touching the goal.
+type GoalRequirements struct {
+	AllCheckpoints bool    // Every checkpoint must have been reached
+	Collectibles   int     // Minimum number of collectibles collected
+	ParTime        float64 // Time limit in seconds; 0 means no limit
+}
+
+// NewGoalRequirements reads the completion requirements from a goal object.
+func NewGoalRequirements(obj ObjectData) GoalRequirements {
+	return GoalRequirements{
+		AllCheckpoints: obj.GetPropBool(PropRequireCheckpoints, false),
+		Collectibles:   max(0, obj.GetPropInt(PropRequiredCollectibles, 0)),
+		ParTime:        max(0, obj.GetPropFloat(PropParTime, 0)),
+	}
+}
+
+// IsZero returns true if the goal has no requirements beyond being touched.
+func (r GoalRequirements) IsZero() bool {
+	return r == GoalRequirements{}
+}
+
+// String returns a short summary, e.g. "all checkpoints, 5 collectibles, par 60s".
+func (r GoalRequirements) String() string {
+	var parts []string
+	if r.AllCheckpoints {
+		parts = append(parts, "all checkpoints")
+	}
+	if r.Collectibles > 0 {
+		parts = append(parts, fmt.Sprintf("%d collectibles", r.Collectibles))
+	}
+	if r.ParTime > 0 {
+		parts = append(parts, fmt.Sprintf("par %gs", r.ParTime))
+	}
+	if len(parts) == 0 {
+		return "touch goal"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,196 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Infinite maps keep their tiles sparsely, in ChunkSize x ChunkSize chunks
+// like the render chunks, so tiles can be anywhere, even left of or above
+// the origin, and painting past the last chunk adds one. Their Width and
+// Height are the extent of the chunks right and below the origin, which is
+// what the camera and the level checks see.
+
+// TileChunk is a ChunkSize x ChunkSize block of an infinite layer's tiles.
+type TileChunk struct {
+	X, Y int   // Top left, in tiles; multiples of ChunkSize
+	Data []int // Row-major global tile IDs
+}
+
+// chunkOf returns the chunk holding tile tx, ty and the tile's index in it.
+func chunkOf(tx, ty int) (chunkKey, int) {
+	key := chunkKey{floorDiv(tx, ChunkSize), floorDiv(ty, ChunkSize)}
+	return key, (ty-key.cy*ChunkSize)*ChunkSize + tx - key.cx*ChunkSize
+}
+
+// floorDiv divides rounding toward negative infinity, so tile -1 is in
+// chunk -1 rather than 0.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// newInfiniteLayer creates an empty infinite layer.
+func newInfiniteLayer(name string) *TileLayer {
+	return &TileLayer{name: name, chunks: make(map[chunkKey][]int), opacity: 1}
+}
+
+// Infinite returns whether the layer is from an infinite map, keeping its
+// tiles in chunks.
+func (l *TileLayer) Infinite() bool {
+	return l.chunks != nil
+}
+
+// Chunks returns the chunks of an infinite layer that have tiles, top to
+// bottom and left to right, or nil for other layers.
+func (l *TileLayer) Chunks() []TileChunk {
+	var chunks []TileChunk
+	for key, data := range l.chunks {
+		for _, id := range data {
+			if id != 0 {
+				chunks = append(chunks, TileChunk{X: key.cx * ChunkSize, Y: key.cy * ChunkSize, Data: data})
+				break
+			}
+		}
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		if chunks[i].Y != chunks[j].Y {
+			return chunks[i].Y < chunks[j].Y
+		}
+		return chunks[i].X < chunks[j].X
+	})
+	return chunks
+}
+
+// setChunkTile sets a tile of an infinite layer, adding its chunk when
+// needed and growing the layer's extent to include it.
+func (l *TileLayer) setChunkTile(tx, ty, id int) {
+	key, i := chunkOf(tx, ty)
+	data := l.chunks[key]
+	if data == nil {
+		if id == 0 {
+			return
+		}
+		data = make([]int, ChunkSize*ChunkSize)
+		l.chunks[key] = data
+		l.width = max(l.width, (key.cx+1)*ChunkSize)
+		l.height = max(l.height, (key.cy+1)*ChunkSize)
+	}
+	data[i] = id
+}
+
+// denseData returns an infinite layer's tiles from 0, 0 to its extent.
+func (l *TileLayer) denseData() []int {
+	data := make([]int, l.width*l.height)
+	for key, chunk := range l.chunks {
+		for i, id := range chunk {
+			tx, ty := key.cx*ChunkSize+i%ChunkSize, key.cy*ChunkSize+i/ChunkSize
+			if id != 0 && tx >= 0 && ty >= 0 && tx < l.width && ty < l.height {
+				data[ty*l.width+tx] = id
+			}
+		}
+	}
+	return data
+}
+
+// hasChunk returns whether the layer may have tiles in render chunk cx, cy:
+// always for other layers than infinite ones.
+func (l *TileLayer) hasChunk(cx, cy int) bool {
+	return l.chunks == nil || l.chunks[chunkKey{cx, cy}] != nil
+}
+
+// row returns the layer's tiles tx1 to tx2 of row ty, which must be in one
+// render chunk and in the layer.
+func (l *TileLayer) row(ty, tx1, tx2 int) []int {
+	if l.chunks == nil {
+		return l.data[ty*l.width+tx1 : ty*l.width+tx2]
+	}
+	key, i := chunkOf(tx1, ty)
+	data := l.chunks[key]
+	if data == nil {
+		return make([]int, tx2-tx1)
+	}
+	return data[i : i+tx2-tx1]
+}
+
+// tiledChunk is a chunk of an infinite Tiled map's tile layer.
+type tiledChunk struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Data   json.RawMessage `json:"data"` // Encoded like the layer's data
+}
+
+// parseInfiniteLayer builds an infinite layer from Tiled's chunks, which
+// may be of any size.
+func parseInfiniteLayer(tl tiledLayer) (*TileLayer, error) {
+	layer := newInfiniteLayer(tl.Name)
+	for _, c := range tl.Chunks {
+		if c.Width <= 0 {
+			continue
+		}
+		data, err := DecodeTileData(c.Data, tl.Encoding, tl.Compression)
+		if err != nil {
+			return nil, fmt.Errorf("chunk at %d, %d: %w", c.X, c.Y, err)
+		}
+		for i, id := range data {
+			layer.setChunkTile(c.X+i%c.Width, c.Y+i/c.Width, id)
+		}
+	}
+	return layer, nil
+}
+
+// Infinite returns whether the map is infinite, see TileLayer.Infinite.
+func (m *MapData) Infinite() bool {
+	return m.infinite
+}
+
+// Infinite returns whether the map is infinite. Its tiles can be anywhere,
+// left of and above the origin too.
+func (m *Map) Infinite() bool {
+	return m.infinite
+}
+
+// extent returns the width and height of an infinite map: its own, grown
+// to fit every layer.
+func extent(width, height int, layers []*TileLayer) (int, int) {
+	for _, l := range layers {
+		width, height = max(width, l.width), max(height, l.height)
+	}
+	return width, height
+}
+
+// NewSparseSolidGrid creates an empty grid keeping solid tiles in chunks,
+// for infinite maps: tiles can be anywhere, and Width and Height are the
+// extent of the chunks right and below the origin.
+func NewSparseSolidGrid() *SolidGrid {
+	return &SolidGrid{chunks: make(map[chunkKey][]bool)}
+}
+
+// solidChunkTile returns a sparse grid's solid state at tx, ty.
+func (g *SolidGrid) solidChunkTile(tx, ty int) bool {
+	key, i := chunkOf(tx, ty)
+	chunk := g.chunks[key]
+	return chunk != nil && chunk[i]
+}
+
+// setSolidChunkTile sets a sparse grid's solid state at tx, ty.
+func (g *SolidGrid) setSolidChunkTile(tx, ty int, solid bool) {
+	key, i := chunkOf(tx, ty)
+	chunk := g.chunks[key]
+	if chunk == nil {
+		if !solid {
+			return
+		}
+		chunk = make([]bool, ChunkSize*ChunkSize)
+		g.chunks[key] = chunk
+		g.width = max(g.width, (key.cx+1)*ChunkSize)
+		g.height = max(g.height, (key.cy+1)*ChunkSize)
+	}
+	chunk[i] = solid
+}
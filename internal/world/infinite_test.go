@@ -0,0 +1,115 @@
+//go:build display
+
+package world
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// infiniteLevel has a 4x2 chunk at the origin, as Tiled may write them, and
+// a collision tile left of the origin.
+const infiniteLevel = `{"width": 10, "height": 10, "tilewidth": 16, "tileheight": 16, "infinite": true, "layers": [
+  {"name": "Tiles", "type": "tilelayer", "chunks": [
+    {"x": 0, "y": 0, "width": 4, "height": 2, "data": [1, 0, 0, 0, 0, 0, 0, 2]},
+    {"x": 32, "y": 16, "width": 1, "height": 1, "data": [3]}
+  ]},
+  {"name": "Collision", "type": "tilelayer", "chunks": [
+    {"x": -16, "y": 0, "width": 16, "height": 1, "data": [0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1]}
+  ]}
+]}`
+
+func TestParseInfiniteMap(t *testing.T) {
+	md, err := ParseTiledJSON([]byte(infiniteLevel))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON failed: %v", err)
+	}
+	if !md.Infinite() {
+		t.Fatal("map isn't infinite")
+	}
+
+	tiles := md.Layer("Tiles")
+	for _, tt := range []struct{ tx, ty, want int }{{0, 0, 1}, {3, 1, 2}, {32, 16, 3}, {5, 5, 0}, {-1, 0, 0}} {
+		if got := tiles.TileAt(tt.tx, tt.ty); got != tt.want {
+			t.Errorf("TileAt(%d, %d) = %d, want %d", tt.tx, tt.ty, got, tt.want)
+		}
+	}
+	if got := md.Layer("Collision").TileAt(-1, 0); got != 1 {
+		t.Errorf("collision TileAt(-1, 0) = %d, want 1", got)
+	}
+
+	// The extent reaches the end of the chunk at 32, 16
+	if md.Width() != 48 || md.Height() != 32 {
+		t.Errorf("map is %dx%d, want 48x32", md.Width(), md.Height())
+	}
+	if got := len(tiles.Chunks()); got != 2 {
+		t.Errorf("Tiles has %d chunks, want 2", got)
+	}
+	if data := tiles.Data(); len(data) != 48*32 || data[1*48+3] != 2 {
+		t.Errorf("Data has %d tiles, want 48x32 with tile 3, 1 set", len(data))
+	}
+}
+
+func TestInfiniteLayerSetTileExtends(t *testing.T) {
+	layer := newInfiniteLayer("Tiles")
+	layer.SetTile(40, 5, 3)
+	if layer.Width() != 48 || layer.Height() != 16 {
+		t.Errorf("layer is %dx%d after painting 40, 5, want 48x16", layer.Width(), layer.Height())
+	}
+	layer.SetTile(-3, -20, 1)
+	layer.SetTile(100, 100, 0) // Erasing outside the chunks adds none
+
+	chunks := layer.Chunks()
+	if len(chunks) != 2 || chunks[0].X != -16 || chunks[0].Y != -32 || chunks[1].X != 32 {
+		t.Fatalf("Chunks = %+v, want the chunks at -16, -32 and 32, 0", chunks)
+	}
+	if got := layer.TileAt(-3, -20); got != 1 {
+		t.Errorf("TileAt(-3, -20) = %d, want 1", got)
+	}
+
+	// A chunk erased empty is left out
+	layer.SetTile(40, 5, 0)
+	if got := len(layer.Chunks()); got != 1 {
+		t.Errorf("%d chunks after erasing one, want 1", got)
+	}
+}
+
+func TestSparseCollisionMap(t *testing.T) {
+	md, err := ParseTiledJSON([]byte(infiniteLevel))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON failed: %v", err)
+	}
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 48, 16)), 16, 16)
+	cm := NewCollisionMapFromMap(NewMap(md, tileset), "Collision")
+
+	if !cm.IsSolidAtTile(-1, 0) || cm.IsSolidAtTile(0, 0) || cm.IsSolidAtTile(-1, 1) {
+		t.Error("solid tiles don't match the collision chunk left of the origin")
+	}
+	if !cm.OverlapsSolid(-8, 4, 4, 4) {
+		t.Error("a box left of the origin doesn't hit the solid tile there")
+	}
+
+	cm.Grid().SetSolid(500, 3, true)
+	if !cm.IsSolidAtTile(500, 3) || cm.Grid().Width() != 512 {
+		t.Errorf("SetSolid far right: solid %v, width %d, want true, 512", cm.IsSolidAtTile(500, 3), cm.Grid().Width())
+	}
+}
+
+func TestChunkCacheDrawsInfiniteLayer(t *testing.T) {
+	tileset := NewTilesetFromImage(image.NewRGBA(image.Rect(0, 0, 32, 16)), 16, 16)
+	layer := newInfiniteLayer("Tiles")
+	layer.SetTile(-1, -1, 1) // Chunk -1, -1
+	layer.SetTile(20, 3, 2)  // Chunk 1, 0
+
+	cache := NewChunkCache(tileset, 16, 16)
+	cache.BeginFrame()
+	cache.DrawLayer(ebiten.NewImage(640, 320), layer, -256, -256, 1, 1024, 1024)
+	if got := cache.DrawCalls(); got != 2 {
+		t.Errorf("drew %d chunks, want 2 (chunks without tiles skipped)", got)
+	}
+	if got := len(cache.layers[layer]); got != 2 {
+		t.Errorf("cached %d chunks, want only the 2 with tiles", got)
+	}
+}
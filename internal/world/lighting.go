@@ -0,0 +1,18 @@
+package world
+
+// PropAmbientDarkness is the map property setting a level's ambient darkness,
+// from 0 (fully lit, lighting disabled) to 1 (pitch black outside lights).
+const PropAmbientDarkness = "ambientDarkness"
+
+// AmbientDarkness returns the level's ambient darkness from its map properties,
+// clamped to the 0-1 range. Returns 0 if the property isn't set.
+func AmbientDarkness(props map[string]any) float64 {
+	darkness, _ := props[PropAmbientDarkness].(float64)
+	if darkness < 0 {
+		return 0
+	}
+	if darkness > 1 {
+		return 1
+	}
+	return darkness
+}
@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
+	"image/color"
+	"strconv"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/torsten/GoP/internal/mathx"
 )
 
 // Tileset represents a tileset image with sliced tiles.
@@ -16,6 +20,7 @@ type Tileset struct {
 	tileHeight int
 	tiles      []*ebiten.Image // Pre-sliced tile images
 	columns    int             // Number of columns in tileset
+	animations map[int]tileAnimation
 }
 
 // NewTilesetFromImage creates a tileset from an image with uniform tile size.
@@ -79,10 +84,13 @@ func (t *Tileset) Columns() int {
 
 // TileLayer represents a single tile layer from the map.
 type TileLayer struct {
-	name   string
-	width  int
-	height int
-	data   []int // Global tile IDs, 0 = empty
+	name    string
+	width   int
+	height  int
+	data    []int              // Global tile IDs, 0 = empty; nil when infinite
+	chunks  map[chunkKey][]int // Tiles of infinite layers, see infinite.go
+	opacity float64            // 0-1, from Tiled's layer opacity
+	tint    color.RGBA         // Multiplies the tiles' colors; zero = none
 }
 
 // Name returns the layer name.
@@ -101,8 +109,16 @@ func (l *TileLayer) Height() int {
 }
 
 // TileAt returns the tile ID at the given tile coordinates.
-// Returns 0 if coordinates are out of bounds.
+// Returns 0 if coordinates are out of bounds, or outside the chunks of an
+// infinite layer.
 func (l *TileLayer) TileAt(tx, ty int) int {
+	if l.chunks != nil {
+		key, i := chunkOf(tx, ty)
+		if chunk := l.chunks[key]; chunk != nil {
+			return chunk[i]
+		}
+		return 0
+	}
 	if tx < 0 || tx >= l.width || ty < 0 || ty >= l.height {
 		return 0
 	}
@@ -110,19 +126,77 @@ func (l *TileLayer) TileAt(tx, ty int) int {
 }
 
 // SetTile sets the tile ID at the given tile coordinates.
-// Does nothing if coordinates are out of bounds.
+// Does nothing if coordinates are out of bounds, except on an infinite
+// layer, which adds a chunk for the tile instead.
 func (l *TileLayer) SetTile(tx, ty, id int) {
+	if l.chunks != nil {
+		l.setChunkTile(tx, ty, id)
+		return
+	}
 	if tx < 0 || tx >= l.width || ty < 0 || ty >= l.height {
 		return
 	}
 	l.data[ty*l.width+tx] = id
 }
 
-// Data returns the raw tile data array.
+// Data returns the raw tile data array. For an infinite layer it's a copy
+// of the tiles from 0, 0 to Width, Height; change those through SetTile.
 func (l *TileLayer) Data() []int {
+	if l.chunks != nil {
+		return l.denseData()
+	}
 	return l.data
 }
 
+// Opacity returns the layer's opacity, from 0 to 1.
+func (l *TileLayer) Opacity() float64 {
+	return l.opacity
+}
+
+// SetOpacity sets the layer's opacity, clamped to 0-1.
+func (l *TileLayer) SetOpacity(opacity float64) {
+	l.opacity = mathx.Clamp01(opacity)
+}
+
+// Tint returns the color the layer's tiles are multiplied with, e.g. dark
+// blue to dim a background, or zero for none.
+func (l *TileLayer) Tint() color.RGBA {
+	return l.tint
+}
+
+// SetTint sets the layer's tint; zero removes it.
+func (l *TileLayer) SetTint(tint color.RGBA) {
+	l.tint = tint
+}
+
+// ParseTintColor parses a Tiled color, #RRGGBB or #AARRGGBB.
+func ParseTintColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 || len(hex) == len(s) {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, expected #RRGGBB or #AARRGGBB", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	if len(hex) == 6 {
+		v |= 0xff << 24
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: uint8(v >> 24)}, nil
+}
+
+// FormatTintColor formats a color the way Tiled writes it: #rrggbb when
+// opaque, #aarrggbb otherwise, and "" for zero.
+func FormatTintColor(c color.RGBA) string {
+	switch c.A {
+	case 0:
+		return ""
+	case 0xff:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.A, c.R, c.G, c.B)
+}
+
 // MapData represents the parsed Tiled JSON data before tileset association.
 type MapData struct {
 	width      int
@@ -131,15 +205,26 @@ type MapData struct {
 	tileHeight int
 	layers     []*TileLayer
 	layerIndex map[string]int
+	properties map[string]any // Custom map properties (e.g., bounds policy)
+	infinite   bool
 }
 
-// Width returns the map width in tiles.
+// Width returns the map width in tiles. An infinite map grows as its
+// layers do.
 func (m *MapData) Width() int {
+	if m.infinite {
+		w, _ := extent(m.width, m.height, m.layers)
+		return w
+	}
 	return m.width
 }
 
 // Height returns the map height in tiles.
 func (m *MapData) Height() int {
+	if m.infinite {
+		_, h := extent(m.width, m.height, m.layers)
+		return h
+	}
 	return m.height
 }
 
@@ -167,22 +252,48 @@ func (m *MapData) Layers() []*TileLayer {
 	return m.layers
 }
 
+// Properties returns the custom map properties.
+// The returned map may be nil if the map has no properties.
+func (m *MapData) Properties() map[string]any {
+	return m.properties
+}
+
+// SetProperty sets a custom map property.
+// A nil value removes the property.
+func (m *MapData) SetProperty(name string, value any) {
+	if value == nil {
+		delete(m.properties, name)
+		return
+	}
+	if m.properties == nil {
+		m.properties = make(map[string]any)
+	}
+	m.properties[name] = value
+}
+
 // tiledMap represents the JSON structure from Tiled.
 type tiledMap struct {
-	Width      int            `json:"width"`
-	Height     int            `json:"height"`
-	TileWidth  int            `json:"tilewidth"`
-	TileHeight int            `json:"tileheight"`
-	Layers     []tiledLayer   `json:"layers"`
-	Tilesets   []tiledTileset `json:"tilesets"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	TileWidth  int             `json:"tilewidth"`
+	TileHeight int             `json:"tileheight"`
+	Infinite   bool            `json:"infinite"`
+	Layers     []tiledLayer    `json:"layers"`
+	Tilesets   []tiledTileset  `json:"tilesets"`
+	Properties []tiledProperty `json:"properties"`
 }
 
 type tiledLayer struct {
-	Name   string `json:"name"`
-	Type   string `json:"type"`
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	Data   []int  `json:"data"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Data        json.RawMessage `json:"data"`     // See DecodeTileData
+	Chunks      []tiledChunk    `json:"chunks"`   // Instead of data in infinite maps
+	Encoding    string          `json:"encoding"` // Of data and chunks
+	Compression string          `json:"compression"`
+	Opacity     *float64        `json:"opacity"` // Missing means opaque, as in Tiled
+	TintColor   string          `json:"tintcolor"`
 }
 
 type tiledTileset struct {
@@ -209,6 +320,15 @@ func ParseTiledJSON(data []byte) (*MapData, error) {
 		tileHeight: tm.TileHeight,
 		layers:     make([]*TileLayer, 0, len(tm.Layers)),
 		layerIndex: make(map[string]int),
+		infinite:   tm.Infinite,
+	}
+
+	// Parse custom map properties
+	if len(tm.Properties) > 0 {
+		mapData.properties = make(map[string]any, len(tm.Properties))
+		for _, prop := range tm.Properties {
+			mapData.properties[prop.Name] = prop.Value
+		}
 	}
 
 	for i, tl := range tm.Layers {
@@ -227,11 +347,31 @@ func ParseTiledJSON(data []byte) (*MapData, error) {
 			layerH = tm.Height
 		}
 
+		data, err := DecodeTileData(tl.Data, tl.Encoding, tl.Compression)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+		}
 		layer := &TileLayer{
-			name:   tl.Name,
-			width:  layerW,
-			height: layerH,
-			data:   tl.Data,
+			name:    tl.Name,
+			width:   layerW,
+			height:  layerH,
+			data:    data,
+			opacity: 1,
+		}
+		if tm.Infinite {
+			if layer, err = parseInfiniteLayer(tl); err != nil {
+				return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+			}
+		}
+		if tl.Opacity != nil {
+			layer.SetOpacity(*tl.Opacity)
+		}
+		if tl.TintColor != "" {
+			tint, err := ParseTintColor(tl.TintColor)
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+			}
+			layer.tint = tint
 		}
 
 		mapData.layerIndex[tl.Name] = len(mapData.layers)
@@ -253,6 +393,8 @@ type Map struct {
 	layers     []*TileLayer
 	tileset    *Tileset
 	layerIndex map[string]int
+	properties map[string]any
+	infinite   bool
 }
 
 // NewMap creates a new map from MapData and a tileset.
@@ -265,16 +407,27 @@ func NewMap(mapData *MapData, tileset *Tileset) *Map {
 		layers:     mapData.layers,
 		tileset:    tileset,
 		layerIndex: mapData.layerIndex,
+		properties: mapData.properties,
+		infinite:   mapData.infinite,
 	}
 }
 
-// Width returns the map width in tiles.
+// Width returns the map width in tiles. An infinite map grows as its
+// layers do.
 func (m *Map) Width() int {
+	if m.infinite {
+		w, _ := extent(m.width, m.height, m.layers)
+		return w
+	}
 	return m.width
 }
 
 // Height returns the map height in tiles.
 func (m *Map) Height() int {
+	if m.infinite {
+		_, h := extent(m.width, m.height, m.layers)
+		return h
+	}
 	return m.height
 }
 
@@ -290,12 +443,12 @@ func (m *Map) TileHeight() int {
 
 // PixelWidth returns the map width in pixels.
 func (m *Map) PixelWidth() int {
-	return m.width * m.tileWidth
+	return m.Width() * m.tileWidth
 }
 
 // PixelHeight returns the map height in pixels.
 func (m *Map) PixelHeight() int {
-	return m.height * m.tileHeight
+	return m.Height() * m.tileHeight
 }
 
 // Layer returns the layer by name, or nil if not found.
@@ -312,6 +465,11 @@ func (m *Map) Layers() []*TileLayer {
 	return m.layers
 }
 
+// Properties returns the custom map properties.
+func (m *Map) Properties() map[string]any {
+	return m.properties
+}
+
 // Tileset returns the map's tileset.
 func (m *Map) Tileset() *Tileset {
 	return m.tileset
@@ -320,10 +478,11 @@ func (m *Map) Tileset() *Tileset {
 // AddLayer adds a new layer to the map.
 func (m *Map) AddLayer(name string, data []int) *TileLayer {
 	layer := &TileLayer{
-		name:   name,
-		width:  m.width,
-		height: m.height,
-		data:   data,
+		name:    name,
+		width:   m.width,
+		height:  m.height,
+		data:    data,
+		opacity: 1,
 	}
 	m.layerIndex[name] = len(m.layers)
 	m.layers = append(m.layers, layer)
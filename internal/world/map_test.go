@@ -0,0 +1,53 @@
+//go:build display
+
+package world
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseTiledJSONLayerOpacityAndTint(t *testing.T) {
+	data := `{"width": 2, "height": 1, "tilewidth": 16, "tileheight": 16, "layers": [
+	  {"name": "Back", "type": "tilelayer", "data": [1, 1], "opacity": 0.5, "tintcolor": "#8040a0"},
+	  {"name": "Tiles", "type": "tilelayer", "data": [0, 1]}
+	]}`
+	md, err := ParseTiledJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON failed: %v", err)
+	}
+
+	back := md.Layer("Back")
+	if back.Opacity() != 0.5 || back.Tint() != (color.RGBA{0x80, 0x40, 0xa0, 0xff}) {
+		t.Errorf("Back: opacity %v, tint %v, want 0.5, #8040a0", back.Opacity(), back.Tint())
+	}
+	tiles := md.Layer("Tiles")
+	if tiles.Opacity() != 1 || tiles.Tint() != (color.RGBA{}) {
+		t.Errorf("Tiles: opacity %v, tint %v, want opaque without tint", tiles.Opacity(), tiles.Tint())
+	}
+
+	if _, err := ParseTiledJSON([]byte(`{"layers": [{"name": "Bad", "type": "tilelayer", "tintcolor": "red"}]}`)); err == nil {
+		t.Error("a layer with tint color \"red\" parsed")
+	}
+}
+
+func TestTintColorRoundTrip(t *testing.T) {
+	for _, s := range []string{"#8040a0", "#80102030", ""} {
+		c, err := ParseTintColor(s)
+		if s == "" {
+			if err == nil {
+				t.Error("ParseTintColor(\"\") succeeded")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseTintColor(%q) failed: %v", s, err)
+		}
+		if got := FormatTintColor(c); got != s {
+			t.Errorf("FormatTintColor(ParseTintColor(%q)) = %q", s, got)
+		}
+	}
+	if c, _ := ParseTintColor("#80102030"); c != (color.RGBA{0x10, 0x20, 0x30, 0x80}) {
+		t.Errorf("ParseTintColor(#80102030) = %v, want alpha 0x80 first", c)
+	}
+}
@@ -3,29 +3,125 @@ package world
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/torsten/GoP/internal/mathx"
 )
 
 // ObjectType represents the type of entity to spawn.
 type ObjectType string
 
 const (
-	ObjectTypeSpawn      ObjectType = "spawn"
-	ObjectTypeHazard     ObjectType = "hazard"
-	ObjectTypeCheckpoint ObjectType = "checkpoint"
-	ObjectTypeSwitch     ObjectType = "switch"
-	ObjectTypeDoor       ObjectType = "door"
-	ObjectTypeGoal       ObjectType = "goal"
-	ObjectTypePlatform   ObjectType = "platform"
+	ObjectTypeSpawn       ObjectType = "spawn"
+	ObjectTypeHazard      ObjectType = "hazard"
+	ObjectTypeCheckpoint  ObjectType = "checkpoint"
+	ObjectTypeSwitch      ObjectType = "switch"
+	ObjectTypeDoor        ObjectType = "door"
+	ObjectTypeGoal        ObjectType = "goal"
+	ObjectTypePlatform    ObjectType = "platform"
+	ObjectTypeKillPlane   ObjectType = "killplane"
+	ObjectTypeLight       ObjectType = "light"
+	ObjectTypeCollectible ObjectType = "collectible"
+	ObjectTypeKey         ObjectType = "key"
+	ObjectTypeExit        ObjectType = "exit"
+	ObjectTypeSound       ObjectType = "sound_emitter"
+	ObjectTypeForceZone   ObjectType = "force_zone"
+	ObjectTypePath        ObjectType = "path"
 )
 
+// DefaultObjectLayer is the object layer name used when a level has none.
+const DefaultObjectLayer = "Objects"
+
+// PropTags lists the tags of a target object (door, platform, light),
+// separated by commas. Rules can act on every target with a tag at once.
+const PropTags = "tags"
+
+// PropEase names the easing curve of a door's slide or a platform's trips
+// (see tween.ParseEase). Empty is linear.
+const PropEase = "ease"
+
+// PropPlatformPath names the path object (its "id" property, or its name)
+// a platform follows instead of moving between its start and endX/endY:
+// the platform's center runs along a polyline back and forth, or around a
+// polygon.
+const PropPlatformPath = "path"
+
+// PropCollectibleCounter names the gameplay variable a collectible adds one
+// to when picked up, e.g. "gems".
+const PropCollectibleCounter = "counter"
+
 // ObjectData represents a parsed Tiled object.
+//
+// Polygon and polyline objects keep their points relative to X, Y, which
+// ParseObjects moves to the top left of the points' bounding box, with W, H
+// its size, so shapes select, move and overlap like rectangles. Ellipse
+// objects fill their rectangle, and point objects, such as a spawn placed
+// exactly, have no size.
 type ObjectData struct {
-	ID    int
-	Name  string
-	Type  ObjectType
-	X, Y  float64
-	W, H  float64
-	Props map[string]any
+	ID       int
+	Name     string
+	Type     ObjectType
+	X, Y     float64
+	W, H     float64
+	Props    map[string]any
+	Layer    string       // Name of the object layer the object is on
+	Polygon  []mathx.Vec2 `json:",omitempty"` // Closed shape, relative to X, Y
+	Polyline []mathx.Vec2 `json:",omitempty"` // Open path, relative to X, Y
+	Ellipse  bool         `json:",omitempty"`
+	Point    bool         `json:",omitempty"`
+}
+
+// IsShape reports whether the object is a polygon or polyline rather than a
+// rectangle.
+func (o *ObjectData) IsShape() bool {
+	return len(o.Polygon) > 0 || len(o.Polyline) > 0
+}
+
+// Points returns the polygon's or polyline's points in world coordinates,
+// or nil for a rectangle.
+func (o *ObjectData) Points() []mathx.Vec2 {
+	rel := o.Polygon
+	if len(rel) == 0 {
+		rel = o.Polyline
+	}
+	if len(rel) == 0 {
+		return nil
+	}
+	points := make([]mathx.Vec2, len(rel))
+	for i, p := range rel {
+		points[i] = p.Add(mathx.V(o.X, o.Y))
+	}
+	return points
+}
+
+// normalizeShape moves a polygon or polyline object's position to the top
+// left of its points and sets its size to theirs.
+func (o *ObjectData) normalizeShape() {
+	rel := o.Polygon
+	if len(rel) == 0 {
+		rel = o.Polyline
+	}
+	if len(rel) == 0 {
+		return
+	}
+	lo, hi := mathx.PointsBounds(rel)
+	shift := func(points []mathx.Vec2) []mathx.Vec2 {
+		if len(points) == 0 {
+			return nil
+		}
+		out := make([]mathx.Vec2, len(points))
+		for i, p := range points {
+			out[i] = p.Sub(lo)
+		}
+		return out
+	}
+	o.Polygon, o.Polyline = shift(o.Polygon), shift(o.Polyline)
+	o.X, o.Y = o.X+lo.X, o.Y+lo.Y
+	o.W, o.H = hi.X-lo.X, hi.Y-lo.Y
+}
+
+// Tags returns the object's PropTags list.
+func (o *ObjectData) Tags() []string {
+	return ParseTargetList(o.GetPropString(PropTags, ""))
 }
 
 // GetPropString returns a string property or the default value.
@@ -81,6 +177,10 @@ type tiledObject struct {
 	Width      float64         `json:"width"`
 	Height     float64         `json:"height"`
 	Properties []tiledProperty `json:"properties"`
+	Polygon    []mathx.Vec2    `json:"polygon"`
+	Polyline   []mathx.Vec2    `json:"polyline"`
+	Ellipse    bool            `json:"ellipse"`
+	Point      bool            `json:"point"`
 	// TODO: this seems like an anti-pattern. or at least, no one will understand this. we need to fix this properly.
 	// Visible is a pointer to detect missing field (Tiled default is visible=true)
 	Visible *bool `json:"visible"`
@@ -101,7 +201,8 @@ type tiledObjectLayer struct {
 }
 
 // ParseObjects extracts objects from raw Tiled JSON data.
-// This function parses the JSON and returns all objects from object layers.
+// This function parses the JSON and returns all objects from object layers,
+// in layer order, each tagged with its layer's name.
 func ParseObjects(data []byte) ([]ObjectData, error) {
 	var tm struct {
 		Layers []json.RawMessage `json:"layers"`
@@ -148,15 +249,24 @@ func ParseObjects(data []byte) ([]ObjectData, error) {
 			}
 
 			data := ObjectData{
-				ID:    obj.ID,
-				Name:  obj.Name,
-				Type:  ObjectType(obj.Type),
-				X:     obj.X,
-				Y:     obj.Y,
-				W:     obj.Width,
-				H:     obj.Height,
-				Props: props,
+				ID:       obj.ID,
+				Name:     obj.Name,
+				Type:     ObjectType(obj.Type),
+				X:        obj.X,
+				Y:        obj.Y,
+				W:        obj.Width,
+				H:        obj.Height,
+				Props:    props,
+				Layer:    layer.Name,
+				Polygon:  obj.Polygon,
+				Polyline: obj.Polyline,
+				Ellipse:  obj.Ellipse,
+				Point:    obj.Point,
 			}
+			if data.Point {
+				data.W, data.H = 0, 0
+			}
+			data.normalizeShape()
 			objects = append(objects, data)
 		}
 	}
@@ -175,6 +285,32 @@ func FilterObjectsByType(objects []ObjectData, typ ObjectType) []ObjectData {
 	return result
 }
 
+// FindPath returns the path object with the given id (its "id" property,
+// or its name) for PropPlatformPath, or nil.
+func FindPath(objects []ObjectData, id string) *ObjectData {
+	for i := range objects {
+		obj := &objects[i]
+		if obj.Type == ObjectTypePath && obj.GetPropString("id", obj.Name) == id {
+			return obj
+		}
+	}
+	return nil
+}
+
+// FindSpawnByID returns the spawn object with the given id (its "id"
+// property, or its name), or the first spawn if id is empty.
+func FindSpawnByID(objects []ObjectData, id string) (x, y float64, found bool) {
+	if id == "" {
+		return FindSpawnPoint(objects)
+	}
+	for _, obj := range FilterObjectsByType(objects, ObjectTypeSpawn) {
+		if obj.GetPropString("id", obj.Name) == id {
+			return obj.X, obj.Y, true
+		}
+	}
+	return 0, 0, false
+}
+
 // FindSpawnPoint returns the first spawn object, or a default position.
 func FindSpawnPoint(objects []ObjectData) (x, y float64, found bool) {
 	spawns := FilterObjectsByType(objects, ObjectTypeSpawn)
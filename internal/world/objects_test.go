@@ -0,0 +1,48 @@
+//go:build display
+
+package world
+
+import (
+	"testing"
+
+	"github.com/torsten/GoP/internal/mathx"
+)
+
+func TestParseObjectsShapes(t *testing.T) {
+	data := `{"layers": [{"type": "objectgroup", "name": "Objects", "objects": [
+	  {"id": 1, "type": "hazard", "x": 100, "y": 50, "polygon": [{"x": 0, "y": 0}, {"x": 32, "y": -16}, {"x": 32, "y": 0}]},
+	  {"id": 2, "type": "path", "name": "lift", "x": 10, "y": 10, "polyline": [{"x": 0, "y": 0}, {"x": 40, "y": 0}, {"x": 40, "y": 20}]},
+	  {"id": 3, "type": "goal", "x": 5, "y": 6, "width": 7, "height": 8},
+	  {"id": 4, "type": "checkpoint", "x": 20, "y": 30, "width": 48, "height": 48, "ellipse": true},
+	  {"id": 5, "type": "spawn", "x": 64, "y": 96, "width": 0, "height": 0, "point": true}
+	]}]}`
+	objects, err := ParseObjects([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseObjects failed: %v", err)
+	}
+
+	// Moved to the top left of the points, which stay in place
+	lava := objects[0]
+	if lava.X != 100 || lava.Y != 34 || lava.W != 32 || lava.H != 16 {
+		t.Errorf("polygon at (%v, %v) %vx%v, want (100, 34) 32x16", lava.X, lava.Y, lava.W, lava.H)
+	}
+	want := []mathx.Vec2{mathx.V(100, 50), mathx.V(132, 34), mathx.V(132, 50)}
+	for i, p := range lava.Points() {
+		if p != want[i] {
+			t.Errorf("polygon point %d at %v, want %v", i, p, want[i])
+		}
+	}
+
+	if path := FindPath(objects, "lift"); path == nil || len(path.Polyline) != 3 || path.W != 40 || path.H != 20 {
+		t.Errorf("FindPath(lift) = %+v, want the 40x20 polyline", path)
+	}
+	if goal := objects[2]; goal.IsShape() || goal.Points() != nil || goal.W != 7 {
+		t.Errorf("rectangle parsed as %+v", goal)
+	}
+	if round := objects[3]; !round.Ellipse || round.IsShape() || round.W != 48 {
+		t.Errorf("ellipse parsed as %+v", round)
+	}
+	if x, y, _ := FindSpawnPoint(objects); !objects[4].Point || x != 64 || y != 96 {
+		t.Errorf("spawn point at (%v, %v), Point %v, want (64, 96)", x, y, objects[4].Point)
+	}
+}
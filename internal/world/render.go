@@ -1,6 +1,8 @@
 package world
 
 import (
+	"time"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -46,10 +48,10 @@ func (c *Camera) CenterOn(x, y float64, mapPixelWidth, mapPixelHeight int) {
 // VisibleBounds returns the visible tile range (tx1, ty1, tx2, ty2).
 // The range is inclusive for start, exclusive for end.
 func (c *Camera) VisibleBounds(tileSize int) (int, int, int, int) {
-	tx1 := int(c.X) / tileSize
-	ty1 := int(c.Y) / tileSize
-	tx2 := (int(c.X) + c.ViewWidth + tileSize - 1) / tileSize
-	ty2 := (int(c.Y) + c.ViewHeight + tileSize - 1) / tileSize
+	tx1 := TileIndex(c.X, tileSize)
+	ty1 := TileIndex(c.Y, tileSize)
+	tx2 := TileIndex(c.X+float64(c.ViewWidth)+float64(tileSize)-1, tileSize)
+	ty2 := TileIndex(c.Y+float64(c.ViewHeight)+float64(tileSize)-1, tileSize)
 	return tx1, ty1, tx2, ty2
 }
 
@@ -64,16 +66,29 @@ func (c *Camera) ScreenToWorld(sx, sy float64) (wx, wy float64) {
 }
 
 // MapRenderer handles rendering a map with camera support.
+// Tiles are drawn in cached chunks (see ChunkCache).
 type MapRenderer struct {
-	m   *Map
-	cam *Camera
+	m      *Map
+	cam    *Camera
+	chunks *ChunkCache
 }
 
 // NewMapRenderer creates a new renderer for the given map.
 func NewMapRenderer(m *Map) *MapRenderer {
-	return &MapRenderer{
+	r := &MapRenderer{
 		m: m,
 	}
+	if m != nil {
+		r.chunks = NewChunkCache(m.tileset, m.tileWidth, m.tileHeight)
+	}
+	return r
+}
+
+// Update advances the map's tile animations by dt.
+func (r *MapRenderer) Update(dt time.Duration) {
+	if r.chunks != nil {
+		r.chunks.SetTime(r.chunks.Time() + dt)
+	}
 }
 
 // SetCamera updates the camera reference.
@@ -82,69 +97,20 @@ func (r *MapRenderer) SetCamera(cam *Camera) {
 }
 
 // Draw renders all visible tiles to the screen.
-// Only tiles within the camera viewport are drawn.
+// Only chunks within the camera viewport are drawn.
 // camX and camY are the camera offset in world pixels.
 func (r *MapRenderer) Draw(screen *ebiten.Image, camX, camY float64) {
 	if r.m == nil || r.m.tileset == nil {
 		return
 	}
 
-	tileW := r.m.tileWidth
-	tileH := r.m.tileHeight
-
-	// Calculate visible tile range
-	tx1 := int(camX) / tileW
-	ty1 := int(camY) / tileH
-
-	// Add 1 to ensure we cover tiles partially visible
-	tx2 := (int(camX) + screen.Bounds().Dx() + tileW - 1) / tileW
-	ty2 := (int(camY) + screen.Bounds().Dy() + tileH - 1) / tileH
-
-	// Clamp to map bounds
-	if tx1 < 0 {
-		tx1 = 0
-	}
-	if ty1 < 0 {
-		ty1 = 0
-	}
-	if tx2 > r.m.width {
-		tx2 = r.m.width
-	}
-	if ty2 > r.m.height {
-		ty2 = r.m.height
-	}
-
-	// Draw each layer
+	r.chunks.BeginFrame()
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
 	for _, layer := range r.m.layers {
 		if layer.Name() == "Collision" {
 			continue // Don't render collision layer
 		}
-		for ty := ty1; ty < ty2; ty++ {
-			for tx := tx1; tx < tx2; tx++ {
-				tileID := layer.TileAt(tx, ty)
-				if tileID == 0 {
-					continue // Empty tile
-				}
-
-				// Tiled uses 1-based IDs, convert to 0-based
-				tile := r.m.tileset.Tile(tileID - 1)
-				if tile == nil {
-					continue
-				}
-
-				// Calculate screen position
-				worldX := float64(tx * tileW)
-				worldY := float64(ty * tileH)
-				screenX := worldX - camX
-				screenY := worldY - camY
-
-				// Draw tile
-				op := &ebiten.DrawImageOptions{}
-				op.GeoM.Translate(screenX, screenY)
-				op.Filter = ebiten.FilterNearest
-				screen.DrawImage(tile, op)
-			}
-		}
+		r.chunks.DrawLayer(screen, layer, camX, camY, 1, w, h)
 	}
 }
 
@@ -159,53 +125,16 @@ func (r *MapRenderer) DrawLayer(screen *ebiten.Image, layerName string, camX, ca
 	if layer == nil {
 		return
 	}
+	r.chunks.DrawLayer(screen, layer, camX, camY, 1, screen.Bounds().Dx(), screen.Bounds().Dy())
+}
 
-	tileW := r.m.tileWidth
-	tileH := r.m.tileHeight
-
-	// Calculate visible tile range
-	tx1 := int(camX) / tileW
-	ty1 := int(camY) / tileH
-	tx2 := (int(camX) + screen.Bounds().Dx() + tileW - 1) / tileW
-	ty2 := (int(camY) + screen.Bounds().Dy() + tileH - 1) / tileH
-
-	// Clamp to map bounds
-	if tx1 < 0 {
-		tx1 = 0
-	}
-	if ty1 < 0 {
-		ty1 = 0
-	}
-	if tx2 > r.m.width {
-		tx2 = r.m.width
-	}
-	if ty2 > r.m.height {
-		ty2 = r.m.height
-	}
-
-	for ty := ty1; ty < ty2; ty++ {
-		for tx := tx1; tx < tx2; tx++ {
-			tileID := layer.TileAt(tx, ty)
-			if tileID == 0 {
-				continue
-			}
-
-			tile := r.m.tileset.Tile(tileID - 1)
-			if tile == nil {
-				continue
-			}
-
-			worldX := float64(tx * tileW)
-			worldY := float64(ty * tileH)
-			screenX := worldX - camX
-			screenY := worldY - camY
-
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(screenX, screenY)
-			op.Filter = ebiten.FilterNearest
-			screen.DrawImage(tile, op)
-		}
+// DrawCalls returns the number of DrawImage calls the last Draw made onto the
+// screen, one per visible non-empty chunk.
+func (r *MapRenderer) DrawCalls() int {
+	if r.chunks == nil {
+		return 0
 	}
+	return r.chunks.DrawCalls()
 }
 
 // DrawWithCamera renders the map using the Camera struct.
@@ -13,15 +13,22 @@ type RenderContext struct {
 	Debug  bool
 	DT     float64 // Delta time for animations
 	Screen *ebiten.Image
+
+	// Alpha is how far drawing is between the last two fixed updates, from
+	// 0 (the previous step) to 1 (the latest). Entities that move in fixed
+	// updates interpolate with it.
+	Alpha float64
 }
 
 // NewRenderContext creates a new render context with the given parameters.
+// Alpha starts at 1, drawing the latest state.
 func NewRenderContext(cam *camera.Camera, screen *ebiten.Image, dt float64) *RenderContext {
 	return &RenderContext{
 		Cam:    cam,
 		Debug:  false,
 		DT:     dt,
 		Screen: screen,
+		Alpha:  1,
 	}
 }
 
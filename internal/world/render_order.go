@@ -0,0 +1,39 @@
+package world
+
+// PropZ is the object property setting the render order of its entity:
+// entities draw from the lowest z up, and the player draws at ZPlayer, so
+// lower values go behind the player. Without it the type's DefaultZ applies.
+const PropZ = "z"
+
+// PropYSort is the map property that, when true, draws entities with the
+// same z, and the player among them, by their bottom edge, so decorations
+// overlap by depth.
+const PropYSort = "ySort"
+
+// ZPlayer is the render order of the player. Entities at the same z draw
+// before it, unless the level y-sorts.
+const ZPlayer = 0
+
+// DefaultZ returns the render order of a type's entities without PropZ:
+// doors, platforms and level markers behind the player, pickups in front.
+func DefaultZ(typ ObjectType) int {
+	switch typ {
+	case ObjectTypeDoor, ObjectTypePlatform, ObjectTypeSwitch, ObjectTypeCheckpoint, ObjectTypeGoal, ObjectTypeExit:
+		return -10
+	case ObjectTypeCollectible, ObjectTypeKey:
+		return 10
+	}
+	return ZPlayer
+}
+
+// Z returns the object's render order: its PropZ, or its type's DefaultZ.
+func (o *ObjectData) Z() int {
+	return o.GetPropInt(PropZ, DefaultZ(o.Type))
+}
+
+// YSort returns whether the level y-sorts its entities, from its map
+// properties (see PropYSort).
+func YSort(props map[string]any) bool {
+	ySort, _ := props[PropYSort].(bool)
+	return ySort
+}
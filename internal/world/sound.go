@@ -0,0 +1,23 @@
+package world
+
+// Sound emitter object property names. An emitter loops a clip at the
+// object's center.
+const (
+	// PropSoundClip is the clip to loop.
+	PropSoundClip = "clip"
+	// PropSoundRadius is the distance in pixels at which the sound falls
+	// silent.
+	PropSoundRadius = "radius"
+	// PropSoundVolume is the volume at the emitter, 0-1.
+	PropSoundVolume = "volume"
+	// PropSoundFalloff is how the volume drops with distance: "linear"
+	// (default), "quadratic", or "none" for an ambient sound heard
+	// everywhere.
+	PropSoundFalloff = "falloff"
+)
+
+// Sound emitter defaults.
+const (
+	DefaultSoundRadius = 128.0
+	DefaultSoundVolume = 1.0
+)
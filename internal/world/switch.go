@@ -0,0 +1,57 @@
+package world
+
+import "strings"
+
+// Switch object property names.
+const (
+	// PropSwitchTarget is the primary target ID (doors, platforms, lights).
+	PropSwitchTarget = "door_id"
+	// PropSwitchTargets lists additional target IDs, separated by commas.
+	PropSwitchTargets = "targets"
+	// PropSwitchMode selects how the switch behaves: toggle, plate, or timed.
+	PropSwitchMode = "mode"
+	// PropSwitchDuration is how long a timed switch stays on, in seconds.
+	PropSwitchDuration = "duration"
+)
+
+// SwitchTargets returns the IDs a switch object controls: its primary
+// target ("door_id", or the older "target") followed by the "targets" list,
+// without duplicates.
+func SwitchTargets(obj ObjectData) []string {
+	primary := obj.GetPropString("target", "")
+	if primary == "" {
+		primary = obj.GetPropString(PropSwitchTarget, "")
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range append([]string{primary}, ParseTargetList(obj.GetPropString(PropSwitchTargets, ""))...) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// ParseTargetList splits a comma-separated list of IDs, dropping blanks.
+func ParseTargetList(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// AddTarget appends id to a comma-separated list unless it's already there.
+func AddTarget(list, id string) string {
+	ids := ParseTargetList(list)
+	for _, existing := range ids {
+		if existing == id {
+			return strings.Join(ids, ",")
+		}
+	}
+	return strings.Join(append(ids, id), ",")
+}
@@ -0,0 +1,32 @@
+package world
+
+import (
+	"encoding/json"
+
+	"github.com/torsten/GoP/internal/tiled"
+)
+
+// Tile layer data is encoded as in internal/tiled, which the tools load
+// levels with, so the game and the tools read the same files.
+
+// EncodingBase64 is the "encoding" of tile layers stored as strings.
+const EncodingBase64 = tiled.EncodingBase64
+
+// Compressions of base64 encoded tile data.
+const (
+	CompressionZlib = tiled.CompressionZlib
+	CompressionGzip = tiled.CompressionGzip
+)
+
+// DecodeTileData returns the tile IDs of a tile layer's or chunk's "data"
+// stored with the given encoding and compression (see
+// tiled.DecodeTileData).
+func DecodeTileData(data json.RawMessage, encoding, compression string) ([]int, error) {
+	return tiled.DecodeTileData(data, encoding, compression)
+}
+
+// EncodeTileData returns ids as base64 encoded tile data, compressed with
+// compression ("" for none), as DecodeTileData reads it.
+func EncodeTileData(ids []int, compression string) (string, error) {
+	return tiled.EncodeTileData(ids, compression)
+}
@@ -0,0 +1,80 @@
+//go:build display
+
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTileDataRoundTrip(t *testing.T) {
+	ids := []int{0, 1, 2, 300, 0, 0x80000005} // The last one flipped horizontally
+	for _, compression := range []string{"", CompressionZlib, CompressionGzip} {
+		s, err := EncodeTileData(ids, compression)
+		if err != nil {
+			t.Fatalf("EncodeTileData(%q) failed: %v", compression, err)
+		}
+		raw, _ := json.Marshal(s)
+		got, err := DecodeTileData(raw, EncodingBase64, compression)
+		if err != nil {
+			t.Fatalf("DecodeTileData(%q) failed: %v", compression, err)
+		}
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("%q: decoded %v, want %v", compression, got, ids)
+		}
+	}
+
+	if _, err := EncodeTileData(ids, "zstd"); err == nil {
+		t.Error("EncodeTileData with zstd succeeded")
+	}
+	if _, err := DecodeTileData(json.RawMessage(`"AAAA"`), EncodingBase64, "zstd"); err == nil {
+		t.Error("DecodeTileData with zstd succeeded")
+	}
+	if _, err := DecodeTileData(json.RawMessage(`"AAA="`), EncodingBase64, ""); err == nil {
+		t.Error("DecodeTileData of 2 bytes succeeded")
+	}
+}
+
+func TestDecodeTileDataArray(t *testing.T) {
+	got, err := DecodeTileData(json.RawMessage(`[1, 0, 2]`), "csv", "")
+	if err != nil || !reflect.DeepEqual(got, []int{1, 0, 2}) {
+		t.Errorf("DecodeTileData(array) = %v, %v, want [1 0 2]", got, err)
+	}
+	if got, err := DecodeTileData(nil, "", ""); got != nil || err != nil {
+		t.Errorf("DecodeTileData(nil) = %v, %v, want no tiles", got, err)
+	}
+}
+
+func TestParseTiledJSONCompressedLayers(t *testing.T) {
+	zlibData, _ := EncodeTileData([]int{1, 0, 0, 2}, CompressionZlib)
+	gzipChunk, _ := EncodeTileData([]int{3}, CompressionGzip)
+	data := fmt.Sprintf(`{"width": 2, "height": 2, "tilewidth": 16, "tileheight": 16, "layers": [
+	  {"name": "Tiles", "type": "tilelayer", "encoding": "base64", "compression": "zlib", "data": %q}
+	]}`, zlibData)
+	md, err := ParseTiledJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON failed: %v", err)
+	}
+	if tiles := md.Layer("Tiles"); tiles.TileAt(0, 0) != 1 || tiles.TileAt(1, 1) != 2 {
+		t.Errorf("zlib layer tiles = %v, want [1 0 0 2]", tiles.Data())
+	}
+
+	infinite := fmt.Sprintf(`{"tilewidth": 16, "tileheight": 16, "infinite": true, "layers": [
+	  {"name": "Tiles", "type": "tilelayer", "encoding": "base64", "compression": "gzip",
+	   "chunks": [{"x": 16, "y": 0, "width": 1, "height": 1, "data": %q}]}
+	]}`, gzipChunk)
+	md, err = ParseTiledJSON([]byte(infinite))
+	if err != nil {
+		t.Fatalf("ParseTiledJSON(infinite) failed: %v", err)
+	}
+	if got := md.Layer("Tiles").TileAt(16, 0); got != 3 {
+		t.Errorf("gzip chunk tile = %d, want 3", got)
+	}
+
+	bad := `{"layers": [{"name": "Tiles", "type": "tilelayer", "encoding": "base64", "compression": "zstd", "data": "AAAA"}]}`
+	if _, err := ParseTiledJSON([]byte(bad)); err == nil {
+		t.Error("a zstd compressed layer parsed")
+	}
+}
@@ -0,0 +1,170 @@
+// Package worldmap describes the overworld: level nodes joined by paths that
+// open as the player completes levels.
+package worldmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/torsten/GoP/internal/storage"
+)
+
+// Map is an overworld loaded from a worldmap JSON file.
+type Map struct {
+	// Name is shown as the overworld's title
+	Name string `json:"name"`
+	// Start is the ID of the node the player starts at
+	Start string `json:"start"`
+	Nodes []Node `json:"nodes"`
+	Paths []Path `json:"paths"`
+
+	dir string // Directory node levels are relative to
+}
+
+// Node is a stop on the overworld, usually a level.
+type Node struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Level string  `json:"level,omitempty"` // Level file relative to the worldmap ("" = no level, e.g. a crossing)
+	X     float64 `json:"x"`               // Screen position in logical pixels
+	Y     float64 `json:"y"`
+}
+
+// Path joins two nodes. It opens once the From node's level is completed
+// (straight away if From has no level) and can then be walked both ways.
+type Path struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Completed reports whether the level at a node's LevelPath is completed.
+type Completed func(level string) bool
+
+// Parse parses a worldmap from JSON. Node levels are relative to dir.
+// Node IDs are checked so mistakes are reported at load time.
+func Parse(data []byte, dir string) (*Map, error) {
+	var m Map
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse worldmap: %w", err)
+	}
+	m.dir = dir
+
+	ids := make(map[string]bool, len(m.Nodes))
+	for _, n := range m.Nodes {
+		if n.ID == "" {
+			return nil, fmt.Errorf("worldmap %q: node %q has no id", m.Name, n.Name)
+		}
+		if ids[n.ID] {
+			return nil, fmt.Errorf("worldmap %q: duplicate node %q", m.Name, n.ID)
+		}
+		ids[n.ID] = true
+	}
+	if !ids[m.Start] {
+		return nil, fmt.Errorf("worldmap %q: start node %q not found", m.Name, m.Start)
+	}
+	for _, p := range m.Paths {
+		if !ids[p.From] || !ids[p.To] {
+			return nil, fmt.Errorf("worldmap %q: path %s-%s leads to an unknown node", m.Name, p.From, p.To)
+		}
+	}
+	return &m, nil
+}
+
+// Load loads a worldmap file. Node levels are relative to the file.
+func Load(path string) (*Map, error) {
+	data, err := storage.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worldmap %s: %w", path, err)
+	}
+	return Parse(data, filepath.Dir(path))
+}
+
+// Node returns the node with the given ID.
+func (m *Map) Node(id string) (Node, bool) {
+	for _, n := range m.Nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Node{}, false
+}
+
+// LevelPath returns the file path of a node's level, or "" if it has none.
+func (m *Map) LevelPath(n Node) string {
+	if n.Level == "" {
+		return ""
+	}
+	if filepath.IsAbs(n.Level) {
+		return n.Level
+	}
+	return filepath.Join(m.dir, n.Level)
+}
+
+// IsOpen reports whether a path can be walked.
+func (m *Map) IsOpen(p Path, completed Completed) bool {
+	from, _ := m.Node(p.From)
+	level := m.LevelPath(from)
+	return level == "" || completed(level)
+}
+
+// Unlocked returns the IDs of the nodes the player can reach from the start
+// through open paths.
+func (m *Map) Unlocked(completed Completed) map[string]bool {
+	unlocked := map[string]bool{m.Start: true}
+	queue := []string{m.Start}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, other := range m.neighbors(id, completed) {
+			if !unlocked[other] {
+				unlocked[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+	return unlocked
+}
+
+// neighbors returns the nodes joined to id by open paths.
+func (m *Map) neighbors(id string, completed Completed) []string {
+	var ids []string
+	for _, p := range m.Paths {
+		if !m.IsOpen(p, completed) {
+			continue
+		}
+		switch id {
+		case p.From:
+			ids = append(ids, p.To)
+		case p.To:
+			ids = append(ids, p.From)
+		}
+	}
+	return ids
+}
+
+// Neighbor returns the node the player reaches by walking from the node id
+// toward direction dx, dy along an open path: the one lying closest to that
+// direction, within 60 degrees of it.
+func (m *Map) Neighbor(id string, dx, dy float64, completed Completed) (string, bool) {
+	from, ok := m.Node(id)
+	if !ok || dx == 0 && dy == 0 {
+		return "", false
+	}
+	dirLen := math.Hypot(dx, dy)
+
+	best, bestDot := "", 0.5 // cos(60°)
+	for _, other := range m.neighbors(id, completed) {
+		to, _ := m.Node(other)
+		vx, vy := to.X-from.X, to.Y-from.Y
+		dist := math.Hypot(vx, vy)
+		if dist == 0 {
+			continue
+		}
+		if dot := (vx*dx + vy*dy) / (dist * dirLen); dot > bestDot {
+			best, bestDot = other, dot
+		}
+	}
+	return best, best != ""
+}
@@ -0,0 +1,104 @@
+package worldmap
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testMap is a camp with a path east to level 1, which opens paths north
+// to level 2 and east to the summit.
+const testMap = `{
+	"name": "Test",
+	"start": "camp",
+	"nodes": [
+		{"id": "camp", "name": "Camp", "x": 0, "y": 100},
+		{"id": "l1", "name": "Level 1", "level": "levels/l1.json", "x": 100, "y": 100},
+		{"id": "l2", "name": "Level 2", "level": "levels/l2.json", "x": 100, "y": 0},
+		{"id": "summit", "name": "Summit", "x": 200, "y": 90}
+	],
+	"paths": [
+		{"from": "camp", "to": "l1"},
+		{"from": "l1", "to": "l2"},
+		{"from": "l1", "to": "summit"}
+	]
+}`
+
+func parseTestMap(t *testing.T) *Map {
+	t.Helper()
+	m, err := Parse([]byte(testMap), "worlds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// completedLevels returns a Completed func for the given level paths.
+func completedLevels(paths ...string) Completed {
+	return func(level string) bool {
+		for _, p := range paths {
+			if p == level {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestUnlocked(t *testing.T) {
+	m := parseTestMap(t)
+
+	got := m.Unlocked(completedLevels())
+	if len(got) != 2 || !got["camp"] || !got["l1"] {
+		t.Errorf("Unlocked with nothing completed = %v, want camp and l1", got)
+	}
+
+	got = m.Unlocked(completedLevels(filepath.Join("worlds", "levels", "l1.json")))
+	if len(got) != 4 {
+		t.Errorf("Unlocked with l1 completed = %v, want every node", got)
+	}
+}
+
+func TestNeighbor(t *testing.T) {
+	m := parseTestMap(t)
+	done := completedLevels(filepath.Join("worlds", "levels", "l1.json"))
+
+	tests := []struct {
+		from   string
+		dx, dy float64
+		want   string
+	}{
+		{"camp", 1, 0, "l1"},
+		{"l1", -1, 0, "camp"},
+		{"l1", 0, -1, "l2"},
+		{"l1", 1, 0, "summit"}, // Slightly up still counts as east
+		{"l1", 0, 1, ""},       // Nothing south
+	}
+	for _, tt := range tests {
+		got, _ := m.Neighbor(tt.from, tt.dx, tt.dy, done)
+		if got != tt.want {
+			t.Errorf("Neighbor(%s, %v, %v) = %q, want %q", tt.from, tt.dx, tt.dy, got, tt.want)
+		}
+	}
+
+	// Locked paths can't be walked
+	if got, ok := m.Neighbor("l1", 0, -1, completedLevels()); ok {
+		t.Errorf("Neighbor walked a locked path to %q", got)
+	}
+}
+
+func TestParseRejectsBadNodes(t *testing.T) {
+	tests := []struct {
+		data, want string
+	}{
+		{`{"start": "a", "nodes": [{"id": "a"}, {"id": "a"}]}`, "duplicate node"},
+		{`{"start": "b", "nodes": [{"id": "a"}]}`, "start node"},
+		{`{"start": "a", "nodes": [{"id": "a"}], "paths": [{"from": "a", "to": "b"}]}`, "unknown node"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.data), "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%s) = %v, want error containing %q", tt.data, err, tt.want)
+		}
+	}
+}